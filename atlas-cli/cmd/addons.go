@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+var clusterAddonsCmd = &cobra.Command{
+	Use:   "addons",
+	Short: "Manage cluster addons",
+	Long: `List, enable and disable the optional components the provider manages for a cluster: minikube
+addons for local clusters and EKS managed add-ons, such as vpc-cni or aws-ebs-csi-driver, for AWS.`,
+}
+
+var clusterAddonsListCmd = &cobra.Command{
+	Use:   "list [name]",
+	Short: "List a cluster's addons",
+	Long: `List the addons the provider reports for a cluster. Local clusters list every minikube addon
+with whether it is enabled; EKS clusters list the add-ons installed on them.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		clusterName := args[0]
+		p, err := addonProvider(cmd)
+		if err != nil {
+			return err
+		}
+		lister, ok := p.(providers.AddonLister)
+		if !ok {
+			return fmt.Errorf("provider %s does not support addons", p.GetProviderName())
+		}
+		addons, err := lister.ListAddons(commandContext(), clusterName)
+		if err != nil {
+			return fmt.Errorf("failed to list addons: %w", err)
+		}
+
+		if ok, err := writeStructured(os.Stdout, addons); ok {
+			return err
+		}
+		if len(addons) == 0 {
+			fmt.Printf("No addons found for cluster '%s'\n", clusterName)
+			return nil
+		}
+
+		t := newTable("NAME", "STATUS", "VERSION")
+		for _, addon := range addons {
+			status := "disabled"
+			if addon.Enabled {
+				status = "enabled"
+			}
+			t.addRow(addon.Name, status, valueOrDash(addon.Version))
+		}
+		t.render(os.Stdout)
+		return nil
+	},
+}
+
+var clusterAddonsEnableCmd = &cobra.Command{
+	Use:   "enable [name] [addon...]",
+	Short: "Enable addons on a cluster",
+	Example: `  atlas-cli cluster addons enable dev ingress metrics-server
+  atlas-cli cluster addons enable prod aws-ebs-csi-driver -p aws -r us-west-2`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setClusterAddons(cmd, args[0], args[1:], true)
+	},
+}
+
+var clusterAddonsDisableCmd = &cobra.Command{
+	Use:   "disable [name] [addon...]",
+	Short: "Disable addons on a cluster",
+	Long: `Disable addons on a cluster. For EKS the add-on is deleted, along with the software it
+installed in the cluster.`,
+	Example: `  atlas-cli cluster addons disable dev dashboard`,
+	Args:    cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setClusterAddons(cmd, args[0], args[1:], false)
+	},
+}
+
+// setClusterAddons enables or disables each addon in turn, stopping at the first failure
+func setClusterAddons(cmd *cobra.Command, clusterName string, addons []string, enable bool) error {
+	services := GetServices()
+	if services == nil {
+		return fmt.Errorf("services not initialized")
+	}
+
+	verb, done := "enable", "enabled"
+	if !enable {
+		verb, done = "disable", "disabled"
+		warnOutsideMaintenanceWindow(clusterName, "disabling addons on")
+		if err := requireApproval(clusterName, "disable addons", strings.Join(addons, ", ")); err != nil {
+			return err
+		}
+	}
+
+	p, err := addonProvider(cmd)
+	if err != nil {
+		return err
+	}
+	manager, ok := p.(providers.AddonManager)
+	if !ok {
+		return fmt.Errorf("provider %s does not support managing addons", p.GetProviderName())
+	}
+	ctx := commandContext()
+	if err := requireCredentials(ctx, p); err != nil {
+		return err
+	}
+
+	var changed []string
+	for _, addon := range addons {
+		services.Log(fmt.Sprintf("Addon %s: %s on cluster %s", addon, verb, clusterName))
+		if enable {
+			err = manager.EnableAddon(ctx, clusterName, addon)
+		} else {
+			err = manager.DisableAddon(ctx, clusterName, addon)
+		}
+		if err != nil {
+			break
+		}
+		changed = append(changed, addon)
+		if !currentOutputFormat().structured() {
+			fmt.Printf("Addon '%s' %s on cluster '%s'\n", addon, done, clusterName)
+		}
+	}
+	if len(changed) > 0 {
+		syncInventory(ctx, p, clusterName)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = writeStructured(os.Stdout, map[string]any{
+		"cluster": clusterName,
+		"addons":  changed,
+		"status":  done,
+	})
+	return err
+}
+
+// addonProvider returns the provider from the command's flags
+func addonProvider(cmd *cobra.Command) (providers.Provider, error) {
+	providerName, _ := cmd.Flags().GetString("provider")
+	region, _ := cmd.Flags().GetString("region")
+	awsProfile, _ := cmd.Flags().GetString("aws-profile")
+
+	p, err := GetServices().GetProvider(providerName, region, awsProfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider: %w", err)
+	}
+	return p, nil
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterAddonsCmd)
+	clusterAddonsCmd.AddCommand(clusterAddonsListCmd, clusterAddonsEnableCmd, clusterAddonsDisableCmd)
+
+	for _, c := range []*cobra.Command{clusterAddonsListCmd, clusterAddonsEnableCmd, clusterAddonsDisableCmd} {
+		c.Flags().StringP("provider", "p", "local", "Cloud provider (local, aws)")
+		c.Flags().StringP("region", "r", "", "Region the cluster runs in")
+		c.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	}
+}
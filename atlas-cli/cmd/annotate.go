@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/annotations"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/approvals"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/spf13/cobra"
+)
+
+var operationAnnotateCmd = &cobra.Command{
+	Use:   "annotate [id] [note]",
+	Short: "Attach a note to a past operation",
+	Long: `Attach a note to an operation from cluster history, e.g. why it was rolled back, so it shows
+up under the operation in 'cluster history' and history exports. Operation IDs are the op-... IDs
+shown by 'cluster history'.
+
+The operation is looked up in the provider's recent history; pass --cluster to search only that
+cluster's history, or raise --limit to search further back.`,
+	Example: `  atlas-cli operation annotate op-1a2b3c4d "rolled back due to bad AMI"
+  atlas-cli operation annotate op-1a2b3c4d -p aws -r us-west-2 --cluster prod "caused by quota limit"`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		ref := args[0]
+		text := strings.TrimSpace(strings.Join(args[1:], " "))
+		if text == "" {
+			return fmt.Errorf("note cannot be empty")
+		}
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		clusterName, _ := cmd.Flags().GetString("cluster")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		p, err := services.GetProvider(providerName, region, awsProfile)
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+		ctx := commandContext()
+		var ops []*logsource.OperationHistory
+		if clusterName != "" {
+			ops, err = historySource(p).GetClusterHistory(ctx, clusterName, limit)
+		} else {
+			var histories map[string][]*logsource.OperationHistory
+			histories, err = historySource(p).GetAllClustersHistory(ctx, limit)
+			for _, history := range histories {
+				ops = append(ops, history...)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get operation history: %w", err)
+		}
+
+		var op *logsource.OperationHistory
+		for _, candidate := range ops {
+			if logsource.OperationRef(candidate) == ref {
+				op = candidate
+				break
+			}
+		}
+		if op == nil {
+			return fmt.Errorf("operation %s not found in the last %d operations; pass --cluster or raise --limit", ref, limit)
+		}
+
+		store, err := loadDefault(annotations.DefaultStorePath, annotations.LoadStore)
+		if err != nil {
+			return err
+		}
+		note := &annotations.Note{
+			Operation: ref,
+			Cluster:   op.ClusterName,
+			Text:      text,
+			Author:    approvals.CurrentUser(),
+			CreatedAt: time.Now(),
+		}
+		store.Add(note)
+		if err := store.Save(); err != nil {
+			return err
+		}
+
+		if ok, err := writeStructured(os.Stdout, note); ok {
+			return err
+		}
+		fmt.Printf("Annotated %s of cluster '%s' (operation %s)\n", op.OperationType, op.ClusterName, ref)
+		return nil
+	},
+}
+
+// annotateHistory sets the operations' refs, adds their notes to their metadata and their phase
+// timings to their details. Notes or timings that can't be read are skipped with a warning, since
+// history is still useful without them.
+func annotateHistory(ops []*logsource.OperationHistory) {
+	applyPhaseTimings(ops)
+	store, err := loadDefault(annotations.DefaultStorePath, annotations.LoadStore)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		for _, op := range ops {
+			op.Ref = logsource.OperationRef(op)
+		}
+		return
+	}
+	store.Apply(ops)
+}
+
+func init() {
+	operationCmd.AddCommand(operationAnnotateCmd)
+
+	operationAnnotateCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+	operationAnnotateCmd.Flags().StringP("region", "r", "", "Region the cluster runs in")
+	operationAnnotateCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	operationAnnotateCmd.Flags().StringP("cluster", "c", "", "Only search this cluster's history")
+	operationAnnotateCmd.Flags().IntP("limit", "l", 200, "Number of recent operations to search")
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/operations"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+var clusterApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Bring a cluster in line with a config file",
+	Long: `Compare a cluster config file with the cluster it names and make only the changes needed: create
+the cluster if it doesn't exist, upgrade its Kubernetes version, scale it to nodeCount and enable
+missing addons. The plan is printed first and applied once confirmed.
+
+Settings that can't change after creation, such as the network config, are only used when the
+cluster is created. Addons enabled on the cluster but not listed in the file are left alone.`,
+	Example: `  atlas-cli cluster apply -f cluster.yaml --plan
+  atlas-cli cluster apply -f cluster.yaml -p aws --yes`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		configFile, _ := cmd.Flags().GetString("file")
+		providerName, _ := cmd.Flags().GetString("provider")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		planOnly, _ := cmd.Flags().GetBool("plan")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		config, _, err := loadClusterConfig(configFile, false)
+		if err != nil {
+			return err
+		}
+		if config.Name == "" {
+			return fmt.Errorf("%s does not name a cluster; set name", configFile)
+		}
+		ctx := commandContext()
+		if err := secretResolver.ResolveAll(ctx, config); err != nil {
+			return err
+		}
+		if awsProfile == "" && config.AWS != nil {
+			awsProfile = config.AWS.Profile
+		}
+
+		p, err := services.GetProvider(providerName, config.Region, awsProfile)
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+		if err := requireCredentials(ctx, p); err != nil {
+			return err
+		}
+
+		services.Log(fmt.Sprintf("Planning changes for cluster: %s", config.Name))
+		plan, err := planClusterApply(ctx, p, config)
+		if err != nil {
+			return err
+		}
+
+		structured := currentOutputFormat().structured()
+		printResult := func(applied bool) error {
+			_, err := writeStructured(os.Stdout, map[string]any{"plan": plan, "applied": applied})
+			return err
+		}
+		if !structured {
+			printPlan(os.Stdout, p.GetProviderName(), plan)
+		}
+		if len(plan.Actions) == 0 || planOnly {
+			return printResult(false)
+		}
+
+		if !yes {
+			if !isTerminal(os.Stdin) {
+				return fmt.Errorf("refusing to apply without confirmation; pass --yes")
+			}
+			proceed, err := newPrompter(os.Stdin, os.Stdout).confirm("Apply these changes?", false)
+			if err != nil {
+				return err
+			}
+			if !proceed {
+				return nil
+			}
+		}
+
+		warnOutsideMaintenanceWindow(config.Name, "applying changes to")
+		if err := requireApproval(config.Name, "apply", fmt.Sprintf("%d changes", len(plan.Actions))); err != nil {
+			return err
+		}
+		release, err := services.GetOperationLimiter().Acquire(ctx, &operations.Operation{
+			Type:     "apply",
+			Cluster:  config.Name,
+			Provider: p.GetProviderName(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to acquire operation slot: %w", err)
+		}
+		defer release()
+
+		for i, action := range plan.Actions {
+			if err := applyPlanAction(ctx, p, config, action); err != nil {
+				return fmt.Errorf("failed to %s: %w (%d of %d changes applied)", action.Type, err, i, len(plan.Actions))
+			}
+		}
+		syncInventory(ctx, p, config.Name)
+
+		if !structured {
+			fmt.Printf("Cluster '%s' is up to date (%d changes applied)\n", config.Name, len(plan.Actions))
+		}
+		return printResult(true)
+	},
+}
+
+// planClusterApply looks up the cluster config names and plans the changes it needs, checking the
+// provider can make each of them
+func planClusterApply(ctx context.Context, p providers.Provider, config *providers.ClusterConfig) (*providers.Plan, error) {
+	clusters, err := p.ListClusters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	}
+	var cluster *providers.Cluster
+	for _, c := range clusters {
+		if c.Name == config.Name {
+			cluster = c
+		}
+	}
+
+	var addons []providers.Addon
+	if cluster == nil {
+		if config.NodeCount == 0 {
+			config.NodeCount = 1
+		}
+		if err := checkClusterConfig(p, config, false); err != nil {
+			return nil, err
+		}
+	} else if lister, ok := p.(providers.AddonLister); ok && len(config.Addons) > 0 {
+		if addons, err = lister.ListAddons(ctx, config.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	plan, err := providers.PlanCluster(config, cluster, addons)
+	if err != nil {
+		return nil, err
+	}
+	for _, action := range plan.Actions {
+		supported := true
+		switch action.Type {
+		case providers.PlanUpgrade:
+			_, supported = p.(providers.ClusterUpgrader)
+		case providers.PlanEnableAddon:
+			_, supported = p.(providers.AddonManager)
+		}
+		if !supported {
+			return nil, fmt.Errorf("provider %s does not support the planned %s of cluster %s", p.GetProviderName(), action.Type, config.Name)
+		}
+	}
+	return plan, nil
+}
+
+// applyPlanAction makes one planned change
+func applyPlanAction(ctx context.Context, p providers.Provider, config *providers.ClusterConfig, action providers.PlanAction) error {
+	switch action.Type {
+	case providers.PlanCreate:
+		reservation, err := reserveClusterPorts(p, config)
+		if err != nil {
+			return err
+		}
+		defer reservation.release()
+		if _, err := p.CreateCluster(ctx, config); err != nil {
+			return err
+		}
+		reservation.keep()
+		return nil
+	case providers.PlanUpgrade:
+		return p.(providers.ClusterUpgrader).UpgradeCluster(ctx, config.Name, providers.UpgradeOptions{Version: action.To})
+	case providers.PlanScale:
+		nodeCount, err := strconv.Atoi(action.To)
+		if err != nil {
+			return err
+		}
+		return p.ScaleCluster(ctx, config.Name, nodeCount)
+	case providers.PlanEnableAddon:
+		return p.(providers.AddonManager).EnableAddon(ctx, config.Name, action.To)
+	}
+	return fmt.Errorf("unknown action %s", action.Type)
+}
+
+func printPlan(w io.Writer, providerName string, plan *providers.Plan) {
+	if len(plan.Actions) == 0 {
+		fmt.Fprintf(w, "Cluster '%s' (%s) matches its config; nothing to do\n", plan.Cluster, providerName)
+		return
+	}
+	fmt.Fprintf(w, "Plan for cluster '%s' (%s):\n", plan.Cluster, providerName)
+	for _, action := range plan.Actions {
+		fmt.Fprintf(w, "  %s\n", action)
+	}
+	fmt.Fprintf(w, "%d changes\n", len(plan.Actions))
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterApplyCmd)
+
+	clusterApplyCmd.Flags().StringP("file", "f", "", "Cluster config file (YAML)")
+	clusterApplyCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+	clusterApplyCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	clusterApplyCmd.Flags().Bool("plan", false, "Only print the plan")
+	clusterApplyCmd.Flags().BoolP("yes", "y", false, "Apply without asking for confirmation")
+	clusterApplyCmd.MarkFlagRequired("file")
+}
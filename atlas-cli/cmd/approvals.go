@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/approvals"
+	"github.com/spf13/cobra"
+)
+
+var clusterProtectCmd = &cobra.Command{
+	Use:   "protect [name]",
+	Short: "Require approval for disruptive operations on a cluster",
+	Long: `Protect a cluster so that stopping, scaling, renaming or deleting it first creates an approval
+request. Another user approves it with 'atlas-cli operation approve <id>', then the requester runs
+the command again. Removing protection needs approval too.
+
+Users are identified by their OS account.`,
+	Example: `  atlas-cli cluster protect prod
+  atlas-cli cluster protect prod --remove`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		remove, _ := cmd.Flags().GetBool("remove")
+		if !remove {
+			err := updateApprovals(func(store *approvals.Store) error {
+				store.Protect(args[0], approvals.CurrentUser())
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Cluster '%s' is protected; disruptive operations now need approval\n", args[0])
+			return nil
+		}
+
+		store, err := loadDefault(approvals.DefaultStorePath, approvals.LoadStore)
+		if err != nil {
+			return err
+		}
+		if !store.IsProtected(args[0]) {
+			return fmt.Errorf("cluster %s is not protected", args[0])
+		}
+		if err := requireApproval(args[0], "unprotect", ""); err != nil {
+			return err
+		}
+		err = updateApprovals(func(store *approvals.Store) error {
+			store.Unprotect(args[0])
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Cluster '%s' is no longer protected\n", args[0])
+		return nil
+	},
+}
+
+var operationApproveCmd = &cobra.Command{
+	Use:   "approve [id]",
+	Short: "Approve a pending operation on a protected cluster",
+	Long: `Approve an approval request listed by 'operation list'. The requester can then run the operation
+once within 24 hours. Requests can't be approved by the user who made them.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return decideApproval(args[0], true)
+	},
+}
+
+var operationRejectCmd = &cobra.Command{
+	Use:   "reject [id]",
+	Short: "Reject a pending operation on a protected cluster",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return decideApproval(args[0], false)
+	},
+}
+
+func decideApproval(id string, approve bool) error {
+	services := GetServices()
+	if services == nil {
+		return fmt.Errorf("services not initialized")
+	}
+
+	var request *approvals.Request
+	err := updateApprovals(func(store *approvals.Store) error {
+		var err error
+		request, err = store.Decide(id, approvals.CurrentUser(), approve)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if ok, err := writeStructured(os.Stdout, request); ok {
+		return err
+	}
+	decision := "Rejected"
+	if approve {
+		decision = "Approved"
+	}
+	fmt.Printf("%s %s of cluster '%s' requested by %s (request %s)\n",
+		decision, describeRequest(request), request.Cluster, request.RequestedBy, request.ID)
+	return nil
+}
+
+// updateApprovals changes the approvals file under its lock, so approval checks and decisions
+// made concurrently, e.g. by fleet operations or other users, don't overwrite each other
+func updateApprovals(update func(*approvals.Store) error) error {
+	path, err := approvals.DefaultStorePath()
+	if err != nil {
+		return err
+	}
+	return approvals.Update(path, update)
+}
+
+// requireApproval lets operation on a protected cluster run only with an approval granted to the
+// current user by someone else. Without one it records a pending request and returns an error
+// naming it. Unprotected clusters always pass.
+func requireApproval(clusterName, operation, detail string) error {
+	// most clusters aren't protected, and checking them shouldn't write the file
+	store, err := loadDefault(approvals.DefaultStorePath, approvals.LoadStore)
+	if err != nil {
+		return err
+	}
+	if !store.IsProtected(clusterName) {
+		return nil
+	}
+	var request *approvals.Request
+	err = updateApprovals(func(store *approvals.Store) error {
+		if !store.IsProtected(clusterName) {
+			return nil
+		}
+		var err error
+		request, err = store.Authorize(clusterName, operation, detail, approvals.CurrentUser())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if request == nil {
+		if services := GetServices(); services != nil {
+			services.Log(fmt.Sprintf("Using approval to %s cluster %s", operation, clusterName))
+		}
+		return nil
+	}
+	return fmt.Errorf("cluster %s is protected: %s needs approval (request %s); ask another user to run 'atlas-cli operation approve %s', then run this command again",
+		clusterName, describeRequest(request), request.ID, request.ID)
+}
+
+// describeRequest renders what a request asks for, e.g. "scale to 5 nodes"
+func describeRequest(r *approvals.Request) string {
+	if r.Detail == "" {
+		return r.Operation
+	}
+	return r.Operation + " " + r.Detail
+}
+
+// printApprovalRequests prints open approval requests as a table
+func printApprovalRequests(w io.Writer, requests []*approvals.Request) {
+	t := newTable("ID", "CLUSTER", "OPERATION", "STATE", "REQUESTED BY", "DECIDED BY", "AGE").withSeparator()
+	for _, r := range requests {
+		t.addRow(r.ID, r.Cluster, describeRequest(r), r.State, r.RequestedBy, r.DecidedBy, time.Since(r.RequestedAt).Round(time.Second))
+	}
+	t.render(w)
+}
+
+// renameProtection keeps a renamed cluster protected under its new name
+func renameProtection(oldName, newName string) {
+	store, err := loadDefault(approvals.DefaultStorePath, approvals.LoadStore)
+	if err != nil || !store.IsProtected(oldName) {
+		return
+	}
+	err = updateApprovals(func(store *approvals.Store) error {
+		store.Rename(oldName, newName)
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to move protection of cluster %s to %s: %v\n", oldName, newName, err)
+	}
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterProtectCmd)
+	operationCmd.AddCommand(operationApproveCmd)
+	operationCmd.AddCommand(operationRejectCmd)
+
+	clusterProtectCmd.Flags().Bool("remove", false, "Remove protection; this needs approval like other disruptive operations")
+}
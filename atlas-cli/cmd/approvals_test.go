@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/approvals"
+)
+
+func TestRequireApproval(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := requireApproval("prod", "delete", ""); err != nil {
+		t.Fatalf("requireApproval() on an unprotected cluster error = %v", err)
+	}
+
+	store, err := loadDefault(approvals.DefaultStorePath, approvals.LoadStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Protect("prod", "alice")
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	err = requireApproval("prod", "delete", "")
+	if err == nil || !strings.Contains(err.Error(), "needs approval") {
+		t.Fatalf("requireApproval() error = %v, want a pending approval", err)
+	}
+
+	store, _ = loadDefault(approvals.DefaultStorePath, approvals.LoadStore)
+	request := store.Open()[0]
+	if _, err := store.Decide(request.ID, "alice", true); err != nil {
+		t.Fatalf("Decide() error = %v", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := requireApproval("prod", "delete", ""); err != nil {
+		t.Errorf("requireApproval() after approval error = %v", err)
+	}
+	if err := requireApproval("prod", "delete", ""); err == nil {
+		t.Error("requireApproval() should need a new approval once the last one is used")
+	}
+
+	renameProtection("prod", "production")
+	if err := requireApproval("production", "stop", ""); err == nil {
+		t.Error("a renamed cluster should stay protected")
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+// credentialExpiryWarning is how close to expiry credentials may be before operations warn that
+// they could run out midway
+const credentialExpiryWarning = 15 * time.Minute
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Check cloud credentials",
+}
+
+var authCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Validate a provider's credentials and show who they belong to",
+	Long: `Validate the credentials a cloud provider would use and report the identity and, for temporary
+credentials, when they expire. AWS credentials are checked with sts get-caller-identity, GCP
+credentials by minting a gcloud access token, and Azure credentials through the az login.
+
+Cluster operations on cloud providers run the same check first.`,
+	Example: `  atlas-cli auth check --provider aws --aws-profile prod
+  atlas-cli auth check --provider gcp`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		awsProfile, err := secretResolver.Resolve(commandContext(), awsProfile)
+		if err != nil {
+			return err
+		}
+
+		var check func(ctx context.Context) (*providers.Identity, error)
+		if p, err := services.GetProvider(providerName, region, awsProfile); err == nil {
+			checker, ok := p.(providers.CredentialChecker)
+			if !ok {
+				fmt.Printf("Provider %s runs clusters on this machine and uses no cloud credentials\n", providerName)
+				return nil
+			}
+			check = checker.CheckCredentials
+		} else if check = providers.CloudCredentialCheck(providerName); check == nil {
+			return fmt.Errorf("unknown provider %s; supported: %s", providerName,
+				strings.Join(append(services.GetSupportedProviders(), providers.CloudCredentialNames()...), ", "))
+		}
+
+		identity, err := check(commandContext())
+		if err != nil {
+			return err
+		}
+
+		if ok, err := writeStructured(os.Stdout, identity); ok {
+			return err
+		}
+
+		fmt.Printf("Provider:  %s\n", identity.Provider)
+		fmt.Printf("Identity:  %s\n", identity.Principal)
+		if identity.Account != "" {
+			fmt.Printf("Account:   %s\n", identity.Account)
+		}
+		if identity.Profile != "" {
+			fmt.Printf("Profile:   %s\n", identity.Profile)
+		}
+		if identity.ExpiresAt != nil {
+			fmt.Printf("Expires:   %s (in %s)\n", identity.ExpiresAt.Local().Format(time.RFC3339),
+				time.Until(*identity.ExpiresAt).Round(time.Minute))
+		} else {
+			fmt.Println("Expires:   never (long-lived credentials)")
+		}
+		return nil
+	},
+}
+
+// requireCredentials checks p's cloud credentials before an operation, so a missing or expired
+// login fails up front with instructions instead of partway through. Providers that run on this
+// machine pass without a check.
+func requireCredentials(ctx context.Context, p providers.Provider) error {
+	checker, ok := p.(providers.CredentialChecker)
+	if !ok {
+		return nil
+	}
+	identity, err := checker.CheckCredentials(ctx)
+	if err != nil {
+		return fmt.Errorf("credential check failed: %w", err)
+	}
+	if identity.ExpiresWithin(time.Now(), credentialExpiryWarning) {
+		fmt.Fprintf(os.Stderr, "Warning: %s credentials for %s expire at %s and may run out during this operation\n",
+			identity.Provider, identity.Principal, identity.ExpiresAt.Local().Format(time.Kitchen))
+	}
+	if services := GetServices(); services != nil {
+		services.Log(fmt.Sprintf("Authenticated to %s as %s", identity.Provider, identity.Principal))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authCheckCmd)
+
+	authCheckCmd.Flags().StringP("provider", "p", "aws", "Provider whose credentials to check (aws, gcp, azure)")
+	authCheckCmd.Flags().StringP("region", "r", "", "Region to check against")
+	authCheckCmd.Flags().String("aws-profile", "", "AWS profile to check; may be a secret reference such as env://AWS_PROFILE")
+}
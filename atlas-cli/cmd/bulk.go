@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/fleet"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+// exitPartialFailure is the exit status of a bulk operation that failed on some of its clusters
+// but not all, so scripts can tell it from a complete failure (exit status 1)
+const exitPartialFailure = 2
+
+// liveSummaryInterval is how often a live bulk operation summary is redrawn
+const liveSummaryInterval = 500 * time.Millisecond
+
+// renderLiveSummary draws summary on w and redraws it in place until the returned function is
+// called, which draws it a final time. w must be a terminal, since each redraw moves the cursor back
+// up over the previous one.
+func renderLiveSummary(w io.Writer, summary *progress.Summary) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	lines := summary.Render(w)
+	redraw := func() {
+		fmt.Fprintf(w, "\033[%dA\033[J", lines)
+		lines = summary.Render(w)
+	}
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(liveSummaryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				redraw()
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+		redraw()
+	}
+}
+
+// bulkOperationError turns the failures in summary into the command's error: nil when every
+// cluster succeeded, and an error exiting with exitPartialFailure when only some failed
+func bulkOperationError(summary *progress.Summary, op, scope string) error {
+	failed, total := len(summary.Failed()), len(summary.Results())
+	switch {
+	case failed == 0:
+		return nil
+	case failed == total:
+		return fmt.Errorf("failed to %s all %d clusters in %s", op, total, scope)
+	default:
+		return &exitError{code: exitPartialFailure, err: fmt.Errorf("failed to %s %d of %d clusters in %s", op, failed, total, scope)}
+	}
+}
+
+// bulkRun performs a bulk operation's work on one cluster
+type bulkRun func(ctx context.Context, p providers.Provider, cluster string) error
+
+// runBulkOperation runs op against every member concurrently. On a terminal it shows a live table
+// of each member's phase, duration and result; otherwise the members' progress is streamed and
+// the table printed at the end. Failed members are listed with their errors, and the command
+// fails, with exitPartialFailure if only some members failed. scope names the members in errors,
+// e.g. "fleet dev", and fields are added to the JSON output.
+func runBulkOperation(ctx context.Context, members []fleet.Member, op, scope string, fields map[string]any, awsProfile string, run bulkRun) error {
+	services := GetServices()
+	if services == nil {
+		return fmt.Errorf("services not initialized")
+	}
+
+	names := make([]string, len(members))
+	for i, member := range members {
+		names[i] = member.String()
+	}
+	summary := progress.NewSummary(names...)
+	structured := currentOutputFormat().structured()
+	live := !structured && isTerminal(os.Stdout)
+	next := progress.FromContext(ctx)
+	stopLive := func() {}
+	if live {
+		next = progress.Discard
+		stopLive = renderLiveSummary(os.Stdout, summary)
+	}
+
+	var wg sync.WaitGroup
+	for i, member := range members {
+		wg.Add(1)
+		go func(name string, member fleet.Member) {
+			defer wg.Done()
+			summary.Start(name)
+			ctx := progress.WithReporter(ctx, summary.Reporter(name, next))
+			p, err := services.GetProvider(member.Provider, member.Region, awsProfile)
+			if err == nil {
+				err = requireCredentials(ctx, p)
+			}
+			if err == nil {
+				err = run(ctx, p, member.Cluster)
+			}
+			if err == nil {
+				syncInventory(ctx, p, member.Cluster)
+			}
+			summary.Finish(name, err)
+		}(names[i], member)
+	}
+	wg.Wait()
+	stopLive()
+
+	if structured {
+		result := map[string]any{"operation": op, "results": summary.Results()}
+		for key, value := range fields {
+			result[key] = value
+		}
+		if _, err := writeStructured(os.Stdout, result); err != nil {
+			return err
+		}
+	} else {
+		if !live {
+			summary.Render(os.Stdout)
+		}
+		summary.RenderErrors(os.Stdout)
+	}
+	return bulkOperationError(summary, op, scope)
+}
+
+// nameOrSelector accepts a single cluster name, or none when --selector picks the clusters
+func nameOrSelector(cmd *cobra.Command, args []string) error {
+	if selector, _ := cmd.Flags().GetString("selector"); selector != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("pass either a cluster name or --selector, not both")
+		}
+		return nil
+	}
+	return cobra.ExactArgs(1)(cmd, args)
+}
+
+// selectedMembers lists the clusters on p whose tags match selector
+func selectedMembers(ctx context.Context, p providers.Provider, region string, selector fleet.Selector) ([]fleet.Member, error) {
+	clusters, err := p.ListClusters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	}
+	var members []fleet.Member
+	for _, cluster := range clusters {
+		if selector.Matches(cluster.Tags) {
+			members = append(members, fleet.Member{Cluster: cluster.Name, Provider: p.GetProviderName(), Region: region})
+		}
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("no %s clusters match selector %s", p.GetProviderName(), selector)
+	}
+	return members, nil
+}
+
+// runSelectorOperation runs op concurrently against every cluster on cmd's --provider whose tags
+// match its --selector; see runBulkOperation. confirm, when set, can refuse the matched clusters
+// before anything runs.
+func runSelectorOperation(cmd *cobra.Command, op string, confirm func(members []fleet.Member) error, run bulkRun) error {
+	services := GetServices()
+	if services == nil {
+		return fmt.Errorf("services not initialized")
+	}
+	rawSelector, _ := cmd.Flags().GetString("selector")
+	providerName, _ := cmd.Flags().GetString("provider")
+	region, _ := cmd.Flags().GetString("region")
+	awsProfile, _ := cmd.Flags().GetString("aws-profile")
+
+	selector, err := fleet.ParseSelector(rawSelector)
+	if err != nil {
+		return err
+	}
+	p, err := services.GetProvider(providerName, region, awsProfile)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+	ctx := commandContext()
+	if err := requireCredentials(ctx, p); err != nil {
+		return err
+	}
+	members, err := selectedMembers(ctx, p, region, selector)
+	if err != nil {
+		return err
+	}
+	if confirm != nil {
+		if err := confirm(members); err != nil {
+			return err
+		}
+	}
+	services.Log(fmt.Sprintf("Running %s on %d clusters matching %s", op, len(members), selector))
+	return runBulkOperation(ctx, members, op, "clusters matching "+selector.String(),
+		map[string]any{"selector": selector.String()}, awsProfile, run)
+}
+
+// confirmSelected asks before running op on members, unless yes is set. Without a terminal to ask
+// on, the operation is refused.
+func confirmSelected(op string, yes bool) func(members []fleet.Member) error {
+	return func(members []fleet.Member) error {
+		if yes {
+			return nil
+		}
+		if !isTerminal(os.Stdin) {
+			return fmt.Errorf("refusing to %s %d clusters without confirmation; pass --yes", op, len(members))
+		}
+		names := make([]string, len(members))
+		for i, member := range members {
+			names[i] = member.Cluster
+		}
+		fmt.Printf("This will %s %d clusters: %s\n", op, len(members), strings.Join(names, ", "))
+		proceed, err := newPrompter(os.Stdin, os.Stdout).confirm("Continue?", false)
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			return fmt.Errorf("%s canceled", op)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+)
+
+func TestBulkOperationError(t *testing.T) {
+	summary := progress.NewSummary("dev-1", "dev-2")
+	summary.Finish("dev-1", nil)
+	summary.Finish("dev-2", nil)
+	if err := bulkOperationError(summary, "stop", "fleet dev"); err != nil {
+		t.Errorf("bulkOperationError() with no failures = %v", err)
+	}
+
+	summary.Finish("dev-2", errors.New("boom"))
+	err := bulkOperationError(summary, "stop", "fleet dev")
+	var exitErr *exitError
+	if !errors.As(err, &exitErr) || exitErr.code != exitPartialFailure {
+		t.Errorf("bulkOperationError() with a partial failure = %v, want exit status %d", err, exitPartialFailure)
+	}
+	if err.Error() != "failed to stop 1 of 2 clusters in fleet dev" {
+		t.Errorf("bulkOperationError() = %q", err)
+	}
+
+	summary.Finish("dev-1", errors.New("boom"))
+	err = bulkOperationError(summary, "stop", "fleet dev")
+	if err == nil || errors.As(err, &exitErr) {
+		t.Errorf("bulkOperationError() with every cluster failed = %v, want a plain error", err)
+	}
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the images and binaries local clusters are built from",
+	Long: `Manage the dependency cache shared by local clusters. minikube keeps the kicbase node image, the
+preload tarball of each Kubernetes version's images and the kubeadm, kubelet and kubectl binaries
+in ~/.minikube/cache (or $MINIKUBE_HOME/cache). Warming the cache before creating clusters keeps
+repeated creates from downloading them again; pruning removes versions no cluster uses.`,
+}
+
+var cacheWarmCmd = &cobra.Command{
+	Use:   "warm",
+	Short: "Download the dependencies of a Kubernetes version ahead of cluster creation",
+	Long: `Download the node image, preload tarball and binaries for each Kubernetes version given with
+--version, without creating a cluster. Later creates of that version start from the cache.`,
+	Example: `  atlas-cli cache warm --version v1.31.0
+  atlas-cli cache warm --version v1.31.0 --version v1.30.0`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		providerName, _ := cmd.Flags().GetString("provider")
+		versions, _ := cmd.Flags().GetStringSlice("version")
+		cacher, p, err := dependencyCacher(providerName)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			versions = p.GetSupportedVersions()[:1]
+		}
+
+		ctx := commandContext()
+		for _, version := range versions {
+			services.Log(fmt.Sprintf("Warming dependency cache for Kubernetes %s", version))
+			if err := cacher.WarmCache(ctx, version); err != nil {
+				return err
+			}
+		}
+		entries, err := cacher.CacheEntries(ctx)
+		if err != nil {
+			return err
+		}
+		// everything not prunable when keeping the warmed versions belongs to them
+		prunable := providers.PrunableCacheEntries(entries, versions, false)
+		var warmed []providers.CacheEntry
+		for _, entry := range entries {
+			if !slices.Contains(prunable, entry) {
+				warmed = append(warmed, entry)
+			}
+		}
+
+		if ok, err := writeStructured(os.Stdout, warmed); ok || err != nil {
+			return err
+		}
+		fmt.Printf("Cached dependencies for Kubernetes %s\n", strings.Join(versions, ", "))
+		printCacheEntries(warmed)
+		return nil
+	},
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove cached dependencies of Kubernetes versions no cluster uses",
+	Long: `Remove the preload tarballs and binaries of every Kubernetes version that no existing cluster of
+the provider runs and that isn't listed with --keep. The node image and loaded images are shared
+by every version and only removed with --all, which empties the cache.`,
+	Example: `  atlas-cli cache prune --dry-run
+  atlas-cli cache prune --keep v1.30.0
+  atlas-cli cache prune --all`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		providerName, _ := cmd.Flags().GetString("provider")
+		keep, _ := cmd.Flags().GetStringSlice("keep")
+		all, _ := cmd.Flags().GetBool("all")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		cacher, p, err := dependencyCacher(providerName)
+		if err != nil {
+			return err
+		}
+
+		ctx := commandContext()
+		if !all {
+			clusters, err := p.ListClusters(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list clusters: %w", err)
+			}
+			for _, cluster := range clusters {
+				if cluster.Version != "" {
+					keep = append(keep, cluster.Version)
+				}
+			}
+		}
+		entries, err := cacher.CacheEntries(ctx)
+		if err != nil {
+			return err
+		}
+		prunable := providers.PrunableCacheEntries(entries, keep, all)
+
+		if !dryRun && len(prunable) > 0 {
+			services.Log(fmt.Sprintf("Pruning %d dependency cache entries", len(prunable)))
+			if err := cacher.RemoveCacheEntries(ctx, prunable); err != nil {
+				return err
+			}
+		}
+
+		if ok, err := writeStructured(os.Stdout, prunable); ok || err != nil {
+			return err
+		}
+		if len(prunable) == 0 {
+			fmt.Println("Nothing to prune")
+			return nil
+		}
+		if dryRun {
+			fmt.Println("Would remove:")
+		} else {
+			fmt.Println("Removed:")
+		}
+		printCacheEntries(prunable)
+		return nil
+	},
+}
+
+// dependencyCacher returns the named provider if it keeps a dependency cache
+func dependencyCacher(providerName string) (providers.DependencyCacher, providers.Provider, error) {
+	p, err := GetServices().GetProvider(providerName, "", "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get provider: %w", err)
+	}
+	cacher, ok := p.(providers.DependencyCacher)
+	if !ok {
+		return nil, nil, fmt.Errorf("provider %s does not cache cluster dependencies", p.GetProviderName())
+	}
+	return cacher, p, nil
+}
+
+// printCacheEntries prints entries as a table sorted by kind and version, followed by their total size
+func printCacheEntries(entries []providers.CacheEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind < entries[j].Kind
+		}
+		return entries[i].Version < entries[j].Version
+	})
+	var total int64
+	t := newTable("KIND", "VERSION", "SIZE", "PATH")
+	for _, entry := range entries {
+		t.addRow(entry.Kind, valueOrDash(entry.Version), formatSize(entry.Size), entry.Path)
+		total += entry.Size
+	}
+	t.render(os.Stdout)
+	fmt.Printf("Total: %s\n", formatSize(total))
+}
+
+// formatSize formats a byte count with a binary unit
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheWarmCmd, cachePruneCmd)
+
+	cacheCmd.PersistentFlags().StringP("provider", "p", "local", "Provider whose cache to manage (local)")
+	cacheWarmCmd.Flags().StringSlice("version", nil, "Kubernetes version to download dependencies for (repeatable, default: the newest supported)")
+	cachePruneCmd.Flags().StringSlice("keep", nil, "Kubernetes version to keep even if no cluster runs it (repeatable)")
+	cachePruneCmd.Flags().Bool("all", false, "Remove everything in the cache, including the node image")
+	cachePruneCmd.Flags().Bool("dry-run", false, "List what would be removed without removing it")
+}
@@ -1,16 +1,30 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/annotations"
 	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
 	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/operations"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/ports"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
 	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/timings"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -36,94 +50,163 @@ var clusterCreateCmd = &cobra.Command{
 		services.Log(fmt.Sprintf("Creating cluster: %s", clusterName))
 
 		configFile, _ := cmd.Flags().GetString("config")
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		providerName, _ := cmd.Flags().GetString("provider")
+		maxDuration, _ := cmd.Flags().GetDuration("max-duration")
+		sloFlags, _ := cmd.Flags().GetStringArray("phase-slo")
+		phaseSLOs, err := parsePhaseSLOs(sloFlags)
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("preset") && (interactive || configFile != "") {
+			return fmt.Errorf("--preset cannot be combined with --config or --interactive")
+		}
+		if explain, _ := cmd.Flags().GetBool("explain-config"); explain && interactive {
+			return fmt.Errorf("--explain-config cannot be combined with --interactive")
+		}
 		var config *providers.ClusterConfig
 
-		if configFile != "" {
+		if interactive {
+			prompt := newPrompter(os.Stdin, os.Stdout)
 			var err error
-			config, err = loadClusterConfig(configFile)
+			providerName, config, err = runCreateWizard(prompt, clusterName, services.GetProviderFactory())
+			if err != nil {
+				return err
+			}
+			proceed, err := prompt.confirm(fmt.Sprintf("Create cluster %s now?", clusterName), true)
 			if err != nil {
-				return fmt.Errorf("failed to load config file: %w", err)
+				return err
+			}
+			if !proceed {
+				return nil
 			}
-			config.Name = clusterName
 		} else {
-			region, _ := cmd.Flags().GetString("region")
-			nodeCount, _ := cmd.Flags().GetInt("nodes")
-			version, _ := cmd.Flags().GetString("version")
-			instanceType, _ := cmd.Flags().GetString("instance-type")
-
-			config = &providers.ClusterConfig{
-				Name:         clusterName,
-				Region:       region,
-				NodeCount:    nodeCount,
-				Version:      version,
-				InstanceType: instanceType,
-			}
-
-			enableIngress, _ := cmd.Flags().GetBool("enable-ingress")
-			enableLoadBalancer, _ := cmd.Flags().GetBool("enable-load-balancer")
-			enableRBAC, _ := cmd.Flags().GetBool("enable-rbac")
-			enableNetworkPolicy, _ := cmd.Flags().GetBool("enable-network-policy")
-			enableMonitoring, _ := cmd.Flags().GetBool("enable-monitoring")
-			apiServerPort, _ := cmd.Flags().GetInt("api-server-port")
-			cpuLimit, _ := cmd.Flags().GetString("cpu-limit")
-			memoryLimit, _ := cmd.Flags().GetString("memory-limit")
-
-			if enableIngress || enableLoadBalancer || apiServerPort > 0 {
-				config.NetworkConfig = &providers.NetworkConfig{}
-				if enableIngress {
-					config.NetworkConfig.Ingress = &providers.IngressConfig{Enabled: true}
-				}
-				if enableLoadBalancer {
-					config.NetworkConfig.LoadBalancer = &providers.LoadBalancerConfig{Enabled: true}
-				}
-				if apiServerPort > 0 {
-					config.NetworkConfig.APIServerPort = apiServerPort
-				}
+			var sources map[string]string
+			var err error
+			config, sources, err = resolveClusterConfig(cmd, clusterName, providerName)
+			if err != nil {
+				return err
 			}
-
-			if enableRBAC || enableNetworkPolicy {
-				config.SecurityConfig = &providers.SecurityConfig{}
-				if enableRBAC {
-					config.SecurityConfig.RBAC = &providers.RBACConfig{Enabled: true}
-				}
-				if enableNetworkPolicy {
-					config.SecurityConfig.NetworkPolicy = &providers.NetworkPolicyConfig{Enabled: true}
-				}
+			if explain, _ := cmd.Flags().GetBool("explain-config"); explain {
+				return explainClusterConfig(config, sources)
 			}
+		}
 
-			if enableMonitoring || cpuLimit != "" || memoryLimit != "" {
-				config.ResourceConfig = &providers.ResourceConfig{}
-				if enableMonitoring {
-					config.ResourceConfig.Monitoring = &providers.MonitoringConfig{
-						Enabled:    true,
-						Prometheus: &providers.PrometheusConfig{Enabled: true},
-					}
-				}
-				if cpuLimit != "" || memoryLimit != "" {
-					config.ResourceConfig.Limits = &providers.ResourceLimits{
-						CPU:    cpuLimit,
-						Memory: memoryLimit,
-					}
-				}
-			}
+		// References are resolved after --explain-config so it shows them instead of the secrets
+		if err := secretResolver.ResolveAll(commandContext(), config); err != nil {
+			return err
 		}
 
-		providerName, _ := cmd.Flags().GetString("provider")
 		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		awsProfile, err = secretResolver.Resolve(commandContext(), awsProfile)
+		if err != nil {
+			return err
+		}
+		if awsProfile == "" && config.AWS != nil {
+			awsProfile = config.AWS.Profile
+		}
 		
 		p, err := services.GetProvider(providerName, config.Region, awsProfile)
 		if err != nil {
 			return fmt.Errorf("failed to create provider: %w", err)
 		}
 
-		if err := p.ValidateConfig(config); err != nil {
-			return fmt.Errorf("configuration validation failed: %w", err)
+		validateOnly, _ := cmd.Flags().GetBool("validate-only")
+		if err := checkClusterConfig(p, config, validateOnly); err != nil || validateOnly {
+			return err
 		}
 
-		_, err = p.CreateCluster(context.Background(), config)
+		reservation, err := reserveClusterPorts(p, config)
 		if err != nil {
+			return err
+		}
+		defer reservation.release()
+
+		ctx := commandContext()
+		if err := requireCredentials(ctx, p); err != nil {
+			return err
+		}
+
+		if quotaChecker, ok := p.(providers.QuotaChecker); ok {
+			ignoreQuota, _ := cmd.Flags().GetBool("ignore-quota-check")
+			checks, err := quotaChecker.CheckCreateQuotas(ctx, config)
+			if err := enforceQuotas(checks, err, ignoreQuota); err != nil {
+				return err
+			}
+		}
+
+		release, err := services.GetOperationLimiter().Acquire(ctx, &operations.Operation{
+			Type:     "create",
+			Cluster:  clusterName,
+			Provider: p.GetProviderName(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to acquire operation slot: %w", err)
+		}
+		defer release()
+
+		// the budget and phase timings cover the provider's work, not waiting for a slot
+		recorder := timings.NewRecorder(progress.FromContext(ctx))
+		createCtx := progress.WithReporter(ctx, recorder)
+		if maxDuration > 0 {
+			var cancel context.CancelFunc
+			createCtx, cancel = context.WithTimeout(createCtx, maxDuration)
+			defer cancel()
+		}
+		started := time.Now()
+
+		if localProvider, ok := p.(*providers.LocalProvider); ok {
+			autoFit, _ := cmd.Flags().GetBool("auto-fit")
+			if err := localProvider.Preflight(createCtx, config, autoFit); err != nil {
+				return fmt.Errorf("preflight check failed: %w", err)
+			}
+		}
+
+		cluster, err := p.CreateCluster(createCtx, config)
+		budgetExceeded := maxDuration > 0 && ctx.Err() == nil && errors.Is(createCtx.Err(), context.DeadlineExceeded)
+		recordPhaseTimings(recorder, &timings.Record{
+			Cluster:        clusterName,
+			Provider:       p.GetProviderName(),
+			Operation:      string(logsource.OpTypeCreate),
+			StartedAt:      started,
+			BudgetMS:       maxDuration.Milliseconds(),
+			BudgetExceeded: budgetExceeded,
+		}, phaseSLOs)
+		if err != nil {
+			if budgetExceeded {
+				during := ""
+				if phase := recorder.Current(); phase != "" {
+					during = " during " + phase
+				}
+				if rollbackErr := rollbackCreate(ctx, p, clusterName); rollbackErr != nil {
+					return fmt.Errorf("cluster creation exceeded its %s budget%s (rollback failed: %v)", maxDuration, during, rollbackErr)
+				}
+				return fmt.Errorf("cluster creation exceeded its %s budget%s, partially created cluster %s was deleted", maxDuration, during, clusterName)
+			}
+			rollback, _ := cmd.Flags().GetBool("rollback-on-cancel")
+			if rollback && ctx.Err() != nil {
+				if rollbackErr := rollbackCreate(ctx, p, clusterName); rollbackErr != nil {
+					return fmt.Errorf("failed to create cluster: %w (rollback failed: %v)", err, rollbackErr)
+				}
+				return fmt.Errorf("cluster creation canceled, partially created cluster %s was deleted", clusterName)
+			}
 			return fmt.Errorf("failed to create cluster: %w", err)
 		}
+		reservation.keep()
+		syncInventory(ctx, p, clusterName)
+		if manager, ok := p.(providers.AddonManager); ok {
+			for _, addon := range config.Addons {
+				if err := manager.EnableAddon(ctx, clusterName, addon); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+			}
+		} else if len(config.Addons) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: provider %s does not support enabling addons; skipping %s\n",
+				p.GetProviderName(), strings.Join(config.Addons, ", "))
+		}
+		for _, resource := range cluster.Resources {
+			services.Log(fmt.Sprintf("Bootstrap resource %s/%s from %s", resource.Kind, resource.Name, resource.Source))
+		}
 		services.Log("Cluster creation initiated successfully")
 		return nil
 	},
@@ -132,7 +215,14 @@ var clusterCreateCmd = &cobra.Command{
 var clusterListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all clusters",
-	Long:  `List all clusters managed by Atlas CLI.`,
+	Long: `List all clusters managed by Atlas CLI.
+
+With --provider all, clusters from every registered provider are listed together. --filter keeps
+only clusters whose field matches a value, which may use shell wildcards; repeat it to require
+several matches.`,
+	Example: `  atlas-cli cluster list --provider all --filter status=running
+  atlas-cli cluster list --filter 'name!=ci-*' --filter tag.team=web
+  atlas-cli cluster list --provider all --sort-by version`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		services := GetServices()
 		if services == nil {
@@ -144,39 +234,84 @@ var clusterListCmd = &cobra.Command{
 		awsProfile, _ := cmd.Flags().GetString("aws-profile")
 		region, _ := cmd.Flags().GetString("region")
 		
-		p, err := services.GetProvider(providerName, region, awsProfile)
-		if err != nil {
-			return fmt.Errorf("failed to create provider: %w", err)
+		var clusters []*providers.Cluster
+		var err error
+		if providerName == "all" {
+			var failures map[string]error
+			clusters, failures = listAllClusters(commandContext(), services.GetProviderFactory(), region, awsProfile)
+			for _, name := range sortedKeys(failures) {
+				fmt.Fprintf(os.Stderr, "Warning: failed to list %s clusters: %v\n", name, failures[name])
+			}
+			if len(failures) > 0 && len(failures) == len(services.GetSupportedProviders()) {
+				return fmt.Errorf("error listing clusters: every provider failed")
+			}
+		} else {
+			p, err := services.GetProvider(providerName, region, awsProfile)
+			if err != nil {
+				return fmt.Errorf("failed to create provider: %w", err)
+			}
+
+			clusters, err = p.ListClusters(commandContext())
+			if err != nil {
+				return fmt.Errorf("error listing clusters: %s", err)
+			}
 		}
-		
-		clusters, err := p.ListClusters(context.Background())
 
+		filter := clusterFilter{Region: region}
+		filter.Status, _ = cmd.Flags().GetString("status")
+		tagFlags, _ := cmd.Flags().GetStringArray("tag")
+		filter.Tags, err = parseTagFilters(tagFlags)
+		if err != nil {
+			return err
+		}
+		filterFlags, _ := cmd.Flags().GetStringArray("filter")
+		filter.Fields, err = parseFieldFilters(filterFlags)
 		if err != nil {
-			return fmt.Errorf("error listing clusters: %s", err)
+			return err
+		}
+		sortBy, _ := cmd.Flags().GetString("sort-by")
+		if cmd.Flags().Changed("sort") {
+			sortBy, _ = cmd.Flags().GetString("sort")
 		}
 
-		if len(clusters) == 0 {
-			fmt.Println("No clusters found")
-			return nil
+		clusters = filterClusters(clusters, filter)
+		if err := sortClusters(clusters, sortBy); err != nil {
+			return err
 		}
 
-		if services.GetOutput() == "json" {
-			jsonOutput, err := json.MarshalIndent(clusters, "", "  ")
+		withHealth, _ := cmd.Flags().GetBool("with-health")
+		if withHealth {
+			cachePath, err := monitoring.DefaultHealthCachePath()
 			if err != nil {
-				return fmt.Errorf("failed to marshal clusters: %w", err)
+				return err
 			}
-			fmt.Println(string(jsonOutput))
-		} else {
-			fmt.Printf("%-20s %-10s %-15s %-6s %-10s\n", "NAME", "PROVIDER", "REGION", "NODES", "STATUS")
-			fmt.Printf("%-20s %-10s %-15s %-6s %-10s\n", "----", "--------", "------", "-----", "------")
+			cache := monitoring.NewHealthCache(cachePath, monitoring.DefaultHealthCacheTTL)
+			annotateHealth(commandContext(), clusters, cache, func(providerName string) (providers.Provider, error) {
+				return services.GetProvider(providerName, region, awsProfile)
+			})
+			if err := cache.Save(); err != nil {
+				services.Log(fmt.Sprintf("Failed to save health cache: %v", err))
+			}
+		}
+		warnVersionSupport(clusters...)
+
+		if clusters == nil {
+			clusters = []*providers.Cluster{}
+		}
+		if ok, err := writeStructured(os.Stdout, clusters); ok {
+			return err
+		}
+		switch format := currentOutputFormat(); format.Kind {
+		case outputName:
 			for _, cluster := range clusters {
-				fmt.Printf("%-20s %-10s %-15s %-6v %-10s\n",
-					cluster.Name,
-					cluster.Provider,
-					cluster.Region,
-					cluster.NodeCount,
-					cluster.Status)
+				fmt.Println(cluster.Name)
 			}
+		default:
+			if len(clusters) == 0 {
+				fmt.Println("No clusters found")
+				return nil
+			}
+			printClusterTable(os.Stdout, clusters, withHealth, format.Kind == outputWide)
 		}
 
 		services.Log("Listed clusters successfully")
@@ -187,42 +322,105 @@ var clusterListCmd = &cobra.Command{
 var clusterDeleteCmd = &cobra.Command{
 	Use:   "delete [name]",
 	Short: "Delete a cluster",
-	Long:  `Delete a Kubernetes cluster by name.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Delete a Kubernetes cluster by name.
+
+With --selector, every cluster on the provider whose tags match is deleted concurrently instead,
+with a summary of each cluster's result. The matched clusters are listed for confirmation first;
+pass --yes to skip it. Exits with status 2 if only some of them failed.`,
+	Example: `  atlas-cli cluster delete dev
+  atlas-cli cluster delete --selector env=preview,pr --yes`,
+	Args: nameOrSelector,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		services := GetServices()
 		if services == nil {
 			return fmt.Errorf("services not initialized")
 		}
+		if selector, _ := cmd.Flags().GetString("selector"); selector != "" {
+			yes, _ := cmd.Flags().GetBool("yes")
+			return runSelectorOperation(cmd, "delete", confirmSelected("delete", yes), func(ctx context.Context, p providers.Provider, cluster string) error {
+				warnOutsideMaintenanceWindow(cluster, "deleting")
+				if err := requireApproval(cluster, "delete", ""); err != nil {
+					return err
+				}
+				if err := p.DeleteCluster(ctx, cluster); err != nil {
+					return err
+				}
+				services.TeardownCluster(ctx, p, cluster)
+				return nil
+			})
+		}
 
 		clusterName := args[0]
 		services.Log(fmt.Sprintf("Deleting cluster: %s", clusterName))
+		warnOutsideMaintenanceWindow(clusterName, "deleting")
+		if err := requireApproval(clusterName, "delete", ""); err != nil {
+			return err
+		}
 
-		var p providers.Provider
-		var err error
-		p, err = services.GetProvider("local", "local", "")
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		wait, _ := cmd.Flags().GetBool("wait")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		p, err := services.GetProvider(providerName, region, awsProfile)
 		if err != nil {
 			return fmt.Errorf("failed to get provider: %w", err)
 		}
-		err = p.DeleteCluster(context.Background(), clusterName)
+
+		ctx := commandContext()
+		if err := requireCredentials(ctx, p); err != nil {
+			return err
+		}
+		force, _ := cmd.Flags().GetBool("force")
+		if force {
+			forceDeleter, ok := p.(providers.ForceDeleter)
+			if !ok {
+				return fmt.Errorf("provider %s does not support --force", p.GetProviderName())
+			}
+			err = forceDeleter.ForceDeleteCluster(ctx, clusterName)
+		} else {
+			err = p.DeleteCluster(ctx, clusterName)
+		}
 		if err != nil {
+			if force {
+				services.TeardownCluster(ctx, p, clusterName)
+			}
 			return fmt.Errorf("failed to delete cluster: %w", err)
 		}
 
+		if wait {
+			waiter, ok := p.(providers.DeletionWaiter)
+			if !ok {
+				return fmt.Errorf("provider %s does not support --wait", p.GetProviderName())
+			}
+			services.Log(fmt.Sprintf("Waiting up to %s for cluster %s to be removed", timeout, clusterName))
+			if err := waiter.WaitForDeletion(ctx, clusterName, timeout); err != nil {
+				return fmt.Errorf("failed to verify cluster deletion: %w", err)
+			}
+		}
+
+		teardown := services.TeardownCluster(ctx, p, clusterName)
+		syncInventory(ctx, p, clusterName)
+
 		result := map[string]any{
-			"name":    clusterName,
-			"status":  "deleted",
-			"message": fmt.Sprintf("Cluster '%s' deleted successfully", clusterName),
+			"name":     clusterName,
+			"status":   "deleted",
+			"message":  fmt.Sprintf("Cluster '%s' deleted successfully", clusterName),
+			"teardown": teardown,
 		}
 
-		if services.GetOutput() == "json" {
-			jsonOutput, err := json.MarshalIndent(result, "", "  ")
-			if err != nil {
-				return fmt.Errorf("failed to marshal result: %w", err)
-			}
-			fmt.Println(string(jsonOutput))
-		} else {
+		ok, err := writeStructured(os.Stdout, result)
+		if err != nil {
+			return err
+		}
+		if !ok {
 			fmt.Printf("Cluster '%s' deleted successfully\n", clusterName)
+			for _, step := range teardown {
+				if step.Error != "" {
+					fmt.Printf("Warning: teardown step %s failed: %s\n", step.Step, step.Error)
+				}
+			}
 		}
 
 		services.Log("Cluster deletion completed successfully")
@@ -233,27 +431,41 @@ var clusterDeleteCmd = &cobra.Command{
 var clusterStartCmd = &cobra.Command{
 	Use:   "start [name]",
 	Short: "Start a cluster",
-	Long:  `Start a stopped Kubernetes cluster by name.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Start a stopped Kubernetes cluster by name.
+
+With --selector, every cluster on the provider whose tags match is started concurrently instead,
+with a summary of each cluster's result. Exits with status 2 if only some of them failed.`,
+	Example: `  atlas-cli cluster start dev
+  atlas-cli cluster start --selector env=dev,team=payments`,
+	Args: nameOrSelector,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		services := GetServices()
 		if services == nil {
 			return fmt.Errorf("services not initialized")
 		}
+		if selector, _ := cmd.Flags().GetString("selector"); selector != "" {
+			return runSelectorOperation(cmd, "start", nil, func(ctx context.Context, p providers.Provider, cluster string) error {
+				return p.StartCluster(ctx, cluster)
+			})
+		}
 
 		clusterName := args[0]
 		services.Log(fmt.Sprintf("Starting cluster: %s", clusterName))
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
 
 		var p providers.Provider
 		var err error
-		p, err = services.GetProvider("local", "local", "")
+		p, err = services.GetProvider(providerName, region, awsProfile)
 		if err != nil {
 			return fmt.Errorf("failed to get provider: %w", err)
 		}
-		err = p.StartCluster(context.Background(), clusterName)
+		err = p.StartCluster(commandContext(), clusterName)
 		if err != nil {
 			return fmt.Errorf("failed to start cluster: %w", err)
 		}
+		syncInventory(commandContext(), p, clusterName)
 
 		result := map[string]any{
 			"name":    clusterName,
@@ -261,13 +473,11 @@ var clusterStartCmd = &cobra.Command{
 			"message": fmt.Sprintf("Cluster '%s' started successfully", clusterName),
 		}
 
-		if services.GetOutput() == "json" {
-			jsonOutput, err := json.MarshalIndent(result, "", "  ")
-			if err != nil {
-				return fmt.Errorf("failed to marshal result: %w", err)
-			}
-			fmt.Println(string(jsonOutput))
-		} else {
+		ok, err := writeStructured(os.Stdout, result)
+		if err != nil {
+			return err
+		}
+		if !ok {
 			fmt.Printf("Cluster '%s' started successfully\n", clusterName)
 		}
 
@@ -279,27 +489,51 @@ var clusterStartCmd = &cobra.Command{
 var clusterStopCmd = &cobra.Command{
 	Use:   "stop [name]",
 	Short: "Stop a cluster",
-	Long:  `Stop a running Kubernetes cluster by name.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Stop a running Kubernetes cluster by name.
+
+With --selector, every cluster on the provider whose tags match is stopped concurrently instead,
+with a summary of each cluster's result. Exits with status 2 if only some of them failed.`,
+	Example: `  atlas-cli cluster stop dev
+  atlas-cli cluster stop --selector env=dev
+  atlas-cli cluster stop -p aws -r us-west-2 --selector 'env=staging,!keep-running'`,
+	Args: nameOrSelector,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		services := GetServices()
 		if services == nil {
 			return fmt.Errorf("services not initialized")
 		}
+		if selector, _ := cmd.Flags().GetString("selector"); selector != "" {
+			return runSelectorOperation(cmd, "stop", nil, func(ctx context.Context, p providers.Provider, cluster string) error {
+				warnOutsideMaintenanceWindow(cluster, "stopping")
+				if err := requireApproval(cluster, "stop", ""); err != nil {
+					return err
+				}
+				return p.StopCluster(ctx, cluster)
+			})
+		}
 
 		clusterName := args[0]
 		services.Log(fmt.Sprintf("Stopping cluster: %s", clusterName))
+		warnOutsideMaintenanceWindow(clusterName, "stopping")
+		if err := requireApproval(clusterName, "stop", ""); err != nil {
+			return err
+		}
+
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
 
 		var p providers.Provider
 		var err error
-		p, err = services.GetProvider("local", "local", "")
+		p, err = services.GetProvider(providerName, region, awsProfile)
 		if err != nil {
 			return fmt.Errorf("failed to get provider: %w", err)
 		}
-		err = p.StopCluster(context.Background(), clusterName)
+		err = p.StopCluster(commandContext(), clusterName)
 		if err != nil {
 			return fmt.Errorf("failed to stop cluster: %w", err)
 		}
+		syncInventory(commandContext(), p, clusterName)
 
 		result := map[string]any{
 			"name":    clusterName,
@@ -307,13 +541,11 @@ var clusterStopCmd = &cobra.Command{
 			"message": fmt.Sprintf("Cluster '%s' stopped successfully", clusterName),
 		}
 
-		if services.GetOutput() == "json" {
-			jsonOutput, err := json.MarshalIndent(result, "", "  ")
-			if err != nil {
-				return fmt.Errorf("failed to marshal result: %w", err)
-			}
-			fmt.Println(string(jsonOutput))
-		} else {
+		ok, err := writeStructured(os.Stdout, result)
+		if err != nil {
+			return err
+		}
+		if !ok {
 			fmt.Printf("Cluster '%s' stopped successfully\n", clusterName)
 		}
 
@@ -337,17 +569,37 @@ var clusterScaleCmd = &cobra.Command{
 		nodeCount, _ := cmd.Flags().GetInt("nodes")
 
 		services.Log(fmt.Sprintf("Scaling cluster: %s to %d nodes", clusterName, nodeCount))
+		warnOutsideMaintenanceWindow(clusterName, "scaling")
+		if err := requireApproval(clusterName, "scale", fmt.Sprintf("to %d nodes", nodeCount)); err != nil {
+			return err
+		}
 
-		var p providers.Provider
-		var err error
-		p, err = services.GetProvider("local", "local", "")
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+
+		p, err := services.GetProvider(providerName, region, awsProfile)
 		if err != nil {
 			return fmt.Errorf("failed to get provider: %w", err)
 		}
-		err = p.ScaleCluster(context.Background(), clusterName, nodeCount)
+
+		ctx := commandContext()
+		if err := requireCredentials(ctx, p); err != nil {
+			return err
+		}
+		if quotaChecker, ok := p.(providers.QuotaChecker); ok {
+			ignoreQuota, _ := cmd.Flags().GetBool("ignore-quota-check")
+			checks, err := quotaChecker.CheckScaleQuotas(ctx, clusterName, nodeCount)
+			if err := enforceQuotas(checks, err, ignoreQuota); err != nil {
+				return err
+			}
+		}
+
+		err = p.ScaleCluster(ctx, clusterName, nodeCount)
 		if err != nil {
 			return fmt.Errorf("failed to scale cluster: %w", err)
 		}
+		syncInventory(ctx, p, clusterName)
 
 		result := map[string]any{
 			"name":      clusterName,
@@ -356,13 +608,11 @@ var clusterScaleCmd = &cobra.Command{
 			"message":   fmt.Sprintf("Cluster '%s' scaled to %d nodes successfully", clusterName, nodeCount),
 		}
 
-		if services.GetOutput() == "json" {
-			jsonOutput, err := json.MarshalIndent(result, "", "  ")
-			if err != nil {
-				return fmt.Errorf("failed to marshal result: %w", err)
-			}
-			fmt.Println(string(jsonOutput))
-		} else {
+		ok, err := writeStructured(os.Stdout, result)
+		if err != nil {
+			return err
+		}
+		if !ok {
 			fmt.Printf("Cluster '%s' scaled to %d nodes successfully\n", clusterName, nodeCount)
 		}
 
@@ -389,24 +639,28 @@ var clusterStatusCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to get provider: %w", err)
 		}
-		actualCluster, err := p.GetCluster(context.Background(), clusterName)
+		actualCluster, err := p.GetCluster(commandContext(), clusterName)
 		if err != nil {
 			return fmt.Errorf("failed to get cluster status: %w", err)
 		}
+		warnVersionSupport(actualCluster)
 
-		if services.GetOutput() == "json" {
-			jsonOutput, err := json.MarshalIndent(actualCluster, "", "  ")
-			if err != nil {
-				return fmt.Errorf("failed to marshal cluster: %w", err)
+		if ok, err := writeStructured(os.Stdout, actualCluster); ok {
+			return err
+		}
+		fmt.Printf("Cluster: %s\n", clusterName)
+		fmt.Printf("Provider: %s\n", actualCluster.Provider)
+		fmt.Printf("Status: %s\n", actualCluster.Status)
+		fmt.Printf("Nodes: %d\n", actualCluster.NodeCount)
+		fmt.Printf("Version: %s%s\n", actualCluster.Version, versionSupportNote(actualCluster.VersionSupport))
+		fmt.Printf("Endpoint: %s\n", actualCluster.Endpoint)
+		if currentOutputFormat().Kind == outputWide {
+			fmt.Printf("Region: %s\n", valueOrDash(actualCluster.Region))
+			fmt.Printf("Created: %s\n", actualCluster.CreatedAt.Format(time.RFC3339))
+			fmt.Printf("Updated: %s\n", actualCluster.UpdatedAt.Format(time.RFC3339))
+			if len(actualCluster.Tags) > 0 {
+				fmt.Printf("Tags: %s\n", strings.ReplaceAll(formatTagList(actualCluster.Tags), "\n", ","))
 			}
-			fmt.Println(string(jsonOutput))
-		} else {
-			fmt.Printf("Cluster: %s\n", clusterName)
-			fmt.Printf("Provider: %s\n", actualCluster.Provider)
-			fmt.Printf("Status: %s\n", actualCluster.Status)
-			fmt.Printf("Nodes: %d\n", actualCluster.NodeCount)
-			fmt.Printf("Version: %s\n", actualCluster.Version)
-			fmt.Printf("Endpoint: %s\n", actualCluster.Endpoint)
 		}
 
 		return nil
@@ -427,22 +681,42 @@ var clusterHistoryCmd = &cobra.Command{
 
 		clusterName := args[0]
 		limit, _ := cmd.Flags().GetInt("limit")
+		follow, _ := cmd.Flags().GetBool("follow")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		absolute, _ := cmd.Flags().GetBool("absolute")
+		noPager, _ := cmd.Flags().GetBool("no-pager")
 		
-		provider := services.GetLocalProvider()
-		logSource := provider.GetLogSource()
+		provider, err := services.GetProvider("local", "local", "")
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+		logSource := historySource(provider)
 		
-		operationHistory, err := logSource.GetClusterHistory(context.Background(), clusterName, limit)
+		ctx := commandContext()
+		operationHistory, err := logSource.GetClusterHistory(ctx, clusterName, limit)
 		if err != nil {
 			return fmt.Errorf("failed to get cluster history: %w", err)
 		}
+		annotateHistory(operationHistory)
 
-		if services.GetOutput() == "json" {
-			jsonOutput, err := json.MarshalIndent(operationHistory, "", "  ")
-			if err != nil {
-				return fmt.Errorf("failed to marshal operation history: %w", err)
+		now := time.Now()
+		if absolute {
+			now = time.Time{}
+		}
+
+		if follow {
+			format := currentOutputFormat()
+			if format.structured() && format.Kind != outputJSON {
+				return fmt.Errorf("unsupported output format %s with --follow; use text or json", format.Kind)
 			}
-			fmt.Println(string(jsonOutput))
-			return nil
+			return followOperationHistory(ctx, logSource, clusterName, limit, interval, operationHistory, format.Kind == outputJSON, absolute)
+		}
+
+		if operationHistory == nil {
+			operationHistory = []*logsource.OperationHistory{}
+		}
+		if ok, err := writeStructured(os.Stdout, operationHistory); ok {
+			return err
 		}
 
 		if len(operationHistory) == 0 {
@@ -450,33 +724,13 @@ var clusterHistoryCmd = &cobra.Command{
 			return nil
 		}
 
-		fmt.Printf("Operation History for '%s' (%d operations):\n\n", clusterName, len(operationHistory))
-		fmt.Printf("%-20s %-8s %-10s %-12s %-12s\n", "STARTED", "TYPE", "STATUS", "USER", "DURATION")
-		fmt.Printf("%-20s %-8s %-10s %-12s %-12s\n", "----", "----", "----", "----", "----")
-
-		for _, op := range operationHistory {
-			started := op.StartedAt.Format("Jan 02 15:04:05")
-			statusColor := getStatusColor(op.OperationStatus)
-			
-			duration := "-"
-			if op.DurationMS != nil {
-				if *op.DurationMS < 1000 {
-					duration = fmt.Sprintf("%.0fms", *op.DurationMS)
-				} else {
-					duration = fmt.Sprintf("%.1fs", *op.DurationMS/1000)
-				}
-			}
-			
-			fmt.Printf("%-20s %-8s %s%-10s%s %-12s %-12s\n",
-				started,
-				string(op.OperationType),
-				statusColor,
-				string(op.OperationStatus),
-				"\033[0m", 
-				truncateString(op.UserID, 12),
-				duration)
+		var out io.Writer = os.Stdout
+		closePager := func() {}
+		if !noPager {
+			out, closePager = pagedOutput()
 		}
-
+		defer closePager()
+		printOperationHistory(out, clusterName, operationHistory, now)
 		return nil
 	},
 }
@@ -498,39 +752,615 @@ var clusterWatchCmd = &cobra.Command{
 		
 		includeMetrics, _ := cmd.Flags().GetBool("metrics")
 		interval, _ := cmd.Flags().GetInt("interval")
+		ctx, err := certificateWarningContext(commandContext(), cmd)
+		if err != nil {
+			return err
+		}
 		
-		return watchCluster(monitor, clusterName, includeMetrics, interval)
+		return watchCluster(ctx, monitor, clusterName, includeMetrics, interval)
 	},
 }
 
-func loadClusterConfig(configFile string) (*providers.ClusterConfig, error) {
+// loadClusterConfig parses configFile strictly so misspelled fields aren't silently dropped.
+// With allowUnknown set, unknown fields are skipped instead and returned for reporting.
+func loadClusterConfig(configFile string, allowUnknown bool) (*providers.ClusterConfig, []string, error) {
 	data, err := os.ReadFile(configFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
 	var config providers.ClusterConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err = decoder.Decode(&config)
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+
+	unknown, err := splitUnknownFields(err)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse YAML config: %w", err)
+	}
+	if len(unknown) > 0 && !allowUnknown {
+		return nil, nil, fmt.Errorf("unknown fields in %s: %s (use --allow-unknown-fields to ignore them)",
+			configFile, strings.Join(unknown, ", "))
+	}
+
+	return &config, unknown, nil
+}
+
+// unknownFieldPattern matches the errors yaml.v3 reports for fields missing from the target type
+var unknownFieldPattern = regexp.MustCompile(`^line (\d+): field (\S+) not found in type`)
+
+// splitUnknownFields separates unknown-field errors, returned as "name (line N)", from any other
+// decoding error. The decoder fills in everything else before reporting unknown fields.
+func splitUnknownFields(err error) ([]string, error) {
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return nil, err
+	}
+
+	var unknown, remaining []string
+	for _, message := range typeErr.Errors {
+		if match := unknownFieldPattern.FindStringSubmatch(message); match != nil {
+			unknown = append(unknown, fmt.Sprintf("%s (line %s)", match[2], match[1]))
+		} else {
+			remaining = append(remaining, message)
+		}
+	}
+	if len(remaining) > 0 {
+		return unknown, &yaml.TypeError{Errors: remaining}
+	}
+	return unknown, nil
+}
+
+// configFromFlags builds a cluster config from the create flags alone, using flag defaults for
+// anything not set on the command line
+func configFromFlags(cmd *cobra.Command, clusterName string) (*providers.ClusterConfig, error) {
+	region, _ := cmd.Flags().GetString("region")
+	nodeCount, _ := cmd.Flags().GetInt("nodes")
+	version, _ := cmd.Flags().GetString("version")
+	instanceType, _ := cmd.Flags().GetString("instance-type")
+	diskSize, _ := cmd.Flags().GetString("disk-size")
+	mount, _ := cmd.Flags().GetString("mount")
+
+	config := &providers.ClusterConfig{
+		Name:         clusterName,
+		Region:       region,
+		NodeCount:    nodeCount,
+		Version:      version,
+		InstanceType: instanceType,
+		DiskSize:     diskSize,
 	}
 
-	return &config, nil
+	if mount != "" {
+		mountConfig, err := parseMountFlag(mount)
+		if err != nil {
+			return nil, err
+		}
+		config.Mounts = []providers.MountConfig{*mountConfig}
+	}
+
+	if awsProfile, _ := cmd.Flags().GetString("aws-profile"); awsProfile != "" {
+		config.AWS = &providers.AWSConfig{Profile: awsProfile}
+	}
+
+	enableIngress, _ := cmd.Flags().GetBool("enable-ingress")
+	enableLoadBalancer, _ := cmd.Flags().GetBool("enable-load-balancer")
+	enableRBAC, _ := cmd.Flags().GetBool("enable-rbac")
+	enableNetworkPolicy, _ := cmd.Flags().GetBool("enable-network-policy")
+	enableMonitoring, _ := cmd.Flags().GetBool("enable-monitoring")
+	apiServerPortFlag, _ := cmd.Flags().GetString("api-server-port")
+	apiServerPort, err := parseAPIServerPort(apiServerPortFlag, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	cpuLimit, _ := cmd.Flags().GetString("cpu-limit")
+	memoryLimit, _ := cmd.Flags().GetString("memory-limit")
+
+	if enableIngress || enableLoadBalancer || apiServerPort > 0 {
+		config.NetworkConfig = &providers.NetworkConfig{}
+		if enableIngress {
+			config.NetworkConfig.Ingress = &providers.IngressConfig{Enabled: true}
+		}
+		if enableLoadBalancer {
+			config.NetworkConfig.LoadBalancer = &providers.LoadBalancerConfig{Enabled: true}
+		}
+		if apiServerPort > 0 {
+			config.NetworkConfig.APIServerPort = apiServerPort
+		}
+	}
+
+	if enableRBAC || enableNetworkPolicy {
+		config.SecurityConfig = &providers.SecurityConfig{}
+		if enableRBAC {
+			config.SecurityConfig.RBAC = &providers.RBACConfig{Enabled: true}
+		}
+		if enableNetworkPolicy {
+			config.SecurityConfig.NetworkPolicy = &providers.NetworkPolicyConfig{Enabled: true}
+		}
+	}
+
+	if enableMonitoring || cpuLimit != "" || memoryLimit != "" {
+		config.ResourceConfig = &providers.ResourceConfig{}
+		if enableMonitoring {
+			config.ResourceConfig.Monitoring = &providers.MonitoringConfig{
+				Enabled:    true,
+				Prometheus: &providers.PrometheusConfig{Enabled: true},
+			}
+		}
+		if cpuLimit != "" || memoryLimit != "" {
+			config.ResourceConfig.Limits = &providers.ResourceLimits{
+				CPU:    cpuLimit,
+				Memory: memoryLimit,
+			}
+		}
+	}
+
+	return config, nil
 }
 
-func watchCluster(monitor monitoring.Monitor, clusterName string, includeMetrics bool, intervalSecs int) error {
+// applyFlagOverrides copies the settings of every flag the user set explicitly from flagConfig
+// onto base, a preset or config file, so base only fills in what wasn't specified
+func applyFlagOverrides(cmd *cobra.Command, base, flagConfig *providers.ClusterConfig) *providers.ClusterConfig {
+	flags := cmd.Flags()
+	base.Name = flagConfig.Name
+
+	if flags.Changed("region") {
+		base.Region = flagConfig.Region
+	}
+	if flags.Changed("nodes") {
+		base.NodeCount = flagConfig.NodeCount
+	}
+	if flags.Changed("version") {
+		base.Version = flagConfig.Version
+	}
+	if flags.Changed("instance-type") {
+		base.InstanceType = flagConfig.InstanceType
+	}
+	if flags.Changed("disk-size") {
+		base.DiskSize = flagConfig.DiskSize
+	}
+	if flags.Changed("mount") {
+		base.Mounts = flagConfig.Mounts
+	}
+	if flags.Changed("aws-profile") && flagConfig.AWS != nil {
+		if base.AWS == nil {
+			base.AWS = &providers.AWSConfig{}
+		}
+		base.AWS.Profile = flagConfig.AWS.Profile
+	}
+
+	if flags.Changed("enable-ingress") || flags.Changed("enable-load-balancer") || flags.Changed("api-server-port") {
+		if base.NetworkConfig == nil {
+			base.NetworkConfig = &providers.NetworkConfig{}
+		}
+		flagNetwork := flagConfig.NetworkConfig
+		if flagNetwork == nil {
+			flagNetwork = &providers.NetworkConfig{}
+		}
+		if flags.Changed("enable-ingress") {
+			base.NetworkConfig.Ingress = flagNetwork.Ingress
+		}
+		if flags.Changed("enable-load-balancer") {
+			base.NetworkConfig.LoadBalancer = flagNetwork.LoadBalancer
+		}
+		if flags.Changed("api-server-port") {
+			base.NetworkConfig.APIServerPort = flagNetwork.APIServerPort
+		}
+	}
+
+	if flags.Changed("enable-rbac") || flags.Changed("enable-network-policy") {
+		if base.SecurityConfig == nil {
+			base.SecurityConfig = &providers.SecurityConfig{}
+		}
+		flagSecurity := flagConfig.SecurityConfig
+		if flagSecurity == nil {
+			flagSecurity = &providers.SecurityConfig{}
+		}
+		if flags.Changed("enable-rbac") {
+			base.SecurityConfig.RBAC = flagSecurity.RBAC
+		}
+		if flags.Changed("enable-network-policy") {
+			base.SecurityConfig.NetworkPolicy = flagSecurity.NetworkPolicy
+		}
+	}
+
+	if flags.Changed("enable-monitoring") || flags.Changed("cpu-limit") || flags.Changed("memory-limit") {
+		if base.ResourceConfig == nil {
+			base.ResourceConfig = &providers.ResourceConfig{}
+		}
+		flagResources := flagConfig.ResourceConfig
+		if flagResources == nil {
+			flagResources = &providers.ResourceConfig{}
+		}
+		if flags.Changed("enable-monitoring") {
+			base.ResourceConfig.Monitoring = flagResources.Monitoring
+		}
+		if flags.Changed("cpu-limit") || flags.Changed("memory-limit") {
+			if base.ResourceConfig.Limits == nil {
+				base.ResourceConfig.Limits = &providers.ResourceLimits{}
+			}
+			flagLimits := flagResources.Limits
+			if flagLimits == nil {
+				flagLimits = &providers.ResourceLimits{}
+			}
+			if flags.Changed("cpu-limit") {
+				base.ResourceConfig.Limits.CPU = flagLimits.CPU
+			}
+			if flags.Changed("memory-limit") {
+				base.ResourceConfig.Limits.Memory = flagLimits.Memory
+			}
+		}
+	}
+
+	return base
+}
+
+// parseAPIServerPort parses --api-server-port. "auto" picks the port the port registry would
+// allocate to clusterName.
+func parseAPIServerPort(value, clusterName string) (int, error) {
+	switch value {
+	case "":
+		return 0, nil
+	case "auto":
+		store, err := loadDefault(ports.DefaultStorePath, ports.LoadStore)
+		if err != nil {
+			return 0, err
+		}
+		return store.Allocate(clusterName, ports.PurposeAPIServer)
+	}
+	port, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid API server port %q: must be a number or \"auto\"", value)
+	}
+	return port, nil
+}
+
+func parseMountFlag(value string) (*providers.MountConfig, error) {
+	idx := strings.LastIndex(value, ":")
+	if idx <= 0 || idx == len(value)-1 {
+		return nil, fmt.Errorf("invalid --mount value %q, expected <host-path>:<node-path>", value)
+	}
+	return &providers.MountConfig{
+		HostPath: value[:idx],
+		NodePath: value[idx+1:],
+	}, nil
+}
+
+// listAllClusters queries every registered provider concurrently and merges the results. Providers
+// that fail are returned in the error map so the remaining results can still be shown.
+func listAllClusters(ctx context.Context, factory *providers.ProviderFactory, region, awsProfile string) ([]*providers.Cluster, map[string]error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		clusters []*providers.Cluster
+		failures = make(map[string]error)
+	)
+
+	for _, name := range factory.GetSupportedProviders() {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+
+			var result []*providers.Cluster
+			p, err := factory.CreateProvider(name, region, awsProfile)
+			if err == nil {
+				result, err = p.ListClusters(ctx)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures[name] = err
+				return
+			}
+			for _, cluster := range result {
+				if cluster.Provider == "" {
+					cluster.Provider = name
+				}
+				clusters = append(clusters, cluster)
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	return clusters, failures
+}
+
+// listHealthTimeout bounds each health check run by cluster list --with-health
+const listHealthTimeout = 15 * time.Second
+
+// annotateHealth fills in Health for each cluster, running checks concurrently and reusing
+// cached results. Clusters that aren't running are reported as unknown without a check.
+func annotateHealth(ctx context.Context, clusters []*providers.Cluster, cache *monitoring.HealthCache, getProvider func(name string) (providers.Provider, error)) {
+	var wg sync.WaitGroup
+	for _, cluster := range clusters {
+		key := cluster.Provider + "/" + cluster.Region + "/" + cluster.Name
+		if status, ok := cache.Get(key); ok {
+			cluster.Health = status
+			continue
+		}
+		if cluster.Status != providers.ClusterStatusRunning {
+			cluster.Health = monitoring.HealthStatusUnknown
+			continue
+		}
+
+		wg.Add(1)
+		go func(cluster *providers.Cluster, key string) {
+			defer wg.Done()
+
+			cluster.Health = monitoring.HealthStatusUnknown
+			p, err := getProvider(cluster.Provider)
+			if err != nil {
+				return
+			}
+
+			checkCtx, cancel := context.WithTimeout(ctx, listHealthTimeout)
+			defer cancel()
+			health, err := p.HealthCheck(checkCtx, cluster.Name)
+			if err != nil {
+				return
+			}
+			cluster.Health = health.OverallStatus
+			cache.Put(key, health.OverallStatus)
+		}(cluster, key)
+	}
+	wg.Wait()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// clusterFilter selects clusters in cluster list; empty fields match everything
+type clusterFilter struct {
+	Status string
+	Region string
+	Tags   map[string]string
+	// Fields are the --filter conditions, all of which must hold
+	Fields []fieldFilter
+}
+
+// fieldFilter is a --filter condition such as status=running, name!=ci-* or tag.team=web
+type fieldFilter struct {
+	Key     string
+	Pattern string
+	Negate  bool
+}
+
+// filterKeys are the cluster fields --filter accepts, besides tag.<key>
+var filterKeys = []string{"name", "provider", "region", "status", "version", "nodes"}
+
+func (f clusterFilter) matches(cluster *providers.Cluster) bool {
+	if f.Status != "" && !strings.EqualFold(string(cluster.Status), f.Status) {
+		return false
+	}
+	if f.Region != "" && cluster.Region != f.Region {
+		return false
+	}
+	for key, value := range f.Tags {
+		if cluster.Tags[key] != value {
+			return false
+		}
+	}
+	for _, field := range f.Fields {
+		if field.matches(cluster) == field.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether the cluster's field matches the pattern, ignoring case. Patterns may
+// use shell wildcards, and a version without wildcards matches however the provider spells it.
+func (f fieldFilter) matches(cluster *providers.Cluster) bool {
+	var value string
+	switch f.Key {
+	case "name":
+		value = cluster.Name
+	case "provider":
+		value = cluster.Provider
+	case "region":
+		value = cluster.Region
+	case "status":
+		value = string(cluster.Status)
+	case "version":
+		if !strings.ContainsAny(f.Pattern, "*?[") {
+			return providers.SameKubeVersion(cluster.Version, f.Pattern)
+		}
+		value = cluster.Version
+	case "nodes":
+		value = strconv.Itoa(cluster.NodeCount)
+	default:
+		value = cluster.Tags[strings.TrimPrefix(f.Key, "tag.")]
+	}
+	matched, _ := path.Match(strings.ToLower(f.Pattern), strings.ToLower(value))
+	return matched
+}
+
+// parseFieldFilters parses --filter values of the form key=pattern or key!=pattern
+func parseFieldFilters(values []string) ([]fieldFilter, error) {
+	var filters []fieldFilter
+	for _, value := range values {
+		key, pattern, found := strings.Cut(value, "=")
+		if !found || key == "" || key == "!" {
+			return nil, fmt.Errorf("invalid --filter value %q, expected key=value or key!=value", value)
+		}
+		filter := fieldFilter{Key: key, Pattern: pattern}
+		if strings.HasSuffix(key, "!") {
+			filter.Key, filter.Negate = strings.TrimSuffix(key, "!"), true
+		}
+		if !slices.Contains(filterKeys, filter.Key) && (!strings.HasPrefix(filter.Key, "tag.") || filter.Key == "tag.") {
+			return nil, fmt.Errorf("invalid --filter key %q, expected one of %s or tag.<key>", filter.Key, strings.Join(filterKeys, ", "))
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid --filter pattern %q: %w", pattern, err)
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+func filterClusters(clusters []*providers.Cluster, filter clusterFilter) []*providers.Cluster {
+	var filtered []*providers.Cluster
+	for _, cluster := range clusters {
+		if filter.matches(cluster) {
+			filtered = append(filtered, cluster)
+		}
+	}
+	return filtered
+}
+
+// sortClusters orders clusters by name, age (oldest first), node count (largest first), status,
+// provider, region or version (oldest first). Ties keep their name order.
+func sortClusters(clusters []*providers.Cluster, sortBy string) error {
+	var less func(a, b *providers.Cluster) bool
+	switch sortBy {
+	case "", "name":
+		less = func(a, b *providers.Cluster) bool { return a.Name < b.Name }
+	case "age":
+		less = func(a, b *providers.Cluster) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	case "nodes":
+		less = func(a, b *providers.Cluster) bool { return a.NodeCount > b.NodeCount }
+	case "status":
+		less = func(a, b *providers.Cluster) bool { return a.Status < b.Status }
+	case "provider":
+		less = func(a, b *providers.Cluster) bool { return a.Provider < b.Provider }
+	case "region":
+		less = func(a, b *providers.Cluster) bool { return a.Region < b.Region }
+	case "version":
+		less = func(a, b *providers.Cluster) bool { return providers.CompareKubeVersions(a.Version, b.Version) < 0 }
+	default:
+		return fmt.Errorf("invalid --sort-by value %q, expected name, age, nodes, status, provider, region or version", sortBy)
+	}
+	if sortBy != "" && sortBy != "name" {
+		sort.SliceStable(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return less(clusters[i], clusters[j])
+	})
+	return nil
+}
+
+func parseTagFilters(values []string) (map[string]string, error) {
+	tags := make(map[string]string)
+	for _, value := range values {
+		key, tagValue, found := strings.Cut(value, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid --tag value %q, expected key=value", value)
+		}
+		tags[key] = tagValue
+	}
+	return tags, nil
+}
+
+// checkClusterConfig reports every validation issue in config at once. Errors fail the command;
+// warnings are printed and creation goes ahead. With report set the result is printed even when
+// the config is clean.
+func checkClusterConfig(p providers.Provider, config *providers.ClusterConfig, report bool) error {
+	result := providers.Validate(p, config)
+	errs := result.Errors()
+
+	if currentOutputFormat().structured() {
+		if report || len(errs) > 0 {
+			if _, err := writeStructured(os.Stdout, map[string]any{
+				"valid":  len(errs) == 0,
+				"issues": result.Issues,
+			}); err != nil {
+				return err
+			}
+		}
+	} else if report || len(errs) > 0 {
+		out := os.Stdout
+		if !report {
+			out = os.Stderr
+		}
+		printValidationResult(out, result)
+	} else {
+		for _, warning := range result.Warnings() {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("configuration validation failed with %d %s", len(errs), pluralIssues(len(errs), "error"))
+	}
+	return nil
+}
+
+func printValidationResult(w io.Writer, result *providers.ValidationResult) {
+	errs, warnings := result.Errors(), result.Warnings()
+	if len(result.Issues) == 0 {
+		fmt.Fprintln(w, "✅ Configuration is valid")
+		return
+	}
+	fmt.Fprintf(w, "Configuration has %d %s and %d %s:\n",
+		len(errs), pluralIssues(len(errs), "error"), len(warnings), pluralIssues(len(warnings), "warning"))
+	for _, issue := range errs {
+		fmt.Fprintf(w, "  ❌ %s\n", issue)
+	}
+	for _, issue := range warnings {
+		fmt.Fprintf(w, "  ⚠️  %s\n", issue)
+	}
+}
+
+func pluralIssues(n int, noun string) string {
+	if n == 1 {
+		return noun
+	}
+	return noun + "s"
+}
+
+// enforceQuotas prints quota warnings and fails when a quota would be exceeded, unless ignore is set.
+// A failed quota lookup only warns so missing Service Quotas permissions don't block provisioning.
+func enforceQuotas(checks []providers.QuotaCheck, checkErr error, ignore bool) error {
+	if checkErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not check account quotas: %v\n", checkErr)
+		return nil
+	}
+
+	warnings, err := providers.EvaluateQuotas(checks)
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	if err != nil {
+		if ignore {
+			fmt.Fprintf(os.Stderr, "Warning: %v (ignored)\n", err)
+			return nil
+		}
+		return fmt.Errorf("quota check failed: %w (use --ignore-quota-check to proceed anyway)", err)
+	}
+	return nil
+}
+
+func watchCluster(ctx context.Context, monitor monitoring.Monitor, clusterName string, includeMetrics bool, intervalSecs int) error {
 	fmt.Printf("Watching cluster '%s' (Press Ctrl+C to exit)\n\n", clusterName)
 	
 	interval := time.Duration(intervalSecs) * time.Second
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	ctx := context.Background()
-
 	for {
 		healthStatus, err := monitor.CheckClusterHealth(ctx, clusterName)
 		if err != nil {
+			if ctx.Err() != nil {
+				// SIGINT/SIGTERM abandoned the check
+				fmt.Printf("\nStopped watching cluster '%s'\n", clusterName)
+				return nil
+			}
 			fmt.Printf("Health check failed: %v\n", err)
-			time.Sleep(interval)
+			select {
+			case <-ctx.Done():
+				fmt.Printf("\nStopped watching cluster '%s'\n", clusterName)
+				return nil
+			case <-time.After(interval):
+			}
 			continue
 		}
 
@@ -554,8 +1384,10 @@ func watchCluster(monitor monitoring.Monitor, clusterName string, includeMetrics
 		fmt.Println("\n" + strings.Repeat("=", 50))
 		
 		select {
+		case <-ctx.Done():
+			fmt.Printf("\nStopped watching cluster '%s'\n", clusterName)
+			return nil
 		case <-ticker.C:
-			continue
 		}
 	}
 }
@@ -601,6 +1433,14 @@ func printClusterHealthStatus(health *monitoring.HealthStatus) {
 		fmt.Printf("Total: %d | Healthy: %d\n", health.Services.TotalServices, health.Services.HealthyServices)
 	}
 	
+	if health.Workloads != nil {
+		printWorkloadHealth(os.Stdout, health.Workloads)
+	}
+	
+	if len(health.Certificates) > 0 {
+		printCertificateHealth(os.Stdout, health.Certificates)
+	}
+	
 	if len(health.Warnings) > 0 {
 		fmt.Println("\n--- Warnings ---")
 		for _, warning := range health.Warnings {
@@ -688,6 +1528,7 @@ var clusterGenerateConfigCmd = &cobra.Command{
 			Version:      "v1.31.0",
 			NodeCount:    2,
 			InstanceType: "standard",
+			DiskSize:     "20g",
 			NetworkConfig: &providers.NetworkConfig{
 				PodCIDR:       "10.244.0.0/16",
 				ServiceCIDR:   "10.96.0.0/12",
@@ -766,11 +1607,21 @@ var clusterGenerateConfigCmd = &cobra.Command{
 					},
 				},
 			},
+			BootstrapManifests: []providers.BootstrapManifest{
+				{
+					Name:   "dev-namespace",
+					Inline: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: dev\n",
+				},
+			},
 			Tags: map[string]string{
 				"environment": "development",
 				"team":        "platform",
 				"purpose":     "testing",
 			},
+			TaggingPolicy: &providers.TaggingPolicy{
+				Workspace: "platform",
+				Required:  []string{"team"},
+			},
 		}
 
 		yamlData, err := yaml.Marshal(sampleConfig)
@@ -791,6 +1642,185 @@ var clusterGenerateConfigCmd = &cobra.Command{
 	},
 }
 
+// printClusterTable renders cluster list's table; wide adds each cluster's version, creation
+// time and endpoint
+func printClusterTable(w io.Writer, clusters []*providers.Cluster, withHealth, wide bool) {
+	headers := []string{"NAME", "PROVIDER", "REGION", "NODES", "STATUS"}
+	if withHealth {
+		headers = append(headers, "HEALTH")
+	}
+	if wide {
+		headers = append(headers, "VERSION", "CREATED", "ENDPOINT")
+	}
+	t := newTable(headers...).withSeparator()
+	for _, cluster := range clusters {
+		row := []any{cluster.Name, cluster.Provider, cluster.Region, cluster.NodeCount, cluster.Status}
+		if withHealth {
+			row = append(row, cluster.Health)
+		}
+		if wide {
+			created := "-"
+			if !cluster.CreatedAt.IsZero() {
+				created = cluster.CreatedAt.UTC().Format("2006-01-02 15:04")
+			}
+			row = append(row, valueOrDash(cluster.Version), created, valueOrDash(cluster.Endpoint))
+		}
+		t.addRow(row...)
+	}
+	t.render(w)
+}
+
+// printOperationHistory renders the text table shown by cluster history. Start times are shown
+// relative to now, or as timestamps when now is the zero time.
+func printOperationHistory(w io.Writer, clusterName string, operationHistory []*logsource.OperationHistory, now time.Time) {
+	fmt.Fprintf(w, "Operation History for '%s' (%d operations):\n\n", clusterName, len(operationHistory))
+	printOperationHistoryHeader(w)
+	for _, op := range operationHistory {
+		printOperationHistoryRow(w, op, now)
+	}
+}
+
+func printOperationHistoryHeader(w io.Writer) {
+	fmt.Fprintf(w, "%-11s %-20s %-8s %-10s %-12s %-12s\n", "ID", "STARTED", "TYPE", "STATUS", "USER", "DURATION")
+	fmt.Fprintf(w, "%-11s %-20s %-8s %-10s %-12s %-12s\n", "--", "----", "----", "----", "----", "----")
+}
+
+// printOperationHistoryRow prints one operation, followed by its details in wide output, what a
+// deploy changed, its error when it failed and any notes attached with operation annotate
+func printOperationHistoryRow(w io.Writer, op *logsource.OperationHistory, now time.Time) {
+	started := op.StartedAt.Format("Jan 02 15:04:05")
+	if !now.IsZero() {
+		started = formatRelativeTime(op.StartedAt, now)
+	}
+	statusColor := getStatusColor(op.OperationStatus)
+	
+	duration := "-"
+	if op.DurationMS != nil {
+		if *op.DurationMS < 1000 {
+			duration = fmt.Sprintf("%.0fms", *op.DurationMS)
+		} else {
+			duration = fmt.Sprintf("%.1fs", *op.DurationMS/1000)
+		}
+	}
+	
+	wide := currentOutputFormat().Kind == outputWide
+	user := op.UserID
+	if !noTruncate && !wide {
+		user = truncateString(user, 12)
+	}
+	fmt.Fprintf(w, "%-11s %-20s %-8s %s%-10s%s %-12s %-12s\n",
+		op.Ref,
+		started,
+		string(op.OperationType),
+		statusColor,
+		string(op.OperationStatus),
+		"\033[0m", 
+		user,
+		duration)
+
+	if wide && len(op.OperationDetails) > 0 {
+		fmt.Fprintf(w, "  └─ %s\n", formatOperationDetails(op.OperationDetails))
+	}
+	if summary := op.Metadata["summary"]; op.OperationType == logsource.OpTypeDeploy && summary != "" {
+		fmt.Fprintf(w, "  └─ %s\n", summary)
+	}
+	if op.OperationStatus == logsource.OpStatusFailed && op.ErrorMessage != "" {
+		fmt.Fprintf(w, "  %s└─ %s\033[0m\n", statusColor, op.ErrorMessage)
+	}
+	if notes := op.Metadata[annotations.MetadataKey]; notes != "" {
+		for _, note := range strings.Split(notes, "\n") {
+			fmt.Fprintf(w, "  └─ note by %s\n", note)
+		}
+	}
+}
+
+// formatOperationDetails renders an operation's details as key=value pairs sorted by key
+func formatOperationDetails(details map[string]interface{}) string {
+	pairs := make([]string, 0, len(details))
+	for _, key := range sortedKeys(details) {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, details[key]))
+	}
+	return strings.Join(pairs, " ")
+}
+
+// formatRelativeTime describes t as an age such as "5m ago", falling back to the date once it is
+// more than a month old
+func formatRelativeTime(t, now time.Time) string {
+	age := now.Sub(t)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	case age < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	default:
+		return t.Format("Jan 02 2006")
+	}
+}
+
+// followOperationHistory prints the current history, then polls for new operations and status
+// changes until the command is interrupted. JSON output streams one operation per line.
+func followOperationHistory(ctx context.Context, logSource logsource.LogSource, clusterName string, limit int,
+	interval time.Duration, initial []*logsource.OperationHistory, asJSON, absolute bool) error {
+	seen := make(map[string]logsource.OperationStatus)
+	emit := func(ops []*logsource.OperationHistory) {
+		sort.SliceStable(ops, func(i, j int) bool { return ops[i].StartedAt.Before(ops[j].StartedAt) })
+		annotateHistory(ops)
+		now := time.Now()
+		if absolute {
+			now = time.Time{}
+		}
+		for _, op := range ops {
+			key := operationHistoryKey(op)
+			if status, exists := seen[key]; exists && status == op.OperationStatus {
+				continue
+			}
+			seen[key] = op.OperationStatus
+			if asJSON {
+				line, _ := json.Marshal(op)
+				fmt.Println(string(line))
+			} else {
+				printOperationHistoryRow(os.Stdout, op, now)
+			}
+		}
+	}
+
+	if !asJSON {
+		fmt.Printf("Following operation history for '%s' (Ctrl+C to stop):\n\n", clusterName)
+		printOperationHistoryHeader(os.Stdout)
+	}
+	emit(initial)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		history, err := logSource.GetClusterHistory(ctx, clusterName, limit)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "Warning: failed to refresh history: %v\n", err)
+			continue
+		}
+		emit(history)
+	}
+}
+
+// operationHistoryKey identifies an operation across polls, since a running operation is
+// reported again once it completes
+func operationHistoryKey(op *logsource.OperationHistory) string {
+	return fmt.Sprintf("%d|%s|%d", op.ID, op.OperationType, op.StartedAt.UnixNano())
+}
+
 func getStatusColor(status logsource.OperationStatus) string {
 	switch status {
 	case logsource.OpStatusCompleted:
@@ -805,13 +1835,14 @@ func getStatusColor(status logsource.OperationStatus) string {
 }
 
 func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
 		return s
 	}
 	if maxLen <= 3 {
-		return s[:maxLen]
+		return string(runes[:maxLen])
 	}
-	return s[:maxLen-3] + "..."
+	return string(runes[:maxLen-3]) + "..."
 }
 
 func init() {
@@ -827,34 +1858,79 @@ func init() {
 	clusterCmd.AddCommand(clusterHistoryCmd)
 	clusterCmd.AddCommand(clusterWatchCmd)
 
-	clusterCreateCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, aws, gcp, azure)")
+	clusterCreateCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws, gcp, azure)")
 	clusterCreateCmd.Flags().StringP("region", "r", "", "Region to create cluster in")
 	clusterCreateCmd.Flags().IntP("nodes", "n", 1, "Number of nodes in the cluster")
 	clusterCreateCmd.Flags().StringP("version", "k", "", "Kubernetes version")
 	clusterCreateCmd.Flags().String("instance-type", "", "Instance type for nodes")
+	clusterCreateCmd.Flags().String("disk-size", "", "Disk size per node (e.g., '20g', '40000mb')")
+	clusterCreateCmd.Flags().String("mount", "", "Mount a host directory into the nodes as <host-path>:<node-path>")
 	clusterCreateCmd.Flags().StringP("config", "c", "", "Path to cluster configuration YAML file")
-	clusterCreateCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	clusterCreateCmd.Flags().Bool("allow-unknown-fields", false, "Ignore fields in the --config file that Atlas doesn't recognize instead of failing")
+	clusterCreateCmd.Flags().String("preset", "", "Start from a built-in preset ("+strings.Join(providers.PresetNames(), ", ")+"); explicit flags override it")
+	clusterCreateCmd.Flags().BoolP("interactive", "i", false, "Walk through provider, size, networking and monitoring choices interactively")
+	clusterCreateCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider); may be a secret reference such as env://AWS_PROFILE")
 
 	clusterCreateCmd.Flags().Bool("enable-ingress", false, "Enable ingress controller")
 	clusterCreateCmd.Flags().Bool("enable-load-balancer", false, "Enable load balancer")
 	clusterCreateCmd.Flags().Bool("enable-rbac", false, "Enable RBAC")
 	clusterCreateCmd.Flags().Bool("enable-network-policy", false, "Enable network policies")
 	clusterCreateCmd.Flags().Bool("enable-monitoring", false, "Enable monitoring stack")
-	clusterCreateCmd.Flags().Int("api-server-port", 0, "API server port (0 for default)")
+	clusterCreateCmd.Flags().String("api-server-port", "", "API server port, or \"auto\" to pick a free one")
 	clusterCreateCmd.Flags().String("cpu-limit", "", "CPU limit per node (e.g., '4', '2.5')")
 	clusterCreateCmd.Flags().String("memory-limit", "", "Memory limit per node (e.g., '8Gi', '4096Mi')")
-
-	clusterListCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, aws, gcp, azure)")
+	clusterCreateCmd.Flags().Bool("auto-fit", false, "Clamp CPU and memory limits to the host's available resources (local provider)")
+	clusterCreateCmd.Flags().Bool("rollback-on-cancel", false, "Delete the partially created cluster if creation is canceled")
+	clusterCreateCmd.Flags().Duration("max-duration", 0, "Abort creation and delete the partially created cluster if it takes longer than this (e.g. 8m)")
+	clusterCreateCmd.Flags().StringArray("phase-slo", nil, "Warn when a creation phase takes longer than its objective, as phase=duration (e.g. provision=5m; repeatable)")
+	clusterCreateCmd.Flags().Bool("explain-config", false, "Print each effective setting and whether it came from a flag, ATLAS_* variable, config file, preset or default, then exit")
+	clusterCreateCmd.Flags().Bool("validate-only", false, "Report every configuration error and warning, then exit without creating the cluster")
+	clusterCreateCmd.Flags().Bool("ignore-quota-check", false, "Proceed even if the request exceeds account quotas (AWS provider)")
+
+	clusterListCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws, gcp, azure), or all to query every provider")
 	clusterListCmd.Flags().StringP("region", "r", "", "Region to list clusters from") 
 	clusterListCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	clusterListCmd.Flags().String("status", "", "Only list clusters with this status (pending, running, stopped, error, deleting)")
+	clusterListCmd.Flags().StringArray("tag", nil, "Only list clusters with this tag as key=value (repeatable)")
+	clusterListCmd.Flags().StringArray("filter", nil, "Only list clusters whose field matches, e.g. status=running, name!=ci-* or tag.team=web (repeatable; fields: name, provider, region, status, version, nodes, tag.<key>)")
+	clusterListCmd.Flags().String("sort-by", "name", "Sort clusters by name, age, nodes, status, provider, region or version")
+	clusterListCmd.Flags().String("sort", "name", "Sort clusters by name, age or nodes")
+	clusterListCmd.Flags().MarkDeprecated("sort", "use --sort-by instead")
+	clusterListCmd.Flags().Bool("with-health", false, "Run health checks concurrently and add a HEALTH column")
+
+	clusterDeleteCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+	clusterDeleteCmd.Flags().StringP("region", "r", "", "Region the cluster runs in")
+	clusterDeleteCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	clusterDeleteCmd.Flags().Bool("force", false, "Force removal of broken or half-created clusters with escalating cleanup")
+	clusterDeleteCmd.Flags().Bool("wait", false, "Wait until the cluster and its resources are fully removed")
+	clusterDeleteCmd.Flags().Duration("timeout", 30*time.Minute, "Maximum time to wait when --wait is set")
+	clusterDeleteCmd.Flags().BoolP("yes", "y", false, "Delete the clusters matched by --selector without asking for confirmation")
+
+	for _, cmd := range []*cobra.Command{clusterStartCmd, clusterStopCmd} {
+		cmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+		cmd.Flags().StringP("region", "r", "", "Region the cluster runs in")
+		cmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	}
+	for _, cmd := range []*cobra.Command{clusterStartCmd, clusterStopCmd, clusterDeleteCmd} {
+		cmd.Flags().StringP("selector", "l", "", "Operate on every cluster whose tags match, e.g. env=dev,team!=payments")
+	}
 
 	clusterScaleCmd.Flags().IntP("nodes", "n", 1, "Number of nodes to scale to")
+	clusterScaleCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+	clusterScaleCmd.Flags().StringP("region", "r", "", "Region the cluster runs in")
+	clusterScaleCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	clusterScaleCmd.Flags().Bool("ignore-quota-check", false, "Proceed even if the request exceeds account quotas (AWS provider)")
 	clusterScaleCmd.MarkFlagRequired("nodes")
 
 	clusterGenerateConfigCmd.Flags().StringP("output", "o", "", "Output file path (default: stdout)")
 
 	clusterHistoryCmd.Flags().IntP("limit", "l", 50, "Number of operations to display")
+	clusterHistoryCmd.Flags().BoolP("follow", "f", false, "Keep running and print new operations as they happen")
+	clusterHistoryCmd.Flags().Duration("interval", 2*time.Second, "How often to poll for new operations with --follow")
+	clusterHistoryCmd.Flags().Bool("absolute", false, "Show start timestamps instead of relative times")
+	clusterHistoryCmd.Flags().Bool("no-pager", false, "Print directly instead of paging long histories")
 	
 	clusterWatchCmd.Flags().BoolP("metrics", "m", false, "Include detailed resource metrics")
 	clusterWatchCmd.Flags().IntP("interval", "i", 5, "Update interval in seconds")
+	clusterWatchCmd.Flags().String("cert-warning", "30d", "Warn about cluster certificates that expire within this window, e.g. 72h, 30d, 2w")
 }
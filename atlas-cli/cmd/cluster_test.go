@@ -2,11 +2,17 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
 	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -133,7 +139,7 @@ resourceConfig:
 			}
 
 			// Load config
-			config, err := loadClusterConfig(configFile)
+			config, _, err := loadClusterConfig(configFile, false)
 
 			if tt.wantErr {
 				if err == nil {
@@ -159,7 +165,7 @@ resourceConfig:
 }
 
 func TestLoadClusterConfig_FileNotFound(t *testing.T) {
-	_, err := loadClusterConfig("/nonexistent/path/config.yaml")
+	_, _, err := loadClusterConfig("/nonexistent/path/config.yaml", false)
 	if err == nil {
 		t.Error("loadClusterConfig() should fail for non-existent file")
 	}
@@ -168,6 +174,42 @@ func TestLoadClusterConfig_FileNotFound(t *testing.T) {
 	}
 }
 
+func TestLoadClusterConfig_UnknownFields(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	configYAML := `
+name: test-cluster
+nodeCont: 3
+networkConfig:
+  ingress:
+    enabled: true
+    controler: traefik
+`
+	if err := os.WriteFile(configFile, []byte(configYAML), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	_, _, err := loadClusterConfig(configFile, false)
+	if err == nil {
+		t.Fatal("loadClusterConfig() should reject unknown fields by default")
+	}
+	for _, want := range []string{"nodeCont (line 3)", "controler (line 7)", "--allow-unknown-fields"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("loadClusterConfig() error = %v, want it to mention %q", err, want)
+		}
+	}
+
+	config, ignored, err := loadClusterConfig(configFile, true)
+	if err != nil {
+		t.Fatalf("loadClusterConfig() with unknown fields allowed error = %v", err)
+	}
+	if len(ignored) != 2 {
+		t.Errorf("ignored fields = %v, want nodeCont and controler", ignored)
+	}
+	if config.Name != "test-cluster" || config.NetworkConfig == nil || !config.NetworkConfig.Ingress.Enabled {
+		t.Errorf("known fields should still be decoded, got %+v", config)
+	}
+}
+
 func TestClusterGenerateConfigCmd(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -413,7 +455,7 @@ func buildConfigFromFlags(cmd *cobra.Command, clusterName string) *providers.Clu
 
 	if configFile != "" {
 		var err error
-		config, err = loadClusterConfig(configFile)
+		config, _, err = loadClusterConfig(configFile, false)
 		if err != nil {
 			return nil
 		}
@@ -511,7 +553,7 @@ resourceConfig:
 	}
 
 	// Test loading the config
-	config, err := loadClusterConfig(configFile)
+	config, _, err := loadClusterConfig(configFile, false)
 	if err != nil {
 		t.Fatalf("loadClusterConfig() error = %v", err)
 	}
@@ -532,4 +574,306 @@ resourceConfig:
 	if config.ResourceConfig == nil || config.ResourceConfig.Limits == nil {
 		t.Error("Resource limits should be set from config file")
 	}
-}
\ No newline at end of file
+}
+func TestParseAPIServerPort(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if port, err := parseAPIServerPort("", "dev"); err != nil || port != 0 {
+		t.Errorf("parseAPIServerPort(\"\") = %d, %v, want 0", port, err)
+	}
+	if port, err := parseAPIServerPort("9443", "dev"); err != nil || port != 9443 {
+		t.Errorf("parseAPIServerPort(\"9443\") = %d, %v, want 9443", port, err)
+	}
+	if port, err := parseAPIServerPort("auto", "dev"); err != nil || port <= 0 {
+		t.Errorf("parseAPIServerPort(\"auto\") = %d, %v, want a free port", port, err)
+	}
+	if _, err := parseAPIServerPort("high", "dev"); err == nil {
+		t.Error("parseAPIServerPort(\"high\") expected error")
+	}
+}
+
+func TestParseMountFlag(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantHost string
+		wantNode string
+		wantErr  bool
+	}{
+		{value: "/home/dev/src:/src", wantHost: "/home/dev/src", wantNode: "/src"},
+		{value: `C:\code:/code`, wantHost: `C:\code`, wantNode: "/code"},
+		{value: "/home/dev/src", wantErr: true},
+		{value: "/home/dev/src:", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			mount, err := parseMountFlag(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseMountFlag(%q) expected error but got none", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseMountFlag(%q) unexpected error = %v", tt.value, err)
+			}
+			if mount.HostPath != tt.wantHost || mount.NodePath != tt.wantNode {
+				t.Errorf("parseMountFlag(%q) = %s:%s, want %s:%s", tt.value, mount.HostPath, mount.NodePath, tt.wantHost, tt.wantNode)
+			}
+		})
+	}
+}
+
+func TestFilterAndSortClusters(t *testing.T) {
+	now := time.Now()
+	clusters := []*providers.Cluster{
+		{Name: "web", Provider: "aws", Region: "us-west-2", Version: "1.10", Status: providers.ClusterStatusRunning, NodeCount: 3, CreatedAt: now.Add(-time.Hour), Tags: map[string]string{"team": "web"}},
+		{Name: "api", Provider: "aws", Region: "us-east-1", Version: "v1.9.2", Status: providers.ClusterStatusRunning, NodeCount: 5, CreatedAt: now.Add(-2 * time.Hour), Tags: map[string]string{"team": "api"}},
+		{Name: "batch", Provider: "local", Region: "us-west-2", Version: "v1.31.0", Status: providers.ClusterStatusStopped, NodeCount: 1, CreatedAt: now, Tags: map[string]string{"team": "api"}},
+	}
+
+	names := func(clusters []*providers.Cluster) string {
+		var result []string
+		for _, cluster := range clusters {
+			result = append(result, cluster.Name)
+		}
+		return strings.Join(result, ",")
+	}
+
+	tests := []struct {
+		name    string
+		filter  clusterFilter
+		sortBy  string
+		want    string
+		wantErr bool
+	}{
+		{name: "no filter sorted by name", sortBy: "name", want: "api,batch,web"},
+		{name: "status filter", filter: clusterFilter{Status: "running"}, sortBy: "name", want: "api,web"},
+		{name: "region filter", filter: clusterFilter{Region: "us-west-2"}, sortBy: "name", want: "batch,web"},
+		{name: "tag filter", filter: clusterFilter{Tags: map[string]string{"team": "api"}}, sortBy: "name", want: "api,batch"},
+		{name: "sort by age", sortBy: "age", want: "api,web,batch"},
+		{name: "sort by nodes", sortBy: "nodes", want: "api,web,batch"},
+		{name: "field filter", filter: clusterFilter{Fields: []fieldFilter{{Key: "status", Pattern: "RUNNING"}}}, sortBy: "name", want: "api,web"},
+		{name: "negated glob filter", filter: clusterFilter{Fields: []fieldFilter{{Key: "name", Pattern: "*a*", Negate: true}}}, sortBy: "name", want: "web"},
+		{name: "version filter", filter: clusterFilter{Fields: []fieldFilter{{Key: "version", Pattern: "1.31"}}}, sortBy: "name", want: "batch"},
+		{name: "tag field filter", filter: clusterFilter{Fields: []fieldFilter{{Key: "tag.team", Pattern: "api"}, {Key: "nodes", Pattern: "5"}}}, sortBy: "name", want: "api"},
+		{name: "sort by version", sortBy: "version", want: "api,web,batch"},
+		{name: "sort by provider keeps name order", sortBy: "provider", want: "api,web,batch"},
+		{name: "sort by region", sortBy: "region", want: "api,batch,web"},
+		{name: "invalid sort", sortBy: "size", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := filterClusters(clusters, tt.filter)
+			err := sortClusters(filtered, tt.sortBy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sortClusters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := names(filtered); got != tt.want {
+				t.Errorf("clusters = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTagFilters(t *testing.T) {
+	tags, err := parseTagFilters([]string{"team=api", "env="})
+	if err != nil {
+		t.Fatalf("parseTagFilters() unexpected error = %v", err)
+	}
+	if tags["team"] != "api" || tags["env"] != "" || len(tags) != 2 {
+		t.Errorf("parseTagFilters() = %v", tags)
+	}
+
+	if _, err := parseTagFilters([]string{"team"}); err == nil {
+		t.Error("parseTagFilters() expected error for value without '='")
+	}
+}
+
+func TestParseFieldFilters(t *testing.T) {
+	filters, err := parseFieldFilters([]string{"status=running", "name!=ci-*", "tag.team="})
+	if err != nil {
+		t.Fatalf("parseFieldFilters() unexpected error = %v", err)
+	}
+	want := []fieldFilter{{Key: "status", Pattern: "running"}, {Key: "name", Pattern: "ci-*", Negate: true}, {Key: "tag.team"}}
+	if !reflect.DeepEqual(filters, want) {
+		t.Errorf("parseFieldFilters() = %+v, want %+v", filters, want)
+	}
+
+	for _, value := range []string{"status", "=running", "!=running", "owner=me", "tag.=x", "name=[ci"} {
+		if _, err := parseFieldFilters([]string{value}); err == nil {
+			t.Errorf("parseFieldFilters(%q) expected error", value)
+		}
+	}
+}
+
+type listOnlyProvider struct {
+	providers.Provider
+	clusters []*providers.Cluster
+	err      error
+}
+
+func (p *listOnlyProvider) ListClusters(ctx context.Context) ([]*providers.Cluster, error) {
+	return p.clusters, p.err
+}
+
+func TestListAllClusters(t *testing.T) {
+	factory := providers.NewProviderFactory()
+	factory.RegisterProvider("local", func(region, profile string) providers.Provider {
+		return &listOnlyProvider{clusters: []*providers.Cluster{{Name: "a", Provider: "local"}}}
+	})
+	factory.RegisterProvider("second", func(region, profile string) providers.Provider {
+		return &listOnlyProvider{clusters: []*providers.Cluster{{Name: "b"}}}
+	})
+	factory.RegisterProvider("kind", func(region, profile string) providers.Provider {
+		return &listOnlyProvider{}
+	})
+	factory.RegisterProvider("k3d", func(region, profile string) providers.Provider {
+		return &listOnlyProvider{}
+	})
+	factory.RegisterProvider("aws", func(region, profile string) providers.Provider {
+		return &listOnlyProvider{err: fmt.Errorf("no credentials")}
+	})
+
+	clusters, failures := listAllClusters(context.Background(), factory, "", "")
+
+	if len(clusters) != 2 {
+		t.Fatalf("listAllClusters() returned %d clusters, want 2", len(clusters))
+	}
+	sortClusters(clusters, "name")
+	if clusters[1].Provider != "second" {
+		t.Errorf("cluster without provider should be annotated with its source, got %q", clusters[1].Provider)
+	}
+	if len(failures) != 1 || failures["aws"] == nil {
+		t.Errorf("listAllClusters() failures = %v, want only aws", failures)
+	}
+}
+
+type healthOnlyProvider struct {
+	providers.Provider
+	checks atomic.Int32
+}
+
+func (p *healthOnlyProvider) HealthCheck(ctx context.Context, clusterName string) (*monitoring.HealthStatus, error) {
+	p.checks.Add(1)
+	if clusterName == "broken" {
+		return nil, fmt.Errorf("unreachable")
+	}
+	return &monitoring.HealthStatus{ClusterName: clusterName, OverallStatus: monitoring.HealthStatusHealthy}, nil
+}
+
+func TestAnnotateHealth(t *testing.T) {
+	cache := monitoring.NewHealthCache(filepath.Join(t.TempDir(), "health.json"), time.Minute)
+	cache.Put("local/local/cached", monitoring.HealthStatusWarning)
+
+	clusters := []*providers.Cluster{
+		{Name: "web", Provider: "local", Region: "local", Status: providers.ClusterStatusRunning},
+		{Name: "cached", Provider: "local", Region: "local", Status: providers.ClusterStatusRunning},
+		{Name: "stopped", Provider: "local", Region: "local", Status: providers.ClusterStatusStopped},
+		{Name: "broken", Provider: "local", Region: "local", Status: providers.ClusterStatusRunning},
+	}
+	provider := &healthOnlyProvider{}
+
+	annotateHealth(context.Background(), clusters, cache, func(string) (providers.Provider, error) {
+		return provider, nil
+	})
+
+	want := []monitoring.ClusterHealthStatus{
+		monitoring.HealthStatusHealthy,
+		monitoring.HealthStatusWarning,
+		monitoring.HealthStatusUnknown,
+		monitoring.HealthStatusUnknown,
+	}
+	for i, cluster := range clusters {
+		if cluster.Health != want[i] {
+			t.Errorf("%s health = %q, want %q", cluster.Name, cluster.Health, want[i])
+		}
+	}
+	if checks := provider.checks.Load(); checks != 2 {
+		t.Errorf("ran %d health checks, want 2 (cached and stopped clusters are skipped)", checks)
+	}
+	if status, ok := cache.Get("local/local/web"); !ok || status != monitoring.HealthStatusHealthy {
+		t.Error("successful health checks should be cached")
+	}
+}
+
+// failingMonitor fails every health check, cancelling the watch after its first check
+type failingMonitor struct {
+	monitoring.Monitor
+	checks atomic.Int32
+	cancel context.CancelFunc
+}
+
+func (m *failingMonitor) CheckClusterHealth(ctx context.Context, clusterName string) (*monitoring.HealthStatus, error) {
+	if m.checks.Add(1) == 1 {
+		m.cancel()
+	}
+	return nil, ctx.Err()
+}
+
+func TestWatchCluster_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	monitor := &failingMonitor{cancel: cancel}
+
+	done := make(chan error, 1)
+	go func() { done <- watchCluster(ctx, monitor, "dev", false, 60) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("watchCluster() error = %v, want nil after cancellation", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchCluster() kept running after its context was cancelled")
+	}
+	if got := monitor.checks.Load(); got != 1 {
+		t.Errorf("watchCluster() ran %d checks, want 1", got)
+	}
+}
+
+func TestApplyFlagOverrides(t *testing.T) {
+	cmd := &cobra.Command{Use: "create"}
+	cmd.Flags().IntP("nodes", "n", 1, "")
+	cmd.Flags().String("instance-type", "", "")
+	cmd.Flags().String("region", "", "")
+	cmd.Flags().Bool("enable-monitoring", false, "")
+	cmd.Flags().String("memory-limit", "", "")
+	if err := cmd.ParseFlags([]string{"--nodes", "5", "--enable-monitoring=false", "--memory-limit", "16Gi"}); err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+
+	preset, err := providers.PresetConfig("prod-small", "local")
+	if err != nil {
+		t.Fatalf("PresetConfig() error = %v", err)
+	}
+	flagConfig := &providers.ClusterConfig{
+		Name:      "prod",
+		NodeCount: 5,
+		ResourceConfig: &providers.ResourceConfig{
+			Limits: &providers.ResourceLimits{Memory: "16Gi"},
+		},
+	}
+
+	config := applyFlagOverrides(cmd, preset, flagConfig)
+
+	if config.Name != "prod" {
+		t.Errorf("Name = %q, want prod", config.Name)
+	}
+	if config.NodeCount != 5 {
+		t.Errorf("NodeCount = %d, want explicit flag value 5", config.NodeCount)
+	}
+	if config.DiskSize != "40g" {
+		t.Errorf("DiskSize = %q, want preset value 40g", config.DiskSize)
+	}
+	if config.ResourceConfig.Monitoring != nil {
+		t.Error("explicit --enable-monitoring=false should turn off the preset's monitoring")
+	}
+	if config.ResourceConfig.Limits.Memory != "16Gi" || config.ResourceConfig.Limits.CPU != "4" {
+		t.Errorf("Limits = %+v, want memory overridden and preset CPU kept", config.ResourceConfig.Limits)
+	}
+	if config.SecurityConfig == nil || !config.SecurityConfig.RBAC.Enabled {
+		t.Error("preset security settings should be kept when no security flags are set")
+	}
+}
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+// clusterSnapshot flattens what compare looks at into field -> value, e.g. version, tags.team,
+// addons.ingress or resources.pods. Sections that could not be collected are explained in
+// Warnings.
+type clusterSnapshot struct {
+	Name     string
+	Fields   map[string]string
+	Warnings []string
+}
+
+// fieldDifference is one field compared between two clusters; a missing value is empty
+type fieldDifference struct {
+	Field string `json:"field"`
+	A     string `json:"a"`
+	B     string `json:"b"`
+	Same  bool   `json:"same"`
+}
+
+type clusterComparison struct {
+	A           string            `json:"a"`
+	B           string            `json:"b"`
+	Differences []fieldDifference `json:"differences"`
+	Identical   int               `json:"identical"`
+	Warnings    []string          `json:"warnings,omitempty"`
+}
+
+var clusterCompareCmd = &cobra.Command{
+	Use:   "compare [a] [b]",
+	Short: "Compare the configuration and contents of two clusters",
+	Long: `Compare two clusters' configuration (provider, region, version, node count, tags), node
+kubelet versions, enabled addons and key resource counts (nodes, namespaces, pods, services), and
+print the fields that differ. Helpful when something works on one cluster but not on another.
+
+Both clusters are looked up with --provider and --region unless --provider-b or --region-b name
+another place for the second one.`,
+	Example: `  atlas-cli cluster compare staging prod -p aws
+  atlas-cli cluster compare dev prod --provider local --provider-b aws --region-b us-east-1`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		providerB, _ := cmd.Flags().GetString("provider-b")
+		regionB, _ := cmd.Flags().GetString("region-b")
+		showAll, _ := cmd.Flags().GetBool("all")
+		if providerB == "" {
+			providerB = providerName
+		}
+		if regionB == "" {
+			regionB = region
+		}
+
+		pA, err := services.GetProvider(providerName, region, awsProfile)
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+		pB, err := services.GetProvider(providerB, regionB, awsProfile)
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+
+		ctx := commandContext()
+		services.Log(fmt.Sprintf("Comparing clusters %s and %s", args[0], args[1]))
+		a, err := snapshotCluster(ctx, pA, args[0])
+		if err != nil {
+			return err
+		}
+		b, err := snapshotCluster(ctx, pB, args[1])
+		if err != nil {
+			return err
+		}
+		comparison := compareSnapshots(a, b, showAll)
+
+		if ok, err := writeStructured(os.Stdout, comparison); ok {
+			return err
+		}
+		printComparison(os.Stdout, comparison)
+		return nil
+	},
+}
+
+// snapshotCluster collects the fields compare looks at from clusterName
+func snapshotCluster(ctx context.Context, p providers.Provider, clusterName string) (*clusterSnapshot, error) {
+	cluster, err := p.GetCluster(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s: %w", clusterName, err)
+	}
+
+	snapshot := &clusterSnapshot{Name: clusterName, Fields: map[string]string{
+		"provider":  cluster.Provider,
+		"region":    cluster.Region,
+		"version":   cluster.Version,
+		"status":    string(cluster.Status),
+		"nodeCount": strconv.Itoa(cluster.NodeCount),
+	}}
+	for key, value := range cluster.Tags {
+		snapshot.Fields["tags."+key] = value
+	}
+
+	if addonLister, ok := p.(providers.AddonLister); ok {
+		addons, err := addonLister.ListAddons(ctx, clusterName)
+		if err != nil {
+			snapshot.Warnings = append(snapshot.Warnings, fmt.Sprintf("%s addons: %v", clusterName, err))
+		}
+		for _, addon := range addons {
+			value := "disabled"
+			if addon.Enabled {
+				value = "enabled"
+				if addon.Version != "" {
+					value += " " + addon.Version
+				}
+			}
+			snapshot.Fields["addons."+addon.Name] = value
+		}
+	}
+
+	if cluster.Status == providers.ClusterStatusRunning {
+		health, err := p.HealthCheck(ctx, clusterName)
+		if err != nil {
+			snapshot.Warnings = append(snapshot.Warnings, fmt.Sprintf("%s resources: %v", clusterName, err))
+		} else {
+			snapshot.Fields["resources.nodes"] = strconv.Itoa(len(health.Nodes))
+			var versions []string
+			for _, node := range health.Nodes {
+				if node.Version != "" && !containsString(versions, node.Version) {
+					versions = append(versions, node.Version)
+				}
+			}
+			sort.Strings(versions)
+			snapshot.Fields["nodes.kubeletVersions"] = strings.Join(versions, ",")
+			if pods := health.Pods; pods != nil {
+				snapshot.Fields["resources.pods"] = strconv.Itoa(pods.TotalPods)
+				snapshot.Fields["resources.runningPods"] = strconv.Itoa(pods.RunningPods)
+				if len(pods.Namespaces) > 0 {
+					snapshot.Fields["resources.namespaces"] = strconv.Itoa(len(pods.Namespaces))
+				}
+			}
+			if health.Services != nil {
+				snapshot.Fields["resources.services"] = strconv.Itoa(health.Services.TotalServices)
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// compareSnapshots lines up the fields of a and b, sorted by field. Identical fields are counted
+// and only listed when showAll is set.
+func compareSnapshots(a, b *clusterSnapshot, showAll bool) *clusterComparison {
+	comparison := &clusterComparison{A: a.Name, B: b.Name, Differences: []fieldDifference{}}
+	comparison.Warnings = append(append(comparison.Warnings, a.Warnings...), b.Warnings...)
+
+	fields := make(map[string]bool)
+	for field := range a.Fields {
+		fields[field] = true
+	}
+	for field := range b.Fields {
+		fields[field] = true
+	}
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	for _, field := range names {
+		difference := fieldDifference{Field: field, A: a.Fields[field], B: b.Fields[field]}
+		difference.Same = difference.A == difference.B
+		if difference.Same {
+			comparison.Identical++
+			if !showAll {
+				continue
+			}
+		}
+		comparison.Differences = append(comparison.Differences, difference)
+	}
+	return comparison
+}
+
+func printComparison(w io.Writer, c *clusterComparison) {
+	differing := 0
+	for _, d := range c.Differences {
+		if !d.Same {
+			differing++
+		}
+	}
+	if len(c.Differences) > 0 {
+		t := newTable("", "FIELD", c.A, c.B)
+		for _, d := range c.Differences {
+			marker := "~"
+			if d.Same {
+				marker = " "
+			}
+			t.addRow(marker, d.Field, valueOrDash(d.A), valueOrDash(d.B))
+		}
+		t.render(w)
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d fields differ, %d identical\n", differing, c.Identical)
+	for _, warning := range c.Warnings {
+		fmt.Fprintf(w, "Warning: %s\n", warning)
+	}
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterCompareCmd)
+
+	clusterCompareCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+	clusterCompareCmd.Flags().StringP("region", "r", "", "Region the clusters run in")
+	clusterCompareCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	clusterCompareCmd.Flags().String("provider-b", "", "Provider of the second cluster (default: --provider)")
+	clusterCompareCmd.Flags().String("region-b", "", "Region of the second cluster (default: --region)")
+	clusterCompareCmd.Flags().Bool("all", false, "List identical fields too")
+}
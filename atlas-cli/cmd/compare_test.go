@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareSnapshots(t *testing.T) {
+	a := &clusterSnapshot{Name: "dev", Fields: map[string]string{
+		"version": "1.31.0", "nodeCount": "1", "tags.team": "web", "addons.ingress": "enabled",
+	}, Warnings: []string{"dev resources: timed out"}}
+	b := &clusterSnapshot{Name: "prod", Fields: map[string]string{
+		"version": "1.31.0", "nodeCount": "3", "addons.ingress": "enabled", "addons.metrics-server": "enabled",
+	}}
+
+	got := compareSnapshots(a, b, false)
+	want := []fieldDifference{
+		{Field: "addons.metrics-server", B: "enabled"},
+		{Field: "nodeCount", A: "1", B: "3"},
+		{Field: "tags.team", A: "web"},
+	}
+	if !reflect.DeepEqual(got.Differences, want) {
+		t.Errorf("Differences = %+v, want %+v", got.Differences, want)
+	}
+	if got.Identical != 2 {
+		t.Errorf("Identical = %d, want 2", got.Identical)
+	}
+	if len(got.Warnings) != 1 {
+		t.Errorf("Warnings = %v, want the snapshot's warning", got.Warnings)
+	}
+
+	all := compareSnapshots(a, b, true)
+	if len(all.Differences) != 5 || all.Differences[0] != (fieldDifference{Field: "addons.ingress", A: "enabled", B: "enabled", Same: true}) {
+		t.Errorf("Differences with showAll = %+v", all.Differences)
+	}
+}
@@ -1,8 +1,8 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -17,21 +17,28 @@ var configShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show current configuration",
 	Long:  `Display the current configuration settings.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		config := map[string]any{
-			"verbose": GetVerbose(),
-			"output":  GetOutput(),
-			"version": GetVersion(),
+			"verbose":   GetVerbose(),
+			"output":    GetOutput(),
+			"version":   GetVersion(),
+			"read_only": isReadOnly(),
+		}
+		if services := GetServices(); services != nil {
+			config["max_concurrent_operations"] = services.GetOperationLimiter().Limit()
 		}
 
-		if GetOutput() == "json" {
-			jsonOutput, _ := json.MarshalIndent(config, "", "  ")
-			fmt.Println(string(jsonOutput))
-		} else {
-			fmt.Printf("Verbose: %t\n", config["verbose"])
-			fmt.Printf("Output Format: %s\n", config["output"])
-			fmt.Printf("Version: %s\n", config["version"])
+		if ok, err := writeStructured(os.Stdout, config); ok {
+			return err
+		}
+		fmt.Printf("Verbose: %t\n", config["verbose"])
+		fmt.Printf("Output Format: %s\n", config["output"])
+		fmt.Printf("Version: %s\n", config["version"])
+		fmt.Printf("Read Only: %t\n", config["read_only"])
+		if limit, ok := config["max_concurrent_operations"]; ok {
+			fmt.Printf("Max Concurrent Operations: %d\n", limit)
 		}
+		return nil
 	},
 }
 
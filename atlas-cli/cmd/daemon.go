@@ -0,0 +1,451 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/daemon"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/fleet"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+// monitorDaemonStartWait bounds how long 'monitor daemon start' waits for the daemon to list
+// its clusters and start monitoring them
+const monitorDaemonStartWait = time.Minute
+
+var monitorDaemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Monitor clusters from a background process",
+	Long: `Run health checks, metrics checks and alerts for a set of clusters from a long-lived process
+instead of an interactive terminal.
+
+Each change in a cluster's overall health and every alert that fires or resolves is recorded in
+~/.atlas/monitor/events.jsonl, which 'monitor daemon status' summarizes. Alerts are also sent to
+the channels in ~/.atlas/alerts.yaml when it exists.`,
+}
+
+var monitorDaemonStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the monitoring daemon",
+	Long: `Start monitoring every cluster of every provider, or those selected with --provider, --cluster
+or --fleet, in a background process. The clusters are listed once at start; restart the daemon to
+pick up new ones.
+
+The daemon detaches from the terminal, writes its PID to ~/.atlas/monitor/daemon.pid and logs to
+~/.atlas/monitor/daemon.log. Under a service manager such as systemd, run it with --foreground
+so it stays attached and logs to stdout; SIGTERM stops it cleanly.`,
+	Example: `  # Monitor every cluster in the background
+  atlas-cli monitor daemon start
+
+  # Monitor a fleet, checking health every minute
+  atlas-cli monitor daemon start --fleet prod --interval 1m
+
+  # systemd unit
+  #   [Service]
+  #   ExecStart=/usr/local/bin/atlas-cli monitor daemon start --foreground -p kind
+  #   Restart=on-failure`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		foreground, _ := cmd.Flags().GetBool("foreground")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		metricsInterval, _ := cmd.Flags().GetDuration("metrics-interval")
+		if interval < time.Second || metricsInterval < time.Second {
+			return fmt.Errorf("--interval and --metrics-interval must be at least 1s")
+		}
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		clusters, _ := cmd.Flags().GetStringArray("cluster")
+		fleetName, _ := cmd.Flags().GetString("fleet")
+		if fleetName != "" && (len(clusters) > 0 || cmd.Flags().Changed("provider")) {
+			return fmt.Errorf("--fleet cannot be combined with --cluster or --provider")
+		}
+		alertsPath, _ := cmd.Flags().GetString("alerts")
+
+		pidPath, err := monitorDaemonPath("daemon.pid")
+		if err != nil {
+			return err
+		}
+		pidFile := daemon.NewPIDFile(pidPath)
+		if !foreground {
+			executable, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("failed to find atlas-cli executable: %w", err)
+			}
+			logPath, err := monitorDaemonPath("daemon.log")
+			if err != nil {
+				return err
+			}
+			pid, err := daemon.Start(executable, append(os.Args[1:], "--foreground"), logPath, pidFile, monitorDaemonStartWait)
+			if err != nil {
+				return fmt.Errorf("monitoring daemon: %w", err)
+			}
+			fmt.Printf("Monitoring daemon started (pid %d); logging to %s\n", pid, logPath)
+			return nil
+		}
+		if pid, ok := pidFile.Running(); ok {
+			return fmt.Errorf("monitoring daemon is already running as pid %d", pid)
+		}
+
+		ctx := commandContext()
+		var members []fleet.Member
+		if fleetName != "" {
+			f, err := loadFleet(fleetName)
+			if err != nil {
+				return err
+			}
+			members = f.Members
+		} else {
+			listed, failures, err := exporterTargets(ctx, services.GetProviderFactory(), providerName, region, awsProfile, clusters)
+			if err != nil {
+				return err
+			}
+			for name, err := range failures {
+				fmt.Fprintf(os.Stderr, "Warning: failed to list %s clusters: %v\n", name, err)
+			}
+			members = listed
+		}
+		if len(members) == 0 {
+			return fmt.Errorf("no clusters to monitor")
+		}
+
+		alerts, err := loadAlertReporter(ctx, alertsPath, cmd.Flags().Changed("alerts"))
+		if err != nil {
+			return err
+		}
+		// events are journaled even without an alert config to send them anywhere else
+		var notifier *monitoring.Notifier
+		if alerts != nil {
+			notifier = alerts.notifier
+		} else if notifier, err = monitoring.NewNotifier(&monitoring.AlertConfig{}); err != nil {
+			return err
+		}
+		journal, err := loadDefault(monitoring.DefaultEventJournalPath, monitoring.NewEventJournal)
+		if err != nil {
+			return err
+		}
+		notifier.AddChannel(journal, monitoring.SeverityInfo)
+		notifier.AddChannel(&logChannel{w: os.Stdout}, monitoring.SeverityInfo)
+
+		config := monitoring.MonitoringConfig{
+			CheckInterval:   interval,
+			MetricsInterval: metricsInterval,
+			AlertThresholds: alerts.thresholds(),
+			EnableAlerts:    true,
+			Notifier:        notifier,
+			Journal:         journal,
+		}
+		getProvider := func(member fleet.Member) (providers.Provider, error) {
+			return services.GetProvider(member.Provider, member.Region, awsProfile)
+		}
+		stop, err := startDaemonMonitoring(ctx, members, getProvider, config)
+		if err != nil {
+			return err
+		}
+		defer stop()
+
+		release, err := pidFile.Acquire()
+		if err != nil {
+			return fmt.Errorf("monitoring daemon: %w", err)
+		}
+		defer release()
+		state := monitorDaemonState{PID: os.Getpid(), StartedAt: time.Now(), Interval: interval.String(), Clusters: members}
+		if err := state.save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		fmt.Printf("Monitoring %d clusters every %s (pid %d)\n", len(members), interval, os.Getpid())
+		services.Log(fmt.Sprintf("Monitoring daemon started for %d clusters", len(members)))
+		<-ctx.Done()
+		fmt.Println("Monitoring daemon stopping")
+		return nil
+	},
+}
+
+var monitorDaemonStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the monitoring daemon",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		pidPath, err := monitorDaemonPath("daemon.pid")
+		if err != nil {
+			return err
+		}
+		pid, err := daemon.Stop(daemon.NewPIDFile(pidPath), timeout)
+		if err != nil {
+			return fmt.Errorf("failed to stop monitoring daemon: %w", err)
+		}
+		if pid == 0 {
+			fmt.Println("Monitoring daemon is not running")
+			return nil
+		}
+		fmt.Printf("Stopped monitoring daemon (pid %d)\n", pid)
+		return nil
+	},
+}
+
+var monitorDaemonStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the monitoring daemon is running and what it has recorded",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("events")
+
+		journalPath, err := monitoring.DefaultEventJournalPath()
+		if err != nil {
+			return err
+		}
+		journal, err := monitoring.NewEventJournal(journalPath)
+		if err != nil {
+			return err
+		}
+		events, err := journal.Read(time.Time{}, "")
+		if err != nil {
+			return err
+		}
+		status := buildMonitorDaemonStatus(journalPath, events, limit)
+
+		if ok, err := writeStructured(os.Stdout, status); ok {
+			return err
+		}
+		printMonitorDaemonStatus(os.Stdout, status)
+		return nil
+	},
+}
+
+// startDaemonMonitoring starts monitoring members, with one StartMonitoring call per provider
+// and region, and returns a function that stops every cluster again
+func startDaemonMonitoring(ctx context.Context, members []fleet.Member, getProvider func(member fleet.Member) (providers.Provider, error), config monitoring.MonitoringConfig) (func(), error) {
+	type group struct {
+		monitor  monitoring.Monitor
+		clusters []string
+	}
+	groups := make(map[string]*group)
+	var order []string
+	for _, member := range members {
+		key := member.Provider + "/" + member.Region
+		g, ok := groups[key]
+		if !ok {
+			p, err := getProvider(member)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get provider for %s: %w", member, err)
+			}
+			g = &group{monitor: p.GetMonitor()}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.clusters = append(g.clusters, member.Cluster)
+	}
+
+	stop := func() {
+		stopCtx := context.WithoutCancel(ctx)
+		for _, g := range groups {
+			for _, cluster := range g.clusters {
+				g.monitor.StopMonitoring(stopCtx, cluster)
+			}
+		}
+	}
+	for _, key := range order {
+		g := groups[key]
+		groupConfig := config
+		groupConfig.ClusterNames = g.clusters
+		if err := g.monitor.StartMonitoring(ctx, &groupConfig); err != nil {
+			stop()
+			return nil, fmt.Errorf("failed to start monitoring %s clusters: %w", key, err)
+		}
+	}
+	return stop, nil
+}
+
+// monitorDaemonState is what a running daemon records about itself for 'monitor daemon status'
+type monitorDaemonState struct {
+	PID       int            `json:"pid"`
+	StartedAt time.Time      `json:"started_at"`
+	Interval  string         `json:"interval"`
+	Clusters  []fleet.Member `json:"clusters"`
+}
+
+func (s *monitorDaemonState) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode daemon state: %w", err)
+	}
+	path, err := monitorDaemonPath("daemon.json")
+	if err != nil {
+		return err
+	}
+	if err := store.WriteFile(path, data); err != nil {
+		return fmt.Errorf("failed to write daemon state: %w", err)
+	}
+	return nil
+}
+
+// monitorDaemonStatus is the output of 'monitor daemon status'
+type monitorDaemonStatus struct {
+	Running   bool                         `json:"running"`
+	PID       int                          `json:"pid,omitempty"`
+	StartedAt *time.Time                   `json:"started_at,omitempty"`
+	Interval  string                       `json:"interval,omitempty"`
+	Clusters  []monitorDaemonCluster       `json:"clusters"`
+	Events    []monitoring.MonitoringEvent `json:"recent_events"`
+	Journal   string                       `json:"journal"`
+}
+
+type monitorDaemonCluster struct {
+	Cluster  string                         `json:"cluster"`
+	Provider string                         `json:"provider,omitempty"`
+	Status   monitoring.ClusterHealthStatus `json:"status"`
+	Since    *time.Time                     `json:"since,omitempty"`
+}
+
+// buildMonitorDaemonStatus combines the state of the daemon keeping the journal at journalPath
+// with the last health transition of each cluster and the latest limit events from the journal
+func buildMonitorDaemonStatus(journalPath string, events []monitoring.MonitoringEvent, limit int) monitorDaemonStatus {
+	status := monitorDaemonStatus{Journal: journalPath}
+	dir := filepath.Dir(journalPath)
+	var monitored []fleet.Member
+	if pid, ok := daemon.NewPIDFile(filepath.Join(dir, "daemon.pid")).Running(); ok {
+		status.Running = true
+		status.PID = pid
+		var state monitorDaemonState
+		if data, err := os.ReadFile(filepath.Join(dir, "daemon.json")); err == nil && json.Unmarshal(data, &state) == nil && state.PID == pid {
+			status.StartedAt = &state.StartedAt
+			status.Interval = state.Interval
+			monitored = state.Clusters
+		}
+	}
+
+	latest := make(map[string]monitoring.MonitoringEvent)
+	for _, event := range events {
+		if event.EventType == monitoring.EventTypeStatusChange {
+			latest[event.ClusterName] = event
+		}
+	}
+	clusterStatus := func(name, provider string) monitorDaemonCluster {
+		cluster := monitorDaemonCluster{Cluster: name, Provider: provider, Status: monitoring.HealthStatusUnknown}
+		if event, ok := latest[name]; ok {
+			if s, ok := event.Details["status"].(string); ok {
+				cluster.Status = monitoring.ClusterHealthStatus(s)
+			}
+			since := event.Timestamp
+			cluster.Since = &since
+		}
+		return cluster
+	}
+	if len(monitored) > 0 {
+		for _, member := range monitored {
+			status.Clusters = append(status.Clusters, clusterStatus(member.Cluster, member.Provider))
+		}
+	} else {
+		for name := range latest {
+			status.Clusters = append(status.Clusters, clusterStatus(name, ""))
+		}
+		sort.Slice(status.Clusters, func(i, j int) bool { return status.Clusters[i].Cluster < status.Clusters[j].Cluster })
+	}
+	if status.Clusters == nil {
+		status.Clusters = []monitorDaemonCluster{}
+	}
+
+	if limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	if events == nil {
+		events = []monitoring.MonitoringEvent{}
+	}
+	status.Events = events
+	return status
+}
+
+func printMonitorDaemonStatus(w io.Writer, status monitorDaemonStatus) {
+	if status.Running {
+		fmt.Fprintf(w, "Monitoring daemon: running (pid %d)\n", status.PID)
+		if status.StartedAt != nil {
+			fmt.Fprintf(w, "Started: %s, checking every %s\n", status.StartedAt.Local().Format("2006-01-02 15:04:05"), status.Interval)
+		}
+	} else {
+		fmt.Fprintln(w, "Monitoring daemon: not running")
+	}
+
+	if len(status.Clusters) > 0 {
+		fmt.Fprintln(w)
+		t := newTable("CLUSTER", "PROVIDER", "STATUS", "SINCE").withSeparator()
+		for _, cluster := range status.Clusters {
+			since := "-"
+			if cluster.Since != nil {
+				since = time.Since(*cluster.Since).Round(time.Second).String()
+			}
+			t.addRow(cluster.Cluster, cluster.Provider, cluster.Status, since)
+		}
+		t.render(w)
+	}
+
+	if len(status.Events) > 0 {
+		fmt.Fprintln(w, "\nRecent events:")
+		t := newTable("TIME", "CLUSTER", "SEVERITY", "MESSAGE")
+		for _, event := range status.Events {
+			t.addRow(event.Timestamp.Local().Format("2006-01-02 15:04:05"), event.ClusterName, event.Severity, event.Message)
+		}
+		t.render(w)
+	}
+	fmt.Fprintf(w, "\nEvents are recorded in %s\n", status.Journal)
+}
+
+// logChannel prints the alerts the daemon sends, so they show up in its log or journald
+type logChannel struct {
+	w io.Writer
+}
+
+func (c *logChannel) Name() string {
+	return "log"
+}
+
+func (c *logChannel) Send(ctx context.Context, event monitoring.MonitoringEvent) error {
+	state := "firing"
+	if event.Resolved {
+		state = "resolved"
+	}
+	_, err := fmt.Fprintf(c.w, "%s %s %s %s: %s\n", event.Timestamp.Format(time.RFC3339), event.ClusterName, event.Severity, state, event.Message)
+	return err
+}
+
+// monitorDaemonPath returns the path of a file the monitoring daemon keeps next to its journal
+func monitorDaemonPath(name string) (string, error) {
+	journal, err := monitoring.DefaultEventJournalPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(journal), name), nil
+}
+
+func init() {
+	monitorCmd.AddCommand(monitorDaemonCmd)
+	monitorDaemonCmd.AddCommand(monitorDaemonStartCmd, monitorDaemonStopCmd, monitorDaemonStatusCmd)
+
+	monitorDaemonStartCmd.Flags().Bool("foreground", false, "Stay attached and log to stdout, for systemd and other service managers")
+	monitorDaemonStartCmd.Flags().Duration("interval", 30*time.Second, "How often each cluster's health is checked")
+	monitorDaemonStartCmd.Flags().Duration("metrics-interval", time.Minute, "How often each cluster's resource usage is checked against the alert thresholds")
+	monitorDaemonStartCmd.Flags().String("alerts", "", "Send alerts to the channels in this file (default ~/.atlas/alerts.yaml when it exists)")
+	monitorDaemonStartCmd.Flags().StringP("provider", "p", "all", "Cloud provider (local, kind, k3d, kubeadm, capi, aws), or all to monitor every provider")
+	monitorDaemonStartCmd.Flags().StringArray("cluster", nil, "Only monitor this cluster (repeatable)")
+	monitorDaemonStartCmd.Flags().String("fleet", "", "Monitor the members of this fleet")
+	monitorDaemonStartCmd.Flags().StringP("region", "r", "", "Region")
+	monitorDaemonStartCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+
+	monitorDaemonStopCmd.Flags().Duration("timeout", 30*time.Second, "How long to wait for the daemon to exit")
+	monitorDaemonStatusCmd.Flags().Int("events", 10, "Number of recent events to show (0 for all)")
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/grafana"
+	"github.com/spf13/cobra"
+)
+
+var dashboardsCmd = &cobra.Command{
+	Use:   "dashboards",
+	Short: "Provision Grafana dashboards for Atlas metrics",
+}
+
+var dashboardsProvisionCmd = &cobra.Command{
+	Use:   "provision",
+	Short: "Create or update the Atlas dashboards in Grafana",
+	Long: `Create or update ready-made Grafana dashboards for the metrics atlas-cli serves with
+'monitor --watch --metrics-addr': a fleet overview, provider call and operation durations, and
+cluster health history. Each dashboard has a Prometheus data source variable, so point it at the
+Prometheus that scrapes atlas-cli.
+
+Dashboards are written through the Grafana API, replacing earlier versions, or with --output-dir
+saved as JSON files for Grafana's file provisioning.`,
+	Example: `  atlas-cli dashboards provision --grafana-url https://grafana.example.com --grafana-token env://GRAFANA_TOKEN
+  atlas-cli dashboards provision --output-dir ./grafana/dashboards`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		url, _ := cmd.Flags().GetString("grafana-url")
+		token, _ := cmd.Flags().GetString("grafana-token")
+		folder, _ := cmd.Flags().GetString("folder")
+		outputDir, _ := cmd.Flags().GetString("output-dir")
+		dashboards := grafana.Dashboards()
+
+		if outputDir != "" {
+			if err := os.MkdirAll(outputDir, 0755); err != nil {
+				return fmt.Errorf("failed to create output directory: %w", err)
+			}
+			for _, dashboard := range dashboards {
+				data, err := json.MarshalIndent(dashboard.Model(), "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal dashboard %s: %w", dashboard.Title, err)
+				}
+				path := filepath.Join(outputDir, dashboard.UID+".json")
+				if err := os.WriteFile(path, data, 0644); err != nil {
+					return fmt.Errorf("failed to write dashboard %s: %w", dashboard.Title, err)
+				}
+				fmt.Printf("Wrote %s to %s\n", dashboard.Title, path)
+			}
+			return nil
+		}
+
+		if url == "" {
+			return fmt.Errorf("--grafana-url is required unless --output-dir is set")
+		}
+		ctx := commandContext()
+		url, err := secretResolver.Resolve(ctx, url)
+		if err != nil {
+			return err
+		}
+		token, err = secretResolver.Resolve(ctx, token)
+		if err != nil {
+			return err
+		}
+
+		client := grafana.NewClient(url, token)
+		folderUID, err := client.EnsureFolder(ctx, folder)
+		if err != nil {
+			return err
+		}
+		provisioned := make(map[string]string)
+		for _, dashboard := range dashboards {
+			dashboardURL, err := client.Provision(ctx, dashboard, folderUID)
+			if err != nil {
+				return err
+			}
+			provisioned[dashboard.Title] = dashboardURL
+			services.Log(fmt.Sprintf("Provisioned dashboard %s", dashboard.UID))
+		}
+
+		if ok, err := writeStructured(os.Stdout, provisioned); ok {
+			return err
+		}
+		for _, dashboard := range dashboards {
+			fmt.Printf("Provisioned %s: %s\n", dashboard.Title, provisioned[dashboard.Title])
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dashboardsCmd)
+	dashboardsCmd.AddCommand(dashboardsProvisionCmd)
+
+	dashboardsProvisionCmd.Flags().String("grafana-url", "", "Grafana base URL; may be a secret reference")
+	dashboardsProvisionCmd.Flags().String("grafana-token", "", "Grafana service account token; may be a secret reference such as env://GRAFANA_TOKEN")
+	dashboardsProvisionCmd.Flags().String("folder", "Atlas", "Grafana folder to put the dashboards in")
+	dashboardsProvisionCmd.Flags().String("output-dir", "", "Write the dashboards as JSON files here instead of calling the Grafana API")
+}
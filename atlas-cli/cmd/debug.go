@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Diagnose a running atlas-cli process",
+	Long:  `Tools for diagnosing CPU and memory use of long-running atlas-cli processes such as 'monitor --watch'.`,
+}
+
+var debugProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Capture a profile from a running atlas-cli process",
+	Long: `Capture a CPU, heap, goroutine, allocation or execution trace profile from an atlas-cli process
+started with --metrics-addr and --enable-pprof, and save it for 'go tool pprof' or 'go tool trace'.`,
+	Example: `  atlas-cli monitor my-cluster --watch --metrics-addr localhost:9464 --enable-pprof
+  atlas-cli debug profile --addr localhost:9464 --duration 30s
+  go tool pprof atlas-cpu.pprof`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr, _ := cmd.Flags().GetString("addr")
+		profileType, _ := cmd.Flags().GetString("type")
+		duration, _ := cmd.Flags().GetDuration("duration")
+		outputPath, _ := cmd.Flags().GetString("file")
+
+		profileURL, err := buildProfileURL(addr, profileType, duration)
+		if err != nil {
+			return err
+		}
+		if outputPath == "" {
+			outputPath = fmt.Sprintf("atlas-%s.pprof", profileType)
+			if profileType == "trace" {
+				outputPath = "atlas.trace"
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(commandContext(), duration+30*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, profileURL, nil)
+		if err != nil {
+			return fmt.Errorf("failed to build profile request: %w", err)
+		}
+
+		fmt.Fprintf(os.Stderr, "Collecting %s profile from %s...\n", profileType, addr)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to fetch profile: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			return fmt.Errorf("failed to fetch profile: %s: %s (is the process running with --enable-pprof?)",
+				resp.Status, strings.TrimSpace(string(body)))
+		}
+
+		file, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+
+		if _, err := io.Copy(file, resp.Body); err != nil {
+			return fmt.Errorf("failed to write profile: %w", err)
+		}
+
+		fmt.Printf("Profile written to %s\n", outputPath)
+		return nil
+	},
+}
+
+// profileEndpoints maps profile types to their pprof endpoint and whether they sample over time
+var profileEndpoints = map[string]struct {
+	path    string
+	sampled bool
+}{
+	"cpu":       {path: "profile", sampled: true},
+	"trace":     {path: "trace", sampled: true},
+	"heap":      {path: "heap"},
+	"allocs":    {path: "allocs"},
+	"goroutine": {path: "goroutine"},
+}
+
+// buildProfileURL returns the pprof URL for profileType on addr
+func buildProfileURL(addr, profileType string, duration time.Duration) (string, error) {
+	endpoint, exists := profileEndpoints[profileType]
+	if !exists {
+		return "", fmt.Errorf("unknown profile type: %s (available: allocs, cpu, goroutine, heap, trace)", profileType)
+	}
+	if endpoint.sampled && duration < time.Second {
+		return "", fmt.Errorf("duration must be at least 1s for %s profiles", profileType)
+	}
+
+	base := addr
+	if !strings.Contains(base, "://") {
+		base = "http://" + base
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid address %s: %w", addr, err)
+	}
+	u.Path = "/debug/pprof/" + endpoint.path
+	if endpoint.sampled {
+		u.RawQuery = url.Values{"seconds": {fmt.Sprintf("%d", int(duration.Seconds()))}}.Encode()
+	}
+	return u.String(), nil
+}
+
+func init() {
+	rootCmd.AddCommand(debugCmd)
+	debugCmd.AddCommand(debugProfileCmd)
+
+	debugProfileCmd.Flags().String("addr", "localhost:9464", "Address the target process serves --metrics-addr on")
+	debugProfileCmd.Flags().String("type", "cpu", "Profile type (cpu, heap, allocs, goroutine, trace)")
+	debugProfileCmd.Flags().Duration("duration", 30*time.Second, "How long to sample cpu and trace profiles")
+	debugProfileCmd.Flags().StringP("file", "f", "", "File to write the profile to (default atlas-<type>.pprof)")
+}
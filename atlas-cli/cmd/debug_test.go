@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildProfileURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		addr        string
+		profileType string
+		duration    time.Duration
+		want        string
+		wantErr     bool
+	}{
+		{"cpu", "localhost:9464", "cpu", 30 * time.Second, "http://localhost:9464/debug/pprof/profile?seconds=30", false},
+		{"trace", "localhost:9464", "trace", 5 * time.Second, "http://localhost:9464/debug/pprof/trace?seconds=5", false},
+		{"heap ignores duration", "localhost:9464", "heap", 0, "http://localhost:9464/debug/pprof/heap", false},
+		{"explicit scheme", "http://10.0.0.5:9464", "goroutine", 0, "http://10.0.0.5:9464/debug/pprof/goroutine", false},
+		{"unknown type", "localhost:9464", "block", 0, "", true},
+		{"cpu needs a duration", "localhost:9464", "cpu", 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildProfileURL(tt.addr, tt.profileType, tt.duration)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildProfileURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("buildProfileURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// clusterDescription is everything cluster describe knows about a cluster. Sections that could
+// not be collected are left empty and explained in Warnings.
+type clusterDescription struct {
+	Cluster          *providers.Cluster            `json:"cluster"`
+	Endpoints        map[string]string             `json:"endpoints,omitempty"`
+	Kubeconfig       string                        `json:"kubeconfig"`
+	Addons           []providers.Addon             `json:"addons,omitempty"`
+	Resources        []providers.ClusterResource   `json:"resources,omitempty"`
+	Health           *healthSummary                `json:"health,omitempty"`
+	RecentOperations []*logsource.OperationHistory `json:"recent_operations,omitempty"`
+	Cost             *providers.CostEstimate       `json:"cost,omitempty"`
+	Warnings         []string                      `json:"warnings,omitempty"`
+}
+
+type healthSummary struct {
+	Status   monitoring.ClusterHealthStatus `json:"status"`
+	Nodes    string                         `json:"nodes"`
+	Warnings []string                       `json:"warnings,omitempty"`
+	Errors   []string                       `json:"errors,omitempty"`
+}
+
+var clusterDescribeCmd = &cobra.Command{
+	Use:   "describe [name]",
+	Short: "Show detailed information about a cluster",
+	Long: `Show an exhaustive view of a cluster: status, endpoints, kubeconfig, addons, resources,
+recent operations, health summary and cost estimate. Supports every --output format except name.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		clusterName := args[0]
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		historyLimit, _ := cmd.Flags().GetInt("history")
+
+		p, err := services.GetProvider(providerName, region, awsProfile)
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+
+		services.Log(fmt.Sprintf("Describing cluster: %s", clusterName))
+		description, err := describeCluster(commandContext(), p, clusterName, historyLimit)
+		if err != nil {
+			return err
+		}
+		warnVersionSupport(description.Cluster)
+
+		if ok, err := writeStructured(os.Stdout, description); ok {
+			return err
+		}
+		printDescription(description)
+		return nil
+	},
+}
+
+func describeCluster(ctx context.Context, p providers.Provider, clusterName string, historyLimit int) (*clusterDescription, error) {
+	cluster, err := p.GetCluster(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	description := &clusterDescription{
+		Cluster:    cluster,
+		Kubeconfig: kubeconfigPath(),
+		Resources:  cluster.Resources,
+	}
+	if cluster.Endpoint != "" {
+		description.Endpoints = map[string]string{"api-server": cluster.Endpoint}
+	}
+
+	if addonLister, ok := p.(providers.AddonLister); ok {
+		addons, err := addonLister.ListAddons(ctx, clusterName)
+		if err != nil {
+			description.Warnings = append(description.Warnings, fmt.Sprintf("addons: %v", err))
+		}
+		description.Addons = addons
+	}
+
+	if cluster.Status == providers.ClusterStatusRunning {
+		health, err := p.HealthCheck(ctx, clusterName)
+		if err != nil {
+			description.Warnings = append(description.Warnings, fmt.Sprintf("health: %v", err))
+		} else {
+			description.Health = summarizeHealth(health)
+		}
+	}
+
+	if historyLimit > 0 {
+		history, err := historySource(p).GetClusterHistory(ctx, clusterName, historyLimit)
+		if err != nil {
+			description.Warnings = append(description.Warnings, fmt.Sprintf("history: %v", err))
+		}
+		description.RecentOperations = history
+	}
+
+	if estimator, ok := p.(providers.CostEstimator); ok {
+		cost, err := estimator.EstimateCost(ctx, clusterName)
+		if err != nil {
+			description.Warnings = append(description.Warnings, fmt.Sprintf("cost: %v", err))
+		}
+		description.Cost = cost
+	}
+
+	return description, nil
+}
+
+func summarizeHealth(health *monitoring.HealthStatus) *healthSummary {
+	ready := 0
+	for _, node := range health.Nodes {
+		if node.Status == monitoring.NodeHealthy {
+			ready++
+		}
+	}
+	return &healthSummary{
+		Status:   health.OverallStatus,
+		Nodes:    fmt.Sprintf("%d/%d healthy", ready, len(health.Nodes)),
+		Warnings: health.Warnings,
+		Errors:   health.Errors,
+	}
+}
+
+// kubeconfigPath returns the kubeconfig file kubectl would use
+func kubeconfigPath() string {
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		return filepath.SplitList(env)[0]
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".kube", "config")
+}
+
+// toYAML renders v as YAML using its JSON field names so both formats share one schema
+func toYAML(v any) ([]byte, error) {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(generic)
+}
+
+func printDescription(d *clusterDescription) {
+	c := d.Cluster
+	fmt.Printf("Name:        %s\n", c.Name)
+	fmt.Printf("Provider:    %s\n", c.Provider)
+	fmt.Printf("Region:      %s\n", c.Region)
+	fmt.Printf("Version:     %s%s\n", c.Version, versionSupportNote(c.VersionSupport))
+	fmt.Printf("Status:      %s\n", c.Status)
+	fmt.Printf("Nodes:       %d\n", c.NodeCount)
+	fmt.Printf("Kubeconfig:  %s\n", d.Kubeconfig)
+
+	if len(d.Endpoints) > 0 {
+		fmt.Println("\nEndpoints:")
+		for name, endpoint := range d.Endpoints {
+			fmt.Printf("  %-12s %s\n", name, endpoint)
+		}
+	}
+
+	if len(c.Tags) > 0 {
+		fmt.Println("\nTags:")
+		for _, tag := range strings.Split(formatTagList(c.Tags), "\n") {
+			fmt.Printf("  %s\n", tag)
+		}
+	}
+
+	if len(d.Addons) > 0 {
+		var enabled []string
+		for _, addon := range d.Addons {
+			if addon.Enabled {
+				enabled = append(enabled, addon.Name)
+			}
+		}
+		fmt.Printf("\nAddons (%d enabled of %d):\n", len(enabled), len(d.Addons))
+		if len(enabled) > 0 {
+			fmt.Printf("  %s\n", strings.Join(enabled, ", "))
+		}
+	}
+
+	if len(d.Resources) > 0 {
+		fmt.Println("\nResources:")
+		for _, resource := range d.Resources {
+			fmt.Printf("  %s/%s (from %s)\n", resource.Kind, resource.Name, resource.Source)
+		}
+	}
+
+	if d.Health != nil {
+		fmt.Println("\nHealth:")
+		fmt.Printf("  Status: %s\n", getStatusDisplayIcon(string(d.Health.Status)))
+		fmt.Printf("  Nodes:  %s\n", d.Health.Nodes)
+		for _, warning := range d.Health.Warnings {
+			fmt.Printf("  Warning: %s\n", warning)
+		}
+		for _, err := range d.Health.Errors {
+			fmt.Printf("  Error: %s\n", err)
+		}
+	}
+
+	if len(d.RecentOperations) > 0 {
+		fmt.Println("\nRecent Operations:")
+		for _, op := range d.RecentOperations {
+			fmt.Printf("  %-20s %-8s %-10s %s\n",
+				op.StartedAt.Format("Jan 02 15:04:05"),
+				op.OperationType,
+				op.OperationStatus,
+				op.UserID)
+		}
+	}
+
+	if d.Cost != nil {
+		fmt.Println("\nCost Estimate:")
+		fmt.Printf("  $%.2f/hour, $%.2f/month\n", d.Cost.HourlyUSD, d.Cost.MonthlyUSD)
+		for _, item := range d.Cost.Items {
+			fmt.Printf("  %-30s x%-3d $%.4f/hour\n", item.Name, item.Quantity, item.HourlyUSD)
+		}
+		if d.Cost.Note != "" {
+			fmt.Printf("  (%s)\n", d.Cost.Note)
+		}
+	}
+
+	for _, warning := range d.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: could not collect %s\n", warning)
+	}
+}
+
+func formatTagList(tags map[string]string) string {
+	var lines []string
+	for key, value := range tags {
+		lines = append(lines, key+"="+value)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterDescribeCmd)
+
+	clusterDescribeCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+	clusterDescribeCmd.Flags().StringP("region", "r", "", "Region the cluster runs in")
+	clusterDescribeCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	clusterDescribeCmd.Flags().Int("history", 10, "Number of recent operations to include (0 to skip)")
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+)
+
+func TestSummarizeHealth(t *testing.T) {
+	summary := summarizeHealth(&monitoring.HealthStatus{
+		OverallStatus: monitoring.HealthStatusWarning,
+		Nodes: []monitoring.NodeHealth{
+			{Name: "node-1", Status: monitoring.NodeHealthy},
+			{Name: "node-2", Status: monitoring.NodeNotReady},
+		},
+		Warnings: []string{"node-2 not ready"},
+	})
+
+	if summary.Status != monitoring.HealthStatusWarning {
+		t.Errorf("Status = %q, want %q", summary.Status, monitoring.HealthStatusWarning)
+	}
+	if summary.Nodes != "1/2 healthy" {
+		t.Errorf("Nodes = %q, want %q", summary.Nodes, "1/2 healthy")
+	}
+}
+
+func TestToYAML_UsesJSONFieldNames(t *testing.T) {
+	description := &clusterDescription{
+		Cluster:    &providers.Cluster{Name: "dev", NodeCount: 2},
+		Kubeconfig: "/home/dev/.kube/config",
+		Cost:       &providers.CostEstimate{HourlyUSD: 0.1},
+	}
+
+	out, err := toYAML(description)
+	if err != nil {
+		t.Fatalf("toYAML() unexpected error = %v", err)
+	}
+	for _, want := range []string{"nodeCount: 2", "kubeconfig: /home/dev/.kube/config", "hourly_usd: 0.1"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("toYAML() output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+// configDrift is one field a config file sets, compared with the cluster; a missing value is empty
+type configDrift struct {
+	Field   string `json:"field"`
+	Desired string `json:"desired"`
+	Actual  string `json:"actual"`
+	InSync  bool   `json:"inSync"`
+}
+
+type clusterDiff struct {
+	Cluster  string        `json:"cluster"`
+	Provider string        `json:"provider"`
+	File     string        `json:"file"`
+	Fields   []configDrift `json:"fields"`
+	Drifted  int           `json:"drifted"`
+	Warnings []string      `json:"warnings,omitempty"`
+}
+
+var clusterDiffCmd = &cobra.Command{
+	Use:   "diff [name] -f config.yaml",
+	Short: "Show how a cluster differs from its config file",
+	Long: `Compare the settings a cluster config file pins down (version, node count, tags, addons and
+network settings) with the cluster as the provider reports it, and print the fields that differ.
+Fields the file leaves unset aren't compared. The cluster is the one the file names unless a name
+is given.
+
+Pass --exit-code to exit with status 1 when the cluster has drifted, e.g. in a scheduled check.
+Run cluster apply to bring the version, node count and addons back in line.`,
+	Example: `  atlas-cli cluster diff -f cluster.yaml
+  atlas-cli cluster diff prod -f prod.yaml -p aws --exit-code`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		configFile, _ := cmd.Flags().GetString("file")
+		providerName, _ := cmd.Flags().GetString("provider")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		showAll, _ := cmd.Flags().GetBool("all")
+		exitCode, _ := cmd.Flags().GetBool("exit-code")
+
+		config, _, err := loadClusterConfig(configFile, false)
+		if err != nil {
+			return err
+		}
+		clusterName := config.Name
+		if len(args) > 0 {
+			clusterName = args[0]
+		}
+		if clusterName == "" {
+			return fmt.Errorf("%s does not name a cluster; set name or pass the cluster name", configFile)
+		}
+		if awsProfile == "" && config.AWS != nil {
+			awsProfile = config.AWS.Profile
+		}
+
+		p, err := services.GetProvider(providerName, config.Region, awsProfile)
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+
+		ctx := commandContext()
+		services.Log(fmt.Sprintf("Comparing cluster %s with %s", clusterName, configFile))
+		diff, err := diffClusterConfig(ctx, p, clusterName, config)
+		if err != nil {
+			return err
+		}
+		diff.File = filepath.Base(configFile)
+
+		ok, err := writeStructured(os.Stdout, diff)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			printClusterDiff(os.Stdout, diff, showAll, colorEnabled())
+		}
+		for _, warning := range diff.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+
+		if exitCode && diff.Drifted > 0 {
+			return &exitError{code: 1, err: fmt.Errorf("cluster %s has drifted from %s in %d fields", clusterName, diff.File, diff.Drifted)}
+		}
+		return nil
+	},
+}
+
+// diffClusterConfig compares what config sets with clusterName as p reports it
+func diffClusterConfig(ctx context.Context, p providers.Provider, clusterName string, config *providers.ClusterConfig) (*clusterDiff, error) {
+	snapshot, err := snapshotCluster(ctx, p, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	warnings := snapshot.Warnings
+	if config.NetworkConfig != nil {
+		if inspector, ok := p.(providers.NetworkInspector); ok {
+			network, err := inspector.GetNetworkConfig(ctx, clusterName)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s network: %v", clusterName, err))
+			} else {
+				for field, value := range networkFields(network) {
+					snapshot.Fields[field] = value
+				}
+			}
+		} else {
+			warnings = append(warnings, fmt.Sprintf("provider %s does not report network settings; networkConfig not compared", p.GetProviderName()))
+		}
+	}
+
+	diff := compareConfigFields(desiredFields(config), snapshot.Fields)
+	diff.Cluster = clusterName
+	diff.Provider = p.GetProviderName()
+	diff.Warnings = warnings
+	return diff, nil
+}
+
+// desiredFields flattens the settings config sets into the field names snapshotCluster uses
+func desiredFields(config *providers.ClusterConfig) map[string]string {
+	fields := make(map[string]string)
+	if config.Version != "" {
+		fields["version"] = config.Version
+	}
+	if config.NodeCount > 0 {
+		fields["nodeCount"] = strconv.Itoa(config.NodeCount)
+	}
+	for key, value := range config.Tags {
+		fields["tags."+key] = value
+	}
+	for _, addon := range config.Addons {
+		fields["addons."+addon] = "enabled"
+	}
+	if config.NetworkConfig != nil {
+		for field, value := range networkFields(config.NetworkConfig) {
+			fields[field] = value
+		}
+		if config.NetworkConfig.NetworkPlugin == "auto" {
+			delete(fields, "network.networkPlugin")
+		}
+	}
+	return fields
+}
+
+// networkFields flattens the network settings diff compares, skipping unset ones
+func networkFields(network *providers.NetworkConfig) map[string]string {
+	fields := make(map[string]string)
+	for field, value := range map[string]string{
+		"network.podCIDR":       network.PodCIDR,
+		"network.serviceCIDR":   network.ServiceCIDR,
+		"network.networkPlugin": network.NetworkPlugin,
+	} {
+		if value != "" {
+			fields[field] = value
+		}
+	}
+	if network.APIServerPort > 0 {
+		fields["network.apiServerPort"] = strconv.Itoa(network.APIServerPort)
+	}
+	return fields
+}
+
+// compareConfigFields compares each desired field with the actual one, sorted by field. Versions
+// match across spellings such as v1.31.0 and 1.31, and an addon is in sync once it is enabled at
+// any version.
+func compareConfigFields(desired, actual map[string]string) *clusterDiff {
+	diff := &clusterDiff{Fields: []configDrift{}}
+	names := make([]string, 0, len(desired))
+	for field := range desired {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	for _, field := range names {
+		drift := configDrift{Field: field, Desired: desired[field], Actual: actual[field]}
+		switch {
+		case field == "version":
+			drift.InSync = providers.SameKubeVersion(drift.Desired, drift.Actual)
+		case strings.HasPrefix(field, "addons."):
+			drift.InSync = drift.Actual == "enabled" || strings.HasPrefix(drift.Actual, "enabled ")
+		default:
+			drift.InSync = drift.Desired == drift.Actual
+		}
+		if !drift.InSync {
+			diff.Drifted++
+		}
+		diff.Fields = append(diff.Fields, drift)
+	}
+	return diff
+}
+
+// printClusterDiff prints the drifted fields, or every compared field when showAll is set. A field
+// the cluster lacks is marked "-" and one with another value "~", in red and yellow when color is
+// set.
+func printClusterDiff(w io.Writer, diff *clusterDiff, showAll, color bool) {
+	if diff.Drifted == 0 {
+		fmt.Fprintf(w, "Cluster '%s' (%s) matches %s (%d fields compared)\n", diff.Cluster, diff.Provider, diff.File, len(diff.Fields))
+		if !showAll {
+			return
+		}
+	} else {
+		fmt.Fprintf(w, "Cluster '%s' (%s) differs from %s in %d of %d fields:\n", diff.Cluster, diff.Provider, diff.File,
+			diff.Drifted, len(diff.Fields))
+	}
+	fmt.Fprintln(w)
+
+	t := newTable("", "FIELD", "DESIRED", "ACTUAL")
+	for _, drift := range diff.Fields {
+		marker := " "
+		switch {
+		case drift.InSync:
+			if !showAll {
+				continue
+			}
+		case drift.Actual == "":
+			marker = "-"
+		default:
+			marker = "~"
+		}
+		t.addRow(marker, drift.Field, drift.Desired, valueOrDash(drift.Actual))
+	}
+	var table bytes.Buffer
+	t.render(&table)
+	for _, line := range strings.SplitAfter(table.String(), "\n") {
+		switch {
+		case color && strings.HasPrefix(line, "-"):
+			line = "\033[31m" + strings.TrimSuffix(line, "\n") + "\033[0m\n"
+		case color && strings.HasPrefix(line, "~"):
+			line = "\033[33m" + strings.TrimSuffix(line, "\n") + "\033[0m\n"
+		}
+		io.WriteString(w, line)
+	}
+}
+
+// colorEnabled reports whether output should be colored: stdout is a terminal and $NO_COLOR is unset
+func colorEnabled() bool {
+	return os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterDiffCmd)
+
+	clusterDiffCmd.Flags().StringP("file", "f", "", "Cluster config file (YAML)")
+	clusterDiffCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+	clusterDiffCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	clusterDiffCmd.Flags().Bool("all", false, "Also list fields that match")
+	clusterDiffCmd.Flags().Bool("exit-code", false, "Exit with status 1 when the cluster differs from the file")
+	clusterDiffCmd.MarkFlagRequired("file")
+}
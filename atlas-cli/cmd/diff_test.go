@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+)
+
+func TestCompareConfigFields(t *testing.T) {
+	config := &providers.ClusterConfig{
+		Name:      "dev",
+		Version:   "v1.31.0",
+		NodeCount: 3,
+		Tags:      map[string]string{"team": "payments"},
+		Addons:    []string{"ingress", "metrics-server"},
+		NetworkConfig: &providers.NetworkConfig{
+			ServiceCIDR:   "10.96.0.0/12",
+			NetworkPlugin: "auto",
+		},
+	}
+	actual := map[string]string{
+		"version":               "1.31",
+		"nodeCount":             "1",
+		"tags.team":             "payments",
+		"addons.ingress":        "enabled v1.2.0",
+		"network.serviceCIDR":   "10.100.0.0/16",
+		"network.networkPlugin": "calico",
+	}
+
+	diff := compareConfigFields(desiredFields(config), actual)
+
+	want := map[string]bool{
+		"version":               true,
+		"nodeCount":             false,
+		"tags.team":             true,
+		"addons.ingress":        true,
+		"addons.metrics-server": false,
+		"network.serviceCIDR":   false,
+	}
+	if len(diff.Fields) != len(want) {
+		t.Fatalf("compareConfigFields() compared %d fields, want %d: %+v", len(diff.Fields), len(want), diff.Fields)
+	}
+	for _, drift := range diff.Fields {
+		inSync, ok := want[drift.Field]
+		if !ok {
+			t.Errorf("unexpected field %s", drift.Field)
+			continue
+		}
+		if drift.InSync != inSync {
+			t.Errorf("%s in sync = %v, want %v", drift.Field, drift.InSync, inSync)
+		}
+	}
+	if diff.Drifted != 3 {
+		t.Errorf("Drifted = %d, want 3", diff.Drifted)
+	}
+}
+
+func TestPrintClusterDiff(t *testing.T) {
+	diff := &clusterDiff{Cluster: "dev", Provider: "local", File: "cluster.yaml", Drifted: 2, Fields: []configDrift{
+		{Field: "addons.ingress", Desired: "enabled"},
+		{Field: "nodeCount", Desired: "3", Actual: "1"},
+		{Field: "version", Desired: "v1.31.0", Actual: "v1.31.0", InSync: true},
+	}}
+
+	var out bytes.Buffer
+	printClusterDiff(&out, diff, false, true)
+	got := out.String()
+	for _, want := range []string{
+		"differs from cluster.yaml in 2 of 3 fields",
+		"\033[31m-  addons.ingress  enabled  -\033[0m",
+		"\033[33m~  nodeCount       3        1\033[0m",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("printClusterDiff() output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "version") {
+		t.Errorf("printClusterDiff() listed a field in sync without showAll:\n%s", got)
+	}
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed examples.yaml
+var examplesCatalog []byte
+
+// example is a copy-pasteable recipe from the embedded catalog
+type example struct {
+	Name  string `yaml:"name" json:"name"`
+	Title string `yaml:"title" json:"title"`
+	// Command is the command path the example is listed under, e.g. "cluster create"
+	Command     string   `yaml:"command" json:"command"`
+	Description string   `yaml:"description" json:"description"`
+	Steps       []string `yaml:"steps" json:"steps"`
+}
+
+var examplesCmd = &cobra.Command{
+	Use:   "examples [command]",
+	Short: "Show examples of common tasks",
+	Long: `Print curated, copy-pasteable examples of common tasks, such as creating a cluster with
+monitoring or an ephemeral cluster for CI. Pass a command, e.g. 'cluster create', to show only
+the examples for it and its subcommands, or the name of an example to show just that one.`,
+	Example: `  atlas-cli examples
+  atlas-cli examples cluster create
+  atlas-cli examples ci-ephemeral`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		examples, err := loadExamples()
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			examples = matchExamples(examples, strings.Join(args, " "))
+			if len(examples) == 0 {
+				return fmt.Errorf("no examples for '%s'; run 'atlas-cli examples' to see them all", strings.Join(args, " "))
+			}
+		}
+
+		if ok, err := writeStructured(os.Stdout, examples); ok {
+			return err
+		}
+		printExamples(os.Stdout, examples)
+		return nil
+	},
+}
+
+func loadExamples() ([]example, error) {
+	var examples []example
+	if err := yaml.Unmarshal(examplesCatalog, &examples); err != nil {
+		return nil, fmt.Errorf("failed to parse examples catalog: %w", err)
+	}
+	return examples, nil
+}
+
+// matchExamples returns the example named query, or else the examples for the command path query
+// and its subcommands
+func matchExamples(examples []example, query string) []example {
+	for _, e := range examples {
+		if e.Name == query {
+			return []example{e}
+		}
+	}
+	var matched []example
+	for _, e := range examples {
+		if e.Command == query || strings.HasPrefix(e.Command, query+" ") {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+func printExamples(w io.Writer, examples []example) {
+	for i, e := range examples {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "# %s (%s)\n", e.Title, e.Name)
+		fmt.Fprintf(w, "# %s\n", e.Description)
+		for _, step := range e.Steps {
+			fmt.Fprintln(w, step)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(examplesCmd)
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// TestExamplesCatalog checks every example step names real commands, flags and argument counts
+func TestExamplesCatalog(t *testing.T) {
+	examples, err := loadExamples()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(examples) == 0 {
+		t.Fatal("examples catalog is empty")
+	}
+
+	names := make(map[string]bool)
+	for _, e := range examples {
+		if e.Name == "" || e.Title == "" || e.Description == "" || len(e.Steps) == 0 {
+			t.Errorf("example %+v needs a name, title, description and steps", e)
+		}
+		if names[e.Name] {
+			t.Errorf("duplicate example name %q", e.Name)
+		}
+		names[e.Name] = true
+
+		if c, rest, err := rootCmd.Find(strings.Fields(e.Command)); err != nil || len(rest) > 0 || c == rootCmd {
+			t.Errorf("example %s: command %q does not exist", e.Name, e.Command)
+		}
+		for _, step := range e.Steps {
+			if err := checkExampleStep(step); err != nil {
+				t.Errorf("example %s: %q: %v", e.Name, step, err)
+			}
+		}
+	}
+}
+
+// checkExampleStep resolves step's subcommands the way cobra would and checks its flags exist
+// and its positional arguments satisfy the command's Args
+func checkExampleStep(step string) error {
+	tokens := strings.Fields(step)
+	if len(tokens) == 0 || tokens[0] != "atlas-cli" {
+		return fmt.Errorf("steps must start with atlas-cli")
+	}
+	c := rootCmd
+	var positional []string
+	for i := 1; i < len(tokens); i++ {
+		token := tokens[i]
+		if !strings.HasPrefix(token, "-") || token == "-" {
+			if len(positional) == 0 {
+				if sub := findSubcommand(c, token); sub != nil {
+					c = sub
+					continue
+				}
+			}
+			positional = append(positional, token)
+			continue
+		}
+
+		name, _, hasValue := strings.Cut(strings.TrimLeft(token, "-"), "=")
+		var flag *pflag.Flag
+		if strings.HasPrefix(token, "--") {
+			flag = lookupFlag(c, name)
+		} else if len(name) == 1 {
+			flag = lookupShorthand(c, name)
+		}
+		if flag == nil {
+			return fmt.Errorf("unknown flag %s for '%s'", token, c.CommandPath())
+		}
+		if !hasValue && flag.NoOptDefVal == "" {
+			i++
+			if i >= len(tokens) {
+				return fmt.Errorf("flag %s needs a value", token)
+			}
+		}
+	}
+	if !c.Runnable() {
+		return fmt.Errorf("'%s' is not a runnable command", c.CommandPath())
+	}
+	return c.ValidateArgs(positional)
+}
+
+func findSubcommand(c *cobra.Command, name string) *cobra.Command {
+	for _, sub := range c.Commands() {
+		if sub.Name() == name || sub.HasAlias(name) {
+			return sub
+		}
+	}
+	return nil
+}
+
+func lookupFlag(c *cobra.Command, name string) *pflag.Flag {
+	if flag := c.Flags().Lookup(name); flag != nil {
+		return flag
+	}
+	return c.InheritedFlags().Lookup(name)
+}
+
+func lookupShorthand(c *cobra.Command, name string) *pflag.Flag {
+	if flag := c.Flags().ShorthandLookup(name); flag != nil {
+		return flag
+	}
+	return c.InheritedFlags().ShorthandLookup(name)
+}
+
+func TestMatchExamples(t *testing.T) {
+	examples := []example{
+		{Name: "ci", Command: "cluster create"},
+		{Name: "pools", Command: "nodepool create"},
+		{Name: "demo", Command: "cluster"},
+	}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"ci", []string{"ci"}},
+		{"cluster create", []string{"ci"}},
+		{"cluster", []string{"ci", "demo"}},
+		{"nodepool", []string{"pools"}},
+		{"fleet", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, e := range matchExamples(examples, tt.query) {
+			got = append(got, e.Name)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("matchExamples(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/fleet"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/metrics"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+var monitorServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Export cluster health and metrics to Prometheus",
+	Long: `Check every monitored cluster on an interval and serve the results as Prometheus metrics
+at /metrics, so existing Prometheus and Grafana stacks can scrape Atlas.
+
+By default every cluster of every provider is monitored; narrow it with --provider, --cluster
+or --fleet. Clusters are listed again each round, so new clusters are picked up and deleted ones
+stop being exported. Each cluster's latest health is also served at /health/<cluster>, and the
+metrics collected are recorded for 'metrics history'.
+
+Exported metrics, labelled with cluster and provider, include atlas_cluster_up,
+atlas_cluster_ready_nodes, atlas_cluster_failed_pods, atlas_cluster_pods{phase},
+atlas_control_plane_component_up{component}, atlas_node_ready{node}, atlas_cluster_cpu_percent,
+atlas_cluster_memory_percent, atlas_node_cpu_percent{node} and atlas_node_memory_percent{node}.`,
+	Example: `  # Export every cluster on port 9100
+  atlas-cli monitor serve --listen :9100
+
+  # Export two kind clusters every 15 seconds
+  atlas-cli monitor serve -p kind --cluster dev --cluster staging --interval 15s
+
+  # Export the members of a fleet
+  atlas-cli monitor serve --fleet prod`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		listen, _ := cmd.Flags().GetString("listen")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if interval < time.Second {
+			return fmt.Errorf("--interval must be at least 1s")
+		}
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		clusters, _ := cmd.Flags().GetStringArray("cluster")
+		fleetName, _ := cmd.Flags().GetString("fleet")
+		includeMetrics, _ := cmd.Flags().GetBool("metrics")
+		if fleetName != "" && (len(clusters) > 0 || cmd.Flags().Changed("provider")) {
+			return fmt.Errorf("--fleet cannot be combined with --cluster or --provider")
+		}
+
+		ctx := commandContext()
+		// providers that can't be listed, such as aws without credentials, are only reported once
+		warned := make(map[string]bool)
+		targets := func(ctx context.Context) ([]fleet.Member, error) {
+			if fleetName != "" {
+				f, err := loadFleet(fleetName)
+				if err != nil {
+					return nil, err
+				}
+				return f.Members, nil
+			}
+			members, failures, err := exporterTargets(ctx, services.GetProviderFactory(), providerName, region, awsProfile, clusters)
+			for name, err := range failures {
+				if !warned[name] {
+					fmt.Fprintf(os.Stderr, "Warning: failed to list %s clusters: %v\n", name, err)
+					warned[name] = true
+				}
+			}
+			return members, err
+		}
+		// fail fast on a bad fleet or provider rather than serving nothing
+		if _, err := targets(ctx); err != nil {
+			return err
+		}
+
+		exporter := monitoring.NewExporter()
+		registry := metrics.NewRegistry()
+		registry.OnScrape(exporter.Collect)
+		registry.OnScrape(collectOperationMetrics(services.GetOperationLimiter()))
+		health := monitoring.NewHealthEndpoint(3 * interval)
+		if err := serveMetrics(ctx, listen, registry, health, false); err != nil {
+			return err
+		}
+
+		historyDir, err := monitoring.DefaultMetricsHistoryDir()
+		if err != nil {
+			return err
+		}
+		history := monitoring.NewMetricsHistory(historyDir)
+		getProvider := func(member fleet.Member) (providers.Provider, error) {
+			return services.GetProvider(member.Provider, member.Region, awsProfile)
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			members, err := targets(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			} else {
+				exportRound(ctx, members, includeMetrics, getProvider, exporter, health, registry, history)
+				services.Log(fmt.Sprintf("Exported %d clusters", len(members)))
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+// exporterTargets lists the clusters monitor serve checks: every cluster of providerName, or
+// of every provider when it is "all", narrowed to names when any are given. With "all", a
+// provider that can't be listed is returned in failures so the others are still exported.
+func exporterTargets(ctx context.Context, factory *providers.ProviderFactory, providerName, region, awsProfile string, names []string) ([]fleet.Member, map[string]error, error) {
+	var clusters []*providers.Cluster
+	var failures map[string]error
+	if providerName == "all" {
+		clusters, failures = listAllClusters(ctx, factory, region, awsProfile)
+	} else {
+		p, err := factory.CreateProvider(providerName, region, awsProfile)
+		if err != nil {
+			return nil, nil, err
+		}
+		clusters, err = p.ListClusters(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list %s clusters: %w", providerName, err)
+		}
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var members []fleet.Member
+	for _, cluster := range clusters {
+		if len(wanted) > 0 && !wanted[cluster.Name] {
+			continue
+		}
+		provider := cluster.Provider
+		if provider == "" {
+			provider = providerName
+		}
+		members = append(members, fleet.Member{Cluster: cluster.Name, Provider: provider, Region: cluster.Region})
+	}
+	return members, failures, nil
+}
+
+// exportRound checks every member concurrently, each bounded by monitorCheckTimeout, records
+// the results in exporter and health, records collected metrics in history when it is set, and
+// drops clusters that are no longer members
+func exportRound(ctx context.Context, members []fleet.Member, includeMetrics bool, getProvider func(member fleet.Member) (providers.Provider, error),
+	exporter *monitoring.Exporter, health *monitoring.HealthEndpoint, registry *metrics.Registry, history *monitoring.MetricsHistory) {
+	exported := make([]monitoring.ExportedCluster, len(members))
+	var wg sync.WaitGroup
+	for i, member := range members {
+		exported[i] = monitoring.ExportedCluster{Provider: member.Provider, Name: member.Cluster}
+		wg.Add(1)
+		go func(cluster monitoring.ExportedCluster, member fleet.Member) {
+			defer wg.Done()
+
+			p, err := getProvider(member)
+			if err != nil {
+				exporter.Record(cluster, nil, err, nil, nil)
+				return
+			}
+			checkCtx, cancel := context.WithTimeout(ctx, monitorCheckTimeout)
+			defer cancel()
+			monitor := p.GetMonitor()
+
+			start := time.Now()
+			status, checkErr := monitor.CheckClusterHealth(checkCtx, member.Cluster)
+			registry.Since(providerCallMetric, providerCallHelp, metrics.Labels{"cluster": member.Cluster, "call": "health"}, start, checkErr)
+			if ctx.Err() != nil {
+				return
+			}
+			var clusterMetrics *monitoring.ClusterMetrics
+			var metricsErr error
+			if includeMetrics && checkErr == nil {
+				start := time.Now()
+				clusterMetrics, metricsErr = monitor.GetClusterMetrics(checkCtx, member.Cluster)
+				registry.Since(providerCallMetric, providerCallHelp, metrics.Labels{"cluster": member.Cluster, "call": "metrics"}, start, metricsErr)
+				if metricsErr == nil && history != nil {
+					recordMetricsHistory(history, member.Provider, member.Cluster, clusterMetrics)
+				}
+			}
+			exporter.Record(cluster, status, checkErr, clusterMetrics, metricsErr)
+			health.Update(monitoring.Summarize(member.Cluster, status, checkErr))
+		}(exported[i], member)
+	}
+	wg.Wait()
+	exporter.Retain(exported)
+}
+
+func init() {
+	monitorCmd.AddCommand(monitorServeCmd)
+
+	monitorServeCmd.Flags().String("listen", ":9100", "Address to serve /metrics and /health/<cluster> on")
+	monitorServeCmd.Flags().Duration("interval", 30*time.Second, "How often every cluster is checked")
+	monitorServeCmd.Flags().Bool("metrics", true, "Also collect CPU and memory usage; disable for clusters without metrics-server")
+	monitorServeCmd.Flags().StringP("provider", "p", "all", "Cloud provider (local, kind, k3d, kubeadm, capi, aws), or all to export every provider")
+	monitorServeCmd.Flags().StringArray("cluster", nil, "Only export this cluster (repeatable)")
+	monitorServeCmd.Flags().String("fleet", "", "Export the members of this fleet")
+	monitorServeCmd.Flags().StringP("region", "r", "", "Region")
+	monitorServeCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+}
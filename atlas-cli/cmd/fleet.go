@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/fleet"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+var fleetCmd = &cobra.Command{
+	Use:   "fleet",
+	Short: "Manage groups of clusters",
+	Long: `Group clusters, across providers and regions, into named fleets and operate on or monitor
+every cluster in a fleet at once.`,
+}
+
+var fleetCreateCmd = &cobra.Command{
+	Use:   "create [fleet] [cluster...]",
+	Short: "Create a fleet",
+	Example: `  atlas-cli fleet create dev-fleet dev-1 dev-2
+  atlas-cli fleet create prod --provider aws --region us-west-2 prod-usw2`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := loadDefault(fleet.DefaultStorePath, fleet.LoadStore)
+		if err != nil {
+			return err
+		}
+		name := args[0]
+		if _, err := store.Get(name); err == nil {
+			return fmt.Errorf("fleet %s already exists", name)
+		}
+
+		description, _ := cmd.Flags().GetString("description")
+		f := &fleet.Fleet{Name: name, Description: description, Members: []fleet.Member{}}
+		f.Add(fleetMembers(cmd, args[1:])...)
+		store.Put(f)
+		if err := store.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Fleet '%s' created with %d clusters\n", name, len(f.Members))
+		return nil
+	},
+}
+
+var fleetAddCmd = &cobra.Command{
+	Use:   "add [fleet] [cluster...]",
+	Short: "Add clusters to a fleet",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := loadDefault(fleet.DefaultStorePath, fleet.LoadStore)
+		if err != nil {
+			return err
+		}
+		f, err := store.Get(args[0])
+		if err != nil {
+			return err
+		}
+		added := f.Add(fleetMembers(cmd, args[1:])...)
+		if err := store.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Added %d clusters to fleet '%s'\n", added, f.Name)
+		return nil
+	},
+}
+
+var fleetRemoveCmd = &cobra.Command{
+	Use:   "remove [fleet] [cluster...]",
+	Short: "Remove clusters from a fleet",
+	Long:  `Remove clusters from a fleet. The clusters themselves are not deleted.`,
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := loadDefault(fleet.DefaultStorePath, fleet.LoadStore)
+		if err != nil {
+			return err
+		}
+		f, err := store.Get(args[0])
+		if err != nil {
+			return err
+		}
+		removed := 0
+		for _, cluster := range args[1:] {
+			removed += f.Remove(cluster)
+		}
+		if err := store.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %d clusters from fleet '%s'\n", removed, f.Name)
+		return nil
+	},
+}
+
+var fleetDeleteCmd = &cobra.Command{
+	Use:   "delete [fleet]",
+	Short: "Delete a fleet",
+	Long:  `Delete a fleet definition. The clusters in it are not deleted.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := loadDefault(fleet.DefaultStorePath, fleet.LoadStore)
+		if err != nil {
+			return err
+		}
+		if err := store.Delete(args[0]); err != nil {
+			return err
+		}
+		if err := store.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Fleet '%s' deleted\n", args[0])
+		return nil
+	},
+}
+
+var fleetListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List fleets",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		store, err := loadDefault(fleet.DefaultStorePath, fleet.LoadStore)
+		if err != nil {
+			return err
+		}
+		fleets := store.List()
+
+		if ok, err := writeStructured(os.Stdout, fleets); ok {
+			return err
+		}
+		if len(fleets) == 0 {
+			fmt.Println("No fleets found")
+			return nil
+		}
+		t := newTable("NAME", "CLUSTERS", "MEMBERS").withSeparator()
+		for _, f := range fleets {
+			var members []string
+			for _, member := range f.Members {
+				members = append(members, member.String())
+			}
+			t.addRow(f.Name, len(f.Members), strings.Join(members, ", "))
+		}
+		t.render(os.Stdout)
+		return nil
+	},
+}
+
+var fleetStatusCmd = &cobra.Command{
+	Use:   "status [fleet]",
+	Short: "Show the aggregated health of a fleet",
+	Long: `Check the health of every cluster in a fleet concurrently and roll it up: the fleet is
+unhealthy if any cluster is, and warning if any cluster is warning or could not be checked.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		includeMetrics, _ := cmd.Flags().GetBool("metrics")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		return showFleetHealth(commandContext(), args[0], includeMetrics, awsProfile, "")
+	},
+}
+
+var fleetStartCmd = &cobra.Command{
+	Use:   "start [fleet]",
+	Short: "Start every cluster in a fleet",
+	Long: `Start every cluster in a fleet concurrently, showing each one's phase, duration and result.
+Exits with status 2 if only some of the clusters failed to start.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		return runFleetOperation(commandContext(), args[0], "start", awsProfile, func(ctx context.Context, p providers.Provider, cluster string) error {
+			return p.StartCluster(ctx, cluster)
+		})
+	},
+}
+
+var fleetStopCmd = &cobra.Command{
+	Use:   "stop [fleet]",
+	Short: "Stop every cluster in a fleet",
+	Long: `Stop every cluster in a fleet concurrently, showing each one's phase, duration and result.
+Exits with status 2 if only some of the clusters failed to stop.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		return runFleetOperation(commandContext(), args[0], "stop", awsProfile, func(ctx context.Context, p providers.Provider, cluster string) error {
+			warnOutsideMaintenanceWindow(cluster, "stopping")
+			if err := requireApproval(cluster, "stop", ""); err != nil {
+				return err
+			}
+			return p.StopCluster(ctx, cluster)
+		})
+	},
+}
+
+// fleetMembers builds members for clusters on the --provider and --region given to cmd
+func fleetMembers(cmd *cobra.Command, clusters []string) []fleet.Member {
+	providerName, _ := cmd.Flags().GetString("provider")
+	region, _ := cmd.Flags().GetString("region")
+	members := make([]fleet.Member, len(clusters))
+	for i, cluster := range clusters {
+		members[i] = fleet.Member{Cluster: cluster, Provider: providerName, Region: region}
+	}
+	return members
+}
+
+// runFleetOperation runs op against every member of the named fleet concurrently; see
+// runBulkOperation
+func runFleetOperation(ctx context.Context, fleetName, op, awsProfile string, run bulkRun) error {
+	f, err := loadFleet(fleetName)
+	if err != nil {
+		return err
+	}
+	return runBulkOperation(ctx, f.Members, op, "fleet "+f.Name, map[string]any{"fleet": f.Name}, awsProfile, run)
+}
+
+// showFleetHealth checks and prints the aggregated health of the named fleet, failing as
+// healthGate does when failOn is set
+func showFleetHealth(ctx context.Context, fleetName string, includeMetrics bool, awsProfile, failOn string) error {
+	services := GetServices()
+	if services == nil {
+		return fmt.Errorf("services not initialized")
+	}
+	f, err := loadFleet(fleetName)
+	if err != nil {
+		return err
+	}
+
+	services.Log(fmt.Sprintf("Checking health for fleet: %s", f.Name))
+	health := checkFleetHealth(ctx, f, includeMetrics, func(member fleet.Member) (providers.Provider, error) {
+		return services.GetProvider(member.Provider, member.Region, awsProfile)
+	})
+
+	ok, err := writeStructured(os.Stdout, health)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		printFleetHealth(os.Stdout, health)
+	}
+	return healthGate(failOn, "fleet "+f.Name, health.OverallStatus)
+}
+
+// checkFleetHealth checks every member concurrently, each bounded by monitorCheckTimeout, and
+// aggregates the results. Members that can't be checked are reported as unknown with the error.
+func checkFleetHealth(ctx context.Context, f *fleet.Fleet, includeMetrics bool, getProvider func(member fleet.Member) (providers.Provider, error)) *fleet.Health {
+	members := make([]fleet.MemberHealth, len(f.Members))
+	var wg sync.WaitGroup
+	for i, member := range f.Members {
+		wg.Add(1)
+		go func(i int, member fleet.Member) {
+			defer wg.Done()
+
+			p, err := getProvider(member)
+			if err != nil {
+				members[i] = fleet.NewMemberHealth(member, nil, nil)
+				members[i].Error = err.Error()
+				return
+			}
+
+			checkCtx, cancel := context.WithTimeout(ctx, monitorCheckTimeout)
+			defer cancel()
+			monitor := p.GetMonitor()
+			health, err := monitor.CheckClusterHealth(checkCtx, member.Cluster)
+			if err != nil {
+				members[i] = fleet.NewMemberHealth(member, nil, nil)
+				members[i].Error = err.Error()
+				return
+			}
+			var metrics *monitoring.ClusterMetrics
+			if includeMetrics {
+				metrics, err = monitor.GetClusterMetrics(checkCtx, member.Cluster)
+			}
+			members[i] = fleet.NewMemberHealth(member, health, metrics)
+			if err != nil {
+				members[i].Error = fmt.Sprintf("failed to get metrics: %v", err)
+			}
+		}(i, member)
+	}
+	wg.Wait()
+	return fleet.Aggregate(f.Name, members)
+}
+
+// printFleetHealth renders the text output of fleet status and monitor --fleet
+func printFleetHealth(w io.Writer, health *fleet.Health) {
+	fmt.Fprintf(w, "Fleet: %s\n", health.Fleet)
+	fmt.Fprintf(w, "Overall Status: %s\n", getStatusIcon(string(health.OverallStatus)))
+	fmt.Fprintf(w, "Clusters: %d healthy, %d warning, %d unhealthy, %d unknown\n",
+		health.Counts[monitoring.HealthStatusHealthy], health.Counts[monitoring.HealthStatusWarning],
+		health.Counts[monitoring.HealthStatusUnhealthy], health.Counts[monitoring.HealthStatusUnknown])
+	fmt.Fprintf(w, "Nodes: %d/%d healthy\n", health.ReadyNodes, health.Nodes)
+	if health.CPUPercentage != nil {
+		fmt.Fprintf(w, "Average CPU Usage: %.1f%%\n", *health.CPUPercentage)
+		fmt.Fprintf(w, "Average Memory Usage: %.1f%%\n", *health.MemoryPercentage)
+	}
+
+	fmt.Fprintln(w, "\n--- Clusters ---")
+	fmt.Fprintf(w, "%-20s %-10s %-15s %-10s %-8s %-6s %-6s\n", "NAME", "PROVIDER", "REGION", "HEALTH", "NODES", "CPU", "MEMORY")
+	for _, member := range health.Clusters {
+		cpu, memory := "-", "-"
+		if member.CPUPercentage != nil {
+			cpu = fmt.Sprintf("%.1f%%", *member.CPUPercentage)
+			memory = fmt.Sprintf("%.1f%%", *member.MemoryPercentage)
+		}
+		fmt.Fprintf(w, "%-20s %-10s %-15s %-10s %-8s %-6s %-6s\n",
+			member.Cluster,
+			member.Provider,
+			member.Region,
+			member.Status,
+			fmt.Sprintf("%d/%d", member.ReadyNodes, member.Nodes),
+			cpu,
+			memory)
+		if member.Error != "" {
+			fmt.Fprintf(w, "  └─ %s\n", member.Error)
+		}
+	}
+}
+
+func loadFleet(name string) (*fleet.Fleet, error) {
+	store, err := loadDefault(fleet.DefaultStorePath, fleet.LoadStore)
+	if err != nil {
+		return nil, err
+	}
+	return store.Get(name)
+}
+
+func init() {
+	rootCmd.AddCommand(fleetCmd)
+	fleetCmd.AddCommand(fleetCreateCmd)
+	fleetCmd.AddCommand(fleetAddCmd)
+	fleetCmd.AddCommand(fleetRemoveCmd)
+	fleetCmd.AddCommand(fleetDeleteCmd)
+	fleetCmd.AddCommand(fleetListCmd)
+	fleetCmd.AddCommand(fleetStatusCmd)
+	fleetCmd.AddCommand(fleetStartCmd)
+	fleetCmd.AddCommand(fleetStopCmd)
+
+	for _, cmd := range []*cobra.Command{fleetCreateCmd, fleetAddCmd} {
+		cmd.Flags().StringP("provider", "p", "local", "Provider the clusters run on (local, kind, k3d, kubeadm, capi, aws)")
+		cmd.Flags().StringP("region", "r", "", "Region the clusters run in")
+	}
+	fleetCreateCmd.Flags().String("description", "", "What the fleet is for")
+
+	fleetStatusCmd.Flags().BoolP("metrics", "m", false, "Include aggregated resource usage")
+	for _, cmd := range []*cobra.Command{fleetStatusCmd, fleetStartCmd, fleetStopCmd} {
+		cmd.Flags().String("aws-profile", "", "AWS profile to use for AWS clusters")
+	}
+}
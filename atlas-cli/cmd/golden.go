@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/golden"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/operations"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+var goldenCmd = &cobra.Command{
+	Use:   "golden",
+	Short: "Capture blessed cluster builds and create clusters from them",
+	Long: `Capture a cluster that has been tested and approved as a signed "golden" artifact, and create
+new clusters from it that are built the same way.
+
+An artifact records the cluster config (with its revision, a digest of the config), the version
+of every enabled addon and the content of each bootstrap manifest, so later changes to a
+manifest file or URL don't change the clusters created from it. Artifacts are signed with an
+Ed25519 key kept in ~/.atlas/golden/signing.key, created on first use; share signing.key.pub
+with anyone who should trust your artifacts.`,
+}
+
+var goldenCaptureCmd = &cobra.Command{
+	Use:   "capture [cluster]",
+	Short: "Record a cluster's build as a signed artifact",
+	Long: `Record a running cluster's build as a signed golden artifact. The Kubernetes version, node count
+and region are taken from the cluster and the enabled addons and their versions from the
+provider. Pass the config file the cluster was created from with --config to also record its
+other settings and bootstrap manifests, whose files and URLs are read now and stored in the
+artifact.`,
+	Example: `  atlas-cli golden capture dev --config dev.yaml
+  atlas-cli golden capture prod -p aws -r us-west-2 --config prod.yaml --name prod-2024-06 --out prod.golden.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		clusterName := args[0]
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		configFile, _ := cmd.Flags().GetString("config")
+		name, _ := cmd.Flags().GetString("name")
+		out, _ := cmd.Flags().GetString("out")
+		keyPath, _ := cmd.Flags().GetString("sign-key")
+		if name == "" {
+			name = clusterName
+		}
+		if out == "" {
+			out = name + ".golden.json"
+		}
+		if keyPath == "" {
+			var err error
+			if keyPath, err = golden.DefaultKeyPath(); err != nil {
+				return err
+			}
+		}
+
+		config := &providers.ClusterConfig{}
+		if configFile != "" {
+			var err error
+			if config, _, err = loadClusterConfig(configFile, false); err != nil {
+				return err
+			}
+			if region == "" {
+				region = config.Region
+			}
+		}
+
+		p, err := services.GetProvider(providerName, region, awsProfile)
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+		ctx := commandContext()
+		cluster, err := p.GetCluster(ctx, clusterName)
+		if err != nil {
+			return fmt.Errorf("failed to get cluster: %w", err)
+		}
+
+		// The running cluster is what was blessed, so its version and size win over the file's
+		config.Name = clusterName
+		config.Version = cluster.Version
+		config.NodeCount = cluster.NodeCount
+		if cluster.Region != "" {
+			config.Region = cluster.Region
+		}
+		if configFile == "" {
+			config.Tags = cluster.Tags
+			if len(cluster.Resources) > 0 {
+				fmt.Fprintf(os.Stderr, "Warning: cluster %s has bootstrap resources; pass --config to capture the manifests that created them\n", clusterName)
+			}
+		}
+
+		var addons []providers.Addon
+		if lister, ok := p.(providers.AddonLister); ok {
+			if addons, err = lister.ListAddons(ctx, clusterName); err != nil {
+				return fmt.Errorf("failed to list addons: %w", err)
+			}
+		}
+		manifests, err := golden.ResolveManifests(ctx, config.BootstrapManifests)
+		if err != nil {
+			return err
+		}
+
+		artifact, err := golden.NewArtifact(name, clusterName, providerName, *config, addons, manifests)
+		if err != nil {
+			return err
+		}
+		key, err := golden.LoadOrCreateKey(keyPath)
+		if err != nil {
+			return err
+		}
+		signed, err := golden.Sign(artifact, key)
+		if err != nil {
+			return err
+		}
+		if err := golden.Write(out, signed); err != nil {
+			return err
+		}
+		services.Log(fmt.Sprintf("Captured golden artifact %s from cluster %s", name, clusterName))
+
+		result := goldenCaptureResult{
+			Artifact:       name,
+			Cluster:        clusterName,
+			File:           out,
+			ConfigRevision: artifact.ConfigRevision,
+			Addons:         artifact.Addons,
+			Manifests:      len(artifact.Manifests),
+			SignedBy:       golden.Fingerprint(key.Public().(ed25519.PublicKey)),
+		}
+		if ok, err := writeStructured(os.Stdout, result); ok {
+			return err
+		}
+		printGoldenCapture(os.Stdout, result)
+		return nil
+	},
+}
+
+type goldenCaptureResult struct {
+	Artifact       string            `json:"artifact"`
+	Cluster        string            `json:"cluster"`
+	File           string            `json:"file"`
+	ConfigRevision string            `json:"configRevision"`
+	Addons         []providers.Addon `json:"addons"`
+	Manifests      int               `json:"manifests"`
+	SignedBy       string            `json:"signedBy"`
+}
+
+func printGoldenCapture(w io.Writer, result goldenCaptureResult) {
+	fmt.Fprintf(w, "Captured golden artifact '%s' from cluster '%s'\n", result.Artifact, result.Cluster)
+	fmt.Fprintf(w, "  Config revision: %s\n", result.ConfigRevision)
+	fmt.Fprintf(w, "  Addons:          %s\n", valueOrDash(strings.Join(addonSpecs(result.Addons), ", ")))
+	fmt.Fprintf(w, "  Manifests:       %d\n", result.Manifests)
+	fmt.Fprintf(w, "  Signed by:       %s\n", result.SignedBy)
+	fmt.Fprintf(w, "  Written to:      %s\n", result.File)
+}
+
+// addonSpecs renders addons as name@version, or just the name when it has no version
+func addonSpecs(addons []providers.Addon) []string {
+	specs := make([]string, len(addons))
+	for i, addon := range addons {
+		specs[i] = addon.Name
+		if addon.Version != "" {
+			specs[i] += "@" + addon.Version
+		}
+	}
+	return specs
+}
+
+var goldenCreateCmd = &cobra.Command{
+	Use:   "create [name] --from artifact",
+	Short: "Create a cluster from a golden artifact",
+	Long: `Create a cluster built exactly like the one a golden artifact was captured from: the same provider,
+region, Kubernetes version and config, the same addons at their recorded versions where the
+provider supports pinning them, and the recorded bootstrap manifests.
+
+The artifact's signature is checked first. It must be signed by your own key or by one passed
+with --public-key; an artifact that was modified after it was signed is refused.`,
+	Example: `  atlas-cli golden create dev2 --from dev.golden.json
+  atlas-cli golden create prod-eu --from prod.golden.json --public-key platform-team.pub`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		clusterName := args[0]
+		from, _ := cmd.Flags().GetString("from")
+		publicKeys, _ := cmd.Flags().GetStringArray("public-key")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+
+		artifact, err := loadGoldenArtifact(from, publicKeys)
+		if err != nil {
+			return err
+		}
+		config, err := artifact.ClusterConfig(clusterName)
+		if err != nil {
+			return err
+		}
+		ctx := commandContext()
+		if err := secretResolver.ResolveAll(ctx, config); err != nil {
+			return err
+		}
+		if awsProfile == "" && config.AWS != nil {
+			awsProfile = config.AWS.Profile
+		}
+
+		p, err := services.GetProvider(artifact.Provider, config.Region, awsProfile)
+		if err != nil {
+			return fmt.Errorf("failed to create provider: %w", err)
+		}
+		if err := checkClusterConfig(p, config, false); err != nil {
+			return err
+		}
+		reservation, err := reserveClusterPorts(p, config)
+		if err != nil {
+			return err
+		}
+		defer reservation.release()
+		if err := requireCredentials(ctx, p); err != nil {
+			return err
+		}
+
+		release, err := services.GetOperationLimiter().Acquire(ctx, &operations.Operation{
+			Type:     "create",
+			Cluster:  clusterName,
+			Provider: p.GetProviderName(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to acquire operation slot: %w", err)
+		}
+		defer release()
+
+		services.Log(fmt.Sprintf("Creating cluster %s from golden artifact %s (config %s)", clusterName, artifact.Name, artifact.ConfigRevision))
+		if _, err := p.CreateCluster(ctx, config); err != nil {
+			return fmt.Errorf("failed to create cluster: %w", err)
+		}
+		reservation.keep()
+		syncInventory(ctx, p, clusterName)
+
+		var warnings []string
+		if manager, ok := p.(providers.AddonManager); ok {
+			for _, addon := range config.Addons {
+				if err := manager.EnableAddon(ctx, clusterName, addon); err != nil {
+					warnings = append(warnings, err.Error())
+				}
+			}
+		} else if len(config.Addons) > 0 {
+			warnings = append(warnings, fmt.Sprintf("provider %s does not support enabling addons; skipped %s",
+				p.GetProviderName(), strings.Join(config.Addons, ", ")))
+		}
+		if lister, ok := p.(providers.AddonLister); ok && len(artifact.Addons) > 0 {
+			if addons, err := lister.ListAddons(ctx, clusterName); err == nil {
+				warnings = append(warnings, addonVersionDrift(artifact.Addons, addons)...)
+			}
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+
+		result := map[string]any{
+			"cluster":        clusterName,
+			"artifact":       artifact.Name,
+			"configRevision": artifact.ConfigRevision,
+			"status":         "created",
+		}
+		if len(warnings) > 0 {
+			result["warnings"] = warnings
+		}
+		if ok, err := writeStructured(os.Stdout, result); ok {
+			return err
+		}
+		fmt.Printf("Cluster '%s' created from golden artifact '%s' (config %s)\n", clusterName, artifact.Name, artifact.ConfigRevision)
+		return nil
+	},
+}
+
+// loadGoldenArtifact reads and verifies the artifact at path against the local signing key and
+// the public keys at publicKeys
+func loadGoldenArtifact(path string, publicKeys []string) (*golden.Artifact, error) {
+	signed, err := golden.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	var trusted []ed25519.PublicKey
+	if keyPath, err := golden.DefaultKeyPath(); err == nil {
+		if key, err := golden.LoadPublicKey(keyPath + ".pub"); err == nil {
+			trusted = append(trusted, key)
+		}
+	}
+	for _, path := range publicKeys {
+		key, err := golden.LoadPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		trusted = append(trusted, key)
+	}
+	artifact, err := signed.Verify(trusted)
+	if err != nil {
+		return nil, fmt.Errorf("refusing golden artifact %s: %w", path, err)
+	}
+	return artifact, nil
+}
+
+// addonVersionDrift describes each recorded addon that is missing or at a different version in
+// the new cluster
+func addonVersionDrift(recorded, actual []providers.Addon) []string {
+	current := make(map[string]providers.Addon, len(actual))
+	for _, addon := range actual {
+		current[addon.Name] = addon
+	}
+	var drift []string
+	for _, want := range recorded {
+		got, ok := current[want.Name]
+		switch {
+		case !ok || !got.Enabled:
+			drift = append(drift, fmt.Sprintf("addon %s is not enabled", want.Name))
+		case want.Version != "" && got.Version != want.Version:
+			drift = append(drift, fmt.Sprintf("addon %s is at version %s, the artifact recorded %s", want.Name, got.Version, want.Version))
+		}
+	}
+	return drift
+}
+
+func init() {
+	rootCmd.AddCommand(goldenCmd)
+	goldenCmd.AddCommand(goldenCaptureCmd, goldenCreateCmd)
+
+	goldenCaptureCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+	goldenCaptureCmd.Flags().StringP("region", "r", "", "Region the cluster runs in")
+	goldenCaptureCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	goldenCaptureCmd.Flags().StringP("config", "f", "", "Config file the cluster was created from")
+	goldenCaptureCmd.Flags().String("name", "", "Artifact name (default: the cluster name)")
+	goldenCaptureCmd.Flags().String("out", "", "File to write the artifact to (default: <name>.golden.json)")
+	goldenCaptureCmd.Flags().String("sign-key", "", "PEM-encoded PKCS#8 Ed25519 private key to sign with (default ~/.atlas/golden/signing.key, created if missing)")
+
+	goldenCreateCmd.Flags().String("from", "", "Golden artifact to create the cluster from")
+	goldenCreateCmd.Flags().StringArray("public-key", nil, "PEM-encoded Ed25519 public key to trust besides your own (repeatable)")
+	goldenCreateCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	goldenCreateCmd.MarkFlagRequired("from")
+}
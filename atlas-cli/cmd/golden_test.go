@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/fleet"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/migrate"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata/golden with current output")
+
+// assertGolden compares got with testdata/golden/<name>.golden, rewriting the file when -update is set
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".golden")
+
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run go test ./cmd -run %s -update to create it): %v", t.Name(), err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (run go test ./cmd -run %s -update to accept)\n--- got ---\n%s\n--- want ---\n%s",
+			path, t.Name(), got, want)
+	}
+}
+
+func goldenClusters() []*providers.Cluster {
+	return []*providers.Cluster{
+		{Name: "dev", Provider: "local", Region: "local", NodeCount: 1, Status: providers.ClusterStatusRunning, Health: monitoring.HealthStatusHealthy,
+			Version: "v1.31.0", Endpoint: "https://192.168.49.2:8443", CreatedAt: time.Date(2025, time.March, 4, 9, 15, 0, 0, time.UTC)},
+		{Name: "staging-eks", Provider: "aws", Region: "us-west-2", NodeCount: 3, Status: providers.ClusterStatusStopped, Health: monitoring.HealthStatusUnknown},
+	}
+}
+
+func TestPrintClusterTable_Golden(t *testing.T) {
+	tests := []struct {
+		name       string
+		withHealth bool
+		wide       bool
+	}{
+		{"cluster_list", false, false},
+		{"cluster_list_with_health", true, false},
+		{"cluster_list_wide", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			printClusterTable(&out, goldenClusters(), tt.withHealth, tt.wide)
+			assertGolden(t, tt.name, out.Bytes())
+		})
+	}
+}
+
+func TestPrintOperationHistory_Golden(t *testing.T) {
+	started := time.Date(2025, time.March, 4, 9, 15, 0, 0, time.UTC)
+	fast, slow := 850.0, 93500.0
+	history := []*logsource.OperationHistory{
+		{Ref: "op-1a2b3c4d", OperationType: logsource.OpTypeCreate, OperationStatus: logsource.OpStatusCompleted, StartedAt: started, DurationMS: &slow, UserID: "alice"},
+		{Ref: "op-5e6f7a8b", OperationType: logsource.OpTypeStop, OperationStatus: logsource.OpStatusFailed, StartedAt: started.Add(time.Hour), DurationMS: &fast, UserID: "a-very-long-user-name",
+			ErrorMessage: "minikube stop timed out", Metadata: map[string]string{"notes": "alice: laptop went to sleep\nbob: retried after reboot"}},
+		{Ref: "op-9c0d1e2f", OperationType: logsource.OpTypeStart, OperationStatus: logsource.OpStatusRunning, StartedAt: started.Add(2 * time.Hour), UserID: "bob"},
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+	}{
+		{"cluster_history", started.Add(2*time.Hour + 30*time.Second)},
+		{"cluster_history_absolute", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			printOperationHistory(&out, "dev", history, tt.now)
+			assertGolden(t, tt.name, out.Bytes())
+		})
+	}
+}
+
+func TestFormatRelativeTime(t *testing.T) {
+	now := time.Date(2025, time.March, 4, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{20 * time.Second, "just now"},
+		{5 * time.Minute, "5m ago"},
+		{3 * time.Hour, "3h ago"},
+		{47 * time.Hour, "47h ago"},
+		{72 * time.Hour, "3d ago"},
+		{45 * 24 * time.Hour, "Jan 18 2025"},
+	}
+
+	for _, tt := range tests {
+		if got := formatRelativeTime(now.Add(-tt.age), now); got != tt.want {
+			t.Errorf("formatRelativeTime(now-%v) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestPrintHealthStatus_Golden(t *testing.T) {
+	health := &monitoring.HealthStatus{
+		ClusterName:   "dev",
+		OverallStatus: monitoring.HealthStatusWarning,
+		CheckDuration: 1250 * time.Millisecond,
+		ControlPlane: &monitoring.ControlPlaneHealth{
+			APIServer:         monitoring.ComponentStatus{Status: monitoring.ComponentHealthy},
+			Scheduler:         monitoring.ComponentStatus{Status: monitoring.ComponentHealthy},
+			ControllerManager: monitoring.ComponentStatus{Status: monitoring.ComponentUnknown},
+			Etcd:              monitoring.ComponentStatus{Status: monitoring.ComponentUnhealthy},
+		},
+		Nodes: []monitoring.NodeHealth{
+			{Name: "dev", Ready: true, Version: "v1.31.0"},
+			{Name: "dev-m02", Ready: false, Version: "v1.31.0"},
+		},
+		Pods: &monitoring.PodHealth{
+			TotalPods: 12, RunningPods: 10, PendingPods: 1, FailedPods: 1,
+			CriticalPods: []monitoring.CriticalPodInfo{{Name: "coredns-abc", Namespace: "kube-system", Phase: "Pending"}},
+		},
+		Services: &monitoring.ServiceHealth{TotalServices: 4, HealthyServices: 4},
+		Warnings: []string{"1 pod pending"},
+		Errors:   []string{"etcd is unhealthy"},
+	}
+
+	var out bytes.Buffer
+	printHealthStatus(&out, health)
+	assertGolden(t, "monitor_health", out.Bytes())
+}
+
+func TestPrintMetrics_Golden(t *testing.T) {
+	metrics := &monitoring.ClusterMetrics{
+		ClusterName: "dev",
+		NodeMetrics: []monitoring.NodeMetrics{
+			{NodeName: "dev", CPUUsage: monitoring.ResourceValue{Value: "850m", Usage: 42.5}, MemoryUsage: monitoring.ResourceValue{Value: "2.1Gi", Usage: 55}},
+		},
+		PodMetrics: []monitoring.PodMetrics{
+			{PodName: "prometheus-0", Namespace: "monitoring", CPUUsage: monitoring.ResourceValue{Value: "120m"}, MemoryUsage: monitoring.ResourceValue{Value: "512Mi"}},
+			{PodName: "coredns-abc", Namespace: "kube-system", CPUUsage: monitoring.ResourceValue{Value: "5m"}, MemoryUsage: monitoring.ResourceValue{Value: "20Mi"}},
+		},
+		ResourceUsage: &monitoring.ResourceUsage{CPUPercentage: 42.5, MemoryPercentage: 55},
+	}
+
+	var out bytes.Buffer
+	printMetrics(&out, metrics)
+	assertGolden(t, "monitor_metrics", out.Bytes())
+}
+
+func TestPrintFleetHealth_Golden(t *testing.T) {
+	cpu, memory := 40.0, 60.0
+	healthy := fleet.MemberHealth{Member: fleet.Member{Cluster: "dev-1", Provider: "local"}, Status: monitoring.HealthStatusHealthy,
+		Nodes: 1, ReadyNodes: 1, CPUPercentage: &cpu, MemoryPercentage: &memory}
+	degraded := fleet.MemberHealth{Member: fleet.Member{Cluster: "dev-eks", Provider: "aws", Region: "us-west-2"}, Status: monitoring.HealthStatusWarning,
+		Nodes: 3, ReadyNodes: 2}
+	unreachable := fleet.MemberHealth{Member: fleet.Member{Cluster: "dev-2", Provider: "local"}, Status: monitoring.HealthStatusUnknown,
+		Error: "cluster dev-2 not found"}
+
+	var out bytes.Buffer
+	printFleetHealth(&out, fleet.Aggregate("dev-fleet", []fleet.MemberHealth{healthy, degraded, unreachable}))
+	assertGolden(t, "fleet_status", out.Bytes())
+}
+
+func TestPrintMigrationReport_Golden(t *testing.T) {
+	differences := []migrate.Difference{
+		{Kind: "ConfigMap", Namespace: "web", Name: "feature-flags", Status: migrate.DiffExtra},
+		{Kind: "Deployment", Namespace: "web", Name: "api", Status: migrate.DiffChanged, Fields: []string{"spec.replicas", "spec.template.spec.containers"}},
+		{Kind: "Namespace", Name: "web", Status: migrate.DiffIdentical},
+		{Kind: "PersistentVolumeClaim", Namespace: "web", Name: "uploads", Status: migrate.DiffMissing},
+	}
+
+	var out bytes.Buffer
+	printMigrationReport(&out, "old-cluster", "new-cluster", differences)
+	assertGolden(t, "migrate_workloads", out.Bytes())
+}
+
+func TestPrintValidationResult_Golden(t *testing.T) {
+	result := &providers.ValidationResult{}
+	result.Errorf("nodeCount", "node count cannot exceed 10 for local provider")
+	result.Errorf("networkConfig.extraPortMaps[0].protocol", "invalid protocol: sctp. Valid options: tcp, udp")
+	result.Warnf("instanceType", "instance type t3.large is ignored by the local provider")
+
+	var out bytes.Buffer
+	printValidationResult(&out, result)
+	assertGolden(t, "cluster_validate", out.Bytes())
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/approvals"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/export"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Work with operation history across clusters",
+	Long:  `Inspect and export the operations Atlas has run against every cluster.`,
+}
+
+var historyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export operation history as CSV or Parquet",
+	Long: `Export the operation history of every cluster, or one with --cluster, as a flat table for
+spreadsheets or analytics pipelines. Each row is one operation, including its status, duration,
+user, error, notes attached with 'operation annotate' and any recorded details.`,
+	Example: `  atlas-cli history export --format csv --since 30d > history.csv
+  atlas-cli history export --format parquet --since 2w --file history.parquet`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		format, _ := cmd.Flags().GetString("format")
+		since, _ := cmd.Flags().GetString("since")
+		clusterName, _ := cmd.Flags().GetString("cluster")
+		limit, _ := cmd.Flags().GetInt("limit")
+		outputPath, _ := cmd.Flags().GetString("file")
+
+		var cutoff time.Time
+		if since != "" {
+			age, err := parseAge(since)
+			if err != nil {
+				return err
+			}
+			cutoff = time.Now().Add(-age)
+		}
+		if format != string(export.FormatCSV) && format != string(export.FormatParquet) {
+			return fmt.Errorf("unsupported format %q (want csv or parquet)", format)
+		}
+		if format == string(export.FormatParquet) && outputPath == "" && isTerminal(os.Stdout) {
+			return fmt.Errorf("refusing to write Parquet to a terminal; use --file or redirect stdout")
+		}
+
+		provider, err := services.GetProvider("local", "local", "")
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+		histories, err := historySource(provider).GetAllClustersHistory(commandContext(), limit)
+		if err != nil {
+			return fmt.Errorf("failed to get operation history: %w", err)
+		}
+		for _, history := range histories {
+			annotateHistory(history)
+		}
+
+		table := historyTable(histories, clusterName, cutoff)
+
+		var out io.Writer = os.Stdout
+		if outputPath != "" {
+			file, err := os.Create(outputPath)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", outputPath, err)
+			}
+			defer file.Close()
+			out = file
+		}
+		if err := export.Write(out, table, export.Format(format)); err != nil {
+			return err
+		}
+		if outputPath != "" {
+			fmt.Fprintf(os.Stderr, "Exported %d operations to %s\n", len(table.Rows), outputPath)
+		}
+		return nil
+	},
+}
+
+// historySource returns p's operation history with the deployments Atlas made to its clusters
+// merged in
+func historySource(p providers.Provider) logsource.LogSource {
+	path, err := logsource.DefaultDeployLogPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; deploys are left out of the history\n", err)
+		return p.GetLogSource()
+	}
+	deploys := logsource.NewDeployLog(path)
+	return logsource.Merge(p.GetLogSource(), deploys.Source(p.GetProviderName()))
+}
+
+// recordDeploy logs a workload change to cluster so cluster history shows it next to the
+// infrastructure operations. provider is empty when only the kubeconfig context is known. A
+// failure to record is only a warning, since the deploy itself already happened.
+func recordDeploy(provider, cluster, summary string, details map[string]interface{}, started time.Time, deployErr error) {
+	completed := time.Now()
+	duration := float64(completed.Sub(started).Milliseconds())
+	op := &logsource.OperationHistory{
+		ClusterName:      cluster,
+		OperationStatus:  logsource.OpStatusCompleted,
+		StartedAt:        started,
+		CompletedAt:      &completed,
+		DurationMS:       &duration,
+		UserID:           approvals.CurrentUser(),
+		OperationDetails: details,
+		Metadata:         map[string]string{"summary": summary},
+	}
+	if deployErr != nil {
+		op.OperationStatus = logsource.OpStatusFailed
+		op.ErrorMessage = deployErr.Error()
+	}
+	path, err := logsource.DefaultDeployLogPath()
+	if err == nil {
+		err = logsource.NewDeployLog(path).Record(provider, op)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record deploy to %s: %v\n", cluster, err)
+	}
+}
+
+var historyColumns = []export.Column{
+	{Name: "cluster", Type: export.String},
+	{Name: "operation_id", Type: export.Int64},
+	{Name: "operation_ref", Type: export.String},
+	{Name: "operation_type", Type: export.String},
+	{Name: "status", Type: export.String},
+	{Name: "started_at", Type: export.Timestamp},
+	{Name: "completed_at", Type: export.Timestamp},
+	{Name: "duration_ms", Type: export.Double},
+	{Name: "user", Type: export.String},
+	{Name: "error_message", Type: export.String},
+	{Name: "details", Type: export.String},
+}
+
+// historyTable flattens per-cluster histories into one row per operation, oldest first, keeping
+// operations for clusterName (all clusters when empty) started at or after cutoff
+func historyTable(histories map[string][]*logsource.OperationHistory, clusterName string, cutoff time.Time) *export.Table {
+	var ops []*logsource.OperationHistory
+	for name, history := range histories {
+		if clusterName != "" && name != clusterName {
+			continue
+		}
+		for _, op := range history {
+			if op.StartedAt.Before(cutoff) {
+				continue
+			}
+			if op.ClusterName == "" {
+				op.ClusterName = name
+			}
+			ops = append(ops, op)
+		}
+	}
+	sort.SliceStable(ops, func(i, j int) bool {
+		if !ops[i].StartedAt.Equal(ops[j].StartedAt) {
+			return ops[i].StartedAt.Before(ops[j].StartedAt)
+		}
+		return ops[i].ClusterName < ops[j].ClusterName
+	})
+
+	table := &export.Table{Columns: historyColumns}
+	for _, op := range ops {
+		row := []any{
+			op.ClusterName,
+			int64(op.ID),
+			op.Ref,
+			string(op.OperationType),
+			string(op.OperationStatus),
+			op.StartedAt,
+			nil,
+			nil,
+			nilIfEmpty(op.UserID),
+			nilIfEmpty(op.ErrorMessage),
+			nil,
+		}
+		if op.CompletedAt != nil {
+			row[6] = *op.CompletedAt
+		}
+		if op.DurationMS != nil {
+			row[7] = *op.DurationMS
+		}
+		if details := historyDetails(op); details != nil {
+			if data, err := json.Marshal(details); err == nil {
+				row[10] = string(data)
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+// historyDetails merges an operation's details and metadata into one object, or nil when empty
+func historyDetails(op *logsource.OperationHistory) map[string]interface{} {
+	if len(op.OperationDetails) == 0 && len(op.Metadata) == 0 {
+		return nil
+	}
+	details := make(map[string]interface{}, len(op.OperationDetails)+len(op.Metadata))
+	for key, value := range op.Metadata {
+		details[key] = value
+	}
+	for key, value := range op.OperationDetails {
+		details[key] = value
+	}
+	return details
+}
+
+func nilIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// parseAge parses a look-back window such as "90m", "12h", "30d" or "2w"
+func parseAge(value string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid --since %q: want a duration like 12h, 30d or 2w", value)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid --since %q: want a duration like 12h, 30d or 2w", value)
+	}
+	return age, nil
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyExportCmd)
+
+	historyExportCmd.Flags().String("format", "csv", "Export format (csv, parquet)")
+	historyExportCmd.Flags().String("since", "30d", "Only export operations started within this window, e.g. 12h, 30d, 2w (empty for all)")
+	historyExportCmd.Flags().String("cluster", "", "Only export operations for this cluster")
+	historyExportCmd.Flags().Int("limit", 1000, "Maximum operations to read per cluster")
+	historyExportCmd.Flags().StringP("file", "f", "", "Write to this file instead of stdout")
+}
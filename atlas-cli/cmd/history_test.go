@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"90m", 90 * time.Minute, false},
+		{"30d", 30 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"d", 0, true},
+		{"-3d", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAge(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseAge(%q) = %v, %v; want %v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestHistoryTable(t *testing.T) {
+	now := time.Date(2025, time.March, 4, 12, 0, 0, 0, time.UTC)
+	duration := 1500.0
+	histories := map[string][]*logsource.OperationHistory{
+		"dev": {
+			{OperationType: logsource.OpTypeStop, OperationStatus: logsource.OpStatusFailed, StartedAt: now.Add(-time.Hour), ErrorMessage: "timed out"},
+			{OperationType: logsource.OpTypeCreate, OperationStatus: logsource.OpStatusCompleted, StartedAt: now.Add(-72 * time.Hour), DurationMS: &duration},
+		},
+		"prod": {
+			{OperationType: logsource.OpTypeScale, OperationStatus: logsource.OpStatusCompleted, StartedAt: now.Add(-2 * time.Hour),
+				Metadata: map[string]string{"nodes": "3"}},
+		},
+	}
+
+	table := historyTable(histories, "", now.Add(-24*time.Hour))
+	if len(table.Rows) != 2 {
+		t.Fatalf("historyTable() returned %d rows, want the 2 inside the window", len(table.Rows))
+	}
+	if table.Rows[0][0] != "prod" || table.Rows[0][10] != `{"nodes":"3"}` {
+		t.Errorf("first row = %v, want the older prod scale with its details", table.Rows[0])
+	}
+	if table.Rows[1][0] != "dev" || table.Rows[1][9] != "timed out" || table.Rows[1][7] != nil {
+		t.Errorf("second row = %v, want the failed dev stop with its error and no duration", table.Rows[1])
+	}
+
+	if rows := historyTable(histories, "dev", time.Time{}).Rows; len(rows) != 2 {
+		t.Errorf("historyTable(dev) returned %d rows, want 2", len(rows))
+	}
+}
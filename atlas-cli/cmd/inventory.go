@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/inventory"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Sync cluster inventory to an external system",
+	Long: `Push cluster inventory (names, versions, owners, endpoints and status) to an external system
+such as a ServiceNow CMDB or any HTTP endpoint.
+
+Sync is configured in ~/.atlas/inventory.yaml:
+
+  url: https://example.service-now.com/api/now/import/u_atlas_clusters
+  format: servicenow            # or http (default)
+  username: atlas
+  password: vault://secret/cmdb#password
+  fields:                       # inventory field <- Atlas field
+    u_name: name
+    u_version: version
+    u_owner: owner
+    u_cost_center: tags.cost-center
+
+Once configured, cluster create, delete, start, stop, scale and rename push the changed cluster
+automatically.`,
+}
+
+var inventorySyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Push clusters that changed since the last sync",
+	Long: `Push every cluster whose inventory record changed since it was last pushed, and push deletions
+for clusters that no longer exist. Deletions are only pushed for providers and regions that were
+listed successfully, so syncing one region with --region leaves the others' records alone. Run it
+on a schedule to catch changes made outside atlas-cli.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		config, err := loadInventoryConfig(commandContext())
+		if err != nil {
+			return err
+		}
+		if config == nil {
+			configPath, err := inventory.DefaultConfigPath()
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("inventory sync is not configured; create %s", configPath)
+		}
+		state, err := loadDefault(inventory.DefaultStatePath, inventory.LoadState)
+		if err != nil {
+			return err
+		}
+
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		force, _ := cmd.Flags().GetBool("force")
+		ctx := commandContext()
+
+		clusters, failures := listAllClusters(ctx, services.GetProviderFactory(), region, awsProfile)
+		for _, name := range sortedKeys(failures) {
+			fmt.Fprintf(os.Stderr, "Warning: failed to list %s clusters, skipping their deletions: %v\n", name, failures[name])
+		}
+
+		// deletions are only pushed for provider and region pairs listed in this run, so a failed
+		// listing or a sync of one region leaves every other record alone
+		listed := listedScopes(services.GetProviderFactory().GetSupportedProviders(), region, clusters, failures)
+
+		result := map[string][]string{"pushed": {}, "deleted": {}, "failed": {}}
+		seen := make(map[string]bool)
+		for _, cluster := range clusters {
+			key := inventory.Key(cluster)
+			seen[key] = true
+			if !force && !state.Changed(cluster) {
+				continue
+			}
+			if err := config.Push(ctx, inventory.EventUpserted, cluster); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				result["failed"] = append(result["failed"], key)
+				continue
+			}
+			state.Pushed(cluster)
+			result["pushed"] = append(result["pushed"], key)
+		}
+		for _, key := range state.Keys() {
+			cluster := inventory.KeyCluster(key)
+			if seen[key] || !listed[cluster.Provider+"/"+cluster.Region] {
+				continue
+			}
+			if err := config.Push(ctx, inventory.EventDeleted, cluster); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				result["failed"] = append(result["failed"], key)
+				continue
+			}
+			state.Deleted(key)
+			result["deleted"] = append(result["deleted"], key)
+		}
+		if err := state.Save(); err != nil {
+			return err
+		}
+
+		ok, err := writeStructured(os.Stdout, result)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Printf("Pushed %d changed clusters and %d deletions to %s\n", len(result["pushed"]), len(result["deleted"]), config.Format)
+		}
+		if len(result["failed"]) > 0 {
+			return fmt.Errorf("failed to push %d clusters: %s", len(result["failed"]), strings.Join(result["failed"], ", "))
+		}
+		return nil
+	},
+}
+
+// loadInventoryConfig reads the inventory config and resolves its secret references. It returns
+// nil when sync isn't configured.
+func loadInventoryConfig(ctx context.Context) (*inventory.Config, error) {
+	config, err := loadDefault(inventory.DefaultConfigPath, inventory.LoadConfig)
+	if err != nil || config == nil {
+		return nil, err
+	}
+	if err := secretResolver.ResolveAll(ctx, config); err != nil {
+		return nil, fmt.Errorf("failed to resolve inventory config: %w", err)
+	}
+	return config, nil
+}
+
+// listedScopes returns the "provider/region" pairs a listAllClusters run in region covered: the
+// region each provider that listed successfully was created with, and any region it returned
+// clusters from
+func listedScopes(providerNames []string, region string, clusters []*providers.Cluster, failures map[string]error) map[string]bool {
+	listed := make(map[string]bool)
+	for _, name := range providerNames {
+		if failures[name] != nil {
+			continue
+		}
+		if region == "" {
+			listed[name+"/"+providers.DefaultRegion(name)] = true
+		} else {
+			listed[name+"/"+region] = true
+		}
+	}
+	for _, cluster := range clusters {
+		listed[cluster.Provider+"/"+cluster.Region] = true
+	}
+	return listed
+}
+
+// inventoryMu serializes syncs made concurrently, e.g. by fleet operations, so they don't
+// overwrite each other's sync state
+var inventoryMu sync.Mutex
+
+// syncInventory pushes clusterName's current record after a lifecycle change, or its deletion
+// when the provider reports the cluster doesn't exist. Sync failures are warnings; the operation
+// itself already succeeded.
+func syncInventory(ctx context.Context, p providers.Provider, clusterName string) {
+	inventoryMu.Lock()
+	defer inventoryMu.Unlock()
+
+	config, err := loadInventoryConfig(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: inventory sync skipped: %v\n", err)
+		return
+	}
+	if config == nil {
+		return
+	}
+	state, err := loadDefault(inventory.DefaultStatePath, inventory.LoadState)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: inventory sync skipped: %v\n", err)
+		return
+	}
+
+	cluster, err := p.GetCluster(ctx, clusterName)
+	switch {
+	case err == nil:
+		if cluster.Provider == "" {
+			cluster.Provider = p.GetProviderName()
+		}
+		if err := config.Push(ctx, inventory.EventUpserted, cluster); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			return
+		}
+		state.Pushed(cluster)
+	case errors.Is(err, providers.ErrClusterNotFound):
+		// the cluster is gone, so its region is only known from what was pushed before
+		for _, key := range state.Keys() {
+			cluster := inventory.KeyCluster(key)
+			if cluster.Provider != p.GetProviderName() || cluster.Name != clusterName {
+				continue
+			}
+			if err := config.Push(ctx, inventory.EventDeleted, cluster); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				return
+			}
+			state.Deleted(key)
+		}
+	default:
+		// a failed lookup says nothing about whether the cluster still exists
+		fmt.Fprintf(os.Stderr, "Warning: inventory sync of cluster %s skipped: %v\n", clusterName, err)
+		return
+	}
+	if err := state.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(inventoryCmd)
+	inventoryCmd.AddCommand(inventorySyncCmd)
+
+	inventorySyncCmd.Flags().StringP("region", "r", "", "Region to list clusters from")
+	inventorySyncCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	inventorySyncCmd.Flags().Bool("force", false, "Push every cluster, not only those that changed")
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/inventory"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+)
+
+// unreachableProvider fails every cluster lookup, as a provider with expired credentials would
+type unreachableProvider struct {
+	providers.Provider
+}
+
+func (unreachableProvider) GetCluster(ctx context.Context, name string) (*providers.Cluster, error) {
+	return nil, errors.New("credentials expired")
+}
+
+func TestSyncInventory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("ATLAS_TEST_CMDB_TOKEN", "s3cret-token")
+
+	var mu sync.Mutex
+	var events []string
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Event   string         `json:"event"`
+			Cluster map[string]any `json:"cluster"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		events = append(events, body.Event+" "+body.Cluster["name"].(string))
+		auth = r.Header.Get("Authorization")
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	p := providers.NewFakeProvider(providers.FakeOptions{Name: "local", StatePath: filepath.Join(home, "fake.json")})
+	if _, err := p.CreateCluster(ctx, &providers.ClusterConfig{Name: "dev", NodeCount: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// without a config nothing is pushed
+	syncInventory(ctx, p, "dev")
+	if len(events) != 0 {
+		t.Fatalf("syncInventory() without a config pushed %v", events)
+	}
+
+	config := "url: " + server.URL + "\ntoken: env://ATLAS_TEST_CMDB_TOKEN\n"
+	configPath, err := inventory.DefaultConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	syncInventory(ctx, p, "dev")
+	// a failed lookup isn't taken as the cluster being gone
+	syncInventory(ctx, unreachableProvider{p}, "dev")
+	if err := p.DeleteCluster(ctx, "dev"); err != nil {
+		t.Fatal(err)
+	}
+	syncInventory(ctx, p, "dev")
+
+	want := []string{"upserted dev", "deleted dev"}
+	if len(events) != len(want) || events[0] != want[0] || events[1] != want[1] {
+		t.Errorf("pushed events = %v, want %v", events, want)
+	}
+	if auth != "Bearer s3cret-token" {
+		t.Errorf("Authorization = %q, want the resolved token", auth)
+	}
+}
+
+func TestListedScopes(t *testing.T) {
+	names := []string{"local", "aws", "kubeadm"}
+	clusters := []*providers.Cluster{{Name: "dev", Provider: "local", Region: "local"}}
+	failures := map[string]error{"kubeadm": errors.New("unreachable")}
+
+	listed := listedScopes(names, "", clusters, failures)
+	for scope, want := range map[string]bool{
+		"local/local":     true,
+		"aws/us-west-2":   true,
+		"aws/eu-west-1":   false,
+		"kubeadm/homelab": false,
+	} {
+		if listed[scope] != want {
+			t.Errorf("listedScopes() without a region: %s = %v, want %v", scope, listed[scope], want)
+		}
+	}
+
+	listed = listedScopes(names, "eu-west-1", clusters, failures)
+	for scope, want := range map[string]bool{
+		"local/local":   true,
+		"aws/eu-west-1": true,
+		"aws/us-west-2": false,
+	} {
+		if listed[scope] != want {
+			t.Errorf("listedScopes() in eu-west-1: %s = %v, want %v", scope, listed[scope], want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/kubeversions"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+var clusterVersionsCmd = &cobra.Command{
+	Use:   "versions",
+	Short: "Show Kubernetes version support windows",
+	Long: `Show when each Kubernetes minor release reaches end of life, as used by cluster list, status
+and describe to warn about clusters running unsupported or soon unsupported versions. The table
+is built into Atlas; run 'cluster versions refresh' to download a newer one.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+		table := versionTable()
+		if ok, err := writeStructured(os.Stdout, table); ok {
+			return err
+		}
+		printVersionTable(table, time.Now())
+		return nil
+	},
+}
+
+var clusterVersionsRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Download the latest Kubernetes version support windows",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+		url, _ := cmd.Flags().GetString("url")
+		cachePath, err := kubeversions.DefaultCachePath()
+		if err != nil {
+			return err
+		}
+		table, err := kubeversions.Refresh(commandContext(), url, cachePath)
+		if err != nil {
+			return err
+		}
+		if ok, err := writeStructured(os.Stdout, table); ok {
+			return err
+		}
+		fmt.Printf("Saved %d Kubernetes releases to %s\n", len(table.Releases), table.Source)
+		return nil
+	},
+}
+
+// versionTable returns the refreshed support table, falling back to the embedded one when the
+// saved table cannot be read
+func versionTable() *kubeversions.Table {
+	table, err := loadDefault(kubeversions.DefaultCachePath, kubeversions.Load)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; using the built-in table\n", err)
+		return kubeversions.Embedded()
+	}
+	return table
+}
+
+// checkVersionSupport sets VersionSupport on each cluster and returns a warning for each one
+// running an end-of-life or soon end-of-life version
+func checkVersionSupport(clusters []*providers.Cluster, now time.Time) []string {
+	if len(clusters) == 0 {
+		return nil
+	}
+	table := versionTable()
+	var warnings []string
+	for _, cluster := range clusters {
+		support := table.Check(cluster.Version, now)
+		cluster.VersionSupport = &support
+		if warning := support.Warning(cluster.Name); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
+
+// warnVersionSupport checks clusters and prints their warnings to stderr
+func warnVersionSupport(clusters ...*providers.Cluster) {
+	for _, warning := range checkVersionSupport(clusters, time.Now()) {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+}
+
+// versionSupportNote returns a suffix for a printed version that is past or near its end of life
+func versionSupportNote(support *kubeversions.Support) string {
+	if support == nil {
+		return ""
+	}
+	switch support.Status {
+	case kubeversions.StatusEOL:
+		return fmt.Sprintf(" (end of life since %s)", support.EOL)
+	case kubeversions.StatusEndingSoon:
+		return fmt.Sprintf(" (end of life on %s)", support.EOL)
+	}
+	return ""
+}
+
+func printVersionTable(table *kubeversions.Table, now time.Time) {
+	t := newTable("VERSION", "RELEASED", "END OF LIFE", "STATUS")
+	for _, release := range table.Releases {
+		support := table.Check(release.Cycle, now)
+		t.addRow(release.Cycle, valueOrDash(release.ReleaseDate), valueOrDash(release.EOL), support.Status)
+	}
+	t.render(os.Stdout)
+	fmt.Printf("\nSource: %s\n", table.Source)
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterVersionsCmd)
+	clusterVersionsCmd.AddCommand(clusterVersionsRefreshCmd)
+
+	clusterVersionsRefreshCmd.Flags().String("url", kubeversions.DefaultURL, "URL of the support table, in endoflife.date's format")
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Query logs collected by a cluster's logging stack",
+}
+
+var logsQueryCmd = &cobra.Command{
+	Use:   "query [cluster] [selector]",
+	Short: "Query container logs by pod label",
+	Long: `Query the container logs collected by the logging stack installed when the cluster was created
+with resourceConfig.monitoring.logAggregation.enabled: Loki on local, kind and k3d clusters, and
+CloudWatch Logs (or Loki, with --backend loki) on AWS.
+
+The selector matches pod labels as label=value terms separated by commas. Without a selector
+every container's logs are returned.`,
+	Example: `  atlas-cli logs query dev 'app=foo'
+  atlas-cli logs query prod 'app=api,tier=web' -p aws --since 30m --limit 500`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		clusterName := args[0]
+		query := providers.LogQuery{}
+		if len(args) == 2 {
+			selector, err := providers.ParseLogSelector(args[1])
+			if err != nil {
+				return err
+			}
+			query.Selector = selector
+		}
+		query.Since, _ = cmd.Flags().GetDuration("since")
+		query.Limit, _ = cmd.Flags().GetInt("limit")
+		query.Backend, _ = cmd.Flags().GetString("backend")
+		if query.Since <= 0 {
+			return fmt.Errorf("--since must be positive")
+		}
+		if query.Limit < 1 {
+			return fmt.Errorf("--limit must be at least 1")
+		}
+
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		p, err := services.GetProvider(providerName, region, awsProfile)
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+		querier, ok := p.(providers.LogQuerier)
+		if !ok {
+			return fmt.Errorf("provider %s does not support log queries", p.GetProviderName())
+		}
+
+		services.Log(fmt.Sprintf("Querying logs for cluster: %s", clusterName))
+		entries, err := querier.QueryLogs(commandContext(), clusterName, query)
+		if err != nil {
+			return err
+		}
+
+		if entries == nil {
+			entries = []providers.LogEntry{}
+		}
+		if ok, err := writeStructured(os.Stdout, entries); ok {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Printf("No log lines matched in the last %s\n", query.Since)
+			return nil
+		}
+		for _, entry := range entries {
+			fmt.Printf("%s %s %s\n", entry.Time.Local().Format(time.RFC3339), logSource(entry.Labels), entry.Line)
+		}
+		return nil
+	},
+}
+
+// logSource names where a line came from: its namespace/pod, or its labels when the backend
+// doesn't report pods
+func logSource(labels map[string]string) string {
+	if pod := labels["pod"]; pod != "" {
+		if namespace := labels["namespace"]; namespace != "" {
+			return namespace + "/" + pod
+		}
+		return pod
+	}
+	terms := make([]string, 0, len(labels))
+	for key, value := range labels {
+		terms = append(terms, key+"="+value)
+	}
+	sort.Strings(terms)
+	return "{" + strings.Join(terms, ",") + "}"
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	logsCmd.AddCommand(logsQueryCmd)
+
+	logsQueryCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+	logsQueryCmd.Flags().StringP("region", "r", "", "Region the cluster runs in")
+	logsQueryCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	logsQueryCmd.Flags().Duration("since", time.Hour, "How far back to search")
+	logsQueryCmd.Flags().Int("limit", 100, "Maximum number of lines to return")
+	logsQueryCmd.Flags().String("backend", "", "Logging stack to query (loki, cloudwatch); defaults to the provider's")
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/maintenance"
+	"github.com/spf13/cobra"
+)
+
+var clusterMaintenanceCmd = &cobra.Command{
+	Use:   "maintenance",
+	Short: "Manage cluster maintenance windows",
+	Long: `Set the recurring window in which disruptive actions on a cluster are expected. Stopping,
+scaling, renaming or deleting a cluster outside its window prints a warning.`,
+}
+
+var clusterMaintenanceSetCmd = &cobra.Command{
+	Use:     "set [name]",
+	Short:   "Set a cluster's maintenance window",
+	Example: `  atlas-cli cluster maintenance set prod --days sat,sun --start 02:00 --duration 4h --timezone Europe/Berlin`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		window := maintenance.Window{}
+		window.Days, _ = cmd.Flags().GetStringSlice("days")
+		window.Start, _ = cmd.Flags().GetString("start")
+		window.Duration, _ = cmd.Flags().GetString("duration")
+		window.Timezone, _ = cmd.Flags().GetString("timezone")
+
+		store, err := loadDefault(maintenance.DefaultStorePath, maintenance.LoadStore)
+		if err != nil {
+			return err
+		}
+		if err := store.Set(args[0], window); err != nil {
+			return err
+		}
+		if err := store.Save(); err != nil {
+			return err
+		}
+		window, _ = store.Get(args[0])
+		fmt.Printf("Maintenance window for '%s' set to %s\n", args[0], window)
+		return nil
+	},
+}
+
+var clusterMaintenanceShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show a cluster's maintenance window and whether it is open",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		store, err := loadDefault(maintenance.DefaultStorePath, maintenance.LoadStore)
+		if err != nil {
+			return err
+		}
+		window, ok := store.Get(args[0])
+		if !ok {
+			return fmt.Errorf("cluster %s has no maintenance window", args[0])
+		}
+		now := time.Now()
+		open, err := window.Contains(now)
+		if err != nil {
+			return err
+		}
+		next, err := window.Next(now)
+		if err != nil {
+			return err
+		}
+
+		result := map[string]any{
+			"name":      args[0],
+			"window":    window,
+			"open":      open,
+			"next_open": next,
+		}
+		if ok, err := writeStructured(os.Stdout, result); ok {
+			return err
+		}
+		printMaintenanceWindow(os.Stdout, args[0], window, open, next, now)
+		return nil
+	},
+}
+
+var clusterMaintenanceClearCmd = &cobra.Command{
+	Use:   "clear [name]",
+	Short: "Remove a cluster's maintenance window",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := loadDefault(maintenance.DefaultStorePath, maintenance.LoadStore)
+		if err != nil {
+			return err
+		}
+		store.Clear(args[0])
+		if err := store.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Maintenance window for '%s' cleared\n", args[0])
+		return nil
+	},
+}
+
+func printMaintenanceWindow(w io.Writer, clusterName string, window maintenance.Window, open bool, next, now time.Time) {
+	fmt.Fprintf(w, "Cluster:   %s\n", clusterName)
+	fmt.Fprintf(w, "Window:    %s\n", window)
+	if open {
+		fmt.Fprintln(w, "Status:    open")
+	} else {
+		fmt.Fprintln(w, "Status:    closed")
+	}
+	fmt.Fprintf(w, "Next open: %s (in %s)\n", next.Format(time.RFC3339), formatWait(next.Sub(now)))
+}
+
+// formatWait renders a wait such as "2d 3h" or "45m"
+func formatWait(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days, hours, minutes := int(d.Hours())/24, int(d.Hours())%24, int(d.Minutes())%60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// warnOutsideMaintenanceWindow prints a warning when action on clusterName is attempted while
+// the cluster's maintenance window is closed. Clusters without a window never warn.
+func warnOutsideMaintenanceWindow(clusterName, action string) {
+	store, err := loadDefault(maintenance.DefaultStorePath, maintenance.LoadStore)
+	if err != nil {
+		return
+	}
+	if window, ok := store.Get(clusterName); ok {
+		if warning := maintenanceWarning(window, clusterName, action, time.Now()); warning != "" {
+			fmt.Fprintln(os.Stderr, warning)
+		}
+	}
+}
+
+// maintenanceWarning returns the warning for action on clusterName at now, or "" while the window is open
+func maintenanceWarning(window maintenance.Window, clusterName, action string, now time.Time) string {
+	if open, err := window.Contains(now); err != nil || open {
+		return ""
+	}
+	next, err := window.Next(now)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("Warning: %s cluster '%s' outside its maintenance window (%s); the window next opens in %s",
+		action, clusterName, window, formatWait(next.Sub(now)))
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterMaintenanceCmd)
+	clusterMaintenanceCmd.AddCommand(clusterMaintenanceSetCmd)
+	clusterMaintenanceCmd.AddCommand(clusterMaintenanceShowCmd)
+	clusterMaintenanceCmd.AddCommand(clusterMaintenanceClearCmd)
+
+	clusterMaintenanceSetCmd.Flags().StringSlice("days", nil, "Weekdays the window opens on, e.g. sat,sun (default every day)")
+	clusterMaintenanceSetCmd.Flags().String("start", "", "Time the window opens, as HH:MM")
+	clusterMaintenanceSetCmd.Flags().String("duration", "4h", "How long the window stays open, e.g. 90m or 4h")
+	clusterMaintenanceSetCmd.Flags().String("timezone", "UTC", "IANA timezone the start time is in")
+	clusterMaintenanceSetCmd.MarkFlagRequired("start")
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/maintenance"
+)
+
+func TestMaintenanceWarning(t *testing.T) {
+	window := maintenance.Window{Days: []string{"sat"}, Start: "02:00", Duration: "4h"}
+	saturday := time.Date(2025, time.May, 3, 0, 0, 0, 0, time.UTC)
+
+	if got := maintenanceWarning(window, "prod", "stopping", saturday.Add(3*time.Hour)); got != "" {
+		t.Errorf("maintenanceWarning() inside the window = %q, want none", got)
+	}
+	got := maintenanceWarning(window, "prod", "stopping", saturday)
+	for _, want := range []string{"stopping cluster 'prod'", "sat 02:00 for 4h (UTC)", "next opens in 2h 0m"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("maintenanceWarning() = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestFormatWait(t *testing.T) {
+	tests := []struct {
+		wait time.Duration
+		want string
+	}{
+		{45 * time.Minute, "45m"},
+		{3*time.Hour + 10*time.Minute, "3h 10m"},
+		{50 * time.Hour, "2d 2h"},
+	}
+	for _, tt := range tests {
+		if got := formatWait(tt.wait); got != tt.want {
+			t.Errorf("formatWait(%v) = %q, want %q", tt.wait, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/export"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/spf13/cobra"
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Query resource metrics recorded while monitoring",
+	Long: `Query the CPU, memory and storage usage recorded each time 'monitor --watch' or
+'monitor serve' collects a cluster's metrics. Samples are kept for 7 days under ~/.atlas/metrics.`,
+}
+
+var metricsHistoryCmd = &cobra.Command{
+	Use:   "history [cluster]",
+	Short: "Show CPU and memory usage over time",
+	Long: `Show a cluster's recorded resource usage, or every cluster's when no name is given, averaged
+into buckets of --resolution. Only clusters monitored with metrics collection enabled have
+history.`,
+	Example: `  atlas-cli metrics history dev --since 1h --resolution 1m
+  atlas-cli metrics history dev --since 7d --resolution 1h --format csv > dev.csv
+  atlas-cli -o json metrics history --since 30m`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, _ := cmd.Flags().GetString("since")
+		resolution, _ := cmd.Flags().GetDuration("resolution")
+		providerName, _ := cmd.Flags().GetString("provider")
+		format, _ := cmd.Flags().GetString("format")
+		if format != "table" && format != string(export.FormatCSV) {
+			return fmt.Errorf("unsupported format %q (want table or csv)", format)
+		}
+		if resolution < 0 {
+			return fmt.Errorf("--resolution must not be negative")
+		}
+		age, err := parseAge(since)
+		if err != nil {
+			return err
+		}
+		var clusterName string
+		if len(args) > 0 {
+			clusterName = args[0]
+		}
+
+		historyDir, err := monitoring.DefaultMetricsHistoryDir()
+		if err != nil {
+			return err
+		}
+		history := monitoring.NewMetricsHistory(historyDir)
+		samples, err := history.Query(providerName, clusterName, time.Now().Add(-age))
+		if err != nil {
+			return err
+		}
+		samples = monitoring.Downsample(samples, resolution)
+
+		if format == string(export.FormatCSV) {
+			return export.WriteCSV(os.Stdout, metricsHistoryTable(samples))
+		}
+		if samples == nil {
+			samples = []monitoring.MetricsSample{}
+		}
+		if ok, err := writeStructured(os.Stdout, samples); ok {
+			return err
+		}
+		if len(samples) == 0 {
+			if clusterName != "" {
+				fmt.Printf("No metrics recorded for %s in the last %s\n", clusterName, since)
+			} else {
+				fmt.Printf("No metrics recorded in the last %s\n", since)
+			}
+			fmt.Println("Metrics are recorded by 'monitor --watch --metrics' and 'monitor serve'.")
+			return nil
+		}
+		printMetricsHistory(os.Stdout, samples)
+		return nil
+	},
+}
+
+var metricsHistoryColumns = []export.Column{
+	{Name: "time", Type: export.Timestamp},
+	{Name: "provider", Type: export.String},
+	{Name: "cluster", Type: export.String},
+	{Name: "cpu_percent", Type: export.Double},
+	{Name: "memory_percent", Type: export.Double},
+	{Name: "storage_percent", Type: export.Double},
+	{Name: "nodes", Type: export.Int64},
+	{Name: "samples", Type: export.Int64},
+}
+
+// metricsHistoryTable flattens samples into rows for CSV export
+func metricsHistoryTable(samples []monitoring.MetricsSample) *export.Table {
+	table := &export.Table{Columns: metricsHistoryColumns}
+	for _, sample := range samples {
+		table.Rows = append(table.Rows, []any{
+			sample.Time,
+			sample.Provider,
+			sample.Cluster,
+			sample.CPUPercent,
+			sample.MemoryPercent,
+			sample.StoragePercent,
+			int64(sample.Nodes),
+			int64(sample.Samples),
+		})
+	}
+	return table
+}
+
+// printMetricsHistory writes samples as a table in local time, leaving out the cluster columns
+// when every sample is from the same cluster
+func printMetricsHistory(w io.Writer, samples []monitoring.MetricsSample) {
+	multiple := false
+	for _, sample := range samples[1:] {
+		if sample.Cluster != samples[0].Cluster || sample.Provider != samples[0].Provider {
+			multiple = true
+			break
+		}
+	}
+
+	headers := []string{"TIME", "CPU", "MEMORY", "NODES", "SAMPLES"}
+	if multiple {
+		headers = append([]string{"TIME", "CLUSTER", "PROVIDER"}, headers[1:]...)
+	}
+	t := newTable(headers...)
+	for _, sample := range samples {
+		values := []any{
+			sample.Time.Local().Format("2006-01-02 15:04:05"),
+			fmt.Sprintf("%.1f%%", sample.CPUPercent),
+			fmt.Sprintf("%.1f%%", sample.MemoryPercent),
+			sample.Nodes,
+			sample.Samples,
+		}
+		if multiple {
+			values = append([]any{values[0], sample.Cluster, sample.Provider}, values[1:]...)
+		}
+		t.addRow(values...)
+	}
+	t.render(w)
+}
+
+// historyRecorder stores the metrics a monitoring loop collects in the metrics history. A
+// failure to record is logged rather than interrupting monitoring.
+type historyRecorder struct {
+	history  *monitoring.MetricsHistory
+	provider string
+}
+
+func (r *historyRecorder) record(cluster string, m *monitoring.ClusterMetrics) {
+	if r == nil {
+		return
+	}
+	recordMetricsHistory(r.history, r.provider, cluster, m)
+}
+
+func recordMetricsHistory(history *monitoring.MetricsHistory, provider, cluster string, m *monitoring.ClusterMetrics) {
+	if err := history.Record(provider, cluster, m); err != nil {
+		GetServices().Log(fmt.Sprintf("Failed to record metrics history: %v", err))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(metricsCmd)
+	metricsCmd.AddCommand(metricsHistoryCmd)
+
+	metricsHistoryCmd.Flags().String("since", "1h", "How far back to show, e.g. 30m, 12h, 7d")
+	metricsHistoryCmd.Flags().Duration("resolution", time.Minute, "Average samples into buckets of this width (0 for raw samples)")
+	metricsHistoryCmd.Flags().StringP("provider", "p", "", "Only show clusters of this provider")
+	metricsHistoryCmd.Flags().String("format", "table", "Output format when --output is text (table, csv)")
+}
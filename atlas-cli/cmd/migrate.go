@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/migrate"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move workloads between clusters",
+	Long:  `Copy workloads from one cluster to another, for example when replacing a cluster during an upgrade.`,
+}
+
+var migrateWorkloadsCmd = &cobra.Command{
+	Use:   "workloads",
+	Short: "Copy namespaces from one cluster to another and report differences",
+	Long: `Snapshot the selected namespaces on the source cluster, restore them on the target and report
+how the target differs from the source afterwards.
+
+--from and --to are kubeconfig contexts; for minikube clusters the context is the cluster name.
+
+The native method exports objects with kubectl, strips the fields the source cluster assigned
+(uids, resource versions, cluster IPs, status) and applies them to the target. Persistent volume
+data is not copied. The velero method runs a Velero backup and restore instead, which also copies
+volume data but requires Velero on both clusters with a shared backup storage location.`,
+	Example: `  atlas-cli migrate workloads --from old-cluster --to new-cluster --namespaces web,api
+  atlas-cli migrate workloads --from old-cluster --to new-cluster --namespaces web --dry-run
+  atlas-cli migrate workloads --from old-cluster --to new-cluster --namespaces web --method velero`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		namespaces, _ := cmd.Flags().GetStringSlice("namespaces")
+		method, _ := cmd.Flags().GetString("method")
+		kinds, _ := cmd.Flags().GetStringSlice("kinds")
+		snapshotPath, _ := cmd.Flags().GetString("snapshot")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		if from == to {
+			return fmt.Errorf("--from and --to must be different clusters")
+		}
+		if len(namespaces) == 0 {
+			return fmt.Errorf("--namespaces is required")
+		}
+		if method != "native" && method != "velero" {
+			return fmt.Errorf("unsupported method %q (want native or velero)", method)
+		}
+
+		ctx := subprocess.WithOperation(commandContext(), "update")
+
+		services.Log(fmt.Sprintf("Exporting namespaces %s from %s", strings.Join(namespaces, ", "), from))
+		snapshot, err := migrate.Export(ctx, migrate.RunKubectl, from, namespaces, kinds)
+		if err != nil {
+			return err
+		}
+		if snapshotPath != "" {
+			data, err := json.MarshalIndent(snapshot, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal snapshot: %w", err)
+			}
+			if err := os.WriteFile(snapshotPath, data, 0600); err != nil {
+				return fmt.Errorf("failed to write snapshot: %w", err)
+			}
+			services.Log(fmt.Sprintf("Wrote snapshot of %d objects to %s", len(snapshot.Objects), snapshotPath))
+		}
+
+		if !dryRun {
+			started := time.Now()
+			switch method {
+			case "velero":
+				backupName := fmt.Sprintf("atlas-migrate-%s", time.Now().UTC().Format("20060102-150405"))
+				services.Log(fmt.Sprintf("Migrating with Velero backup %s", backupName))
+				err = migrate.VeleroMigrate(ctx, migrate.RunVelero, backupName, from, to, namespaces)
+			default:
+				services.Log(fmt.Sprintf("Restoring %d objects to %s", len(snapshot.Objects), to))
+				err = migrate.Restore(ctx, migrate.RunKubectl, to, snapshot)
+			}
+			// --to is a kubeconfig context, which for minikube clusters is the cluster name
+			recordDeploy("", to, fmt.Sprintf("migrated namespaces %s from %s (%s)", strings.Join(namespaces, ", "), from, method),
+				map[string]interface{}{"kind": "migrate", "from": from, "method": method, "namespaces": namespaces}, started, err)
+			if err != nil {
+				return err
+			}
+		}
+
+		target, err := migrate.ExportExisting(ctx, migrate.RunKubectl, to, namespaces, kinds)
+		if err != nil {
+			return fmt.Errorf("failed to read back %s: %w", to, err)
+		}
+		differences := migrate.Diff(snapshot.Objects, target.Objects)
+
+		result := map[string]any{
+			"from":        from,
+			"to":          to,
+			"method":      method,
+			"dry_run":     dryRun,
+			"namespaces":  namespaces,
+			"summary":     migrate.Summary(differences),
+			"differences": differences,
+		}
+		ok, err := writeStructured(os.Stdout, result)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if dryRun {
+				fmt.Printf("Dry run: nothing was applied to %s\n\n", to)
+			}
+			printMigrationReport(os.Stdout, from, to, differences)
+		}
+
+		if counts := migrate.Summary(differences); !dryRun && (counts[migrate.DiffMissing] > 0 || counts[migrate.DiffChanged] > 0) {
+			return fmt.Errorf("%d objects missing and %d changed on %s after migration", counts[migrate.DiffMissing], counts[migrate.DiffChanged], to)
+		}
+		return nil
+	},
+}
+
+// printMigrationReport renders the per-object comparison of a migration
+func printMigrationReport(w io.Writer, from, to string, differences []migrate.Difference) {
+	counts := migrate.Summary(differences)
+	fmt.Fprintf(w, "Migration %s -> %s: %d identical, %d changed, %d missing, %d extra\n\n", from, to,
+		counts[migrate.DiffIdentical], counts[migrate.DiffChanged], counts[migrate.DiffMissing], counts[migrate.DiffExtra])
+	fmt.Fprintf(w, "%-24s %-15s %-30s %-10s\n", "KIND", "NAMESPACE", "NAME", "RESULT")
+	fmt.Fprintf(w, "%-24s %-15s %-30s %-10s\n", "----", "---------", "----", "------")
+	for _, difference := range differences {
+		name := difference.Name
+		if !noTruncate {
+			name = truncateString(name, 30)
+		}
+		fmt.Fprintf(w, "%-24s %-15s %-30s %-10s\n",
+			difference.Kind,
+			difference.Namespace,
+			name,
+			difference.Status)
+		if len(difference.Fields) > 0 {
+			fmt.Fprintf(w, "  └─ %s\n", strings.Join(difference.Fields, ", "))
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(migrateWorkloadsCmd)
+
+	migrateWorkloadsCmd.Flags().String("from", "", "Kubeconfig context of the source cluster")
+	migrateWorkloadsCmd.Flags().String("to", "", "Kubeconfig context of the target cluster")
+	migrateWorkloadsCmd.Flags().StringSlice("namespaces", nil, "Namespaces to migrate (comma-separated)")
+	migrateWorkloadsCmd.Flags().String("method", "native", "Migration method (native, velero)")
+	migrateWorkloadsCmd.Flags().StringSlice("kinds", migrate.DefaultKinds, "Resource kinds copied by the native method")
+	migrateWorkloadsCmd.Flags().String("snapshot", "", "Also write the exported snapshot to this file")
+	migrateWorkloadsCmd.Flags().Bool("dry-run", false, "Export and compare without changing the target")
+	migrateWorkloadsCmd.MarkFlagRequired("from")
+	migrateWorkloadsCmd.MarkFlagRequired("to")
+	migrateWorkloadsCmd.MarkFlagRequired("namespaces")
+}
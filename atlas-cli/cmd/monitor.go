@@ -2,26 +2,80 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/metrics"
 	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/operations"
 	"github.com/spf13/cobra"
 )
 
+// monitorCheckTimeout bounds a single health check, including each refresh in watch mode
+const monitorCheckTimeout = 30 * time.Second
+
 var monitorCmd = &cobra.Command{
-	Use:   "monitor [cluster-name]",
+	Use:   "monitor [cluster-name...]",
 	Short: "Monitor cluster health and metrics",
-	Long:  `Check cluster health status and collect performance metrics.`,
-	Args:  cobra.MaximumNArgs(1),
+	Long: `Check cluster health status and collect performance metrics.
+
+With --fail-on, the command exits with status 3 when the cluster's (or fleet's) overall status
+is at least as bad as the threshold, so a CI pipeline can gate a deployment on cluster health
+without parsing the output. A health check that can't run exits with status 1.
+
+Several cluster names, or --all for every cluster of the provider, are checked concurrently and
+shown as a summary table followed by each cluster's details. --fail-on then applies to the worst
+of them.
+
+The health check also reads the certificate the API server serves and, for minikube and kind
+clusters, the certificates kubeadm issued on the control plane. Certificates that expire within
+--cert-warning are warnings and expired ones are errors.`,
+	Example: `  atlas-cli monitor dev
+  atlas-cli monitor dev staging --metrics
+  atlas-cli monitor --all --fail-on unhealthy
+  atlas-cli monitor dev --cert-warning 60d
+  atlas-cli monitor prod -p aws -r us-west-2 --fail-on warning
+  atlas-cli monitor --fleet production --fail-on unhealthy -o json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		services := GetServices()
 		if services == nil {
 			return fmt.Errorf("services not initialized")
 		}
 
+		all, _ := cmd.Flags().GetBool("all")
+		failOn, _ := cmd.Flags().GetString("fail-on")
+		if err := validateFailOn(failOn); err != nil {
+			return err
+		}
+		ctx, err := certificateWarningContext(commandContext(), cmd)
+		if err != nil {
+			return err
+		}
+		if watch, _ := cmd.Flags().GetBool("watch"); watch && failOn != "" {
+			return fmt.Errorf("--fail-on cannot be combined with --watch")
+		}
+
+		if fleetName, _ := cmd.Flags().GetString("fleet"); fleetName != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("--fleet cannot be combined with a cluster name")
+			}
+			if all {
+				return fmt.Errorf("--fleet cannot be combined with --all")
+			}
+			if watch, _ := cmd.Flags().GetBool("watch"); watch {
+				return fmt.Errorf("--fleet does not support --watch")
+			}
+			includeMetrics, _ := cmd.Flags().GetBool("metrics")
+			awsProfile, _ := cmd.Flags().GetString("aws-profile")
+			return showFleetHealth(ctx, fleetName, includeMetrics, awsProfile, failOn)
+		}
+
 		providerName, _ := cmd.Flags().GetString("provider")
 		region, _ := cmd.Flags().GetString("region") 
 		awsProfile, _ := cmd.Flags().GetString("aws-profile")
@@ -36,27 +90,122 @@ var monitorCmd = &cobra.Command{
 		}
 		monitor := provider.GetMonitor()
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
-		if len(args) == 0 {
+		if all && len(args) > 0 {
+			return fmt.Errorf("--all cannot be combined with cluster names")
+		}
+		if len(args) == 0 && !all {
 			return fmt.Errorf("cluster name is required")
 		}
 
-		clusterName := args[0]
-
 		includeMetrics, _ := cmd.Flags().GetBool("metrics")
 		watch, _ := cmd.Flags().GetBool("watch")
+		if all || len(args) > 1 {
+			if watch {
+				return fmt.Errorf("--watch monitors a single cluster")
+			}
+			return monitorClusters(ctx, provider, region, args, all, includeMetrics, failOn)
+		}
+
+		clusterName := args[0]
 		
 		if watch {
-			return monitorWatchMode(ctx, monitor, clusterName, includeMetrics)
+			registry := metrics.NewRegistry()
+			metricsAddr, _ := cmd.Flags().GetString("metrics-addr")
+			enablePprof, _ := cmd.Flags().GetBool("enable-pprof")
+			if enablePprof && metricsAddr == "" {
+				return fmt.Errorf("--enable-pprof requires --metrics-addr")
+			}
+			heartbeatURL, _ := cmd.Flags().GetString("heartbeat-url")
+			heartbeatURL, err := secretResolver.Resolve(ctx, heartbeatURL)
+			if err != nil {
+				return err
+			}
+			uptime := &uptimeReporter{heartbeatURL: heartbeatURL}
+			alertsPath, _ := cmd.Flags().GetString("alerts")
+			alerts, err := loadAlertReporter(ctx, alertsPath, cmd.Flags().Changed("alerts"))
+			if err != nil {
+				return err
+			}
+			if metricsAddr != "" {
+				registry.OnScrape(collectOperationMetrics(services.GetOperationLimiter()))
+				uptime.endpoint = monitoring.NewHealthEndpoint(3 * monitorCheckTimeout)
+				if err := serveMetrics(ctx, metricsAddr, registry, uptime.endpoint, enablePprof); err != nil {
+					return err
+				}
+			}
+			historyDir, err := monitoring.DefaultMetricsHistoryDir()
+			if err != nil {
+				return err
+			}
+			history := &historyRecorder{history: monitoring.NewMetricsHistory(historyDir), provider: providerName}
+			return monitorWatchMode(ctx, monitor, clusterName, includeMetrics, registry, uptime, alerts, history)
+		}
+		if heartbeatURL, _ := cmd.Flags().GetString("heartbeat-url"); heartbeatURL != "" {
+			return fmt.Errorf("--heartbeat-url requires --watch")
 		}
+		if cmd.Flags().Changed("alerts") {
+			return fmt.Errorf("--alerts requires --watch")
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, monitorCheckTimeout)
+		defer cancel()
 
-		return monitorOneTime(ctx, monitor, clusterName, includeMetrics)
+		return monitorOneTime(ctx, monitor, clusterName, includeMetrics, failOn)
 	},
 }
 
-func monitorOneTime(ctx context.Context, monitor monitoring.Monitor, clusterName string, includeMetrics bool) error {
+// exitHealthGate is the exit status of 'monitor --fail-on' when the cluster's health reaches the
+// threshold, so CI can tell an unhealthy cluster from a check that couldn't run (exit status 1)
+const exitHealthGate = 3
+
+// failOnStatuses are the thresholds --fail-on accepts
+var failOnStatuses = []monitoring.ClusterHealthStatus{monitoring.HealthStatusWarning, monitoring.HealthStatusUnhealthy}
+
+func validateFailOn(failOn string) error {
+	if failOn == "" {
+		return nil
+	}
+	for _, status := range failOnStatuses {
+		if failOn == string(status) {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid --fail-on %q (want warning or unhealthy)", failOn)
+}
+
+// certificateWarningContext returns ctx set to flag cluster certificates that expire within
+// --cert-warning
+func certificateWarningContext(ctx context.Context, cmd *cobra.Command) (context.Context, error) {
+	value, _ := cmd.Flags().GetString("cert-warning")
+	window, err := parseAge(value)
+	if err != nil || window == 0 {
+		return nil, fmt.Errorf("invalid --cert-warning %q: want a duration like 72h, 30d or 2w", value)
+	}
+	return monitoring.WithCertificateWarning(ctx, window), nil
+}
+
+// healthRank orders overall statuses by severity. Unknown ranks with unhealthy: a gate can't
+// pass a cluster whose health couldn't be determined.
+func healthRank(status monitoring.ClusterHealthStatus) int {
+	switch status {
+	case monitoring.HealthStatusHealthy:
+		return 0
+	case monitoring.HealthStatusWarning:
+		return 1
+	}
+	return 2
+}
+
+// healthGate returns an error exiting with exitHealthGate when status is at least as bad as
+// failOn, and nil when failOn is empty or the status is better
+func healthGate(failOn, subject string, status monitoring.ClusterHealthStatus) error {
+	if failOn == "" || healthRank(status) < healthRank(monitoring.ClusterHealthStatus(failOn)) {
+		return nil
+	}
+	return &exitError{code: exitHealthGate, err: fmt.Errorf("%s is %s (--fail-on %s)", subject, status, failOn)}
+}
+
+func monitorOneTime(ctx context.Context, monitor monitoring.Monitor, clusterName string, includeMetrics bool, failOn string) error {
 	services := GetServices()
 	
 	services.Log(fmt.Sprintf("Checking health for cluster: %s", clusterName))
@@ -66,7 +215,7 @@ func monitorOneTime(ctx context.Context, monitor monitoring.Monitor, clusterName
 		return fmt.Errorf("failed to check cluster health: %w", err)
 	}
 
-	if services.GetOutput() == "json" {
+	if currentOutputFormat().structured() {
 		output := map[string]interface{}{
 			"health": healthStatus,
 		}
@@ -74,16 +223,17 @@ func monitorOneTime(ctx context.Context, monitor monitoring.Monitor, clusterName
 		if includeMetrics {
 			metrics, err := monitor.GetClusterMetrics(ctx, clusterName)
 			if err != nil {
-				fmt.Printf("Warning: failed to get metrics: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Warning: failed to get metrics: %v\n", err)
 			} else {
 				output["metrics"] = metrics
 			}
 		}
 		
-		jsonOutput, _ := json.MarshalIndent(output, "", "  ")
-		fmt.Println(string(jsonOutput))
+		if _, err := writeStructured(os.Stdout, output); err != nil {
+			return err
+		}
 	} else {
-		printHealthStatus(healthStatus)
+		printHealthStatus(os.Stdout, healthStatus)
 		
 		if includeMetrics {
 			fmt.Println()
@@ -91,15 +241,15 @@ func monitorOneTime(ctx context.Context, monitor monitoring.Monitor, clusterName
 			if err != nil {
 				fmt.Printf("Warning: failed to get metrics: %v\n", err)
 			} else {
-				printMetrics(metrics)
+				printMetrics(os.Stdout, metrics)
 			}
 		}
 	}
 
-	return nil
+	return healthGate(failOn, "cluster "+clusterName, healthStatus.OverallStatus)
 }
 
-func monitorWatchMode(ctx context.Context, monitor monitoring.Monitor, clusterName string, includeMetrics bool) error {
+func monitorWatchMode(ctx context.Context, monitor monitoring.Monitor, clusterName string, includeMetrics bool, registry *metrics.Registry, uptime *uptimeReporter, alerts *alertReporter, history *historyRecorder) error {
 	fmt.Printf("Monitoring cluster '%s' (Press Ctrl+C to exit)\n\n", clusterName)
 	
 	ticker := time.NewTicker(5 * time.Second)
@@ -108,112 +258,341 @@ func monitorWatchMode(ctx context.Context, monitor monitoring.Monitor, clusterNa
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
-		case <-ticker.C:
-			healthStatus, err := monitor.CheckClusterHealth(ctx, clusterName)
-			if err != nil {
+			// SIGINT/SIGTERM: the in-flight check has been abandoned, stop cleanly
+			fmt.Printf("\nStopped monitoring cluster '%s'\n", clusterName)
+			return nil
+		case tick := <-ticker.C:
+			registry.Set("atlas_monitor_loop_lag_seconds", "Delay between a scheduled monitor refresh and its start",
+				metrics.Labels{"cluster": clusterName}, time.Since(tick).Seconds())
+			if err := monitorWatchTick(ctx, monitor, clusterName, includeMetrics, registry, uptime, alerts, history); err != nil && ctx.Err() == nil {
 				fmt.Printf("Health check failed: %v\n", err)
-				continue
 			}
+		}
+	}
+}
 
-			fmt.Print("\033[2J\033[H")
-			
-			fmt.Printf("=== Cluster Monitor: %s ===\n", clusterName)
-			fmt.Printf("Last updated: %s\n\n", time.Now().Format("15:04:05"))
-			
-			printHealthStatus(healthStatus)
-			
-			if includeMetrics {
-				fmt.Println()
-				metrics, err := monitor.GetClusterMetrics(ctx, clusterName)
-				if err != nil {
-					fmt.Printf("Metrics collection failed: %v\n", err)
-				} else {
-					printMetrics(metrics)
-				}
+// monitorWatchTick runs one refresh of watch mode, bounded so a hung check can't stall the loop
+func monitorWatchTick(ctx context.Context, monitor monitoring.Monitor, clusterName string, includeMetrics bool, registry *metrics.Registry, uptime *uptimeReporter, alerts *alertReporter, history *historyRecorder) error {
+	ctx, cancel := context.WithTimeout(ctx, monitorCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	healthStatus, err := monitor.CheckClusterHealth(ctx, clusterName)
+	registry.Since(providerCallMetric, providerCallHelp, metrics.Labels{"cluster": clusterName, "call": "health"}, start, err)
+	if ctx.Err() != context.Canceled {
+		summary := monitoring.Summarize(clusterName, healthStatus, err)
+		recordHealthMetrics(registry, summary, healthStatus)
+		// a check that timed out still counts as a failure, so report it outside the expired deadline
+		uptime.report(context.WithoutCancel(ctx), summary)
+		alerts.update(context.WithoutCancel(ctx), clusterName, "health", monitoring.EvaluateHealth(clusterName, healthStatus, err, alerts.thresholds()))
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Print("\033[2J\033[H")
+	
+	fmt.Printf("=== Cluster Monitor: %s ===\n", clusterName)
+	fmt.Printf("Last updated: %s\n\n", time.Now().Format("15:04:05"))
+	
+	printHealthStatus(os.Stdout, healthStatus)
+	
+	// metrics are collected for alerts even when they aren't shown
+	if includeMetrics || alerts != nil {
+		start := time.Now()
+		clusterMetrics, err := monitor.GetClusterMetrics(ctx, clusterName)
+		registry.Since(providerCallMetric, providerCallHelp, metrics.Labels{"cluster": clusterName, "call": "metrics"}, start, err)
+		if includeMetrics {
+			fmt.Println()
+			if err != nil {
+				fmt.Printf("Metrics collection failed: %v\n", err)
+			} else {
+				printMetrics(os.Stdout, clusterMetrics)
+			}
+		}
+		if err == nil {
+			history.record(clusterName, clusterMetrics)
+			alerts.update(ctx, clusterName, "metrics", monitoring.EvaluateMetrics(clusterName, clusterMetrics, alerts.thresholds()))
+		}
+	}
+	
+	fmt.Println("\n" + strings.Repeat("=", 50))
+	return nil
+}
+
+// recordHealthMetrics exports the result of a health check, for dashboards of cluster health over time
+func recordHealthMetrics(registry *metrics.Registry, summary monitoring.HealthSummary, status *monitoring.HealthStatus) {
+	labels := metrics.Labels{"cluster": summary.Cluster}
+	healthy := 0.0
+	if summary.Up() {
+		healthy = 1
+	}
+	registry.Set("atlas_cluster_healthy", "Whether the cluster's last health check passed (1) or failed (0)", labels, healthy)
+	if status == nil {
+		return
+	}
+	ready := 0
+	for _, node := range status.Nodes {
+		if node.Ready {
+			ready++
+		}
+	}
+	registry.Set("atlas_cluster_ready_nodes", "Nodes reporting Ready in the cluster's last health check", labels, float64(ready))
+}
+
+// uptimeReporter hands each watch-mode health result to external uptime monitors: the
+// /health/<cluster> endpoint when metrics are served, and a heartbeat URL when one is set
+type uptimeReporter struct {
+	endpoint     *monitoring.HealthEndpoint
+	heartbeatURL string
+}
+
+func (u *uptimeReporter) report(ctx context.Context, summary monitoring.HealthSummary) {
+	if u == nil {
+		return
+	}
+	if u.endpoint != nil {
+		u.endpoint.Update(summary)
+	}
+	if u.heartbeatURL != "" {
+		if err := monitoring.SendHeartbeat(ctx, u.heartbeatURL, summary); err != nil {
+			GetServices().Log(fmt.Sprintf("Heartbeat failed: %v", err))
+		}
+	}
+}
+
+// alertReporter sends the alerts each watch-mode check raises to the channels in the alert config
+type alertReporter struct {
+	notifier *monitoring.Notifier
+	config   *monitoring.AlertConfig
+}
+
+// loadAlertReporter reads the alert config at path, or the default one when path is empty. It
+// returns nil when no config exists, unless the path was given explicitly.
+func loadAlertReporter(ctx context.Context, path string, explicit bool) (*alertReporter, error) {
+	if path == "" {
+		var err error
+		if path, err = monitoring.DefaultAlertConfigPath(); err != nil {
+			return nil, err
+		}
+	}
+	config, err := monitoring.LoadAlertConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		if explicit {
+			return nil, fmt.Errorf("alert config %s does not exist", path)
+		}
+		return nil, nil
+	}
+	if err := secretResolver.ResolveAll(ctx, config); err != nil {
+		return nil, err
+	}
+	notifier, err := monitoring.NewNotifier(config)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "Sending alerts to %d channels from %s\n", len(config.Channels), path)
+	return &alertReporter{notifier: notifier, config: config}, nil
+}
+
+func (a *alertReporter) thresholds() *monitoring.AlertThresholds {
+	if a == nil {
+		return nil
+	}
+	return a.config.Thresholds
+}
+
+func (a *alertReporter) update(ctx context.Context, clusterName, source string, firing []monitoring.MonitoringEvent) {
+	if a == nil {
+		return
+	}
+	if err := a.notifier.Update(ctx, clusterName, source, firing); err != nil {
+		GetServices().Log(fmt.Sprintf("Alert notification failed: %v", err))
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+const (
+	providerCallMetric = "atlas_provider_call_duration_seconds"
+	providerCallHelp   = "Latency of provider calls made by atlas-cli"
+)
+
+// collectOperationMetrics reports in-flight operations across every atlas-cli process on this machine
+func collectOperationMetrics(limiter *operations.Limiter) func(r *metrics.Registry) {
+	return func(r *metrics.Registry) {
+		ops, err := limiter.List()
+		if err != nil {
+			return
+		}
+		counts := map[operations.State]int{operations.StateQueued: 0, operations.StateRunning: 0}
+		var oldest time.Duration
+		for _, op := range ops {
+			counts[op.State]++
+			if op.StartedAt != nil && time.Since(*op.StartedAt) > oldest {
+				oldest = time.Since(*op.StartedAt)
 			}
-			
-			fmt.Println("\n" + strings.Repeat("=", 50))
 		}
+		for state, count := range counts {
+			r.Set("atlas_operations", "Heavy operations in flight by state", metrics.Labels{"state": string(state)}, float64(count))
+		}
+		r.Set("atlas_operation_oldest_running_seconds", "Duration of the longest running operation", nil, oldest.Seconds())
+	}
+}
+
+// serveMetrics exposes registry on addr at /metrics until ctx is done, along with per-cluster
+// health at /health/<cluster> and, when enablePprof is set, the pprof and trace endpoints
+func serveMetrics(ctx context.Context, addr string, registry *metrics.Registry, health *monitoring.HealthEndpoint, enablePprof bool) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry.Handler())
+	if health != nil {
+		mux.Handle("/health/", health.Handler())
 	}
+	if enablePprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+	server := &http.Server{Handler: mux}
+
+	go server.Serve(listener)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving Atlas metrics on http://%s/metrics\n", listener.Addr())
+	return nil
 }
 
-func printHealthStatus(health *monitoring.HealthStatus) {
-	fmt.Printf("Overall Status: %s\n", getStatusIcon(string(health.OverallStatus)))
-	fmt.Printf("Check Duration: %v\n", health.CheckDuration)
+func printHealthStatus(w io.Writer, health *monitoring.HealthStatus) {
+	fmt.Fprintf(w, "Overall Status: %s\n", getStatusIcon(string(health.OverallStatus)))
+	fmt.Fprintf(w, "Check Duration: %v\n", health.CheckDuration)
 	
 	if health.ControlPlane != nil {
-		fmt.Println("\n--- Control Plane ---")
-		fmt.Printf("API Server:          %s\n", getComponentStatusIcon(health.ControlPlane.APIServer.Status))
-		fmt.Printf("Scheduler:           %s\n", getComponentStatusIcon(health.ControlPlane.Scheduler.Status))
-		fmt.Printf("Controller Manager:  %s\n", getComponentStatusIcon(health.ControlPlane.ControllerManager.Status))
-		fmt.Printf("Etcd:               %s\n", getComponentStatusIcon(health.ControlPlane.Etcd.Status))
+		fmt.Fprintln(w, "\n--- Control Plane ---")
+		fmt.Fprintf(w, "API Server:          %s\n", getComponentStatusIcon(health.ControlPlane.APIServer.Status))
+		fmt.Fprintf(w, "Scheduler:           %s\n", getComponentStatusIcon(health.ControlPlane.Scheduler.Status))
+		fmt.Fprintf(w, "Controller Manager:  %s\n", getComponentStatusIcon(health.ControlPlane.ControllerManager.Status))
+		fmt.Fprintf(w, "Etcd:               %s\n", getComponentStatusIcon(health.ControlPlane.Etcd.Status))
 	}
 	
 	if len(health.Nodes) > 0 {
-		fmt.Println("\n--- Nodes ---")
+		fmt.Fprintln(w, "\n--- Nodes ---")
 		for _, node := range health.Nodes {
 			readyIcon := "❌"
 			if node.Ready {
 				readyIcon = "✅"
+			} else if node.Status == monitoring.NodeStarting {
+				readyIcon = "⏳"
 			}
-			fmt.Printf("%s %s (%s)\n", readyIcon, node.Name, node.Version)
+			version := node.Version
+			if node.OS == "windows" {
+				version += ", windows"
+			}
+			fmt.Fprintf(w, "%s %s (%s)\n", readyIcon, node.Name, version)
 		}
 	}
 	
 	if health.Pods != nil {
-		fmt.Println("\n--- Pods ---")
-		fmt.Printf("Total: %d | Running: %d | Pending: %d | Failed: %d\n",
+		fmt.Fprintln(w, "\n--- Pods ---")
+		fmt.Fprintf(w, "Total: %d | Running: %d | Pending: %d | Failed: %d\n",
 			health.Pods.TotalPods, health.Pods.RunningPods, health.Pods.PendingPods, health.Pods.FailedPods)
 		
 		if len(health.Pods.CriticalPods) > 0 {
-			fmt.Println("Critical Pods:")
+			fmt.Fprintln(w, "Critical Pods:")
 			for _, pod := range health.Pods.CriticalPods {
-				fmt.Printf("  ⚠️  %s/%s (%s)\n", pod.Namespace, pod.Name, pod.Phase)
+				fmt.Fprintf(w, "  ⚠️  %s/%s (%s)\n", pod.Namespace, pod.Name, pod.Phase)
 			}
 		}
 	}
 	
 	if health.Services != nil {
-		fmt.Printf("\n--- Services ---\n")
-		fmt.Printf("Total: %d | Healthy: %d\n", health.Services.TotalServices, health.Services.HealthyServices)
+		fmt.Fprintf(w, "\n--- Services ---\n")
+		fmt.Fprintf(w, "Total: %d | Healthy: %d\n", health.Services.TotalServices, health.Services.HealthyServices)
+	}
+	
+	if health.Workloads != nil {
+		printWorkloadHealth(w, health.Workloads)
+	}
+	
+	if len(health.Certificates) > 0 {
+		printCertificateHealth(w, health.Certificates)
 	}
 	
 	if len(health.Warnings) > 0 {
-		fmt.Println("\n--- Warnings ---")
+		fmt.Fprintln(w, "\n--- Warnings ---")
 		for _, warning := range health.Warnings {
-			fmt.Printf("⚠️  %s\n", warning)
+			fmt.Fprintf(w, "⚠️  %s\n", warning)
 		}
 	}
 	
 	if len(health.Errors) > 0 {
-		fmt.Println("\n--- Errors ---")
+		fmt.Fprintln(w, "\n--- Errors ---")
 		for _, error := range health.Errors {
-			fmt.Printf("❌ %s\n", error)
+			fmt.Fprintf(w, "❌ %s\n", error)
+		}
+	}
+}
+
+// printWorkloadHealth prints workload counts by kind and flags each degraded workload
+func printWorkloadHealth(w io.Writer, workloads *monitoring.WorkloadHealth) {
+	fmt.Fprintln(w, "\n--- Workloads ---")
+	fmt.Fprintf(w, "Deployments: %d/%d | StatefulSets: %d/%d | DaemonSets: %d/%d healthy\n",
+		workloads.Deployments.Healthy, workloads.Deployments.Total,
+		workloads.StatefulSets.Healthy, workloads.StatefulSets.Total,
+		workloads.DaemonSets.Healthy, workloads.DaemonSets.Total)
+	if len(workloads.Degraded) > 0 {
+		fmt.Fprintln(w, "Degraded Workloads:")
+		for _, workload := range workloads.Degraded {
+			fmt.Fprintf(w, "  ⚠️  %s %s/%s: %s\n", workload.Kind, workload.Namespace, workload.Name, workload.Message)
+		}
+	}
+}
+
+// printCertificateHealth prints how many cluster certificates were checked and which expires first
+func printCertificateHealth(w io.Writer, certificates []monitoring.ClusterCertificate) {
+	next := certificates[0]
+	for _, cert := range certificates[1:] {
+		if cert.NotAfter.Before(next.NotAfter) {
+			next = cert
 		}
 	}
+	fmt.Fprintln(w, "\n--- Certificates ---")
+	fmt.Fprintf(w, "Total: %d | Next expiry: %s (%s) %s\n", len(certificates), next.Name, next.Source,
+		certificateExpiry(&monitoring.Certificate{NotAfter: next.NotAfter}, time.Now()))
 }
 
-func printMetrics(metrics *monitoring.ClusterMetrics) {
-	fmt.Println("--- Resource Metrics ---")
+func printMetrics(w io.Writer, metrics *monitoring.ClusterMetrics) {
+	fmt.Fprintln(w, "--- Resource Metrics ---")
 	
 	if len(metrics.NodeMetrics) > 0 {
-		fmt.Println("Node Metrics:")
+		fmt.Fprintln(w, "Node Metrics:")
 		for _, node := range metrics.NodeMetrics {
-			fmt.Printf("  %s: CPU %s (%.1f%%) | Memory %s (%.1f%%)\n",
+			fmt.Fprintf(w, "  %s: CPU %s (%.1f%%) | Memory %s (%.1f%%)\n",
 				node.NodeName, node.CPUUsage.Value, node.CPUUsage.Usage,
 				node.MemoryUsage.Value, node.MemoryUsage.Usage)
 		}
 	}
 	
 	if metrics.ResourceUsage != nil {
-		fmt.Printf("\nCluster Totals:\n")
-		fmt.Printf("  CPU Usage: %.1f%%\n", metrics.ResourceUsage.CPUPercentage)
-		fmt.Printf("  Memory Usage: %.1f%%\n", metrics.ResourceUsage.MemoryPercentage)
+		fmt.Fprintf(w, "\nCluster Totals:\n")
+		fmt.Fprintf(w, "  CPU Usage: %.1f%%\n", metrics.ResourceUsage.CPUPercentage)
+		fmt.Fprintf(w, "  Memory Usage: %.1f%%\n", metrics.ResourceUsage.MemoryPercentage)
 	}
 	
 	if len(metrics.PodMetrics) > 0 {
-		fmt.Printf("\nTop Resource-Consuming Pods:\n")
+		fmt.Fprintf(w, "\nTop Resource-Consuming Pods:\n")
 		maxDisplay := 5
 		if len(metrics.PodMetrics) < maxDisplay {
 			maxDisplay = len(metrics.PodMetrics)
@@ -221,7 +600,7 @@ func printMetrics(metrics *monitoring.ClusterMetrics) {
 		
 		for i := 0; i < maxDisplay; i++ {
 			pod := metrics.PodMetrics[i]
-			fmt.Printf("  %s/%s: CPU %s | Memory %s\n",
+			fmt.Fprintf(w, "  %s/%s: CPU %s | Memory %s\n",
 				pod.Namespace, pod.PodName, pod.CPUUsage.Value, pod.MemoryUsage.Value)
 		}
 	}
@@ -254,9 +633,17 @@ func getComponentStatusIcon(status monitoring.ComponentHealthStatus) string {
 func init() {
 	rootCmd.AddCommand(monitorCmd)
 	
-	monitorCmd.Flags().BoolP("metrics", "m", false, "Include detailed resource metrics")
+	monitorCmd.Flags().BoolP("metrics", "m", false, "Include detailed resource metrics; in watch mode they are also recorded for 'metrics history'")
 	monitorCmd.Flags().BoolP("watch", "w", false, "Watch mode - continuously monitor cluster")
-	monitorCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, aws)")
+	monitorCmd.Flags().String("metrics-addr", "", "In watch mode, serve Atlas's own metrics on this address (e.g. :9464)")
+	monitorCmd.Flags().String("heartbeat-url", "", "In watch mode, POST each health result to this healthchecks.io-style ping URL (/fail is appended when unhealthy); may be a secret reference such as vault://path#key")
+	monitorCmd.Flags().String("alerts", "", "In watch mode, send alerts to the Slack, webhook and PagerDuty channels in this file (default ~/.atlas/alerts.yaml when it exists)")
+	monitorCmd.Flags().Bool("enable-pprof", false, "Also serve pprof and trace endpoints under /debug/pprof/ on --metrics-addr")
+	monitorCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+	monitorCmd.Flags().Bool("all", false, "Check every cluster of the provider concurrently")
+	monitorCmd.Flags().String("cert-warning", "30d", "Warn about cluster certificates that expire within this window, e.g. 72h, 30d, 2w")
+	monitorCmd.Flags().String("fleet", "", "Check every cluster in this fleet and show aggregated health")
+	monitorCmd.Flags().String("fail-on", "", "Exit with status 3 when the overall status is at least this bad: warning or unhealthy (unknown counts as unhealthy)")
 	monitorCmd.Flags().StringP("region", "r", "", "Region")
 	monitorCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
 }
\ No newline at end of file
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/fleet"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+)
+
+// clusterCheck is the result of checking one of several clusters
+type clusterCheck struct {
+	Cluster string                         `json:"cluster"`
+	Status  monitoring.ClusterHealthStatus `json:"status"`
+	Health  *monitoring.HealthStatus       `json:"health,omitempty"`
+	Metrics *monitoring.ClusterMetrics     `json:"metrics,omitempty"`
+	Error   string                         `json:"error,omitempty"`
+}
+
+// multiClusterHealth is the output of monitor with several clusters or --all
+type multiClusterHealth struct {
+	OverallStatus monitoring.ClusterHealthStatus         `json:"overall_status"`
+	Counts        map[monitoring.ClusterHealthStatus]int `json:"counts"`
+	Clusters      []clusterCheck                         `json:"clusters"`
+	summary       *fleet.Health
+}
+
+// monitorClusters checks the named clusters, or every cluster of the provider when all is set,
+// concurrently and prints a summary table followed by each cluster's details
+func monitorClusters(ctx context.Context, p providers.Provider, region string, names []string, all, includeMetrics bool, failOn string) error {
+	services := GetServices()
+	if all {
+		clusters, err := p.ListClusters(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list clusters: %w", err)
+		}
+		for _, cluster := range clusters {
+			names = append(names, cluster.Name)
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("no %s clusters found", p.GetProviderName())
+		}
+	}
+
+	services.Log(fmt.Sprintf("Checking health for clusters: %s", strings.Join(names, ", ")))
+	health := checkClusters(ctx, p, region, names, includeMetrics)
+
+	ok, err := writeStructured(os.Stdout, health)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		printClustersHealth(os.Stdout, health)
+	}
+
+	// gate on the worst cluster, so the failure names it
+	worst := health.Clusters[0]
+	for _, check := range health.Clusters[1:] {
+		if healthRank(check.Status) > healthRank(worst.Status) {
+			worst = check
+		}
+	}
+	return healthGate(failOn, "cluster "+worst.Cluster, worst.Status)
+}
+
+// checkClusters checks every cluster concurrently, each bounded by monitorCheckTimeout. Clusters
+// that can't be checked are reported as unknown with the error.
+func checkClusters(ctx context.Context, p providers.Provider, region string, names []string, includeMetrics bool) *multiClusterHealth {
+	monitor := p.GetMonitor()
+	checks := make([]clusterCheck, len(names))
+	members := make([]fleet.MemberHealth, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, monitorCheckTimeout)
+			defer cancel()
+			check := clusterCheck{Cluster: name, Status: monitoring.HealthStatusUnknown}
+			member := fleet.Member{Cluster: name, Provider: p.GetProviderName(), Region: region}
+			health, err := monitor.CheckClusterHealth(checkCtx, name)
+			if err != nil {
+				check.Error = err.Error()
+			} else {
+				check.Health = health
+				check.Status = health.OverallStatus
+				if includeMetrics {
+					if check.Metrics, err = monitor.GetClusterMetrics(checkCtx, name); err != nil {
+						check.Error = fmt.Sprintf("failed to get metrics: %v", err)
+					}
+				}
+			}
+			checks[i] = check
+			members[i] = fleet.NewMemberHealth(member, check.Health, check.Metrics)
+		}(i, name)
+	}
+	wg.Wait()
+
+	summary := fleet.Aggregate("", members)
+	return &multiClusterHealth{
+		OverallStatus: summary.OverallStatus,
+		Counts:        summary.Counts,
+		Clusters:      checks,
+		summary:       summary,
+	}
+}
+
+// printClustersHealth renders a summary table of every cluster and then each one's health
+func printClustersHealth(w io.Writer, health *multiClusterHealth) {
+	fmt.Fprintf(w, "Overall Status: %s\n", getStatusIcon(string(health.OverallStatus)))
+	fmt.Fprintf(w, "Clusters: %d healthy, %d warning, %d unhealthy, %d unknown\n\n",
+		health.Counts[monitoring.HealthStatusHealthy], health.Counts[monitoring.HealthStatusWarning],
+		health.Counts[monitoring.HealthStatusUnhealthy], health.Counts[monitoring.HealthStatusUnknown])
+
+	t := newTable("NAME", "HEALTH", "NODES", "CPU", "MEMORY", "ERROR")
+	for i, check := range health.Clusters {
+		member := health.summary.Clusters[i]
+		cpu, memory := "-", "-"
+		if member.CPUPercentage != nil {
+			cpu = fmt.Sprintf("%.1f%%", *member.CPUPercentage)
+			memory = fmt.Sprintf("%.1f%%", *member.MemoryPercentage)
+		}
+		t.addRow(check.Cluster, check.Status, fmt.Sprintf("%d/%d", member.ReadyNodes, member.Nodes), cpu, memory, valueOrDash(check.Error))
+	}
+	t.render(w)
+
+	for _, check := range health.Clusters {
+		if check.Health == nil {
+			continue
+		}
+		fmt.Fprintf(w, "\n=== Cluster: %s ===\n", check.Cluster)
+		printHealthStatus(w, check.Health)
+		if check.Metrics != nil {
+			fmt.Fprintln(w)
+			printMetrics(w, check.Metrics)
+		}
+	}
+}
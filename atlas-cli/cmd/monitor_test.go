@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/daemon"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/fleet"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/metrics"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/operations"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+)
+
+func TestCollectOperationMetrics(t *testing.T) {
+	limiter := operations.NewLimiter(t.TempDir(), 2)
+	release, err := limiter.Acquire(context.Background(), &operations.Operation{Type: "create", Cluster: "dev"})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer release()
+
+	registry := metrics.NewRegistry()
+	registry.OnScrape(collectOperationMetrics(limiter))
+
+	var out strings.Builder
+	if err := registry.Write(&out); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	for _, want := range []string{
+		`atlas_operations{state="running"} 1`,
+		`atlas_operations{state="queued"} 0`,
+		"atlas_operation_oldest_running_seconds ",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRecordHealthMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	status := &monitoring.HealthStatus{
+		ClusterName:   "dev",
+		OverallStatus: monitoring.HealthStatusWarning,
+		Nodes:         []monitoring.NodeHealth{{Name: "a", Ready: true}, {Name: "b"}},
+	}
+	recordHealthMetrics(registry, monitoring.Summarize("dev", status, nil), status)
+	recordHealthMetrics(registry, monitoring.Summarize("prod", nil, errors.New("unreachable")), nil)
+
+	var out strings.Builder
+	if err := registry.Write(&out); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	for _, want := range []string{
+		`atlas_cluster_healthy{cluster="dev"} 1`,
+		`atlas_cluster_healthy{cluster="prod"} 0`,
+		`atlas_cluster_ready_nodes{cluster="dev"} 1`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestExporterTargets(t *testing.T) {
+	factory := providers.NewProviderFactory()
+	factory.RegisterProvider("kind", func(region, profile string) providers.Provider {
+		return &listOnlyProvider{clusters: []*providers.Cluster{{Name: "dev", Region: "local"}, {Name: "staging"}}}
+	})
+	factory.RegisterProvider("aws", func(region, profile string) providers.Provider {
+		return &listOnlyProvider{err: errors.New("no credentials")}
+	})
+
+	members, failures, err := exporterTargets(context.Background(), factory, "kind", "", "", []string{"dev"})
+	if err != nil || failures != nil {
+		t.Fatalf("exporterTargets() error = %v, failures %v", err, failures)
+	}
+	if len(members) != 1 || members[0] != (fleet.Member{Cluster: "dev", Provider: "kind", Region: "local"}) {
+		t.Errorf("exporterTargets() = %+v", members)
+	}
+
+	if _, _, err := exporterTargets(context.Background(), factory, "aws", "", "", nil); err == nil {
+		t.Error("exporterTargets() for a provider that can't be listed should fail")
+	}
+	members, failures, err = exporterTargets(context.Background(), factory, "all", "", "", nil)
+	if err != nil || failures["aws"] == nil {
+		t.Errorf("exporterTargets(all) error = %v, failures %v, want aws to fail alone", err, failures)
+	}
+	if len(members) < 2 {
+		t.Errorf("exporterTargets(all) = %+v, want the kind clusters", members)
+	}
+}
+
+func TestExportRound(t *testing.T) {
+	ctx := context.Background()
+	p := providers.NewFakeProvider(providers.FakeOptions{Name: "kind", StatePath: filepath.Join(t.TempDir(), "fake.json")})
+	if _, err := p.CreateCluster(ctx, &providers.ClusterConfig{Name: "dev", NodeCount: 2}); err != nil {
+		t.Fatal(err)
+	}
+	getProvider := func(member fleet.Member) (providers.Provider, error) {
+		if member.Provider != "kind" {
+			return nil, errors.New("unsupported provider")
+		}
+		return p, nil
+	}
+
+	exporter := monitoring.NewExporter()
+	registry := metrics.NewRegistry()
+	registry.OnScrape(exporter.Collect)
+	health := monitoring.NewHealthEndpoint(time.Minute)
+	members := []fleet.Member{{Cluster: "dev", Provider: "kind"}, {Cluster: "gone", Provider: "kind"}, {Cluster: "x", Provider: "nope"}}
+	history := monitoring.NewMetricsHistory(filepath.Join(t.TempDir(), "metrics"))
+	exportRound(ctx, members, true, getProvider, exporter, health, registry, history)
+
+	var out strings.Builder
+	if err := registry.Write(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`atlas_cluster_up{cluster="dev",provider="kind"} 1`,
+		`atlas_cluster_ready_nodes{cluster="dev",provider="kind"} 2`,
+		`atlas_node_cpu_percent{cluster="dev",node="dev-m02",provider="kind"} 25`,
+		`atlas_cluster_up{cluster="gone",provider="kind"} 0`,
+		`atlas_cluster_up{cluster="x",provider="nope"} 0`,
+		`atlas_provider_call_duration_seconds_count{call="metrics",cluster="dev"} 1`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	recorded, err := history.Query("", "", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recorded) != 1 || recorded[0].Provider != "kind" || recorded[0].Cluster != "dev" {
+		t.Errorf("metrics history after a round = %+v, want one sample for kind/dev", recorded)
+	}
+
+	exportRound(ctx, members[:1], false, getProvider, exporter, health, registry, nil)
+	if got := exporter.Clusters(); len(got) != 1 || got[0].Name != "dev" {
+		t.Errorf("Clusters() after a round without gone and x = %v", got)
+	}
+}
+
+// startStopMonitor records the StartMonitoring and StopMonitoring calls it receives
+type startStopMonitor struct {
+	monitoring.Monitor
+	started [][]string
+	stopped []string
+	err     error
+}
+
+func (m *startStopMonitor) StartMonitoring(ctx context.Context, config *monitoring.MonitoringConfig) error {
+	m.started = append(m.started, config.ClusterNames)
+	return m.err
+}
+
+func (m *startStopMonitor) StopMonitoring(ctx context.Context, clusterName string) error {
+	m.stopped = append(m.stopped, clusterName)
+	return nil
+}
+
+type monitorOnlyProvider struct {
+	providers.Provider
+	monitor *startStopMonitor
+}
+
+func (p *monitorOnlyProvider) GetMonitor() monitoring.Monitor {
+	return p.monitor
+}
+
+func TestStartDaemonMonitoring(t *testing.T) {
+	monitors := make(map[string]*startStopMonitor)
+	getProvider := func(member fleet.Member) (providers.Provider, error) {
+		if member.Provider == "nope" {
+			return nil, errors.New("unsupported provider")
+		}
+		key := member.Provider + "/" + member.Region
+		if monitors[key] == nil {
+			monitors[key] = &startStopMonitor{}
+		}
+		return &monitorOnlyProvider{monitor: monitors[key]}, nil
+	}
+	members := []fleet.Member{
+		{Cluster: "dev", Provider: "kind"},
+		{Cluster: "prod", Provider: "aws", Region: "us-east-1"},
+		{Cluster: "staging", Provider: "kind"},
+		{Cluster: "dr", Provider: "aws", Region: "us-west-2"},
+	}
+
+	stop, err := startDaemonMonitoring(context.Background(), members, getProvider, monitoring.MonitoringConfig{CheckInterval: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := monitors["kind/"].started; len(got) != 1 || strings.Join(got[0], ",") != "dev,staging" {
+		t.Errorf("kind StartMonitoring calls = %v, want one for dev and staging", got)
+	}
+	if len(monitors["aws/us-east-1"].started) != 1 || len(monitors["aws/us-west-2"].started) != 1 {
+		t.Errorf("aws clusters in different regions should be started separately: %v", monitors)
+	}
+	stop()
+	if got := monitors["kind/"].stopped; strings.Join(got, ",") != "dev,staging" {
+		t.Errorf("kind clusters stopped = %v, want dev and staging", got)
+	}
+
+	if _, err := startDaemonMonitoring(context.Background(), append(members, fleet.Member{Cluster: "x", Provider: "nope"}), getProvider, monitoring.MonitoringConfig{}); err == nil {
+		t.Error("startDaemonMonitoring() with an unknown provider should fail")
+	}
+}
+
+func TestBuildMonitorDaemonStatus(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	changed := time.Now().Add(-time.Hour)
+	events := []monitoring.MonitoringEvent{
+		{ClusterName: "dev", EventType: monitoring.EventTypeStatusChange, Details: map[string]interface{}{"status": "healthy"}, Timestamp: changed.Add(-time.Hour)},
+		{ClusterName: "prod", EventType: monitoring.EventTypeAlert, Message: "CPU high", Timestamp: changed.Add(-time.Minute)},
+		{ClusterName: "dev", EventType: monitoring.EventTypeStatusChange, Details: map[string]interface{}{"status": "unhealthy"}, Timestamp: changed},
+	}
+
+	journalPath, err := monitoring.DefaultEventJournalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := buildMonitorDaemonStatus(journalPath, events, 2)
+	if status.Running {
+		t.Error("status without a PID file reports the daemon running")
+	}
+	if len(status.Clusters) != 1 || status.Clusters[0].Status != monitoring.HealthStatusUnhealthy || !status.Clusters[0].Since.Equal(changed) {
+		t.Errorf("Clusters = %+v, want dev unhealthy since its last transition", status.Clusters)
+	}
+	if len(status.Events) != 2 || status.Events[0].Message != "CPU high" {
+		t.Errorf("Events = %+v, want the 2 most recent", status.Events)
+	}
+
+	// while running, every monitored cluster is listed, including those with no transitions yet
+	pidPath, err := monitorDaemonPath("daemon.pid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release, err := daemon.NewPIDFile(pidPath).Acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	state := monitorDaemonState{PID: os.Getpid(), StartedAt: time.Now(), Interval: "30s",
+		Clusters: []fleet.Member{{Cluster: "dev", Provider: "kind"}, {Cluster: "prod", Provider: "aws"}}}
+	if err := state.save(); err != nil {
+		t.Fatal(err)
+	}
+	status = buildMonitorDaemonStatus(journalPath, events, 0)
+	if !status.Running || status.PID != os.Getpid() || status.Interval != "30s" {
+		t.Errorf("status = %+v, want this process running", status)
+	}
+	if len(status.Clusters) != 2 || status.Clusters[1].Cluster != "prod" || status.Clusters[1].Status != monitoring.HealthStatusUnknown {
+		t.Errorf("Clusters = %+v, want dev and prod, prod unknown", status.Clusters)
+	}
+	if len(status.Events) != 3 {
+		t.Errorf("Events with no limit = %d, want 3", len(status.Events))
+	}
+}
+
+func TestHealthGate(t *testing.T) {
+	tests := []struct {
+		failOn string
+		status monitoring.ClusterHealthStatus
+		fail   bool
+	}{
+		{"", monitoring.HealthStatusUnhealthy, false},
+		{"warning", monitoring.HealthStatusHealthy, false},
+		{"warning", monitoring.HealthStatusWarning, true},
+		{"warning", monitoring.HealthStatusUnknown, true},
+		{"unhealthy", monitoring.HealthStatusWarning, false},
+		{"unhealthy", monitoring.HealthStatusUnhealthy, true},
+		{"unhealthy", monitoring.HealthStatusUnknown, true},
+	}
+	for _, tt := range tests {
+		err := healthGate(tt.failOn, "cluster dev", tt.status)
+		if !tt.fail {
+			if err != nil {
+				t.Errorf("healthGate(%q, %s) = %v, want nil", tt.failOn, tt.status, err)
+			}
+			continue
+		}
+		var exitErr *exitError
+		if !errors.As(err, &exitErr) || exitErr.code != exitHealthGate {
+			t.Errorf("healthGate(%q, %s) = %v, want exit status %d", tt.failOn, tt.status, err, exitHealthGate)
+		}
+	}
+
+	if err := validateFailOn("critical"); err == nil {
+		t.Error("validateFailOn(critical) should fail")
+	}
+}
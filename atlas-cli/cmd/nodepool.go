@@ -0,0 +1,332 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+var nodepoolCmd = &cobra.Command{
+	Use:   "nodepool",
+	Short: "Manage a cluster's node pools",
+	Long: `Manage the node pools of clusters that can run more than one, such as EKS managed node groups.
+Each pool has its own instance type, labels, taints and size range, e.g. a tainted GPU pool next
+to the general purpose pool the cluster was created with.`,
+}
+
+var nodepoolListCmd = &cobra.Command{
+	Use:   "list [cluster]",
+	Short: "List a cluster's node pools",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		clusterName := args[0]
+		_, manager, err := nodePoolManager(cmd)
+		if err != nil {
+			return err
+		}
+		pools, err := manager.ListNodePools(commandContext(), clusterName)
+		if err != nil {
+			return fmt.Errorf("failed to list node pools: %w", err)
+		}
+
+		if ok, err := writeStructured(os.Stdout, pools); ok {
+			return err
+		}
+		if len(pools) == 0 {
+			fmt.Printf("No node pools found for cluster '%s'\n", clusterName)
+			return nil
+		}
+
+		t := newTable("NAME", "STATUS", "OS", "INSTANCE TYPE", "NODES", "MIN", "MAX", "LABELS", "TAINTS")
+		for _, pool := range pools {
+			taints := make([]string, len(pool.Taints))
+			for i, taint := range pool.Taints {
+				taints[i] = taint.String()
+			}
+			t.addRow(pool.Name, pool.Status, valueOrDash(pool.OS), valueOrDash(pool.InstanceType), pool.Scaling.DesiredSize, pool.Scaling.MinSize,
+				pool.Scaling.MaxSize, valueOrDash(formatLabels(pool.Labels)), valueOrDash(strings.Join(taints, ",")))
+		}
+		t.render(os.Stdout)
+		return nil
+	},
+}
+
+var nodepoolCreateCmd = &cobra.Command{
+	Use:   "create [cluster] [pool]",
+	Short: "Add a node pool to a cluster",
+	Long: `Add a node pool to a cluster and wait for its nodes to join. --nodes sets the pool's size;
+--min and --max set the range the cluster autoscaler may resize it within and default to --nodes.
+
+Labels are applied to every node in the pool. Taints use kubectl's key=value:Effect form, where
+Effect is NoSchedule, PreferNoSchedule or NoExecute.
+
+--os windows creates Windows nodes, which EKS supports from Kubernetes 1.23 in clusters that
+already have a Linux pool for system pods. The pool defaults to the WINDOWS_CORE_2022_x86_64 AMI
+and, unless --taint is given, is tainted os=windows:NoSchedule so only pods that tolerate it are
+scheduled there. Windows IP address management is enabled in the cluster's VPC CNI first.`,
+	Example: `  atlas-cli nodepool create prod gpu -p aws -r us-west-2 --instance-type g5.xlarge --nodes 2 --max 4 \
+    --label workload=gpu --taint nvidia.com/gpu=present:NoSchedule
+  atlas-cli nodepool create prod win -p aws -r us-west-2 --os windows --nodes 2
+  atlas-cli nodepool create prod win2019 -p aws -r us-west-2 --ami-type WINDOWS_FULL_2019_x86_64`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		clusterName, poolName := args[0], args[1]
+		config := providers.NodePoolConfig{Name: poolName}
+		config.InstanceType, _ = cmd.Flags().GetString("instance-type")
+		config.OS, _ = cmd.Flags().GetString("os")
+		config.AMIType, _ = cmd.Flags().GetString("ami-type")
+		config.Scaling.DesiredSize, _ = cmd.Flags().GetInt("nodes")
+		config.Scaling.MinSize, config.Scaling.MaxSize = config.Scaling.DesiredSize, config.Scaling.DesiredSize
+		if cmd.Flags().Changed("min") {
+			config.Scaling.MinSize, _ = cmd.Flags().GetInt("min")
+		}
+		if cmd.Flags().Changed("max") {
+			config.Scaling.MaxSize, _ = cmd.Flags().GetInt("max")
+		}
+		if err := config.Scaling.Validate(); err != nil {
+			return err
+		}
+		labels, _ := cmd.Flags().GetStringSlice("label")
+		var err error
+		if config.Labels, err = parseLabels(labels); err != nil {
+			return err
+		}
+		taints, _ := cmd.Flags().GetStringArray("taint")
+		for _, value := range taints {
+			taint, err := providers.ParseTaint(value)
+			if err != nil {
+				return err
+			}
+			config.Taints = append(config.Taints, taint)
+		}
+
+		services.Log(fmt.Sprintf("Creating node pool %s in cluster %s", poolName, clusterName))
+		warnOutsideMaintenanceWindow(clusterName, "adding a node pool to")
+		if err := requireApproval(clusterName, "create node pool", poolName); err != nil {
+			return err
+		}
+		p, manager, err := nodePoolManager(cmd)
+		if err != nil {
+			return err
+		}
+		ctx := commandContext()
+		if err := requireCredentials(ctx, p); err != nil {
+			return err
+		}
+		if err := manager.CreateNodePool(ctx, clusterName, config); err != nil {
+			return fmt.Errorf("failed to create node pool: %w", err)
+		}
+		syncInventory(ctx, p, clusterName)
+
+		return printNodePoolResult(clusterName, poolName, "created",
+			fmt.Sprintf("Node pool '%s' created in cluster '%s' with %d nodes", poolName, clusterName, config.Scaling.DesiredSize))
+	},
+}
+
+var nodepoolScaleCmd = &cobra.Command{
+	Use:   "scale [cluster] [pool]",
+	Short: "Resize a node pool",
+	Long: `Change a node pool's size. --min and --max keep their current values unless given, and are
+widened to include --nodes when it falls outside them.`,
+	Example: `  atlas-cli nodepool scale prod gpu -p aws -r us-west-2 --nodes 4
+  atlas-cli nodepool scale prod gpu -p aws -r us-west-2 --nodes 0 --min 0`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		clusterName, poolName := args[0], args[1]
+		nodes, _ := cmd.Flags().GetInt("nodes")
+
+		services.Log(fmt.Sprintf("Scaling node pool %s in cluster %s to %d nodes", poolName, clusterName, nodes))
+		warnOutsideMaintenanceWindow(clusterName, "scaling")
+		if err := requireApproval(clusterName, "scale node pool", fmt.Sprintf("%s to %d nodes", poolName, nodes)); err != nil {
+			return err
+		}
+		p, manager, err := nodePoolManager(cmd)
+		if err != nil {
+			return err
+		}
+		ctx := commandContext()
+		if err := requireCredentials(ctx, p); err != nil {
+			return err
+		}
+		pools, err := manager.ListNodePools(ctx, clusterName)
+		if err != nil {
+			return fmt.Errorf("failed to list node pools: %w", err)
+		}
+		var current *providers.NodePool
+		for i := range pools {
+			if pools[i].Name == poolName {
+				current = &pools[i]
+			}
+		}
+		if current == nil {
+			return fmt.Errorf("node pool %s not found in cluster %s", poolName, clusterName)
+		}
+
+		scaling := nodePoolScaling(current.Scaling, nodes)
+		if cmd.Flags().Changed("min") {
+			scaling.MinSize, _ = cmd.Flags().GetInt("min")
+		}
+		if cmd.Flags().Changed("max") {
+			scaling.MaxSize, _ = cmd.Flags().GetInt("max")
+		}
+		if err := scaling.Validate(); err != nil {
+			return err
+		}
+		if err := manager.ScaleNodePool(ctx, clusterName, poolName, scaling); err != nil {
+			return fmt.Errorf("failed to scale node pool: %w", err)
+		}
+		syncInventory(ctx, p, clusterName)
+
+		return printNodePoolResult(clusterName, poolName, "scaled",
+			fmt.Sprintf("Node pool '%s' in cluster '%s' scaled to %d nodes (min %d, max %d)", poolName, clusterName,
+				scaling.DesiredSize, scaling.MinSize, scaling.MaxSize))
+	},
+}
+
+var nodepoolDeleteCmd = &cobra.Command{
+	Use:   "delete [cluster] [pool]",
+	Short: "Remove a node pool from a cluster",
+	Long:  `Delete a node pool and its nodes. Pods running on them are rescheduled onto the cluster's other pools.`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		clusterName, poolName := args[0], args[1]
+		services.Log(fmt.Sprintf("Deleting node pool %s from cluster %s", poolName, clusterName))
+		warnOutsideMaintenanceWindow(clusterName, "removing a node pool from")
+		if err := requireApproval(clusterName, "delete node pool", poolName); err != nil {
+			return err
+		}
+		p, manager, err := nodePoolManager(cmd)
+		if err != nil {
+			return err
+		}
+		ctx := commandContext()
+		if err := requireCredentials(ctx, p); err != nil {
+			return err
+		}
+		if err := manager.DeleteNodePool(ctx, clusterName, poolName); err != nil {
+			return fmt.Errorf("failed to delete node pool: %w", err)
+		}
+		syncInventory(ctx, p, clusterName)
+
+		return printNodePoolResult(clusterName, poolName, "deleted",
+			fmt.Sprintf("Node pool '%s' deleted from cluster '%s'", poolName, clusterName))
+	},
+}
+
+// nodePoolManager returns the provider from the command's flags, if it supports node pools
+func nodePoolManager(cmd *cobra.Command) (providers.Provider, providers.NodePoolManager, error) {
+	providerName, _ := cmd.Flags().GetString("provider")
+	region, _ := cmd.Flags().GetString("region")
+	awsProfile, _ := cmd.Flags().GetString("aws-profile")
+
+	p, err := GetServices().GetProvider(providerName, region, awsProfile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get provider: %w", err)
+	}
+	manager, ok := p.(providers.NodePoolManager)
+	if !ok {
+		return nil, nil, fmt.Errorf("provider %s does not support node pools", p.GetProviderName())
+	}
+	return p, manager, nil
+}
+
+// nodePoolScaling sets current's desired size to nodes, widening its range to include it
+func nodePoolScaling(current providers.NodePoolScaling, nodes int) providers.NodePoolScaling {
+	scaling := current
+	scaling.DesiredSize = nodes
+	if nodes < scaling.MinSize {
+		scaling.MinSize = nodes
+	}
+	if nodes > scaling.MaxSize {
+		scaling.MaxSize = nodes
+	}
+	return scaling
+}
+
+// parseLabels parses key=value node labels
+func parseLabels(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string, len(values))
+	for _, value := range values {
+		key, labelValue, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label %q; use key=value", value)
+		}
+		labels[key] = labelValue
+	}
+	return labels, nil
+}
+
+// formatLabels renders labels as sorted key=value pairs
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func printNodePoolResult(clusterName, poolName, status, message string) error {
+	if ok, err := writeStructured(os.Stdout, map[string]any{
+		"cluster":  clusterName,
+		"nodePool": poolName,
+		"status":   status,
+		"message":  message,
+	}); ok {
+		return err
+	}
+	fmt.Println(message)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(nodepoolCmd)
+	nodepoolCmd.AddCommand(nodepoolListCmd, nodepoolCreateCmd, nodepoolScaleCmd, nodepoolDeleteCmd)
+
+	for _, c := range []*cobra.Command{nodepoolListCmd, nodepoolCreateCmd, nodepoolScaleCmd, nodepoolDeleteCmd} {
+		c.Flags().StringP("provider", "p", "aws", "Cloud provider (aws)")
+		c.Flags().StringP("region", "r", "", "Region the cluster runs in")
+		c.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	}
+
+	nodepoolCreateCmd.Flags().String("instance-type", "", "Instance type of the pool's nodes (provider default if empty)")
+	nodepoolCreateCmd.Flags().String("os", "", "Operating system of the pool's nodes: linux or windows (default from --ami-type, else linux)")
+	nodepoolCreateCmd.Flags().String("ami-type", "", "EKS AMI type, e.g. AL2023_x86_64_STANDARD or WINDOWS_CORE_2022_x86_64 (provider default if empty)")
+	nodepoolCreateCmd.Flags().IntP("nodes", "n", 1, "Number of nodes")
+	nodepoolCreateCmd.Flags().Int("min", 0, "Minimum pool size (default --nodes)")
+	nodepoolCreateCmd.Flags().Int("max", 0, "Maximum pool size (default --nodes)")
+	nodepoolCreateCmd.Flags().StringSlice("label", nil, "Node label as key=value (repeatable)")
+	nodepoolCreateCmd.Flags().StringArray("taint", nil, "Node taint as key=value:Effect (repeatable)")
+
+	nodepoolScaleCmd.Flags().IntP("nodes", "n", 0, "Number of nodes")
+	nodepoolScaleCmd.Flags().Int("min", 0, "Minimum pool size (default: unchanged)")
+	nodepoolScaleCmd.Flags().Int("max", 0, "Maximum pool size (default: unchanged)")
+	nodepoolScaleCmd.MarkFlagRequired("nodes")
+}
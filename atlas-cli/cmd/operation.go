@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/approvals"
+	"github.com/spf13/cobra"
+)
+
+var operationCmd = &cobra.Command{
+	Use:   "operation",
+	Short: "Inspect in-flight operations",
+	Long: `Inspect heavy operations that are running or queued behind the concurrency limit, approve
+operations on protected clusters and annotate past operations.`,
+}
+
+var operationListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List running and queued operations",
+	Long: `List heavy operations across all atlas-cli processes on this machine, in the order they will be admitted,
+followed by approval requests for operations on protected clusters.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		limiter := services.GetOperationLimiter()
+		ops, err := limiter.List()
+		if err != nil {
+			return fmt.Errorf("failed to list operations: %w", err)
+		}
+		approvalStore, err := loadDefault(approvals.DefaultStorePath, approvals.LoadStore)
+		if err != nil {
+			return err
+		}
+		requests := approvalStore.Open()
+
+		result := map[string]any{
+			"limit":      limiter.Limit(),
+			"operations": ops,
+			"approvals":  requests,
+		}
+		if ok, err := writeStructured(os.Stdout, result); ok {
+			return err
+		}
+
+		if len(ops) == 0 {
+			fmt.Println("No operations in progress")
+		} else {
+			t := newTable("ID", "TYPE", "CLUSTER", "PROVIDER", "STATE", "PID", "AGE").withSeparator()
+			for _, op := range ops {
+				t.addRow(op.ID, op.Type, op.Cluster, op.Provider, op.State, op.PID, time.Since(op.QueuedAt).Round(time.Second))
+			}
+			t.render(os.Stdout)
+			fmt.Printf("\nConcurrency limit: %d\n", limiter.Limit())
+		}
+
+		if len(requests) > 0 {
+			fmt.Println("\nApproval requests:")
+			printApprovalRequests(os.Stdout, requests)
+		}
+		return nil
+	},
+}
+
+var operationCancelCmd = &cobra.Command{
+	Use:   "cancel [id]",
+	Short: "Cancel a running or queued operation",
+	Long: `Cancel an in-flight operation listed by 'operation list'. The process running it is signalled,
+its provider subprocesses are killed, and a partially created cluster is rolled back if the operation
+was started with --rollback-on-cancel.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		op, err := services.GetOperationLimiter().Cancel(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to cancel operation: %w", err)
+		}
+
+		if ok, err := writeStructured(os.Stdout, op); ok {
+			return err
+		}
+
+		fmt.Printf("Canceled %s of cluster '%s' (operation %s)\n", op.Type, op.Cluster, op.ID)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(operationCmd)
+	operationCmd.AddCommand(operationListCmd)
+	operationCmd.AddCommand(operationCancelCmd)
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Output formats accepted by --output. jsonpath and go-template take their expression after an
+// equals sign, e.g. -o jsonpath={.name}.
+const (
+	outputText       = "text"
+	outputWide       = "wide"
+	outputJSON       = "json"
+	outputYAML       = "yaml"
+	outputName       = "name"
+	outputJSONPath   = "jsonpath"
+	outputGoTemplate = "go-template"
+)
+
+// outputFormat is a parsed --output value
+type outputFormat struct {
+	Kind string
+	// Template is the jsonpath expression or Go template
+	Template string
+}
+
+// parseOutputFormat parses an --output value, checking that a jsonpath or template compiles
+func parseOutputFormat(value string) (outputFormat, error) {
+	kind, tmpl, hasTemplate := strings.Cut(value, "=")
+	format := outputFormat{Kind: kind, Template: tmpl}
+	switch kind {
+	case outputText, outputWide, outputJSON, outputYAML, outputName:
+		if hasTemplate {
+			return format, fmt.Errorf("output format %s does not take a template", kind)
+		}
+	case outputJSONPath:
+		if tmpl == "" {
+			return format, fmt.Errorf("--output jsonpath needs an expression, e.g. jsonpath={.name}")
+		}
+		if err := jsonpath.New("output").Parse(relaxedJSONPath(tmpl)); err != nil {
+			return format, fmt.Errorf("invalid jsonpath %q: %w", tmpl, err)
+		}
+	case outputGoTemplate:
+		if tmpl == "" {
+			return format, fmt.Errorf("--output go-template needs a template, e.g. go-template={{.name}}")
+		}
+		if _, err := template.New("output").Parse(tmpl); err != nil {
+			return format, fmt.Errorf("invalid go-template: %w", err)
+		}
+	default:
+		return format, fmt.Errorf("unknown output format %q (use text, wide, json, yaml, name, jsonpath=... or go-template=...)", value)
+	}
+	return format, nil
+}
+
+// currentOutputFormat returns the --output format, already validated by the root command
+func currentOutputFormat() outputFormat {
+	format, _ := parseOutputFormat(GetOutput())
+	return format
+}
+
+// structured reports whether the format renders data rather than a human-readable view
+func (f outputFormat) structured() bool {
+	switch f.Kind {
+	case outputJSON, outputYAML, outputJSONPath, outputGoTemplate:
+		return true
+	}
+	return false
+}
+
+// writeStructured renders v in the selected structured format and reports whether it did. It
+// returns false for text, wide and name, which each command prints its own way. Field names are
+// v's JSON names in every format.
+func writeStructured(w io.Writer, v any) (bool, error) {
+	format := currentOutputFormat()
+	if !format.structured() {
+		return false, nil
+	}
+	out, err := renderStructured(format, v)
+	if err != nil {
+		return true, err
+	}
+	if len(out) > 0 && !bytes.HasSuffix(out, []byte("\n")) {
+		out = append(out, '\n')
+	}
+	_, err = w.Write(out)
+	return true, err
+}
+
+func renderStructured(format outputFormat, v any) ([]byte, error) {
+	switch format.Kind {
+	case outputJSON:
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal output: %w", err)
+		}
+		return out, nil
+	case outputYAML:
+		out, err := toYAML(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal output: %w", err)
+		}
+		return out, nil
+	}
+
+	generic, err := toGeneric(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %w", err)
+	}
+	var buf bytes.Buffer
+	if format.Kind == outputJSONPath {
+		jp := jsonpath.New("output")
+		if err := jp.Parse(relaxedJSONPath(format.Template)); err != nil {
+			return nil, fmt.Errorf("invalid jsonpath %q: %w", format.Template, err)
+		}
+		if err := jp.Execute(&buf, generic); err != nil {
+			return nil, fmt.Errorf("failed to apply jsonpath %q: %w", format.Template, err)
+		}
+		return buf.Bytes(), nil
+	}
+	tmpl, err := template.New("output").Parse(format.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid go-template: %w", err)
+	}
+	if err := tmpl.Execute(&buf, generic); err != nil {
+		return nil, fmt.Errorf("failed to apply go-template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// toGeneric converts v to maps and slices keyed by its JSON field names
+func toGeneric(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// relaxedJSONPath wraps a bare expression such as .name in braces, as kubectl does
+func relaxedJSONPath(expression string) string {
+	if strings.Contains(expression, "{") {
+		return expression
+	}
+	if !strings.HasPrefix(expression, ".") && !strings.HasPrefix(expression, "[") {
+		expression = "." + expression
+	}
+	return "{" + expression + "}"
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		value    string
+		kind     string
+		template string
+		wantErr  bool
+	}{
+		{value: "text", kind: outputText},
+		{value: "wide", kind: outputWide},
+		{value: "yaml", kind: outputYAML},
+		{value: "jsonpath={.name}", kind: outputJSONPath, template: "{.name}"},
+		{value: "jsonpath=.name", kind: outputJSONPath, template: ".name"},
+		{value: "go-template={{.name}} {{.status}}", kind: outputGoTemplate, template: "{{.name}} {{.status}}"},
+		{value: "table", wantErr: true},
+		{value: "json=x", wantErr: true},
+		{value: "jsonpath=", wantErr: true},
+		{value: "jsonpath={.name", wantErr: true},
+		{value: "go-template={{.name", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseOutputFormat(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseOutputFormat(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (got.Kind != tt.kind || got.Template != tt.template) {
+			t.Errorf("parseOutputFormat(%q) = %+v, want kind %q template %q", tt.value, got, tt.kind, tt.template)
+		}
+	}
+}
+
+func TestRenderStructured(t *testing.T) {
+	clusters := []map[string]any{
+		{"name": "dev", "status": "running", "nodeCount": 1},
+		{"name": "prod", "status": "stopped", "nodeCount": 3},
+	}
+	tests := []struct {
+		format outputFormat
+		want   string
+	}{
+		{outputFormat{Kind: outputJSONPath, Template: "{[*].name}"}, "dev prod"},
+		{outputFormat{Kind: outputJSONPath, Template: "[0].status"}, "running"},
+		{outputFormat{Kind: outputJSONPath, Template: `{range [*]}{.name}={.nodeCount}{"\n"}{end}`}, "dev=1\nprod=3\n"},
+		{outputFormat{Kind: outputGoTemplate, Template: "{{range .}}{{.name}} {{end}}"}, "dev prod "},
+		{outputFormat{Kind: outputYAML}, "- name: dev\n  nodeCount: 1\n  status: running\n"},
+	}
+	for _, tt := range tests {
+		got, err := renderStructured(tt.format, clusters)
+		if err != nil {
+			t.Errorf("renderStructured(%+v) error = %v", tt.format, err)
+			continue
+		}
+		if !strings.HasPrefix(string(got), tt.want) {
+			t.Errorf("renderStructured(%+v) = %q, want it to start with %q", tt.format, got, tt.want)
+		}
+	}
+
+	if _, err := renderStructured(outputFormat{Kind: outputJSONPath, Template: "{.missing}"}, clusters); err == nil {
+		t.Error("renderStructured() with a missing field should fail")
+	}
+}
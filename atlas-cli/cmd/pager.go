@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// pagedOutput returns a writer that feeds $PAGER (less by default) when stdout is a terminal, so
+// long output can be scrolled. Like git, LESS defaults to FRX so output that fits on one screen
+// is printed without waiting for a keypress. The returned function waits for the pager to exit.
+func pagedOutput() (io.Writer, func()) {
+	if !isTerminal(os.Stdout) {
+		return os.Stdout, func() {}
+	}
+
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+	}
+	fields := strings.Fields(pager)
+	if len(fields) == 0 || fields[0] == "cat" {
+		return os.Stdout, func() {}
+	}
+
+	// The pager is interactive, so it runs outside the subprocess watchdog
+	cmd := exec.Command(fields[0], fields[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if os.Getenv("LESS") == "" {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return os.Stdout, func() {}
+	}
+	if err := cmd.Start(); err != nil {
+		return os.Stdout, func() {}
+	}
+	return stdin, func() {
+		stdin.Close()
+		cmd.Wait()
+	}
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/ports"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+var portsCmd = &cobra.Command{
+	Use:   "ports",
+	Short: "Inspect host ports allocated to local clusters",
+	Long: `Atlas records the host ports each local cluster uses (API server, port mappings, ingress,
+tunnels, registries) and hands out non-conflicting ones, so clusters can run side by side.`,
+}
+
+var portsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List port allocations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := loadDefault(ports.DefaultStorePath, ports.LoadStore)
+		if err != nil {
+			return err
+		}
+		allocations := store.Allocations()
+
+		if allocations == nil {
+			allocations = []ports.Allocation{}
+		}
+		if ok, err := writeStructured(os.Stdout, allocations); ok {
+			return err
+		}
+		if len(allocations) == 0 {
+			fmt.Println("No ports allocated")
+			return nil
+		}
+		printPortAllocations(os.Stdout, allocations)
+		return nil
+	},
+}
+
+func printPortAllocations(w io.Writer, allocations []ports.Allocation) {
+	t := newTable("PORT", "CLUSTER", "PURPOSE")
+	for _, allocation := range allocations {
+		t.addRow(allocation.Port, allocation.Cluster, allocation.Purpose)
+	}
+	t.render(w)
+}
+
+// portReservation holds the ports reserved for a cluster while it is created
+type portReservation struct {
+	path      string
+	allocated []ports.Allocation
+	kept      bool
+}
+
+// reserveClusterPorts allocates config's ports when p runs clusters on this host, recording them
+// right away under the registry's lock so a concurrent create can't pick the same ports. It
+// returns nil for other providers. Callers defer release and keep the ports once the cluster exists.
+func reserveClusterPorts(p providers.Provider, config *providers.ClusterConfig) (*portReservation, error) {
+	if !providers.RunsOnHost(p.GetProviderName()) {
+		return nil, nil
+	}
+	path, err := ports.DefaultStorePath()
+	if err != nil {
+		return nil, err
+	}
+	reservation := &portReservation{path: path}
+	err = ports.Update(path, func(store *ports.Store) error {
+		held := make(map[ports.Allocation]bool)
+		for _, allocation := range store.Allocations() {
+			held[allocation] = true
+		}
+		if err := allocateClusterPorts(store, config); err != nil {
+			return err
+		}
+		for _, allocation := range store.Allocations() {
+			if !held[allocation] {
+				reservation.allocated = append(reservation.allocated, allocation)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// keep marks the reserved ports as the created cluster's
+func (r *portReservation) keep() {
+	if r != nil {
+		r.kept = true
+	}
+}
+
+// release frees the ports reserved for a cluster that wasn't created, warning on failure
+func (r *portReservation) release() {
+	if r == nil || r.kept || len(r.allocated) == 0 {
+		return
+	}
+	err := ports.Update(r.path, func(store *ports.Store) error {
+		for _, allocation := range r.allocated {
+			store.Free(allocation.Cluster, allocation.Purpose)
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to release reserved ports: %v\n", err)
+	}
+}
+
+// allocateClusterPorts reserves the API server port and mapped host ports config asks for, and
+// assigns a free API server port when none is set
+func allocateClusterPorts(store *ports.Store, config *providers.ClusterConfig) error {
+	if config.NetworkConfig == nil {
+		config.NetworkConfig = &providers.NetworkConfig{}
+	}
+	network := config.NetworkConfig
+
+	if network.APIServerPort > 0 {
+		if err := store.Reserve(config.Name, ports.PurposeAPIServer, network.APIServerPort); err != nil {
+			return fmt.Errorf("%w; choose another with --api-server-port or use --api-server-port auto", err)
+		}
+	} else {
+		port, err := store.Allocate(config.Name, ports.PurposeAPIServer)
+		if err != nil {
+			return err
+		}
+		network.APIServerPort = port
+	}
+
+	for _, portMap := range network.ExtraPortMaps {
+		if err := store.Reserve(config.Name, ports.HostPortPurpose(portMap.ContainerPort), portMap.HostPort); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(portsCmd)
+	portsCmd.AddCommand(portsListCmd)
+}
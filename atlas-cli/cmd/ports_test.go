@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/ports"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+)
+
+func TestAllocateClusterPorts(t *testing.T) {
+	store, err := ports.LoadStore(filepath.Join(t.TempDir(), "ports.json"))
+	if err != nil {
+		t.Fatalf("LoadStore() error = %v", err)
+	}
+
+	dev := &providers.ClusterConfig{
+		Name: "dev",
+		NetworkConfig: &providers.NetworkConfig{
+			ExtraPortMaps: []providers.PortMapping{{HostPort: 18080, ContainerPort: 80}},
+		},
+	}
+	if err := allocateClusterPorts(store, dev); err != nil {
+		t.Fatalf("allocateClusterPorts(dev) error = %v", err)
+	}
+	if dev.NetworkConfig.APIServerPort == 0 {
+		t.Error("allocateClusterPorts() should assign an API server port")
+	}
+
+	test := &providers.ClusterConfig{
+		Name:          "test",
+		NetworkConfig: &providers.NetworkConfig{APIServerPort: dev.NetworkConfig.APIServerPort},
+	}
+	err = allocateClusterPorts(store, test)
+	if err == nil || !strings.Contains(err.Error(), "cluster dev") {
+		t.Errorf("allocateClusterPorts(test) = %v, want a conflict with cluster dev", err)
+	}
+
+	test = &providers.ClusterConfig{Name: "test"}
+	if err := allocateClusterPorts(store, test); err != nil {
+		t.Fatalf("allocateClusterPorts(test) error = %v", err)
+	}
+	if test.NetworkConfig.APIServerPort == dev.NetworkConfig.APIServerPort {
+		t.Errorf("test and dev were both given API server port %d", test.NetworkConfig.APIServerPort)
+	}
+}
+
+func TestReserveClusterPorts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	p := providers.NewLocalProvider()
+	allocated := func() []ports.Allocation {
+		t.Helper()
+		store, err := loadDefault(ports.DefaultStorePath, ports.LoadStore)
+		if err != nil {
+			t.Fatalf("LoadStore() error = %v", err)
+		}
+		return store.Allocations()
+	}
+
+	dev := &providers.ClusterConfig{Name: "dev"}
+	reservation, err := reserveClusterPorts(p, dev)
+	if err != nil {
+		t.Fatalf("reserveClusterPorts(dev) error = %v", err)
+	}
+	// the ports are recorded before the cluster is created, so other creates see them
+	if got := allocated(); len(got) != 1 || got[0].Port != dev.NetworkConfig.APIServerPort {
+		t.Fatalf("allocations while creating dev = %+v, want its API server port", got)
+	}
+	_, err = reserveClusterPorts(p, &providers.ClusterConfig{
+		Name:          "test",
+		NetworkConfig: &providers.NetworkConfig{APIServerPort: dev.NetworkConfig.APIServerPort},
+	})
+	if err == nil {
+		t.Error("reserveClusterPorts(test) expected a conflict with the port reserved for dev")
+	}
+	reservation.keep()
+	reservation.release()
+	if got := allocated(); len(got) != 1 {
+		t.Errorf("allocations after keeping dev's ports = %+v, want them kept", got)
+	}
+
+	// a failed create frees only the ports it reserved
+	failed := &providers.ClusterConfig{
+		Name: "dev",
+		NetworkConfig: &providers.NetworkConfig{
+			ExtraPortMaps: []providers.PortMapping{{HostPort: 18080, ContainerPort: 80}},
+		},
+	}
+	reservation, err = reserveClusterPorts(p, failed)
+	if err != nil {
+		t.Fatalf("reserveClusterPorts(dev) again error = %v", err)
+	}
+	if got := allocated(); len(got) != 2 {
+		t.Fatalf("allocations while recreating dev = %+v, want its API server and mapped ports", got)
+	}
+	reservation.release()
+	if got := allocated(); len(got) != 1 || got[0].Purpose != ports.PurposeAPIServer {
+		t.Errorf("allocations after a failed create = %+v, want only dev's original API server port", got)
+	}
+}
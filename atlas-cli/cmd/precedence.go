@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+// configField is a cluster setting that --explain-config reports. Most can come from a flag, and
+// scalar settings also from an ATLAS_* environment variable.
+type configField struct {
+	Path string
+	Flag string
+	Env  string
+	get  func(c *providers.ClusterConfig) string
+	set  func(c *providers.ClusterConfig, value string) error
+}
+
+var clusterConfigFields = []configField{
+	{Path: "region", Flag: "region", Env: "ATLAS_REGION",
+		get: func(c *providers.ClusterConfig) string { return c.Region },
+		set: func(c *providers.ClusterConfig, v string) error { c.Region = v; return nil }},
+	{Path: "nodeCount", Flag: "nodes", Env: "ATLAS_NODES",
+		get: func(c *providers.ClusterConfig) string { return formatNonZero(c.NodeCount) },
+		set: func(c *providers.ClusterConfig, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("node count must be a number, got %q", v)
+			}
+			c.NodeCount = n
+			return nil
+		}},
+	{Path: "version", Flag: "version", Env: "ATLAS_KUBERNETES_VERSION",
+		get: func(c *providers.ClusterConfig) string { return c.Version },
+		set: func(c *providers.ClusterConfig, v string) error { c.Version = v; return nil }},
+	{Path: "instanceType", Flag: "instance-type", Env: "ATLAS_INSTANCE_TYPE",
+		get: func(c *providers.ClusterConfig) string { return c.InstanceType },
+		set: func(c *providers.ClusterConfig, v string) error { c.InstanceType = v; return nil }},
+	{Path: "diskSize", Flag: "disk-size", Env: "ATLAS_DISK_SIZE",
+		get: func(c *providers.ClusterConfig) string { return c.DiskSize },
+		set: func(c *providers.ClusterConfig, v string) error { c.DiskSize = v; return nil }},
+	{Path: "mounts", Flag: "mount",
+		get: func(c *providers.ClusterConfig) string {
+			if len(c.Mounts) == 0 {
+				return ""
+			}
+			return c.Mounts[0].HostPath + ":" + c.Mounts[0].NodePath
+		}},
+	{Path: "networkConfig.ingress.enabled", Flag: "enable-ingress",
+		get: func(c *providers.ClusterConfig) string {
+			return formatEnabled(c.NetworkConfig != nil && c.NetworkConfig.Ingress != nil && c.NetworkConfig.Ingress.Enabled)
+		}},
+	{Path: "networkConfig.loadBalancer.enabled", Flag: "enable-load-balancer",
+		get: func(c *providers.ClusterConfig) string {
+			return formatEnabled(c.NetworkConfig != nil && c.NetworkConfig.LoadBalancer != nil && c.NetworkConfig.LoadBalancer.Enabled)
+		}},
+	{Path: "networkConfig.apiServerPort", Flag: "api-server-port",
+		get: func(c *providers.ClusterConfig) string {
+			if c.NetworkConfig == nil {
+				return ""
+			}
+			return formatNonZero(c.NetworkConfig.APIServerPort)
+		}},
+	{Path: "securityConfig.rbac.enabled", Flag: "enable-rbac",
+		get: func(c *providers.ClusterConfig) string {
+			return formatEnabled(c.SecurityConfig != nil && c.SecurityConfig.RBAC != nil && c.SecurityConfig.RBAC.Enabled)
+		}},
+	{Path: "securityConfig.networkPolicy.enabled", Flag: "enable-network-policy",
+		get: func(c *providers.ClusterConfig) string {
+			return formatEnabled(c.SecurityConfig != nil && c.SecurityConfig.NetworkPolicy != nil && c.SecurityConfig.NetworkPolicy.Enabled)
+		}},
+	{Path: "resourceConfig.monitoring.enabled", Flag: "enable-monitoring",
+		get: func(c *providers.ClusterConfig) string {
+			return formatEnabled(c.ResourceConfig != nil && c.ResourceConfig.Monitoring != nil && c.ResourceConfig.Monitoring.Enabled)
+		}},
+	{Path: "resourceConfig.limits.cpu", Flag: "cpu-limit",
+		get: func(c *providers.ClusterConfig) string {
+			if c.ResourceConfig == nil || c.ResourceConfig.Limits == nil {
+				return ""
+			}
+			return c.ResourceConfig.Limits.CPU
+		}},
+	{Path: "resourceConfig.limits.memory", Flag: "memory-limit",
+		get: func(c *providers.ClusterConfig) string {
+			if c.ResourceConfig == nil || c.ResourceConfig.Limits == nil {
+				return ""
+			}
+			return c.ResourceConfig.Limits.Memory
+		}},
+	{Path: "aws.profile", Flag: "aws-profile",
+		get: func(c *providers.ClusterConfig) string {
+			if c.AWS == nil {
+				return ""
+			}
+			return c.AWS.Profile
+		}},
+	{Path: "aws.subnets",
+		get: func(c *providers.ClusterConfig) string {
+			if c.AWS == nil {
+				return ""
+			}
+			return strings.Join(c.AWS.Subnets, ",")
+		}},
+	{Path: "aws.clusterRoleArn",
+		get: func(c *providers.ClusterConfig) string {
+			if c.AWS == nil {
+				return ""
+			}
+			return c.AWS.ClusterRoleARN
+		}},
+	{Path: "aws.nodeRoleArn",
+		get: func(c *providers.ClusterConfig) string {
+			if c.AWS == nil {
+				return ""
+			}
+			return c.AWS.NodeRoleARN
+		}},
+}
+
+func formatNonZero(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func formatEnabled(enabled bool) string {
+	if enabled {
+		return "true"
+	}
+	return ""
+}
+
+// resolveClusterConfig merges the create settings with flags taking precedence over ATLAS_*
+// environment variables, then the config file or preset, then the provider's defaults file,
+// then flag defaults. It also returns where each setting's value came from.
+func resolveClusterConfig(cmd *cobra.Command, clusterName, providerName string) (*providers.ClusterConfig, map[string]string, error) {
+	flagConfig, err := configFromFlags(cmd, clusterName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	configFile, _ := cmd.Flags().GetString("config")
+	preset, _ := cmd.Flags().GetString("preset")
+	var base *providers.ClusterConfig
+	baseSource := "default"
+	switch {
+	case configFile != "":
+		allowUnknown, _ := cmd.Flags().GetBool("allow-unknown-fields")
+		var ignored []string
+		base, ignored, err = loadClusterConfig(configFile, allowUnknown)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load config file: %w", err)
+		}
+		for _, field := range ignored {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring unknown field %s in %s\n", field, configFile)
+		}
+		baseSource = "config file " + configFile
+	case preset != "":
+		base, err = providers.PresetConfig(preset, providerName)
+		if err != nil {
+			return nil, nil, err
+		}
+		baseSource = "preset " + preset
+	default:
+		// Changed flags are applied last, so nothing from the flags belongs beneath the environment
+		base = &providers.ClusterConfig{}
+	}
+
+	sources := map[string]string{"name": "argument"}
+	for _, field := range clusterConfigFields {
+		sources[field.Path] = "default"
+		if field.get(base) != "" {
+			sources[field.Path] = baseSource
+		}
+	}
+
+	defaultsDir, err := providers.DefaultsDir()
+	if err != nil {
+		return nil, nil, err
+	}
+	defaults, err := providers.LoadProviderDefaults(defaultsDir, providerName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if defaults != nil {
+		before := make(map[string]string)
+		for _, field := range clusterConfigFields {
+			before[field.Path] = field.get(base)
+		}
+		if err := providers.MergeDefaults(base, defaults); err != nil {
+			return nil, nil, err
+		}
+		for _, field := range clusterConfigFields {
+			if before[field.Path] == "" && field.get(base) != "" {
+				sources[field.Path] = "provider defaults " + providers.DefaultsPath(defaultsDir, providerName)
+			}
+		}
+	}
+
+	for _, field := range clusterConfigFields {
+		flag := cmd.Flags().Lookup(field.Flag)
+		if field.get(base) == "" && field.set != nil && flag != nil && flag.DefValue != "" && flag.DefValue != "0" {
+			if err := field.set(base, flag.DefValue); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if field.Env == "" {
+			continue
+		}
+		if value := os.Getenv(field.Env); value != "" {
+			if err := field.set(base, value); err != nil {
+				return nil, nil, fmt.Errorf("invalid %s: %w", field.Env, err)
+			}
+			sources[field.Path] = "env " + field.Env
+		}
+	}
+
+	config := applyFlagOverrides(cmd, base, flagConfig)
+	for _, field := range clusterConfigFields {
+		if cmd.Flags().Changed(field.Flag) {
+			sources[field.Path] = "flag --" + field.Flag
+		}
+	}
+	return config, sources, nil
+}
+
+// configExplanation is one effective setting and where its value came from
+type configExplanation struct {
+	Field  string `json:"field"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+func explainConfig(config *providers.ClusterConfig, sources map[string]string) []configExplanation {
+	explanations := []configExplanation{{Field: "name", Value: config.Name, Source: sources["name"]}}
+	for _, field := range clusterConfigFields {
+		explanations = append(explanations, configExplanation{
+			Field:  field.Path,
+			Value:  field.get(config),
+			Source: sources[field.Path],
+		})
+	}
+	return explanations
+}
+
+func explainClusterConfig(config *providers.ClusterConfig, sources map[string]string) error {
+	explanations := explainConfig(config, sources)
+	if ok, err := writeStructured(os.Stdout, explanations); ok {
+		return err
+	}
+	printConfigExplanation(os.Stdout, explanations)
+	return nil
+}
+
+func printConfigExplanation(w io.Writer, explanations []configExplanation) {
+	t := newTable("FIELD", "VALUE", "SOURCE")
+	for _, explanation := range explanations {
+		value := explanation.Value
+		if value == "" {
+			value = "-"
+		}
+		t.addRow(explanation.Field, value, explanation.Source)
+	}
+	t.render(w)
+}
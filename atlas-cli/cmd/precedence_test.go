@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newCreateFlagsCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "create"}
+	cmd.Flags().StringP("region", "r", "", "")
+	cmd.Flags().IntP("nodes", "n", 1, "")
+	cmd.Flags().StringP("version", "k", "", "")
+	cmd.Flags().String("instance-type", "", "")
+	cmd.Flags().String("disk-size", "", "")
+	cmd.Flags().String("mount", "", "")
+	cmd.Flags().StringP("config", "c", "", "")
+	cmd.Flags().String("preset", "", "")
+	cmd.Flags().Bool("allow-unknown-fields", false, "")
+	cmd.Flags().Bool("enable-ingress", false, "")
+	cmd.Flags().Bool("enable-load-balancer", false, "")
+	cmd.Flags().Bool("enable-rbac", false, "")
+	cmd.Flags().Bool("enable-network-policy", false, "")
+	cmd.Flags().Bool("enable-monitoring", false, "")
+	cmd.Flags().String("api-server-port", "", "")
+	cmd.Flags().String("cpu-limit", "", "")
+	cmd.Flags().String("memory-limit", "", "")
+	cmd.Flags().String("aws-profile", "", "")
+	return cmd
+}
+
+func TestResolveClusterConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configFile := filepath.Join(t.TempDir(), "cluster.yaml")
+	configYAML := `
+name: from-file
+region: us-east-1
+nodeCount: 3
+version: "1.30"
+instanceType: t3.large
+networkConfig:
+  ingress:
+    enabled: true
+`
+	if err := os.WriteFile(configFile, []byte(configYAML), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		env         map[string]string
+		wantValues  map[string]string
+		wantSources map[string]string
+	}{
+		{
+			name: "defaults only",
+			wantValues: map[string]string{
+				"name":      "web",
+				"nodeCount": "1",
+				"region":    "",
+			},
+			wantSources: map[string]string{
+				"name":      "argument",
+				"nodeCount": "default",
+				"region":    "default",
+			},
+		},
+		{
+			name: "environment over defaults, flags over environment",
+			args: []string{"--nodes", "4"},
+			env:  map[string]string{"ATLAS_REGION": "us-west-2", "ATLAS_NODES": "2"},
+			wantValues: map[string]string{
+				"region":    "us-west-2",
+				"nodeCount": "4",
+			},
+			wantSources: map[string]string{
+				"region":    "env ATLAS_REGION",
+				"nodeCount": "flag --nodes",
+			},
+		},
+		{
+			name: "config file under environment and flags",
+			args: []string{"--config", configFile, "--instance-type", "m5.large", "--enable-rbac"},
+			env:  map[string]string{"ATLAS_KUBERNETES_VERSION": "1.31"},
+			wantValues: map[string]string{
+				"name":                          "web",
+				"region":                        "us-east-1",
+				"nodeCount":                     "3",
+				"version":                       "1.31",
+				"instanceType":                  "m5.large",
+				"networkConfig.ingress.enabled": "true",
+				"securityConfig.rbac.enabled":   "true",
+			},
+			wantSources: map[string]string{
+				"region":                        "config file " + configFile,
+				"version":                       "env ATLAS_KUBERNETES_VERSION",
+				"instanceType":                  "flag --instance-type",
+				"networkConfig.ingress.enabled": "config file " + configFile,
+				"securityConfig.rbac.enabled":   "flag --enable-rbac",
+				"diskSize":                      "default",
+			},
+		},
+		{
+			name: "preset fills in what the flags leave out",
+			args: []string{"--preset", "prod-small", "--nodes", "5"},
+			wantValues: map[string]string{
+				"nodeCount": "5",
+				"diskSize":  "40g",
+			},
+			wantSources: map[string]string{
+				"nodeCount": "flag --nodes",
+				"diskSize":  "preset prod-small",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, field := range clusterConfigFields {
+				if field.Env != "" {
+					t.Setenv(field.Env, tt.env[field.Env])
+				}
+			}
+			cmd := newCreateFlagsCmd()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+
+			config, sources, err := resolveClusterConfig(cmd, "web", "local")
+			if err != nil {
+				t.Fatalf("resolveClusterConfig() error = %v", err)
+			}
+			values := make(map[string]string)
+			for _, explanation := range explainConfig(config, sources) {
+				values[explanation.Field] = explanation.Value
+			}
+			for field, want := range tt.wantValues {
+				if values[field] != want {
+					t.Errorf("%s = %q, want %q", field, values[field], want)
+				}
+			}
+			for field, want := range tt.wantSources {
+				if sources[field] != want {
+					t.Errorf("source of %s = %q, want %q", field, sources[field], want)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveClusterConfig_InvalidEnv(t *testing.T) {
+	t.Setenv("ATLAS_NODES", "three")
+	if _, _, err := resolveClusterConfig(newCreateFlagsCmd(), "web", "local"); err == nil {
+		t.Error("resolveClusterConfig() expected error for a non-numeric ATLAS_NODES")
+	}
+}
+
+func TestResolveClusterConfig_ProviderDefaults(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("ATLAS_REGION", "")
+	dir := filepath.Join(home, ".atlas", "providers")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	defaultsYAML := "region: us-west-2\ndiskSize: 50g\nnodeCount: 2\naws:\n  profile: staging\n"
+	if err := os.WriteFile(filepath.Join(dir, "aws.yaml"), []byte(defaultsYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newCreateFlagsCmd()
+	if err := cmd.ParseFlags([]string{"--nodes", "4", "--aws-profile", "prod"}); err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	config, sources, err := resolveClusterConfig(cmd, "web", "aws")
+	if err != nil {
+		t.Fatalf("resolveClusterConfig() error = %v", err)
+	}
+
+	defaultsSource := "provider defaults " + filepath.Join(dir, "aws.yaml")
+	if config.Region != "us-west-2" || sources["region"] != defaultsSource {
+		t.Errorf("region = %q from %q, want us-west-2 from %q", config.Region, sources["region"], defaultsSource)
+	}
+	if config.DiskSize != "50g" || sources["diskSize"] != defaultsSource {
+		t.Errorf("diskSize = %q from %q", config.DiskSize, sources["diskSize"])
+	}
+	if config.NodeCount != 4 || sources["nodeCount"] != "flag --nodes" {
+		t.Errorf("nodeCount = %d from %q, want 4 from flag", config.NodeCount, sources["nodeCount"])
+	}
+	if config.AWS == nil || config.AWS.Profile != "prod" || sources["aws.profile"] != "flag --aws-profile" {
+		t.Errorf("aws.profile = %+v from %q, want prod from flag", config.AWS, sources["aws.profile"])
+	}
+
+	// the local provider has no defaults file, so nothing leaks across providers
+	config, _, err = resolveClusterConfig(newCreateFlagsCmd(), "web", "local")
+	if err != nil {
+		t.Fatalf("resolveClusterConfig() error = %v", err)
+	}
+	if config.Region != "" {
+		t.Errorf("local region = %q, want empty", config.Region)
+	}
+}
@@ -0,0 +1,416 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/operations"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/preview"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+var previewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Manage pull request preview environments",
+	Long: `Create a short-lived cluster per pull request, deploy the change into it and tear it down when
+the pull request closes or its TTL runs out.
+
+Atlas does not run in the background, so expired previews are removed by 'preview cleanup';
+run it on a schedule from CI.`,
+}
+
+var previewCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a preview environment for a pull request",
+	Long: `Create the preview cluster for a pull request from a template, apply manifests and/or install a
+Helm chart into it, and print its endpoint. With --notify-url the endpoint is also posted as JSON,
+for example to a webhook that comments on the pull request.`,
+	Example: `  atlas-cli preview create --pr 123 --manifest deploy/app.yaml
+  atlas-cli preview create --pr 123 --chart ./charts/app --values ci/preview.yaml --ttl 24h`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		pr, _ := cmd.Flags().GetInt("pr")
+		template, _ := cmd.Flags().GetString("template")
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		manifests, _ := cmd.Flags().GetStringArray("manifest")
+		chartName, _ := cmd.Flags().GetString("chart")
+		ttl, _ := cmd.Flags().GetDuration("ttl")
+		notifyURL, _ := cmd.Flags().GetString("notify-url")
+
+		if pr < 1 {
+			return fmt.Errorf("--pr must be a pull request number")
+		}
+		if ttl <= 0 {
+			return fmt.Errorf("--ttl must be positive")
+		}
+		if chartName != "" && providerName != "local" {
+			return fmt.Errorf("--chart is only supported for local previews")
+		}
+
+		store, err := loadDefault(preview.DefaultStorePath, preview.LoadStore)
+		if err != nil {
+			return err
+		}
+		if existing := store.Get(pr); existing != nil {
+			return fmt.Errorf("preview for PR #%d already exists on cluster %s; delete it first", pr, existing.Cluster)
+		}
+
+		clusterName := preview.ClusterName(pr)
+		config, err := providers.PresetConfig(template, providerName)
+		if err != nil {
+			return err
+		}
+		config.Name = clusterName
+		if region != "" {
+			config.Region = region
+		}
+		now := time.Now()
+		env := &preview.Environment{
+			PR:        pr,
+			Cluster:   clusterName,
+			Provider:  providerName,
+			Region:    config.Region,
+			Template:  template,
+			NotifyURL: notifyURL,
+			CreatedAt: now.UTC(),
+			ExpiresAt: now.Add(ttl).UTC(),
+		}
+		if config.Tags == nil {
+			config.Tags = make(map[string]string)
+		}
+		config.Tags["preview-pr"] = strconv.Itoa(pr)
+		config.Tags["preview-expires-at"] = env.ExpiresAt.Format(time.RFC3339)
+		config.BootstrapManifests = previewManifests(manifests)
+
+		p, err := services.GetProvider(providerName, config.Region, awsProfile)
+		if err != nil {
+			return fmt.Errorf("failed to create provider: %w", err)
+		}
+		if err := checkClusterConfig(p, config, false); err != nil {
+			return err
+		}
+		if err := requireCredentials(commandContext(), p); err != nil {
+			return err
+		}
+		reservation, err := reserveClusterPorts(p, config)
+		if err != nil {
+			return err
+		}
+		defer reservation.release()
+
+		ctx := commandContext()
+		release, err := services.GetOperationLimiter().Acquire(ctx, &operations.Operation{
+			Type:     "create",
+			Cluster:  clusterName,
+			Provider: p.GetProviderName(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to acquire operation slot: %w", err)
+		}
+		defer release()
+
+		services.Log(fmt.Sprintf("Creating preview for PR #%d on cluster %s", pr, clusterName))
+		cluster, err := p.CreateCluster(ctx, config)
+		if err != nil {
+			return fmt.Errorf("failed to create preview cluster: %w", err)
+		}
+		reservation.keep()
+		syncInventory(ctx, p, clusterName)
+
+		if chartName != "" {
+			chart := preview.Chart{Chart: chartName}
+			chart.Release, _ = cmd.Flags().GetString("release")
+			if chart.Release == "" {
+				chart.Release = clusterName
+			}
+			chart.Namespace, _ = cmd.Flags().GetString("namespace")
+			chart.Values, _ = cmd.Flags().GetStringArray("values")
+			chart.Set, _ = cmd.Flags().GetStringArray("set")
+
+			services.Log(fmt.Sprintf("Installing chart %s as release %s", chart.Chart, chart.Release))
+			started := time.Now()
+			err := preview.InstallChart(ctx, clusterName, chart)
+			recordDeploy(p.GetProviderName(), clusterName, fmt.Sprintf("helm chart %s as release %s", chart.Chart, chart.Release),
+				map[string]interface{}{"kind": "helm", "chart": chart.Chart, "release": chart.Release, "namespace": chart.Namespace}, started, err)
+			if err != nil {
+				return previewRollback(p, clusterName, err)
+			}
+			env.Release = chart.Release
+		}
+
+		env.Endpoint = cluster.Endpoint
+		store.Put(env)
+		if err := store.Save(); err != nil {
+			return err
+		}
+
+		if notifyURL != "" {
+			if err := notifyPreview(ctx, notifyURL, "created", env); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+
+		if ok, err := writeStructured(os.Stdout, env); ok {
+			return err
+		}
+		fmt.Printf("Preview for PR #%d is ready\n", pr)
+		fmt.Printf("  Cluster:  %s\n", env.Cluster)
+		fmt.Printf("  Endpoint: %s\n", env.Endpoint)
+		fmt.Printf("  Expires:  %s\n", env.ExpiresAt.Local().Format(time.RFC3339))
+		return nil
+	},
+}
+
+var previewDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Tear down a pull request's preview environment",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		pr, _ := cmd.Flags().GetInt("pr")
+		store, err := loadDefault(preview.DefaultStorePath, preview.LoadStore)
+		if err != nil {
+			return err
+		}
+		env := store.Get(pr)
+		if env == nil {
+			return fmt.Errorf("no preview found for PR #%d", pr)
+		}
+
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		if err := deletePreview(commandContext(), env, store, awsProfile); err != nil {
+			return err
+		}
+
+		if ok, err := writeStructured(os.Stdout, map[string]any{"pr": pr, "cluster": env.Cluster, "status": "deleted"}); ok {
+			return err
+		}
+		fmt.Printf("Preview for PR #%d deleted (cluster %s)\n", pr, env.Cluster)
+		return nil
+	},
+}
+
+var previewListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List preview environments",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		store, err := loadDefault(preview.DefaultStorePath, preview.LoadStore)
+		if err != nil {
+			return err
+		}
+		environments := store.List()
+
+		if ok, err := writeStructured(os.Stdout, environments); ok {
+			return err
+		}
+		if len(environments) == 0 {
+			fmt.Println("No previews found")
+			return nil
+		}
+		printPreviewTable(os.Stdout, environments, time.Now())
+		return nil
+	},
+}
+
+var previewCleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Tear down previews whose TTL has expired",
+	Long: `Delete every preview whose TTL has run out. Run this on a schedule, e.g. an hourly CI job, to
+enforce preview TTLs.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		store, err := loadDefault(preview.DefaultStorePath, preview.LoadStore)
+		if err != nil {
+			return err
+		}
+
+		expired := store.Expired(time.Now())
+		if len(expired) == 0 {
+			fmt.Println("No expired previews")
+			return nil
+		}
+
+		ctx := commandContext()
+		var failed int
+		for _, env := range expired {
+			if dryRun {
+				fmt.Printf("Would delete preview for PR #%d (cluster %s, expired %s)\n", env.PR, env.Cluster, env.ExpiresAt.Local().Format(time.RFC3339))
+				continue
+			}
+			if err := deletePreview(ctx, env, store, awsProfile); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				failed++
+				continue
+			}
+			fmt.Printf("Deleted expired preview for PR #%d (cluster %s)\n", env.PR, env.Cluster)
+		}
+		if failed > 0 {
+			return fmt.Errorf("failed to delete %d of %d expired previews", failed, len(expired))
+		}
+		return nil
+	},
+}
+
+// previewManifests turns --manifest values into bootstrap manifests; http(s) values are fetched
+// by URL, anything else is read as a file
+func previewManifests(values []string) []providers.BootstrapManifest {
+	var manifests []providers.BootstrapManifest
+	for _, value := range values {
+		if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+			manifests = append(manifests, providers.BootstrapManifest{URL: value})
+		} else {
+			manifests = append(manifests, providers.BootstrapManifest{File: value})
+		}
+	}
+	return manifests
+}
+
+// previewRollback deletes a preview cluster whose deployment failed so it isn't left running
+// unregistered
+func previewRollback(p providers.Provider, clusterName string, deployErr error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+	defer cancel()
+	if err := p.DeleteCluster(ctx, clusterName); err != nil {
+		return fmt.Errorf("%w (cleanup of cluster %s failed: %v)", deployErr, clusterName, err)
+	}
+	return fmt.Errorf("%w (cluster %s was deleted)", deployErr, clusterName)
+}
+
+// deletePreview deletes env's cluster, runs the registered teardown steps, removes it from the
+// registry and posts the deletion to its notify URL. A cluster that is already gone is not an error.
+func deletePreview(ctx context.Context, env *preview.Environment, store *preview.Store, awsProfile string) error {
+	services := GetServices()
+	p, err := services.GetProvider(env.Provider, env.Region, awsProfile)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+	if err := requireCredentials(ctx, p); err != nil {
+		return err
+	}
+
+	services.Log(fmt.Sprintf("Deleting preview for PR #%d on cluster %s", env.PR, env.Cluster))
+	// only a cluster known to be gone skips the delete; any other lookup failure keeps the record
+	// so the preview can be deleted again
+	_, err = p.GetCluster(ctx, env.Cluster)
+	switch {
+	case err == nil:
+		if err := p.DeleteCluster(ctx, env.Cluster); err != nil {
+			return fmt.Errorf("failed to delete preview cluster %s: %w", env.Cluster, err)
+		}
+	case !errors.Is(err, providers.ErrClusterNotFound):
+		return fmt.Errorf("failed to look up preview cluster %s: %w", env.Cluster, err)
+	}
+	syncInventory(ctx, p, env.Cluster)
+	for _, step := range services.TeardownCluster(ctx, p, env.Cluster) {
+		if step.Error != "" {
+			fmt.Fprintf(os.Stderr, "Warning: teardown step %s failed: %s\n", step.Step, step.Error)
+		}
+	}
+
+	store.Remove(env.PR)
+	if err := store.Save(); err != nil {
+		return err
+	}
+	if env.NotifyURL != "" {
+		if err := notifyPreview(ctx, env.NotifyURL, "deleted", env); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// printPreviewTable renders previews as the text table shown by preview list
+func printPreviewTable(w io.Writer, environments []*preview.Environment, now time.Time) {
+	t := newTable("PR", "CLUSTER", "PROVIDER", "ENDPOINT", "EXPIRES").withSeparator()
+	for _, env := range environments {
+		t.addRow("#"+strconv.Itoa(env.PR), env.Cluster, env.Provider, env.Endpoint, formatExpiry(env, now))
+	}
+	t.render(w)
+}
+
+// formatExpiry renders how long a preview has left, e.g. "in 3h" or "expired"
+func formatExpiry(env *preview.Environment, now time.Time) string {
+	if env.Expired(now) {
+		return "expired"
+	}
+	left := env.ExpiresAt.Sub(now)
+	switch {
+	case left < time.Hour:
+		return fmt.Sprintf("in %dm", int(left.Minutes())+1)
+	case left < 48*time.Hour:
+		return fmt.Sprintf("in %dh", int(left.Hours()))
+	default:
+		return fmt.Sprintf("in %dd", int(left.Hours()/24))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(previewCmd)
+	previewCmd.AddCommand(previewCreateCmd)
+	previewCmd.AddCommand(previewDeleteCmd)
+	previewCmd.AddCommand(previewListCmd)
+	previewCmd.AddCommand(previewCleanupCmd)
+
+	previewCreateCmd.Flags().Int("pr", 0, "Pull request number")
+	previewCreateCmd.MarkFlagRequired("pr")
+	previewCreateCmd.Flags().String("template", "preview", "Cluster preset to create the preview from")
+	previewCreateCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+	previewCreateCmd.Flags().StringP("region", "r", "", "Region to create the preview in")
+	previewCreateCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	previewCreateCmd.Flags().StringArray("manifest", nil, "Manifest file or URL to apply after the cluster is created (repeatable)")
+	previewCreateCmd.Flags().String("chart", "", "Helm chart to install (path, repo/chart or OCI reference)")
+	previewCreateCmd.Flags().String("release", "", "Helm release name (default: the preview cluster name)")
+	previewCreateCmd.Flags().String("namespace", "", "Namespace to install the chart into")
+	previewCreateCmd.Flags().StringArray("values", nil, "Helm values file (repeatable)")
+	previewCreateCmd.Flags().StringArray("set", nil, "Helm value override as key=value (repeatable)")
+	previewCreateCmd.Flags().Duration("ttl", preview.DefaultTTL, "Delete the preview after this long (enforced by 'preview cleanup')")
+	previewCreateCmd.Flags().String("notify-url", "", "POST the preview endpoint as JSON to this URL on create and delete; may be a secret reference such as vault://path#key, which is stored unresolved")
+
+	previewDeleteCmd.Flags().Int("pr", 0, "Pull request number")
+	previewDeleteCmd.MarkFlagRequired("pr")
+	previewDeleteCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+
+	previewCleanupCmd.Flags().Bool("dry-run", false, "Only list the previews that would be deleted")
+	previewCleanupCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+}
+
+// notifyPreview posts event to url, which may be a secret reference. The store keeps the
+// reference, so the webhook's token is never written to disk.
+func notifyPreview(ctx context.Context, url, event string, env *preview.Environment) error {
+	url, err := secretResolver.Resolve(ctx, url)
+	if err != nil {
+		return err
+	}
+	return preview.Notify(ctx, url, event, env)
+}
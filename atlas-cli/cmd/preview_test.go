@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/preview"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+)
+
+func TestFormatExpiry(t *testing.T) {
+	now := time.Date(2025, time.May, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		left time.Duration
+		want string
+	}{
+		{-time.Minute, "expired"},
+		{30 * time.Second, "in 1m"},
+		{45 * time.Minute, "in 46m"},
+		{5 * time.Hour, "in 5h"},
+		{72 * time.Hour, "in 3d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			env := &preview.Environment{ExpiresAt: now.Add(tt.left)}
+			if got := formatExpiry(env, now); got != tt.want {
+				t.Errorf("formatExpiry(%v) = %q, want %q", tt.left, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreviewManifests(t *testing.T) {
+	got := previewManifests([]string{"deploy/app.yaml", "https://example.com/app.yaml"})
+	want := []providers.BootstrapManifest{{File: "deploy/app.yaml"}, {URL: "https://example.com/app.yaml"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("previewManifests() = %+v, want %+v", got, want)
+	}
+}
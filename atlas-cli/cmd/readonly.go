@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// ReadOnlyEnvVar turns on read-only mode for every invocation, e.g. for a dashboard or an auditor's
+// shell. --read-only=false does not override it.
+const ReadOnlyEnvVar = "ATLAS_READ_ONLY"
+
+// mutatingAnnotation marks commands that change clusters or Atlas's state; read-only mode refuses them
+const mutatingAnnotation = "atlas.mutating"
+
+var readOnly bool
+
+// markMutating flags cmds as changing clusters or Atlas's state
+func markMutating(cmds ...*cobra.Command) {
+	for _, cmd := range cmds {
+		if cmd.Annotations == nil {
+			cmd.Annotations = make(map[string]string)
+		}
+		cmd.Annotations[mutatingAnnotation] = "true"
+	}
+}
+
+// isReadOnly reports whether read-only mode is on, from --read-only or $ATLAS_READ_ONLY
+func isReadOnly() bool {
+	if readOnly {
+		return true
+	}
+	enabled, _ := strconv.ParseBool(os.Getenv(ReadOnlyEnvVar))
+	return enabled
+}
+
+// checkReadOnly refuses mutating commands in read-only mode
+func checkReadOnly(cmd *cobra.Command) error {
+	if isReadOnly() && cmd.Annotations[mutatingAnnotation] == "true" {
+		return fmt.Errorf("'%s' changes clusters or Atlas state and is disabled in read-only mode", cmd.CommandPath())
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "Refuse commands that change clusters or Atlas state (also $"+ReadOnlyEnvVar+")")
+
+	markMutating(
+		clusterCreateCmd, clusterDeleteCmd, clusterStartCmd, clusterStopCmd, clusterScaleCmd, clusterRenameCmd, clusterUpgradeCmd, clusterApplyCmd,
+		clusterMaintenanceSetCmd, clusterMaintenanceClearCmd, clusterProtectCmd, clusterAddonsEnableCmd, clusterAddonsDisableCmd,
+		fleetCreateCmd, fleetAddCmd, fleetRemoveCmd, fleetDeleteCmd, fleetStartCmd, fleetStopCmd,
+		previewCreateCmd, previewDeleteCmd, previewCleanupCmd,
+		goldenCreateCmd,
+		cacheWarmCmd, cachePruneCmd,
+		migrateWorkloadsCmd,
+		nodepoolCreateCmd, nodepoolScaleCmd, nodepoolDeleteCmd,
+		operationCancelCmd, operationApproveCmd, operationRejectCmd, operationAnnotateCmd,
+		inventorySyncCmd, dashboardsProvisionCmd, clusterVersionsRefreshCmd,
+		monitorDaemonStartCmd, monitorDaemonStopCmd,
+	)
+}
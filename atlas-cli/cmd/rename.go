@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+	"github.com/spf13/cobra"
+)
+
+var clusterRenameCmd = &cobra.Command{
+	Use:   "rename [old] [new]",
+	Short: "Rename a cluster",
+	Long: `Rename a cluster on the provider and update the matching kubeconfig context.
+Only providers that support renaming in place can be used; minikube profiles and EKS clusters cannot be renamed.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		oldName, newName := args[0], args[1]
+		if oldName == newName {
+			return fmt.Errorf("new name must differ from the current name")
+		}
+		if newName == "" || strings.ContainsAny(newName, " /") {
+			return fmt.Errorf("invalid cluster name: %q", newName)
+		}
+
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+
+		p, err := services.GetProvider(providerName, region, awsProfile)
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+
+		renamer, ok := p.(providers.ClusterRenamer)
+		if !ok {
+			return fmt.Errorf("provider %s does not support renaming clusters", p.GetProviderName())
+		}
+
+		ctx := commandContext()
+		if err := requireCredentials(ctx, p); err != nil {
+			return err
+		}
+		if _, err := p.GetCluster(ctx, newName); err == nil {
+			return fmt.Errorf("cluster %s already exists", newName)
+		}
+
+		warnOutsideMaintenanceWindow(oldName, "renaming")
+		if err := requireApproval(oldName, "rename", "to "+newName); err != nil {
+			return err
+		}
+		services.Log(fmt.Sprintf("Renaming cluster %s to %s", oldName, newName))
+		if err := renamer.RenameCluster(ctx, oldName, newName); err != nil {
+			return fmt.Errorf("failed to rename cluster: %w", err)
+		}
+
+		renameProtection(oldName, newName)
+		syncInventory(ctx, p, oldName)
+		syncInventory(ctx, p, newName)
+		contextRenamed := renameKubeconfigContext(oldName, newName) == nil
+
+		result := map[string]any{
+			"old_name":           oldName,
+			"name":               newName,
+			"kubeconfig_updated": contextRenamed,
+		}
+		if ok, err := writeStructured(os.Stdout, result); ok {
+			return err
+		}
+
+		fmt.Printf("Cluster '%s' renamed to '%s'\n", oldName, newName)
+		if !contextRenamed {
+			fmt.Printf("Warning: kubeconfig context '%s' was not renamed\n", oldName)
+		}
+		return nil
+	},
+}
+
+// renameKubeconfigContext renames the kubeconfig context that points at the cluster
+func renameKubeconfigContext(oldName, newName string) error {
+	output, err := subprocess.CommandContext(context.Background(), "kubectl", "config", "rename-context", oldName, newName).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to rename kubeconfig context: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterRenameCmd)
+
+	clusterRenameCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+	clusterRenameCmd.Flags().StringP("region", "r", "", "Region the cluster runs in")
+	clusterRenameCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+}
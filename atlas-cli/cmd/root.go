@@ -1,19 +1,43 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/ryanjwong/Atlas/atlas-cli/internal/services"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/cleanup"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/errhints"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/secrets"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
 	"github.com/spf13/cobra"
 )
 
-const version = "1.0.0"
+// Build metadata, stamped by tools/release with -ldflags "-X .../cmd.version=..."
+var (
+	version   = "1.0.0"
+	commit    = "unknown"
+	buildDate = "unknown"
+)
 
 var (
-	verbose bool
-	output  string
-	svc     *services.Services
+	verbose          bool
+	output           string
+	maxConcurrentOps int
+	demo             bool
+	recordPath       string
+	replayPath       string
+	svc              *services.Services
+
+	// secretResolver resolves env://, keychain:// and vault:// references in config and flags
+	secretResolver = secrets.NewResolver()
+
+	// rootContext is the context every command runs under. Execute replaces it with one that
+	// interrupts and `operation cancel` (SIGTERM) cancel.
+	rootContext = context.Background()
 )
 
 var rootCmd = &cobra.Command{
@@ -21,22 +45,125 @@ var rootCmd = &cobra.Command{
 	Short:   "Atlas CLI - A command line interface for Atlas",
 	Long:    `Atlas CLI is a command line interface that automates your entire software development lifecycle.`,
 	Version: version,
+	// Execute prints errors itself so recognized failures can be shortened
+	SilenceErrors: true,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := parseOutputFormat(output); err != nil {
+			return err
+		}
+		if err := checkReadOnly(cmd); err != nil {
+			return err
+		}
 		svc = services.NewServices(verbose, output, version)
-		return nil
+		if maxConcurrentOps > 0 {
+			svc.SetMaxConcurrentOperations(maxConcurrentOps)
+		}
+		if demo {
+			dir, err := providers.DefaultDemoDir()
+			if err != nil {
+				return err
+			}
+			svc.EnableDemo(dir)
+		}
+		return useTranscript()
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		if svc == nil {
+			return
+		}
+		for _, tool := range providers.DetectedTools() {
+			svc.Log(fmt.Sprintf("Detected %s %s at %s", tool.Name, tool.Version, tool.Path))
+		}
 	},
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	// a second interrupt exits without waiting for the operation to unwind
+	ctx, cancel := cleanup.NotifyContext(context.Background())
+	rootContext = ctx
+	err := rootCmd.Execute()
+	cancel()
+	// reap child processes and temporary files an interrupted or failed command left behind
+	if cleanupErr := cleanup.Run(); cleanupErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", cleanupErr)
+	}
+	if err != nil {
+		printError(err)
+		code := 1
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			code = exitErr.code
+		}
+		os.Exit(code)
+	}
+}
+
+// printError reports err on stderr. A recognized provider failure is shortened to its cause and
+// a suggested fix; --verbose keeps the raw tool output as well.
+func printError(err error) {
+	hint, ok := errhints.Translate(err)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+	if verbose {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	} else {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", hint)
+	}
+	fmt.Fprintf(os.Stderr, "Hint: %s\n", hint.Fix)
+	if !verbose {
+		fmt.Fprintln(os.Stderr, "Run with --verbose to see the full error output.")
 	}
 }
 
+// exitError makes the process exit with code rather than 1 when err reaches Execute
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
 func init() {
+	if commit != "unknown" {
+		rootCmd.Version = fmt.Sprintf("%s (commit %s, built %s)", version, commit, buildDate)
+	}
+
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
-	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "text", "Output format (text, json)")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "text", "Output format (text, wide, json, yaml, name, jsonpath=..., go-template=...)")
+	rootCmd.PersistentFlags().BoolVar(&noTruncate, "no-truncate", false, "Wrap long table cells instead of truncating them to fit the terminal")
+	rootCmd.PersistentFlags().BoolVar(&demo, "demo", false, "Simulate every provider so commands can be tried without minikube or cloud accounts")
+	rootCmd.PersistentFlags().StringVar(&recordPath, "record", "", "Record every external command and its scrubbed output to this session file")
+	rootCmd.PersistentFlags().StringVar(&replayPath, "replay", "", "Answer external commands from a session file recorded with --record instead of running them")
+	rootCmd.PersistentFlags().IntVar(&maxConcurrentOps, "max-concurrent-operations", 0, "Maximum heavy operations running at once across all atlas-cli processes (default $ATLAS_MAX_CONCURRENT_OPERATIONS or 2)")
+}
+
+// useTranscript routes provider commands through the session file named by --record or --replay
+func useTranscript() error {
+	if recordPath != "" && replayPath != "" {
+		return fmt.Errorf("--record and --replay cannot be used together")
+	}
+
+	var transcript *subprocess.Transcript
+	var err error
+	switch {
+	case recordPath != "":
+		transcript, err = subprocess.RecordTo(recordPath)
+	case replayPath != "":
+		transcript, err = subprocess.ReplayFrom(replayPath)
+	}
+	if err != nil {
+		return err
+	}
+	subprocess.UseTranscript(transcript)
+	return nil
 }
 
 func GetServices() *services.Services {
@@ -60,3 +187,29 @@ func GetOutput() string {
 func GetVersion() string {
 	return version
 }
+
+// commandContext returns a context whose progress reporter matches the selected output format.
+// Machine-readable formats send events to stderr so stdout stays parseable.
+func commandContext() context.Context {
+	var reporter progress.Reporter
+	switch format := currentOutputFormat(); {
+	case format.Kind == outputJSON:
+		reporter = progress.NewJSONReporter(os.Stderr)
+	case format.Kind == outputName || format.structured():
+		reporter = progress.NewTextReporter(os.Stderr)
+	default:
+		reporter = progress.NewTextReporter(os.Stdout)
+	}
+	// cancelling rootContext kills provider subprocesses
+	return progress.WithReporter(rootContext, reporter)
+}
+
+// loadDefault opens the state a package keeps at its default path under ~/.atlas
+func loadDefault[T any](path func() (string, error), load func(string) (T, error)) (T, error) {
+	p, err := path()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return load(p)
+}
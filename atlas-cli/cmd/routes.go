@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/spf13/cobra"
+)
+
+// certificateExpiryWarning is how close to expiry a route's certificate is flagged
+const certificateExpiryWarning = 14 * 24 * time.Hour
+
+var clusterRoutesCmd = &cobra.Command{
+	Use:   "routes [name]",
+	Short: "List the Ingress and Gateway routes a cluster exposes",
+	Long: `List every host and path a cluster exposes through Ingresses and Gateway API HTTPRoutes, with
+the ready endpoints behind each backend service and the issuer and expiry of the TLS certificate
+served for the host. Use it to check exposure after enabling the ingress addon.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		clusterName := args[0]
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+
+		p, err := services.GetProvider(providerName, region, awsProfile)
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+		lister, ok := p.GetMonitor().(monitoring.RouteLister)
+		if !ok {
+			return fmt.Errorf("provider %s does not support listing routes", p.GetProviderName())
+		}
+
+		services.Log(fmt.Sprintf("Listing routes for cluster: %s", clusterName))
+		routes, err := lister.ListRoutes(commandContext(), clusterName)
+		if err != nil {
+			return fmt.Errorf("failed to list routes: %w", err)
+		}
+
+		if routes == nil {
+			routes = []monitoring.Route{}
+		}
+		if ok, err := writeStructured(os.Stdout, routes); ok {
+			return err
+		}
+		if len(routes) == 0 {
+			fmt.Printf("Cluster %s exposes no Ingress or HTTPRoute routes\n", clusterName)
+			return nil
+		}
+		printRoutes(os.Stdout, routes, time.Now())
+		return nil
+	},
+}
+
+func printRoutes(w io.Writer, routes []monitoring.Route, now time.Time) {
+	t := newTable("HOST", "PATH", "BACKEND", "ENDPOINTS", "CERT EXPIRES", "ISSUER")
+	for _, route := range routes {
+		endpoints := fmt.Sprintf("%d/%d %s", route.Health.ReadyEndpoints, route.Health.TotalEndpoints, route.Health.Status)
+		if route.Health.Status == monitoring.BackendMissing {
+			endpoints = monitoring.BackendMissing
+		}
+		expires, issuer := "-", "-"
+		if cert := route.TLS; cert != nil {
+			expires, issuer = certificateExpiry(cert, now), cert.Issuer
+			if cert.Error != "" {
+				issuer = cert.Error
+			}
+		}
+		t.addRow(route.Host, route.Path, route.Backend, endpoints, expires, issuer)
+	}
+	t.render(w)
+}
+
+// certificateExpiry shows when cert expires, flagging certificates that have expired or are
+// about to
+func certificateExpiry(cert *monitoring.Certificate, now time.Time) string {
+	if cert.NotAfter.IsZero() {
+		return "unknown"
+	}
+	date := cert.NotAfter.Format("2006-01-02")
+	left := cert.NotAfter.Sub(now)
+	switch {
+	case left <= 0:
+		return date + " EXPIRED"
+	case left < certificateExpiryWarning:
+		return fmt.Sprintf("%s (%dd left!)", date, int(left.Hours()/24))
+	default:
+		return fmt.Sprintf("%s (%dd)", date, int(left.Hours()/24))
+	}
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterRoutesCmd)
+
+	clusterRoutesCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+	clusterRoutesCmd.Flags().StringP("region", "r", "", "Region the cluster runs in")
+	clusterRoutesCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+)
+
+func TestCertificateExpiry(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		notAfter time.Time
+		want     string
+	}{
+		{notAfter: time.Time{}, want: "unknown"},
+		{notAfter: now.Add(-time.Hour), want: "2025-03-01 EXPIRED"},
+		{notAfter: now.Add(3 * 24 * time.Hour), want: "2025-03-04 (3d left!)"},
+		{notAfter: now.Add(90 * 24 * time.Hour), want: "2025-05-30 (90d)"},
+	}
+
+	for _, tt := range tests {
+		if got := certificateExpiry(&monitoring.Certificate{NotAfter: tt.notAfter}, now); got != tt.want {
+			t.Errorf("certificateExpiry(%v) = %q, want %q", tt.notAfter, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/schema"
+	"github.com/spf13/cobra"
+)
+
+var schemaRegistry = newSchemaRegistry()
+
+func newSchemaRegistry() *schema.Registry {
+	registry := schema.NewRegistry()
+	registry.Register(schema.Type{Name: "cluster-config", Description: "Cluster configuration file (YAML)", Value: providers.ClusterConfig{}, TagName: "yaml"})
+	registry.Register(schema.Type{Name: "cluster", Description: "Cluster output (cluster list/status -o json)", Value: providers.Cluster{}, TagName: "json"})
+	registry.Register(schema.Type{Name: "health-status", Description: "Health check output (monitor -o json)", Value: monitoring.HealthStatus{}, TagName: "json"})
+	registry.Register(schema.Type{Name: "operation-history", Description: "Operation history entry (cluster history -o json)", Value: logsource.OperationHistory{}, TagName: "json"})
+	return registry
+}
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [type]",
+	Short: "Print JSON Schemas for Atlas files and outputs",
+	Long: `Print the JSON Schema for an Atlas configuration file or command output so editors
+and external tools can validate and autocomplete them. Run without arguments to list the available types.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, _ := cmd.Flags().GetString("dir")
+
+		if dir != "" {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create schema directory: %w", err)
+			}
+			for _, name := range schemaRegistry.Names() {
+				s, err := schemaRegistry.Get(name)
+				if err != nil {
+					return err
+				}
+				data, err := json.MarshalIndent(s, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal schema: %w", err)
+				}
+				path := filepath.Join(dir, name+".schema.json")
+				if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+					return fmt.Errorf("failed to write schema file: %w", err)
+				}
+				fmt.Printf("Wrote %s\n", path)
+			}
+			return nil
+		}
+
+		if len(args) == 0 {
+			fmt.Printf("%-20s %s\n", "TYPE", "DESCRIPTION")
+			fmt.Printf("%-20s %s\n", "----", "-----------")
+			for _, t := range schemaRegistry.Types() {
+				fmt.Printf("%-20s %s\n", t.Name, t.Description)
+			}
+			return nil
+		}
+
+		s, err := schemaRegistry.Get(args[0])
+		if err != nil {
+			return err
+		}
+
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal schema: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+
+	schemaCmd.Flags().String("dir", "", "Write every schema to <type>.schema.json files in this directory")
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/rogpeppe/go-internal/testscript"
+)
+
+// TestMain lets testscript run the test binary as atlas-cli inside scripts
+func TestMain(m *testing.M) {
+	testscript.Main(m, map[string]func(){
+		"atlas-cli": Execute,
+	})
+}
+
+// TestScripts runs the end-to-end CLI scenarios in testdata/script. Each script gets its own
+// HOME so operation records and caches never leak between runs.
+func TestScripts(t *testing.T) {
+	testscript.Run(t, testscript.Params{
+		Dir: "testdata/script",
+		Setup: func(env *testscript.Env) error {
+			env.Setenv("HOME", env.WorkDir)
+			return nil
+		},
+		Cmds: map[string]func(ts *testscript.TestScript, neg bool, args []string){
+			// sleep duration gives background commands such as --follow time to poll
+			"sleep": func(ts *testscript.TestScript, neg bool, args []string) {
+				if neg || len(args) != 1 {
+					ts.Fatalf("usage: sleep duration")
+				}
+				d, err := time.ParseDuration(args[0])
+				ts.Check(err)
+				time.Sleep(d)
+			},
+			// capture name regexp sets $name to the regexp's first group in the last command's stdout
+			"capture": func(ts *testscript.TestScript, neg bool, args []string) {
+				if neg || len(args) != 2 {
+					ts.Fatalf("usage: capture name regexp")
+				}
+				re, err := regexp.Compile("(?m)" + args[1])
+				ts.Check(err)
+				match := re.FindStringSubmatch(ts.ReadFile("stdout"))
+				if len(match) < 2 {
+					ts.Fatalf("no match for %q in stdout", args[1])
+				}
+				ts.Setenv(args[0], match[1])
+			},
+		},
+	})
+}
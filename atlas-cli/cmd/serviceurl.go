@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+var clusterServiceURLCmd = &cobra.Command{
+	Use:   "service-url [name] [namespace/]service",
+	Short: "Print the URLs a cluster service is reachable at",
+	Long: `Print the URLs that reach a Kubernetes service from outside the cluster: its load balancer
+address (an ELB hostname on EKS), its node ports, and the hosts of ingresses routing to it. On
+minikube the URLs come from 'minikube service --url'. The namespace defaults to default.`,
+	Example: `  atlas-cli cluster service-url dev web
+  atlas-cli cluster service-url prod shop/frontend -p aws --open`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		clusterName := args[0]
+		namespace, service, found := strings.Cut(args[1], "/")
+		if !found {
+			namespace, service = "default", args[1]
+		}
+		if namespace == "" || service == "" {
+			return fmt.Errorf("invalid service %q; use namespace/service or service", args[1])
+		}
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		open, _ := cmd.Flags().GetBool("open")
+
+		p, err := services.GetProvider(providerName, region, awsProfile)
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+		resolver, ok := p.(providers.ServiceURLResolver)
+		if !ok {
+			return fmt.Errorf("provider %s does not support resolving service URLs", p.GetProviderName())
+		}
+
+		services.Log(fmt.Sprintf("Resolving URLs for service %s/%s in cluster %s", namespace, service, clusterName))
+		urls, err := resolver.ServiceURLs(commandContext(), clusterName, namespace, service)
+		if err != nil {
+			return err
+		}
+
+		ok, err = writeStructured(os.Stdout, urls)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			for _, url := range urls {
+				fmt.Println(url)
+			}
+		}
+
+		if open {
+			if err := openBrowser(urls[0]); err != nil {
+				return fmt.Errorf("failed to open %s: %w", urls[0], err)
+			}
+		}
+		return nil
+	},
+}
+
+// openBrowser opens url with $BROWSER, or the platform's default handler, without waiting for it
+func openBrowser(url string) error {
+	var name string
+	var args []string
+	switch {
+	case os.Getenv("BROWSER") != "":
+		name = os.Getenv("BROWSER")
+	case runtime.GOOS == "darwin":
+		name = "open"
+	case runtime.GOOS == "windows":
+		name, args = "rundll32", []string{"url.dll,FileProtocolHandler"}
+	default:
+		name = "xdg-open"
+	}
+	return exec.Command(name, append(args, url)...).Start()
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterServiceURLCmd)
+
+	clusterServiceURLCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+	clusterServiceURLCmd.Flags().StringP("region", "r", "", "Region the cluster runs in")
+	clusterServiceURLCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	clusterServiceURLCmd.Flags().Bool("open", false, "Open the first URL in a browser")
+}
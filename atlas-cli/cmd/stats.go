@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/timings"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Analyse operation history",
+	Long:  `Summarize how Atlas operations have performed across clusters.`,
+}
+
+var statsOperationsCmd = &cobra.Command{
+	Use:   "operations",
+	Short: "Show how long operations and their phases take",
+	Long: `Summarize operation history: how many operations of each type ran, how many failed and their
+median (p50), p95 and longest durations. Creates record how long each phase took, shown per phase
+with how often it missed its --phase-slo objective, and how many creates with a --max-duration
+budget exceeded it.`,
+	Example: `  atlas-cli stats operations
+  atlas-cli stats operations --since 2w --cluster ci-runner -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		since, _ := cmd.Flags().GetString("since")
+		clusterName, _ := cmd.Flags().GetString("cluster")
+		limit, _ := cmd.Flags().GetInt("limit")
+		var cutoff time.Time
+		if since != "" {
+			age, err := parseAge(since)
+			if err != nil {
+				return err
+			}
+			cutoff = time.Now().Add(-age)
+		}
+
+		provider, err := services.GetProvider("local", "local", "")
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+		histories, err := historySource(provider).GetAllClustersHistory(commandContext(), limit)
+		if err != nil {
+			return fmt.Errorf("failed to get operation history: %w", err)
+		}
+		var ops []*logsource.OperationHistory
+		for name, history := range histories {
+			if clusterName != "" && name != clusterName {
+				continue
+			}
+			for _, op := range history {
+				if op.ClusterName == "" {
+					op.ClusterName = name
+				}
+				if !op.StartedAt.Before(cutoff) {
+					ops = append(ops, op)
+				}
+			}
+		}
+		applyPhaseTimings(ops)
+		stats := timings.Analyze(ops)
+
+		if ok, err := writeStructured(os.Stdout, stats); ok || err != nil {
+			return err
+		}
+		printOperationStats(os.Stdout, stats)
+		return nil
+	},
+}
+
+// printOperationStats renders the text output of stats operations
+func printOperationStats(w io.Writer, stats *timings.Stats) {
+	if len(stats.Operations) == 0 {
+		fmt.Fprintln(w, "No operations found")
+		return
+	}
+	t := newTable("OPERATION", "COUNT", "FAILED", "P50", "P95", "MAX")
+	for _, op := range stats.Operations {
+		t.addRow(op.Name, op.Count, op.Failed, formatMS(op.P50MS), formatMS(op.P95MS), formatMS(op.MaxMS))
+	}
+	t.render(w)
+
+	if len(stats.Phases) > 0 {
+		fmt.Fprintln(w)
+		t := newTable("PHASE", "COUNT", "P50", "P95", "MAX", "SLO BREACHES")
+		for _, phase := range stats.Phases {
+			t.addRow(phase.Name, phase.Count, formatMS(phase.P50MS), formatMS(phase.P95MS), formatMS(phase.MaxMS), phase.SLOBreaches)
+		}
+		t.render(w)
+	}
+	if stats.Budgeted > 0 {
+		fmt.Fprintf(w, "\nBudgets: %d of %d budgeted operations exceeded their --max-duration\n", stats.BudgetExceeded, stats.Budgeted)
+	}
+}
+
+// formatMS formats a duration in milliseconds to a tenth of a second
+func formatMS(ms float64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(100 * time.Millisecond).String()
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.AddCommand(statsOperationsCmd)
+
+	statsOperationsCmd.Flags().String("since", "30d", "Only include operations started within this window, e.g. 12h, 30d, 2w (empty for all)")
+	statsOperationsCmd.Flags().String("cluster", "", "Only include operations for this cluster")
+	statsOperationsCmd.Flags().Int("limit", 1000, "Maximum operations to read per cluster")
+}
@@ -1,8 +1,8 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -11,7 +11,7 @@ var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show current status",
 	Long:  `Display the current status of Atlas CLI and related services.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if GetVerbose() {
 			fmt.Println("Running status command with verbose output...")
 		}
@@ -25,16 +25,15 @@ var statusCmd = &cobra.Command{
 			status["args"] = args
 		}
 
-		if GetOutput() == "json" {
-			jsonOutput, _ := json.MarshalIndent(status, "", "  ")
-			fmt.Println(string(jsonOutput))
-		} else {
-			fmt.Printf("Status: %s\n", status["status"])
-			fmt.Printf("Message: %s\n", status["message"])
-			if len(args) > 0 {
-				fmt.Printf("Additional arguments: %v\n", args)
-			}
+		if ok, err := writeStructured(os.Stdout, status); ok {
+			return err
 		}
+		fmt.Printf("Status: %s\n", status["status"])
+		fmt.Printf("Message: %s\n", status["message"])
+		if len(args) > 0 {
+			fmt.Printf("Additional arguments: %v\n", args)
+		}
+		return nil
 	},
 }
 
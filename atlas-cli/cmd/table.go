@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// noTruncate is set by --no-truncate: cells too wide for the terminal wrap onto extra lines
+// instead of being cut short
+var noTruncate bool
+
+const (
+	tableGap            = "  "
+	minTableColumnWidth = 6
+)
+
+// table lays out rows in columns sized to their contents. When the table is wider than the
+// terminal the widest columns are narrowed until it fits, and cells that no longer fit are
+// truncated, or wrapped with --no-truncate. Output that isn't going to a terminal is never
+// narrowed unless $COLUMNS is set.
+type table struct {
+	headers   []string
+	rows      [][]string
+	separator bool
+}
+
+// newTable creates a table with the given column headers
+func newTable(headers ...string) *table {
+	return &table{headers: headers}
+}
+
+// withSeparator underlines each header with dashes
+func (t *table) withSeparator() *table {
+	t.separator = true
+	return t
+}
+
+// addRow appends a row, formatting each value with fmt.Sprint
+func (t *table) addRow(values ...any) {
+	row := make([]string, len(t.headers))
+	for i, value := range values {
+		if i < len(row) {
+			row[i] = fmt.Sprint(value)
+		}
+	}
+	t.rows = append(t.rows, row)
+}
+
+// render writes the table sized to the terminal
+func (t *table) render(w io.Writer) {
+	t.renderWidth(w, terminalWidth(), noTruncate)
+}
+
+// renderWidth writes the table fitted to width columns, or at its natural width when width is 0
+func (t *table) renderWidth(w io.Writer, width int, wrap bool) {
+	widths := t.columnWidths(width)
+	t.writeRow(w, t.headers, widths, wrap)
+	if t.separator {
+		dashes := make([]string, len(t.headers))
+		for i, header := range t.headers {
+			dashes[i] = strings.Repeat("-", utf8.RuneCountInString(header))
+		}
+		t.writeRow(w, dashes, widths, wrap)
+	}
+	for _, row := range t.rows {
+		t.writeRow(w, row, widths, wrap)
+	}
+}
+
+// columnWidths sizes each column to its widest cell, then takes a character at a time from the
+// widest column until the table fits width. Columns aren't narrowed below their header or
+// minTableColumnWidth, so a very narrow terminal still overflows.
+func (t *table) columnWidths(width int) []int {
+	widths := make([]int, len(t.headers))
+	floors := make([]int, len(t.headers))
+	for i, header := range t.headers {
+		widths[i] = utf8.RuneCountInString(header)
+		floors[i] = max(widths[i], minTableColumnWidth)
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	if width <= 0 {
+		return widths
+	}
+
+	total := len(tableGap) * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	for total > width {
+		widest := -1
+		for i, w := range widths {
+			if w > floors[i] && (widest < 0 || w > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+	return widths
+}
+
+// writeRow writes one row, which takes several lines when a wrapped cell doesn't fit its column
+func (t *table) writeRow(w io.Writer, row []string, widths []int, wrap bool) {
+	cells := make([][]string, len(row))
+	height := 1
+	for i, cell := range row {
+		if wrap {
+			cells[i] = wrapCell(cell, widths[i])
+		} else {
+			cells[i] = []string{truncateString(cell, widths[i])}
+		}
+		height = max(height, len(cells[i]))
+	}
+
+	for line := 0; line < height; line++ {
+		var b strings.Builder
+		for i, lines := range cells {
+			text := ""
+			if line < len(lines) {
+				text = lines[line]
+			}
+			if i > 0 {
+				b.WriteString(tableGap)
+			}
+			b.WriteString(text)
+			if i < len(cells)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(text)))
+			}
+		}
+		fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+	}
+}
+
+// wrapCell splits s into lines of at most width characters, breaking after a space, '-', '/' or
+// ',' when one falls on the line and mid-word otherwise
+func wrapCell(s string, width int) []string {
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return []string{s}
+	}
+	var lines []string
+	for len(runes) > width {
+		cut := width
+		for i := width; i > 0; i-- {
+			if strings.ContainsRune(" -/,", runes[i-1]) {
+				cut = i
+				break
+			}
+		}
+		lines = append(lines, strings.TrimRight(string(runes[:cut]), " "))
+		runes = runes[cut:]
+	}
+	if len(runes) > 0 {
+		lines = append(lines, string(runes))
+	}
+	return lines
+}
+
+// terminalWidth is the width tables are fitted to: $COLUMNS when set, the width of stdout when it
+// is a terminal, and 0, meaning unlimited, otherwise
+func terminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if !isTerminal(os.Stdout) {
+		return 0
+	}
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
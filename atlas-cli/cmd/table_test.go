@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTableRenderWidth(t *testing.T) {
+	newRoutes := func() *table {
+		tbl := newTable("HOST", "BACKEND", "ISSUER").withSeparator()
+		tbl.addRow("shop.example.com", "web:80", "letsencrypt-production")
+		tbl.addRow("api.example.com", "api-gateway-internal:8443", "-")
+		return tbl
+	}
+
+	tests := []struct {
+		name  string
+		width int
+		wrap  bool
+		want  string
+	}{
+		{
+			name: "natural width",
+			want: "HOST              BACKEND                    ISSUER\n" +
+				"----              -------                    ------\n" +
+				"shop.example.com  web:80                     letsencrypt-production\n" +
+				"api.example.com   api-gateway-internal:8443  -\n",
+		},
+		{
+			name:  "truncated to fit",
+			width: 50,
+			want: "HOST             BACKEND          ISSUER\n" +
+				"----             -------          ------\n" +
+				"shop.example...  web:80           letsencrypt-p...\n" +
+				"api.example.com  api-gateway-...  -\n",
+		},
+		{
+			name:  "wrapped to fit",
+			width: 50,
+			wrap:  true,
+			want: "HOST             BACKEND          ISSUER\n" +
+				"----             -------          ------\n" +
+				"shop.example.co  web:80           letsencrypt-\n" +
+				"m                                 production\n" +
+				"api.example.com  api-gateway-     -\n" +
+				"                 internal:8443\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			newRoutes().renderWidth(&out, tt.width, tt.wrap)
+			if out.String() != tt.want {
+				t.Errorf("renderWidth() =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapCell(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  []string
+	}{
+		{"short", 10, []string{"short"}},
+		{"alpha beta gamma", 11, []string{"alpha beta", "gamma"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+	}
+	for _, tt := range tests {
+		got := wrapCell(tt.s, tt.width)
+		if len(got) != len(tt.want) {
+			t.Fatalf("wrapCell(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("wrapCell(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/timings"
+)
+
+// parsePhaseSLOs parses --phase-slo values of the form phase=duration
+func parsePhaseSLOs(values []string) (map[string]time.Duration, error) {
+	slos := make(map[string]time.Duration, len(values))
+	for _, value := range values {
+		phase, duration, ok := strings.Cut(value, "=")
+		if !ok || phase == "" {
+			return nil, fmt.Errorf("invalid --phase-slo %q (want phase=duration, e.g. provision=5m)", value)
+		}
+		slo, err := time.ParseDuration(duration)
+		if err != nil || slo <= 0 {
+			return nil, fmt.Errorf("invalid --phase-slo %q: duration must be positive, e.g. 5m", value)
+		}
+		slos[phase] = slo
+	}
+	return slos, nil
+}
+
+// recordPhaseTimings saves the phases recorder timed with record, warning about each phase that
+// missed its objective in slos. A failure to save is only a warning; the operation already ran.
+func recordPhaseTimings(recorder *timings.Recorder, record *timings.Record, slos map[string]time.Duration) {
+	record.Phases = recorder.Phases()
+	record.SLOBreaches = timings.SLOBreaches(record.Phases, slos)
+	for _, phase := range record.Phases {
+		if slices.Contains(record.SLOBreaches, phase.Name) {
+			fmt.Fprintf(os.Stderr, "Warning: phase %s took %s, over its %s objective\n",
+				phase.Name, (time.Duration(phase.DurationMS) * time.Millisecond).Round(time.Second), slos[phase.Name])
+		}
+	}
+
+	store, err := loadDefault(timings.DefaultStorePath, timings.LoadStore)
+	if err == nil {
+		store.Add(record)
+		err = store.Save()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record phase timings: %v\n", err)
+	}
+}
+
+// applyPhaseTimings adds the recorded phase timings to the operations' details. Timings that
+// can't be read are skipped with a warning.
+func applyPhaseTimings(ops []*logsource.OperationHistory) {
+	store, err := loadDefault(timings.DefaultStorePath, timings.LoadStore)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	store.Apply(ops)
+}
+
+// rollbackCreate deletes a partially created cluster after its creation was canceled or ran out
+// of time. ctx has already ended, so the deletion runs on its own context with ctx's reporter. A
+// cluster the provider never got as far as creating has nothing to roll back.
+func rollbackCreate(ctx context.Context, p providers.Provider, name string) error {
+	rollbackCtx, cancel := context.WithTimeout(progress.WithReporter(context.Background(), progress.FromContext(ctx)), 15*time.Minute)
+	defer cancel()
+	if err := p.DeleteCluster(rollbackCtx, name); err != nil {
+		if _, getErr := p.GetCluster(rollbackCtx, name); getErr != nil {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
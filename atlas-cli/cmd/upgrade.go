@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/operations"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/spf13/cobra"
+)
+
+var clusterUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [name]",
+	Short: "Upgrade a cluster's Kubernetes version",
+	Long: `Upgrade a cluster to a newer Kubernetes version in place.
+
+minikube clusters are restarted with the new version. EKS clusters have their control plane
+upgraded first, then each node group is rolled onto the new version; --max-unavailable or
+--max-unavailable-percent set how many nodes a node group replaces at once. Rerunning an EKS
+upgrade that failed partway resumes with the node groups still behind.
+
+Upgrades follow Kubernetes' version skew policy: clusters can't be downgraded, move one minor
+version at a time, and nodes can't fall more than three minor versions behind the control plane.
+kind and k3d clusters can't be upgraded in place; recreate them with --version instead.`,
+	Example: `  atlas-cli cluster upgrade dev --version v1.31.0
+  atlas-cli cluster upgrade prod -p aws --version 1.31 --max-unavailable-percent 25`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services := GetServices()
+		if services == nil {
+			return fmt.Errorf("services not initialized")
+		}
+
+		clusterName := args[0]
+		providerName, _ := cmd.Flags().GetString("provider")
+		region, _ := cmd.Flags().GetString("region")
+		awsProfile, _ := cmd.Flags().GetString("aws-profile")
+		opts := providers.UpgradeOptions{}
+		opts.Version, _ = cmd.Flags().GetString("version")
+		opts.MaxUnavailable, _ = cmd.Flags().GetInt("max-unavailable")
+		opts.MaxUnavailablePercent, _ = cmd.Flags().GetInt("max-unavailable-percent")
+		if err := opts.Validate(); err != nil {
+			return err
+		}
+
+		services.Log(fmt.Sprintf("Upgrading cluster: %s to %s", clusterName, opts.Version))
+		warnOutsideMaintenanceWindow(clusterName, "upgrading")
+		if err := requireApproval(clusterName, "upgrade", "to "+opts.Version); err != nil {
+			return err
+		}
+
+		p, err := services.GetProvider(providerName, region, awsProfile)
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+		upgrader, ok := p.(providers.ClusterUpgrader)
+		if !ok {
+			return fmt.Errorf("provider %s does not support upgrading clusters; recreate the cluster with --version instead", p.GetProviderName())
+		}
+
+		ctx := commandContext()
+		if err := requireCredentials(ctx, p); err != nil {
+			return err
+		}
+		release, err := services.GetOperationLimiter().Acquire(ctx, &operations.Operation{
+			Type:     "upgrade",
+			Cluster:  clusterName,
+			Provider: p.GetProviderName(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to acquire operation slot: %w", err)
+		}
+		defer release()
+
+		if err := upgrader.UpgradeCluster(ctx, clusterName, opts); err != nil {
+			return fmt.Errorf("failed to upgrade cluster: %w", err)
+		}
+		syncInventory(ctx, p, clusterName)
+
+		result := map[string]any{
+			"name":    clusterName,
+			"status":  "upgraded",
+			"version": opts.Version,
+			"message": fmt.Sprintf("Cluster '%s' upgraded to %s successfully", clusterName, opts.Version),
+		}
+
+		ok, err = writeStructured(os.Stdout, result)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Printf("Cluster '%s' upgraded to %s successfully\n", clusterName, opts.Version)
+		}
+
+		services.Log("Cluster upgrade completed successfully")
+		return nil
+	},
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterUpgradeCmd)
+
+	clusterUpgradeCmd.Flags().StringP("version", "k", "", "Kubernetes version to upgrade to, e.g. v1.31.0")
+	clusterUpgradeCmd.Flags().StringP("provider", "p", "local", "Cloud provider (local, kind, k3d, kubeadm, capi, aws)")
+	clusterUpgradeCmd.Flags().StringP("region", "r", "", "Region the cluster runs in")
+	clusterUpgradeCmd.Flags().String("aws-profile", "", "AWS profile to use (for AWS provider)")
+	clusterUpgradeCmd.Flags().Int("max-unavailable", 0, "Nodes each node group may replace at once (AWS provider)")
+	clusterUpgradeCmd.Flags().Int("max-unavailable-percent", 0, "Percentage of each node group's nodes that may be replaced at once (AWS provider)")
+	clusterUpgradeCmd.MarkFlagRequired("version")
+}
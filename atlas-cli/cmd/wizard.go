@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"gopkg.in/yaml.v3"
+)
+
+// prompter asks questions on a line-oriented terminal, re-asking until an answer validates
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{in: bufio.NewReader(in), out: out}
+}
+
+// ask prints question with its default and returns the validated answer
+func (p *prompter) ask(question, def string, validate func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", question)
+		}
+
+		line, err := p.in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", fmt.Errorf("failed to read answer: %w", err)
+		}
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = def
+		}
+
+		if validate != nil {
+			if err := validate(answer); err != nil {
+				fmt.Fprintf(p.out, "  %v\n", err)
+				continue
+			}
+		}
+		return answer, nil
+	}
+}
+
+func (p *prompter) choose(question string, options []string, def string) (string, error) {
+	return p.ask(fmt.Sprintf("%s (%s)", question, strings.Join(options, ", ")), def, func(answer string) error {
+		for _, option := range options {
+			if answer == option {
+				return nil
+			}
+		}
+		return fmt.Errorf("choose one of: %s", strings.Join(options, ", "))
+	})
+}
+
+func (p *prompter) askInt(question string, def, min, max int) (int, error) {
+	answer, err := p.ask(question, strconv.Itoa(def), func(answer string) error {
+		value, err := strconv.Atoi(answer)
+		if err != nil {
+			return fmt.Errorf("enter a whole number")
+		}
+		if value < min || value > max {
+			return fmt.Errorf("enter a number between %d and %d", min, max)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(answer)
+}
+
+func (p *prompter) confirm(question string, def bool) (bool, error) {
+	defAnswer := "n"
+	if def {
+		defAnswer = "y"
+	}
+	answer, err := p.ask(question+" (y/n)", defAnswer, func(answer string) error {
+		switch strings.ToLower(answer) {
+		case "y", "yes", "n", "no":
+			return nil
+		}
+		return fmt.Errorf("answer y or n")
+	})
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
+
+// wizardMaxNodes mirrors each provider's node count validation
+var wizardMaxNodes = map[string]int{
+	"local": 10,
+	"kind":  10,
+	"k3d":   10,
+	"aws":   100,
+}
+
+// runCreateWizard walks the user through provider, size, networking and monitoring choices and
+// returns the chosen provider name with the resulting config
+func runCreateWizard(p *prompter, clusterName string, factory *providers.ProviderFactory) (string, *providers.ClusterConfig, error) {
+	providerNames := factory.GetSupportedProviders()
+	sort.Strings(providerNames)
+
+	providerName, err := p.choose("Provider", providerNames, "local")
+	if err != nil {
+		return "", nil, err
+	}
+	provider, err := factory.CreateProvider(providerName, "", "")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create provider: %w", err)
+	}
+
+	config := &providers.ClusterConfig{Name: clusterName}
+
+	regions := provider.GetSupportedRegions()
+	config.Region, err = p.choose("Region", regions, regions[0])
+	if err != nil {
+		return "", nil, err
+	}
+
+	versions := provider.GetSupportedVersions()
+	config.Version, err = p.choose("Kubernetes version", versions, versions[0])
+	if err != nil {
+		return "", nil, err
+	}
+
+	maxNodes, exists := wizardMaxNodes[providerName]
+	if !exists {
+		maxNodes = 100
+	}
+	config.NodeCount, err = p.askInt("Number of nodes", 1, 1, maxNodes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if providerName == "aws" {
+		config.InstanceType, err = p.ask("Instance type", "t3.medium", func(answer string) error {
+			probe := &providers.ClusterConfig{Name: clusterName, NodeCount: 1, InstanceType: answer}
+			return provider.ValidateConfig(probe)
+		})
+	} else {
+		config.DiskSize, err = p.ask("Disk size per node", "20g", nil)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	ingress, err := p.confirm("Enable ingress controller?", false)
+	if err != nil {
+		return "", nil, err
+	}
+	loadBalancer, err := p.confirm("Enable load balancer?", false)
+	if err != nil {
+		return "", nil, err
+	}
+	if ingress || loadBalancer {
+		config.NetworkConfig = &providers.NetworkConfig{}
+		if ingress {
+			config.NetworkConfig.Ingress = &providers.IngressConfig{Enabled: true}
+		}
+		if loadBalancer {
+			config.NetworkConfig.LoadBalancer = &providers.LoadBalancerConfig{Enabled: true}
+		}
+	}
+
+	monitoring, err := p.confirm("Enable monitoring (Prometheus)?", false)
+	if err != nil {
+		return "", nil, err
+	}
+	if monitoring {
+		config.ResourceConfig = &providers.ResourceConfig{
+			Monitoring: &providers.MonitoringConfig{
+				Enabled:    true,
+				Prometheus: &providers.PrometheusConfig{Enabled: true},
+			},
+		}
+	}
+
+	savePath, err := p.ask("Save configuration to file (leave empty to skip)", "", nil)
+	if err != nil {
+		return "", nil, err
+	}
+	if savePath != "" {
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal config to YAML: %w", err)
+		}
+		if err := os.WriteFile(savePath, data, 0644); err != nil {
+			return "", nil, fmt.Errorf("failed to write config file: %w", err)
+		}
+		fmt.Fprintf(p.out, "Configuration written to %s\n", savePath)
+	}
+
+	return providerName, config, nil
+}
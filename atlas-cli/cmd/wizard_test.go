@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"gopkg.in/yaml.v3"
+)
+
+func TestPrompter_RepromptsInvalidAnswers(t *testing.T) {
+	var out bytes.Buffer
+	p := newPrompter(strings.NewReader("20\nabc\n3\n"), &out)
+
+	got, err := p.askInt("Number of nodes", 1, 1, 10)
+	if err != nil {
+		t.Fatalf("askInt() unexpected error = %v", err)
+	}
+	if got != 3 {
+		t.Errorf("askInt() = %d, want 3", got)
+	}
+	if strings.Count(out.String(), "Number of nodes") != 3 {
+		t.Errorf("expected the question to be asked three times, output:\n%s", out.String())
+	}
+}
+
+func TestRunCreateWizard(t *testing.T) {
+	savePath := filepath.Join(t.TempDir(), "wizard.yaml")
+	answers := strings.Join([]string{
+		"gcp",   // invalid provider, re-asked
+		"local", // provider
+		"",      // region (default local)
+		"",      // version (default)
+		"2",     // nodes
+		"40g",   // disk size
+		"y",     // ingress
+		"n",     // load balancer
+		"yes",   // monitoring
+		savePath,
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	providerName, config, err := runCreateWizard(newPrompter(strings.NewReader(answers), &out), "wizard-cluster", providers.NewProviderFactory())
+	if err != nil {
+		t.Fatalf("runCreateWizard() unexpected error = %v", err)
+	}
+
+	if providerName != "local" {
+		t.Errorf("provider = %q, want local", providerName)
+	}
+	if config.Name != "wizard-cluster" || config.Region != "local" || config.NodeCount != 2 || config.DiskSize != "40g" {
+		t.Errorf("unexpected config: %+v", config)
+	}
+	if config.NetworkConfig == nil || config.NetworkConfig.Ingress == nil || config.NetworkConfig.LoadBalancer != nil {
+		t.Errorf("expected only ingress enabled, got %+v", config.NetworkConfig)
+	}
+	if config.ResourceConfig == nil || !config.ResourceConfig.Monitoring.Enabled {
+		t.Error("expected monitoring enabled")
+	}
+
+	data, err := os.ReadFile(savePath)
+	if err != nil {
+		t.Fatalf("wizard did not save the config: %v", err)
+	}
+	var saved providers.ClusterConfig
+	if err := yaml.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("saved config is not valid YAML: %v", err)
+	}
+	if saved.NodeCount != 2 {
+		t.Errorf("saved nodeCount = %d, want 2", saved.NodeCount)
+	}
+}
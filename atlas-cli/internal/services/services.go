@@ -2,28 +2,38 @@ package services
 
 import (
 	"fmt"
+	"sync"
 
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/operations"
 	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
 )
 
+// Services is created for every invocation, including --help, so nothing expensive is built
+// until a command asks for it. The provider factory and local provider are built on first use,
+// and providers are kept for reuse by GetProvider.
 type Services struct {
 	verbose         bool
 	output          string
 	version         string
-	providerFactory *providers.ProviderFactory
-	localProvider   *providers.LocalProvider
+	providerFactory func() *providers.ProviderFactory
+	localProvider   func() providers.Provider
+	teardownSteps   []TeardownStep
+	limiter         *operations.Limiter
+
+	mu        sync.Mutex
+	providers map[string]providers.Provider
 }
 
 func NewServices(verbose bool, output string, version string) *Services {
-	localProvider := providers.NewLocalProvider()
-	providerFactory := providers.GetDefaultProviderFactory()
-
 	return &Services{
 		verbose:         verbose,
 		output:          output,
 		version:         version,
-		providerFactory: providerFactory,
-		localProvider:   localProvider,
+		providerFactory: sync.OnceValue(providers.GetDefaultProviderFactory),
+		localProvider:   sync.OnceValue(func() providers.Provider { return providers.NewLocalProvider() }),
+		teardownSteps:   defaultTeardownSteps(),
+		limiter:         operations.NewLimiter("", operations.LimitFromEnv()),
+		providers:       make(map[string]providers.Provider),
 	}
 }
 
@@ -46,18 +56,67 @@ func (s *Services) Log(message string) {
 }
 
 
-func (s *Services) GetLocalProvider() *providers.LocalProvider {
-	return s.localProvider
+func (s *Services) GetLocalProvider() providers.Provider {
+	return s.localProvider()
 }
 
+// GetProvider creates a provider, falling back to the region and profile in the provider's
+// defaults file when they aren't given. The provider is reused by later calls with the same
+// arguments.
 func (s *Services) GetProvider(providerName, region, profile string) (providers.Provider, error) {
-	return s.providerFactory.CreateProvider(providerName, region, profile)
+	key := providerName + "/" + region + "/" + profile
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.providers[key]; ok {
+		return p, nil
+	}
+
+	if dir, err := providers.DefaultsDir(); err == nil {
+		if defaults, err := providers.LoadProviderDefaults(dir, providerName); err == nil && defaults != nil {
+			if region == "" {
+				region = defaults.Region
+			}
+			if profile == "" && defaults.AWS != nil {
+				profile = defaults.AWS.Profile
+			}
+		}
+	}
+	p, err := s.providerFactory().CreateProvider(providerName, region, profile)
+	if err != nil {
+		return nil, err
+	}
+	s.providers[key] = p
+	return p, nil
 }
 
 func (s *Services) GetProviderFactory() *providers.ProviderFactory {
-	return s.providerFactory
+	return s.providerFactory()
+}
+
+func (s *Services) GetOperationLimiter() *operations.Limiter {
+	return s.limiter
+}
+
+func (s *Services) SetMaxConcurrentOperations(limit int) {
+	s.limiter = operations.NewLimiter("", limit)
+}
+
+// EnableDemo swaps every provider, including the local one, for a simulated one backed by dir
+func (s *Services) EnableDemo(dir string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	factory := sync.OnceValue(func() *providers.ProviderFactory {
+		return providers.NewDemoProviderFactory(dir)
+	})
+	s.providerFactory = factory
+	s.localProvider = sync.OnceValue(func() providers.Provider {
+		// the demo factory registers every provider name, so "local" can't be unsupported
+		p, _ := factory().CreateProvider("local", "", "")
+		return p
+	})
+	s.providers = make(map[string]providers.Provider)
 }
 
 func (s *Services) GetSupportedProviders() []string {
-	return s.providerFactory.GetSupportedProviders()
+	return s.providerFactory().GetSupportedProviders()
 }
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/ports"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// TeardownStep removes one kind of resource Atlas attached to a cluster
+type TeardownStep struct {
+	Name string
+	Run  func(ctx context.Context, p providers.Provider, clusterName string) error
+}
+
+// TeardownResult reports the outcome of a single teardown step
+type TeardownResult struct {
+	Step  string `json:"step"`
+	Error string `json:"error,omitempty"`
+}
+
+func defaultTeardownSteps() []TeardownStep {
+	return []TeardownStep{
+		{Name: "monitoring", Run: teardownMonitoring},
+		{Name: "tunnels", Run: teardownTunnels},
+		{Name: "kubeconfig", Run: teardownKubeconfig},
+		{Name: "ports", Run: teardownPorts},
+	}
+}
+
+// RegisterTeardownStep adds a step to the pipeline run after a cluster is deleted
+func (s *Services) RegisterTeardownStep(step TeardownStep) {
+	s.teardownSteps = append(s.teardownSteps, step)
+}
+
+// TeardownCluster walks every registered step, continuing past failures so one broken step
+// doesn't leave the rest behind
+func (s *Services) TeardownCluster(ctx context.Context, p providers.Provider, clusterName string) []TeardownResult {
+	var results []TeardownResult
+	for _, step := range s.teardownSteps {
+		s.Log(fmt.Sprintf("Teardown: %s", step.Name))
+		result := TeardownResult{Step: step.Name}
+		if err := step.Run(ctx, p, clusterName); err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func teardownMonitoring(ctx context.Context, p providers.Provider, clusterName string) error {
+	return p.GetMonitor().StopMonitoring(ctx, clusterName)
+}
+
+func teardownTunnels(ctx context.Context, p providers.Provider, clusterName string) error {
+	if p.GetProviderName() != "local" || runtime.GOOS == "windows" {
+		return nil
+	}
+
+	err := subprocess.CommandContext(ctx, "pkill", "-f", tunnelPattern(clusterName)).Run()
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil
+	}
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return nil
+	}
+	return err
+}
+
+// tunnelPattern matches the command line of clusterName's minikube tunnel and nothing else: not
+// another profile sharing its prefix, and not a process that merely mentions the tunnel
+func tunnelPattern(clusterName string) string {
+	return `^([^ ]*/)?minikube tunnel -p ` + regexp.QuoteMeta(clusterName) + `( |$)`
+}
+
+func teardownPorts(ctx context.Context, p providers.Provider, clusterName string) error {
+	if !providers.RunsOnHost(p.GetProviderName()) {
+		return nil
+	}
+	path, err := ports.DefaultStorePath()
+	if err != nil {
+		return err
+	}
+	return ports.Update(path, func(store *ports.Store) error {
+		store.Release(clusterName)
+		return nil
+	})
+}
+
+func teardownKubeconfig(ctx context.Context, p providers.Provider, clusterName string) error {
+	output, err := subprocess.CommandContext(ctx, "kubectl", "config", "get-contexts", "-o", "name").Output()
+	if err != nil {
+		return nil
+	}
+
+	var failed []string
+	for _, contextName := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if contextName != clusterName && !strings.HasSuffix(contextName, ":cluster/"+clusterName) {
+			continue
+		}
+		if out, err := subprocess.CommandContext(ctx, "kubectl", "config", "delete-context", contextName).CombinedOutput(); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s)", contextName, strings.TrimSpace(string(out))))
+			continue
+		}
+		subprocess.CommandContext(ctx, "kubectl", "config", "delete-cluster", contextName).Run()
+		subprocess.CommandContext(ctx, "kubectl", "config", "unset", "users."+contextName).Run()
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to remove kubeconfig contexts: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/ports"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+)
+
+func TestTunnelPattern(t *testing.T) {
+	pattern := regexp.MustCompile(tunnelPattern("dev.1"))
+	tests := []struct {
+		cmdline string
+		want    bool
+	}{
+		{"minikube tunnel -p dev.1", true},
+		{"minikube tunnel -p dev.1 --cleanup", true},
+		{"/usr/local/bin/minikube tunnel -p dev.1", true},
+		{"minikube tunnel -p dev.10", false},
+		{"minikube tunnel -p dev-1", false},
+		{"minikube tunnel -p devx1", false},
+		{"vim notes-about-minikube tunnel -p dev.1", false},
+		{"bash -c minikube tunnel -p dev.1", false},
+	}
+	for _, tt := range tests {
+		if got := pattern.MatchString(tt.cmdline); got != tt.want {
+			t.Errorf("tunnelPattern(dev.1) matches %q = %v, want %v", tt.cmdline, got, tt.want)
+		}
+	}
+}
+
+func TestTeardownCluster(t *testing.T) {
+	s := &Services{}
+	var ran []string
+	step := func(name string, err error) TeardownStep {
+		return TeardownStep{Name: name, Run: func(ctx context.Context, p providers.Provider, clusterName string) error {
+			ran = append(ran, name+":"+clusterName)
+			return err
+		}}
+	}
+	s.RegisterTeardownStep(step("first", errors.New("boom")))
+	s.RegisterTeardownStep(step("second", nil))
+
+	results := s.TeardownCluster(context.Background(), providers.NewLocalProvider(), "dev")
+
+	// a failing step doesn't stop the ones after it
+	if len(ran) != 2 || ran[0] != "first:dev" || ran[1] != "second:dev" {
+		t.Errorf("ran steps %q, want first and second for dev", ran)
+	}
+	want := []TeardownResult{{Step: "first", Error: "boom"}, {Step: "second"}}
+	if len(results) != len(want) {
+		t.Fatalf("TeardownCluster() = %+v, want %+v", results, want)
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("TeardownCluster()[%d] = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+}
+
+func TestTeardownPorts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path, err := ports.DefaultStorePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ports.Update(path, func(store *ports.Store) error {
+		if err := store.Reserve("dev", ports.PurposeAPIServer, 18443); err != nil {
+			return err
+		}
+		return store.Reserve("test", ports.PurposeAPIServer, 18444)
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if err := teardownPorts(context.Background(), providers.NewLocalProvider(), "dev"); err != nil {
+		t.Fatalf("teardownPorts() error = %v", err)
+	}
+	store, err := ports.LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore() error = %v", err)
+	}
+	if got := store.Allocations(); len(got) != 1 || got[0].Cluster != "test" {
+		t.Errorf("Allocations() after teardown = %+v, want only test's", got)
+	}
+}
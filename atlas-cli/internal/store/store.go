@@ -0,0 +1,167 @@
+// Package store reads and writes the files Atlas keeps its state in under ~/.atlas. Files are
+// only readable by their owner and replaced atomically, and writers hold a lock so concurrent
+// atlas-cli processes take turns.
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/cleanup"
+)
+
+// Dir returns ~/.atlas. It fails when the home directory can't be resolved rather than fall back
+// to a shared directory such as /tmp, where other users could read or plant Atlas's state.
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the directory for Atlas state: %w", err)
+	}
+	return filepath.Join(home, ".atlas"), nil
+}
+
+// Path returns elem joined onto Dir
+func Path(elem ...string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(append([]string{dir}, elem...)...), nil
+}
+
+// MkdirAll creates dir and its parents, readable only by their owner
+func MkdirAll(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	return nil
+}
+
+// Lock takes an exclusive lock on path, waiting for other processes holding it. The lock is a
+// separate path.lock file, as path itself is replaced on every save.
+func Lock(path string) (unlock func(), err error) {
+	if err := MkdirAll(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// LoadJSON decodes the JSON file at path into v. A missing file leaves v untouched.
+func LoadJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// SaveJSON writes v to path as indented JSON, holding path's lock
+func SaveJSON(path string, v any) error {
+	unlock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return saveJSON(path, v)
+}
+
+// UpdateJSON loads path into v, lets update change it and saves it again, holding path's lock
+// throughout so changes made concurrently by other processes aren't lost. Nothing is saved when
+// update fails.
+func UpdateJSON(path string, v any, update func() error) error {
+	unlock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := LoadJSON(path, v); err != nil {
+		return err
+	}
+	if err := update(); err != nil {
+		return err
+	}
+	return saveJSON(path, v)
+}
+
+// WriteFile replaces path with data, holding path's lock
+func WriteFile(path string, data []byte) error {
+	unlock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return writeFile(path, data)
+}
+
+// AppendFile appends data to path, creating the file readable only by its owner, holding path's lock
+func AppendFile(path string, data []byte) error {
+	unlock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReplaceFile replaces path with data without taking path's lock, for callers that already
+// serialize their writes
+func ReplaceFile(path string, data []byte) error {
+	return writeFile(path, data)
+}
+
+func saveJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	return writeFile(path, data)
+}
+
+// writeFile writes data to a temporary file first, so readers never see a partial file. The
+// temporary file is registered for cleanup until it is renamed, so an interrupt doesn't leave it
+// behind.
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	release := cleanup.RemoveOnExit(tmp)
+	defer release()
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	// WriteFile keeps the mode of a file left behind by an interrupted save
+	if err := os.Chmod(tmp, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
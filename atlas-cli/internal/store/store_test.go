@@ -0,0 +1,89 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestSaveJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+
+	var missing map[string]int
+	if err := LoadJSON(path, &missing); err != nil || missing != nil {
+		t.Fatalf("LoadJSON() of a missing file = %v, %v, want nothing loaded", missing, err)
+	}
+
+	if err := SaveJSON(path, map[string]int{"dev": 1}); err != nil {
+		t.Fatalf("SaveJSON() error = %v", err)
+	}
+	var loaded map[string]int
+	if err := LoadJSON(path, &loaded); err != nil || loaded["dev"] != 1 {
+		t.Fatalf("LoadJSON() = %v, %v, want what was saved", loaded, err)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("SaveJSON() wrote mode %v, want 0600", info.Mode().Perm())
+		}
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("SaveJSON() left its temporary file behind: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadJSON(path, &loaded); err == nil {
+		t.Error("LoadJSON() of a corrupt file should fail")
+	}
+}
+
+func TestUpdateJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter.json")
+
+	// each increment is a read-modify-write, so any overlap would lose one
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var count int
+			if err := UpdateJSON(path, &count, func() error { count++; return nil }); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var count int
+	if err := LoadJSON(path, &count); err != nil || count != workers {
+		t.Errorf("count = %d, %v, want %d", count, err, workers)
+	}
+
+	failed := errors.New("refused")
+	if err := UpdateJSON(path, &count, func() error { count = 0; return failed }); err != failed {
+		t.Errorf("UpdateJSON() error = %v, want the update's error", err)
+	}
+	if err := LoadJSON(path, &count); err != nil || count != workers {
+		t.Errorf("a failed update saved count = %d, %v, want %d kept", count, err, workers)
+	}
+}
+
+func TestDir_NoHome(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("the home directory isn't taken from $HOME")
+	}
+	t.Setenv("HOME", "")
+	if path, err := Path("state.json"); err == nil {
+		t.Errorf("Path() without a home directory = %q, want an error", path)
+	}
+}
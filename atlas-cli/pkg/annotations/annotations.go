@@ -0,0 +1,91 @@
+// Package annotations stores notes attached to past operations, such as why a change was rolled
+// back, so they show up alongside the operation in history for postmortems.
+package annotations
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+)
+
+// MetadataKey is the operation metadata key notes are shown under, one "author: text" line each
+const MetadataKey = "notes"
+
+// Note is a note attached to one operation
+type Note struct {
+	Operation string    `json:"operation"`
+	Cluster   string    `json:"cluster"`
+	Text      string    `json:"text"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DefaultStorePath returns the location of the annotations file
+func DefaultStorePath() (string, error) {
+	return store.Path("annotations.json")
+}
+
+// Store holds operation notes on disk
+type Store struct {
+	mu    sync.Mutex
+	path  string
+	notes []*Note
+}
+
+// LoadStore reads the notes at path; a missing file starts empty
+func LoadStore(path string) (*Store, error) {
+	s := &Store{path: path}
+	if err := store.LoadJSON(path, &s.notes); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Add attaches a note to an operation
+func (s *Store) Add(note *Note) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notes = append(s.notes, note)
+}
+
+// Notes returns the notes on the operation ref, oldest first
+func (s *Store) Notes(ref string) []*Note {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var notes []*Note
+	for _, note := range s.notes {
+		if note.Operation == ref {
+			notes = append(notes, note)
+		}
+	}
+	return notes
+}
+
+// Apply sets each operation's Ref and adds its notes to its metadata under MetadataKey
+func (s *Store) Apply(ops []*logsource.OperationHistory) {
+	for _, op := range ops {
+		op.Ref = logsource.OperationRef(op)
+		notes := s.Notes(op.Ref)
+		if len(notes) == 0 {
+			continue
+		}
+		lines := make([]string, len(notes))
+		for i, note := range notes {
+			lines[i] = note.Author + ": " + note.Text
+		}
+		if op.Metadata == nil {
+			op.Metadata = make(map[string]string)
+		}
+		op.Metadata[MetadataKey] = strings.Join(lines, "\n")
+	}
+}
+
+// Save writes the notes back to disk
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return store.SaveJSON(s.path, s.notes)
+}
@@ -0,0 +1,61 @@
+package annotations
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "annotations.json")
+	store, err := LoadStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, time.May, 3, 12, 0, 0, 0, time.UTC)
+	store.Add(&Note{Operation: "op-1", Cluster: "prod", Text: "bad AMI", Author: "alice", CreatedAt: now})
+	store.Add(&Note{Operation: "op-2", Cluster: "prod", Text: "quota", Author: "bob", CreatedAt: now})
+	store.Add(&Note{Operation: "op-1", Cluster: "prod", Text: "rolled back", Author: "bob", CreatedAt: now.Add(time.Minute)})
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = LoadStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes := store.Notes("op-1")
+	if len(notes) != 2 || notes[0].Text != "bad AMI" || notes[1].Text != "rolled back" {
+		t.Errorf("Notes(op-1) = %+v, want both op-1 notes oldest first", notes)
+	}
+	if notes := store.Notes("op-3"); len(notes) != 0 {
+		t.Errorf("Notes(op-3) = %+v, want none", notes)
+	}
+}
+
+func TestApply(t *testing.T) {
+	started := time.Date(2025, time.May, 3, 12, 0, 0, 0, time.UTC)
+	annotated := &logsource.OperationHistory{ID: 1, ClusterName: "prod", OperationType: logsource.OpTypeScale, StartedAt: started}
+	plain := &logsource.OperationHistory{ID: 2, ClusterName: "prod", OperationType: logsource.OpTypeStop, StartedAt: started}
+
+	store, err := LoadStore(filepath.Join(t.TempDir(), "annotations.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := logsource.OperationRef(annotated)
+	store.Add(&Note{Operation: ref, Text: "bad AMI", Author: "alice"})
+	store.Add(&Note{Operation: ref, Text: "rolled back", Author: "bob"})
+	store.Apply([]*logsource.OperationHistory{annotated, plain})
+
+	if annotated.Ref != ref || plain.Ref == "" || plain.Ref == ref {
+		t.Errorf("refs = %q, %q, want distinct refs with %q first", annotated.Ref, plain.Ref, ref)
+	}
+	if got, want := annotated.Metadata[MetadataKey], "alice: bad AMI\nbob: rolled back"; got != want {
+		t.Errorf("notes = %q, want %q", got, want)
+	}
+	if plain.Metadata != nil {
+		t.Errorf("metadata = %v, want none on an operation without notes", plain.Metadata)
+	}
+}
@@ -0,0 +1,241 @@
+// Package approvals stores protected clusters and the approval requests for disruptive operations
+// on them. An operation on a protected cluster only runs once a different user has approved it.
+//
+// Users are identified by their OS account, which, unlike an environment variable, the requester
+// can't choose. The store is a local file only its owner can read or write, so approvals guard
+// against mistakes rather than enforce a security boundary: anyone who can edit the file can
+// bypass them.
+package approvals
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os/user"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+)
+
+// ApprovalTTL is how long an approval can be used after it is granted
+const ApprovalTTL = 24 * time.Hour
+
+// State is the state of an approval request
+type State string
+
+const (
+	StatePending  State = "pending"
+	StateApproved State = "approved"
+	StateRejected State = "rejected"
+	StateUsed     State = "used"
+)
+
+// Request asks to run Operation on Cluster. Detail distinguishes operations of the same type,
+// e.g. the node count of a scale, so an approval only covers what was approved.
+type Request struct {
+	ID          string     `json:"id"`
+	Cluster     string     `json:"cluster"`
+	Operation   string     `json:"operation"`
+	Detail      string     `json:"detail,omitempty"`
+	State       State      `json:"state"`
+	RequestedBy string     `json:"requested_by"`
+	RequestedAt time.Time  `json:"requested_at"`
+	DecidedBy   string     `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+}
+
+func (r *Request) matches(cluster, operation, detail string) bool {
+	return r.Cluster == cluster && r.Operation == operation && r.Detail == detail
+}
+
+// CurrentUser returns the OS user running atlas-cli
+func CurrentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
+
+// DefaultStorePath returns the location of the approvals file
+func DefaultStorePath() (string, error) {
+	return store.Path("approvals.json")
+}
+
+// storeFile is the on-disk layout of the store
+type storeFile struct {
+	Protected map[string]string `json:"protected"`
+	Requests  []*Request        `json:"requests"`
+}
+
+// Store holds protected clusters, mapped to who protected them, and approval requests on disk
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	data    storeFile
+	nowFunc func() time.Time
+}
+
+// LoadStore reads the approvals at path; a missing file starts empty
+func LoadStore(path string) (*Store, error) {
+	s := &Store{path: path, data: storeFile{Protected: make(map[string]string)}, nowFunc: time.Now}
+	if err := store.LoadJSON(path, &s.data); err != nil {
+		return nil, err
+	}
+	if s.data.Protected == nil {
+		s.data.Protected = make(map[string]string)
+	}
+	return s, nil
+}
+
+// Update loads the approvals at path, lets update change them and saves them again, holding the
+// file's lock throughout so concurrent decisions and requests aren't lost. Nothing is saved when
+// update fails.
+func Update(path string, update func(*Store) error) error {
+	s := &Store{path: path, data: storeFile{Protected: make(map[string]string)}, nowFunc: time.Now}
+	return store.UpdateJSON(path, &s.data, func() error {
+		if s.data.Protected == nil {
+			s.data.Protected = make(map[string]string)
+		}
+		return update(s)
+	})
+}
+
+// Protect requires approval for disruptive operations on cluster
+func (s *Store) Protect(cluster, by string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Protected[cluster] = by
+}
+
+// Unprotect stops requiring approval for cluster and drops its open requests
+func (s *Store) Unprotect(cluster string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data.Protected, cluster)
+	kept := s.data.Requests[:0]
+	for _, r := range s.data.Requests {
+		if r.Cluster != cluster || r.State == StateUsed || r.State == StateRejected {
+			kept = append(kept, r)
+		}
+	}
+	s.data.Requests = kept
+}
+
+// Rename moves cluster's protection to its new name
+func (s *Store) Rename(oldName, newName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if by, ok := s.data.Protected[oldName]; ok {
+		delete(s.data.Protected, oldName)
+		s.data.Protected[newName] = by
+	}
+}
+
+// IsProtected reports whether operations on cluster need approval
+func (s *Store) IsProtected(cluster string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.data.Protected[cluster]
+	return ok
+}
+
+// Authorize checks whether by may run operation on cluster now. It consumes a matching approval
+// and returns nil, or returns the pending request, creating it if there is none yet.
+func (s *Store) Authorize(cluster, operation, detail, by string) (*Request, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.nowFunc()
+	var pending *Request
+	for _, r := range s.data.Requests {
+		if !r.matches(cluster, operation, detail) || r.RequestedBy != by {
+			continue
+		}
+		switch r.State {
+		case StateApproved:
+			if r.DecidedAt != nil && now.Sub(*r.DecidedAt) <= ApprovalTTL {
+				r.State = StateUsed
+				return nil, nil
+			}
+		case StatePending:
+			pending = r
+		}
+	}
+	if pending != nil {
+		return pending, nil
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	pending = &Request{
+		ID:          id,
+		Cluster:     cluster,
+		Operation:   operation,
+		Detail:      detail,
+		State:       StatePending,
+		RequestedBy: by,
+		RequestedAt: now,
+	}
+	s.data.Requests = append(s.data.Requests, pending)
+	return pending, nil
+}
+
+// Decide approves or rejects the pending request id. Requesters can't approve their own requests.
+func (s *Store) Decide(id, by string, approve bool) (*Request, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.data.Requests {
+		if r.ID != id {
+			continue
+		}
+		if r.State != StatePending {
+			return nil, fmt.Errorf("request %s is already %s", id, r.State)
+		}
+		if approve && r.RequestedBy == by {
+			return nil, fmt.Errorf("request %s must be approved by someone other than %s, who requested it", id, by)
+		}
+		now := s.nowFunc()
+		r.State = StateRejected
+		if approve {
+			r.State = StateApproved
+		}
+		r.DecidedBy = by
+		r.DecidedAt = &now
+		return r, nil
+	}
+	return nil, fmt.Errorf("approval request %s not found", id)
+}
+
+// Open returns the requests that are pending or approved and not yet used, oldest first
+func (s *Store) Open() []*Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var open []*Request
+	for _, r := range s.data.Requests {
+		if r.State == StatePending || r.State == StateApproved {
+			open = append(open, r)
+		}
+	}
+	sort.SliceStable(open, func(i, j int) bool { return open[i].RequestedAt.Before(open[j].RequestedAt) })
+	return open
+}
+
+// Save writes the approvals back to disk
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return store.SaveJSON(s.path, s.data)
+}
+
+func newID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate request id: %w", err)
+	}
+	return "apr-" + hex.EncodeToString(b), nil
+}
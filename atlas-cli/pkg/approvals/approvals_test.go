@@ -0,0 +1,139 @@
+package approvals
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "approvals.json")
+	store, err := LoadStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, time.May, 3, 12, 0, 0, 0, time.UTC)
+	store.nowFunc = func() time.Time { return now }
+
+	store.Protect("prod", "alice")
+	request, err := store.Authorize("prod", "scale", "to 5 nodes", "bob")
+	if err != nil || request == nil || request.State != StatePending {
+		t.Fatalf("Authorize() = %+v, %v, want a pending request", request, err)
+	}
+	if again, _ := store.Authorize("prod", "scale", "to 5 nodes", "bob"); again.ID != request.ID {
+		t.Errorf("Authorize() again created %s, want the pending %s", again.ID, request.ID)
+	}
+
+	if _, err := store.Decide(request.ID, "bob", true); err == nil {
+		t.Error("Decide() should refuse approval by the requester")
+	}
+	if _, err := store.Decide(request.ID, "alice", true); err != nil {
+		t.Fatalf("Decide() error = %v", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm() != 0600 {
+		t.Errorf("Save() wrote mode %v, want 0600", info.Mode().Perm())
+	}
+
+	store, err = LoadStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.nowFunc = func() time.Time { return now.Add(time.Hour) }
+	if other, _ := store.Authorize("prod", "scale", "to 9 nodes", "bob"); other == nil {
+		t.Error("an approval should only cover the operation that was approved")
+	}
+	if other, _ := store.Authorize("prod", "scale", "to 5 nodes", "carol"); other == nil {
+		t.Error("an approval should only cover the user who requested it")
+	}
+	if granted, _ := store.Authorize("prod", "scale", "to 5 nodes", "bob"); granted != nil {
+		t.Errorf("Authorize() = %+v, want the approval to be used", granted)
+	}
+	if next, _ := store.Authorize("prod", "scale", "to 5 nodes", "bob"); next == nil || next.ID == request.ID {
+		t.Error("an approval should be usable only once")
+	}
+
+	expired, _ := store.Authorize("prod", "delete", "", "bob")
+	store.Decide(expired.ID, "alice", true)
+	store.nowFunc = func() time.Time { return now.Add(time.Hour + ApprovalTTL + time.Minute) }
+	if again, _ := store.Authorize("prod", "delete", "", "bob"); again == nil {
+		t.Error("an approval should expire after ApprovalTTL")
+	}
+
+	store.Rename("prod", "production")
+	if store.IsProtected("prod") || !store.IsProtected("production") {
+		t.Error("Rename() should move protection to the new name")
+	}
+	store.Unprotect("production")
+	if store.IsProtected("production") {
+		t.Error("Unprotect() should remove protection")
+	}
+}
+
+func TestCurrentUser(t *testing.T) {
+	u, err := user.Current()
+	if err != nil {
+		t.Skip("no OS user:", err)
+	}
+	t.Setenv("ATLAS_USER", "mallory")
+	if got := CurrentUser(); got != u.Username {
+		t.Errorf("CurrentUser() = %q, want the OS user %q", got, u.Username)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "approvals.json")
+	err := Update(path, func(store *Store) error {
+		store.Protect("prod", "alice")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	// requests made concurrently each see the others' changes, so none is lost
+	const requesters = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, requesters)
+	for i := 0; i < requesters; i++ {
+		wg.Add(1)
+		go func(detail string) {
+			defer wg.Done()
+			errs <- Update(path, func(store *Store) error {
+				_, err := store.Authorize("prod", "scale", detail, "bob")
+				return err
+			})
+		}(fmt.Sprintf("to %d nodes", i))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+
+	store, err := LoadStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if open := store.Open(); len(open) != requesters {
+		t.Errorf("Open() = %d requests, want %d", len(open), requesters)
+	}
+	if !store.IsProtected("prod") {
+		t.Error("prod should still be protected")
+	}
+
+	if err := Update(path, func(store *Store) error { return errors.New("boom") }); err == nil {
+		t.Error("Update() expected the update's error")
+	}
+}
@@ -0,0 +1,123 @@
+// Package atlas is the embeddable entry point to Atlas. It wraps the provider, monitoring and
+// operation history packages behind a single client with no dependency on the CLI, so other Go
+// programs can create, inspect and monitor clusters programmatically.
+package atlas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+)
+
+// Config selects the provider a Client talks to
+type Config struct {
+	// Provider is the provider name, e.g. "local" or "aws". Defaults to "local".
+	Provider string
+	// Region is the provider region. Empty selects the provider default.
+	Region string
+	// Profile is the credentials profile (AWS only).
+	Profile string
+	// Factory overrides the provider factory, e.g. to register custom providers.
+	Factory *providers.ProviderFactory
+	// Reporter receives provider progress events. Nil discards them unless the caller's
+	// context already carries a reporter.
+	Reporter progress.Reporter
+}
+
+// Client manages clusters for a single provider
+type Client struct {
+	provider providers.Provider
+	reporter progress.Reporter
+}
+
+// New creates a Client for the provider described by cfg
+func New(cfg Config) (*Client, error) {
+	if cfg.Provider == "" {
+		cfg.Provider = "local"
+	}
+	factory := cfg.Factory
+	if factory == nil {
+		factory = providers.NewProviderFactory()
+	}
+
+	provider, err := factory.CreateProvider(cfg.Provider, cfg.Region, cfg.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create provider: %w", err)
+	}
+	return &Client{provider: provider, reporter: cfg.Reporter}, nil
+}
+
+// NewWithProvider creates a Client around an already constructed provider. reporter receives its
+// progress events like Config.Reporter and may be nil.
+func NewWithProvider(provider providers.Provider, reporter progress.Reporter) *Client {
+	return &Client{provider: provider, reporter: reporter}
+}
+
+func (c *Client) context(ctx context.Context) context.Context {
+	if c.reporter == nil {
+		return ctx
+	}
+	return progress.WithReporter(ctx, c.reporter)
+}
+
+// Provider returns the underlying provider for operations not covered by the Client
+func (c *Client) Provider() providers.Provider {
+	return c.provider
+}
+
+// CreateCluster validates config and creates the cluster
+func (c *Client) CreateCluster(ctx context.Context, config *providers.ClusterConfig) (*providers.Cluster, error) {
+	if err := c.provider.ValidateConfig(config); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+	return c.provider.CreateCluster(c.context(ctx), config)
+}
+
+// DeleteCluster deletes the named cluster
+func (c *Client) DeleteCluster(ctx context.Context, name string) error {
+	return c.provider.DeleteCluster(c.context(ctx), name)
+}
+
+// StartCluster starts a stopped cluster
+func (c *Client) StartCluster(ctx context.Context, name string) error {
+	return c.provider.StartCluster(c.context(ctx), name)
+}
+
+// StopCluster stops a running cluster
+func (c *Client) StopCluster(ctx context.Context, name string) error {
+	return c.provider.StopCluster(c.context(ctx), name)
+}
+
+// ScaleCluster changes the number of nodes in a cluster
+func (c *Client) ScaleCluster(ctx context.Context, name string, nodeCount int) error {
+	return c.provider.ScaleCluster(c.context(ctx), name, nodeCount)
+}
+
+// GetCluster returns the current state of a cluster
+func (c *Client) GetCluster(ctx context.Context, name string) (*providers.Cluster, error) {
+	return c.provider.GetCluster(ctx, name)
+}
+
+// ListClusters returns every cluster the provider knows about
+func (c *Client) ListClusters(ctx context.Context) ([]*providers.Cluster, error) {
+	return c.provider.ListClusters(ctx)
+}
+
+// CheckHealth runs a health check against a cluster
+func (c *Client) CheckHealth(ctx context.Context, name string) (*monitoring.HealthStatus, error) {
+	return c.provider.HealthCheck(ctx, name)
+}
+
+// GetMetrics collects resource metrics for a cluster
+func (c *Client) GetMetrics(ctx context.Context, name string) (*monitoring.ClusterMetrics, error) {
+	return c.provider.GetMonitor().GetClusterMetrics(ctx, name)
+}
+
+// History returns up to limit operations recorded for a cluster
+func (c *Client) History(ctx context.Context, name string, limit int) ([]*logsource.OperationHistory, error) {
+	return c.provider.GetLogSource().GetClusterHistory(ctx, name, limit)
+}
@@ -0,0 +1,50 @@
+package atlas_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/atlas"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+)
+
+func Example() {
+	// the fake provider simulates clusters in a state file, so the example runs anywhere; use
+	// atlas.New(atlas.Config{Provider: "local"}) for a real minikube cluster
+	dir, err := os.MkdirTemp("", "atlas-example")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	provider := providers.NewFakeProvider(providers.FakeOptions{StatePath: filepath.Join(dir, "state.json")})
+
+	client := atlas.NewWithProvider(provider, progress.ReporterFunc(func(event progress.Event) {
+		if event.Status == progress.StatusCompleted {
+			fmt.Println("completed", event.Operation, event.Phase)
+		}
+	}))
+
+	ctx := context.Background()
+	cluster, err := client.CreateCluster(ctx, &providers.ClusterConfig{
+		Name:      "sdk-demo",
+		NodeCount: 1,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	health, err := client.CheckHealth(ctx, cluster.Name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(cluster.Name, health.OverallStatus)
+	// Output:
+	// completed create provision
+	// completed create configure
+	// completed create done
+	// sdk-demo healthy
+}
@@ -0,0 +1,144 @@
+// Package cleanup keeps track of what Atlas has to undo before it exits: child processes still
+// running and temporary files it owns. Whoever creates such a resource registers a function that
+// disposes of it and releases the registration once the resource is gone. Run disposes of
+// whatever is left, however the command ended.
+//
+// The first interrupt or SIGTERM only cancels the contexts from NotifyContext, so the operation
+// can unwind and roll back; a second one stops waiting, runs the registered cleanups and exits.
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+)
+
+// Registry holds cleanup functions until they are released or run. It is safe for concurrent use.
+type Registry struct {
+	mu      sync.Mutex
+	next    int
+	entries map[int]entry
+}
+
+type entry struct {
+	name string
+	fn   func() error
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{entries: make(map[int]entry)}
+}
+
+// Register adds fn, described by name in errors, to the registry. The returned function removes
+// it again without running it; call it once the resource has been disposed of normally.
+func (r *Registry) Register(name string, fn func() error) (release func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := r.next
+	r.next++
+	r.entries[id] = entry{name: name, fn: fn}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.entries, id)
+	}
+}
+
+// Len returns the number of registered cleanups
+func (r *Registry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+// Run runs and removes every registered cleanup, newest first, so resources are disposed of in
+// the reverse of the order they were acquired. It returns the failures joined together.
+func (r *Registry) Run() error {
+	r.mu.Lock()
+	ids := make([]int, 0, len(r.entries))
+	for id := range r.entries {
+		ids = append(ids, id)
+	}
+	entries := r.entries
+	r.entries = make(map[int]entry)
+	r.mu.Unlock()
+
+	// ids increase with registration order
+	sort.Sort(sort.Reverse(sort.IntSlice(ids)))
+	var errs []error
+	for _, id := range ids {
+		e := entries[id]
+		if err := e.fn(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to clean up %s: %w", e.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var defaultRegistry = NewRegistry()
+
+// Register adds fn to the process-wide registry that Run and a second interrupt empty
+func Register(name string, fn func() error) (release func()) {
+	return defaultRegistry.Register(name, fn)
+}
+
+// RemoveOnExit registers path, a temporary file or directory, for removal
+func RemoveOnExit(path string) (release func()) {
+	return Register(path, func() error {
+		if err := os.RemoveAll(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	})
+}
+
+// Run runs every cleanup still registered in the process-wide registry
+func Run() error {
+	return defaultRegistry.Run()
+}
+
+// forcedExitCode is the conventional exit status of a process stopped by SIGINT
+const forcedExitCode = 130
+
+var (
+	watchOnce   sync.Once
+	interrupted = make(chan struct{})
+)
+
+// NotifyContext returns a copy of parent that is cancelled on the first SIGINT or SIGTERM. On a
+// second signal the process runs the registered cleanups and exits without waiting for the
+// cancelled work to finish.
+func NotifyContext(parent context.Context) (context.Context, context.CancelFunc) {
+	watchOnce.Do(watchSignals)
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-interrupted:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// watchSignals starts the goroutine that turns signals into cancellation and then a forced exit
+func watchSignals() {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		close(interrupted)
+		<-signals
+		fmt.Fprintln(os.Stderr, "\nInterrupted again, cleaning up and exiting")
+		if err := Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		os.Exit(forcedExitCode)
+	}()
+}
@@ -0,0 +1,66 @@
+package cleanup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	var ran []string
+	register := func(name string, err error) func() {
+		return r.Register(name, func() error {
+			ran = append(ran, name)
+			return err
+		})
+	}
+	register("manifest", nil)
+	release := register("port-forward", nil)
+	register("minikube", errors.New("no such process"))
+	release()
+
+	if r.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2 after releasing one", r.Len())
+	}
+	err := r.Run()
+	if want := []string{"minikube", "manifest"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("Run() ran %v, want %v", ran, want)
+	}
+	if err == nil || !strings.Contains(err.Error(), "failed to clean up minikube: no such process") {
+		t.Errorf("Run() error = %v, want the minikube failure", err)
+	}
+
+	ran = nil
+	if err := r.Run(); err != nil || len(ran) != 0 || r.Len() != 0 {
+		t.Errorf("second Run() = %v and ran %v, want nothing left to run", err, ran)
+	}
+}
+
+func TestRemoveOnExit(t *testing.T) {
+	dir := t.TempDir()
+	kept := filepath.Join(dir, "kept.yaml")
+	removed := filepath.Join(dir, "manifests")
+	if err := os.WriteFile(kept, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(removed, "nested"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	RemoveOnExit(kept)()
+	RemoveOnExit(removed)
+	RemoveOnExit(filepath.Join(dir, "never-created"))
+	if err := Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if _, err := os.Stat(removed); !os.IsNotExist(err) {
+		t.Errorf("%s still exists after Run()", removed)
+	}
+	if _, err := os.Stat(kept); err != nil {
+		t.Errorf("released %s was removed: %v", kept, err)
+	}
+}
@@ -0,0 +1,151 @@
+// Package daemon runs a long-lived atlas-cli command in the background, tracked by a PID file so
+// later invocations can report on it and stop it
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// PIDFile records the process ID of a running daemon
+type PIDFile struct {
+	path string
+}
+
+// NewPIDFile tracks the daemon whose PID is kept at path
+func NewPIDFile(path string) *PIDFile {
+	return &PIDFile{path: path}
+}
+
+// Path returns the location of the PID file
+func (p *PIDFile) Path() string {
+	return p.path
+}
+
+// Running returns the PID of the daemon when one is alive. A PID file left behind by a daemon
+// that crashed is ignored.
+func (p *PIDFile) Running() (int, bool) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || !processAlive(pid) {
+		return 0, false
+	}
+	return pid, true
+}
+
+// Acquire records this process as the daemon. It fails when another live daemon holds the PID
+// file. The returned function removes the file, if it is still ours.
+func (p *PIDFile) Acquire() (func(), error) {
+	if pid, ok := p.Running(); ok && pid != os.Getpid() {
+		return nil, fmt.Errorf("already running as pid %d", pid)
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(p.path), err)
+	}
+	if err := os.WriteFile(p.path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write PID file: %w", err)
+	}
+	return func() {
+		if pid, ok := p.Running(); ok && pid == os.Getpid() {
+			os.Remove(p.path)
+		}
+	}, nil
+}
+
+// Start runs executable with args detached from the terminal, with its output appended to
+// logPath, and waits up to wait for it to take the PID file. The daemon is expected to Acquire
+// the PID file itself once it has started successfully.
+func Start(executable string, args []string, logPath string, pidFile *PIDFile, wait time.Duration) (int, error) {
+	if pid, ok := pidFile.Running(); ok {
+		return 0, fmt.Errorf("already running as pid %d", pid)
+	}
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", filepath.Dir(logPath), err)
+	}
+	logFile, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open daemon log: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(executable, args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start daemon: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	deadline := time.After(wait)
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if pid, ok := pidFile.Running(); ok && pid == cmd.Process.Pid {
+			return pid, nil
+		}
+		select {
+		case err := <-exited:
+			if err == nil {
+				err = errors.New("exited")
+			}
+			return 0, fmt.Errorf("daemon failed to start (%v); see %s", err, logPath)
+		case <-deadline:
+			return cmd.Process.Pid, fmt.Errorf("daemon started as pid %d but has not reported ready after %s; see %s", cmd.Process.Pid, wait, logPath)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop asks the daemon to shut down with SIGTERM and waits up to timeout for it to exit. It
+// returns the PID that was stopped, or 0 when no daemon was running.
+func Stop(pidFile *PIDFile, timeout time.Duration) (int, error) {
+	pid, ok := pidFile.Running()
+	if !ok {
+		os.Remove(pidFile.path)
+		return 0, nil
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return pid, fmt.Errorf("failed to find pid %d: %w", pid, err)
+	}
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		// Windows can't deliver SIGTERM
+		if err := process.Kill(); err != nil {
+			return pid, fmt.Errorf("failed to stop pid %d: %w", pid, err)
+		}
+	}
+
+	deadline := time.Now().Add(timeout)
+	for processAlive(pid) {
+		if time.Now().After(deadline) {
+			return pid, fmt.Errorf("pid %d did not exit within %s", pid, timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	os.Remove(pidFile.path)
+	return pid, nil
+}
+
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
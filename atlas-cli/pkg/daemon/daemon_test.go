@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestHelperDaemon is run as the daemon by TestStartStop
+func TestHelperDaemon(t *testing.T) {
+	path := os.Getenv("ATLAS_TEST_DAEMON_PID_FILE")
+	if path == "" {
+		t.Skip("only run as a helper process")
+	}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM)
+	release, err := NewPIDFile(path).Acquire()
+	if err != nil {
+		os.Exit(2)
+	}
+	<-stop
+	release()
+	os.Exit(0)
+}
+
+func TestPIDFile(t *testing.T) {
+	pidFile := NewPIDFile(filepath.Join(t.TempDir(), "monitor", "daemon.pid"))
+	if _, ok := pidFile.Running(); ok {
+		t.Fatal("Running() without a PID file = true")
+	}
+
+	release, err := pidFile.Acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pid, ok := pidFile.Running(); !ok || pid != os.Getpid() {
+		t.Errorf("Running() = %d, %v, want this process", pid, ok)
+	}
+	release()
+	if _, err := os.Stat(pidFile.Path()); !os.IsNotExist(err) {
+		t.Errorf("PID file still exists after release: %v", err)
+	}
+
+	// a PID file left by a daemon that died doesn't count as running
+	if err := os.WriteFile(pidFile.Path(), []byte("999999999\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pidFile.Running(); ok {
+		t.Error("Running() with a stale PID file = true")
+	}
+	if pid, err := Stop(pidFile, time.Second); pid != 0 || err != nil {
+		t.Errorf("Stop() with a stale PID file = %d, %v, want nothing stopped", pid, err)
+	}
+	if _, err := os.Stat(pidFile.Path()); !os.IsNotExist(err) {
+		t.Error("Stop() left the stale PID file behind")
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	dir := t.TempDir()
+	pidFile := NewPIDFile(filepath.Join(dir, "daemon.pid"))
+	t.Setenv("ATLAS_TEST_DAEMON_PID_FILE", pidFile.Path())
+
+	pid, err := Start(os.Args[0], []string{"-test.run=^TestHelperDaemon$"}, filepath.Join(dir, "daemon.log"), pidFile, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if running, ok := pidFile.Running(); !ok || running != pid {
+		t.Fatalf("Running() = %d, %v, want the started daemon %d", running, ok, pid)
+	}
+	if _, err := Start(os.Args[0], []string{"-test.run=^TestHelperDaemon$"}, filepath.Join(dir, "daemon.log"), pidFile, time.Second); err == nil {
+		t.Error("Start() while a daemon is running should fail")
+	}
+
+	stopped, err := Stop(pidFile, 10*time.Second)
+	if err != nil || stopped != pid {
+		t.Fatalf("Stop() = %d, %v, want %d stopped", stopped, err, pid)
+	}
+	if _, ok := pidFile.Running(); ok {
+		t.Error("daemon still running after Stop()")
+	}
+	if data, err := os.ReadFile(pidFile.Path()); err == nil {
+		t.Errorf("PID file still holds %s after Stop()", strconv.Quote(string(data)))
+	}
+}
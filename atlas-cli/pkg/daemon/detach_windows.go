@@ -0,0 +1,8 @@
+//go:build windows
+
+package daemon
+
+import "os/exec"
+
+// detach does nothing on Windows, where a child already outlives its console's parent
+func detach(cmd *exec.Cmd) {}
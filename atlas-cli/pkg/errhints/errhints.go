@@ -0,0 +1,111 @@
+// Package errhints recognizes common failures in the raw minikube and aws output that provider
+// errors carry, so they can be reported as a short cause with a suggested fix instead.
+package errhints
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Hint explains a recognized failure
+type Hint struct {
+	// Context is what was being done when it failed, e.g. "failed to create cluster dev"
+	Context string `json:"context,omitempty"`
+	Cause   string `json:"cause"`
+	Fix     string `json:"fix"`
+}
+
+// String renders the hint as a one-line summary of the failure
+func (h Hint) String() string {
+	if h.Context == "" {
+		return h.Cause
+	}
+	return h.Context + ": " + h.Cause
+}
+
+type rule struct {
+	pattern *regexp.Regexp
+	// hint builds the explanation from the pattern's match in message
+	hint func(message string, match []string) Hint
+}
+
+// deniedAction finds the IAM action in an AWS authorization failure
+var deniedAction = regexp.MustCompile(`not authorized to perform:?\s*([\w-]+:\w+)`)
+
+// rules are checked in order, so more specific failures come before general ones
+var rules = []rule{
+	{
+		pattern: regexp.MustCompile(`(?i)cannot connect to the docker daemon|is the docker daemon running|PROVIDER_DOCKER_NOT_RUNNING|docker daemon is not running`),
+		hint: func(string, []string) Hint {
+			return Hint{Cause: "Docker is not running", Fix: "start Docker Desktop or the docker service, then try again"}
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)RSRC_INSUFFICIENT_\w*MEMORY|insufficient memory|requested memory allocation \(?(\d+\s*[MG]i?B)?`),
+		hint: func(_ string, match []string) Hint {
+			cause := "not enough memory for the cluster"
+			if len(match) > 1 && match[1] != "" {
+				cause = "not enough memory for the requested " + match[1]
+			}
+			return Hint{Cause: cause, Fix: "lower --memory, or give Docker more memory in its resource settings"}
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)ExpiredToken|security token included in the request is expired|token has expired|sso session .*expired`),
+		hint: func(string, []string) Hint {
+			return Hint{Cause: "AWS credentials have expired", Fix: "run 'aws sso login' (with --profile if you use one) or refresh your credentials, then check them with 'atlas-cli auth check'"}
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)unable to locate credentials|NoCredentialProviders`),
+		hint: func(string, []string) Hint {
+			return Hint{Cause: "no AWS credentials found", Fix: "run 'aws configure' or 'aws sso login', or pass --aws-profile"}
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)not authorized to perform|AccessDenied\w*|UnauthorizedOperation`),
+		hint: func(message string, _ []string) Hint {
+			cause := "AWS denied permission for the request"
+			if action := deniedAction.FindStringSubmatch(message); action != nil {
+				cause = "AWS denied permission for " + action[1]
+			}
+			return Hint{Cause: cause, Fix: "ask your AWS administrator to grant it to your IAM role, or use a profile that has it"}
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`exec: "(minikube|aws|kubectl|helm)": executable file not found`),
+		hint: func(_ string, match []string) Hint {
+			return Hint{Cause: match[1] + " is not installed", Fix: "install " + match[1] + " and make sure it is on your PATH"}
+		},
+	},
+}
+
+// Translate returns a hint for err when its message contains a recognized failure
+func Translate(err error) (Hint, bool) {
+	if err == nil {
+		return Hint{}, false
+	}
+	message := err.Error()
+	for _, r := range rules {
+		match := r.pattern.FindStringSubmatch(message)
+		if match == nil {
+			continue
+		}
+		hint := r.hint(message, match)
+		hint.Context = wrappingContext(message, match[0])
+		return hint, true
+	}
+	return Hint{}, false
+}
+
+// wrappingContext returns the text in front of the raw output, e.g. "failed to create cluster
+// dev" from "failed to create cluster dev: exit status 80\nOutput: ...", or "" when the message
+// starts with the output itself
+func wrappingContext(message, match string) string {
+	firstLine, _, _ := strings.Cut(message, "\n")
+	prefix, _, found := strings.Cut(firstLine, ": ")
+	if !found || strings.Contains(prefix, match) {
+		return ""
+	}
+	return prefix
+}
@@ -0,0 +1,101 @@
+package errhints
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		want    string
+		fix     string
+		context string
+	}{
+		{
+			name: "docker not running",
+			err: fmt.Errorf("failed to create cluster dev: exit status 69\nOutput: %s",
+				"X Exiting due to PROVIDER_DOCKER_NOT_RUNNING: \"docker version --format -:\" exit status 1: Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?"),
+			want:    "Docker is not running",
+			fix:     "start Docker",
+			context: "failed to create cluster dev",
+		},
+		{
+			name:    "insufficient memory",
+			err:     errors.New("failed to start cluster dev: exit status 29\nOutput: X Exiting due to RSRC_INSUFFICIENT_CONTAINER_MEMORY: docker only has 1987MiB available"),
+			want:    "not enough memory for the cluster",
+			fix:     "--memory",
+			context: "failed to start cluster dev",
+		},
+		{
+			name: "requested memory",
+			err:  errors.New("Requested memory allocation (8192MB) is more than your system limit 4096MB"),
+			want: "not enough memory for the requested 8192MB",
+			fix:  "--memory",
+		},
+		{
+			name:    "expired aws token",
+			err:     errors.New("failed to list clusters: An error occurred (ExpiredTokenException) when calling the ListClusters operation: The security token included in the request is expired"),
+			want:    "AWS credentials have expired",
+			fix:     "aws sso login",
+			context: "failed to list clusters",
+		},
+		{
+			name: "iam permission denied",
+			err:  errors.New("failed to create node group gpu: An error occurred (AccessDeniedException) when calling the CreateNodegroup operation: User: arn:aws:iam::123456789012:user/dev is not authorized to perform: eks:CreateNodegroup on resource: arn:aws:eks:us-west-2:123456789012:cluster/prod"),
+			want: "AWS denied permission for eks:CreateNodegroup",
+			fix:  "IAM",
+		},
+		{
+			name: "missing credentials",
+			err:  errors.New("credential check failed: Unable to locate credentials. You can configure credentials by running \"aws configure\"."),
+			want: "no AWS credentials found",
+			fix:  "aws configure",
+		},
+		{
+			name: "missing tool",
+			err:  errors.New("failed to list clusters: error getting profiles: exec: \"minikube\": executable file not found in $PATH"),
+			want: "minikube is not installed",
+			fix:  "install minikube",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hint, ok := Translate(tt.err)
+			if !ok {
+				t.Fatalf("Translate() did not recognize %q", tt.err)
+			}
+			if hint.Cause != tt.want {
+				t.Errorf("Cause = %q, want %q", hint.Cause, tt.want)
+			}
+			if !strings.Contains(hint.Fix, tt.fix) {
+				t.Errorf("Fix = %q, want it to mention %q", hint.Fix, tt.fix)
+			}
+			if tt.context != "" && hint.Context != tt.context {
+				t.Errorf("Context = %q, want %q", hint.Context, tt.context)
+			}
+		})
+	}
+}
+
+func TestTranslate_Unrecognized(t *testing.T) {
+	for _, err := range []error{nil, errors.New("cluster dev does not exist")} {
+		if hint, ok := Translate(err); ok {
+			t.Errorf("Translate(%v) = %+v, want no hint", err, hint)
+		}
+	}
+}
+
+func TestHint_String(t *testing.T) {
+	hint := Hint{Context: "failed to create cluster dev", Cause: "Docker is not running"}
+	if got := hint.String(); got != "failed to create cluster dev: Docker is not running" {
+		t.Errorf("String() = %q", got)
+	}
+	hint.Context = ""
+	if got := hint.String(); got != "Docker is not running" {
+		t.Errorf("String() = %q", got)
+	}
+}
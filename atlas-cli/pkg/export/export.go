@@ -0,0 +1,95 @@
+// Package export writes flat tables of Atlas data (operation history, events) in formats that
+// spreadsheets and analytics pipelines read directly: CSV and Parquet.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ColumnType is the type of every value in a column
+type ColumnType int
+
+const (
+	String ColumnType = iota
+	Int64
+	Double
+	Timestamp
+)
+
+// Column describes one column of a table
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Table is a set of rows sharing the same columns. Each value must be nil or match its column's
+// type: string, int64, float64 or time.Time.
+type Table struct {
+	Columns []Column
+	Rows    [][]any
+}
+
+// Format is a supported export format
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatParquet Format = "parquet"
+)
+
+// Write renders t to w in format
+func Write(w io.Writer, t *Table, format Format) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, t)
+	case FormatParquet:
+		return WriteParquet(w, t)
+	default:
+		return fmt.Errorf("unsupported export format %q (want csv or parquet)", format)
+	}
+}
+
+// WriteCSV writes t with a header row. Timestamps are RFC 3339 in UTC and nil values are empty.
+func WriteCSV(w io.Writer, t *Table) error {
+	writer := csv.NewWriter(w)
+	header := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		header[i] = column.Name
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	record := make([]string, len(t.Columns))
+	for _, row := range t.Rows {
+		for i, value := range row {
+			record[i] = formatCSVValue(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatCSVValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
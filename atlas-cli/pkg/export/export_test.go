@@ -0,0 +1,68 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func testTable() *Table {
+	started := time.Date(2025, time.March, 4, 9, 15, 0, 0, time.UTC)
+	return &Table{
+		Columns: []Column{{"cluster", String}, {"id", Int64}, {"duration_ms", Double}, {"started_at", Timestamp}},
+		Rows: [][]any{
+			{"dev", int64(1), 850.5, started},
+			{"prod, eu", int64(2), nil, started.Add(time.Hour)},
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteCSV(&out, testTable()); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	want := `cluster,id,duration_ms,started_at
+dev,1,850.5,2025-03-04T09:15:00Z
+"prod, eu",2,,2025-03-04T10:15:00Z
+`
+	if out.String() != want {
+		t.Errorf("WriteCSV() =\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteParquet(&out, testTable()); err != nil {
+		t.Fatalf("WriteParquet() error = %v", err)
+	}
+
+	data := out.Bytes()
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatalf("Parquet file is missing its PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	if footerLen <= 0 || footerLen > len(data)-12 {
+		t.Fatalf("footer length %d out of range for %d byte file", footerLen, len(data))
+	}
+	footer := data[len(data)-8-footerLen : len(data)-8]
+	for _, name := range []string{"cluster", "duration_ms", "started_at", "atlas-cli"} {
+		if !bytes.Contains(footer, []byte(name)) {
+			t.Errorf("footer does not mention %q", name)
+		}
+	}
+
+	if err := WriteParquet(&out, &Table{Columns: []Column{{"x", Int64}}, Rows: [][]any{{"not an int"}}}); err == nil {
+		t.Error("WriteParquet() should reject a value that doesn't match its column type")
+	}
+}
+
+func TestEncodeRLELevels(t *testing.T) {
+	got := encodeRLELevels([]bool{true, true, true, false, true})
+	want := []byte{3 << 1, 1, 1 << 1, 0, 1 << 1, 1}
+	if !bytes.Equal(got, want) {
+		t.Errorf("encodeRLELevels() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,301 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// WriteParquet writes t as an uncompressed Parquet file with a single row group. Every column is
+// optional and PLAIN encoded, which every Parquet reader supports; the tables Atlas exports are
+// small enough that compression isn't worth a dependency.
+func WriteParquet(w io.Writer, t *Table) error {
+	var file bytes.Buffer
+	file.WriteString("PAR1")
+
+	chunks := make([]columnChunk, len(t.Columns))
+	for i, column := range t.Columns {
+		page, err := encodeDataPage(t, i)
+		if err != nil {
+			return err
+		}
+		header := encodePageHeader(len(page), len(t.Rows))
+
+		chunks[i] = columnChunk{
+			column: column,
+			offset: int64(file.Len()),
+			size:   int64(len(header) + len(page)),
+			rows:   int64(len(t.Rows)),
+		}
+		file.Write(header)
+		file.Write(page)
+	}
+
+	metadata := encodeFileMetaData(t, chunks)
+	file.Write(metadata)
+	binary.Write(&file, binary.LittleEndian, uint32(len(metadata)))
+	file.WriteString("PAR1")
+
+	if _, err := w.Write(file.Bytes()); err != nil {
+		return fmt.Errorf("failed to write Parquet file: %w", err)
+	}
+	return nil
+}
+
+// Parquet enum values from parquet.thrift
+const (
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	repetitionOptional = 1
+
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	pageTypeData = 0
+)
+
+type columnChunk struct {
+	column Column
+	offset int64
+	size   int64
+	rows   int64
+}
+
+func physicalType(t ColumnType) int32 {
+	switch t {
+	case Int64, Timestamp:
+		return parquetInt64
+	case Double:
+		return parquetDouble
+	default:
+		return parquetByteArray
+	}
+}
+
+// encodeDataPage encodes column i as a v1 data page: RLE definition levels (1 for present,
+// 0 for null) followed by the PLAIN values of the non-null rows
+func encodeDataPage(t *Table, i int) ([]byte, error) {
+	levels := make([]bool, len(t.Rows))
+	var values bytes.Buffer
+	for r, row := range t.Rows {
+		value := row[i]
+		if value == nil {
+			continue
+		}
+		levels[r] = true
+
+		switch v := value.(type) {
+		case string:
+			if t.Columns[i].Type == String {
+				binary.Write(&values, binary.LittleEndian, uint32(len(v)))
+				values.WriteString(v)
+				continue
+			}
+		case int64:
+			if t.Columns[i].Type == Int64 {
+				binary.Write(&values, binary.LittleEndian, v)
+				continue
+			}
+		case float64:
+			if t.Columns[i].Type == Double {
+				binary.Write(&values, binary.LittleEndian, math.Float64bits(v))
+				continue
+			}
+		case time.Time:
+			if t.Columns[i].Type == Timestamp {
+				binary.Write(&values, binary.LittleEndian, v.UnixMilli())
+				continue
+			}
+		}
+		return nil, fmt.Errorf("column %s: value %v of type %T does not match the column type", t.Columns[i].Name, value, value)
+	}
+
+	encodedLevels := encodeRLELevels(levels)
+	var page bytes.Buffer
+	binary.Write(&page, binary.LittleEndian, uint32(len(encodedLevels)))
+	page.Write(encodedLevels)
+	page.Write(values.Bytes())
+	return page.Bytes(), nil
+}
+
+// encodeRLELevels encodes bit-width-1 levels with the RLE/bit-packing hybrid, using RLE runs only
+func encodeRLELevels(levels []bool) []byte {
+	var out []byte
+	for start := 0; start < len(levels); {
+		end := start
+		for end < len(levels) && levels[end] == levels[start] {
+			end++
+		}
+		out = binary.AppendUvarint(out, uint64(end-start)<<1)
+		if levels[start] {
+			out = append(out, 1)
+		} else {
+			out = append(out, 0)
+		}
+		start = end
+	}
+	return out
+}
+
+func encodePageHeader(pageSize, numValues int) []byte {
+	var c compactWriter
+	c.i32Field(1, pageTypeData)
+	c.i32Field(2, int32(pageSize))
+	c.i32Field(3, int32(pageSize))
+	c.structField(5, func() {
+		c.i32Field(1, int32(numValues))
+		c.i32Field(2, encodingPlain)
+		c.i32Field(3, encodingRLE)
+		c.i32Field(4, encodingRLE)
+	})
+	c.stop()
+	return c.buf.Bytes()
+}
+
+func encodeFileMetaData(t *Table, chunks []columnChunk) []byte {
+	var c compactWriter
+	c.i32Field(1, 1)
+	c.listField(2, compactStruct, len(t.Columns)+1, func(i int) {
+		c.structElement(func() {
+			if i == 0 {
+				c.stringField(4, "schema")
+				c.i32Field(5, int32(len(t.Columns)))
+				return
+			}
+			column := t.Columns[i-1]
+			c.i32Field(1, physicalType(column.Type))
+			c.i32Field(3, repetitionOptional)
+			c.stringField(4, column.Name)
+			switch column.Type {
+			case String:
+				c.i32Field(6, convertedUTF8)
+			case Timestamp:
+				c.i32Field(6, convertedTimestampMillis)
+			}
+		})
+	})
+	c.i64Field(3, int64(len(t.Rows)))
+
+	var totalSize int64
+	for _, chunk := range chunks {
+		totalSize += chunk.size
+	}
+	c.listField(4, compactStruct, 1, func(int) {
+		c.structElement(func() {
+			c.listField(1, compactStruct, len(chunks), func(i int) {
+				chunk := chunks[i]
+				c.structElement(func() {
+					c.i64Field(2, chunk.offset)
+					c.structField(3, func() {
+						c.i32Field(1, physicalType(chunk.column.Type))
+						c.listField(2, compactI32, 2, func(i int) {
+							c.varint(int64([]int32{encodingPlain, encodingRLE}[i]))
+						})
+						c.listField(3, compactBinary, 1, func(int) { c.binary(chunk.column.Name) })
+						c.i32Field(4, 0)
+						c.i64Field(5, chunk.rows)
+						c.i64Field(6, chunk.size)
+						c.i64Field(7, chunk.size)
+						c.i64Field(9, chunk.offset)
+					})
+				})
+			})
+			c.i64Field(2, totalSize)
+			c.i64Field(3, int64(len(t.Rows)))
+		})
+	})
+	c.stringField(6, "atlas-cli")
+	c.stop()
+	return c.buf.Bytes()
+}
+
+// Thrift compact protocol type codes
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter encodes the subset of the Thrift compact protocol Parquet metadata needs
+type compactWriter struct {
+	buf       bytes.Buffer
+	lastField []int16
+	current   int16
+}
+
+func (c *compactWriter) fieldHeader(id int16, typ byte) {
+	if delta := id - c.current; delta > 0 && delta <= 15 {
+		c.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		c.buf.WriteByte(typ)
+		c.varint(int64(id))
+	}
+	c.current = id
+}
+
+// varint writes a zigzag-encoded integer
+func (c *compactWriter) varint(v int64) {
+	c.buf.Write(binary.AppendUvarint(nil, uint64((v<<1)^(v>>63))))
+}
+
+func (c *compactWriter) binary(s string) {
+	c.buf.Write(binary.AppendUvarint(nil, uint64(len(s))))
+	c.buf.WriteString(s)
+}
+
+func (c *compactWriter) i32Field(id int16, v int32) {
+	c.fieldHeader(id, compactI32)
+	c.varint(int64(v))
+}
+
+func (c *compactWriter) i64Field(id int16, v int64) {
+	c.fieldHeader(id, compactI64)
+	c.varint(v)
+}
+
+func (c *compactWriter) stringField(id int16, s string) {
+	c.fieldHeader(id, compactBinary)
+	c.binary(s)
+}
+
+func (c *compactWriter) structField(id int16, fields func()) {
+	c.fieldHeader(id, compactStruct)
+	c.structElement(fields)
+}
+
+// structElement writes a struct body with its own field id numbering
+func (c *compactWriter) structElement(fields func()) {
+	c.lastField = append(c.lastField, c.current)
+	c.current = 0
+	fields()
+	c.stop()
+	c.current = c.lastField[len(c.lastField)-1]
+	c.lastField = c.lastField[:len(c.lastField)-1]
+}
+
+func (c *compactWriter) listField(id int16, elemType byte, size int, element func(i int)) {
+	c.fieldHeader(id, compactList)
+	if size < 15 {
+		c.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		c.buf.WriteByte(0xf0 | elemType)
+		c.buf.Write(binary.AppendUvarint(nil, uint64(size)))
+	}
+	for i := 0; i < size; i++ {
+		element(i)
+	}
+}
+
+func (c *compactWriter) stop() {
+	c.buf.WriteByte(0)
+}
@@ -0,0 +1,138 @@
+// Package fleet groups clusters, possibly on different providers and regions, into named fleets
+// so they can be operated on and monitored together.
+package fleet
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+)
+
+// Member is one cluster in a fleet
+type Member struct {
+	Cluster  string `json:"cluster"`
+	Provider string `json:"provider"`
+	Region   string `json:"region,omitempty"`
+}
+
+// String returns provider/region/cluster, or provider/cluster when no region is set
+func (m Member) String() string {
+	if m.Region == "" {
+		return m.Provider + "/" + m.Cluster
+	}
+	return m.Provider + "/" + m.Region + "/" + m.Cluster
+}
+
+// Fleet is a named group of clusters
+type Fleet struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Members     []Member `json:"members"`
+}
+
+// Add appends members that aren't already in the fleet and returns how many were added
+func (f *Fleet) Add(members ...Member) int {
+	added := 0
+	for _, member := range members {
+		if f.index(member) < 0 {
+			f.Members = append(f.Members, member)
+			added++
+		}
+	}
+	return added
+}
+
+// Remove drops every member named cluster and returns how many were removed
+func (f *Fleet) Remove(cluster string) int {
+	kept := f.Members[:0]
+	for _, member := range f.Members {
+		if member.Cluster != cluster {
+			kept = append(kept, member)
+		}
+	}
+	removed := len(f.Members) - len(kept)
+	f.Members = kept
+	return removed
+}
+
+func (f *Fleet) index(member Member) int {
+	for i, existing := range f.Members {
+		if existing == member {
+			return i
+		}
+	}
+	return -1
+}
+
+// DefaultStorePath returns the location of the fleet definitions file
+func DefaultStorePath() (string, error) {
+	return store.Path("fleets.json")
+}
+
+// Store holds the fleet definitions on disk
+type Store struct {
+	mu     sync.Mutex
+	path   string
+	fleets map[string]*Fleet
+}
+
+// LoadStore reads the fleets at path; a missing file starts empty
+func LoadStore(path string) (*Store, error) {
+	s := &Store{path: path, fleets: make(map[string]*Fleet)}
+	var fleets []*Fleet
+	if err := store.LoadJSON(path, &fleets); err != nil {
+		return nil, err
+	}
+	for _, f := range fleets {
+		s.fleets[f.Name] = f
+	}
+	return s, nil
+}
+
+// Get returns the fleet called name
+func (s *Store) Get(name string) (*Fleet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, exists := s.fleets[name]
+	if !exists {
+		return nil, fmt.Errorf("fleet %s not found", name)
+	}
+	return f, nil
+}
+
+// Put adds or replaces f
+func (s *Store) Put(f *Fleet) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fleets[f.Name] = f
+}
+
+// Delete removes the fleet called name; its clusters are left untouched
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.fleets[name]; !exists {
+		return fmt.Errorf("fleet %s not found", name)
+	}
+	delete(s.fleets, name)
+	return nil
+}
+
+// List returns every fleet sorted by name
+func (s *Store) List() []*Fleet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fleets := make([]*Fleet, 0, len(s.fleets))
+	for _, f := range s.fleets {
+		fleets = append(fleets, f)
+	}
+	sort.Slice(fleets, func(i, j int) bool { return fleets[i].Name < fleets[j].Name })
+	return fleets
+}
+
+// Save writes the fleet definitions back to disk
+func (s *Store) Save() error {
+	return store.SaveJSON(s.path, s.List())
+}
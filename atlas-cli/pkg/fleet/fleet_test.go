@@ -0,0 +1,114 @@
+package fleet
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+)
+
+func TestFleet_AddRemove(t *testing.T) {
+	f := &Fleet{Name: "dev"}
+	dev1 := Member{Cluster: "dev-1", Provider: "local"}
+	eks := Member{Cluster: "dev-1", Provider: "aws", Region: "us-west-2"}
+
+	if added := f.Add(dev1, eks, dev1); added != 2 {
+		t.Errorf("Add() = %d, want 2 (duplicates skipped)", added)
+	}
+	if got := eks.String(); got != "aws/us-west-2/dev-1" {
+		t.Errorf("Member.String() = %q, want aws/us-west-2/dev-1", got)
+	}
+	if removed := f.Remove("dev-1"); removed != 2 {
+		t.Errorf("Remove() = %d, want 2", removed)
+	}
+	if len(f.Members) != 0 {
+		t.Errorf("Members = %v, want none", f.Members)
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fleets.json")
+	store, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore() on a missing file error = %v", err)
+	}
+	store.Put(&Fleet{Name: "prod", Members: []Member{{Cluster: "prod-usw2", Provider: "aws", Region: "us-west-2"}}})
+	store.Put(&Fleet{Name: "dev", Members: []Member{{Cluster: "dev-1", Provider: "local"}}})
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore() error = %v", err)
+	}
+	fleets := reloaded.List()
+	if len(fleets) != 2 || fleets[0].Name != "dev" || fleets[1].Name != "prod" {
+		t.Fatalf("List() = %+v, want dev and prod sorted by name", fleets)
+	}
+	prod, err := reloaded.Get("prod")
+	if err != nil || prod.Members[0].Region != "us-west-2" {
+		t.Errorf("Get(prod) = %+v, %v", prod, err)
+	}
+
+	if err := reloaded.Delete("dev"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	if _, err := reloaded.Get("dev"); err == nil {
+		t.Error("Get() expected error after Delete()")
+	}
+	if err := reloaded.Delete("dev"); err == nil {
+		t.Error("Delete() expected error for an unknown fleet")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	member := func(status monitoring.ClusterHealthStatus) MemberHealth {
+		return MemberHealth{Status: status}
+	}
+	tests := []struct {
+		name    string
+		members []MemberHealth
+		want    monitoring.ClusterHealthStatus
+	}{
+		{"empty fleet", nil, monitoring.HealthStatusUnknown},
+		{"all healthy", []MemberHealth{member(monitoring.HealthStatusHealthy), member(monitoring.HealthStatusHealthy)}, monitoring.HealthStatusHealthy},
+		{"one warning", []MemberHealth{member(monitoring.HealthStatusHealthy), member(monitoring.HealthStatusWarning)}, monitoring.HealthStatusWarning},
+		{"one unknown", []MemberHealth{member(monitoring.HealthStatusHealthy), member(monitoring.HealthStatusUnknown)}, monitoring.HealthStatusWarning},
+		{"unhealthy wins", []MemberHealth{member(monitoring.HealthStatusWarning), member(monitoring.HealthStatusUnhealthy)}, monitoring.HealthStatusUnhealthy},
+		{"all unknown", []MemberHealth{member(monitoring.HealthStatusUnknown)}, monitoring.HealthStatusUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Aggregate("fleet", tt.members).OverallStatus; got != tt.want {
+				t.Errorf("Aggregate() status = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregate_Totals(t *testing.T) {
+	health := &monitoring.HealthStatus{
+		OverallStatus: monitoring.HealthStatusHealthy,
+		Nodes:         []monitoring.NodeHealth{{Status: monitoring.NodeHealthy}, {Status: monitoring.NodeNotReady}},
+	}
+	metrics := func(cpu, memory float64) *monitoring.ClusterMetrics {
+		return &monitoring.ClusterMetrics{ResourceUsage: &monitoring.ResourceUsage{CPUPercentage: cpu, MemoryPercentage: memory}}
+	}
+	members := []MemberHealth{
+		NewMemberHealth(Member{Cluster: "a"}, health, metrics(20, 40)),
+		NewMemberHealth(Member{Cluster: "b"}, health, metrics(60, 80)),
+		NewMemberHealth(Member{Cluster: "c"}, nil, nil),
+	}
+
+	got := Aggregate("fleet", members)
+	if got.Nodes != 4 || got.ReadyNodes != 2 {
+		t.Errorf("nodes = %d/%d, want 2/4", got.ReadyNodes, got.Nodes)
+	}
+	if got.CPUPercentage == nil || *got.CPUPercentage != 40 || *got.MemoryPercentage != 60 {
+		t.Errorf("average usage = %v/%v, want 40/60 from members with metrics only", got.CPUPercentage, got.MemoryPercentage)
+	}
+	if got.Counts[monitoring.HealthStatusUnknown] != 1 {
+		t.Errorf("unknown count = %d, want 1 for the member without a health check", got.Counts[monitoring.HealthStatusUnknown])
+	}
+}
@@ -0,0 +1,91 @@
+package fleet
+
+import (
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+)
+
+// MemberHealth is the health, and optionally resource usage, of one fleet member
+type MemberHealth struct {
+	Member
+	Status     monitoring.ClusterHealthStatus `json:"status"`
+	Nodes      int                            `json:"nodes"`
+	ReadyNodes int                            `json:"ready_nodes"`
+	// CPUPercentage and MemoryPercentage are nil when metrics weren't collected or failed
+	CPUPercentage    *float64 `json:"cpu_percentage,omitempty"`
+	MemoryPercentage *float64 `json:"memory_percentage,omitempty"`
+	Error            string   `json:"error,omitempty"`
+}
+
+// Health is the aggregated health of a fleet
+type Health struct {
+	Fleet         string                                 `json:"fleet"`
+	OverallStatus monitoring.ClusterHealthStatus         `json:"overall_status"`
+	Counts        map[monitoring.ClusterHealthStatus]int `json:"counts"`
+	Nodes         int                                    `json:"nodes"`
+	ReadyNodes    int                                    `json:"ready_nodes"`
+	// CPUPercentage and MemoryPercentage average the members that reported metrics
+	CPUPercentage    *float64       `json:"cpu_percentage,omitempty"`
+	MemoryPercentage *float64       `json:"memory_percentage,omitempty"`
+	Clusters         []MemberHealth `json:"clusters"`
+}
+
+// NewMemberHealth summarizes a member's health check and metrics; either may be nil
+func NewMemberHealth(member Member, health *monitoring.HealthStatus, metrics *monitoring.ClusterMetrics) MemberHealth {
+	result := MemberHealth{Member: member, Status: monitoring.HealthStatusUnknown}
+	if health != nil {
+		result.Status = health.OverallStatus
+		result.Nodes = len(health.Nodes)
+		for _, node := range health.Nodes {
+			if node.Status == monitoring.NodeHealthy {
+				result.ReadyNodes++
+			}
+		}
+	}
+	if metrics != nil && metrics.ResourceUsage != nil {
+		cpu, memory := metrics.ResourceUsage.CPUPercentage, metrics.ResourceUsage.MemoryPercentage
+		result.CPUPercentage = &cpu
+		result.MemoryPercentage = &memory
+	}
+	return result
+}
+
+// Aggregate rolls member health up to the fleet. The fleet is unhealthy if any member is,
+// warning if any member is warning or couldn't be checked, and unknown only when no member could
+// be checked at all.
+func Aggregate(name string, members []MemberHealth) *Health {
+	health := &Health{
+		Fleet:    name,
+		Counts:   make(map[monitoring.ClusterHealthStatus]int),
+		Clusters: members,
+	}
+
+	var cpuTotal, memoryTotal float64
+	var withMetrics int
+	for _, member := range members {
+		health.Counts[member.Status]++
+		health.Nodes += member.Nodes
+		health.ReadyNodes += member.ReadyNodes
+		if member.CPUPercentage != nil && member.MemoryPercentage != nil {
+			cpuTotal += *member.CPUPercentage
+			memoryTotal += *member.MemoryPercentage
+			withMetrics++
+		}
+	}
+	if withMetrics > 0 {
+		cpu, memory := cpuTotal/float64(withMetrics), memoryTotal/float64(withMetrics)
+		health.CPUPercentage = &cpu
+		health.MemoryPercentage = &memory
+	}
+
+	switch {
+	case len(members) == 0 || health.Counts[monitoring.HealthStatusUnknown] == len(members):
+		health.OverallStatus = monitoring.HealthStatusUnknown
+	case health.Counts[monitoring.HealthStatusUnhealthy] > 0:
+		health.OverallStatus = monitoring.HealthStatusUnhealthy
+	case health.Counts[monitoring.HealthStatusWarning] > 0 || health.Counts[monitoring.HealthStatusUnknown] > 0:
+		health.OverallStatus = monitoring.HealthStatusWarning
+	default:
+		health.OverallStatus = monitoring.HealthStatusHealthy
+	}
+	return health
+}
@@ -0,0 +1,88 @@
+package fleet
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Requirement is one term of a tag selector
+type Requirement struct {
+	Key string
+	// Value is compared when Op is "=" or "!="
+	Value string
+	// Op is "=", "!=", "exists" or "!exists"
+	Op string
+}
+
+// Selector picks clusters by their tags; a cluster matches when it meets every requirement
+type Selector []Requirement
+
+// ParseSelector parses comma separated terms: env=dev, env!=prod, team (the tag is set) and !team
+// (the tag is not set)
+func ParseSelector(selector string) (Selector, error) {
+	var s Selector
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		var r Requirement
+		switch {
+		case strings.Contains(term, "!="):
+			key, value, _ := strings.Cut(term, "!=")
+			r = Requirement{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value), Op: "!="}
+		case strings.Contains(term, "="):
+			key, value, _ := strings.Cut(term, "=")
+			r = Requirement{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value), Op: "="}
+		case strings.HasPrefix(term, "!"):
+			r = Requirement{Key: strings.TrimSpace(term[1:]), Op: "!exists"}
+		default:
+			r = Requirement{Key: term, Op: "exists"}
+		}
+		if r.Key == "" || strings.ContainsAny(r.Key, "!= ") || strings.Contains(r.Value, "=") {
+			return nil, fmt.Errorf("invalid selector term %q; use tag=value, tag!=value, tag or !tag separated by commas", term)
+		}
+		s = append(s, r)
+	}
+	if len(s) == 0 {
+		return nil, fmt.Errorf("selector %q has no terms", selector)
+	}
+	return s, nil
+}
+
+// Matches reports whether tags meet every requirement
+func (s Selector) Matches(tags map[string]string) bool {
+	for _, r := range s {
+		value, ok := tags[r.Key]
+		var met bool
+		switch r.Op {
+		case "=":
+			met = ok && value == r.Value
+		case "!=":
+			met = !ok || value != r.Value
+		case "exists":
+			met = ok
+		case "!exists":
+			met = !ok
+		}
+		if !met {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Selector) String() string {
+	terms := make([]string, len(s))
+	for i, r := range s {
+		switch r.Op {
+		case "exists":
+			terms[i] = r.Key
+		case "!exists":
+			terms[i] = "!" + r.Key
+		default:
+			terms[i] = r.Key + r.Op + r.Value
+		}
+	}
+	return strings.Join(terms, ",")
+}
@@ -0,0 +1,51 @@
+package fleet
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelector(t *testing.T) {
+	tags := map[string]string{"env": "dev", "team": "payments"}
+
+	tests := []struct {
+		selector string
+		want     bool
+		wantErr  string
+	}{
+		{selector: "env=dev", want: true},
+		{selector: "env=dev, team=payments", want: true},
+		{selector: "env=prod"},
+		{selector: "env!=prod", want: true},
+		{selector: "owner!=alice", want: true},
+		{selector: "team", want: true},
+		{selector: "owner"},
+		{selector: "!owner", want: true},
+		{selector: "!team"},
+		{selector: "env=dev,owner"},
+		{selector: "", wantErr: "no terms"},
+		{selector: "=dev", wantErr: "invalid selector term"},
+		{selector: "env==dev", wantErr: "invalid selector term"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			s, err := ParseSelector(tt.selector)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseSelector() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSelector() error = %v", err)
+			}
+			if got := s.Matches(tags); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+			if reparsed, err := ParseSelector(s.String()); err != nil || reparsed.String() != s.String() {
+				t.Errorf("String() = %q does not round trip: %v", s.String(), err)
+			}
+		})
+	}
+}
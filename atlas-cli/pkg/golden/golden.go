@@ -0,0 +1,346 @@
+// Package golden captures a blessed cluster build as a signed artifact: the cluster config it was
+// created from, the versions of its addons and the exact bootstrap manifests applied to it. A
+// team commits the artifact and creates new clusters from it, so every copy is built the same way.
+package golden
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"gopkg.in/yaml.v3"
+)
+
+// maxManifestSize bounds a bootstrap manifest fetched from a URL
+const maxManifestSize = 10 << 20
+
+// Artifact is a captured cluster build
+type Artifact struct {
+	Name       string    `json:"name"`
+	Cluster    string    `json:"cluster"`
+	Provider   string    `json:"provider"`
+	CapturedAt time.Time `json:"capturedAt"`
+	// ConfigRevision is the sha256 digest of Config
+	ConfigRevision string `json:"configRevision"`
+	// Config is the cluster config as YAML, without the addons and bootstrap manifests, which are
+	// recorded below
+	Config    string            `json:"config"`
+	Addons    []providers.Addon `json:"addons,omitempty"`
+	Manifests []Manifest        `json:"manifests,omitempty"`
+}
+
+// Manifest is the content of a bootstrap manifest as it was when the artifact was captured
+type Manifest struct {
+	// Source is the manifest's name, file or URL in the original config
+	Source  string `json:"source"`
+	Digest  string `json:"digest"`
+	Content string `json:"content"`
+}
+
+// SignedArtifact is an artifact with the Ed25519 signature of its compact JSON encoding and the
+// public key that made it
+type SignedArtifact struct {
+	Artifact  json.RawMessage `json:"artifact"`
+	Signature string          `json:"signature"`
+	PublicKey string          `json:"publicKey"`
+}
+
+// NewArtifact records config, the enabled addons and the resolved bootstrap manifests of
+// provider's cluster. The config's own addons and manifests are left out in favour of these.
+func NewArtifact(name, cluster, provider string, config providers.ClusterConfig, addons []providers.Addon, manifests []Manifest) (*Artifact, error) {
+	config.Addons = nil
+	config.BootstrapManifests = nil
+	data, err := yaml.Marshal(&config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cluster config: %w", err)
+	}
+
+	artifact := &Artifact{
+		Name:           name,
+		Cluster:        cluster,
+		Provider:       provider,
+		CapturedAt:     time.Now().UTC().Truncate(time.Second),
+		ConfigRevision: digest(data),
+		Config:         string(data),
+		Manifests:      manifests,
+	}
+	for _, addon := range addons {
+		if addon.Enabled {
+			artifact.Addons = append(artifact.Addons, addon)
+		}
+	}
+	return artifact, nil
+}
+
+// ClusterConfig rebuilds the captured config for a new cluster called name. Addons with a
+// recorded version are requested as name@version, and the manifests are applied inline.
+func (a *Artifact) ClusterConfig(name string) (*providers.ClusterConfig, error) {
+	var config providers.ClusterConfig
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(a.Config)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse captured cluster config: %w", err)
+	}
+	config.Name = name
+	for _, addon := range a.Addons {
+		if addon.Version != "" {
+			config.Addons = append(config.Addons, addon.Name+"@"+addon.Version)
+		} else {
+			config.Addons = append(config.Addons, addon.Name)
+		}
+	}
+	for _, manifest := range a.Manifests {
+		config.BootstrapManifests = append(config.BootstrapManifests, providers.BootstrapManifest{
+			Name:   manifest.Source,
+			Inline: manifest.Content,
+		})
+	}
+	return &config, nil
+}
+
+// ResolveManifests reads each bootstrap manifest's content from its file or URL
+func ResolveManifests(ctx context.Context, manifests []providers.BootstrapManifest) ([]Manifest, error) {
+	var resolved []Manifest
+	for i, manifest := range manifests {
+		var content []byte
+		source := manifest.Name
+		switch {
+		case manifest.Inline != "":
+			content = []byte(manifest.Inline)
+			if source == "" {
+				source = fmt.Sprintf("inline-%d", i)
+			}
+		case manifest.File != "":
+			data, err := os.ReadFile(manifest.File)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read bootstrap manifest %s: %w", manifest.File, err)
+			}
+			content = data
+			if source == "" {
+				source = manifest.File
+			}
+		case manifest.URL != "":
+			data, err := fetchManifest(ctx, manifest.URL)
+			if err != nil {
+				return nil, err
+			}
+			content = data
+			if source == "" {
+				source = manifest.URL
+			}
+		default:
+			return nil, fmt.Errorf("bootstrap manifest %d sets none of inline, file or url", i)
+		}
+		resolved = append(resolved, Manifest{Source: source, Digest: digest(content), Content: string(content)})
+	}
+	return resolved, nil
+}
+
+func fetchManifest(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bootstrap manifest url %s: %w", url, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bootstrap manifest %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch bootstrap manifest %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bootstrap manifest %s: %w", url, err)
+	}
+	if len(data) > maxManifestSize {
+		return nil, fmt.Errorf("bootstrap manifest %s is larger than %d bytes", url, maxManifestSize)
+	}
+	return data, nil
+}
+
+// Sign signs the artifact with key
+func Sign(artifact *Artifact, key ed25519.PrivateKey) (*SignedArtifact, error) {
+	payload, err := json.Marshal(artifact)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode artifact: %w", err)
+	}
+	return &SignedArtifact{
+		Artifact:  payload,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload)),
+		PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+	}, nil
+}
+
+// Verify checks the signature, that it was made by one of the trusted keys and that the config
+// and manifests match their recorded digests, and returns the artifact
+func (s *SignedArtifact) Verify(trusted []ed25519.PublicKey) (*Artifact, error) {
+	publicKey, err := base64.StdEncoding.DecodeString(s.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("artifact has an invalid public key")
+	}
+	signature, err := base64.StdEncoding.DecodeString(s.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("artifact has an invalid signature")
+	}
+	var payload bytes.Buffer
+	if err := json.Compact(&payload, s.Artifact); err != nil {
+		return nil, fmt.Errorf("failed to parse artifact: %w", err)
+	}
+	if !ed25519.Verify(publicKey, payload.Bytes(), signature) {
+		return nil, fmt.Errorf("artifact signature does not match its contents; it was modified after it was signed")
+	}
+	isTrusted := false
+	for _, key := range trusted {
+		isTrusted = isTrusted || key.Equal(ed25519.PublicKey(publicKey))
+	}
+	if !isTrusted {
+		return nil, fmt.Errorf("artifact was signed by key %s, which is not trusted", Fingerprint(publicKey))
+	}
+
+	var artifact Artifact
+	if err := json.Unmarshal(payload.Bytes(), &artifact); err != nil {
+		return nil, fmt.Errorf("failed to parse artifact: %w", err)
+	}
+	if digest([]byte(artifact.Config)) != artifact.ConfigRevision {
+		return nil, fmt.Errorf("artifact config does not match revision %s", artifact.ConfigRevision)
+	}
+	for _, manifest := range artifact.Manifests {
+		if digest([]byte(manifest.Content)) != manifest.Digest {
+			return nil, fmt.Errorf("bootstrap manifest %s does not match digest %s", manifest.Source, manifest.Digest)
+		}
+	}
+	return &artifact, nil
+}
+
+// Write saves the signed artifact to path
+func Write(path string, signed *SignedArtifact) error {
+	data, err := json.MarshalIndent(signed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode artifact: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write artifact: %w", err)
+	}
+	return nil
+}
+
+// Read loads a signed artifact without verifying it
+func Read(path string) (*SignedArtifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+	var signed SignedArtifact
+	if err := json.Unmarshal(data, &signed); err != nil {
+		return nil, fmt.Errorf("failed to parse artifact %s: %w", path, err)
+	}
+	if len(signed.Artifact) == 0 || signed.Signature == "" {
+		return nil, fmt.Errorf("%s is not a signed golden artifact", path)
+	}
+	return &signed, nil
+}
+
+// DefaultKeyPath returns where the signing key is kept; its public key is next to it with a .pub
+// extension
+func DefaultKeyPath() (string, error) {
+	return store.Path("golden", "signing.key")
+}
+
+// LoadOrCreateKey reads the PEM-encoded PKCS#8 Ed25519 private key at path. When the file doesn't
+// exist a new key is generated and saved there, with its public key at path.pub.
+func LoadOrCreateKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return createKey(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not PEM encoded", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key must be Ed25519, got %T", parsed)
+	}
+	return key, nil
+}
+
+func createKey(path string) (ed25519.PrivateKey, error) {
+	publicKey, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signing key: %w", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode public key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create key directory: %w", err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write signing key: %w", err)
+	}
+	if err := os.WriteFile(path+".pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write public key: %w", err)
+	}
+	return key, nil
+}
+
+// LoadPublicKey reads a PEM-encoded Ed25519 public key
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("public key %s is not PEM encoded", path)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s must be Ed25519, got %T", path, parsed)
+	}
+	return key, nil
+}
+
+// Fingerprint identifies a public key by the start of its sha256 digest
+func Fingerprint(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])[:16]
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,154 @@
+package golden
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+)
+
+func TestResolveManifests(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "ns.yaml")
+	if err := os.WriteFile(file, []byte("kind: Namespace\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/crds.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("kind: CustomResourceDefinition\n"))
+	}))
+	defer server.Close()
+
+	manifests, err := ResolveManifests(context.Background(), []providers.BootstrapManifest{
+		{Name: "quota", Inline: "kind: ResourceQuota\n"},
+		{File: file},
+		{URL: server.URL + "/crds.yaml"},
+	})
+	if err != nil {
+		t.Fatalf("ResolveManifests() error = %v", err)
+	}
+	sources := []string{manifests[0].Source, manifests[1].Source, manifests[2].Source}
+	if !reflect.DeepEqual(sources, []string{"quota", file, server.URL + "/crds.yaml"}) {
+		t.Errorf("ResolveManifests() sources = %v", sources)
+	}
+	if manifests[2].Content != "kind: CustomResourceDefinition\n" || !strings.HasPrefix(manifests[2].Digest, "sha256:") {
+		t.Errorf("ResolveManifests() url manifest = %+v", manifests[2])
+	}
+
+	if _, err := ResolveManifests(context.Background(), []providers.BootstrapManifest{{URL: server.URL + "/missing"}}); err == nil {
+		t.Error("ResolveManifests() should fail when a URL can't be fetched")
+	}
+}
+
+func TestArtifactRoundTrip(t *testing.T) {
+	config := providers.ClusterConfig{
+		Name:               "dev",
+		Version:            "1.31.0",
+		NodeCount:          3,
+		Tags:               map[string]string{"team": "platform"},
+		Addons:             []string{"ignored"},
+		BootstrapManifests: []providers.BootstrapManifest{{File: "ignored.yaml"}},
+	}
+	addons := []providers.Addon{
+		{Name: "vpc-cni", Enabled: true, Version: "v1.18.3-eksbuild.1"},
+		{Name: "dashboard", Enabled: false},
+		{Name: "ingress", Enabled: true},
+	}
+	manifests := []Manifest{{Source: "ns.yaml", Digest: digest([]byte("kind: Namespace\n")), Content: "kind: Namespace\n"}}
+	artifact, err := NewArtifact("blessed", "dev", "aws", config, addons, manifests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(artifact.Addons) != 2 || strings.Contains(artifact.Config, "ignored") {
+		t.Errorf("NewArtifact() = %+v, want only enabled addons and no config addons or manifests", artifact)
+	}
+
+	got, err := artifact.ClusterConfig("dev2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &providers.ClusterConfig{
+		Name:               "dev2",
+		Version:            "1.31.0",
+		NodeCount:          3,
+		Tags:               map[string]string{"team": "platform"},
+		Addons:             []string{"vpc-cni@v1.18.3-eksbuild.1", "ingress"},
+		BootstrapManifests: []providers.BootstrapManifest{{Name: "ns.yaml", Inline: "kind: Namespace\n"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ClusterConfig() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "keys", "signing.key")
+	key, err := LoadOrCreateKey(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, err := LoadOrCreateKey(keyPath); err != nil || !again.Equal(key) {
+		t.Fatalf("LoadOrCreateKey() second call = %v, want the saved key", err)
+	}
+	publicKey, err := LoadPublicKey(keyPath + ".pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	artifact, err := NewArtifact("blessed", "dev", "local", providers.ClusterConfig{NodeCount: 2}, nil,
+		[]Manifest{{Source: "ns", Digest: digest([]byte("a")), Content: "a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := Sign(artifact, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "blessed.golden.json")
+	if err := Write(path, signed); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verified, err := read.Verify([]ed25519.PublicKey{publicKey})
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if verified.ConfigRevision != artifact.ConfigRevision || !verified.CapturedAt.Equal(artifact.CapturedAt) {
+		t.Errorf("Verify() = %+v, want %+v", verified, artifact)
+	}
+
+	otherPublic, _, _ := ed25519.GenerateKey(nil)
+	if _, err := read.Verify([]ed25519.PublicKey{otherPublic}); err == nil || !strings.Contains(err.Error(), "not trusted") {
+		t.Errorf("Verify() with another key error = %v, want not trusted", err)
+	}
+
+	tampered := *read
+	tampered.Artifact = json.RawMessage(strings.Replace(string(read.Artifact), `nodeCount: 2`, `nodeCount: 9`, 1))
+	if _, err := tampered.Verify([]ed25519.PublicKey{publicKey}); err == nil || !strings.Contains(err.Error(), "modified after it was signed") {
+		t.Errorf("Verify() of a modified artifact error = %v", err)
+	}
+
+	// a re-signed artifact whose content doesn't match its digests is refused too
+	artifact.Manifests[0].Content = "b"
+	resigned, err := Sign(artifact, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resigned.Verify([]ed25519.PublicKey{publicKey}); err == nil || !strings.Contains(err.Error(), "does not match digest") {
+		t.Errorf("Verify() with a mismatched manifest digest error = %v", err)
+	}
+}
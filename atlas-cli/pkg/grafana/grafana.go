@@ -0,0 +1,228 @@
+// Package grafana builds dashboards for the metrics atlas-cli exports in monitor watch mode and
+// provisions them through the Grafana HTTP API
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Panel is one visualization on a dashboard
+type Panel struct {
+	Title string
+	// Type is a Grafana panel type: timeseries, stat or table
+	Type string
+	// Expr is the PromQL query; Legend names its series, e.g. "{{cluster}}"
+	Expr   string
+	Legend string
+	Unit   string
+	Width  int
+	Height int
+}
+
+// Dashboard is a set of panels laid out left to right, top to bottom
+type Dashboard struct {
+	UID    string
+	Title  string
+	Panels []Panel
+}
+
+// datasource refers panels to the dashboard's Prometheus data source variable, so the
+// dashboards work with whichever Prometheus scrapes atlas-cli
+var datasource = map[string]string{"type": "prometheus", "uid": "${datasource}"}
+
+// Model renders d as a Grafana dashboard JSON model
+func (d Dashboard) Model() map[string]any {
+	panels := make([]map[string]any, 0, len(d.Panels))
+	x, y, rowHeight := 0, 0, 0
+	for i, p := range d.Panels {
+		width, height := p.Width, p.Height
+		if width == 0 {
+			width = 12
+		}
+		if height == 0 {
+			height = 8
+		}
+		if x+width > 24 {
+			x, y = 0, y+rowHeight
+			rowHeight = 0
+		}
+		panel := map[string]any{
+			"id":         i + 1,
+			"title":      p.Title,
+			"type":       p.Type,
+			"datasource": datasource,
+			"gridPos":    map[string]int{"x": x, "y": y, "w": width, "h": height},
+			"targets": []map[string]any{{
+				"refId":        "A",
+				"datasource":   datasource,
+				"expr":         p.Expr,
+				"legendFormat": p.Legend,
+				"instant":      p.Type != "timeseries",
+			}},
+			"fieldConfig": map[string]any{"defaults": map[string]any{"unit": p.Unit}, "overrides": []any{}},
+		}
+		panels = append(panels, panel)
+		x += width
+		if height > rowHeight {
+			rowHeight = height
+		}
+	}
+
+	return map[string]any{
+		"uid":           d.UID,
+		"title":         d.Title,
+		"tags":          []string{"atlas"},
+		"timezone":      "browser",
+		"schemaVersion": 39,
+		"refresh":       "30s",
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"panels":        panels,
+		"templating": map[string]any{"list": []map[string]any{{
+			"name":  "datasource",
+			"label": "Prometheus",
+			"type":  "datasource",
+			"query": "prometheus",
+		}}},
+	}
+}
+
+// Dashboards returns the dashboards for Atlas's metrics: a fleet overview, provider call and
+// operation durations, and cluster health history
+func Dashboards() []Dashboard {
+	return []Dashboard{
+		{
+			UID:   "atlas-fleet-overview",
+			Title: "Atlas / Fleet Overview",
+			Panels: []Panel{
+				{Title: "Healthy clusters", Type: "stat", Expr: `sum(atlas_cluster_healthy)`, Width: 6, Height: 4},
+				{Title: "Unhealthy clusters", Type: "stat", Expr: `count(atlas_cluster_healthy == 0) or vector(0)`, Width: 6, Height: 4},
+				{Title: "Operations running", Type: "stat", Expr: `sum(atlas_operations{state="running"})`, Width: 6, Height: 4},
+				{Title: "Operations queued", Type: "stat", Expr: `sum(atlas_operations{state="queued"})`, Width: 6, Height: 4},
+				{Title: "Cluster health", Type: "table", Expr: `atlas_cluster_healthy`, Legend: "{{cluster}}", Width: 12},
+				{Title: "Ready nodes", Type: "table", Expr: `atlas_cluster_ready_nodes`, Legend: "{{cluster}}", Width: 12},
+			},
+		},
+		{
+			UID:   "atlas-operations",
+			Title: "Atlas / Operation Durations",
+			Panels: []Panel{
+				{Title: "Mean provider call duration", Type: "timeseries", Unit: "s", Legend: "{{cluster}} {{call}}",
+					Expr: `rate(atlas_provider_call_duration_seconds_sum[5m]) / rate(atlas_provider_call_duration_seconds_count[5m])`},
+				{Title: "Provider call errors", Type: "timeseries", Unit: "ops", Legend: "{{cluster}} {{call}}",
+					Expr: `rate(atlas_provider_call_duration_errors_total[5m])`},
+				{Title: "Operations in flight", Type: "timeseries", Legend: "{{state}}", Expr: `atlas_operations`},
+				{Title: "Longest running operation", Type: "timeseries", Unit: "s", Expr: `atlas_operation_oldest_running_seconds`},
+			},
+		},
+		{
+			UID:   "atlas-health-history",
+			Title: "Atlas / Health History",
+			Panels: []Panel{
+				{Title: "Cluster health (1 = up)", Type: "timeseries", Legend: "{{cluster}}", Expr: `atlas_cluster_healthy`, Width: 24},
+				{Title: "Availability over the range", Type: "table", Unit: "percentunit", Legend: "{{cluster}}",
+					Expr: `avg_over_time(atlas_cluster_healthy[$__range])`},
+				{Title: "Monitor loop lag", Type: "timeseries", Unit: "s", Legend: "{{cluster}}", Expr: `atlas_monitor_loop_lag_seconds`},
+			},
+		},
+	}
+}
+
+// Client talks to the Grafana HTTP API with a service account token
+type Client struct {
+	url   string
+	token string
+	http  *http.Client
+}
+
+// NewClient creates a client for the Grafana at url
+func NewClient(url, token string) *Client {
+	return &Client{url: strings.TrimSuffix(url, "/"), token: token, http: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// EnsureFolder returns the uid of the folder titled title, creating it if needed
+func (c *Client) EnsureFolder(ctx context.Context, title string) (string, error) {
+	var folders []struct {
+		UID   string `json:"uid"`
+		Title string `json:"title"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/folders", nil, &folders); err != nil {
+		return "", fmt.Errorf("failed to list Grafana folders: %w", err)
+	}
+	for _, folder := range folders {
+		if folder.Title == title {
+			return folder.UID, nil
+		}
+	}
+
+	var created struct {
+		UID string `json:"uid"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/folders", map[string]string{"title": title}, &created); err != nil {
+		return "", fmt.Errorf("failed to create Grafana folder %s: %w", title, err)
+	}
+	return created.UID, nil
+}
+
+// Provision creates or replaces dashboard in the folder folderUID and returns its URL
+func (c *Client) Provision(ctx context.Context, dashboard Dashboard, folderUID string) (string, error) {
+	body := map[string]any{
+		"dashboard": dashboard.Model(),
+		"folderUid": folderUID,
+		"overwrite": true,
+		"message":   "Provisioned by atlas-cli",
+	}
+	var result struct {
+		URL string `json:"url"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/dashboards/db", body, &result); err != nil {
+		return "", fmt.Errorf("failed to provision dashboard %s: %w", dashboard.Title, err)
+	}
+	return c.url + result.URL, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Message != "" {
+			return fmt.Errorf("Grafana returned %s: %s", resp.Status, apiErr.Message)
+		}
+		return fmt.Errorf("Grafana returned %s", resp.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,87 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDashboards(t *testing.T) {
+	uids := make(map[string]bool)
+	for _, dashboard := range Dashboards() {
+		if uids[dashboard.UID] {
+			t.Errorf("dashboard uid %s is used twice", dashboard.UID)
+		}
+		uids[dashboard.UID] = true
+
+		model := dashboard.Model()
+		panels := model["panels"].([]map[string]any)
+		if len(panels) != len(dashboard.Panels) {
+			t.Fatalf("%s: Model() has %d panels, want %d", dashboard.Title, len(panels), len(dashboard.Panels))
+		}
+		for i, panel := range panels {
+			pos := panel["gridPos"].(map[string]int)
+			if pos["x"]+pos["w"] > 24 {
+				t.Errorf("%s: panel %q overflows the grid: %v", dashboard.Title, panel["title"], pos)
+			}
+			if !strings.Contains(dashboard.Panels[i].Expr, "atlas_") {
+				t.Errorf("%s: panel %q doesn't query an Atlas metric", dashboard.Title, panel["title"])
+			}
+		}
+	}
+}
+
+func TestClient_Provision(t *testing.T) {
+	var posted []string
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/folders":
+			w.Write([]byte(`[{"uid": "other", "title": "Other"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/folders":
+			w.Write([]byte(`{"uid": "atlas-folder"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/dashboards/db":
+			var body struct {
+				Dashboard map[string]any `json:"dashboard"`
+				FolderUID string         `json:"folderUid"`
+				Overwrite bool           `json:"overwrite"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.FolderUID != "atlas-folder" || !body.Overwrite {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"message": "bad request"}`))
+				return
+			}
+			posted = append(posted, body.Dashboard["uid"].(string))
+			w.Write([]byte(`{"url": "/d/` + body.Dashboard["uid"].(string) + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient(server.URL+"/", "glsa_token")
+	folderUID, err := client.EnsureFolder(ctx, "Atlas")
+	if err != nil {
+		t.Fatalf("EnsureFolder() error = %v", err)
+	}
+	url, err := client.Provision(ctx, Dashboards()[0], folderUID)
+	if err != nil {
+		t.Fatalf("Provision() error = %v", err)
+	}
+	if url != server.URL+"/d/atlas-fleet-overview" || len(posted) != 1 {
+		t.Errorf("Provision() = %q after posting %v", url, posted)
+	}
+	if auth != "Bearer glsa_token" {
+		t.Errorf("Authorization = %q, want the token", auth)
+	}
+
+	if _, err := client.Provision(ctx, Dashboards()[0], "missing"); err == nil || !strings.Contains(err.Error(), "bad request") {
+		t.Errorf("Provision() into a missing folder error = %v, want Grafana's message", err)
+	}
+}
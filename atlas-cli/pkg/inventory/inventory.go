@@ -0,0 +1,294 @@
+// Package inventory pushes cluster metadata to an external inventory such as a CMDB, so
+// organizations that keep a central record of their infrastructure see Atlas clusters in it.
+//
+// Records are sent as JSON over HTTP. The "http" format posts {"event": ..., "cluster": {...}};
+// the "servicenow" format posts the mapped fields alone, as the ServiceNow import set API expects,
+// and leaves matching records to the import set's transform map.
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatHTTP       = "http"
+	FormatServiceNow = "servicenow"
+)
+
+// Events sent with each record
+const (
+	EventUpserted = "upserted"
+	EventDeleted  = "deleted"
+)
+
+// Config configures where and how inventory is pushed. URL, Token and Password may be secret
+// references, resolved by the caller before use.
+type Config struct {
+	URL      string            `yaml:"url"`
+	Format   string            `yaml:"format,omitempty"`
+	Token    string            `yaml:"token,omitempty"`
+	Username string            `yaml:"username,omitempty"`
+	Password string            `yaml:"password,omitempty"`
+	Headers  map[string]string `yaml:"headers,omitempty"`
+	// Fields maps each field of the pushed record to an inventory field, e.g. name, version,
+	// owner, endpoint, status, or tags.<key>. Without it every inventory field is sent as is.
+	Fields map[string]string `yaml:"fields,omitempty"`
+}
+
+// DefaultConfigPath returns the location of the inventory config
+func DefaultConfigPath() (string, error) {
+	return store.Path("inventory.yaml")
+}
+
+// LoadConfig reads the config at path, parsed strictly. A missing file returns nil, meaning
+// inventory sync is off.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory config: %w", err)
+	}
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse inventory config %s: %w", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid inventory config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+func (c *Config) validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	switch c.Format {
+	case "":
+		c.Format = FormatHTTP
+	case FormatHTTP, FormatServiceNow:
+	default:
+		return fmt.Errorf("unknown format %q; use %s or %s", c.Format, FormatHTTP, FormatServiceNow)
+	}
+	for target, source := range c.Fields {
+		if !knownField(source) {
+			return fmt.Errorf("field %s maps unknown inventory field %q", target, source)
+		}
+	}
+	return nil
+}
+
+// recordFields are the inventory fields every record has
+var recordFields = []string{"name", "provider", "region", "version", "status", "node_count", "endpoint", "owner", "created_at"}
+
+func knownField(name string) bool {
+	if strings.HasPrefix(name, "tags.") {
+		return true
+	}
+	for _, field := range recordFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Record returns the inventory fields of cluster. Tags are included as tags.<key>.
+func Record(cluster *providers.Cluster) map[string]any {
+	record := map[string]any{
+		"name":       cluster.Name,
+		"provider":   cluster.Provider,
+		"region":     cluster.Region,
+		"version":    cluster.Version,
+		"status":     string(cluster.Status),
+		"node_count": cluster.NodeCount,
+		"endpoint":   cluster.Endpoint,
+		"owner":      owner(cluster.Tags),
+	}
+	if !cluster.CreatedAt.IsZero() {
+		record["created_at"] = cluster.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	for key, value := range cluster.Tags {
+		record["tags."+key] = value
+	}
+	return record
+}
+
+// owner returns the owner tag, whatever prefix the tag policy gives it
+func owner(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == "owner" || strings.HasSuffix(key, ":owner") {
+			return tags[key]
+		}
+	}
+	return ""
+}
+
+// Map renames record's fields to the inventory's, keeping only the mapped ones. Without a
+// mapping the record is returned unchanged.
+func (c *Config) Map(record map[string]any) map[string]any {
+	if len(c.Fields) == 0 {
+		return record
+	}
+	mapped := make(map[string]any, len(c.Fields))
+	for target, source := range c.Fields {
+		if value, ok := record[source]; ok {
+			mapped[target] = value
+		} else {
+			mapped[target] = ""
+		}
+	}
+	return mapped
+}
+
+// Push sends one cluster's record to the inventory
+func (c *Config) Push(ctx context.Context, event string, cluster *providers.Cluster) error {
+	fields := c.Map(Record(cluster))
+	var payload any = map[string]any{"event": event, "cluster": fields}
+	if c.Format == FormatServiceNow {
+		fields["u_atlas_event"] = event
+		payload = fields
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal inventory record: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build inventory request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for name, value := range c.Headers {
+		req.Header.Set(name, value)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	} else if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push cluster %s to inventory: %w", cluster.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("inventory push of cluster %s returned %s", cluster.Name, resp.Status)
+	}
+	return nil
+}
+
+// Key identifies a cluster across providers and regions
+func Key(cluster *providers.Cluster) string {
+	return cluster.Provider + "/" + cluster.Region + "/" + cluster.Name
+}
+
+// State remembers a digest of the last record pushed for each cluster, so syncs only send
+// clusters that changed
+type State struct {
+	mu      sync.Mutex
+	path    string
+	Digests map[string]string `json:"digests"`
+}
+
+// DefaultStatePath returns the location of the sync state
+func DefaultStatePath() (string, error) {
+	return store.Path("inventory-state.json")
+}
+
+// LoadState reads the sync state at path; a missing file starts empty
+func LoadState(path string) (*State, error) {
+	s := &State{path: path, Digests: make(map[string]string)}
+	if err := store.LoadJSON(path, s); err != nil {
+		return nil, err
+	}
+	if s.Digests == nil {
+		s.Digests = make(map[string]string)
+	}
+	return s, nil
+}
+
+// Changed reports whether cluster differs from what was last pushed
+func (s *State) Changed(cluster *providers.Cluster) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Digests[Key(cluster)] != digest(cluster)
+}
+
+// Pushed records that cluster's current record was pushed
+func (s *State) Pushed(cluster *providers.Cluster) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Digests[Key(cluster)] = digest(cluster)
+}
+
+// Deleted forgets a cluster whose deletion was pushed
+func (s *State) Deleted(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.Digests, key)
+}
+
+// Keys returns the clusters that have been pushed, sorted
+func (s *State) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.Digests))
+	for key := range s.Digests {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Save writes the sync state back to disk
+func (s *State) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return store.SaveJSON(s.path, s)
+}
+
+// digest hashes the fields of cluster's record; json sorts map keys, so equal records hash equally
+func digest(cluster *providers.Cluster) string {
+	data, _ := json.Marshal(Record(cluster))
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// KeyCluster returns a placeholder cluster for key, enough to push its deletion
+func KeyCluster(key string) *providers.Cluster {
+	parts := strings.SplitN(key, "/", 3)
+	if len(parts) != 3 {
+		return &providers.Cluster{Name: key}
+	}
+	return &providers.Cluster{Provider: parts[0], Region: parts[1], Name: parts[2], Status: providers.ClusterStatusDeleting}
+}
@@ -0,0 +1,151 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/providers"
+)
+
+func testCluster() *providers.Cluster {
+	return &providers.Cluster{
+		Name:      "dev",
+		Provider:  "aws",
+		Region:    "us-west-2",
+		Version:   "1.31",
+		Status:    providers.ClusterStatusRunning,
+		NodeCount: 3,
+		Endpoint:  "https://dev.example.com",
+		Tags:      map[string]string{"atlas:owner": "alice", "cost-center": "42"},
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "inventory.yaml")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	if config, err := LoadConfig(filepath.Join(dir, "missing.yaml")); config != nil || err != nil {
+		t.Errorf("LoadConfig() of a missing file = %v, %v, want nil, nil", config, err)
+	}
+
+	config, err := LoadConfig(write("url: https://cmdb.example.com\nfields:\n  u_owner: owner\n"))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.Format != FormatHTTP {
+		t.Errorf("Format = %q, want the %q default", config.Format, FormatHTTP)
+	}
+
+	for name, content := range map[string]string{
+		"missing url":   "format: http\n",
+		"bad format":    "url: https://cmdb.example.com\nformat: soap\n",
+		"unknown field": "url: https://cmdb.example.com\nfields:\n  u_team: team\n",
+		"unknown key":   "url: https://cmdb.example.com\nendpoint: x\n",
+	} {
+		if _, err := LoadConfig(write(content)); err == nil {
+			t.Errorf("LoadConfig() with %s should fail", name)
+		}
+	}
+}
+
+func TestConfig_Push(t *testing.T) {
+	var got map[string]any
+	var user, password string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ = r.BasicAuth()
+		got = nil
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	config := &Config{URL: server.URL, Format: FormatHTTP}
+	if err := config.Push(context.Background(), EventUpserted, testCluster()); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	cluster, _ := got["cluster"].(map[string]any)
+	if got["event"] != EventUpserted || cluster["owner"] != "alice" || cluster["tags.cost-center"] != "42" || cluster["node_count"] != float64(3) {
+		t.Errorf("http payload = %v", got)
+	}
+
+	config = &Config{
+		URL:      server.URL,
+		Format:   FormatServiceNow,
+		Username: "atlas",
+		Password: "secret",
+		Fields:   map[string]string{"u_name": "name", "u_cost_center": "tags.cost-center", "u_team": "tags.team"},
+	}
+	if err := config.Push(context.Background(), EventDeleted, testCluster()); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	want := map[string]any{"u_name": "dev", "u_cost_center": "42", "u_team": "", "u_atlas_event": EventDeleted}
+	if len(got) != len(want) {
+		t.Errorf("servicenow payload = %v, want %v", got, want)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("servicenow payload[%s] = %v, want %v", key, got[key], value)
+		}
+	}
+	if user != "atlas" || password != "secret" {
+		t.Errorf("basic auth = %s:%s, want atlas:secret", user, password)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer failing.Close()
+	config = &Config{URL: failing.URL, Format: FormatHTTP}
+	if err := config.Push(context.Background(), EventUpserted, testCluster()); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Push() to a rejecting endpoint error = %v, want the status", err)
+	}
+}
+
+func TestState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cluster := testCluster()
+	if !state.Changed(cluster) {
+		t.Error("a cluster that was never pushed should count as changed")
+	}
+	state.Pushed(cluster)
+	if err := state.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err = LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Changed(cluster) {
+		t.Error("an unchanged cluster should not count as changed after a reload")
+	}
+	cluster.NodeCount = 5
+	if !state.Changed(cluster) {
+		t.Error("scaling a cluster should count as a change")
+	}
+
+	key := Key(cluster)
+	if deleted := KeyCluster(key); deleted.Name != "dev" || deleted.Provider != "aws" || deleted.Region != "us-west-2" {
+		t.Errorf("KeyCluster(%q) = %+v", key, deleted)
+	}
+	state.Deleted(key)
+	if len(state.Keys()) != 0 {
+		t.Errorf("Keys() after Deleted() = %v, want none", state.Keys())
+	}
+}
@@ -0,0 +1,220 @@
+// Package kubeversions knows when each Kubernetes minor release stops being supported upstream, so
+// clusters running an end-of-life version can be flagged. A table is embedded in the binary; a
+// newer one fetched with Refresh takes its place once saved.
+package kubeversions
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+)
+
+// DefaultURL serves the support table in the same format as the embedded one
+const DefaultURL = "https://endoflife.date/api/kubernetes.json"
+
+// SoonWindow is how long before its end of life a version is reported as ending soon
+const SoonWindow = 90 * 24 * time.Hour
+
+// Support states
+const (
+	StatusSupported  = "supported"
+	StatusEndingSoon = "ending-soon"
+	StatusEOL        = "eol"
+	StatusUnknown    = "unknown"
+)
+
+//go:embed releases.json
+var embedded []byte
+
+// Release is the support window of one minor release, e.g. 1.31. Dates are YYYY-MM-DD.
+type Release struct {
+	Cycle       string `json:"cycle"`
+	ReleaseDate string `json:"releaseDate"`
+	EOL         string `json:"eol"`
+}
+
+// Table is a set of release support windows
+type Table struct {
+	Releases []Release `json:"releases"`
+	// Source is "embedded" or the file the table was loaded from
+	Source string `json:"source"`
+}
+
+// Support is how a cluster's version stands against its release's support window
+type Support struct {
+	Version string `json:"version"`
+	Cycle   string `json:"cycle,omitempty"`
+	Status  string `json:"status"`
+	EOL     string `json:"eol,omitempty"`
+	// DaysLeft is negative once the release has reached end of life
+	DaysLeft *int `json:"daysLeft,omitempty"`
+}
+
+// DefaultCachePath returns where a refreshed table is saved
+func DefaultCachePath() (string, error) {
+	return store.Path("kubernetes-versions.json")
+}
+
+// Embedded returns the table built into the binary
+func Embedded() *Table {
+	table, err := Parse(embedded)
+	if err != nil {
+		panic(fmt.Sprintf("embedded kubernetes release table is invalid: %v", err))
+	}
+	table.Source = "embedded"
+	return table
+}
+
+// Load returns the refreshed table saved at path, or the embedded one when there is none
+func Load(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Embedded(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kubernetes release table: %w", err)
+	}
+	table, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubernetes release table %s: %w", path, err)
+	}
+	table.Source = path
+	return table, nil
+}
+
+// Parse reads a JSON array of releases as served by DefaultURL. A release whose eol is false
+// (no date announced yet) is kept without one.
+func Parse(data []byte) (*Table, error) {
+	var raw []struct {
+		Cycle       string          `json:"cycle"`
+		ReleaseDate string          `json:"releaseDate"`
+		EOL         json.RawMessage `json:"eol"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("no releases")
+	}
+	table := &Table{}
+	for _, r := range raw {
+		if _, _, ok := minorVersion(r.Cycle); !ok {
+			return nil, fmt.Errorf("invalid release cycle %q", r.Cycle)
+		}
+		release := Release{Cycle: r.Cycle, ReleaseDate: r.ReleaseDate}
+		var eol string
+		if json.Unmarshal(r.EOL, &eol) == nil {
+			if _, err := time.Parse(time.DateOnly, eol); err != nil {
+				return nil, fmt.Errorf("release %s has invalid eol %q", r.Cycle, eol)
+			}
+			release.EOL = eol
+		}
+		table.Releases = append(table.Releases, release)
+	}
+	return table, nil
+}
+
+// Refresh downloads the support table from url, checks it parses and saves it to path
+func Refresh(ctx context.Context, url, path string) (*Table, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download kubernetes release table: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("downloading kubernetes release table returned %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download kubernetes release table: %w", err)
+	}
+	table, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("downloaded kubernetes release table is invalid: %w", err)
+	}
+
+	if err := store.WriteFile(path, data); err != nil {
+		return nil, err
+	}
+	table.Source = path
+	return table, nil
+}
+
+// Check reports how version, e.g. v1.31.2 or 1.31, stands at now
+func (t *Table) Check(version string, now time.Time) Support {
+	support := Support{Version: version, Status: StatusUnknown}
+	major, minor, ok := minorVersion(version)
+	if !ok {
+		return support
+	}
+	for _, release := range t.Releases {
+		releaseMajor, releaseMinor, _ := minorVersion(release.Cycle)
+		if releaseMajor != major || releaseMinor != minor {
+			continue
+		}
+		support.Cycle = release.Cycle
+		support.Status = StatusSupported
+		if release.EOL == "" {
+			return support
+		}
+		eol, _ := time.Parse(time.DateOnly, release.EOL)
+		support.EOL = release.EOL
+		days := int(eol.Sub(now).Hours() / 24)
+		support.DaysLeft = &days
+		switch {
+		case !now.Before(eol):
+			support.Status = StatusEOL
+		case eol.Sub(now) <= SoonWindow:
+			support.Status = StatusEndingSoon
+		}
+		return support
+	}
+	return support
+}
+
+// Warning describes an EOL or soon-EOL version, or returns "" when there is nothing to warn about
+func (s Support) Warning(cluster string) string {
+	switch s.Status {
+	case StatusEOL:
+		return fmt.Sprintf("cluster %s runs Kubernetes %s, which reached end of life on %s; upgrade to a supported version",
+			cluster, s.Cycle, s.EOL)
+	case StatusEndingSoon:
+		return fmt.Sprintf("cluster %s runs Kubernetes %s, which reaches end of life on %s (%d days)",
+			cluster, s.Cycle, s.EOL, *s.DaysLeft)
+	}
+	return ""
+}
+
+// minorVersion parses the major and minor numbers of versions such as v1.31.2, 1.31 or
+// v1.30.0-eks-1234
+func minorVersion(version string) (int, int, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(strings.SplitN(parts[1], "-", 2)[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
@@ -0,0 +1,93 @@
+package kubeversions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testTable = `[
+  {"cycle": "1.32", "releaseDate": "2024-12-11", "eol": "2026-02-28"},
+  {"cycle": "1.31", "releaseDate": "2024-08-13", "eol": "2025-10-28"},
+  {"cycle": "1.33", "releaseDate": "2025-04-23", "eol": false}
+]`
+
+func TestParse(t *testing.T) {
+	table, err := Parse([]byte(testTable))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(table.Releases) != 3 || table.Releases[2].EOL != "" {
+		t.Errorf("Parse() = %+v, want 3 releases with no eol for 1.33", table.Releases)
+	}
+
+	for _, invalid := range []string{`[]`, `{}`, `[{"cycle": "latest", "eol": false}]`, `[{"cycle": "1.30", "eol": "soon"}]`} {
+		if _, err := Parse([]byte(invalid)); err == nil {
+			t.Errorf("Parse(%s) expected an error", invalid)
+		}
+	}
+}
+
+func TestEmbedded(t *testing.T) {
+	if table := Embedded(); len(table.Releases) == 0 || table.Source != "embedded" {
+		t.Errorf("Embedded() = %+v", table)
+	}
+}
+
+func TestTable_Check(t *testing.T) {
+	table, err := Parse([]byte(testTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		version  string
+		status   string
+		daysLeft int
+	}{
+		{"v1.31.2", StatusEOL, -65},
+		{"1.32.0-eks-1234", StatusEndingSoon, 58},
+		{"1.33", StatusSupported, 0},
+		{"1.40.0", StatusUnknown, 0},
+		{"", StatusUnknown, 0},
+	}
+	for _, tt := range tests {
+		got := table.Check(tt.version, now)
+		if got.Status != tt.status {
+			t.Errorf("Check(%q).Status = %q, want %q", tt.version, got.Status, tt.status)
+		}
+		if tt.daysLeft != 0 && (got.DaysLeft == nil || *got.DaysLeft != tt.daysLeft) {
+			t.Errorf("Check(%q).DaysLeft = %v, want %d", tt.version, got.DaysLeft, tt.daysLeft)
+		}
+		warns := tt.status == StatusEOL || tt.status == StatusEndingSoon
+		if (got.Warning("dev") != "") != warns {
+			t.Errorf("Check(%q).Warning() = %q, want a warning: %v", tt.version, got.Warning("dev"), warns)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	table, err := Load(filepath.Join(dir, "missing.json"))
+	if err != nil || table.Source != "embedded" {
+		t.Errorf("Load(missing) = %+v, %v; want the embedded table", table, err)
+	}
+
+	path := filepath.Join(dir, "kubernetes-versions.json")
+	if err := os.WriteFile(path, []byte(testTable), 0644); err != nil {
+		t.Fatal(err)
+	}
+	table, err = Load(path)
+	if err != nil || table.Source != path || len(table.Releases) != 3 {
+		t.Errorf("Load(saved) = %+v, %v", table, err)
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load(corrupt) expected an error")
+	}
+}
@@ -4,9 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
 )
 
 type AWSLogSource struct {
@@ -26,7 +27,7 @@ func (a *AWSLogSource) GetSourceName() string {
 }
 
 func (a *AWSLogSource) GetClusterHistory(ctx context.Context, clusterName string, limit int) ([]*OperationHistory, error) {
-	cmd := exec.CommandContext(ctx, "aws", "logs", "describe-log-streams",
+	cmd := subprocess.CommandContext(ctx, "aws", "logs", "describe-log-streams",
 		"--log-group-name", fmt.Sprintf("/aws/eks/%s/cluster", clusterName),
 		"--region", a.region,
 		"--max-items", fmt.Sprintf("%d", limit))
@@ -85,7 +86,7 @@ func (a *AWSLogSource) GetClusterHistory(ctx context.Context, clusterName string
 }
 
 func (a *AWSLogSource) GetAllClustersHistory(ctx context.Context, limit int) (map[string][]*OperationHistory, error) {
-	cmd := exec.CommandContext(ctx, "aws", "eks", "list-clusters",
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "list-clusters",
 		"--region", a.region)
 
 	if a.profile != "" {
@@ -118,7 +119,7 @@ func (a *AWSLogSource) GetAllClustersHistory(ctx context.Context, limit int) (ma
 }
 
 func (a *AWSLogSource) getClusterEvents(ctx context.Context, clusterName string) ([]*OperationHistory, error) {
-	cmd := exec.CommandContext(ctx, "aws", "eks", "describe-cluster",
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "describe-cluster",
 		"--name", clusterName,
 		"--region", a.region,
 		"--query", "cluster.{name:name,status:status,createdAt:createdAt,version:version}")
@@ -0,0 +1,168 @@
+package logsource
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+)
+
+// maxDeployEntries caps the deploy log; the oldest deployments are dropped first
+const maxDeployEntries = 1000
+
+// DeployLog records the workload changes Atlas makes to clusters, such as chart installs and
+// workload migrations. Providers only log infrastructure operations, so merging its Source into
+// theirs puts both on one timeline.
+type DeployLog struct {
+	mu   sync.Mutex
+	path string
+}
+
+type deployEntry struct {
+	// Provider is the provider the cluster runs on, or empty when the deploy only knew its
+	// kubeconfig context
+	Provider string `json:"provider,omitempty"`
+	OperationHistory
+}
+
+// DefaultDeployLogPath returns the location of the deploy log
+func DefaultDeployLogPath() (string, error) {
+	return store.Path("deployments.json")
+}
+
+// NewDeployLog creates a deploy log stored at path
+func NewDeployLog(path string) *DeployLog {
+	return &DeployLog{path: path}
+}
+
+// Record appends op as a deploy operation on a cluster of provider, numbering it after the
+// deployments already logged
+func (d *DeployLog) Record(provider string, op *OperationHistory) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var entries []*deployEntry
+	return store.UpdateJSON(d.path, &entries, func() error {
+		op.OperationType = OpTypeDeploy
+		op.ID = 1
+		if len(entries) > 0 {
+			op.ID = entries[len(entries)-1].ID + 1
+		}
+		entries = append(entries, &deployEntry{Provider: provider, OperationHistory: *op})
+		if len(entries) > maxDeployEntries {
+			entries = entries[len(entries)-maxDeployEntries:]
+		}
+		return nil
+	})
+}
+
+func (d *DeployLog) load() ([]*deployEntry, error) {
+	var entries []*deployEntry
+	if err := store.LoadJSON(d.path, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Source serves the deployments made to clusters of provider, and those recorded without one
+func (d *DeployLog) Source(provider string) LogSource {
+	return &deploySource{log: d, provider: provider}
+}
+
+type deploySource struct {
+	log      *DeployLog
+	provider string
+}
+
+func (s *deploySource) GetSourceName() string {
+	return "deploy"
+}
+
+func (s *deploySource) GetClusterHistory(ctx context.Context, clusterName string, limit int) ([]*OperationHistory, error) {
+	all, err := s.GetAllClustersHistory(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return all[clusterName], nil
+}
+
+// GetAllClustersHistory returns each cluster's deployments newest first
+func (s *deploySource) GetAllClustersHistory(ctx context.Context, limit int) (map[string][]*OperationHistory, error) {
+	s.log.mu.Lock()
+	entries, err := s.log.load()
+	s.log.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]*OperationHistory)
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.Provider != "" && entry.Provider != s.provider {
+			continue
+		}
+		if limit > 0 && len(result[entry.ClusterName]) >= limit {
+			continue
+		}
+		op := entry.OperationHistory
+		result[entry.ClusterName] = append(result[entry.ClusterName], &op)
+	}
+	return result, nil
+}
+
+// Merge combines log sources into one that returns each cluster's operations from all of them,
+// newest first
+func Merge(sources ...LogSource) LogSource {
+	return &mergedSource{sources: sources}
+}
+
+type mergedSource struct {
+	sources []LogSource
+}
+
+func (m *mergedSource) GetSourceName() string {
+	names := make([]string, len(m.sources))
+	for i, source := range m.sources {
+		names[i] = source.GetSourceName()
+	}
+	return strings.Join(names, "+")
+}
+
+func (m *mergedSource) GetClusterHistory(ctx context.Context, clusterName string, limit int) ([]*OperationHistory, error) {
+	var history []*OperationHistory
+	for _, source := range m.sources {
+		ops, err := source.GetClusterHistory(ctx, clusterName, limit)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, ops...)
+	}
+	return newestFirst(history, limit), nil
+}
+
+func (m *mergedSource) GetAllClustersHistory(ctx context.Context, limit int) (map[string][]*OperationHistory, error) {
+	result := make(map[string][]*OperationHistory)
+	for _, source := range m.sources {
+		histories, err := source.GetAllClustersHistory(ctx, limit)
+		if err != nil {
+			return nil, err
+		}
+		for clusterName, ops := range histories {
+			result[clusterName] = append(result[clusterName], ops...)
+		}
+	}
+	for clusterName, ops := range result {
+		result[clusterName] = newestFirst(ops, limit)
+	}
+	return result, nil
+}
+
+// newestFirst sorts ops by start time, newest first, and keeps at most limit of them
+func newestFirst(ops []*OperationHistory, limit int) []*OperationHistory {
+	sort.SliceStable(ops, func(i, j int) bool { return ops[i].StartedAt.After(ops[j].StartedAt) })
+	if limit > 0 && len(ops) > limit {
+		ops = ops[:limit]
+	}
+	return ops
+}
@@ -0,0 +1,83 @@
+package logsource
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// staticSource serves fixed histories, standing in for a provider's log source
+type staticSource map[string][]*OperationHistory
+
+func (s staticSource) GetSourceName() string { return "static" }
+
+func (s staticSource) GetClusterHistory(ctx context.Context, clusterName string, limit int) ([]*OperationHistory, error) {
+	return s[clusterName], nil
+}
+
+func (s staticSource) GetAllClustersHistory(ctx context.Context, limit int) (map[string][]*OperationHistory, error) {
+	return s, nil
+}
+
+func TestDeployLog(t *testing.T) {
+	ctx := context.Background()
+	started := time.Date(2025, time.May, 3, 12, 0, 0, 0, time.UTC)
+	log := NewDeployLog(filepath.Join(t.TempDir(), "deployments.json"))
+
+	for _, record := range []struct {
+		provider string
+		op       *OperationHistory
+	}{
+		{"local", &OperationHistory{ClusterName: "dev", StartedAt: started, OperationStatus: OpStatusCompleted}},
+		{"aws", &OperationHistory{ClusterName: "dev", StartedAt: started.Add(time.Minute), OperationStatus: OpStatusCompleted}},
+		{"", &OperationHistory{ClusterName: "dev", StartedAt: started.Add(2 * time.Minute), OperationStatus: OpStatusFailed}},
+	} {
+		if err := log.Record(record.provider, record.op); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := log.Source("local").GetClusterHistory(ctx, "dev", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("GetClusterHistory() returned %d deployments, want the local one and the one without a provider", len(history))
+	}
+	if history[0].ID != 3 || history[1].ID != 1 || history[0].OperationType != OpTypeDeploy {
+		t.Errorf("history = %+v, %+v, want deploys 3 then 1", history[0], history[1])
+	}
+	if history, _ := log.Source("local").GetClusterHistory(ctx, "dev", 1); len(history) != 1 || history[0].ID != 3 {
+		t.Errorf("GetClusterHistory(limit 1) = %v, want the newest deploy", history)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	ctx := context.Background()
+	started := time.Date(2025, time.May, 3, 12, 0, 0, 0, time.UTC)
+	infra := staticSource{"dev": {
+		{ID: 1, OperationType: OpTypeCreate, StartedAt: started},
+		{ID: 2, OperationType: OpTypeScale, StartedAt: started.Add(2 * time.Hour)},
+	}}
+	deploys := staticSource{"dev": {{ID: 1, OperationType: OpTypeDeploy, StartedAt: started.Add(time.Hour)}}}
+	merged := Merge(infra, deploys)
+
+	history, err := merged.GetClusterHistory(ctx, "dev", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []OperationType
+	for _, op := range history {
+		types = append(types, op.OperationType)
+	}
+	if len(types) != 3 || types[0] != OpTypeScale || types[1] != OpTypeDeploy || types[2] != OpTypeCreate {
+		t.Errorf("merged history = %v, want scale, deploy, create", types)
+	}
+	if all, _ := merged.GetAllClustersHistory(ctx, 2); len(all["dev"]) != 2 {
+		t.Errorf("GetAllClustersHistory(limit 2) returned %d operations, want 2", len(all["dev"]))
+	}
+	if name := merged.GetSourceName(); name != "static+static" {
+		t.Errorf("GetSourceName() = %q", name)
+	}
+}
@@ -0,0 +1,103 @@
+package logsource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// DockerLogSource implements LogSource for clusters whose nodes run as docker containers, such as
+// kind and k3d clusters. Neither keeps an audit log, so the only operation it can report is each
+// cluster's creation, taken from its control-plane container.
+type DockerLogSource struct {
+	name          string
+	listClusters  func(ctx context.Context) ([]string, error)
+	nodeContainer func(clusterName string) string
+}
+
+// NewKindLogSource creates a new kind log source
+func NewKindLogSource() *DockerLogSource {
+	return &DockerLogSource{
+		name: "kind",
+		listClusters: func(ctx context.Context) ([]string, error) {
+			output, err := subprocess.CommandContext(ctx, "kind", "get", "clusters").Output()
+			if err != nil {
+				return nil, err
+			}
+			return strings.Fields(string(output)), nil
+		},
+		nodeContainer: func(clusterName string) string { return clusterName + "-control-plane" },
+	}
+}
+
+// NewK3dLogSource creates a new k3d log source
+func NewK3dLogSource() *DockerLogSource {
+	return &DockerLogSource{
+		name: "k3d",
+		listClusters: func(ctx context.Context) ([]string, error) {
+			output, err := subprocess.CommandContext(ctx, "k3d", "cluster", "list", "-o", "json").Output()
+			if err != nil {
+				return nil, err
+			}
+			var clusters []struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(output, &clusters); err != nil {
+				return nil, err
+			}
+			var names []string
+			for _, cluster := range clusters {
+				names = append(names, cluster.Name)
+			}
+			return names, nil
+		},
+		nodeContainer: func(clusterName string) string { return "k3d-" + clusterName + "-server-0" },
+	}
+}
+
+func (d *DockerLogSource) GetSourceName() string {
+	return d.name
+}
+
+func (d *DockerLogSource) GetClusterHistory(ctx context.Context, clusterName string, limit int) ([]*OperationHistory, error) {
+	output, err := subprocess.CommandContext(ctx, "docker", "inspect", "-f", "{{.Created}}", d.nodeContainer(clusterName)).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s cluster %s: %w", d.name, clusterName, err)
+	}
+	created, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(output)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse creation time of %s cluster %s: %w", d.name, clusterName, err)
+	}
+	if limit < 1 {
+		return nil, nil
+	}
+	return []*OperationHistory{{
+		ClusterName:     clusterName,
+		OperationType:   OpTypeCreate,
+		OperationStatus: OpStatusCompleted,
+		StartedAt:       created,
+		CompletedAt:     &created,
+		Metadata:        map[string]string{"source": d.name},
+	}}, nil
+}
+
+func (d *DockerLogSource) GetAllClustersHistory(ctx context.Context, limit int) (map[string][]*OperationHistory, error) {
+	names, err := d.listClusters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s clusters: %w", d.name, err)
+	}
+
+	histories := make(map[string][]*OperationHistory)
+	for _, name := range names {
+		history, err := d.GetClusterHistory(ctx, name, limit)
+		if err != nil {
+			continue
+		}
+		histories[name] = history
+	}
+	return histories, nil
+}
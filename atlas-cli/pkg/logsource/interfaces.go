@@ -2,6 +2,9 @@ package logsource
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"time"
 )
 
@@ -30,6 +33,15 @@ type OperationHistory struct {
 	OperationDetails map[string]interface{} `json:"operation_details,omitempty"`
 	ErrorMessage     string                 `json:"error_message,omitempty"`
 	Metadata         map[string]string      `json:"metadata,omitempty"`
+	// Ref is set from OperationRef by callers that show operations to be referred back to
+	Ref string `json:"ref,omitempty"`
+}
+
+// OperationRef identifies op across history queries, e.g. op-1a2b3c4d. Most log sources don't
+// number operations, so it is derived from the cluster, type and start time.
+func OperationRef(op *OperationHistory) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d|%d", op.ClusterName, op.OperationType, op.ID, op.StartedAt.UnixNano())))
+	return "op-" + hex.EncodeToString(sum[:4])
 }
 
 // ClusterInfo represents basic cluster information from logs
@@ -66,6 +78,8 @@ const (
 	OpTypeDelete OperationType = "delete"
 	OpTypeScale  OperationType = "scale"
 	OpTypeUpdate OperationType = "update"
+	// OpTypeDeploy is a workload change recorded in the DeployLog
+	OpTypeDeploy OperationType = "deploy"
 )
 
 // Operation status from logs
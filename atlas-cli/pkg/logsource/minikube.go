@@ -3,11 +3,12 @@ package logsource
 import (
 	"context"
 	"fmt"
-	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
 )
 
 // MinikubeLogSource implements LogSource using minikube's audit logs and commands
@@ -46,7 +47,7 @@ func (m *MinikubeLogSource) GetSourceName() string {
 }
 
 func (m *MinikubeLogSource) GetClusterHistory(ctx context.Context, clusterName string, limit int) ([]*OperationHistory, error) {
-	cmd := exec.CommandContext(ctx, "minikube", "logs", "--audit", "-n", strconv.Itoa(limit*2))
+	cmd := subprocess.CommandContext(ctx, "minikube", "logs", "--audit", "-n", strconv.Itoa(limit*2))
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get minikube audit logs: %w", err)
@@ -72,7 +73,7 @@ func (m *MinikubeLogSource) GetClusterHistory(ctx context.Context, clusterName s
 }
 
 func (m *MinikubeLogSource) GetAllClustersHistory(ctx context.Context, limit int) (map[string][]*OperationHistory, error) {
-	cmd := exec.CommandContext(ctx, "minikube", "logs", "--audit", "-n", strconv.Itoa(limit*5))
+	cmd := subprocess.CommandContext(ctx, "minikube", "logs", "--audit", "-n", strconv.Itoa(limit*5))
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get minikube audit logs: %w", err)
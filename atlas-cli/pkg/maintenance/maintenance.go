@@ -0,0 +1,205 @@
+// Package maintenance stores per-cluster maintenance windows: the recurring times at which
+// disruptive actions such as stopping, scaling or deleting a cluster are expected.
+package maintenance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+)
+
+// maxDuration keeps a window shorter than the week it repeats in
+const maxDuration = 7 * 24 * time.Hour
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Window is a recurring maintenance window that opens at Start on each of Days, in Timezone, and
+// stays open for Duration
+type Window struct {
+	// Days lists the weekdays the window opens on (mon, tue, ...); empty means every day
+	Days     []string `json:"days,omitempty"`
+	Start    string   `json:"start"`
+	Duration string   `json:"duration"`
+	Timezone string   `json:"timezone,omitempty"`
+}
+
+// window is a validated, parsed Window
+type window struct {
+	days     map[time.Weekday]bool
+	hour     int
+	minute   int
+	duration time.Duration
+	location *time.Location
+}
+
+func (w Window) parse() (*window, error) {
+	parsed := &window{days: make(map[time.Weekday]bool), location: time.UTC}
+	for _, day := range w.Days {
+		weekday, ok := weekdays[normalizeDay(day)]
+		if !ok {
+			return nil, fmt.Errorf("invalid maintenance window day %q (want mon, tue, ... sun)", day)
+		}
+		parsed.days[weekday] = true
+	}
+
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maintenance window start %q (want HH:MM)", w.Start)
+	}
+	parsed.hour, parsed.minute = start.Hour(), start.Minute()
+
+	parsed.duration, err = time.ParseDuration(w.Duration)
+	if err != nil || parsed.duration <= 0 || parsed.duration > maxDuration {
+		return nil, fmt.Errorf("invalid maintenance window duration %q (want e.g. 4h, at most 168h)", w.Duration)
+	}
+
+	if w.Timezone != "" {
+		parsed.location, err = time.LoadLocation(w.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance window timezone %q: %w", w.Timezone, err)
+		}
+	}
+	return parsed, nil
+}
+
+// normalizeDay turns "Saturday" or " SAT" into "sat"
+func normalizeDay(day string) string {
+	day = strings.ToLower(strings.TrimSpace(day))
+	if len(day) > 3 {
+		day = day[:3]
+	}
+	return day
+}
+
+// Validate checks that the window's days, start, duration and timezone all parse
+func (w Window) Validate() error {
+	_, err := w.parse()
+	return err
+}
+
+// opening returns when the window opens on the calendar day of t, and whether it opens that day
+func (p *window) opening(t time.Time) (time.Time, bool) {
+	local := t.In(p.location)
+	start := time.Date(local.Year(), local.Month(), local.Day(), p.hour, p.minute, 0, 0, p.location)
+	return start, len(p.days) == 0 || p.days[start.Weekday()]
+}
+
+// Contains reports whether the window is open at t
+func (w Window) Contains(t time.Time) (bool, error) {
+	p, err := w.parse()
+	if err != nil {
+		return false, err
+	}
+	// A window that opened on any of the previous eight days may still be open
+	for days := 0; days <= 8; days++ {
+		start, ok := p.opening(t.AddDate(0, 0, -days))
+		if ok && !t.Before(start) && t.Before(start.Add(p.duration)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Next returns the first time at or after t that the window opens
+func (w Window) Next(t time.Time) (time.Time, error) {
+	p, err := w.parse()
+	if err != nil {
+		return time.Time{}, err
+	}
+	for days := 0; days <= 8; days++ {
+		start, ok := p.opening(t.AddDate(0, 0, days))
+		if ok && !start.Before(t) {
+			return start, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("maintenance window never opens")
+}
+
+// String describes the window, e.g. "sat,sun 02:00 for 4h (UTC)"
+func (w Window) String() string {
+	days := "daily"
+	if len(w.Days) > 0 {
+		days = strings.Join(w.Days, ",")
+	}
+	timezone := w.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	return fmt.Sprintf("%s %s for %s (%s)", days, w.Start, w.Duration, timezone)
+}
+
+// DefaultStorePath returns the location of the maintenance windows file
+func DefaultStorePath() (string, error) {
+	return store.Path("maintenance.json")
+}
+
+// Store holds the maintenance window of each cluster on disk
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	windows map[string]Window
+}
+
+// LoadStore reads the windows at path; a missing file starts empty
+func LoadStore(path string) (*Store, error) {
+	s := &Store{path: path, windows: make(map[string]Window)}
+	if err := store.LoadJSON(path, &s.windows); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get returns the window for cluster, if it has one
+func (s *Store) Get(cluster string) (Window, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, ok := s.windows[cluster]
+	return w, ok
+}
+
+// Set validates w and makes it cluster's window
+func (s *Store) Set(cluster string, w Window) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	for i, day := range w.Days {
+		w.Days[i] = normalizeDay(day)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.windows[cluster] = w
+	return nil
+}
+
+// Clear removes cluster's window
+func (s *Store) Clear(cluster string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.windows, cluster)
+}
+
+// Clusters returns the clusters that have a window, sorted
+func (s *Store) Clusters() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clusters := make([]string, 0, len(s.windows))
+	for cluster := range s.windows {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+// Save writes the windows back to disk
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return store.SaveJSON(s.path, s.windows)
+}
@@ -0,0 +1,113 @@
+package maintenance
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWindow_Contains(t *testing.T) {
+	// Saturday 2025-05-03
+	saturday := func(hour, minute int) time.Time {
+		return time.Date(2025, time.May, 3, hour, minute, 0, 0, time.UTC)
+	}
+	weekend := Window{Days: []string{"sat"}, Start: "22:00", Duration: "4h"}
+
+	tests := []struct {
+		name   string
+		window Window
+		at     time.Time
+		want   bool
+	}{
+		{"before opening", weekend, saturday(21, 59), false},
+		{"at opening", weekend, saturday(22, 0), true},
+		{"past midnight into sunday", weekend, saturday(22, 0).Add(3 * time.Hour), true},
+		{"closed at the end", weekend, saturday(22, 0).Add(4 * time.Hour), false},
+		{"wrong day", weekend, saturday(23, 0).AddDate(0, 0, 1), false},
+		{"daily", Window{Start: "02:00", Duration: "1h"}, time.Date(2025, time.May, 7, 2, 30, 0, 0, time.UTC), true},
+		{"timezone", Window{Start: "02:00", Duration: "1h", Timezone: "Asia/Tokyo"}, time.Date(2025, time.May, 6, 17, 30, 0, 0, time.UTC), true},
+		{"week long", Window{Days: []string{"mon"}, Start: "00:00", Duration: "168h"}, time.Date(2025, time.May, 4, 23, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.window.Contains(tt.at)
+			if err != nil {
+				t.Fatalf("Contains() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Contains(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindow_Next(t *testing.T) {
+	window := Window{Days: []string{"sat", "sun"}, Start: "02:00", Duration: "4h"}
+	wednesday := time.Date(2025, time.April, 30, 12, 0, 0, 0, time.UTC)
+
+	next, err := window.Next(wednesday)
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if want := time.Date(2025, time.May, 3, 2, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("Next() = %v, want %v", next, want)
+	}
+
+	// Once Saturday's window has opened, the next one is Sunday's
+	next, _ = window.Next(time.Date(2025, time.May, 3, 3, 0, 0, 0, time.UTC))
+	if want := time.Date(2025, time.May, 4, 2, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("Next() = %v, want %v", next, want)
+	}
+}
+
+func TestWindow_Validate(t *testing.T) {
+	invalid := []Window{
+		{Start: "25:00", Duration: "1h"},
+		{Start: "02:00", Duration: "0s"},
+		{Start: "02:00", Duration: "200h"},
+		{Days: []string{"someday"}, Start: "02:00", Duration: "1h"},
+		{Start: "02:00", Duration: "1h", Timezone: "Mars/Olympus"},
+	}
+	for _, window := range invalid {
+		if err := window.Validate(); err == nil {
+			t.Errorf("Validate(%+v) expected error", window)
+		}
+	}
+	if err := (Window{Days: []string{"Saturday"}, Start: "02:00", Duration: "1h"}).Validate(); err != nil {
+		t.Errorf("Validate() unexpected error for a full day name: %v", err)
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "maintenance.json")
+	store, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore() on a missing file error = %v", err)
+	}
+	if err := store.Set("prod", Window{Days: []string{"Saturday", "SUN"}, Start: "02:00", Duration: "4h"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := store.Set("dev", Window{Start: "nope", Duration: "4h"}); err == nil {
+		t.Error("Set() expected error for an invalid window")
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore() error = %v", err)
+	}
+	window, ok := reloaded.Get("prod")
+	if !ok || !reflect.DeepEqual(window.Days, []string{"sat", "sun"}) {
+		t.Errorf("Get(prod) = %+v, %v, want normalized days sat,sun", window, ok)
+	}
+	if clusters := reloaded.Clusters(); !reflect.DeepEqual(clusters, []string{"prod"}) {
+		t.Errorf("Clusters() = %v, want [prod]", clusters)
+	}
+	reloaded.Clear("prod")
+	if _, ok := reloaded.Get("prod"); ok {
+		t.Error("Clear() should remove the window")
+	}
+}
@@ -0,0 +1,166 @@
+// Package metrics records measurements about Atlas itself and renders them in the Prometheus
+// text exposition format, so operators can scrape a long-running atlas-cli process alongside
+// the clusters it manages.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Labels are the label pairs identifying one series of a metric
+type Labels map[string]string
+
+type kind string
+
+const (
+	kindCounter kind = "counter"
+	kindGauge   kind = "gauge"
+	kindSummary kind = "summary"
+)
+
+type family struct {
+	help   string
+	kind   kind
+	series map[string]float64
+}
+
+// Registry holds every metric family. The zero value is not usable; call NewRegistry.
+type Registry struct {
+	mu         sync.Mutex
+	families   map[string]*family
+	collectors []func(r *Registry)
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// Add increments the counter name by delta
+func (r *Registry) Add(name, help string, labels Labels, delta float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.family(name, help, kindCounter).series[formatLabels(labels)] += delta
+}
+
+// Set sets the gauge name to value
+func (r *Registry) Set(name, help string, labels Labels, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.family(name, help, kindGauge).series[formatLabels(labels)] = value
+}
+
+// Observe records a duration in the summary name, exported as name_count and name_sum in seconds
+func (r *Registry) Observe(name, help string, labels Labels, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := r.family(name, help, kindSummary)
+	key := formatLabels(labels)
+	f.series["_count"+key]++
+	f.series["_sum"+key] += d.Seconds()
+}
+
+// OnScrape registers a function run before every render, for gauges read from elsewhere
+func (r *Registry) OnScrape(collect func(r *Registry)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, collect)
+}
+
+// Reset drops every series of the named metrics, for gauges rebuilt from scratch on each scrape
+// so series for things that have gone away stop being exported
+func (r *Registry) Reset(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		delete(r.families, name)
+	}
+}
+
+// Write renders every metric in the Prometheus text format, sorted by name and labels
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]func(*Registry){}, r.collectors...)
+	r.mu.Unlock()
+	for _, collect := range collectors {
+		collect(r)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		f := r.families[name]
+		fmt.Fprintf(&b, "# HELP %s %s\n", name, f.help)
+		fmt.Fprintf(&b, "# TYPE %s %s\n", name, f.kind)
+
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&b, "%s%s %g\n", name, key, f.series[key])
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Handler serves the registry at a Prometheus scrape endpoint
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		r.Write(w)
+	})
+}
+
+// Since records the time elapsed since start in the summary name and counts err in name_errors_total
+func (r *Registry) Since(name, help string, labels Labels, start time.Time, err error) {
+	r.Observe(name, help, labels, time.Since(start))
+	if err != nil {
+		r.Add(strings.TrimSuffix(name, "_seconds")+"_errors_total", "Failed calls timed by "+name, labels, 1)
+	}
+}
+
+func (r *Registry) family(name, help string, k kind) *family {
+	f, exists := r.families[name]
+	if !exists {
+		f = &family{help: help, kind: k, series: make(map[string]float64)}
+		r.families[name] = f
+	}
+	return f
+}
+
+// formatLabels renders labels as {a="1",b="2"} with keys sorted, or "" when there are none
+func formatLabels(labels Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(labels[key])
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, key, value))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistry_Write(t *testing.T) {
+	r := NewRegistry()
+	r.Add("atlas_checks_total", "Health checks run", Labels{"cluster": "dev"}, 1)
+	r.Add("atlas_checks_total", "Health checks run", Labels{"cluster": "dev"}, 2)
+	r.Set("atlas_lag_seconds", "Loop lag", nil, 0.5)
+	r.Observe("atlas_call_seconds", "Call latency", Labels{"call": "health", "cluster": "a\"b"}, 1500*time.Millisecond)
+	r.OnScrape(func(r *Registry) {
+		r.Set("atlas_operations", "Operations by state", Labels{"state": "running"}, 3)
+	})
+
+	var out strings.Builder
+	if err := r.Write(&out); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	want := `# HELP atlas_call_seconds Call latency
+# TYPE atlas_call_seconds summary
+atlas_call_seconds_count{call="health",cluster="a\"b"} 1
+atlas_call_seconds_sum{call="health",cluster="a\"b"} 1.5
+# HELP atlas_checks_total Health checks run
+# TYPE atlas_checks_total counter
+atlas_checks_total{cluster="dev"} 3
+# HELP atlas_lag_seconds Loop lag
+# TYPE atlas_lag_seconds gauge
+atlas_lag_seconds 0.5
+# HELP atlas_operations Operations by state
+# TYPE atlas_operations gauge
+atlas_operations{state="running"} 3
+`
+	if out.String() != want {
+		t.Errorf("Write() =\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestRegistry_Since(t *testing.T) {
+	r := NewRegistry()
+	r.Since("atlas_call_seconds", "Provider call latency", Labels{"call": "health"}, time.Now(), nil)
+	r.Since("atlas_call_seconds", "Provider call latency", Labels{"call": "health"}, time.Now(), errors.New("boom"))
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		`atlas_call_seconds_count{call="health"} 2`,
+		"# HELP atlas_call_errors_total Failed calls timed by atlas_call_seconds",
+		`atlas_call_errors_total{call="health"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q:\n%s", want, body)
+		}
+	}
+}
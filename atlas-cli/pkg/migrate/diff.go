@@ -0,0 +1,118 @@
+package migrate
+
+import (
+	"reflect"
+	"sort"
+)
+
+// DiffStatus is how an object on the target compares with the source
+type DiffStatus string
+
+const (
+	DiffIdentical DiffStatus = "identical"
+	DiffChanged   DiffStatus = "changed"
+	DiffMissing   DiffStatus = "missing"
+	DiffExtra     DiffStatus = "extra"
+)
+
+// maxDiffFields caps how many differing fields are listed per object
+const maxDiffFields = 5
+
+// Difference is the comparison of one object between source and target
+type Difference struct {
+	Kind      string     `json:"kind"`
+	Namespace string     `json:"namespace,omitempty"`
+	Name      string     `json:"name"`
+	Status    DiffStatus `json:"status"`
+	// Fields lists the dotted paths that differ, for changed objects
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Diff compares sanitized source and target objects. Missing objects exist only on the source,
+// extra objects only on the target. Results are sorted by kind, namespace and name.
+func Diff(source, target []Object) []Difference {
+	targetByKey := make(map[string]Object, len(target))
+	for _, object := range target {
+		targetByKey[object.Key()] = object
+	}
+
+	var differences []Difference
+	seen := make(map[string]bool, len(source))
+	for _, object := range source {
+		seen[object.Key()] = true
+		difference := Difference{Kind: object.Kind(), Namespace: object.Namespace(), Name: object.Name(), Status: DiffIdentical}
+		other, exists := targetByKey[object.Key()]
+		switch {
+		case !exists:
+			difference.Status = DiffMissing
+		case !reflect.DeepEqual(map[string]any(object), map[string]any(other)):
+			difference.Status = DiffChanged
+			difference.Fields = diffFields("", map[string]any(object), map[string]any(other))
+		}
+		differences = append(differences, difference)
+	}
+	for _, object := range target {
+		if !seen[object.Key()] {
+			differences = append(differences, Difference{Kind: object.Kind(), Namespace: object.Namespace(), Name: object.Name(), Status: DiffExtra})
+		}
+	}
+
+	sort.SliceStable(differences, func(i, j int) bool {
+		a, b := differences[i], differences[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return differences
+}
+
+// diffFields returns up to maxDiffFields dotted paths at which a and b differ
+func diffFields(prefix string, a, b any) []string {
+	aMap, aIsMap := a.(map[string]any)
+	bMap, bIsMap := b.(map[string]any)
+	if !aIsMap || !bIsMap {
+		if reflect.DeepEqual(a, b) {
+			return nil
+		}
+		return []string{prefix}
+	}
+
+	keys := make(map[string]bool)
+	for key := range aMap {
+		keys[key] = true
+	}
+	for key := range bMap {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var fields []string
+	for _, key := range sorted {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		fields = append(fields, diffFields(path, aMap[key], bMap[key])...)
+		if len(fields) >= maxDiffFields {
+			return fields[:maxDiffFields]
+		}
+	}
+	return fields
+}
+
+// Summary counts differences by status
+func Summary(differences []Difference) map[DiffStatus]int {
+	counts := make(map[DiffStatus]int)
+	for _, difference := range differences {
+		counts[difference.Status]++
+	}
+	return counts
+}
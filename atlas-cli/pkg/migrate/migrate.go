@@ -0,0 +1,278 @@
+// Package migrate copies the workloads in selected namespaces from one cluster to another,
+// either natively (export with kubectl, strip cluster-specific fields, apply to the target) or
+// through Velero, and reports how the target differs from the source afterwards.
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// DefaultKinds are the namespaced resources a native migration copies, in the order they are
+// applied so that config and identities exist before the workloads that use them
+var DefaultKinds = []string{
+	"serviceaccounts",
+	"configmaps",
+	"secrets",
+	"persistentvolumeclaims",
+	"roles",
+	"rolebindings",
+	"services",
+	"deployments",
+	"statefulsets",
+	"daemonsets",
+	"cronjobs",
+	"ingresses",
+	"networkpolicies",
+	"horizontalpodautoscalers",
+	"poddisruptionbudgets",
+}
+
+// Object is a Kubernetes object in its unstructured JSON form
+type Object map[string]any
+
+// Kind returns the object's kind
+func (o Object) Kind() string {
+	kind, _ := o["kind"].(string)
+	return kind
+}
+
+// Name returns the object's metadata.name
+func (o Object) Name() string {
+	name, _ := o.metadata()["name"].(string)
+	return name
+}
+
+// Namespace returns the object's metadata.namespace
+func (o Object) Namespace() string {
+	namespace, _ := o.metadata()["namespace"].(string)
+	return namespace
+}
+
+// Key identifies the object across clusters, e.g. "Deployment/web/api"
+func (o Object) Key() string {
+	return o.Kind() + "/" + o.Namespace() + "/" + o.Name()
+}
+
+func (o Object) metadata() map[string]any {
+	metadata, _ := o["metadata"].(map[string]any)
+	return metadata
+}
+
+// Kubectl runs kubectl against a kubeconfig context, feeding it stdin when not nil
+type Kubectl func(ctx context.Context, kubeContext string, stdin []byte, args ...string) ([]byte, error)
+
+// RunKubectl is the Kubectl that shells out to the kubectl binary
+func RunKubectl(ctx context.Context, kubeContext string, stdin []byte, args ...string) ([]byte, error) {
+	cmd := subprocess.CommandContext(ctx, "kubectl", append([]string{"--context", kubeContext}, args...)...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("kubectl %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+// Snapshot is the portable copy of the selected namespaces taken from the source cluster
+type Snapshot struct {
+	Context    string   `json:"context"`
+	Namespaces []string `json:"namespaces"`
+	Objects    []Object `json:"objects"`
+}
+
+// Export snapshots kinds in namespaces from the cluster behind kubeContext. Objects are sanitized
+// so they can be applied to another cluster; objects owned by another object or created by
+// Kubernetes itself are left out.
+func Export(ctx context.Context, kubectl Kubectl, kubeContext string, namespaces, kinds []string) (*Snapshot, error) {
+	snapshot := &Snapshot{Context: kubeContext, Namespaces: namespaces}
+	for _, namespace := range namespaces {
+		output, err := kubectl(ctx, kubeContext, nil, "get", "namespace", namespace, "-o", "json")
+		if err != nil {
+			return nil, fmt.Errorf("failed to export namespace %s: %w", namespace, err)
+		}
+		var ns Object
+		if err := json.Unmarshal(output, &ns); err != nil {
+			return nil, fmt.Errorf("failed to parse namespace %s: %w", namespace, err)
+		}
+		snapshot.Objects = append(snapshot.Objects, Sanitize(ns))
+
+		objects, err := list(ctx, kubectl, kubeContext, namespace, kinds)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Objects = append(snapshot.Objects, objects...)
+	}
+	return snapshot, nil
+}
+
+// ExportExisting is Export for the namespaces that exist on the cluster; missing namespaces, as
+// on a target that nothing has been restored to yet, are skipped rather than an error
+func ExportExisting(ctx context.Context, kubectl Kubectl, kubeContext string, namespaces, kinds []string) (*Snapshot, error) {
+	var existing []string
+	for _, namespace := range namespaces {
+		if _, err := kubectl(ctx, kubeContext, nil, "get", "namespace", namespace, "-o", "name"); err != nil {
+			if strings.Contains(err.Error(), "NotFound") {
+				continue
+			}
+			return nil, fmt.Errorf("failed to look up namespace %s: %w", namespace, err)
+		}
+		existing = append(existing, namespace)
+	}
+	return Export(ctx, kubectl, kubeContext, existing, kinds)
+}
+
+// list returns the sanitized, portable objects of kinds in namespace
+func list(ctx context.Context, kubectl Kubectl, kubeContext, namespace string, kinds []string) ([]Object, error) {
+	output, err := kubectl(ctx, kubeContext, nil, "get", strings.Join(kinds, ","), "-n", namespace, "-o", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to export namespace %s: %w", namespace, err)
+	}
+	var objectList struct {
+		Items []Object `json:"items"`
+	}
+	if err := json.Unmarshal(output, &objectList); err != nil {
+		return nil, fmt.Errorf("failed to parse objects in namespace %s: %w", namespace, err)
+	}
+
+	var objects []Object
+	for _, object := range objectList.Items {
+		if skip(object) {
+			continue
+		}
+		objects = append(objects, Sanitize(object))
+	}
+	return objects, nil
+}
+
+// skip reports whether object is managed by another object or by Kubernetes itself and so must
+// not be copied
+func skip(object Object) bool {
+	if owners, _ := object.metadata()["ownerReferences"].([]any); len(owners) > 0 {
+		return true
+	}
+	switch object.Kind() {
+	case "ServiceAccount":
+		return object.Name() == "default"
+	case "ConfigMap":
+		return object.Name() == "kube-root-ca.crt"
+	case "Secret":
+		secretType, _ := object["type"].(string)
+		return secretType == "kubernetes.io/service-account-token"
+	}
+	return false
+}
+
+// clusterAnnotationPrefixes are annotations Kubernetes sets on its own
+var clusterAnnotationPrefixes = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"deployment.kubernetes.io/",
+	"pv.kubernetes.io/",
+	"volume.beta.kubernetes.io/",
+	"volume.kubernetes.io/",
+}
+
+// Sanitize returns a copy of object without the status, the fields the source cluster assigned
+// (uid, resourceVersion, cluster IPs, bound volume names) and Velero's restore labels, so it
+// applies cleanly to a different cluster and compares equal after a restore
+func Sanitize(object Object) Object {
+	clean := deepCopy(object).(map[string]any)
+	delete(clean, "status")
+
+	if metadata, ok := clean["metadata"].(map[string]any); ok {
+		for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields", "selfLink",
+			"deletionTimestamp", "deletionGracePeriodSeconds"} {
+			delete(metadata, field)
+		}
+		if annotations, ok := metadata["annotations"].(map[string]any); ok {
+			for key := range annotations {
+				for _, prefix := range clusterAnnotationPrefixes {
+					if strings.HasPrefix(key, prefix) {
+						delete(annotations, key)
+					}
+				}
+			}
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+		if labels, ok := metadata["labels"].(map[string]any); ok {
+			for key := range labels {
+				if strings.HasPrefix(key, "velero.io/") {
+					delete(labels, key)
+				}
+			}
+			if len(labels) == 0 {
+				delete(metadata, "labels")
+			}
+		}
+	}
+
+	spec, _ := clean["spec"].(map[string]any)
+	switch Object(clean).Kind() {
+	case "Service":
+		delete(spec, "clusterIP")
+		delete(spec, "clusterIPs")
+		delete(spec, "healthCheckNodePort")
+	case "PersistentVolumeClaim":
+		delete(spec, "volumeName")
+	case "Namespace":
+		delete(clean, "spec")
+	}
+	return clean
+}
+
+func deepCopy(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(v))
+		for key, item := range v {
+			copied[key] = deepCopy(item)
+		}
+		return copied
+	case Object:
+		return deepCopy(map[string]any(v))
+	case []any:
+		copied := make([]any, len(v))
+		for i, item := range v {
+			copied[i] = deepCopy(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// Restore applies snapshot to the cluster behind kubeContext. Namespaces are applied first, then
+// the remaining objects in snapshot order.
+func Restore(ctx context.Context, kubectl Kubectl, kubeContext string, snapshot *Snapshot) error {
+	var namespaces, objects []any
+	for _, object := range snapshot.Objects {
+		if object.Kind() == "Namespace" {
+			namespaces = append(namespaces, object)
+		} else {
+			objects = append(objects, object)
+		}
+	}
+	for _, batch := range [][]any{namespaces, objects} {
+		if len(batch) == 0 {
+			continue
+		}
+		data, err := json.Marshal(map[string]any{"apiVersion": "v1", "kind": "List", "items": batch})
+		if err != nil {
+			return fmt.Errorf("failed to marshal objects: %w", err)
+		}
+		if _, err := kubectl(ctx, kubeContext, data, "apply", "-f", "-"); err != nil {
+			return fmt.Errorf("failed to restore objects to %s: %w", kubeContext, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeKubectl answers `get` calls from canned JSON keyed by context and arguments, and records
+// what `apply` received
+type fakeKubectl struct {
+	responses map[string]string
+	applied   [][]Object
+}
+
+func (f *fakeKubectl) run(ctx context.Context, kubeContext string, stdin []byte, args ...string) ([]byte, error) {
+	if args[0] == "apply" {
+		var objectList struct {
+			Items []Object `json:"items"`
+		}
+		if err := json.Unmarshal(stdin, &objectList); err != nil {
+			return nil, err
+		}
+		f.applied = append(f.applied, objectList.Items)
+		return nil, nil
+	}
+	key := kubeContext + " " + strings.Join(args, " ")
+	response, ok := f.responses[key]
+	if !ok {
+		return nil, errors.New(`Error from server (NotFound): ` + key)
+	}
+	return []byte(response), nil
+}
+
+const sourceObjects = `{"items": [
+	{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "api", "namespace": "web", "uid": "abc", "resourceVersion": "12",
+		"annotations": {"deployment.kubernetes.io/revision": "3", "team": "payments"}}, "spec": {"replicas": 2}, "status": {"readyReplicas": 2}},
+	{"apiVersion": "apps/v1", "kind": "ReplicaSet", "metadata": {"name": "api-5d8f", "namespace": "web", "ownerReferences": [{"kind": "Deployment"}]}},
+	{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "api", "namespace": "web"}, "spec": {"clusterIP": "10.0.0.7", "clusterIPs": ["10.0.0.7"], "ports": [{"port": 80}]}},
+	{"apiVersion": "v1", "kind": "ServiceAccount", "metadata": {"name": "default", "namespace": "web"}},
+	{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "kube-root-ca.crt", "namespace": "web"}},
+	{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "token", "namespace": "web"}, "type": "kubernetes.io/service-account-token"}
+]}`
+
+func TestExportRestore(t *testing.T) {
+	kinds := []string{"deployments", "services"}
+	kubectl := &fakeKubectl{responses: map[string]string{
+		"old get namespace web -o json":               `{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "web", "uid": "n1"}, "spec": {"finalizers": ["kubernetes"]}}`,
+		"old get deployments,services -n web -o json": sourceObjects,
+	}}
+
+	snapshot, err := Export(context.Background(), kubectl.run, "old", []string{"web"}, kinds)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	var keys []string
+	for _, object := range snapshot.Objects {
+		keys = append(keys, object.Key())
+	}
+	want := []string{"Namespace//web", "Deployment/web/api", "Service/web/api"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("Export() objects = %v, want %v (owned and system objects skipped)", keys, want)
+	}
+
+	deployment := snapshot.Objects[1]
+	if _, ok := deployment["status"]; ok {
+		t.Error("Export() should drop status")
+	}
+	metadata := deployment["metadata"].(map[string]any)
+	if _, ok := metadata["uid"]; ok {
+		t.Error("Export() should drop metadata.uid")
+	}
+	if annotations := metadata["annotations"].(map[string]any); len(annotations) != 1 || annotations["team"] != "payments" {
+		t.Errorf("Export() annotations = %v, want only team", annotations)
+	}
+	if spec := snapshot.Objects[2]["spec"].(map[string]any); spec["clusterIP"] != nil || spec["clusterIPs"] != nil {
+		t.Errorf("Export() should drop service cluster IPs, got %v", spec)
+	}
+
+	if err := Restore(context.Background(), kubectl.run, "new", snapshot); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if len(kubectl.applied) != 2 || len(kubectl.applied[0]) != 1 || kubectl.applied[0][0].Kind() != "Namespace" || len(kubectl.applied[1]) != 2 {
+		t.Errorf("Restore() applied %v, want the namespace first then its two objects", kubectl.applied)
+	}
+
+	existing, err := ExportExisting(context.Background(), kubectl.run, "new", []string{"web"}, kinds)
+	if err != nil {
+		t.Fatalf("ExportExisting() error = %v", err)
+	}
+	if len(existing.Objects) != 0 {
+		t.Errorf("ExportExisting() = %v, want nothing for a namespace missing on the target", existing.Objects)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	object := func(kind, name string, replicas int) Object {
+		return Object{"kind": kind, "metadata": map[string]any{"name": name, "namespace": "web"}, "spec": map[string]any{"replicas": float64(replicas)}}
+	}
+	source := []Object{object("Deployment", "api", 2), object("Deployment", "worker", 1), object("Service", "api", 0)}
+	target := []Object{object("Deployment", "api", 3), object("Service", "api", 0), object("ConfigMap", "leftover", 0)}
+
+	got := Diff(source, target)
+	want := []Difference{
+		{Kind: "ConfigMap", Namespace: "web", Name: "leftover", Status: DiffExtra},
+		{Kind: "Deployment", Namespace: "web", Name: "api", Status: DiffChanged, Fields: []string{"spec.replicas"}},
+		{Kind: "Deployment", Namespace: "web", Name: "worker", Status: DiffMissing},
+		{Kind: "Service", Namespace: "web", Name: "api", Status: DiffIdentical},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v, want %+v", got, want)
+	}
+
+	counts := Summary(got)
+	if counts[DiffChanged] != 1 || counts[DiffMissing] != 1 || counts[DiffExtra] != 1 || counts[DiffIdentical] != 1 {
+		t.Errorf("Summary() = %v, want one of each", counts)
+	}
+}
+
+func TestVeleroMigrate(t *testing.T) {
+	var calls []string
+	velero := func(ctx context.Context, kubeContext string, args ...string) error {
+		calls = append(calls, kubeContext+": "+strings.Join(args, " "))
+		return nil
+	}
+	if err := VeleroMigrate(context.Background(), velero, "b1", "old", "new", []string{"web", "api"}); err != nil {
+		t.Fatalf("VeleroMigrate() error = %v", err)
+	}
+	want := []string{
+		"old: backup create b1 --include-namespaces web,api --wait",
+		"new: restore create b1-restore --from-backup b1 --wait",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("VeleroMigrate() calls = %v, want %v", calls, want)
+	}
+}
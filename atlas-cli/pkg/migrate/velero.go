@@ -0,0 +1,39 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// Velero runs the velero CLI against a kubeconfig context
+type Velero func(ctx context.Context, kubeContext string, args ...string) error
+
+// RunVelero is the Velero that shells out to the velero binary
+func RunVelero(ctx context.Context, kubeContext string, args ...string) error {
+	ctx = subprocess.WithOperation(ctx, "update")
+	cmd := subprocess.CommandContext(ctx, "velero", append(args, "--kubecontext", kubeContext)...)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("velero %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(output.String()))
+	}
+	return nil
+}
+
+// VeleroMigrate backs up namespaces on the source with Velero and restores the backup on the
+// target. Both clusters must have Velero installed with the same backup storage location.
+func VeleroMigrate(ctx context.Context, velero Velero, backupName, fromContext, toContext string, namespaces []string) error {
+	include := strings.Join(namespaces, ",")
+	if err := velero(ctx, fromContext, "backup", "create", backupName, "--include-namespaces", include, "--wait"); err != nil {
+		return fmt.Errorf("failed to back up namespaces on %s: %w", fromContext, err)
+	}
+	if err := velero(ctx, toContext, "restore", "create", backupName+"-restore", "--from-backup", backupName, "--wait"); err != nil {
+		return fmt.Errorf("failed to restore backup %s on %s: %w", backupName, toContext, err)
+	}
+	return nil
+}
@@ -0,0 +1,400 @@
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultAlertRepeatInterval is how often an alert that keeps firing is sent again
+const DefaultAlertRepeatInterval = time.Hour
+
+// AlertConfig configures where alerts are sent, usually kept in ~/.atlas/alerts.yaml
+type AlertConfig struct {
+	// Thresholds replace DefaultAlertThresholds when set
+	Thresholds *AlertThresholds `yaml:"thresholds,omitempty"`
+	// RepeatInterval re-sends alerts that are still firing; defaults to an hour
+	RepeatInterval time.Duration   `yaml:"repeatInterval,omitempty"`
+	Channels       []ChannelConfig `yaml:"channels"`
+}
+
+// Notification channel types
+const (
+	ChannelSlack     = "slack"
+	ChannelWebhook   = "webhook"
+	ChannelPagerDuty = "pagerduty"
+)
+
+// ChannelConfig is one place alerts are sent. URLs and routing keys may be secret references
+// such as vault://path#key.
+type ChannelConfig struct {
+	Name string `yaml:"name"`
+	// Type is slack, webhook or pagerduty
+	Type string `yaml:"type"`
+	// URL is the Slack incoming webhook or HTTP endpoint. PagerDuty channels default to the
+	// Events API v2.
+	URL        string            `yaml:"url,omitempty"`
+	RoutingKey string            `yaml:"routingKey,omitempty"`
+	Headers    map[string]string `yaml:"headers,omitempty"`
+	// MinSeverity drops less severe alerts; defaults to warning
+	MinSeverity EventSeverity `yaml:"minSeverity,omitempty"`
+	// Clusters limits the channel to these clusters; empty means all
+	Clusters []string `yaml:"clusters,omitempty"`
+}
+
+// DefaultAlertConfigPath returns where the alert configuration is read from
+func DefaultAlertConfigPath() (string, error) {
+	return store.Path("alerts.yaml")
+}
+
+// DefaultAlertThresholds returns the thresholds used when the alert configuration sets none.
+// Percentages are of cluster capacity; zero disables a check.
+func DefaultAlertThresholds() *AlertThresholds {
+	return &AlertThresholds{
+		CPUWarning:      80,
+		CPUCritical:     95,
+		MemoryWarning:   80,
+		MemoryCritical:  95,
+		StorageWarning:  80,
+		StorageCritical: 90,
+		NodeDownCount:   1,
+		PodFailureRate:  10,
+	}
+}
+
+// LoadAlertConfig reads and validates the alert configuration at path. A missing file returns
+// nil, meaning alerts are not sent anywhere.
+func LoadAlertConfig(path string) (*AlertConfig, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert config: %w", err)
+	}
+
+	var config AlertConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse alert config %s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid alert config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// Validate checks every channel has what its type needs
+func (c *AlertConfig) Validate() error {
+	var problems []string
+	names := make(map[string]bool)
+	for i, channel := range c.Channels {
+		label := channel.Name
+		if label == "" {
+			label = fmt.Sprintf("channels[%d]", i)
+		} else if names[label] {
+			problems = append(problems, fmt.Sprintf("channel name %s is used twice", label))
+		}
+		names[label] = true
+
+		switch channel.Type {
+		case ChannelSlack, ChannelWebhook:
+			if channel.URL == "" {
+				problems = append(problems, fmt.Sprintf("%s: %s channels need a url", label, channel.Type))
+			}
+		case ChannelPagerDuty:
+			if channel.RoutingKey == "" {
+				problems = append(problems, fmt.Sprintf("%s: pagerduty channels need a routingKey", label))
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("%s: type must be slack, webhook or pagerduty, not %q", label, channel.Type))
+		}
+		switch channel.MinSeverity {
+		case "", SeverityInfo, SeverityWarning, SeverityCritical:
+		default:
+			problems = append(problems, fmt.Sprintf("%s: minSeverity must be info, warning or critical, not %q", label, channel.MinSeverity))
+		}
+	}
+	if c.RepeatInterval < 0 {
+		problems = append(problems, "repeatInterval cannot be negative")
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// EvaluateHealth returns an alert for each threshold a health check breaks. A failed check is
+// itself a critical alert.
+func EvaluateHealth(clusterName string, status *HealthStatus, checkErr error, thresholds *AlertThresholds) []MonitoringEvent {
+	if thresholds == nil {
+		thresholds = DefaultAlertThresholds()
+	}
+	now := time.Now()
+	alert := func(check string, severity EventSeverity, message string, details map[string]interface{}) MonitoringEvent {
+		return MonitoringEvent{
+			ID:          alertID(clusterName, "health", check),
+			ClusterName: clusterName,
+			EventType:   EventTypeAlert,
+			Severity:    severity,
+			Message:     message,
+			Details:     details,
+			Timestamp:   now,
+		}
+	}
+
+	if checkErr != nil || status == nil {
+		message := fmt.Sprintf("Health check failed for cluster %s", clusterName)
+		if checkErr != nil {
+			message = fmt.Sprintf("Health check failed for cluster %s: %v", clusterName, checkErr)
+		}
+		return []MonitoringEvent{alert("check", SeverityCritical, message, nil)}
+	}
+
+	var events []MonitoringEvent
+	if status.OverallStatus == HealthStatusUnhealthy {
+		events = append(events, alert("status", SeverityCritical, fmt.Sprintf("Cluster %s is unhealthy", clusterName),
+			map[string]interface{}{"errors": status.Errors}))
+	}
+
+	var notReady []string
+	for _, node := range status.Nodes {
+		if !node.Ready && node.Status != NodeStarting {
+			notReady = append(notReady, node.Name)
+		}
+	}
+	if thresholds.NodeDownCount > 0 && len(notReady) >= thresholds.NodeDownCount {
+		events = append(events, alert("nodes", SeverityCritical,
+			fmt.Sprintf("%d of %d nodes in cluster %s are not ready: %s", len(notReady), len(status.Nodes), clusterName, strings.Join(notReady, ", ")),
+			map[string]interface{}{"not_ready": notReady}))
+	}
+
+	if pods := status.Pods; pods != nil && pods.TotalPods > 0 && thresholds.PodFailureRate > 0 {
+		rate := float64(pods.FailedPods) / float64(pods.TotalPods) * 100
+		if rate >= thresholds.PodFailureRate {
+			events = append(events, alert("pods", SeverityWarning,
+				fmt.Sprintf("%.1f%% of pods in cluster %s have failed (%d of %d)", rate, clusterName, pods.FailedPods, pods.TotalPods),
+				map[string]interface{}{"failure_rate": rate, "threshold": thresholds.PodFailureRate}))
+		}
+	}
+	return events
+}
+
+// EvaluateMetrics returns an alert for each resource whose usage reaches its warning or critical
+// threshold
+func EvaluateMetrics(clusterName string, metrics *ClusterMetrics, thresholds *AlertThresholds) []MonitoringEvent {
+	if thresholds == nil {
+		thresholds = DefaultAlertThresholds()
+	}
+	if metrics == nil || metrics.ResourceUsage == nil {
+		return nil
+	}
+	usage := metrics.ResourceUsage
+	checks := []struct {
+		resource, label   string
+		percent           float64
+		warning, critical float64
+	}{
+		{"cpu", "CPU", usage.CPUPercentage, thresholds.CPUWarning, thresholds.CPUCritical},
+		{"memory", "Memory", usage.MemoryPercentage, thresholds.MemoryWarning, thresholds.MemoryCritical},
+		{"storage", "Storage", usage.StoragePercentage, thresholds.StorageWarning, thresholds.StorageCritical},
+	}
+
+	var events []MonitoringEvent
+	for _, check := range checks {
+		var severity EventSeverity
+		var threshold float64
+		switch {
+		case check.critical > 0 && check.percent >= check.critical:
+			severity, threshold = SeverityCritical, check.critical
+		case check.warning > 0 && check.percent >= check.warning:
+			severity, threshold = SeverityWarning, check.warning
+		default:
+			continue
+		}
+		events = append(events, MonitoringEvent{
+			ID:          alertID(clusterName, "metrics", check.resource),
+			ClusterName: clusterName,
+			EventType:   EventTypeAlert,
+			Severity:    severity,
+			Message:     fmt.Sprintf("%s usage in cluster %s is %.1f%% (threshold %.0f%%)", check.label, clusterName, check.percent, threshold),
+			Details:     map[string]interface{}{"usage_percent": check.percent, "threshold": threshold},
+			Timestamp:   metrics.Timestamp,
+		})
+	}
+	return events
+}
+
+// alertID identifies an alert across checks, so a notifier can tell a new alert from one still
+// firing
+func alertID(clusterName, source, check string) string {
+	return clusterName + "/" + source + "/" + check
+}
+
+// Channel delivers alerts to one destination
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, event MonitoringEvent) error
+}
+
+// Notifier sends alerts to channels. An alert is sent when it starts firing, when its severity
+// changes and every repeat interval while it keeps firing, and a resolution is sent once it stops.
+type Notifier struct {
+	mu       sync.Mutex
+	channels []routedChannel
+	repeat   time.Duration
+	active   map[string]*activeAlert
+	nowFunc  func() time.Time
+}
+
+type routedChannel struct {
+	Channel
+	minSeverity EventSeverity
+	clusters    []string
+}
+
+type activeAlert struct {
+	event    MonitoringEvent
+	notified time.Time
+}
+
+// NewNotifier creates a notifier for the channels in config
+func NewNotifier(config *AlertConfig) (*Notifier, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	n := &Notifier{
+		repeat:  config.RepeatInterval,
+		active:  make(map[string]*activeAlert),
+		nowFunc: time.Now,
+	}
+	if n.repeat == 0 {
+		n.repeat = DefaultAlertRepeatInterval
+	}
+	for i, channelConfig := range config.Channels {
+		if channelConfig.Name == "" {
+			channelConfig.Name = fmt.Sprintf("%s-%d", channelConfig.Type, i+1)
+		}
+		channel, err := NewChannel(channelConfig)
+		if err != nil {
+			return nil, err
+		}
+		n.AddChannel(channel, channelConfig.MinSeverity, channelConfig.Clusters...)
+	}
+	return n, nil
+}
+
+// AddChannel routes alerts of at least minSeverity (warning when empty) for the given clusters,
+// or all clusters, to channel
+func (n *Notifier) AddChannel(channel Channel, minSeverity EventSeverity, clusters ...string) {
+	if minSeverity == "" {
+		minSeverity = SeverityWarning
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.channels = append(n.channels, routedChannel{Channel: channel, minSeverity: minSeverity, clusters: clusters})
+}
+
+// Update reports the alerts now firing for one source ("health" or "metrics") of a cluster's
+// checks. Alerts the source raised before that are missing from firing are resolved.
+func (n *Notifier) Update(ctx context.Context, clusterName, source string, firing []MonitoringEvent) error {
+	n.mu.Lock()
+	now := n.nowFunc()
+	var send []MonitoringEvent
+	seen := make(map[string]bool)
+	for _, event := range firing {
+		seen[event.ID] = true
+		previous, ok := n.active[event.ID]
+		if ok && previous.event.Severity == event.Severity && now.Sub(previous.notified) < n.repeat {
+			continue
+		}
+		n.active[event.ID] = &activeAlert{event: event, notified: now}
+		send = append(send, event)
+	}
+
+	prefix := alertID(clusterName, source, "")
+	var resolvedIDs []string
+	for id := range n.active {
+		if strings.HasPrefix(id, prefix) && !seen[id] {
+			resolvedIDs = append(resolvedIDs, id)
+		}
+	}
+	sort.Strings(resolvedIDs)
+	for _, id := range resolvedIDs {
+		resolved := n.active[id].event
+		delete(n.active, id)
+		resolved.Resolved = true
+		resolved.ResolvedAt = &now
+		resolved.Timestamp = now
+		resolved.Message = "Resolved: " + resolved.Message
+		send = append(send, resolved)
+	}
+	channels := n.channels
+	n.mu.Unlock()
+
+	var errs []error
+	for _, event := range send {
+		for _, channel := range channels {
+			if !channel.accepts(event) {
+				continue
+			}
+			if err := channel.Send(ctx, event); err != nil {
+				errs = append(errs, fmt.Errorf("failed to notify %s: %w", channel.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c routedChannel) accepts(event MonitoringEvent) bool {
+	if severityRank(event.Severity) < severityRank(c.minSeverity) {
+		return false
+	}
+	if len(c.clusters) == 0 {
+		return true
+	}
+	for _, cluster := range c.clusters {
+		if cluster == event.ClusterName {
+			return true
+		}
+	}
+	return false
+}
+
+func severityRank(severity EventSeverity) int {
+	switch severity {
+	case SeverityCritical:
+		return 2
+	case SeverityWarning:
+		return 1
+	}
+	return 0
+}
+
+// notify sends the alerts StartMonitoring found to the config's notifier
+func (c *MonitoringConfig) notify(ctx context.Context, clusterName, source string, firing []MonitoringEvent) {
+	if err := c.Notifier.Update(ctx, clusterName, source, firing); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// recordHealth writes a StartMonitoring health check to the config's journal, if it has one
+func (c *MonitoringConfig) recordHealth(clusterName string, status *HealthStatus, checkErr error) {
+	if c.Journal == nil {
+		return
+	}
+	if err := c.Journal.RecordHealth(clusterName, status, checkErr); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
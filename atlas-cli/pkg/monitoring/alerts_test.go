@@ -0,0 +1,264 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoadAlertConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "alerts.yaml")
+	if err := os.WriteFile(path, []byte(`
+thresholds:
+  cpuWarning: 70
+  nodeDownCount: 2
+repeatInterval: 30m
+channels:
+  - name: ops
+    type: slack
+    url: https://hooks.slack.com/services/T/B/X
+    minSeverity: critical
+  - name: pager
+    type: pagerduty
+    routingKey: vault://secret/pagerduty#key
+    clusters: [prod]
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadAlertConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Thresholds.CPUWarning != 70 || config.Thresholds.NodeDownCount != 2 || config.RepeatInterval != 30*time.Minute {
+		t.Errorf("LoadAlertConfig() = %+v, thresholds %+v", config, config.Thresholds)
+	}
+	if len(config.Channels) != 2 || config.Channels[1].Clusters[0] != "prod" {
+		t.Errorf("channels = %+v", config.Channels)
+	}
+
+	if config, err := LoadAlertConfig(filepath.Join(dir, "missing.yaml")); config != nil || err != nil {
+		t.Errorf("LoadAlertConfig() for a missing file = %v, %v, want nil, nil", config, err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	os.WriteFile(invalid, []byte("channels:\n  - name: a\n    type: email\n  - name: a\n    type: webhook\n"), 0600)
+	_, err = LoadAlertConfig(invalid)
+	if err == nil || !strings.Contains(err.Error(), `not "email"`) || !strings.Contains(err.Error(), "used twice") || !strings.Contains(err.Error(), "need a url") {
+		t.Errorf("LoadAlertConfig() error = %v", err)
+	}
+
+	unknown := filepath.Join(dir, "unknown.yaml")
+	os.WriteFile(unknown, []byte("chanels: []\n"), 0600)
+	if _, err := LoadAlertConfig(unknown); err == nil {
+		t.Error("LoadAlertConfig() should reject unknown fields")
+	}
+}
+
+func eventIDs(events []MonitoringEvent) string {
+	var ids []string
+	for _, event := range events {
+		ids = append(ids, event.ID+":"+string(event.Severity))
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestEvaluateHealth(t *testing.T) {
+	status := &HealthStatus{
+		OverallStatus: HealthStatusUnhealthy,
+		Nodes:         []NodeHealth{{Name: "a", Ready: true}, {Name: "b"}},
+		Pods:          &PodHealth{TotalPods: 10, FailedPods: 2},
+	}
+	if got, want := eventIDs(EvaluateHealth("dev", status, nil, nil)), "dev/health/status:critical,dev/health/nodes:critical,dev/health/pods:warning"; got != want {
+		t.Errorf("EvaluateHealth() = %s, want %s", got, want)
+	}
+
+	relaxed := &AlertThresholds{NodeDownCount: 2, PodFailureRate: 50}
+	status.OverallStatus = HealthStatusWarning
+	if got := EvaluateHealth("dev", status, nil, relaxed); len(got) != 0 {
+		t.Errorf("EvaluateHealth() with relaxed thresholds = %s, want none", eventIDs(got))
+	}
+
+	failed := EvaluateHealth("dev", nil, errors.New("connection refused"), nil)
+	if eventIDs(failed) != "dev/health/check:critical" || !strings.Contains(failed[0].Message, "connection refused") {
+		t.Errorf("EvaluateHealth() for a failed check = %+v", failed)
+	}
+}
+
+func TestEvaluateMetrics(t *testing.T) {
+	metrics := &ClusterMetrics{ResourceUsage: &ResourceUsage{CPUPercentage: 97, MemoryPercentage: 85, StoragePercentage: 10}}
+	events := EvaluateMetrics("dev", metrics, nil)
+	if got, want := eventIDs(events), "dev/metrics/cpu:critical,dev/metrics/memory:warning"; got != want {
+		t.Errorf("EvaluateMetrics() = %s, want %s", got, want)
+	}
+	if events[0].Message != "CPU usage in cluster dev is 97.0% (threshold 95%)" {
+		t.Errorf("message = %s", events[0].Message)
+	}
+
+	if got := EvaluateMetrics("dev", metrics, &AlertThresholds{MemoryCritical: 90}); len(got) != 0 {
+		t.Errorf("EvaluateMetrics() with cpu checks disabled = %s, want none", eventIDs(got))
+	}
+	if got := EvaluateMetrics("dev", &ClusterMetrics{}, nil); got != nil {
+		t.Errorf("EvaluateMetrics() without usage = %v", got)
+	}
+}
+
+type recordingChannel struct {
+	name string
+	mu   sync.Mutex
+	sent []string
+	err  error
+}
+
+func (c *recordingChannel) Name() string { return c.name }
+
+func (c *recordingChannel) Send(ctx context.Context, event MonitoringEvent) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	state := string(event.Severity)
+	if event.Resolved {
+		state = "resolved"
+	}
+	c.sent = append(c.sent, event.ID+":"+state)
+	return c.err
+}
+
+func (c *recordingChannel) take() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sent := strings.Join(c.sent, ",")
+	c.sent = nil
+	return sent
+}
+
+func TestNotifier(t *testing.T) {
+	now := time.Now()
+	notifier, err := NewNotifier(&AlertConfig{RepeatInterval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier.nowFunc = func() time.Time { return now }
+	all := &recordingChannel{name: "all"}
+	critical := &recordingChannel{name: "critical"}
+	prod := &recordingChannel{name: "prod"}
+	notifier.AddChannel(all, SeverityInfo)
+	notifier.AddChannel(critical, SeverityCritical)
+	notifier.AddChannel(prod, "", "prod")
+
+	ctx := context.Background()
+	cpu := func(severity EventSeverity) MonitoringEvent {
+		return MonitoringEvent{ID: alertID("dev", "metrics", "cpu"), ClusterName: "dev", Severity: severity}
+	}
+	memory := MonitoringEvent{ID: alertID("dev", "metrics", "memory"), ClusterName: "dev", Severity: SeverityWarning}
+	check := func(step, channel string, got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("%s: %s got %q, want %q", step, channel, got, want)
+		}
+	}
+
+	notifier.Update(ctx, "dev", "metrics", []MonitoringEvent{cpu(SeverityWarning), memory})
+	check("firing", "all", all.take(), "dev/metrics/cpu:warning,dev/metrics/memory:warning")
+	check("firing", "critical", critical.take(), "")
+	check("firing", "prod", prod.take(), "")
+
+	notifier.Update(ctx, "dev", "metrics", []MonitoringEvent{cpu(SeverityWarning), memory})
+	check("still firing", "all", all.take(), "")
+
+	notifier.Update(ctx, "dev", "metrics", []MonitoringEvent{cpu(SeverityCritical), memory})
+	check("escalated", "all", all.take(), "dev/metrics/cpu:critical")
+	check("escalated", "critical", critical.take(), "dev/metrics/cpu:critical")
+
+	// health updates leave metrics alerts alone
+	notifier.Update(ctx, "dev", "health", nil)
+	check("other source", "all", all.take(), "")
+
+	now = now.Add(2 * time.Hour)
+	notifier.Update(ctx, "dev", "metrics", []MonitoringEvent{cpu(SeverityCritical)})
+	check("repeat and resolve", "all", all.take(), "dev/metrics/cpu:critical,dev/metrics/memory:resolved")
+
+	notifier.Update(ctx, "prod", "health", []MonitoringEvent{{ID: alertID("prod", "health", "check"), ClusterName: "prod", Severity: SeverityCritical}})
+	check("routed", "prod", prod.take(), "prod/health/check:critical")
+
+	all.err = errors.New("boom")
+	if err := notifier.Update(ctx, "prod", "health", nil); err == nil || !strings.Contains(err.Error(), "failed to notify all: boom") {
+		t.Errorf("Update() error = %v", err)
+	}
+}
+
+func TestChannels(t *testing.T) {
+	var mu sync.Mutex
+	bodies := make(map[string]map[string]interface{})
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		json.Unmarshal(data, &body)
+		mu.Lock()
+		bodies[r.URL.Path] = body
+		if r.URL.Path == "/hook" {
+			header = r.Header.Get("Authorization")
+		}
+		mu.Unlock()
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	event := MonitoringEvent{ID: "dev/health/check", ClusterName: "dev", Severity: SeverityCritical, Message: "Cluster dev is unhealthy", Timestamp: time.Now()}
+	ctx := context.Background()
+	for _, config := range []ChannelConfig{
+		{Name: "slack", Type: ChannelSlack, URL: server.URL + "/slack"},
+		{Name: "hook", Type: ChannelWebhook, URL: server.URL + "/hook", Headers: map[string]string{"Authorization": "Bearer t"}},
+		{Name: "pd", Type: ChannelPagerDuty, URL: server.URL + "/pd", RoutingKey: "key"},
+	} {
+		channel, err := NewChannel(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := channel.Send(ctx, event); err != nil {
+			t.Fatalf("%s: Send() error = %v", config.Name, err)
+		}
+	}
+
+	if text, _ := bodies["/slack"]["text"].(string); !strings.Contains(text, "Cluster dev is unhealthy") || !strings.Contains(text, ":rotating_light:") {
+		t.Errorf("slack text = %q", text)
+	}
+	if bodies["/hook"]["id"] != "dev/health/check" || header != "Bearer t" {
+		t.Errorf("webhook body = %v, authorization %q", bodies["/hook"], header)
+	}
+	pd := bodies["/pd"]
+	payload, _ := pd["payload"].(map[string]interface{})
+	if pd["routing_key"] != "key" || pd["event_action"] != "trigger" || pd["dedup_key"] != "dev/health/check" || payload["severity"] != "critical" || payload["source"] != "dev" {
+		t.Errorf("pagerduty body = %v", pd)
+	}
+
+	pagerDuty, _ := NewChannel(ChannelConfig{Type: ChannelPagerDuty, URL: server.URL + "/pd", RoutingKey: "key"})
+	event.Resolved = true
+	if err := pagerDuty.Send(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+	if bodies["/pd"]["event_action"] != "resolve" || bodies["/pd"]["payload"] != nil {
+		t.Errorf("pagerduty resolve body = %v", bodies["/pd"])
+	}
+
+	broken, _ := NewChannel(ChannelConfig{Type: ChannelWebhook, URL: server.URL + "/broken"})
+	if err := broken.Send(ctx, event); err == nil {
+		t.Error("Send() to a failing endpoint should fail")
+	}
+	unreachable, _ := NewChannel(ChannelConfig{Type: ChannelSlack, URL: "http://127.0.0.1:1/secret-token"})
+	if err := unreachable.Send(ctx, event); err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("Send() to an unreachable webhook = %v, want an error without the URL", err)
+	}
+}
@@ -4,15 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
 )
 
 type AWSMonitor struct {
 	profile            string
 	region             string
 	activeMonitoring   map[string]context.CancelFunc
+	kubeContexts       sync.Map
+	clients            kubeClientCache
 }
 
 func NewAWSMonitor(profile, region string) *AWSMonitor {
@@ -81,6 +85,22 @@ func (a *AWSMonitor) CheckClusterHealth(ctx context.Context, clusterName string)
 		status.Services = serviceHealth
 	}
 
+	workloadHealth, err := a.checkWorkloads(ctx, clusterName)
+	if err != nil {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("Workload check failed: %v", err))
+	} else {
+		status.Workloads = workloadHealth
+	}
+
+	// the control plane is managed, so only the certificate it serves can be checked
+	certificate, err := a.checkAPIServerCertificate(ctx, clusterName)
+	if err != nil {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("API server certificate check failed: %v", err))
+	} else {
+		status.Certificates = append(status.Certificates, *certificate)
+	}
+	FlagExpiringCertificates(status, CertificateWarningFrom(ctx), time.Now())
+
 	status.OverallStatus = a.calculateOverallHealth(status)
 	status.CheckDuration = time.Since(startTime)
 
@@ -156,13 +176,18 @@ func (a *AWSMonitor) monitorCluster(ctx context.Context, clusterName string, con
 		case <-ctx.Done():
 			return
 		case <-healthTicker.C:
-			_, err := a.CheckClusterHealth(ctx, clusterName)
-			if err != nil && config.EnableAlerts {
+			status, err := a.CheckClusterHealth(ctx, clusterName)
+			config.recordHealth(clusterName, status, err)
+			if config.EnableAlerts && config.Notifier != nil {
+				config.notify(ctx, clusterName, "health", EvaluateHealth(clusterName, status, err, config.AlertThresholds))
+			} else if err != nil && config.EnableAlerts {
 				fmt.Printf("Health check failed for EKS cluster %s: %v\n", clusterName, err)
 			}
 		case <-metricsTicker.C:
-			_, err := a.GetClusterMetrics(ctx, clusterName)
-			if err != nil && config.EnableAlerts {
+			metrics, err := a.GetClusterMetrics(ctx, clusterName)
+			if config.EnableAlerts && config.Notifier != nil && err == nil {
+				config.notify(ctx, clusterName, "metrics", EvaluateMetrics(clusterName, metrics, config.AlertThresholds))
+			} else if err != nil && config.EnableAlerts {
 				fmt.Printf("Metrics collection failed for EKS cluster %s: %v\n", clusterName, err)
 			}
 		}
@@ -170,7 +195,7 @@ func (a *AWSMonitor) monitorCluster(ctx context.Context, clusterName string, con
 }
 
 func (a *AWSMonitor) getEKSClusterStatus(ctx context.Context, clusterName string) (string, error) {
-	cmd := exec.CommandContext(ctx, "aws", "eks", "describe-cluster",
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "describe-cluster",
 		"--name", clusterName,
 		"--region", a.region,
 		"--query", "cluster.status",
@@ -197,7 +222,7 @@ func (a *AWSMonitor) isEKSClusterActive(ctx context.Context, clusterName string)
 }
 
 func (a *AWSMonitor) checkControlPlane(ctx context.Context, clusterName string) (*ControlPlaneHealth, error) {
-	cmd := exec.CommandContext(ctx, "aws", "eks", "describe-cluster",
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "describe-cluster",
 		"--name", clusterName,
 		"--region", a.region,
 		"--query", "cluster.{endpoint:endpoint,version:version,status:status}")
@@ -236,275 +261,72 @@ func (a *AWSMonitor) checkControlPlane(ctx context.Context, clusterName string)
 	return health, nil
 }
 
-func (a *AWSMonitor) checkNodes(ctx context.Context, clusterName string) ([]NodeHealth, error) {
-	if err := a.updateKubeConfig(ctx, clusterName); err != nil {
-		return nil, fmt.Errorf("failed to update kubeconfig: %w", err)
+// kubeClients returns clients for clusterName, writing its context to the kubeconfig the first time
+func (a *AWSMonitor) kubeClients(ctx context.Context, clusterName string) (*kubeClients, error) {
+	kubeContext, ok := a.kubeContexts.Load(clusterName)
+	if !ok {
+		if err := a.updateKubeConfig(ctx, clusterName); err != nil {
+			return nil, fmt.Errorf("failed to update kubeconfig: %w", err)
+		}
+		kubeContext, _ = a.kubeContexts.LoadOrStore(clusterName, fmt.Sprintf("arn:aws:eks:%s:%s:cluster/%s", a.region, a.getAccountID(), clusterName))
 	}
+	return a.clients.get(kubeContext.(string))
+}
 
-	cmd := exec.CommandContext(ctx, "kubectl", "get", "nodes", "-o", "json", "--context", fmt.Sprintf("arn:aws:eks:%s:%s:cluster/%s", a.region, a.getAccountID(), clusterName))
-	output, err := cmd.Output()
+func (a *AWSMonitor) checkNodes(ctx context.Context, clusterName string) ([]NodeHealth, error) {
+	clients, err := a.kubeClients(ctx, clusterName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nodes: %w", err)
-	}
-
-	var nodeList struct {
-		Items []struct {
-			Metadata struct {
-				Name string `json:"name"`
-			} `json:"metadata"`
-			Status struct {
-				Conditions []struct {
-					Type               string `json:"type"`
-					Status             string `json:"status"`
-					LastTransitionTime string `json:"lastTransitionTime"`
-					Reason             string `json:"reason,omitempty"`
-					Message            string `json:"message,omitempty"`
-				} `json:"conditions"`
-				NodeInfo struct {
-					KubeletVersion string `json:"kubeletVersion"`
-				} `json:"nodeInfo"`
-				Capacity struct {
-					CPU    string `json:"cpu"`
-					Memory string `json:"memory"`
-				} `json:"capacity"`
-				Allocatable struct {
-					CPU    string `json:"cpu"`
-					Memory string `json:"memory"`
-				} `json:"allocatable"`
-			} `json:"status"`
-		} `json:"items"`
-	}
-
-	if err := json.Unmarshal(output, &nodeList); err != nil {
-		return nil, fmt.Errorf("failed to parse node list: %w", err)
-	}
-
-	var nodes []NodeHealth
-	for _, node := range nodeList.Items {
-		nodeHealth := NodeHealth{
-			Name:        node.Metadata.Name,
-			Status:      NodeUnknown,
-			Ready:       false,
-			Version:     node.Status.NodeInfo.KubeletVersion,
-			LastChecked: time.Now(),
-			Resources: &NodeResources{
-				CPUCapacity:       node.Status.Capacity.CPU,
-				MemoryCapacity:    node.Status.Capacity.Memory,
-				CPUAllocatable:    node.Status.Allocatable.CPU,
-				MemoryAllocatable: node.Status.Allocatable.Memory,
-			},
-		}
-
-		for _, condition := range node.Status.Conditions {
-			conditionTime, _ := time.Parse(time.RFC3339, condition.LastTransitionTime)
-
-			nodeCondition := NodeCondition{
-				Type:               condition.Type,
-				Status:             condition.Status,
-				LastTransitionTime: conditionTime,
-				Reason:             condition.Reason,
-				Message:            condition.Message,
-			}
-			nodeHealth.Conditions = append(nodeHealth.Conditions, nodeCondition)
-
-			if condition.Type == "Ready" {
-				if condition.Status == "True" {
-					nodeHealth.Ready = true
-					nodeHealth.Status = NodeHealthy
-				} else {
-					nodeHealth.Status = NodeNotReady
-				}
-			}
-		}
-
-		nodes = append(nodes, nodeHealth)
+		return nil, err
 	}
-
-	return nodes, nil
+	return checkNodes(ctx, clients.core)
 }
 
 func (a *AWSMonitor) checkPods(ctx context.Context, clusterName string) (*PodHealth, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "get", "pods", "--all-namespaces", "-o", "json", "--context", fmt.Sprintf("arn:aws:eks:%s:%s:cluster/%s", a.region, a.getAccountID(), clusterName))
-	output, err := cmd.Output()
+	clients, err := a.kubeClients(ctx, clusterName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pods: %w", err)
-	}
-
-	var podList struct {
-		Items []struct {
-			Metadata struct {
-				Name      string `json:"name"`
-				Namespace string `json:"namespace"`
-			} `json:"metadata"`
-			Status struct {
-				Phase             string `json:"phase"`
-				ContainerStatuses []struct {
-					RestartCount int `json:"restartCount"`
-				} `json:"containerStatuses,omitempty"`
-				Message string `json:"message,omitempty"`
-			} `json:"status"`
-		} `json:"items"`
-	}
-
-	if err := json.Unmarshal(output, &podList); err != nil {
-		return nil, fmt.Errorf("failed to parse pod list: %w", err)
-	}
-
-	podHealth := &PodHealth{
-		PodsByPhase: make(map[string]int),
-		Namespaces:  make(map[string]*NamespaceHealth),
-	}
-
-	for _, pod := range podList.Items {
-		phase := pod.Status.Phase
-		podHealth.TotalPods++
-		podHealth.PodsByPhase[phase]++
-
-		switch phase {
-		case "Running":
-			podHealth.RunningPods++
-		case "Pending":
-			podHealth.PendingPods++
-		case "Failed":
-			podHealth.FailedPods++
-		case "Succeeded":
-			podHealth.SucceededPods++
-		default:
-			podHealth.UnknownPods++
-		}
-
-		if podHealth.Namespaces[pod.Metadata.Namespace] == nil {
-			podHealth.Namespaces[pod.Metadata.Namespace] = &NamespaceHealth{
-				Name:   pod.Metadata.Namespace,
-				Status: "healthy",
-			}
-		}
-		ns := podHealth.Namespaces[pod.Metadata.Namespace]
-		ns.TotalPods++
-		if phase == "Running" {
-			ns.HealthyPods++
-		}
-
-		if phase == "Failed" || (len(pod.Status.ContainerStatuses) > 0 && pod.Status.ContainerStatuses[0].RestartCount > 5) {
-			criticalPod := CriticalPodInfo{
-				Name:      pod.Metadata.Name,
-				Namespace: pod.Metadata.Namespace,
-				Phase:     phase,
-				Message:   pod.Status.Message,
-			}
-			if len(pod.Status.ContainerStatuses) > 0 {
-				criticalPod.RestartCount = pod.Status.ContainerStatuses[0].RestartCount
-			}
-			podHealth.CriticalPods = append(podHealth.CriticalPods, criticalPod)
-		}
+		return nil, err
 	}
-
-	return podHealth, nil
+	return checkPods(ctx, clients.core)
 }
 
 func (a *AWSMonitor) checkServices(ctx context.Context, clusterName string) (*ServiceHealth, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "get", "services", "--all-namespaces", "-o", "json", "--context", fmt.Sprintf("arn:aws:eks:%s:%s:cluster/%s", a.region, a.getAccountID(), clusterName))
-	output, err := cmd.Output()
+	clients, err := a.kubeClients(ctx, clusterName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get services: %w", err)
-	}
-
-	var serviceList struct {
-		Items []struct {
-			Spec struct {
-				Type string `json:"type"`
-			} `json:"spec"`
-		} `json:"items"`
-	}
-
-	if err := json.Unmarshal(output, &serviceList); err != nil {
-		return nil, fmt.Errorf("failed to parse service list: %w", err)
+		return nil, err
 	}
+	return checkServices(ctx, clients.core)
+}
 
-	serviceHealth := &ServiceHealth{
-		ServicesByType: make(map[string]int),
+func (a *AWSMonitor) checkWorkloads(ctx context.Context, clusterName string) (*WorkloadHealth, error) {
+	clients, err := a.kubeClients(ctx, clusterName)
+	if err != nil {
+		return nil, err
 	}
+	return checkWorkloads(ctx, clients.core)
+}
 
-	for _, service := range serviceList.Items {
-		serviceHealth.TotalServices++
-		serviceHealth.HealthyServices++
-		serviceHealth.ServicesByType[service.Spec.Type]++
+func (a *AWSMonitor) checkAPIServerCertificate(ctx context.Context, clusterName string) (*ClusterCertificate, error) {
+	clients, err := a.kubeClients(ctx, clusterName)
+	if err != nil {
+		return nil, err
 	}
-
-	return serviceHealth, nil
+	return apiServerCertificate(ctx, clients.host)
 }
 
 func (a *AWSMonitor) getNodeMetrics(ctx context.Context, clusterName string) ([]NodeMetrics, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "top", "nodes", "--context", fmt.Sprintf("arn:aws:eks:%s:%s:cluster/%s", a.region, a.getAccountID(), clusterName), "--no-headers")
-	output, err := cmd.Output()
+	clients, err := a.kubeClients(ctx, clusterName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get node metrics (metrics server may not be installed): %w", err)
-	}
-
-	var metrics []NodeMetrics
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-
-	for _, line := range lines {
-		fields := strings.Fields(line)
-		if len(fields) < 5 {
-			continue
-		}
-
-		nodeName := fields[0]
-		cpuUsage := fields[1]
-		memUsage := fields[3]
-
-		metrics = append(metrics, NodeMetrics{
-			NodeName: nodeName,
-			CPUUsage: ResourceValue{
-				Value: cpuUsage,
-			},
-			MemoryUsage: ResourceValue{
-				Value: memUsage,
-			},
-			Timestamp: time.Now(),
-		})
+		return nil, err
 	}
-
-	return metrics, nil
+	return getNodeMetrics(ctx, clients)
 }
 
 func (a *AWSMonitor) getPodMetrics(ctx context.Context, clusterName string) ([]PodMetrics, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "top", "pods", "--all-namespaces", "--context", fmt.Sprintf("arn:aws:eks:%s:%s:cluster/%s", a.region, a.getAccountID(), clusterName), "--no-headers")
-	output, err := cmd.Output()
+	clients, err := a.kubeClients(ctx, clusterName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pod metrics (metrics server may not be installed): %w", err)
-	}
-
-	var metrics []PodMetrics
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-
-	for _, line := range lines {
-		fields := strings.Fields(line)
-		if len(fields) < 4 {
-			continue
-		}
-
-		namespace := fields[0]
-		podName := fields[1]
-		cpuUsage := fields[2]
-		memUsage := fields[3]
-
-		metrics = append(metrics, PodMetrics{
-			PodName:   podName,
-			Namespace: namespace,
-			CPUUsage: ResourceValue{
-				Value: cpuUsage,
-			},
-			MemoryUsage: ResourceValue{
-				Value: memUsage,
-			},
-			Containers: make(map[string]ContainerMetrics),
-			Timestamp:  time.Now(),
-		})
+		return nil, err
 	}
-
-	return metrics, nil
+	return getPodMetrics(ctx, clients)
 }
 
 func (a *AWSMonitor) calculateResourceUsage(nodeMetrics []NodeMetrics) (*ResourceUsage, error) {
@@ -553,15 +375,10 @@ func (a *AWSMonitor) calculateOverallHealth(status *HealthStatus) ClusterHealthS
 		return HealthStatusWarning
 	}
 
-	unhealthyNodes := 0
-	for _, node := range status.Nodes {
-		if node.Status != NodeHealthy {
-			unhealthyNodes++
-		}
-	}
+	unhealthyNodes, judgedNodes := countUnhealthyNodes(status.Nodes)
 
 	if unhealthyNodes > 0 {
-		if unhealthyNodes == len(status.Nodes) {
+		if unhealthyNodes == judgedNodes {
 			return HealthStatusUnhealthy
 		}
 		return HealthStatusWarning
@@ -576,11 +393,15 @@ func (a *AWSMonitor) calculateOverallHealth(status *HealthStatus) ClusterHealthS
 		}
 	}
 
+	if status.Workloads != nil && len(status.Workloads.Degraded) > 0 {
+		return HealthStatusWarning
+	}
+
 	return HealthStatusHealthy
 }
 
 func (a *AWSMonitor) updateKubeConfig(ctx context.Context, clusterName string) error {
-	cmd := exec.CommandContext(ctx, "aws", "eks", "update-kubeconfig",
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "update-kubeconfig",
 		"--region", a.region,
 		"--name", clusterName)
 
@@ -597,7 +418,7 @@ func (a *AWSMonitor) updateKubeConfig(ctx context.Context, clusterName string) e
 }
 
 func (a *AWSMonitor) getAccountID() string {
-	cmd := exec.Command("aws", "sts", "get-caller-identity",
+	cmd := subprocess.CommandContext(context.Background(), "aws", "sts", "get-caller-identity",
 		"--query", "Account",
 		"--output", "text")
 
@@ -0,0 +1,69 @@
+package monitoring
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+)
+
+// DefaultHealthCacheTTL is how long a cached health result is reused
+const DefaultHealthCacheTTL = 30 * time.Second
+
+type cachedHealth struct {
+	Status    ClusterHealthStatus `json:"status"`
+	CheckedAt time.Time           `json:"checked_at"`
+}
+
+// HealthCache keeps recent overall health results on disk so repeated lookups stay fast
+type HealthCache struct {
+	mu      sync.Mutex
+	path    string
+	ttl     time.Duration
+	entries map[string]cachedHealth
+}
+
+// DefaultHealthCachePath returns the location of the shared health cache file
+func DefaultHealthCachePath() (string, error) {
+	return store.Path("cache", "health.json")
+}
+
+// NewHealthCache loads the cache stored at path; a missing or unreadable file starts empty
+func NewHealthCache(path string, ttl time.Duration) *HealthCache {
+	c := &HealthCache{path: path, ttl: ttl, entries: make(map[string]cachedHealth)}
+	store.LoadJSON(path, &c.entries)
+	return c
+}
+
+// Get returns the cached status for key if it is younger than the TTL
+func (c *HealthCache) Get(key string) (ClusterHealthStatus, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.entries[key]
+	if !exists || time.Since(entry.CheckedAt) > c.ttl {
+		return "", false
+	}
+	return entry.Status, true
+}
+
+// Put records status for key
+func (c *HealthCache) Put(key string, status ClusterHealthStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cachedHealth{Status: status, CheckedAt: time.Now()}
+}
+
+// Save writes unexpired entries back to disk
+func (c *HealthCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, entry := range c.entries {
+		if time.Since(entry.CheckedAt) > c.ttl {
+			delete(c.entries, key)
+		}
+	}
+
+	return store.SaveJSON(c.path, c.entries)
+}
@@ -0,0 +1,32 @@
+package monitoring
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHealthCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "health.json")
+
+	cache := NewHealthCache(path, time.Minute)
+	if _, ok := cache.Get("local/local/dev"); ok {
+		t.Fatal("Get() on an empty cache should miss")
+	}
+
+	cache.Put("local/local/dev", HealthStatusWarning)
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded := NewHealthCache(path, time.Minute)
+	status, ok := reloaded.Get("local/local/dev")
+	if !ok || status != HealthStatusWarning {
+		t.Errorf("Get() after reload = %q, %v, want %q, true", status, ok, HealthStatusWarning)
+	}
+
+	expired := NewHealthCache(path, 0)
+	if _, ok := expired.Get("local/local/dev"); ok {
+		t.Error("Get() should miss once the entry is older than the TTL")
+	}
+}
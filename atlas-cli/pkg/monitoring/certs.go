@@ -0,0 +1,159 @@
+package monitoring
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultCertificateWarning is how close to expiry a cluster certificate is flagged by a health
+// check, unless the context sets another window with WithCertificateWarning
+const DefaultCertificateWarning = 30 * 24 * time.Hour
+
+// Certificate sources
+const (
+	// CertificateSourceAPIServer is the certificate the API server serves
+	CertificateSourceAPIServer = "api-server"
+	// CertificateSourceKubeadm is a certificate kubeadm issued on the control plane node
+	CertificateSourceKubeadm = "kubeadm"
+)
+
+// Directories kubeadm keeps its certificates in on local cluster nodes
+const (
+	minikubeCertDir = "/var/lib/minikube/certs"
+	kubeadmCertDir  = "/etc/kubernetes/pki"
+)
+
+// ClusterCertificate is one of the cluster's own certificates and when it expires
+type ClusterCertificate struct {
+	// Name is "apiserver" for the served certificate, or the kubeadm file name without .crt,
+	// such as apiserver-kubelet-client or etcd/server
+	Name     string    `json:"name"`
+	Source   string    `json:"source"`
+	Subject  string    `json:"subject,omitempty"`
+	NotAfter time.Time `json:"not_after"`
+}
+
+type certificateWarningKey struct{}
+
+// WithCertificateWarning makes health checks run with ctx flag certificates that expire within window
+func WithCertificateWarning(ctx context.Context, window time.Duration) context.Context {
+	return context.WithValue(ctx, certificateWarningKey{}, window)
+}
+
+// CertificateWarningFrom returns the window ctx was given, or DefaultCertificateWarning
+func CertificateWarningFrom(ctx context.Context) time.Duration {
+	if window, ok := ctx.Value(certificateWarningKey{}).(time.Duration); ok && window > 0 {
+		return window
+	}
+	return DefaultCertificateWarning
+}
+
+// FlagExpiringCertificates adds an error for each of the status's certificates that has expired
+// and a warning for each that expires within window
+func FlagExpiringCertificates(status *HealthStatus, window time.Duration, now time.Time) {
+	for _, cert := range status.Certificates {
+		left := cert.NotAfter.Sub(now)
+		switch {
+		case left <= 0:
+			status.Errors = append(status.Errors, fmt.Sprintf("Certificate %s (%s) expired on %s",
+				cert.Name, cert.Source, cert.NotAfter.Format("2006-01-02")))
+		case left < window:
+			status.Warnings = append(status.Warnings, fmt.Sprintf("Certificate %s (%s) expires in %dd, on %s",
+				cert.Name, cert.Source, int(left.Hours()/24), cert.NotAfter.Format("2006-01-02")))
+		}
+	}
+}
+
+// apiServerCertificate reads the certificate the API server at host serves. The chain isn't
+// verified: the check only wants the expiry date, which matters most when it is already invalid.
+func apiServerCertificate(ctx context.Context, host string) (*ClusterCertificate, error) {
+	address, err := apiServerAddress(host)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &tls.Dialer{Config: &tls.Config{InsecureSkipVerify: true}}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to API server %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	peers := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(peers) == 0 {
+		return nil, fmt.Errorf("API server %s sent no certificate", address)
+	}
+	return &ClusterCertificate{
+		Name:     "apiserver",
+		Source:   CertificateSourceAPIServer,
+		Subject:  peers[0].Subject.String(),
+		NotAfter: peers[0].NotAfter,
+	}, nil
+}
+
+// apiServerAddress turns a kubeconfig server URL into host:port, defaulting to port 443
+func apiServerAddress(host string) (string, error) {
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	server, err := url.Parse(host)
+	if err != nil {
+		return "", fmt.Errorf("invalid API server address %q: %w", host, err)
+	}
+	if server.Scheme != "https" {
+		return "", fmt.Errorf("API server %s is not served over TLS", host)
+	}
+	if server.Port() == "" {
+		return net.JoinHostPort(server.Hostname(), "443"), nil
+	}
+	return server.Host, nil
+}
+
+// listCertificatesCommand prints every certificate under dir, each line prefixed with its file
+// name. grep is used because it is on every node image, unlike openssl.
+func listCertificatesCommand(dir string) []string {
+	return []string{"grep", "-r", "--include=*.crt", "^", dir}
+}
+
+// parseCertificateListing parses the output of listCertificatesCommand run on dir. Files that
+// don't hold a PEM certificate are skipped.
+func parseCertificateListing(output []byte, dir string) []ClusterCertificate {
+	files := make(map[string]*strings.Builder)
+	for _, line := range strings.Split(string(output), "\n") {
+		path, content, ok := strings.Cut(strings.TrimRight(line, "\r"), ":")
+		if !ok || !strings.HasSuffix(path, ".crt") {
+			continue
+		}
+		if files[path] == nil {
+			files[path] = &strings.Builder{}
+		}
+		files[path].WriteString(content + "\n")
+	}
+
+	var certificates []ClusterCertificate
+	for path, content := range files {
+		block, _ := pem.Decode([]byte(content.String()))
+		if block == nil {
+			continue
+		}
+		parsed, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		certificates = append(certificates, ClusterCertificate{
+			Name:     strings.TrimSuffix(strings.TrimPrefix(path, strings.TrimSuffix(dir, "/")+"/"), ".crt"),
+			Source:   CertificateSourceKubeadm,
+			Subject:  parsed.Subject.String(),
+			NotAfter: parsed.NotAfter,
+		})
+	}
+	sort.Slice(certificates, func(i, j int) bool { return certificates[i].Name < certificates[j].Name })
+	return certificates
+}
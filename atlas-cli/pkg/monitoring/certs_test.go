@@ -0,0 +1,86 @@
+package monitoring
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCertificateListing(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	// grep -r prefixes every line with the file it came from
+	listing := func(path string, data []byte) string {
+		var b strings.Builder
+		for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+			b.WriteString(path + ":" + line + "\r\n")
+		}
+		return b.String()
+	}
+	output := listing(minikubeCertDir+"/apiserver.crt", testCertificatePEM(t, "minikube", now.Add(300*24*time.Hour))) +
+		listing(minikubeCertDir+"/etcd/server.crt", testCertificatePEM(t, "etcd", now.Add(10*24*time.Hour))) +
+		listing(minikubeCertDir+"/broken.crt", []byte("not a certificate"))
+
+	certificates := parseCertificateListing([]byte(output), minikubeCertDir+"/")
+	if len(certificates) != 2 {
+		t.Fatalf("parseCertificateListing() = %+v, want 2 certificates", certificates)
+	}
+	if got := certificates[0]; got.Name != "apiserver" || got.Source != CertificateSourceKubeadm ||
+		got.Subject != "CN=minikube" || !got.NotAfter.Equal(now.Add(300*24*time.Hour)) {
+		t.Errorf("parseCertificateListing()[0] = %+v", got)
+	}
+	if got := certificates[1]; got.Name != "etcd/server" || !got.NotAfter.Equal(now.Add(10*24*time.Hour)) {
+		t.Errorf("parseCertificateListing()[1] = %+v", got)
+	}
+}
+
+func TestAPIServerCertificate(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.NotFoundHandler())
+	// the check hangs up right after the handshake, which the server would log
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	cert, err := apiServerCertificate(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("apiServerCertificate() error = %v", err)
+	}
+	if want := server.Certificate().NotAfter; cert.Source != CertificateSourceAPIServer || !cert.NotAfter.Equal(want) {
+		t.Errorf("apiServerCertificate() = %+v, want the served certificate expiring %v", cert, want)
+	}
+
+	if _, err := apiServerCertificate(context.Background(), "http://127.0.0.1:8080"); err == nil {
+		t.Error("apiServerCertificate() of a plain HTTP server succeeded, want an error")
+	}
+	if address, _ := apiServerAddress("example.eks.amazonaws.com"); address != "example.eks.amazonaws.com:443" {
+		t.Errorf("apiServerAddress() = %q, want the default port", address)
+	}
+}
+
+func TestFlagExpiringCertificates(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	status := &HealthStatus{Certificates: []ClusterCertificate{
+		{Name: "apiserver", Source: CertificateSourceAPIServer, NotAfter: now.Add(200 * 24 * time.Hour)},
+		{Name: "apiserver-kubelet-client", Source: CertificateSourceKubeadm, NotAfter: now.Add(12 * 24 * time.Hour)},
+		{Name: "front-proxy-client", Source: CertificateSourceKubeadm, NotAfter: now.Add(-time.Hour)},
+	}}
+
+	FlagExpiringCertificates(status, CertificateWarningFrom(context.Background()), now)
+	if len(status.Warnings) != 1 || status.Warnings[0] != "Certificate apiserver-kubelet-client (kubeadm) expires in 12d, on 2025-03-13" {
+		t.Errorf("FlagExpiringCertificates() warnings = %q", status.Warnings)
+	}
+	if len(status.Errors) != 1 || status.Errors[0] != "Certificate front-proxy-client (kubeadm) expired on 2025-03-01" {
+		t.Errorf("FlagExpiringCertificates() errors = %q", status.Errors)
+	}
+
+	status.Warnings, status.Errors = nil, nil
+	ctx := WithCertificateWarning(context.Background(), 365*24*time.Hour)
+	FlagExpiringCertificates(status, CertificateWarningFrom(ctx), now)
+	if len(status.Warnings) != 2 {
+		t.Errorf("FlagExpiringCertificates() with a year's window warnings = %q, want 2", status.Warnings)
+	}
+}
@@ -0,0 +1,139 @@
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"time"
+)
+
+// PagerDutyEventsURL is the PagerDuty Events API v2 endpoint
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// NewChannel creates the channel config describes
+func NewChannel(config ChannelConfig) (Channel, error) {
+	switch config.Type {
+	case ChannelSlack:
+		return &SlackChannel{name: config.Name, url: config.URL}, nil
+	case ChannelWebhook:
+		return &WebhookChannel{name: config.Name, url: config.URL, headers: config.Headers}, nil
+	case ChannelPagerDuty:
+		url := config.URL
+		if url == "" {
+			url = PagerDutyEventsURL
+		}
+		return &PagerDutyChannel{name: config.Name, url: url, routingKey: config.RoutingKey}, nil
+	}
+	return nil, fmt.Errorf("unknown channel type %q", config.Type)
+}
+
+// SlackChannel posts alerts to a Slack incoming webhook
+type SlackChannel struct {
+	name string
+	url  string
+}
+
+func (c *SlackChannel) Name() string {
+	return c.name
+}
+
+func (c *SlackChannel) Send(ctx context.Context, event MonitoringEvent) error {
+	icon := ":warning:"
+	switch {
+	case event.Resolved:
+		icon = ":white_check_mark:"
+	case event.Severity == SeverityCritical:
+		icon = ":rotating_light:"
+	case event.Severity == SeverityInfo:
+		icon = ":information_source:"
+	}
+	text := fmt.Sprintf("%s *[%s]* %s", icon, event.Severity, event.Message)
+	return postJSON(ctx, c.url, nil, map[string]string{"text": text})
+}
+
+// WebhookChannel posts each alert as a JSON MonitoringEvent
+type WebhookChannel struct {
+	name    string
+	url     string
+	headers map[string]string
+}
+
+func (c *WebhookChannel) Name() string {
+	return c.name
+}
+
+func (c *WebhookChannel) Send(ctx context.Context, event MonitoringEvent) error {
+	return postJSON(ctx, c.url, c.headers, event)
+}
+
+// PagerDutyChannel triggers and resolves PagerDuty incidents, one per alert
+type PagerDutyChannel struct {
+	name       string
+	url        string
+	routingKey string
+}
+
+func (c *PagerDutyChannel) Name() string {
+	return c.name
+}
+
+func (c *PagerDutyChannel) Send(ctx context.Context, event MonitoringEvent) error {
+	body := map[string]interface{}{
+		"routing_key":  c.routingKey,
+		"event_action": "trigger",
+		"dedup_key":    event.ID,
+	}
+	if event.Resolved {
+		body["event_action"] = "resolve"
+	} else {
+		severity := string(event.Severity)
+		if event.Severity == "" {
+			severity = string(SeverityWarning)
+		}
+		body["payload"] = map[string]interface{}{
+			"summary":        event.Message,
+			"source":         event.ClusterName,
+			"severity":       severity,
+			"timestamp":      event.Timestamp.Format(time.RFC3339),
+			"component":      "atlas-cli",
+			"custom_details": event.Details,
+		}
+	}
+	return postJSON(ctx, c.url, nil, body)
+}
+
+// postJSON posts body as JSON to url, failing on a non-2xx response
+func postJSON(ctx context.Context, url string, headers map[string]string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to build notification: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// webhook URLs are credentials, so keep them out of the error
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// DockerMonitor checks clusters whose nodes run as docker containers, such as kind and k3d
+// clusters. The API checks are the same ones the minikube monitor runs.
+type DockerMonitor struct {
+	name          string
+	kubeContext   func(clusterName string) string
+	nodeContainer func(clusterName string) string
+	// certDir is where kubeadm keeps certificates in the node container, empty when the
+	// cluster isn't built with kubeadm
+	certDir          string
+	kube             *MinikubeMonitor
+	activeMonitoring map[string]context.CancelFunc
+}
+
+// NewKindMonitor creates a monitor for kind clusters, reached through their kind-<name> context
+func NewKindMonitor() *DockerMonitor {
+	monitor := newDockerMonitor("kind",
+		func(clusterName string) string { return "kind-" + clusterName },
+		func(clusterName string) string { return clusterName + "-control-plane" })
+	monitor.certDir = kubeadmCertDir
+	return monitor
+}
+
+// NewK3dMonitor creates a monitor for k3d clusters, reached through their k3d-<name> context
+func NewK3dMonitor() *DockerMonitor {
+	return newDockerMonitor("k3d",
+		func(clusterName string) string { return "k3d-" + clusterName },
+		func(clusterName string) string { return "k3d-" + clusterName + "-server-0" })
+}
+
+// NewKubeadmMonitor creates a monitor for kubeadm clusters, reached through their kubeadm-<name>
+// context. Their nodes are machines rather than containers, so only the API checks run.
+func NewKubeadmMonitor() *DockerMonitor {
+	return newDockerMonitor("kubeadm",
+		func(clusterName string) string { return "kubeadm-" + clusterName },
+		nil)
+}
+
+// NewClusterAPIMonitor creates a monitor for Cluster API workload clusters, reached through their
+// capi-<name> context. Their nodes are cloud machines, so only the API checks run.
+func NewClusterAPIMonitor() *DockerMonitor {
+	return newDockerMonitor("capi",
+		func(clusterName string) string { return "capi-" + clusterName },
+		nil)
+}
+
+func newDockerMonitor(name string, kubeContext, nodeContainer func(string) string) *DockerMonitor {
+	return &DockerMonitor{
+		name:             name,
+		kubeContext:      kubeContext,
+		nodeContainer:    nodeContainer,
+		kube:             NewMinikubeMonitor(),
+		activeMonitoring: make(map[string]context.CancelFunc),
+	}
+}
+
+func (k *DockerMonitor) GetMonitorName() string {
+	return k.name
+}
+
+func (k *DockerMonitor) CheckClusterHealth(ctx context.Context, clusterName string) (*HealthStatus, error) {
+	startTime := time.Now()
+
+	status := &HealthStatus{
+		ClusterName:   clusterName,
+		OverallStatus: HealthStatusUnknown,
+		LastChecked:   startTime,
+		Warnings:      []string{},
+		Errors:        []string{},
+	}
+
+	if !k.isRunning(ctx, clusterName) {
+		status.OverallStatus = HealthStatusUnhealthy
+		status.Errors = append(status.Errors, fmt.Sprintf("%s cluster is not running", k.name))
+		status.CheckDuration = time.Since(startTime)
+		return status, nil
+	}
+
+	kubeContext := k.kubeContext(clusterName)
+	controlPlaneHealth, err := k.kube.checkControlPlane(ctx, kubeContext)
+	if err != nil {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("Control plane check failed: %v", err))
+	} else {
+		status.ControlPlane = controlPlaneHealth
+	}
+
+	nodes, err := k.kube.checkNodes(ctx, kubeContext)
+	if err != nil {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("Node check failed: %v", err))
+	} else {
+		status.Nodes = nodes
+	}
+
+	podHealth, err := k.kube.checkPods(ctx, kubeContext)
+	if err != nil {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("Pod check failed: %v", err))
+	} else {
+		status.Pods = podHealth
+	}
+
+	serviceHealth, err := k.kube.checkServices(ctx, kubeContext)
+	if err != nil {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("Service check failed: %v", err))
+	} else {
+		status.Services = serviceHealth
+	}
+
+	workloadHealth, err := k.kube.checkWorkloads(ctx, kubeContext)
+	if err != nil {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("Workload check failed: %v", err))
+	} else {
+		status.Workloads = workloadHealth
+	}
+
+	certificate, err := k.kube.checkAPIServerCertificate(ctx, kubeContext)
+	if err != nil {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("API server certificate check failed: %v", err))
+	} else {
+		status.Certificates = append(status.Certificates, *certificate)
+	}
+
+	if k.certDir != "" {
+		kubeadmCertificates, err := k.checkKubeadmCertificates(ctx, clusterName)
+		if err != nil {
+			status.Warnings = append(status.Warnings, fmt.Sprintf("Kubeadm certificate check failed: %v", err))
+		} else {
+			status.Certificates = append(status.Certificates, kubeadmCertificates...)
+		}
+	}
+	FlagExpiringCertificates(status, CertificateWarningFrom(ctx), time.Now())
+
+	status.OverallStatus = k.kube.calculateOverallHealth(status)
+	status.CheckDuration = time.Since(startTime)
+
+	return status, nil
+}
+
+func (k *DockerMonitor) GetClusterMetrics(ctx context.Context, clusterName string) (*ClusterMetrics, error) {
+	metrics := &ClusterMetrics{
+		ClusterName: clusterName,
+		Timestamp:   time.Now(),
+	}
+
+	if !k.isRunning(ctx, clusterName) {
+		return nil, fmt.Errorf("cluster %s is not running", clusterName)
+	}
+
+	kubeContext := k.kubeContext(clusterName)
+	nodeMetrics, err := k.kube.getNodeMetrics(ctx, kubeContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node metrics: %w", err)
+	}
+	metrics.NodeMetrics = nodeMetrics
+
+	podMetrics, err := k.kube.getPodMetrics(ctx, kubeContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod metrics: %w", err)
+	}
+	metrics.PodMetrics = podMetrics
+
+	resourceUsage, err := k.kube.calculateResourceUsage(nodeMetrics, podMetrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate resource usage: %w", err)
+	}
+	metrics.ResourceUsage = resourceUsage
+
+	return metrics, nil
+}
+
+func (k *DockerMonitor) StartMonitoring(ctx context.Context, config *MonitoringConfig) error {
+	for _, clusterName := range config.ClusterNames {
+		if _, exists := k.activeMonitoring[clusterName]; exists {
+			continue
+		}
+
+		monitorCtx, cancel := context.WithCancel(ctx)
+		k.activeMonitoring[clusterName] = cancel
+
+		go k.monitorCluster(monitorCtx, clusterName, config)
+	}
+
+	return nil
+}
+
+func (k *DockerMonitor) StopMonitoring(ctx context.Context, clusterName string) error {
+	if cancel, exists := k.activeMonitoring[clusterName]; exists {
+		cancel()
+		delete(k.activeMonitoring, clusterName)
+	}
+
+	return nil
+}
+
+func (k *DockerMonitor) monitorCluster(ctx context.Context, clusterName string, config *MonitoringConfig) {
+	healthTicker := time.NewTicker(config.CheckInterval)
+	metricsTicker := time.NewTicker(config.MetricsInterval)
+
+	defer healthTicker.Stop()
+	defer metricsTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-healthTicker.C:
+			status, err := k.CheckClusterHealth(ctx, clusterName)
+			config.recordHealth(clusterName, status, err)
+			if config.EnableAlerts && config.Notifier != nil {
+				config.notify(ctx, clusterName, "health", EvaluateHealth(clusterName, status, err, config.AlertThresholds))
+			} else if err != nil && config.EnableAlerts {
+				fmt.Printf("Health check failed for cluster %s: %v\n", clusterName, err)
+			}
+		case <-metricsTicker.C:
+			metrics, err := k.GetClusterMetrics(ctx, clusterName)
+			if config.EnableAlerts && config.Notifier != nil && err == nil {
+				config.notify(ctx, clusterName, "metrics", EvaluateMetrics(clusterName, metrics, config.AlertThresholds))
+			} else if err != nil && config.EnableAlerts {
+				fmt.Printf("Metrics collection failed for cluster %s: %v\n", clusterName, err)
+			}
+		}
+	}
+}
+
+// checkKubeadmCertificates reads the certificates kubeadm issued from the control-plane container
+func (k *DockerMonitor) checkKubeadmCertificates(ctx context.Context, clusterName string) ([]ClusterCertificate, error) {
+	args := append([]string{"exec", k.nodeContainer(clusterName)}, listCertificatesCommand(k.certDir)...)
+	output, err := subprocess.CommandContext(ctx, "docker", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list certificates in %s: %w", k.certDir, err)
+	}
+	return parseCertificateListing(output, k.certDir), nil
+}
+
+// isRunning reports whether the cluster's control-plane container is running. Clusters without
+// node containers are assumed running and left to the API checks.
+func (k *DockerMonitor) isRunning(ctx context.Context, clusterName string) bool {
+	if k.nodeContainer == nil {
+		return true
+	}
+	output, err := subprocess.CommandContext(ctx, "docker", "inspect", "-f", "{{.State.Running}}", k.nodeContainer(clusterName)).Output()
+	return err == nil && strings.TrimSpace(string(output)) == "true"
+}
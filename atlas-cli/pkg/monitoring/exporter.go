@@ -0,0 +1,197 @@
+package monitoring
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/metrics"
+)
+
+// exporterMetric is one metric family the exporter renders
+type exporterMetric struct {
+	name string
+	help string
+}
+
+var (
+	metricClusterUp         = exporterMetric{"atlas_cluster_up", "Whether the cluster's last health check found it healthy or degraded (1) or down (0)"}
+	metricClusterLastCheck  = exporterMetric{"atlas_cluster_last_check_timestamp_seconds", "Unix time of the cluster's last health check"}
+	metricClusterCheckTime  = exporterMetric{"atlas_cluster_check_duration_seconds", "How long the cluster's last health check took"}
+	metricClusterNodes      = exporterMetric{"atlas_cluster_nodes", "Nodes in the cluster"}
+	metricClusterReadyNodes = exporterMetric{"atlas_cluster_ready_nodes", "Nodes reporting Ready in the cluster"}
+	metricClusterPods       = exporterMetric{"atlas_cluster_pods", "Pods in the cluster by phase"}
+	metricClusterFailedPods = exporterMetric{"atlas_cluster_failed_pods", "Pods in the Failed phase in the cluster"}
+	metricComponentUp       = exporterMetric{"atlas_control_plane_component_up", "Whether a control plane component is healthy (1) or not (0)"}
+	metricNodeReady         = exporterMetric{"atlas_node_ready", "Whether the node reports Ready (1) or not (0)"}
+	metricClusterCPU        = exporterMetric{"atlas_cluster_cpu_percent", "CPU used across the cluster's nodes, as a percentage of capacity"}
+	metricClusterMemory     = exporterMetric{"atlas_cluster_memory_percent", "Memory used across the cluster's nodes, as a percentage of capacity"}
+	metricClusterStorage    = exporterMetric{"atlas_cluster_storage_percent", "Storage used in the cluster, as a percentage of capacity"}
+	metricNodeCPU           = exporterMetric{"atlas_node_cpu_percent", "CPU used on the node, as a percentage of capacity"}
+	metricNodeMemory        = exporterMetric{"atlas_node_memory_percent", "Memory used on the node, as a percentage of capacity"}
+	metricClusterMetricsUp  = exporterMetric{"atlas_cluster_metrics_up", "Whether resource metrics could be collected from the cluster (1) or not (0)"}
+)
+
+var exporterMetrics = []exporterMetric{
+	metricClusterUp, metricClusterLastCheck, metricClusterCheckTime, metricClusterNodes, metricClusterReadyNodes,
+	metricClusterPods, metricClusterFailedPods, metricComponentUp, metricNodeReady, metricClusterCPU,
+	metricClusterMemory, metricClusterStorage, metricNodeCPU, metricNodeMemory, metricClusterMetricsUp,
+}
+
+// ExportedCluster identifies a cluster whose samples the exporter serves
+type ExportedCluster struct {
+	Provider string
+	Name     string
+}
+
+type exporterSample struct {
+	health     *HealthStatus
+	checkErr   error
+	metrics    *ClusterMetrics
+	metricsErr error
+	checkedAt  time.Time
+}
+
+// Exporter renders the latest health check and resource metrics of each monitored cluster as
+// Prometheus metrics labelled with the cluster and its provider. Every scrape rebuilds the
+// series from the latest samples, so clusters and nodes that go away stop being exported.
+type Exporter struct {
+	mu      sync.Mutex
+	samples map[ExportedCluster]*exporterSample
+}
+
+// NewExporter creates an exporter with no clusters
+func NewExporter() *Exporter {
+	return &Exporter{samples: make(map[ExportedCluster]*exporterSample)}
+}
+
+// Record stores the result of a health check, and of collecting metrics when that was attempted
+// (metricsData and metricsErr both nil means it was not)
+func (e *Exporter) Record(cluster ExportedCluster, health *HealthStatus, checkErr error, metricsData *ClusterMetrics, metricsErr error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.samples[cluster] = &exporterSample{health: health, checkErr: checkErr, metrics: metricsData, metricsErr: metricsErr, checkedAt: time.Now()}
+}
+
+// Retain forgets every cluster not in clusters
+func (e *Exporter) Retain(clusters []ExportedCluster) {
+	keep := make(map[ExportedCluster]bool, len(clusters))
+	for _, cluster := range clusters {
+		keep[cluster] = true
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for cluster := range e.samples {
+		if !keep[cluster] {
+			delete(e.samples, cluster)
+		}
+	}
+}
+
+// Clusters returns the clusters the exporter has samples for, sorted by provider and name
+func (e *Exporter) Clusters() []ExportedCluster {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	clusters := make([]ExportedCluster, 0, len(e.samples))
+	for cluster := range e.samples {
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Provider != clusters[j].Provider {
+			return clusters[i].Provider < clusters[j].Provider
+		}
+		return clusters[i].Name < clusters[j].Name
+	})
+	return clusters
+}
+
+// Collect renders the latest samples into r; register it with r.OnScrape
+func (e *Exporter) Collect(r *metrics.Registry) {
+	names := make([]string, len(exporterMetrics))
+	for i, metric := range exporterMetrics {
+		names[i] = metric.name
+	}
+	r.Reset(names...)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for cluster, sample := range e.samples {
+		sample.collect(r, cluster)
+	}
+}
+
+func (s *exporterSample) collect(r *metrics.Registry, cluster ExportedCluster) {
+	labels := func(extra ...string) metrics.Labels {
+		l := metrics.Labels{"cluster": cluster.Name, "provider": cluster.Provider}
+		for i := 0; i+1 < len(extra); i += 2 {
+			l[extra[i]] = extra[i+1]
+		}
+		return l
+	}
+	set := func(metric exporterMetric, l metrics.Labels, value float64) {
+		r.Set(metric.name, metric.help, l, value)
+	}
+
+	set(metricClusterUp, labels(), boolValue(Summarize(cluster.Name, s.health, s.checkErr).Up()))
+	set(metricClusterLastCheck, labels(), float64(s.checkedAt.Unix()))
+
+	if health := s.health; health != nil && s.checkErr == nil {
+		set(metricClusterCheckTime, labels(), health.CheckDuration.Seconds())
+		ready := 0
+		for _, node := range health.Nodes {
+			if node.Ready {
+				ready++
+			}
+			set(metricNodeReady, labels("node", node.Name), boolValue(node.Ready))
+		}
+		set(metricClusterNodes, labels(), float64(len(health.Nodes)))
+		set(metricClusterReadyNodes, labels(), float64(ready))
+
+		if pods := health.Pods; pods != nil {
+			for phase, count := range map[string]int{
+				"running": pods.RunningPods, "pending": pods.PendingPods, "failed": pods.FailedPods,
+				"succeeded": pods.SucceededPods, "unknown": pods.UnknownPods,
+			} {
+				set(metricClusterPods, labels("phase", phase), float64(count))
+			}
+			set(metricClusterFailedPods, labels(), float64(pods.FailedPods))
+		}
+
+		if cp := health.ControlPlane; cp != nil {
+			for component, status := range map[string]ComponentStatus{
+				"api_server": cp.APIServer, "scheduler": cp.Scheduler, "controller_manager": cp.ControllerManager, "etcd": cp.Etcd,
+			} {
+				// unknown means the component could not be checked, which isn't the same as down
+				if status.Status != ComponentUnknown && status.Status != "" {
+					set(metricComponentUp, labels("component", component), boolValue(status.Status == ComponentHealthy))
+				}
+			}
+		}
+	}
+
+	if s.metrics == nil && s.metricsErr == nil {
+		return
+	}
+	set(metricClusterMetricsUp, labels(), boolValue(s.metricsErr == nil && s.metrics != nil))
+	if s.metrics == nil {
+		return
+	}
+	if usage := s.metrics.ResourceUsage; usage != nil {
+		set(metricClusterCPU, labels(), usage.CPUPercentage)
+		set(metricClusterMemory, labels(), usage.MemoryPercentage)
+		if usage.StoragePercentage > 0 {
+			set(metricClusterStorage, labels(), usage.StoragePercentage)
+		}
+	}
+	for _, node := range s.metrics.NodeMetrics {
+		set(metricNodeCPU, labels("node", node.NodeName), node.CPUUsage.Usage)
+		set(metricNodeMemory, labels("node", node.NodeName), node.MemoryUsage.Usage)
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,78 @@
+package monitoring
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/metrics"
+)
+
+func scrape(t *testing.T, registry *metrics.Registry) string {
+	t.Helper()
+	var out strings.Builder
+	if err := registry.Write(&out); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	return out.String()
+}
+
+func TestExporter(t *testing.T) {
+	exporter := NewExporter()
+	registry := metrics.NewRegistry()
+	registry.OnScrape(exporter.Collect)
+
+	dev := ExportedCluster{Provider: "kind", Name: "dev"}
+	prod := ExportedCluster{Provider: "aws", Name: "prod"}
+	health := &HealthStatus{
+		OverallStatus: HealthStatusWarning,
+		Nodes:         []NodeHealth{{Name: "dev-1", Ready: true}, {Name: "dev-2"}},
+		Pods:          &PodHealth{RunningPods: 10, FailedPods: 2},
+		ControlPlane:  &ControlPlaneHealth{APIServer: ComponentStatus{Status: ComponentHealthy}, Etcd: ComponentStatus{Status: ComponentUnhealthy}},
+	}
+	usage := &ClusterMetrics{
+		ResourceUsage: &ResourceUsage{CPUPercentage: 55.5, MemoryPercentage: 70},
+		NodeMetrics:   []NodeMetrics{{NodeName: "dev-1", CPUUsage: ResourceValue{Usage: 60}, MemoryUsage: ResourceValue{Usage: 75}}},
+	}
+	exporter.Record(dev, health, nil, usage, nil)
+	exporter.Record(prod, nil, errors.New("no credentials"), nil, nil)
+
+	out := scrape(t, registry)
+	for _, want := range []string{
+		`atlas_cluster_up{cluster="dev",provider="kind"} 1`,
+		`atlas_cluster_up{cluster="prod",provider="aws"} 0`,
+		`atlas_cluster_ready_nodes{cluster="dev",provider="kind"} 1`,
+		`atlas_node_ready{cluster="dev",node="dev-2",provider="kind"} 0`,
+		`atlas_cluster_failed_pods{cluster="dev",provider="kind"} 2`,
+		`atlas_cluster_pods{cluster="dev",phase="running",provider="kind"} 10`,
+		`atlas_control_plane_component_up{cluster="dev",component="etcd",provider="kind"} 0`,
+		`atlas_cluster_cpu_percent{cluster="dev",provider="kind"} 55.5`,
+		`atlas_node_cpu_percent{cluster="dev",node="dev-1",provider="kind"} 60`,
+		`atlas_cluster_metrics_up{cluster="dev",provider="kind"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `component="scheduler"`) {
+		t.Error("a component that was not checked should not be exported")
+	}
+	if strings.Contains(out, `atlas_cluster_metrics_up{cluster="prod"`) {
+		t.Error("metrics_up should only be exported when metrics were collected")
+	}
+
+	// a node leaving and a cluster going away both drop their series
+	health.Nodes = health.Nodes[:1]
+	exporter.Record(dev, health, nil, nil, errors.New("metrics-server not installed"))
+	exporter.Retain([]ExportedCluster{dev})
+	out = scrape(t, registry)
+	if strings.Contains(out, `node="dev-2"`) || strings.Contains(out, `cluster="prod"`) || strings.Contains(out, "atlas_cluster_cpu_percent") {
+		t.Errorf("stale series still exported:\n%s", out)
+	}
+	if !strings.Contains(out, `atlas_cluster_metrics_up{cluster="dev",provider="kind"} 0`) {
+		t.Errorf("metrics output missing failed metrics collection:\n%s", out)
+	}
+	if got := exporter.Clusters(); len(got) != 1 || got[0] != dev {
+		t.Errorf("Clusters() = %v, want only dev", got)
+	}
+}
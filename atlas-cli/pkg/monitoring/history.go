@@ -0,0 +1,270 @@
+package monitoring
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+)
+
+const (
+	// DefaultHistoryRetention is how long metrics samples are kept
+	DefaultHistoryRetention = 7 * 24 * time.Hour
+	// DefaultHistoryInterval is the minimum spacing between recorded samples of one cluster, so
+	// a fast watch loop doesn't grow the history faster than anyone will read it
+	DefaultHistoryInterval = 30 * time.Second
+)
+
+// MetricsSample is one recorded reading of a cluster's resource usage
+type MetricsSample struct {
+	Time           time.Time `json:"time"`
+	Provider       string    `json:"provider"`
+	Cluster        string    `json:"cluster"`
+	CPUPercent     float64   `json:"cpu_percent"`
+	MemoryPercent  float64   `json:"memory_percent"`
+	StoragePercent float64   `json:"storage_percent,omitempty"`
+	Nodes          int       `json:"nodes"`
+	// Samples is how many readings were averaged into this one; 1 for a raw sample
+	Samples int `json:"samples"`
+}
+
+// MetricsHistory keeps the resource usage collected while monitoring, one JSON-lines file per
+// cluster under dir/<provider>/<cluster>.jsonl. Samples older than the retention are dropped
+// when a cluster's file is next written.
+type MetricsHistory struct {
+	mu          sync.Mutex
+	dir         string
+	retention   time.Duration
+	minInterval time.Duration
+	last        map[string]time.Time
+	pruned      map[string]time.Time
+	nowFunc     func() time.Time
+}
+
+// DefaultMetricsHistoryDir returns the directory metrics history is kept in
+func DefaultMetricsHistoryDir() (string, error) {
+	return store.Path("metrics")
+}
+
+// NewMetricsHistory opens the history kept in dir with the default retention and interval
+func NewMetricsHistory(dir string) *MetricsHistory {
+	return &MetricsHistory{
+		dir:         dir,
+		retention:   DefaultHistoryRetention,
+		minInterval: DefaultHistoryInterval,
+		last:        make(map[string]time.Time),
+		pruned:      make(map[string]time.Time),
+		nowFunc:     time.Now,
+	}
+}
+
+// Record appends a sample of m for provider's cluster. It does nothing when m has no resource
+// usage or the cluster was recorded less than the minimum interval ago.
+func (h *MetricsHistory) Record(provider, cluster string, m *ClusterMetrics) error {
+	if h == nil || m == nil || m.ResourceUsage == nil {
+		return nil
+	}
+	sample := MetricsSample{
+		Time:           m.Timestamp,
+		Provider:       provider,
+		Cluster:        cluster,
+		CPUPercent:     m.ResourceUsage.CPUPercentage,
+		MemoryPercent:  m.ResourceUsage.MemoryPercentage,
+		StoragePercent: m.ResourceUsage.StoragePercentage,
+		Nodes:          len(m.NodeMetrics),
+		Samples:        1,
+	}
+	if sample.Time.IsZero() {
+		sample.Time = h.nowFunc()
+	}
+	sample.Time = sample.Time.UTC()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	path, err := h.path(provider, cluster)
+	if err != nil {
+		return err
+	}
+	if last, ok := h.last[path]; ok && sample.Time.Sub(last) < h.minInterval {
+		return nil
+	}
+	if h.nowFunc().Sub(h.pruned[path]) > time.Hour {
+		if err := h.prune(path); err != nil {
+			return err
+		}
+		h.pruned[path] = h.nowFunc()
+	}
+
+	data, err := json.Marshal(sample)
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics sample: %w", err)
+	}
+	if err := store.AppendFile(path, append(data, '\n')); err != nil {
+		return err
+	}
+	h.last[path] = sample.Time
+	return nil
+}
+
+// Query returns the samples recorded at or after since, oldest first. An empty provider or
+// cluster matches every provider or cluster.
+func (h *MetricsHistory) Query(provider, cluster string, since time.Time) ([]MetricsSample, error) {
+	for _, name := range []string{provider, cluster} {
+		if strings.ContainsAny(name, `/\*?[`) || name == ".." {
+			return nil, fmt.Errorf("invalid name %q", name)
+		}
+	}
+	providerPattern, clusterPattern := "*", "*"
+	if provider != "" {
+		providerPattern = provider
+	}
+	if cluster != "" {
+		clusterPattern = cluster
+	}
+	paths, err := filepath.Glob(filepath.Join(h.dir, providerPattern, clusterPattern+".jsonl"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list metrics history: %w", err)
+	}
+
+	var samples []MetricsSample
+	for _, path := range paths {
+		fileSamples, err := readSamples(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, sample := range fileSamples {
+			if !sample.Time.Before(since) {
+				samples = append(samples, sample)
+			}
+		}
+	}
+	sortSamples(samples)
+	return samples, nil
+}
+
+// Downsample averages samples into buckets of resolution per cluster, each stamped with the
+// start of its bucket. A resolution of zero returns samples unchanged.
+func Downsample(samples []MetricsSample, resolution time.Duration) []MetricsSample {
+	if resolution <= 0 {
+		return samples
+	}
+	type bucketKey struct {
+		provider, cluster string
+		start             time.Time
+	}
+	buckets := make(map[bucketKey]*MetricsSample)
+	var order []bucketKey
+	for _, sample := range samples {
+		key := bucketKey{sample.Provider, sample.Cluster, sample.Time.Truncate(resolution)}
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &MetricsSample{Time: key.start, Provider: key.provider, Cluster: key.cluster}
+			buckets[key] = bucket
+			order = append(order, key)
+		}
+		weight := sample.Samples
+		if weight < 1 {
+			weight = 1
+		}
+		// running sums; divided by the sample count below
+		bucket.CPUPercent += sample.CPUPercent * float64(weight)
+		bucket.MemoryPercent += sample.MemoryPercent * float64(weight)
+		bucket.StoragePercent += sample.StoragePercent * float64(weight)
+		if sample.Nodes > bucket.Nodes {
+			bucket.Nodes = sample.Nodes
+		}
+		bucket.Samples += weight
+	}
+
+	result := make([]MetricsSample, 0, len(order))
+	for _, key := range order {
+		bucket := buckets[key]
+		n := float64(bucket.Samples)
+		bucket.CPUPercent /= n
+		bucket.MemoryPercent /= n
+		bucket.StoragePercent /= n
+		result = append(result, *bucket)
+	}
+	sortSamples(result)
+	return result
+}
+
+func (h *MetricsHistory) path(provider, cluster string) (string, error) {
+	if provider == "" || cluster == "" || strings.ContainsAny(provider+cluster, `/\`) || provider == ".." || cluster == ".." {
+		return "", fmt.Errorf("invalid metrics history key %s/%s", provider, cluster)
+	}
+	return filepath.Join(h.dir, provider, cluster+".jsonl"), nil
+}
+
+// prune rewrites path without samples older than the retention
+func (h *MetricsHistory) prune(path string) error {
+	samples, err := readSamples(path)
+	if err != nil || len(samples) == 0 {
+		return err
+	}
+	cutoff := h.nowFunc().Add(-h.retention)
+	if !samples[0].Time.Before(cutoff) {
+		return nil
+	}
+
+	var buf strings.Builder
+	for _, sample := range samples {
+		if sample.Time.Before(cutoff) {
+			continue
+		}
+		data, err := json.Marshal(sample)
+		if err != nil {
+			return fmt.Errorf("failed to encode metrics sample: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return store.WriteFile(path, []byte(buf.String()))
+}
+
+// readSamples reads a history file in the order it was written, skipping lines that can't be
+// decoded, such as one cut short by a crash. A missing file has no samples.
+func readSamples(path string) ([]MetricsSample, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics history: %w", err)
+	}
+	defer file.Close()
+
+	var samples []MetricsSample
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var sample MetricsSample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read metrics history: %w", err)
+	}
+	return samples, nil
+}
+
+func sortSamples(samples []MetricsSample) {
+	sort.SliceStable(samples, func(i, j int) bool {
+		if !samples[i].Time.Equal(samples[j].Time) {
+			return samples[i].Time.Before(samples[j].Time)
+		}
+		if samples[i].Cluster != samples[j].Cluster {
+			return samples[i].Cluster < samples[j].Cluster
+		}
+		return samples[i].Provider < samples[j].Provider
+	})
+}
@@ -0,0 +1,92 @@
+package monitoring
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsHistory(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	history := NewMetricsHistory(dir)
+	history.nowFunc = func() time.Time { return now }
+
+	usage := func(at time.Time, cpu, memory float64) *ClusterMetrics {
+		return &ClusterMetrics{
+			Timestamp:     at,
+			NodeMetrics:   []NodeMetrics{{NodeName: "n1"}, {NodeName: "n2"}},
+			ResourceUsage: &ResourceUsage{CPUPercentage: cpu, MemoryPercentage: memory},
+		}
+	}
+
+	// a sample past the retention is dropped the first time the file is written
+	path := filepath.Join(dir, "kind", "dev.jsonl")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	stale := `{"time":"2026-03-01T00:00:00Z","provider":"kind","cluster":"dev","cpu_percent":99,"memory_percent":99,"nodes":1,"samples":1}` + "\n"
+	if err := os.WriteFile(path, []byte(stale+"not json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, record := range []struct {
+		provider, cluster string
+		m                 *ClusterMetrics
+	}{
+		{"kind", "dev", usage(now.Add(-50*time.Minute), 10, 40)},
+		{"kind", "dev", usage(now.Add(-50*time.Minute+10*time.Second), 90, 90)}, // inside the minimum interval
+		{"kind", "dev", usage(now.Add(-49*time.Minute), 30, 60)},
+		{"kind", "dev", usage(now.Add(-10*time.Minute), 50, 50)},
+		{"k3d", "dev", usage(now.Add(-5*time.Minute), 5, 5)},
+		{"kind", "dev", &ClusterMetrics{}}, // no resource usage
+	} {
+		if err := history.Record(record.provider, record.cluster, record.m); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	if err := history.Record("kind", "../escape", usage(now, 1, 1)); err == nil {
+		t.Error("Record() with a path in the cluster name should fail")
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "2026-03-01") {
+		t.Errorf("history still holds a sample past the retention:\n%s", data)
+	}
+
+	samples, err := history.Query("kind", "dev", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 3 || samples[0].CPUPercent != 10 || samples[1].CPUPercent != 30 || samples[0].Nodes != 2 {
+		t.Fatalf("Query(kind, dev) = %+v, want the 3 samples outside the minimum interval", samples)
+	}
+	if samples, _ := history.Query("", "dev", now.Add(-15*time.Minute)); len(samples) != 2 || samples[1].Provider != "k3d" {
+		t.Errorf("Query(all providers, since 15m) = %+v, want the kind and k3d samples", samples)
+	}
+	if samples, _ := history.Query("", "", now.Add(-time.Hour)); len(samples) != 4 {
+		t.Errorf("Query(all) returned %d samples, want 4", len(samples))
+	}
+	if _, err := history.Query("*", "", now); err == nil {
+		t.Error("Query() with a glob pattern should fail")
+	}
+
+	hourly := Downsample(samples, time.Hour)
+	if len(hourly) != 1 || hourly[0].CPUPercent != 30 || hourly[0].MemoryPercent != 50 || hourly[0].Samples != 3 {
+		t.Errorf("Downsample(1h) = %+v, want one bucket averaging the 3 samples", hourly)
+	}
+	if !hourly[0].Time.Equal(now.Add(-time.Hour)) {
+		t.Errorf("Downsample(1h) bucket starts at %v, want %v", hourly[0].Time, now.Add(-time.Hour))
+	}
+	if byMinute := Downsample(samples, time.Minute); len(byMinute) != 3 {
+		t.Errorf("Downsample(1m) = %+v, want a bucket per sample", byMinute)
+	}
+	// averaging already averaged buckets weighs them by their sample counts
+	merged := Downsample([]MetricsSample{
+		{Time: now, Provider: "kind", Cluster: "dev", CPUPercent: 10, Samples: 3},
+		{Time: now, Provider: "kind", Cluster: "dev", CPUPercent: 50, Samples: 1},
+	}, time.Hour)
+	if len(merged) != 1 || merged[0].CPUPercent != 20 || merged[0].Samples != 4 {
+		t.Errorf("Downsample(weighted) = %+v, want CPU 20 over 4 samples", merged)
+	}
+}
@@ -20,6 +20,8 @@ type HealthStatus struct {
 	Nodes            []NodeHealth         `json:"nodes"`
 	Pods             *PodHealth           `json:"pods"`
 	Services         *ServiceHealth       `json:"services"`
+	Workloads        *WorkloadHealth      `json:"workloads,omitempty"`
+	Certificates     []ClusterCertificate `json:"certificates,omitempty"`
 	LastChecked      time.Time            `json:"last_checked"`
 	CheckDuration    time.Duration        `json:"check_duration"`
 	Warnings         []string             `json:"warnings,omitempty"`
@@ -43,6 +45,10 @@ type MonitoringConfig struct {
 	AlertThresholds  *AlertThresholds `json:"alert_thresholds,omitempty"`
 	EnableAlerts     bool          `json:"enable_alerts"`
 	LogPath          string        `json:"log_path,omitempty"`
+	// Notifier receives alerts when EnableAlerts is set; without one they are printed
+	Notifier         *Notifier     `json:"-"`
+	// Journal, when set, records each change in a cluster's overall health
+	Journal          *EventJournal `json:"-"`
 }
 
 type ClusterHealthStatus string
@@ -79,6 +85,7 @@ type NodeHealth struct {
 	Name        string            `json:"name"`
 	Status      NodeHealthStatus  `json:"status"`
 	Ready       bool              `json:"ready"`
+	OS          string            `json:"os,omitempty"`
 	Conditions  []NodeCondition   `json:"conditions"`
 	Resources   *NodeResources    `json:"resources,omitempty"`
 	Version     string            `json:"version"`
@@ -91,6 +98,9 @@ const (
 	NodeHealthy     NodeHealthStatus = "healthy"
 	NodeNotReady    NodeHealthStatus = "not_ready"
 	NodeUnknown     NodeHealthStatus = "unknown"
+	// NodeStarting is a Windows node that joined recently and isn't ready yet; it doesn't count
+	// against the cluster's health until WindowsNodeStartupGrace has passed
+	NodeStarting    NodeHealthStatus = "starting"
 )
 
 type NodeCondition struct {
@@ -143,6 +153,30 @@ type ServiceHealth struct {
 	ServicesByType    map[string]int `json:"services_by_type"`
 }
 
+// WorkloadHealth compares each Deployment, StatefulSet and DaemonSet with the replicas it wants
+type WorkloadHealth struct {
+	Deployments   WorkloadCounts     `json:"deployments"`
+	StatefulSets  WorkloadCounts     `json:"statefulsets"`
+	DaemonSets    WorkloadCounts     `json:"daemonsets"`
+	Degraded      []DegradedWorkload `json:"degraded,omitempty"`
+}
+
+type WorkloadCounts struct {
+	Total   int `json:"total"`
+	Healthy int `json:"healthy"`
+}
+
+// DegradedWorkload is a workload with fewer ready replicas than it wants. For DaemonSets the
+// replicas are the nodes the daemon should run on.
+type DegradedWorkload struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Desired   int32  `json:"desired"`
+	Ready     int32  `json:"ready"`
+	Message   string `json:"message"`
+}
+
 type NodeMetrics struct {
 	NodeName      string        `json:"node_name"`
 	CPUUsage      ResourceValue `json:"cpu_usage"`
@@ -212,14 +246,14 @@ type PersistentVolumeMetrics struct {
 }
 
 type AlertThresholds struct {
-	CPUWarning       float64 `json:"cpu_warning"`
-	CPUCritical      float64 `json:"cpu_critical"`
-	MemoryWarning    float64 `json:"memory_warning"`
-	MemoryCritical   float64 `json:"memory_critical"`
-	StorageWarning   float64 `json:"storage_warning"`
-	StorageCritical  float64 `json:"storage_critical"`
-	NodeDownCount    int     `json:"node_down_count"`
-	PodFailureRate   float64 `json:"pod_failure_rate"`
+	CPUWarning       float64 `json:"cpu_warning" yaml:"cpuWarning"`
+	CPUCritical      float64 `json:"cpu_critical" yaml:"cpuCritical"`
+	MemoryWarning    float64 `json:"memory_warning" yaml:"memoryWarning"`
+	MemoryCritical   float64 `json:"memory_critical" yaml:"memoryCritical"`
+	StorageWarning   float64 `json:"storage_warning" yaml:"storageWarning"`
+	StorageCritical  float64 `json:"storage_critical" yaml:"storageCritical"`
+	NodeDownCount    int     `json:"node_down_count" yaml:"nodeDownCount"`
+	PodFailureRate   float64 `json:"pod_failure_rate" yaml:"podFailureRate"`
 }
 
 type MonitoringEvent struct {
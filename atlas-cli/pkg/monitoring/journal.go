@@ -0,0 +1,150 @@
+package monitoring
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+)
+
+// EventJournal is a durable record of monitoring events, one JSON object per line: each change
+// in a cluster's overall health and every alert that fires or resolves. It implements Channel,
+// so adding it to a Notifier records the alerts the notifier sends.
+type EventJournal struct {
+	mu   sync.Mutex
+	path string
+	// last is each cluster's most recently recorded status, seeded from the file so a restart
+	// doesn't record a transition for every cluster
+	last map[string]ClusterHealthStatus
+}
+
+// DefaultEventJournalPath returns where the monitoring daemon records events
+func DefaultEventJournalPath() (string, error) {
+	return store.Path("monitor", "events.jsonl")
+}
+
+// NewEventJournal opens the journal at path; a missing file starts empty
+func NewEventJournal(path string) (*EventJournal, error) {
+	j := &EventJournal{path: path, last: make(map[string]ClusterHealthStatus)}
+	events, err := j.Read(time.Time{}, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		if event.EventType != EventTypeStatusChange {
+			continue
+		}
+		if status, ok := event.Details["status"].(string); ok {
+			j.last[event.ClusterName] = ClusterHealthStatus(status)
+		}
+	}
+	return j, nil
+}
+
+// RecordHealth appends a status_change event when a health check finds the cluster in a
+// different overall status than the last one recorded. A failed check counts as unhealthy.
+func (j *EventJournal) RecordHealth(clusterName string, status *HealthStatus, checkErr error) error {
+	summary := Summarize(clusterName, status, checkErr)
+
+	j.mu.Lock()
+	previous, known := j.last[clusterName]
+	if known && previous == summary.Status {
+		j.mu.Unlock()
+		return nil
+	}
+	j.last[clusterName] = summary.Status
+	j.mu.Unlock()
+
+	severity := SeverityInfo
+	switch summary.Status {
+	case HealthStatusWarning:
+		severity = SeverityWarning
+	case HealthStatusUnhealthy:
+		severity = SeverityCritical
+	}
+	message := fmt.Sprintf("Cluster %s is %s", clusterName, summary.Status)
+	details := map[string]interface{}{"status": string(summary.Status)}
+	if known {
+		message = fmt.Sprintf("Cluster %s changed from %s to %s", clusterName, previous, summary.Status)
+		details["previous"] = string(previous)
+	}
+	if len(summary.Errors) > 0 {
+		details["errors"] = summary.Errors
+	}
+	return j.Append(MonitoringEvent{
+		ID:          alertID(clusterName, "health", "status_change"),
+		ClusterName: clusterName,
+		EventType:   EventTypeStatusChange,
+		Severity:    severity,
+		Message:     message,
+		Details:     details,
+		Timestamp:   time.Now(),
+	})
+}
+
+// Append writes event to the end of the journal
+func (j *EventJournal) Append(event MonitoringEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return store.AppendFile(j.path, append(data, '\n'))
+}
+
+// Read returns the events recorded at or after since for clusterName, or every cluster when it
+// is empty, oldest first. Lines that can't be decoded are skipped.
+func (j *EventJournal) Read(since time.Time, clusterName string) ([]MonitoringEvent, error) {
+	file, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event journal: %w", err)
+	}
+	defer file.Close()
+
+	var events []MonitoringEvent
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event MonitoringEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		if event.Timestamp.Before(since) || (clusterName != "" && event.ClusterName != clusterName) {
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read event journal: %w", err)
+	}
+	return events, nil
+}
+
+// Statuses returns each cluster's most recently recorded overall status
+func (j *EventJournal) Statuses() map[string]ClusterHealthStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	statuses := make(map[string]ClusterHealthStatus, len(j.last))
+	for cluster, status := range j.last {
+		statuses[cluster] = status
+	}
+	return statuses
+}
+
+func (j *EventJournal) Name() string {
+	return "journal"
+}
+
+func (j *EventJournal) Send(ctx context.Context, event MonitoringEvent) error {
+	return j.Append(event)
+}
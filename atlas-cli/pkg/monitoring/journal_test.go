@@ -0,0 +1,82 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEventJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monitor", "events.jsonl")
+	journal, err := NewEventJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	healthy := &HealthStatus{OverallStatus: HealthStatusHealthy}
+	for _, check := range []struct {
+		status *HealthStatus
+		err    error
+	}{
+		{healthy, nil},
+		{healthy, nil}, // unchanged, not recorded
+		{nil, errors.New("connection refused")},
+		{healthy, nil},
+	} {
+		if err := journal.RecordHealth("dev", check.status, check.err); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// alerts the notifier sends are journaled alongside the transitions
+	notifier, err := NewNotifier(&AlertConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier.AddChannel(journal, SeverityInfo)
+	firing := EvaluateHealth("dev", nil, errors.New("connection refused"), nil)
+	if err := notifier.Update(context.Background(), "dev", "health", firing); err != nil {
+		t.Fatal(err)
+	}
+	if err := notifier.Update(context.Background(), "dev", "health", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := journal.Read(time.Time{}, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 5 {
+		t.Fatalf("journal has %d events, want 3 transitions and an alert firing and resolving: %+v", len(events), events)
+	}
+	if events[0].Message != "Cluster dev is healthy" || events[1].Details["previous"] != "healthy" || events[1].Severity != SeverityCritical {
+		t.Errorf("transitions = %+v, %+v", events[0], events[1])
+	}
+	if events[3].EventType != EventTypeAlert || events[3].Resolved || !events[4].Resolved {
+		t.Errorf("alert events = %+v, %+v, want firing then resolved", events[3], events[4])
+	}
+	if others, _ := journal.Read(time.Time{}, "prod"); len(others) != 0 {
+		t.Errorf("Read(prod) = %+v, want none", others)
+	}
+
+	// a reopened journal remembers the last status, so an unchanged cluster isn't recorded again
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("truncated {\n")
+	f.Close()
+	reopened, err := NewEventJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Statuses()["dev"]; got != HealthStatusHealthy {
+		t.Errorf("reopened status of dev = %q, want healthy", got)
+	}
+	if err := reopened.RecordHealth("dev", healthy, nil); err != nil {
+		t.Fatal(err)
+	}
+	if events, _ := reopened.Read(time.Time{}, ""); len(events) != 5 {
+		t.Errorf("journal has %d events after an unchanged check, want 5", len(events))
+	}
+}
@@ -0,0 +1,548 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+// listPageSize bounds each list request, so large clusters are read in pages rather than one response
+const listPageSize = 500
+
+// kubeClients talks to one cluster's API server and its metrics-server. dynamic reads custom
+// resources such as Gateway API routes. host is the API server's URL.
+type kubeClients struct {
+	core    kubernetes.Interface
+	metrics metricsclient.Interface
+	dynamic dynamic.Interface
+	host    string
+}
+
+// kubeClientCache builds clients for kubeconfig contexts once and reuses them across checks
+type kubeClientCache struct {
+	mu      sync.Mutex
+	clients map[string]*kubeClients
+}
+
+// get returns clients for kubeContext, resolved from $KUBECONFIG or ~/.kube/config
+func (c *kubeClientCache) get(kubeContext string) (*kubeClients, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if clients, ok := c.clients[kubeContext]; ok {
+		return clients, nil
+	}
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+	).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig context %s: %w", kubeContext, err)
+	}
+	config.UserAgent = "atlas-cli"
+	config.QPS = 20
+	config.Burst = 40
+
+	core, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for context %s: %w", kubeContext, err)
+	}
+	metrics, err := metricsclient.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metrics client for context %s: %w", kubeContext, err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client for context %s: %w", kubeContext, err)
+	}
+
+	clients := &kubeClients{core: core, metrics: metrics, dynamic: dynamicClient, host: config.Host}
+	if c.clients == nil {
+		c.clients = make(map[string]*kubeClients)
+	}
+	c.clients[kubeContext] = clients
+	return clients, nil
+}
+
+func checkControlPlane(ctx context.Context, client kubernetes.Interface) (*ControlPlaneHealth, error) {
+	components, err := client.CoreV1().ComponentStatuses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get component status: %w", err)
+	}
+
+	health := &ControlPlaneHealth{
+		APIServer:         ComponentStatus{Status: ComponentUnknown, LastCheck: time.Now()},
+		Scheduler:         ComponentStatus{Status: ComponentUnknown, LastCheck: time.Now()},
+		ControllerManager: ComponentStatus{Status: ComponentUnknown, LastCheck: time.Now()},
+		Etcd:              ComponentStatus{Status: ComponentUnknown, LastCheck: time.Now()},
+	}
+
+	for _, component := range components.Items {
+		status := ComponentUnknown
+		message := ""
+		for _, condition := range component.Conditions {
+			if condition.Type == corev1.ComponentHealthy {
+				if condition.Status == corev1.ConditionTrue {
+					status = ComponentHealthy
+				} else {
+					status = ComponentUnhealthy
+					message = condition.Message
+				}
+				break
+			}
+		}
+
+		componentStatus := ComponentStatus{
+			Status:    status,
+			Message:   message,
+			LastCheck: time.Now(),
+		}
+		switch component.Name {
+		case "scheduler":
+			health.Scheduler = componentStatus
+		case "controller-manager":
+			health.ControllerManager = componentStatus
+		case "etcd-0":
+			health.Etcd = componentStatus
+		}
+	}
+
+	// the list above was served by the API server
+	health.APIServer = ComponentStatus{
+		Status:    ComponentHealthy,
+		LastCheck: time.Now(),
+	}
+	return health, nil
+}
+
+// WindowsNodeStartupGrace is how long a new Windows node may stay not ready before it counts as
+// unhealthy. Windows nodes pull much larger images and take several minutes longer than Linux
+// nodes to become ready.
+const WindowsNodeStartupGrace = 15 * time.Minute
+
+func checkNodes(ctx context.Context, client kubernetes.Interface) ([]NodeHealth, error) {
+	nodeList, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+
+	var nodes []NodeHealth
+	for _, node := range nodeList.Items {
+		nodeHealth := NodeHealth{
+			Name:        node.Name,
+			Status:      NodeUnknown,
+			Ready:       false,
+			OS:          nodeOS(&node),
+			Version:     node.Status.NodeInfo.KubeletVersion,
+			LastChecked: time.Now(),
+			Resources: &NodeResources{
+				CPUCapacity:       quantityString(node.Status.Capacity, corev1.ResourceCPU),
+				MemoryCapacity:    quantityString(node.Status.Capacity, corev1.ResourceMemory),
+				CPUAllocatable:    quantityString(node.Status.Allocatable, corev1.ResourceCPU),
+				MemoryAllocatable: quantityString(node.Status.Allocatable, corev1.ResourceMemory),
+			},
+		}
+
+		for _, condition := range node.Status.Conditions {
+			nodeHealth.Conditions = append(nodeHealth.Conditions, NodeCondition{
+				Type:               string(condition.Type),
+				Status:             string(condition.Status),
+				LastTransitionTime: condition.LastTransitionTime.Time,
+				Reason:             condition.Reason,
+				Message:            condition.Message,
+			})
+
+			if condition.Type == corev1.NodeReady {
+				if condition.Status == corev1.ConditionTrue {
+					nodeHealth.Ready = true
+					nodeHealth.Status = NodeHealthy
+				} else {
+					nodeHealth.Status = NodeNotReady
+				}
+			}
+		}
+
+		if nodeHealth.Status == NodeNotReady && nodeHealth.OS == "windows" &&
+			time.Since(node.CreationTimestamp.Time) < WindowsNodeStartupGrace {
+			nodeHealth.Status = NodeStarting
+		}
+
+		nodes = append(nodes, nodeHealth)
+	}
+
+	return nodes, nil
+}
+
+// nodeOS returns the operating system the node's kubelet reports, falling back to its
+// kubernetes.io/os label before the kubelet has reported
+func nodeOS(node *corev1.Node) string {
+	if node.Status.NodeInfo.OperatingSystem != "" {
+		return node.Status.NodeInfo.OperatingSystem
+	}
+	return node.Labels[corev1.LabelOSStable]
+}
+
+// countUnhealthyNodes returns how many nodes are unhealthy and how many were judged at all;
+// starting nodes are neither
+func countUnhealthyNodes(nodes []NodeHealth) (unhealthy, judged int) {
+	for _, node := range nodes {
+		switch node.Status {
+		case NodeStarting:
+			continue
+		case NodeHealthy:
+		default:
+			unhealthy++
+		}
+		judged++
+	}
+	return unhealthy, judged
+}
+
+func checkPods(ctx context.Context, client kubernetes.Interface) (*PodHealth, error) {
+	podHealth := &PodHealth{
+		PodsByPhase: make(map[string]int),
+		Namespaces:  make(map[string]*NamespaceHealth),
+	}
+
+	opts := metav1.ListOptions{Limit: listPageSize}
+	for {
+		podList, err := client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pods: %w", err)
+		}
+		for i := range podList.Items {
+			addPod(podHealth, &podList.Items[i])
+		}
+		if podList.Continue == "" {
+			break
+		}
+		opts.Continue = podList.Continue
+	}
+
+	return podHealth, nil
+}
+
+// addPod counts pod into podHealth
+func addPod(podHealth *PodHealth, pod *corev1.Pod) {
+	phase := string(pod.Status.Phase)
+	podHealth.TotalPods++
+	podHealth.PodsByPhase[phase]++
+
+	switch pod.Status.Phase {
+	case corev1.PodRunning:
+		podHealth.RunningPods++
+	case corev1.PodPending:
+		podHealth.PendingPods++
+	case corev1.PodFailed:
+		podHealth.FailedPods++
+	case corev1.PodSucceeded:
+		podHealth.SucceededPods++
+	default:
+		podHealth.UnknownPods++
+	}
+
+	if podHealth.Namespaces[pod.Namespace] == nil {
+		podHealth.Namespaces[pod.Namespace] = &NamespaceHealth{
+			Name:   pod.Namespace,
+			Status: "healthy",
+		}
+	}
+	ns := podHealth.Namespaces[pod.Namespace]
+	ns.TotalPods++
+	if pod.Status.Phase == corev1.PodRunning {
+		ns.HealthyPods++
+	}
+
+	restarts := 0
+	if len(pod.Status.ContainerStatuses) > 0 {
+		restarts = int(pod.Status.ContainerStatuses[0].RestartCount)
+	}
+	if pod.Status.Phase == corev1.PodFailed || restarts > 5 {
+		podHealth.CriticalPods = append(podHealth.CriticalPods, CriticalPodInfo{
+			Name:         pod.Name,
+			Namespace:    pod.Namespace,
+			Phase:        phase,
+			RestartCount: restarts,
+			Message:      pod.Status.Message,
+		})
+	}
+}
+
+func checkServices(ctx context.Context, client kubernetes.Interface) (*ServiceHealth, error) {
+	serviceHealth := &ServiceHealth{
+		ServicesByType: make(map[string]int),
+	}
+
+	opts := metav1.ListOptions{Limit: listPageSize}
+	for {
+		serviceList, err := client.CoreV1().Services(metav1.NamespaceAll).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get services: %w", err)
+		}
+		for _, service := range serviceList.Items {
+			serviceHealth.TotalServices++
+			serviceHealth.HealthyServices++
+			serviceHealth.ServicesByType[string(service.Spec.Type)]++
+		}
+		if serviceList.Continue == "" {
+			break
+		}
+		opts.Continue = serviceList.Continue
+	}
+
+	return serviceHealth, nil
+}
+
+// checkWorkloads compares Deployments' available replicas, StatefulSets' ready replicas and
+// DaemonSets' ready and scheduled pods with what each wants
+func checkWorkloads(ctx context.Context, client kubernetes.Interface) (*WorkloadHealth, error) {
+	health := &WorkloadHealth{}
+
+	opts := metav1.ListOptions{Limit: listPageSize}
+	for {
+		deployments, err := client.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployments: %w", err)
+		}
+		for i := range deployments.Items {
+			addWorkload(health, &health.Deployments, deploymentHealth(&deployments.Items[i]))
+		}
+		if deployments.Continue == "" {
+			break
+		}
+		opts.Continue = deployments.Continue
+	}
+
+	opts = metav1.ListOptions{Limit: listPageSize}
+	for {
+		statefulSets, err := client.AppsV1().StatefulSets(metav1.NamespaceAll).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get statefulsets: %w", err)
+		}
+		for i := range statefulSets.Items {
+			addWorkload(health, &health.StatefulSets, statefulSetHealth(&statefulSets.Items[i]))
+		}
+		if statefulSets.Continue == "" {
+			break
+		}
+		opts.Continue = statefulSets.Continue
+	}
+
+	opts = metav1.ListOptions{Limit: listPageSize}
+	for {
+		daemonSets, err := client.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get daemonsets: %w", err)
+		}
+		for i := range daemonSets.Items {
+			addWorkload(health, &health.DaemonSets, daemonSetHealth(&daemonSets.Items[i]))
+		}
+		if daemonSets.Continue == "" {
+			break
+		}
+		opts.Continue = daemonSets.Continue
+	}
+
+	return health, nil
+}
+
+// addWorkload counts a workload, recording it as degraded when it has a message
+func addWorkload(health *WorkloadHealth, counts *WorkloadCounts, workload DegradedWorkload) {
+	counts.Total++
+	if workload.Message == "" {
+		counts.Healthy++
+		return
+	}
+	health.Degraded = append(health.Degraded, workload)
+}
+
+// deploymentHealth judges a Deployment by its available replicas, so pods that are ready but
+// haven't been up for minReadySeconds still count against it. A stalled rollout is reported
+// even when enough old replicas are still available.
+func deploymentHealth(deployment *appsv1.Deployment) DegradedWorkload {
+	workload := DegradedWorkload{
+		Kind:      "Deployment",
+		Namespace: deployment.Namespace,
+		Name:      deployment.Name,
+		Desired:   replicasOrDefault(deployment.Spec.Replicas),
+		Ready:     deployment.Status.AvailableReplicas,
+	}
+	if workload.Ready < workload.Desired {
+		workload.Message = fmt.Sprintf("%d/%d replicas available", workload.Ready, workload.Desired)
+	}
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == "ProgressDeadlineExceeded" {
+			if workload.Message != "" {
+				workload.Message += ", "
+			}
+			workload.Message += "rollout stalled: " + condition.Message
+		}
+	}
+	return workload
+}
+
+func statefulSetHealth(statefulSet *appsv1.StatefulSet) DegradedWorkload {
+	workload := DegradedWorkload{
+		Kind:      "StatefulSet",
+		Namespace: statefulSet.Namespace,
+		Name:      statefulSet.Name,
+		Desired:   replicasOrDefault(statefulSet.Spec.Replicas),
+		Ready:     statefulSet.Status.ReadyReplicas,
+	}
+	if workload.Ready < workload.Desired {
+		workload.Message = fmt.Sprintf("%d/%d replicas ready", workload.Ready, workload.Desired)
+	}
+	return workload
+}
+
+// daemonSetHealth judges a DaemonSet by whether its pod is scheduled and ready on every node it
+// should run on, and on no node it shouldn't
+func daemonSetHealth(daemonSet *appsv1.DaemonSet) DegradedWorkload {
+	status := daemonSet.Status
+	workload := DegradedWorkload{
+		Kind:      "DaemonSet",
+		Namespace: daemonSet.Namespace,
+		Name:      daemonSet.Name,
+		Desired:   status.DesiredNumberScheduled,
+		Ready:     status.NumberReady,
+	}
+	var problems []string
+	if status.NumberReady < status.DesiredNumberScheduled {
+		problems = append(problems, fmt.Sprintf("%d/%d pods ready", status.NumberReady, status.DesiredNumberScheduled))
+	}
+	if unscheduled := status.DesiredNumberScheduled - status.CurrentNumberScheduled; unscheduled > 0 {
+		problems = append(problems, fmt.Sprintf("%d not scheduled", unscheduled))
+	}
+	if status.NumberMisscheduled > 0 {
+		problems = append(problems, fmt.Sprintf("%d misscheduled", status.NumberMisscheduled))
+	}
+	workload.Message = strings.Join(problems, ", ")
+	return workload
+}
+
+// replicasOrDefault returns the replicas a workload asks for; unset means one
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// getNodeMetrics reads node usage from metrics-server. Percentages are of allocatable
+// resources, as kubectl top reports them.
+func getNodeMetrics(ctx context.Context, clients *kubeClients) ([]NodeMetrics, error) {
+	usage, err := clients.metrics.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node metrics: %w", err)
+	}
+	nodeList, err := clients.core.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+	allocatable := make(map[string]corev1.ResourceList)
+	for _, node := range nodeList.Items {
+		allocatable[node.Name] = node.Status.Allocatable
+	}
+
+	var metrics []NodeMetrics
+	for _, node := range usage.Items {
+		cpu := node.Usage[corev1.ResourceCPU]
+		memory := node.Usage[corev1.ResourceMemory]
+		allocatableCPU := allocatable[node.Name][corev1.ResourceCPU]
+		allocatableMemory := allocatable[node.Name][corev1.ResourceMemory]
+
+		metrics = append(metrics, NodeMetrics{
+			NodeName: node.Name,
+			CPUUsage: ResourceValue{
+				Value: formatCPU(cpu),
+				Usage: percentOf(cpu.MilliValue(), allocatableCPU.MilliValue()),
+			},
+			MemoryUsage: ResourceValue{
+				Value: formatMemory(memory),
+				Usage: percentOf(memory.Value(), allocatableMemory.Value()),
+			},
+			Timestamp: node.Timestamp.Time,
+		})
+	}
+
+	return metrics, nil
+}
+
+// getPodMetrics reads pod usage from metrics-server, summed over each pod's containers
+func getPodMetrics(ctx context.Context, clients *kubeClients) ([]PodMetrics, error) {
+	var metrics []PodMetrics
+	opts := metav1.ListOptions{Limit: listPageSize}
+	for {
+		usage, err := clients.metrics.MetricsV1beta1().PodMetricses(metav1.NamespaceAll).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod metrics: %w", err)
+		}
+		for _, pod := range usage.Items {
+			var cpu, memory resource.Quantity
+			containers := make(map[string]ContainerMetrics)
+			for _, container := range pod.Containers {
+				containerCPU := container.Usage[corev1.ResourceCPU]
+				containerMemory := container.Usage[corev1.ResourceMemory]
+				cpu.Add(containerCPU)
+				memory.Add(containerMemory)
+				containers[container.Name] = ContainerMetrics{
+					CPUUsage:    ResourceValue{Value: formatCPU(containerCPU)},
+					MemoryUsage: ResourceValue{Value: formatMemory(containerMemory)},
+				}
+			}
+
+			metrics = append(metrics, PodMetrics{
+				PodName:     pod.Name,
+				Namespace:   pod.Namespace,
+				CPUUsage:    ResourceValue{Value: formatCPU(cpu)},
+				MemoryUsage: ResourceValue{Value: formatMemory(memory)},
+				Containers:  containers,
+				Timestamp:   pod.Timestamp.Time,
+			})
+		}
+		if usage.Continue == "" {
+			break
+		}
+		opts.Continue = usage.Continue
+	}
+
+	return metrics, nil
+}
+
+func quantityString(resources corev1.ResourceList, name corev1.ResourceName) string {
+	quantity, ok := resources[name]
+	if !ok {
+		return ""
+	}
+	return quantity.String()
+}
+
+// formatCPU renders CPU in millicores, e.g. "250m"
+func formatCPU(q resource.Quantity) string {
+	return fmt.Sprintf("%dm", q.MilliValue())
+}
+
+// formatMemory renders memory in mebibytes, e.g. "512Mi"
+func formatMemory(q resource.Quantity) string {
+	return fmt.Sprintf("%dMi", q.Value()/(1024*1024))
+}
+
+func percentOf(used, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(used) / float64(total) * 100
+}
@@ -0,0 +1,217 @@
+package monitoring
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+func testNode(name string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+			NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: "v1.32.0"},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+		},
+	}
+}
+
+func testPod(namespace, name string, phase corev1.PodPhase, restarts int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Status: corev1.PodStatus{
+			Phase:             phase,
+			ContainerStatuses: []corev1.ContainerStatus{{RestartCount: restarts}},
+		},
+	}
+}
+
+func TestKubeClientChecks(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		testNode("node-1", corev1.ConditionTrue),
+		testNode("node-2", corev1.ConditionFalse),
+		testPod("default", "web", corev1.PodRunning, 0),
+		testPod("default", "flaky", corev1.PodRunning, 9),
+		testPod("jobs", "migrate", corev1.PodFailed, 0),
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+		},
+	)
+
+	nodes, err := checkNodes(ctx, client)
+	if err != nil {
+		t.Fatalf("checkNodes() error = %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("checkNodes() returned %d nodes, want 2", len(nodes))
+	}
+	for _, node := range nodes {
+		want := NodeHealthy
+		if node.Name == "node-2" {
+			want = NodeNotReady
+		}
+		if node.Status != want || node.Version != "v1.32.0" || node.Resources.MemoryAllocatable != "4Gi" {
+			t.Errorf("checkNodes() node %s = %+v, want status %s", node.Name, node, want)
+		}
+	}
+
+	pods, err := checkPods(ctx, client)
+	if err != nil {
+		t.Fatalf("checkPods() error = %v", err)
+	}
+	if pods.TotalPods != 3 || pods.RunningPods != 2 || pods.FailedPods != 1 {
+		t.Errorf("checkPods() = %d total, %d running, %d failed, want 3, 2, 1", pods.TotalPods, pods.RunningPods, pods.FailedPods)
+	}
+	if len(pods.CriticalPods) != 2 {
+		t.Errorf("checkPods() critical pods = %+v, want the failed and the restarting pod", pods.CriticalPods)
+	}
+	if ns := pods.Namespaces["default"]; ns == nil || ns.TotalPods != 2 || ns.HealthyPods != 2 {
+		t.Errorf("checkPods() default namespace = %+v, want 2 healthy of 2", ns)
+	}
+
+	services, err := checkServices(ctx, client)
+	if err != nil {
+		t.Fatalf("checkServices() error = %v", err)
+	}
+	if services.TotalServices != 1 || services.ServicesByType["ClusterIP"] != 1 {
+		t.Errorf("checkServices() = %+v, want one ClusterIP service", services)
+	}
+}
+
+func TestCheckNodes_Windows(t *testing.T) {
+	windowsNode := func(name string, age time.Duration) *corev1.Node {
+		node := testNode(name, corev1.ConditionFalse)
+		node.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+		node.Status.NodeInfo.OperatingSystem = "windows"
+		return node
+	}
+	linux := testNode("linux-1", corev1.ConditionTrue)
+	linux.Labels = map[string]string{corev1.LabelOSStable: "linux"}
+	client := fake.NewSimpleClientset(linux, windowsNode("win-new", time.Minute), windowsNode("win-old", time.Hour))
+
+	nodes, err := checkNodes(context.Background(), client)
+	if err != nil {
+		t.Fatalf("checkNodes() error = %v", err)
+	}
+	want := map[string]NodeHealthStatus{"linux-1": NodeHealthy, "win-new": NodeStarting, "win-old": NodeNotReady}
+	for _, node := range nodes {
+		if node.Status != want[node.Name] {
+			t.Errorf("checkNodes() node %s status = %s, want %s", node.Name, node.Status, want[node.Name])
+		}
+		if wantOS := map[bool]string{true: "windows", false: "linux"}[node.Name != "linux-1"]; node.OS != wantOS {
+			t.Errorf("checkNodes() node %s OS = %q, want %q", node.Name, node.OS, wantOS)
+		}
+	}
+
+	if unhealthy, judged := countUnhealthyNodes(nodes); unhealthy != 1 || judged != 2 {
+		t.Errorf("countUnhealthyNodes() = %d, %d, want 1 unhealthy of 2 judged", unhealthy, judged)
+	}
+	status := &HealthStatus{OverallStatus: HealthStatusWarning, Nodes: nodes}
+	if got := EvaluateHealth("dev", status, nil, nil); len(got) != 1 || !strings.Contains(got[0].Message, "1 of 3 nodes") || strings.Contains(got[0].Message, "win-new") {
+		t.Errorf("EvaluateHealth() = %+v, want only win-old reported not ready", got)
+	}
+}
+
+func TestCheckWorkloads(t *testing.T) {
+	replicas := func(n int32) *int32 { return &n }
+	meta := func(name string) metav1.ObjectMeta { return metav1.ObjectMeta{Namespace: "default", Name: name} }
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: meta("web"), Spec: appsv1.DeploymentSpec{Replicas: replicas(3)},
+			Status: appsv1.DeploymentStatus{AvailableReplicas: 3}},
+		&appsv1.Deployment{ObjectMeta: meta("api"), Spec: appsv1.DeploymentSpec{Replicas: replicas(3)},
+			Status: appsv1.DeploymentStatus{AvailableReplicas: 1}},
+		&appsv1.Deployment{ObjectMeta: meta("idle"), Spec: appsv1.DeploymentSpec{Replicas: replicas(0)}},
+		&appsv1.Deployment{ObjectMeta: meta("stuck"), Status: appsv1.DeploymentStatus{
+			AvailableReplicas: 1,
+			Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse,
+				Reason: "ProgressDeadlineExceeded", Message: `ReplicaSet "stuck-7d9f" has timed out progressing.`}},
+		}},
+		&appsv1.StatefulSet{ObjectMeta: meta("db"), Spec: appsv1.StatefulSetSpec{Replicas: replicas(3)},
+			Status: appsv1.StatefulSetStatus{ReadyReplicas: 2}},
+		&appsv1.DaemonSet{ObjectMeta: meta("agent"), Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: 3, CurrentNumberScheduled: 2, NumberReady: 2, NumberMisscheduled: 1}},
+		&appsv1.DaemonSet{ObjectMeta: meta("proxy"), Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: 3, CurrentNumberScheduled: 3, NumberReady: 3}},
+	)
+
+	health, err := checkWorkloads(context.Background(), client)
+	if err != nil {
+		t.Fatalf("checkWorkloads() error = %v", err)
+	}
+	if health.Deployments != (WorkloadCounts{Total: 4, Healthy: 2}) {
+		t.Errorf("checkWorkloads() deployments = %+v, want 2 of 4 healthy", health.Deployments)
+	}
+	if health.StatefulSets != (WorkloadCounts{Total: 1}) || health.DaemonSets != (WorkloadCounts{Total: 2, Healthy: 1}) {
+		t.Errorf("checkWorkloads() statefulsets = %+v, daemonsets = %+v", health.StatefulSets, health.DaemonSets)
+	}
+
+	want := map[string]string{
+		"api":   "1/3 replicas available",
+		"stuck": `rollout stalled: ReplicaSet "stuck-7d9f" has timed out progressing.`,
+		"db":    "2/3 replicas ready",
+		"agent": "2/3 pods ready, 1 not scheduled, 1 misscheduled",
+	}
+	if len(health.Degraded) != len(want) {
+		t.Fatalf("checkWorkloads() degraded = %+v, want %d workloads", health.Degraded, len(want))
+	}
+	for _, workload := range health.Degraded {
+		if workload.Message != want[workload.Name] {
+			t.Errorf("checkWorkloads() %s %s message = %q, want %q", workload.Kind, workload.Name, workload.Message, want[workload.Name])
+		}
+	}
+
+	status := &HealthStatus{Workloads: health}
+	if got := (&MinikubeMonitor{}).calculateOverallHealth(status); got != HealthStatusWarning {
+		t.Errorf("calculateOverallHealth() = %s, want warning with degraded workloads", got)
+	}
+}
+
+func TestGetNodeMetrics(t *testing.T) {
+	metrics := metricsfake.NewSimpleClientset()
+	// the metrics API serves NodeMetrics as "nodes", which the tracker can't guess from the kind
+	nodesResource := metricsv1beta1.SchemeGroupVersion.WithResource("nodes")
+	err := metrics.Tracker().Create(nodesResource, &metricsv1beta1.NodeMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Usage: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clients := &kubeClients{
+		core:    fake.NewSimpleClientset(testNode("node-1", corev1.ConditionTrue)),
+		metrics: metrics,
+	}
+
+	nodeMetrics, err := getNodeMetrics(context.Background(), clients)
+	if err != nil {
+		t.Fatalf("getNodeMetrics() error = %v", err)
+	}
+	if len(nodeMetrics) != 1 {
+		t.Fatalf("getNodeMetrics() returned %d nodes, want 1", len(nodeMetrics))
+	}
+	got := nodeMetrics[0]
+	if got.CPUUsage.Value != "500m" || got.CPUUsage.Usage != 25 {
+		t.Errorf("CPU usage = %+v, want 500m at 25%%", got.CPUUsage)
+	}
+	if got.MemoryUsage.Value != "1024Mi" || got.MemoryUsage.Usage != 25 {
+		t.Errorf("memory usage = %+v, want 1024Mi at 25%%", got.MemoryUsage)
+	}
+}
@@ -4,14 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
-	"strconv"
 	"strings"
 	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
 )
 
 type MinikubeMonitor struct {
 	activeMonitoring map[string]context.CancelFunc
+	clients          kubeClientCache
 }
 
 func NewMinikubeMonitor() *MinikubeMonitor {
@@ -70,6 +71,28 @@ func (m *MinikubeMonitor) CheckClusterHealth(ctx context.Context, clusterName st
 		status.Services = serviceHealth
 	}
 	
+	workloadHealth, err := m.checkWorkloads(ctx, clusterName)
+	if err != nil {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("Workload check failed: %v", err))
+	} else {
+		status.Workloads = workloadHealth
+	}
+	
+	certificate, err := m.checkAPIServerCertificate(ctx, clusterName)
+	if err != nil {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("API server certificate check failed: %v", err))
+	} else {
+		status.Certificates = append(status.Certificates, *certificate)
+	}
+	
+	kubeadmCertificates, err := m.checkKubeadmCertificates(ctx, clusterName)
+	if err != nil {
+		status.Warnings = append(status.Warnings, fmt.Sprintf("Kubeadm certificate check failed: %v", err))
+	} else {
+		status.Certificates = append(status.Certificates, kubeadmCertificates...)
+	}
+	FlagExpiringCertificates(status, CertificateWarningFrom(ctx), time.Now())
+	
 	status.OverallStatus = m.calculateOverallHealth(status)
 	status.CheckDuration = time.Since(startTime)
 	
@@ -145,13 +168,18 @@ func (m *MinikubeMonitor) monitorCluster(ctx context.Context, clusterName string
 		case <-ctx.Done():
 			return
 		case <-healthTicker.C:
-			_, err := m.CheckClusterHealth(ctx, clusterName)
-			if err != nil && config.EnableAlerts {
+			status, err := m.CheckClusterHealth(ctx, clusterName)
+			config.recordHealth(clusterName, status, err)
+			if config.EnableAlerts && config.Notifier != nil {
+				config.notify(ctx, clusterName, "health", EvaluateHealth(clusterName, status, err, config.AlertThresholds))
+			} else if err != nil && config.EnableAlerts {
 				fmt.Printf("Health check failed for cluster %s: %v\n", clusterName, err)
 			}
 		case <-metricsTicker.C:
-			_, err := m.GetClusterMetrics(ctx, clusterName)
-			if err != nil && config.EnableAlerts {
+			metrics, err := m.GetClusterMetrics(ctx, clusterName)
+			if config.EnableAlerts && config.Notifier != nil && err == nil {
+				config.notify(ctx, clusterName, "metrics", EvaluateMetrics(clusterName, metrics, config.AlertThresholds))
+			} else if err != nil && config.EnableAlerts {
 				fmt.Printf("Metrics collection failed for cluster %s: %v\n", clusterName, err)
 			}
 		}
@@ -159,7 +187,7 @@ func (m *MinikubeMonitor) monitorCluster(ctx context.Context, clusterName string
 }
 
 func (m *MinikubeMonitor) isMinikubeRunning(ctx context.Context, clusterName string) bool {
-	cmd := exec.CommandContext(ctx, "minikube", "status", "-p", clusterName, "-o", "json")
+	cmd := subprocess.CommandContext(ctx, "minikube", "status", "-p", clusterName, "-o", "json")
 	output, err := cmd.Output()
 	if err != nil {
 		return false
@@ -179,350 +207,78 @@ func (m *MinikubeMonitor) isMinikubeRunning(ctx context.Context, clusterName str
 	return false
 }
 
-func (m *MinikubeMonitor) checkControlPlane(ctx context.Context, clusterName string) (*ControlPlaneHealth, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "get", "componentstatuses", "-o", "json", "--context", clusterName)
-	output, err := cmd.Output()
+func (m *MinikubeMonitor) checkControlPlane(ctx context.Context, kubeContext string) (*ControlPlaneHealth, error) {
+	clients, err := m.clients.get(kubeContext)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get component status: %w", err)
-	}
-	
-	var components struct {
-		Items []struct {
-			Metadata struct {
-				Name string `json:"name"`
-			} `json:"metadata"`
-			Conditions []struct {
-				Type   string `json:"type"`
-				Status string `json:"status"`
-				Message string `json:"message,omitempty"`
-			} `json:"conditions"`
-		} `json:"items"`
-	}
-	
-	if err := json.Unmarshal(output, &components); err != nil {
-		return nil, fmt.Errorf("failed to parse component status: %w", err)
-	}
-	
-	health := &ControlPlaneHealth{
-		APIServer:         ComponentStatus{Status: ComponentUnknown, LastCheck: time.Now()},
-		Scheduler:         ComponentStatus{Status: ComponentUnknown, LastCheck: time.Now()},
-		ControllerManager: ComponentStatus{Status: ComponentUnknown, LastCheck: time.Now()},
-		Etcd:              ComponentStatus{Status: ComponentUnknown, LastCheck: time.Now()},
-	}
-	
-	for _, component := range components.Items {
-		name := component.Metadata.Name
-		status := ComponentUnknown
-		message := ""
-		
-		for _, condition := range component.Conditions {
-			if condition.Type == "Healthy" {
-				if condition.Status == "True" {
-					status = ComponentHealthy
-				} else {
-					status = ComponentUnhealthy
-					message = condition.Message
-				}
-				break
-			}
-		}
-		
-		componentStatus := ComponentStatus{
-			Status:    status,
-			Message:   message,
-			LastCheck: time.Now(),
-		}
-		
-		switch name {
-		case "scheduler":
-			health.Scheduler = componentStatus
-		case "controller-manager":
-			health.ControllerManager = componentStatus
-		case "etcd-0":
-			health.Etcd = componentStatus
-		}
+		return nil, err
 	}
-	
-	health.APIServer = ComponentStatus{
-		Status:    ComponentHealthy,
-		LastCheck: time.Now(),
-	}
-	
-	return health, nil
+	return checkControlPlane(ctx, clients.core)
 }
 
-func (m *MinikubeMonitor) checkNodes(ctx context.Context, clusterName string) ([]NodeHealth, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "get", "nodes", "-o", "json", "--context", clusterName)
-	output, err := cmd.Output()
+func (m *MinikubeMonitor) checkNodes(ctx context.Context, kubeContext string) ([]NodeHealth, error) {
+	clients, err := m.clients.get(kubeContext)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nodes: %w", err)
-	}
-	
-	var nodeList struct {
-		Items []struct {
-			Metadata struct {
-				Name string `json:"name"`
-			} `json:"metadata"`
-			Status struct {
-				Conditions []struct {
-					Type               string `json:"type"`
-					Status             string `json:"status"`
-					LastTransitionTime string `json:"lastTransitionTime"`
-					Reason             string `json:"reason,omitempty"`
-					Message            string `json:"message,omitempty"`
-				} `json:"conditions"`
-				NodeInfo struct {
-					KubeletVersion string `json:"kubeletVersion"`
-				} `json:"nodeInfo"`
-				Capacity struct {
-					CPU    string `json:"cpu"`
-					Memory string `json:"memory"`
-				} `json:"capacity"`
-				Allocatable struct {
-					CPU    string `json:"cpu"`
-					Memory string `json:"memory"`
-				} `json:"allocatable"`
-			} `json:"status"`
-		} `json:"items"`
-	}
-	
-	if err := json.Unmarshal(output, &nodeList); err != nil {
-		return nil, fmt.Errorf("failed to parse node list: %w", err)
-	}
-	
-	var nodes []NodeHealth
-	for _, node := range nodeList.Items {
-		nodeHealth := NodeHealth{
-			Name:        node.Metadata.Name,
-			Status:      NodeUnknown,
-			Ready:       false,
-			Version:     node.Status.NodeInfo.KubeletVersion,
-			LastChecked: time.Now(),
-			Resources: &NodeResources{
-				CPUCapacity:       node.Status.Capacity.CPU,
-				MemoryCapacity:    node.Status.Capacity.Memory,
-				CPUAllocatable:    node.Status.Allocatable.CPU,
-				MemoryAllocatable: node.Status.Allocatable.Memory,
-			},
-		}
-		
-		for _, condition := range node.Status.Conditions {
-			conditionTime, _ := time.Parse(time.RFC3339, condition.LastTransitionTime)
-			
-			nodeCondition := NodeCondition{
-				Type:               condition.Type,
-				Status:             condition.Status,
-				LastTransitionTime: conditionTime,
-				Reason:             condition.Reason,
-				Message:            condition.Message,
-			}
-			nodeHealth.Conditions = append(nodeHealth.Conditions, nodeCondition)
-			
-			if condition.Type == "Ready" {
-				if condition.Status == "True" {
-					nodeHealth.Ready = true
-					nodeHealth.Status = NodeHealthy
-				} else {
-					nodeHealth.Status = NodeNotReady
-				}
-			}
-		}
-		
-		nodes = append(nodes, nodeHealth)
+		return nil, err
 	}
-	
-	return nodes, nil
+	return checkNodes(ctx, clients.core)
 }
 
-func (m *MinikubeMonitor) checkPods(ctx context.Context, clusterName string) (*PodHealth, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "get", "pods", "--all-namespaces", "-o", "json", "--context", clusterName)
-	output, err := cmd.Output()
+func (m *MinikubeMonitor) checkPods(ctx context.Context, kubeContext string) (*PodHealth, error) {
+	clients, err := m.clients.get(kubeContext)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pods: %w", err)
-	}
-	
-	var podList struct {
-		Items []struct {
-			Metadata struct {
-				Name      string `json:"name"`
-				Namespace string `json:"namespace"`
-			} `json:"metadata"`
-			Status struct {
-				Phase             string `json:"phase"`
-				ContainerStatuses []struct {
-					RestartCount int `json:"restartCount"`
-				} `json:"containerStatuses,omitempty"`
-				Message string `json:"message,omitempty"`
-			} `json:"status"`
-		} `json:"items"`
-	}
-	
-	if err := json.Unmarshal(output, &podList); err != nil {
-		return nil, fmt.Errorf("failed to parse pod list: %w", err)
-	}
-	
-	podHealth := &PodHealth{
-		PodsByPhase: make(map[string]int),
-		Namespaces:  make(map[string]*NamespaceHealth),
-	}
-	
-	for _, pod := range podList.Items {
-		phase := pod.Status.Phase
-		podHealth.TotalPods++
-		podHealth.PodsByPhase[phase]++
-		
-		switch phase {
-		case "Running":
-			podHealth.RunningPods++
-		case "Pending":
-			podHealth.PendingPods++
-		case "Failed":
-			podHealth.FailedPods++
-		case "Succeeded":
-			podHealth.SucceededPods++
-		default:
-			podHealth.UnknownPods++
-		}
-		
-		if podHealth.Namespaces[pod.Metadata.Namespace] == nil {
-			podHealth.Namespaces[pod.Metadata.Namespace] = &NamespaceHealth{
-				Name:   pod.Metadata.Namespace,
-				Status: "healthy",
-			}
-		}
-		ns := podHealth.Namespaces[pod.Metadata.Namespace]
-		ns.TotalPods++
-		if phase == "Running" {
-			ns.HealthyPods++
-		}
-		
-		if phase == "Failed" || (len(pod.Status.ContainerStatuses) > 0 && pod.Status.ContainerStatuses[0].RestartCount > 5) {
-			criticalPod := CriticalPodInfo{
-				Name:      pod.Metadata.Name,
-				Namespace: pod.Metadata.Namespace,
-				Phase:     phase,
-				Message:   pod.Status.Message,
-			}
-			if len(pod.Status.ContainerStatuses) > 0 {
-				criticalPod.RestartCount = pod.Status.ContainerStatuses[0].RestartCount
-			}
-			podHealth.CriticalPods = append(podHealth.CriticalPods, criticalPod)
-		}
+		return nil, err
 	}
-	
-	return podHealth, nil
+	return checkPods(ctx, clients.core)
 }
 
-func (m *MinikubeMonitor) checkServices(ctx context.Context, clusterName string) (*ServiceHealth, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "get", "services", "--all-namespaces", "-o", "json", "--context", clusterName)
-	output, err := cmd.Output()
+func (m *MinikubeMonitor) checkServices(ctx context.Context, kubeContext string) (*ServiceHealth, error) {
+	clients, err := m.clients.get(kubeContext)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get services: %w", err)
-	}
-	
-	var serviceList struct {
-		Items []struct {
-			Spec struct {
-				Type string `json:"type"`
-			} `json:"spec"`
-		} `json:"items"`
-	}
-	
-	if err := json.Unmarshal(output, &serviceList); err != nil {
-		return nil, fmt.Errorf("failed to parse service list: %w", err)
+		return nil, err
 	}
-	
-	serviceHealth := &ServiceHealth{
-		ServicesByType: make(map[string]int),
-	}
-	
-	for _, service := range serviceList.Items {
-		serviceHealth.TotalServices++
-		serviceHealth.HealthyServices++
-		serviceHealth.ServicesByType[service.Spec.Type]++
+	return checkServices(ctx, clients.core)
+}
+
+func (m *MinikubeMonitor) checkWorkloads(ctx context.Context, kubeContext string) (*WorkloadHealth, error) {
+	clients, err := m.clients.get(kubeContext)
+	if err != nil {
+		return nil, err
 	}
-	
-	return serviceHealth, nil
+	return checkWorkloads(ctx, clients.core)
 }
 
-func (m *MinikubeMonitor) getNodeMetrics(ctx context.Context, clusterName string) ([]NodeMetrics, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "top", "nodes", "--context", clusterName, "--no-headers")
-	output, err := cmd.Output()
+func (m *MinikubeMonitor) checkAPIServerCertificate(ctx context.Context, kubeContext string) (*ClusterCertificate, error) {
+	clients, err := m.clients.get(kubeContext)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get node metrics: %w", err)
+		return nil, err
 	}
-	
-	var metrics []NodeMetrics
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	
-	for _, line := range lines {
-		fields := strings.Fields(line)
-		if len(fields) < 5 {
-			continue
-		}
-		
-		nodeName := fields[0]
-		cpuUsage := fields[1]
-		cpuPercent := strings.TrimSuffix(fields[2], "%")
-		memUsage := fields[3]
-		memPercent := strings.TrimSuffix(fields[4], "%")
-		
-		cpuPercentFloat, _ := strconv.ParseFloat(cpuPercent, 64)
-		memPercentFloat, _ := strconv.ParseFloat(memPercent, 64)
-		
-		metrics = append(metrics, NodeMetrics{
-			NodeName: nodeName,
-			CPUUsage: ResourceValue{
-				Value: cpuUsage,
-				Usage: cpuPercentFloat,
-			},
-			MemoryUsage: ResourceValue{
-				Value: memUsage,
-				Usage: memPercentFloat,
-			},
-			Timestamp: time.Now(),
-		})
+	return apiServerCertificate(ctx, clients.host)
+}
+
+// checkKubeadmCertificates reads the certificates kubeadm issued from the control plane node
+func (m *MinikubeMonitor) checkKubeadmCertificates(ctx context.Context, clusterName string) ([]ClusterCertificate, error) {
+	args := append([]string{"ssh", "-p", clusterName, "--", "sudo"}, listCertificatesCommand(minikubeCertDir)...)
+	output, err := subprocess.CommandContext(ctx, "minikube", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list certificates in %s: %w", minikubeCertDir, err)
 	}
-	
-	return metrics, nil
+	return parseCertificateListing(output, minikubeCertDir), nil
 }
 
-func (m *MinikubeMonitor) getPodMetrics(ctx context.Context, clusterName string) ([]PodMetrics, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "top", "pods", "--all-namespaces", "--context", clusterName, "--no-headers")
-	output, err := cmd.Output()
+func (m *MinikubeMonitor) getNodeMetrics(ctx context.Context, kubeContext string) ([]NodeMetrics, error) {
+	clients, err := m.clients.get(kubeContext)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pod metrics: %w", err)
+		return nil, err
 	}
-	
-	var metrics []PodMetrics
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	
-	for _, line := range lines {
-		fields := strings.Fields(line)
-		if len(fields) < 4 {
-			continue
-		}
-		
-		namespace := fields[0]
-		podName := fields[1]
-		cpuUsage := fields[2]
-		memUsage := fields[3]
-		
-		metrics = append(metrics, PodMetrics{
-			PodName:   podName,
-			Namespace: namespace,
-			CPUUsage: ResourceValue{
-				Value: cpuUsage,
-			},
-			MemoryUsage: ResourceValue{
-				Value: memUsage,
-			},
-			Containers: make(map[string]ContainerMetrics),
-			Timestamp:  time.Now(),
-		})
+	return getNodeMetrics(ctx, clients)
+}
+
+func (m *MinikubeMonitor) getPodMetrics(ctx context.Context, kubeContext string) ([]PodMetrics, error) {
+	clients, err := m.clients.get(kubeContext)
+	if err != nil {
+		return nil, err
 	}
-	
-	return metrics, nil
+	return getPodMetrics(ctx, clients)
 }
 
 func (m *MinikubeMonitor) calculateResourceUsage(nodeMetrics []NodeMetrics, podMetrics []PodMetrics) (*ResourceUsage, error) {
@@ -569,15 +325,10 @@ func (m *MinikubeMonitor) calculateOverallHealth(status *HealthStatus) ClusterHe
 		return HealthStatusWarning
 	}
 	
-	unhealthyNodes := 0
-	for _, node := range status.Nodes {
-		if node.Status != NodeHealthy {
-			unhealthyNodes++
-		}
-	}
+	unhealthyNodes, judgedNodes := countUnhealthyNodes(status.Nodes)
 	
 	if unhealthyNodes > 0 {
-		if unhealthyNodes == len(status.Nodes) {
+		if unhealthyNodes == judgedNodes {
 			return HealthStatusUnhealthy
 		}
 		return HealthStatusWarning
@@ -592,5 +343,9 @@ func (m *MinikubeMonitor) calculateOverallHealth(status *HealthStatus) ClusterHe
 		}
 	}
 	
+	if status.Workloads != nil && len(status.Workloads.Degraded) > 0 {
+		return HealthStatusWarning
+	}
+	
 	return HealthStatusHealthy
 }
\ No newline at end of file
@@ -0,0 +1,365 @@
+package monitoring
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Route is a host and path a cluster exposes through an Ingress or a Gateway API HTTPRoute
+type Route struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Host      string `json:"host"`
+	Path      string `json:"path"`
+	// Backend is the service the route sends traffic to, as name:port
+	Backend string        `json:"backend"`
+	Health  BackendHealth `json:"backend_health"`
+	// TLS is the certificate served for Host, or nil when the route is plain HTTP
+	TLS *Certificate `json:"tls,omitempty"`
+}
+
+// BackendHealth counts the ready endpoints behind a route's backend service
+type BackendHealth struct {
+	Status         string `json:"status"`
+	ReadyEndpoints int    `json:"ready_endpoints"`
+	TotalEndpoints int    `json:"total_endpoints"`
+}
+
+// Backend health statuses
+const (
+	BackendHealthy   = "healthy"
+	BackendDegraded  = "degraded"
+	BackendUnhealthy = "unhealthy"
+	BackendMissing   = "missing"
+)
+
+// Certificate describes the TLS certificate in a kubernetes.io/tls secret
+type Certificate struct {
+	Secret   string    `json:"secret"`
+	Issuer   string    `json:"issuer,omitempty"`
+	Subject  string    `json:"subject,omitempty"`
+	DNSNames []string  `json:"dns_names,omitempty"`
+	NotAfter time.Time `json:"not_after,omitempty"`
+	// Error says why the certificate couldn't be read, e.g. a missing secret
+	Error string `json:"error,omitempty"`
+}
+
+// RouteLister is implemented by monitors that can list the routes a cluster exposes
+type RouteLister interface {
+	ListRoutes(ctx context.Context, clusterName string) ([]Route, error)
+}
+
+var _ RouteLister = (*MinikubeMonitor)(nil)
+var _ RouteLister = (*DockerMonitor)(nil)
+var _ RouteLister = (*AWSMonitor)(nil)
+
+var (
+	httpRouteResource = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"}
+	gatewayResource   = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"}
+)
+
+// routeLister resolves backends and certificates once per service and secret while listing
+type routeLister struct {
+	core         kubernetes.Interface
+	dynamic      dynamic.Interface
+	backends     map[string]BackendHealth
+	certificates map[string]*Certificate
+}
+
+// listRoutes returns every Ingress and HTTPRoute rule in the cluster, sorted by host and path.
+// Clusters without the Gateway API CRDs only report Ingresses.
+func listRoutes(ctx context.Context, clients *kubeClients) ([]Route, error) {
+	l := &routeLister{core: clients.core, dynamic: clients.dynamic, backends: make(map[string]BackendHealth), certificates: make(map[string]*Certificate)}
+
+	var routes []Route
+	opts := metav1.ListOptions{Limit: listPageSize}
+	for {
+		ingressList, err := l.core.NetworkingV1().Ingresses(metav1.NamespaceAll).List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ingresses: %w", err)
+		}
+		for i := range ingressList.Items {
+			routes = append(routes, l.ingressRoutes(ctx, &ingressList.Items[i])...)
+		}
+		if ingressList.Continue == "" {
+			break
+		}
+		opts.Continue = ingressList.Continue
+	}
+
+	if l.dynamic != nil {
+		httpRoutes, err := l.httpRoutes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, httpRoutes...)
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Host != routes[j].Host {
+			return routes[i].Host < routes[j].Host
+		}
+		return routes[i].Path < routes[j].Path
+	})
+	return routes, nil
+}
+
+func (l *routeLister) ingressRoutes(ctx context.Context, ingress *networkingv1.Ingress) []Route {
+	tlsSecrets := make(map[string]string)
+	for _, tls := range ingress.Spec.TLS {
+		for _, host := range tls.Hosts {
+			tlsSecrets[host] = tls.SecretName
+		}
+	}
+
+	route := func(host, path string, backend *networkingv1.IngressBackend) Route {
+		r := Route{Kind: "Ingress", Namespace: ingress.Namespace, Name: ingress.Name, Host: host, Path: path}
+		if backend != nil && backend.Service != nil {
+			port := backend.Service.Port.Name
+			if port == "" {
+				port = strconv.Itoa(int(backend.Service.Port.Number))
+			}
+			r.Backend = backend.Service.Name + ":" + port
+			r.Health = l.backendHealth(ctx, ingress.Namespace, backend.Service.Name)
+		} else {
+			r.Health = BackendHealth{Status: BackendMissing}
+		}
+		if secret, ok := tlsSecrets[host]; ok && secret != "" {
+			r.TLS = l.certificate(ctx, ingress.Namespace, secret)
+		}
+		return r
+	}
+
+	var routes []Route
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for i := range rule.HTTP.Paths {
+			path := rule.HTTP.Paths[i].Path
+			if path == "" {
+				path = "/"
+			}
+			routes = append(routes, route(rule.Host, path, &rule.HTTP.Paths[i].Backend))
+		}
+	}
+	if ingress.Spec.DefaultBackend != nil {
+		routes = append(routes, route("*", "/", ingress.Spec.DefaultBackend))
+	}
+	return routes
+}
+
+func (l *routeLister) httpRoutes(ctx context.Context) ([]Route, error) {
+	list, err := l.dynamic.Resource(httpRouteResource).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HTTPRoutes: %w", err)
+	}
+
+	var routes []Route
+	for _, item := range list.Items {
+		hostnames, _, _ := unstructured.NestedStringSlice(item.Object, "spec", "hostnames")
+		if len(hostnames) == 0 {
+			hostnames = []string{"*"}
+		}
+		parents, _, _ := unstructured.NestedSlice(item.Object, "spec", "parentRefs")
+		rules, _, _ := unstructured.NestedSlice(item.Object, "spec", "rules")
+
+		for _, host := range hostnames {
+			tls := l.gatewayCertificate(ctx, item.GetNamespace(), parents, host)
+			for _, rule := range rules {
+				rule, _ := rule.(map[string]any)
+				paths := matchPaths(rule)
+				backendRefs, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+				for _, path := range paths {
+					for _, ref := range backendRefs {
+						ref, _ := ref.(map[string]any)
+						name, _, _ := unstructured.NestedString(ref, "name")
+						namespace, _, _ := unstructured.NestedString(ref, "namespace")
+						if namespace == "" {
+							namespace = item.GetNamespace()
+						}
+						port, _, _ := unstructured.NestedInt64(ref, "port")
+						routes = append(routes, Route{
+							Kind:      "HTTPRoute",
+							Namespace: item.GetNamespace(),
+							Name:      item.GetName(),
+							Host:      host,
+							Path:      path,
+							Backend:   name + ":" + strconv.FormatInt(port, 10),
+							Health:    l.backendHealth(ctx, namespace, name),
+							TLS:       tls,
+						})
+					}
+				}
+			}
+		}
+	}
+	return routes, nil
+}
+
+// matchPaths returns the path values an HTTPRoute rule matches; rules without matches match "/"
+func matchPaths(rule map[string]any) []string {
+	matches, _, _ := unstructured.NestedSlice(rule, "matches")
+	var paths []string
+	for _, match := range matches {
+		match, _ := match.(map[string]any)
+		if path, found, _ := unstructured.NestedString(match, "path", "value"); found {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		paths = []string{"/"}
+	}
+	return paths
+}
+
+// gatewayCertificate finds the certificate the route's parent Gateways serve for host
+func (l *routeLister) gatewayCertificate(ctx context.Context, routeNamespace string, parents []any, host string) *Certificate {
+	for _, parent := range parents {
+		parent, _ := parent.(map[string]any)
+		name, _, _ := unstructured.NestedString(parent, "name")
+		namespace, _, _ := unstructured.NestedString(parent, "namespace")
+		if namespace == "" {
+			namespace = routeNamespace
+		}
+		section, _, _ := unstructured.NestedString(parent, "sectionName")
+
+		gateway, err := l.dynamic.Resource(gatewayResource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		listeners, _, _ := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+		for _, listener := range listeners {
+			listener, _ := listener.(map[string]any)
+			listenerName, _, _ := unstructured.NestedString(listener, "name")
+			hostname, _, _ := unstructured.NestedString(listener, "hostname")
+			if (section != "" && section != listenerName) || (hostname != "" && hostname != host) {
+				continue
+			}
+			refs, _, _ := unstructured.NestedSlice(listener, "tls", "certificateRefs")
+			if len(refs) == 0 {
+				continue
+			}
+			ref, _ := refs[0].(map[string]any)
+			secret, _, _ := unstructured.NestedString(ref, "name")
+			secretNamespace, _, _ := unstructured.NestedString(ref, "namespace")
+			if secretNamespace == "" {
+				secretNamespace = namespace
+			}
+			return l.certificate(ctx, secretNamespace, secret)
+		}
+	}
+	return nil
+}
+
+// backendHealth counts the ready endpoints of a service from its EndpointSlices
+func (l *routeLister) backendHealth(ctx context.Context, namespace, service string) BackendHealth {
+	key := namespace + "/" + service
+	if health, ok := l.backends[key]; ok {
+		return health
+	}
+
+	health := BackendHealth{Status: BackendMissing}
+	if _, err := l.core.CoreV1().Services(namespace).Get(ctx, service, metav1.GetOptions{}); err == nil {
+		slices, err := l.core.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: discoveryv1.LabelServiceName + "=" + service,
+		})
+		if err == nil {
+			for _, slice := range slices.Items {
+				for _, endpoint := range slice.Endpoints {
+					health.TotalEndpoints++
+					if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+						health.ReadyEndpoints++
+					}
+				}
+			}
+			switch {
+			case health.ReadyEndpoints == 0:
+				health.Status = BackendUnhealthy
+			case health.ReadyEndpoints < health.TotalEndpoints:
+				health.Status = BackendDegraded
+			default:
+				health.Status = BackendHealthy
+			}
+		}
+	}
+	l.backends[key] = health
+	return health
+}
+
+// certificate reads the leaf certificate from a TLS secret
+func (l *routeLister) certificate(ctx context.Context, namespace, name string) *Certificate {
+	key := namespace + "/" + name
+	if cert, ok := l.certificates[key]; ok {
+		return cert
+	}
+
+	cert := &Certificate{Secret: key}
+	l.certificates[key] = cert
+	secret, err := l.core.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		cert.Error = fmt.Sprintf("failed to get secret: %v", err)
+		return cert
+	}
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil {
+		cert.Error = "secret has no PEM certificate in " + corev1.TLSCertKey
+		return cert
+	}
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		cert.Error = fmt.Sprintf("failed to parse certificate: %v", err)
+		return cert
+	}
+	cert.Issuer = parsed.Issuer.String()
+	cert.Subject = parsed.Subject.String()
+	cert.DNSNames = parsed.DNSNames
+	cert.NotAfter = parsed.NotAfter
+	return cert
+}
+
+// ListRoutes lists the routes the minikube cluster exposes
+func (m *MinikubeMonitor) ListRoutes(ctx context.Context, clusterName string) ([]Route, error) {
+	return m.listRoutes(ctx, clusterName)
+}
+
+func (m *MinikubeMonitor) listRoutes(ctx context.Context, kubeContext string) ([]Route, error) {
+	clients, err := m.clients.get(kubeContext)
+	if err != nil {
+		return nil, err
+	}
+	return listRoutes(ctx, clients)
+}
+
+// ListRoutes lists the routes the cluster exposes
+func (k *DockerMonitor) ListRoutes(ctx context.Context, clusterName string) ([]Route, error) {
+	return k.kube.listRoutes(ctx, k.kubeContext(clusterName))
+}
+
+// ListRoutes lists the routes the EKS cluster exposes
+func (a *AWSMonitor) ListRoutes(ctx context.Context, clusterName string) ([]Route, error) {
+	clients, err := a.kubeClients(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return listRoutes(ctx, clients)
+}
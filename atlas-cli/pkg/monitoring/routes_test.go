@@ -0,0 +1,148 @@
+package monitoring
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testCertificatePEM(t *testing.T, host string, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: host},
+		Issuer:       pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func testEndpointSlice(namespace, service string, ready ...bool) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: service + "-abc", Labels: map[string]string{discoveryv1.LabelServiceName: service}},
+	}
+	for i := range ready {
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{Conditions: discoveryv1.EndpointConditions{Ready: &ready[i]}})
+	}
+	return slice
+}
+
+func TestListRoutes(t *testing.T) {
+	notAfter := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+	pathType := networkingv1.PathTypePrefix
+	core := fake.NewSimpleClientset(
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web"},
+			Spec: networkingv1.IngressSpec{
+				TLS: []networkingv1.IngressTLS{{Hosts: []string{"shop.example.com"}, SecretName: "shop-tls"}},
+				Rules: []networkingv1.IngressRule{{
+					Host: "shop.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{
+						{Path: "/", PathType: &pathType, Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Number: 80}}}},
+						{Path: "/api", PathType: &pathType, Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "api", Port: networkingv1.ServiceBackendPort{Name: "http"}}}},
+					}}},
+				}},
+			},
+		},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "admin"}},
+		testEndpointSlice("shop", "web", true, false),
+		testEndpointSlice("shop", "admin", true),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "shop-tls"},
+			Data:       map[string][]byte{corev1.TLSCertKey: testCertificatePEM(t, "shop.example.com", notAfter)},
+		},
+	)
+
+	ctx := context.Background()
+	dynamic := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{httpRouteResource: "HTTPRouteList", gatewayResource: "GatewayList"})
+	for resource, object := range map[schema.GroupVersionResource]map[string]any{
+		httpRouteResource: {
+			"apiVersion": "gateway.networking.k8s.io/v1", "kind": "HTTPRoute",
+			"metadata": map[string]any{"namespace": "shop", "name": "admin"},
+			"spec": map[string]any{
+				"hostnames":  []any{"admin.example.com"},
+				"parentRefs": []any{map[string]any{"name": "public"}},
+				"rules": []any{map[string]any{
+					"matches":     []any{map[string]any{"path": map[string]any{"type": "PathPrefix", "value": "/admin"}}},
+					"backendRefs": []any{map[string]any{"name": "admin", "port": int64(8080)}},
+				}},
+			},
+		},
+		gatewayResource: {
+			"apiVersion": "gateway.networking.k8s.io/v1", "kind": "Gateway",
+			"metadata": map[string]any{"namespace": "shop", "name": "public"},
+			"spec": map[string]any{"listeners": []any{map[string]any{
+				"name": "https", "hostname": "admin.example.com",
+				"tls": map[string]any{"certificateRefs": []any{map[string]any{"name": "admin-tls"}}},
+			}}},
+		},
+	} {
+		if _, err := dynamic.Resource(resource).Namespace("shop").Create(ctx, &unstructured.Unstructured{Object: object}, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	routes, err := listRoutes(ctx, &kubeClients{core: core, dynamic: dynamic})
+	if err != nil {
+		t.Fatalf("listRoutes() error = %v", err)
+	}
+	if len(routes) != 3 {
+		t.Fatalf("listRoutes() returned %d routes, want 3: %+v", len(routes), routes)
+	}
+
+	admin := routes[0]
+	if admin.Kind != "HTTPRoute" || admin.Path != "/admin" || admin.Backend != "admin:8080" || admin.Health.Status != BackendHealthy {
+		t.Errorf("HTTPRoute = %+v", admin)
+	}
+	if admin.TLS == nil || admin.TLS.Secret != "shop/admin-tls" || admin.TLS.Error == "" {
+		t.Errorf("HTTPRoute TLS = %+v, want an error for the missing secret", admin.TLS)
+	}
+
+	web, api := routes[1], routes[2]
+	if web.Path != "/" || web.Backend != "web:80" || web.Health != (BackendHealth{Status: BackendDegraded, ReadyEndpoints: 1, TotalEndpoints: 2}) {
+		t.Errorf("web route = %+v", web)
+	}
+	if web.TLS == nil || web.TLS.Issuer != "CN=shop.example.com" || !web.TLS.NotAfter.Equal(notAfter) || web.TLS.Error != "" {
+		t.Errorf("web TLS = %+v", web.TLS)
+	}
+	if api.Backend != "api:http" || api.Health.Status != BackendMissing {
+		t.Errorf("api route = %+v", api)
+	}
+}
+
+func TestListRoutes_WithoutGatewayAPI(t *testing.T) {
+	core := fake.NewSimpleClientset()
+	routes, err := listRoutes(context.Background(), &kubeClients{core: core})
+	if err != nil || len(routes) != 0 {
+		t.Errorf("listRoutes() = %v, %v; want no routes", routes, err)
+	}
+}
@@ -0,0 +1,124 @@
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HealthSummary is the credential-free view of a health check served to and pushed at external
+// uptime monitors
+type HealthSummary struct {
+	Cluster     string              `json:"cluster"`
+	Status      ClusterHealthStatus `json:"status"`
+	LastChecked time.Time           `json:"last_checked"`
+	Warnings    []string            `json:"warnings,omitempty"`
+	Errors      []string            `json:"errors,omitempty"`
+}
+
+// Summarize reduces a health check to what external monitors need. A failed check (err != nil)
+// is reported as unhealthy.
+func Summarize(clusterName string, status *HealthStatus, err error) HealthSummary {
+	if err != nil || status == nil {
+		summary := HealthSummary{Cluster: clusterName, Status: HealthStatusUnhealthy, LastChecked: time.Now()}
+		if err != nil {
+			summary.Errors = []string{fmt.Sprintf("health check failed: %v", err)}
+		}
+		return summary
+	}
+	return HealthSummary{
+		Cluster:     clusterName,
+		Status:      status.OverallStatus,
+		LastChecked: status.LastChecked,
+		Warnings:    status.Warnings,
+		Errors:      status.Errors,
+	}
+}
+
+// Up reports whether an uptime monitor should consider the cluster up. Warnings still count as up.
+func (s HealthSummary) Up() bool {
+	return s.Status == HealthStatusHealthy || s.Status == HealthStatusWarning
+}
+
+// HealthEndpoint serves the latest summary of each cluster at /health/<cluster>, answering 200
+// while the cluster is up and 503 otherwise
+type HealthEndpoint struct {
+	mu      sync.Mutex
+	latest  map[string]HealthSummary
+	maxAge  time.Duration
+	nowFunc func() time.Time
+}
+
+// NewHealthEndpoint creates an endpoint that reports a cluster as down once its latest summary
+// is older than maxAge, so a stalled checker doesn't look healthy forever
+func NewHealthEndpoint(maxAge time.Duration) *HealthEndpoint {
+	return &HealthEndpoint{latest: make(map[string]HealthSummary), maxAge: maxAge, nowFunc: time.Now}
+}
+
+// Update records the latest summary for its cluster
+func (e *HealthEndpoint) Update(summary HealthSummary) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.latest[summary.Cluster] = summary
+}
+
+// Handler returns the HTTP handler for /health/<cluster>
+func (e *HealthEndpoint) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cluster := strings.Trim(strings.TrimPrefix(r.URL.Path, "/health/"), "/")
+		e.mu.Lock()
+		summary, ok := e.latest[cluster]
+		e.mu.Unlock()
+		if !ok {
+			http.Error(w, fmt.Sprintf("cluster %s is not monitored", cluster), http.StatusNotFound)
+			return
+		}
+
+		code := http.StatusOK
+		if !summary.Up() {
+			code = http.StatusServiceUnavailable
+		}
+		if e.maxAge > 0 && e.nowFunc().Sub(summary.LastChecked) > e.maxAge {
+			summary.Status = HealthStatusUnknown
+			summary.Errors = append(summary.Errors, fmt.Sprintf("no health check for %s", e.nowFunc().Sub(summary.LastChecked).Round(time.Second)))
+			code = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(summary)
+	})
+}
+
+// SendHeartbeat pushes summary to a healthchecks.io-style ping URL: a POST to url while the
+// cluster is up, and to url/fail when it is down
+func SendHeartbeat(ctx context.Context, url string, summary HealthSummary) error {
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to marshal heartbeat: %w", err)
+	}
+	if !summary.Up() {
+		url = strings.TrimSuffix(url, "/") + "/fail"
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build heartbeat: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send heartbeat: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("heartbeat to %s returned %s", url, resp.Status)
+	}
+	return nil
+}
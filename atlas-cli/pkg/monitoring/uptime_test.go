@@ -0,0 +1,69 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealthEndpoint(t *testing.T) {
+	now := time.Now()
+	endpoint := NewHealthEndpoint(time.Minute)
+	endpoint.nowFunc = func() time.Time { return now }
+
+	endpoint.Update(HealthSummary{Cluster: "dev", Status: HealthStatusWarning, LastChecked: now})
+	endpoint.Update(Summarize("prod", nil, errors.New("connection refused")))
+	endpoint.Update(HealthSummary{Cluster: "stale", Status: HealthStatusHealthy, LastChecked: now.Add(-time.Hour)})
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/health/dev", http.StatusOK},
+		{"/health/prod", http.StatusServiceUnavailable},
+		{"/health/stale", http.StatusServiceUnavailable},
+		{"/health/missing", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		endpoint.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.want {
+			t.Errorf("GET %s = %d, want %d", tt.path, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestSendHeartbeat(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	if err := SendHeartbeat(ctx, server.URL+"/ping", HealthSummary{Cluster: "dev", Status: HealthStatusHealthy}); err != nil {
+		t.Fatalf("SendHeartbeat() healthy error = %v", err)
+	}
+	if err := SendHeartbeat(ctx, server.URL+"/ping/", HealthSummary{Cluster: "dev", Status: HealthStatusUnhealthy}); err != nil {
+		t.Fatalf("SendHeartbeat() unhealthy error = %v", err)
+	}
+	if err := SendHeartbeat(ctx, server.URL+"/broken", HealthSummary{Cluster: "dev", Status: HealthStatusHealthy}); err == nil {
+		t.Error("SendHeartbeat() should fail when the ping URL rejects it")
+	}
+
+	want := []string{"/ping", "/ping/fail", "/broken"}
+	if len(paths) != len(want) {
+		t.Fatalf("pinged %v, want %v", paths, want)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("ping %d went to %s, want %s", i, paths[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,296 @@
+// Package operations limits how many heavy operations (cluster creations, image loads) run at
+// once across every Atlas process on a machine. Each operation is recorded as a file in a shared
+// directory so queued and running work can be listed from any process.
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/cleanup"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+)
+
+// DefaultLimit is the number of heavy operations allowed to run at once when none is configured
+const DefaultLimit = 2
+
+// LimitEnvVar overrides the default concurrency limit
+const LimitEnvVar = "ATLAS_MAX_CONCURRENT_OPERATIONS"
+
+// lockName is the file admission decisions are serialized on
+const lockName = "admission"
+
+// State is the scheduling state of an operation
+type State string
+
+const (
+	StateQueued   State = "queued"
+	StateRunning  State = "running"
+	StateCanceled State = "canceled"
+)
+
+// ErrCanceled is returned by Acquire when the queued operation is canceled before it starts
+var ErrCanceled = errors.New("operation canceled")
+
+// Operation is a heavy operation holding or waiting for a slot
+type Operation struct {
+	ID        string     `json:"id"`
+	Type      string     `json:"type"`
+	Cluster   string     `json:"cluster"`
+	Provider  string     `json:"provider,omitempty"`
+	State     State      `json:"state"`
+	PID       int        `json:"pid"`
+	QueuedAt  time.Time  `json:"queued_at"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+}
+
+// Limiter is a file-backed semaphore shared by all processes using the same directory
+type Limiter struct {
+	dir          string
+	limit        int
+	pollInterval time.Duration
+}
+
+// NewLimiter creates a limiter that allows limit concurrent operations, tracked in dir. An empty
+// dir means DefaultDir, resolved when the limiter is first used.
+func NewLimiter(dir string, limit int) *Limiter {
+	if limit < 1 {
+		limit = 1
+	}
+	return &Limiter{dir: dir, limit: limit, pollInterval: time.Second}
+}
+
+// DefaultDir returns the directory shared by Atlas processes for operation tracking
+func DefaultDir() (string, error) {
+	return store.Path("operations")
+}
+
+// LimitFromEnv returns the limit set in ATLAS_MAX_CONCURRENT_OPERATIONS, or DefaultLimit
+func LimitFromEnv() int {
+	if value := os.Getenv(LimitEnvVar); value != "" {
+		if limit, err := strconv.Atoi(value); err == nil && limit > 0 {
+			return limit
+		}
+	}
+	return DefaultLimit
+}
+
+// Limit returns the number of operations allowed to run at once
+func (l *Limiter) Limit() int {
+	return l.limit
+}
+
+// Acquire queues op and blocks until a slot is free or ctx is done. Operations are admitted in
+// the order they were queued. The returned function releases the slot.
+func (l *Limiter) Acquire(ctx context.Context, op *Operation) (func(), error) {
+	dir, err := l.directory()
+	if err != nil {
+		return nil, err
+	}
+	if err := store.MkdirAll(dir); err != nil {
+		return nil, err
+	}
+
+	if op.ID == "" {
+		op.ID = fmt.Sprintf("%d-%d", time.Now().UnixNano(), os.Getpid())
+	}
+	op.PID = os.Getpid()
+	op.State = StateQueued
+	op.QueuedAt = time.Now()
+	if err := l.write(dir, op); err != nil {
+		return nil, err
+	}
+
+	path := l.path(dir, op.ID)
+	unregister := cleanup.RemoveOnExit(path)
+	release := func() {
+		unregister()
+		os.Remove(path)
+	}
+
+	reportedWait := false
+	for {
+		admitted, err := l.tryAdmit(dir, op)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		if admitted {
+			return release, nil
+		}
+
+		if !reportedWait {
+			progress.Report(ctx, progress.Event{Cluster: op.Cluster, Operation: op.Type, Phase: "queue", Status: progress.StatusInfo,
+				Message: fmt.Sprintf("Waiting for an operation slot (%d concurrent operations allowed)...", l.limit)})
+			reportedWait = true
+		}
+
+		select {
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		case <-time.After(l.pollInterval):
+		}
+	}
+}
+
+// tryAdmit marks op running if a slot is free and no earlier operation is still queued
+func (l *Limiter) tryAdmit(dir string, op *Operation) (bool, error) {
+	unlock, err := store.Lock(filepath.Join(dir, lockName))
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	ops, err := l.List()
+	if err != nil {
+		return false, err
+	}
+
+	running := 0
+	for _, other := range ops {
+		if other.ID == op.ID {
+			if other.State == StateCanceled {
+				return false, ErrCanceled
+			}
+			continue
+		}
+		if other.StartedAt != nil {
+			running++
+		} else if other.State == StateQueued && queuedBefore(other, op) {
+			return false, nil
+		}
+	}
+	if running >= l.limit {
+		return false, nil
+	}
+
+	now := time.Now()
+	op.State = StateRunning
+	op.StartedAt = &now
+	return true, l.write(dir, op)
+}
+
+// Cancel marks the operation canceled and signals the process running it to stop. The process
+// kills its provider subprocesses and releases its slot as it exits.
+func (l *Limiter) Cancel(id string) (*Operation, error) {
+	dir, err := l.directory()
+	if err != nil {
+		return nil, err
+	}
+	if err := store.MkdirAll(dir); err != nil {
+		return nil, err
+	}
+	unlock, err := store.Lock(filepath.Join(dir, lockName))
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	ops, err := l.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range ops {
+		if op.ID != id {
+			continue
+		}
+		if op.State == StateCanceled {
+			return op, nil
+		}
+		op.State = StateCanceled
+		if err := l.write(dir, op); err != nil {
+			return nil, err
+		}
+		if op.PID != os.Getpid() {
+			if err := signalTerminate(op.PID); err != nil {
+				return nil, fmt.Errorf("failed to signal process %d: %w", op.PID, err)
+			}
+		}
+		return op, nil
+	}
+	return nil, fmt.Errorf("operation %s not found", id)
+}
+
+// List returns the queued and running operations in queue order, pruning records left behind by
+// processes that no longer exist
+func (l *Limiter) List() ([]*Operation, error) {
+	dir, err := l.directory()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read operations directory: %w", err)
+	}
+
+	var ops []*Operation
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var op Operation
+		if err := json.Unmarshal(data, &op); err != nil {
+			continue
+		}
+		if !processAlive(op.PID) {
+			os.Remove(path)
+			continue
+		}
+		ops = append(ops, &op)
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		return queuedBefore(ops[i], ops[j])
+	})
+	return ops, nil
+}
+
+// directory returns the directory operations are tracked in
+func (l *Limiter) directory() (string, error) {
+	if l.dir != "" {
+		return l.dir, nil
+	}
+	return DefaultDir()
+}
+
+func (l *Limiter) path(dir, id string) string {
+	return filepath.Join(dir, id+".json")
+}
+
+// write records op. Callers hold the admission lock, or own op while it is queued.
+func (l *Limiter) write(dir string, op *Operation) error {
+	data, err := json.Marshal(op)
+	if err != nil {
+		return fmt.Errorf("failed to encode operation: %w", err)
+	}
+	if err := store.ReplaceFile(l.path(dir, op.ID), data); err != nil {
+		return fmt.Errorf("failed to record operation: %w", err)
+	}
+	return nil
+}
+
+func queuedBefore(a, b *Operation) bool {
+	if !a.QueuedAt.Equal(b.QueuedAt) {
+		return a.QueuedAt.Before(b.QueuedAt)
+	}
+	return a.ID < b.ID
+}
@@ -0,0 +1,208 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(t *testing.T, limit int) *Limiter {
+	l := NewLimiter(t.TempDir(), limit)
+	l.pollInterval = 10 * time.Millisecond
+	return l
+}
+
+func TestLimiter_Acquire(t *testing.T) {
+	l := newTestLimiter(t, 1)
+	ctx := context.Background()
+
+	release, err := l.Acquire(ctx, &Operation{Type: "create", Cluster: "first"})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	acquired := make(chan func(), 1)
+	go func() {
+		second, err := l.Acquire(ctx, &Operation{Type: "create", Cluster: "second"})
+		if err != nil {
+			t.Errorf("Acquire() error = %v", err)
+			return
+		}
+		acquired <- second
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		ops, err := l.List()
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(ops) == 2 {
+			if ops[0].State != StateRunning || ops[1].State != StateQueued {
+				t.Fatalf("List() states = %s, %s, want running, queued", ops[0].State, ops[1].State)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("second operation was never queued")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	select {
+	case <-acquired:
+		t.Fatal("second operation acquired a slot while the limit was reached")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+
+	select {
+	case second := <-acquired:
+		second()
+	case <-time.After(time.Second):
+		t.Fatal("second operation did not acquire the released slot")
+	}
+
+	ops, _ := l.List()
+	if len(ops) != 0 {
+		t.Errorf("List() after release returned %d operations, want 0", len(ops))
+	}
+}
+
+func TestLimiter_AcquireCancelled(t *testing.T) {
+	l := newTestLimiter(t, 1)
+
+	release, err := l.Acquire(context.Background(), &Operation{Type: "create", Cluster: "first"})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(ctx, &Operation{Type: "create", Cluster: "second"}); err == nil {
+		t.Fatal("Acquire() should fail when the context is done before a slot frees up")
+	}
+
+	ops, _ := l.List()
+	if len(ops) != 1 {
+		t.Errorf("List() returned %d operations, want the cancelled one removed", len(ops))
+	}
+}
+
+func TestLimiter_Cancel(t *testing.T) {
+	l := newTestLimiter(t, 1)
+
+	release, err := l.Acquire(context.Background(), &Operation{Type: "create", Cluster: "first"})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer release()
+
+	queued := &Operation{ID: "queued", Type: "create", Cluster: "second"}
+	result := make(chan error, 1)
+	go func() {
+		_, err := l.Acquire(context.Background(), queued)
+		result <- err
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := os.Stat(filepath.Join(l.dir, "queued.json")); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("queued operation was never recorded")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	op, err := l.Cancel("queued")
+	if err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if op.State != StateCanceled {
+		t.Errorf("Cancel() state = %s, want %s", op.State, StateCanceled)
+	}
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, ErrCanceled) {
+			t.Errorf("Acquire() error = %v, want ErrCanceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Acquire() did not return after cancel")
+	}
+
+	if _, err := l.Cancel("missing"); err == nil {
+		t.Error("Cancel() should fail for an unknown operation")
+	}
+}
+
+func TestLimiter_ListPrunesDeadProcesses(t *testing.T) {
+	l := newTestLimiter(t, 1)
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := json.Marshal(&Operation{ID: "stale", Type: "create", State: StateRunning, PID: -1})
+	path := filepath.Join(l.dir, "stale.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ops, err := l.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(ops) != 0 {
+		t.Errorf("List() returned %d operations, want stale record pruned", len(ops))
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("stale operation record should be removed")
+	}
+}
+
+func TestProcessAlive(t *testing.T) {
+	if !processAlive(os.Getpid()) {
+		t.Error("processAlive() = false for the running test process")
+	}
+
+	exited := exec.Command(os.Args[0], "-test.run=^$")
+	if err := exited.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if processAlive(exited.Process.Pid) {
+		t.Error("processAlive() = true for an exited process")
+	}
+	if processAlive(0) {
+		t.Error("processAlive() = true for PID 0")
+	}
+}
+
+func TestLimitFromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"", DefaultLimit},
+		{"4", 4},
+		{"0", DefaultLimit},
+		{"many", DefaultLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv(LimitEnvVar, tt.value)
+			if got := LimitFromEnv(); got != tt.want {
+				t.Errorf("LimitFromEnv() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
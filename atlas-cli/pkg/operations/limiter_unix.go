@@ -0,0 +1,30 @@
+//go:build !windows
+
+package operations
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// signalTerminate asks the process to stop, letting it release its slot and clean up
+func signalTerminate(pid int) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return process.Signal(syscall.SIGTERM)
+}
+
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,40 @@
+//go:build windows
+
+package operations
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// stillActive is the exit code GetExitCodeProcess reports for a process that hasn't exited
+const stillActive = 259
+
+// signalTerminate stops the process. Windows has no SIGTERM, so the process is terminated
+// outright and its record is pruned once it is gone.
+func signalTerminate(pid int) error {
+	process, err := windows.OpenProcess(windows.PROCESS_TERMINATE, false, uint32(pid))
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(process)
+	return windows.TerminateProcess(process, 1)
+}
+
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// another user's process can't be opened, but it exists
+		return errors.Is(err, windows.ERROR_ACCESS_DENIED)
+	}
+	defer windows.CloseHandle(process)
+	var code uint32
+	if err := windows.GetExitCodeProcess(process, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
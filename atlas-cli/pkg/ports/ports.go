@@ -0,0 +1,182 @@
+// Package ports tracks the host ports assigned to local clusters, so clusters running side by
+// side are never handed the same API server, ingress, tunnel or registry port.
+package ports
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+)
+
+// Well-known allocation purposes. Host ports from a cluster's port mappings are recorded as
+// HostPortPurpose(containerPort).
+const (
+	PurposeAPIServer = "api-server"
+	PurposeIngress   = "ingress"
+	PurposeTunnel    = "tunnel"
+	PurposeRegistry  = "registry"
+)
+
+// rangeStart is where the search for a free port begins for each purpose
+var rangeStart = map[string]int{
+	PurposeAPIServer: 8443,
+	PurposeIngress:   8080,
+	PurposeTunnel:    9000,
+	PurposeRegistry:  5000,
+}
+
+const (
+	defaultRangeStart = 10000
+	searchLimit       = 1000
+)
+
+// HostPortPurpose names the allocation for a port mapping to containerPort
+func HostPortPurpose(containerPort int) string {
+	return "port-map:" + strconv.Itoa(containerPort)
+}
+
+// Allocation is a host port held by a cluster for one purpose
+type Allocation struct {
+	Cluster string `json:"cluster"`
+	Purpose string `json:"purpose"`
+	Port    int    `json:"port"`
+}
+
+// DefaultStorePath returns the location of the port allocations file
+func DefaultStorePath() (string, error) {
+	return store.Path("ports.json")
+}
+
+// Store holds every cluster's port allocations on disk, keyed by cluster then purpose
+type Store struct {
+	mu          sync.Mutex
+	path        string
+	allocations map[string]map[string]int
+	// available reports whether nothing on the host is listening on port
+	available func(port int) bool
+}
+
+// LoadStore reads the allocations at path; a missing file starts empty
+func LoadStore(path string) (*Store, error) {
+	s := &Store{path: path, allocations: make(map[string]map[string]int), available: hostPortFree}
+	if err := store.LoadJSON(path, &s.allocations); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update loads the allocations at path, lets update change them and saves them again, holding
+// the file's lock throughout so concurrent creates are never handed the same port
+func Update(path string, update func(*Store) error) error {
+	s := &Store{path: path, allocations: make(map[string]map[string]int), available: hostPortFree}
+	return store.UpdateJSON(path, &s.allocations, func() error { return update(s) })
+}
+
+// Reserve records port as cluster's port for purpose. It fails if another cluster, or another
+// purpose of the same cluster, already holds the port.
+func (s *Store) Reserve(cluster, purpose string, port int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if owner, ok := s.owner(port); ok && (owner.Cluster != cluster || owner.Purpose != purpose) {
+		return fmt.Errorf("port %d is already allocated to %s of cluster %s", port, owner.Purpose, owner.Cluster)
+	}
+	s.set(cluster, purpose, port)
+	return nil
+}
+
+// Allocate returns cluster's port for purpose, picking the first port from the purpose's range
+// that no cluster holds and nothing on the host is using
+func (s *Store) Allocate(cluster, purpose string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if port, ok := s.allocations[cluster][purpose]; ok {
+		return port, nil
+	}
+
+	start, ok := rangeStart[purpose]
+	if !ok {
+		start = defaultRangeStart
+	}
+	for port := start; port < start+searchLimit; port++ {
+		if _, taken := s.owner(port); taken || !s.available(port) {
+			continue
+		}
+		s.set(cluster, purpose, port)
+		return port, nil
+	}
+	return 0, fmt.Errorf("no free port for %s between %d and %d", purpose, start, start+searchLimit-1)
+}
+
+// Release drops every allocation held by cluster
+func (s *Store) Release(cluster string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.allocations, cluster)
+}
+
+// Free drops cluster's allocation for purpose
+func (s *Store) Free(cluster, purpose string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.allocations[cluster], purpose)
+	if len(s.allocations[cluster]) == 0 {
+		delete(s.allocations, cluster)
+	}
+}
+
+// Allocations returns every allocation sorted by port
+func (s *Store) Allocations() []Allocation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var allocations []Allocation
+	for cluster, purposes := range s.allocations {
+		for purpose, port := range purposes {
+			allocations = append(allocations, Allocation{Cluster: cluster, Purpose: purpose, Port: port})
+		}
+	}
+	sort.Slice(allocations, func(i, j int) bool {
+		if allocations[i].Port != allocations[j].Port {
+			return allocations[i].Port < allocations[j].Port
+		}
+		return allocations[i].Cluster < allocations[j].Cluster
+	})
+	return allocations
+}
+
+// Save writes the allocations back to disk
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return store.SaveJSON(s.path, s.allocations)
+}
+
+func (s *Store) owner(port int) (Allocation, bool) {
+	for cluster, purposes := range s.allocations {
+		for purpose, p := range purposes {
+			if p == port {
+				return Allocation{Cluster: cluster, Purpose: purpose, Port: port}, true
+			}
+		}
+	}
+	return Allocation{}, false
+}
+
+func (s *Store) set(cluster, purpose string, port int) {
+	if s.allocations[cluster] == nil {
+		s.allocations[cluster] = make(map[string]int)
+	}
+	s.allocations[cluster][purpose] = port
+}
+
+func hostPortFree(port int) bool {
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
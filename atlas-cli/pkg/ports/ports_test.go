@@ -0,0 +1,120 @@
+package ports
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ports.json")
+	store, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore() error = %v", err)
+	}
+	// pretend 8443 is held by something outside Atlas
+	store.available = func(port int) bool { return port != 8443 }
+
+	port, err := store.Allocate("dev", PurposeAPIServer)
+	if err != nil || port != 8444 {
+		t.Fatalf("Allocate(dev) = %d, %v, want 8444", port, err)
+	}
+	if again, _ := store.Allocate("dev", PurposeAPIServer); again != port {
+		t.Errorf("Allocate(dev) again = %d, want the existing %d", again, port)
+	}
+	if port, _ := store.Allocate("test", PurposeAPIServer); port != 8445 {
+		t.Errorf("Allocate(test) = %d, want 8445", port)
+	}
+
+	if err := store.Reserve("test", HostPortPurpose(80), 8444); err == nil {
+		t.Error("Reserve() expected error for a port held by another cluster")
+	}
+	if err := store.Reserve("dev", PurposeAPIServer, 8444); err != nil {
+		t.Errorf("Reserve() of the cluster's own port = %v", err)
+	}
+	if err := store.Reserve("test", HostPortPurpose(80), 8080); err != nil {
+		t.Errorf("Reserve() error = %v", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore() error = %v", err)
+	}
+	allocations := loaded.Allocations()
+	want := []Allocation{
+		{Cluster: "test", Purpose: "port-map:80", Port: 8080},
+		{Cluster: "dev", Purpose: PurposeAPIServer, Port: 8444},
+		{Cluster: "test", Purpose: PurposeAPIServer, Port: 8445},
+	}
+	if len(allocations) != len(want) {
+		t.Fatalf("Allocations() = %+v, want %+v", allocations, want)
+	}
+	for i := range want {
+		if allocations[i] != want[i] {
+			t.Errorf("Allocations()[%d] = %+v, want %+v", i, allocations[i], want[i])
+		}
+	}
+
+	loaded.Release("test")
+	if got := loaded.Allocations(); len(got) != 1 || got[0].Cluster != "dev" {
+		t.Errorf("Allocations() after Release = %+v, want only dev", got)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ports.json")
+
+	// concurrent creates each hold the file's lock while they pick a port, so none share one
+	const clusters = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, clusters)
+	for i := 0; i < clusters; i++ {
+		wg.Add(1)
+		go func(cluster string) {
+			defer wg.Done()
+			errs <- Update(path, func(store *Store) error {
+				_, err := store.Allocate(cluster, PurposeTunnel)
+				return err
+			})
+		}(fmt.Sprintf("cluster-%d", i))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+
+	loaded, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore() error = %v", err)
+	}
+	allocations := loaded.Allocations()
+	if len(allocations) != clusters {
+		t.Fatalf("Allocations() = %+v, want one per cluster", allocations)
+	}
+	for i := 1; i < len(allocations); i++ {
+		if allocations[i].Port == allocations[i-1].Port {
+			t.Errorf("clusters %s and %s were both given port %d", allocations[i-1].Cluster, allocations[i].Cluster, allocations[i].Port)
+		}
+	}
+
+	if err := Update(path, func(store *Store) error { return fmt.Errorf("boom") }); err == nil {
+		t.Error("Update() expected the update's error")
+	}
+	err = Update(path, func(store *Store) error {
+		store.Free("cluster-0", PurposeTunnel)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if loaded, _ := LoadStore(path); len(loaded.Allocations()) != clusters-1 {
+		t.Errorf("Allocations() after Free = %+v, want cluster-0 dropped", loaded.Allocations())
+	}
+}
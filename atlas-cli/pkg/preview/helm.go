@@ -0,0 +1,47 @@
+package preview
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// Chart is a Helm chart deployed into a preview cluster
+type Chart struct {
+	Release   string
+	Chart     string
+	Namespace string
+	// Values lists values files passed with -f, in order
+	Values []string
+	// Set lists key=value overrides passed with --set
+	Set []string
+}
+
+// helmArgs builds the idempotent `helm upgrade --install` invocation for chart
+func helmArgs(kubeContext string, chart Chart) []string {
+	args := []string{"upgrade", "--install", chart.Release, chart.Chart,
+		"--kube-context", kubeContext, "--wait", "--timeout", "10m"}
+	if chart.Namespace != "" {
+		args = append(args, "--namespace", chart.Namespace, "--create-namespace")
+	}
+	for _, values := range chart.Values {
+		args = append(args, "-f", values)
+	}
+	for _, set := range chart.Set {
+		args = append(args, "--set", set)
+	}
+	return args
+}
+
+// InstallChart installs or upgrades chart in the cluster behind kubeContext and waits for it to
+// become ready
+func InstallChart(ctx context.Context, kubeContext string, chart Chart) error {
+	ctx = subprocess.WithOperation(ctx, "create")
+	output, err := subprocess.CommandContext(ctx, "helm", helmArgs(kubeContext, chart)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to install chart %s: %w: %s", chart.Chart, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// Package preview tracks short-lived per-pull-request preview environments: the cluster each
+// one runs on, where it is reachable and when it expires. Atlas has no daemon, so expiry is
+// enforced by whoever runs `atlas-cli preview cleanup`, typically a scheduled CI job.
+package preview
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+)
+
+// DefaultTTL is how long a preview lives when no TTL is given
+const DefaultTTL = 72 * time.Hour
+
+// Environment is one pull request's preview deployment
+type Environment struct {
+	PR        int       `json:"pr"`
+	Cluster   string    `json:"cluster"`
+	Provider  string    `json:"provider"`
+	Region    string    `json:"region,omitempty"`
+	Template  string    `json:"template"`
+	Endpoint  string    `json:"endpoint,omitempty"`
+	Release   string    `json:"release,omitempty"`
+	NotifyURL string    `json:"notify_url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Expired reports whether the preview's TTL has run out at now
+func (e *Environment) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+// ClusterName returns the cluster name used for pr's preview
+func ClusterName(pr int) string {
+	return "preview-pr-" + strconv.Itoa(pr)
+}
+
+// DefaultStorePath returns the location of the preview registry file
+func DefaultStorePath() (string, error) {
+	return store.Path("previews.json")
+}
+
+// Store is the on-disk registry of live previews, keyed by pull request number
+type Store struct {
+	mu           sync.Mutex
+	path         string
+	environments map[int]*Environment
+}
+
+// LoadStore reads the registry at path; a missing file starts empty
+func LoadStore(path string) (*Store, error) {
+	s := &Store{path: path, environments: make(map[int]*Environment)}
+	var environments []*Environment
+	if err := store.LoadJSON(path, &environments); err != nil {
+		return nil, err
+	}
+	for _, env := range environments {
+		s.environments[env.PR] = env
+	}
+	return s, nil
+}
+
+// Get returns the preview for pr, or nil if there is none
+func (s *Store) Get(pr int) *Environment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.environments[pr]
+}
+
+// Put adds or replaces env
+func (s *Store) Put(env *Environment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.environments[env.PR] = env
+}
+
+// Remove forgets the preview for pr
+func (s *Store) Remove(pr int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.environments, pr)
+}
+
+// List returns every preview ordered by pull request number
+func (s *Store) List() []*Environment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	environments := make([]*Environment, 0, len(s.environments))
+	for _, env := range s.environments {
+		environments = append(environments, env)
+	}
+	sort.Slice(environments, func(i, j int) bool { return environments[i].PR < environments[j].PR })
+	return environments
+}
+
+// Expired returns the previews whose TTL has run out at now
+func (s *Store) Expired(now time.Time) []*Environment {
+	var expired []*Environment
+	for _, env := range s.List() {
+		if env.Expired(now) {
+			expired = append(expired, env)
+		}
+	}
+	return expired
+}
+
+// Save writes the registry back to disk
+func (s *Store) Save() error {
+	return store.SaveJSON(s.path, s.List())
+}
+
+// Event is the payload posted to a preview's notify URL
+type Event struct {
+	Event       string       `json:"event"`
+	Environment *Environment `json:"preview"`
+}
+
+// Notify posts event for env as JSON to url, e.g. a CI webhook that comments on the pull request
+func Notify(ctx context.Context, url, event string, env *Environment) error {
+	body, err := json.Marshal(Event{Event: event, Environment: env})
+	if err != nil {
+		return fmt.Errorf("failed to marshal preview event: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build preview notification: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post preview notification: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("preview notification to %s returned %s", url, resp.Status)
+	}
+	return nil
+}
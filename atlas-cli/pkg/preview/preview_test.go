@@ -0,0 +1,128 @@
+package preview
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "previews.json")
+	now := time.Date(2025, time.May, 1, 12, 0, 0, 0, time.UTC)
+
+	store, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore() on a missing file error = %v", err)
+	}
+	store.Put(&Environment{PR: 42, Cluster: ClusterName(42), Provider: "local", ExpiresAt: now.Add(time.Hour)})
+	store.Put(&Environment{PR: 7, Cluster: ClusterName(7), Provider: "aws", ExpiresAt: now.Add(-time.Minute)})
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore() error = %v", err)
+	}
+	var prs []int
+	for _, env := range reloaded.List() {
+		prs = append(prs, env.PR)
+	}
+	if !slices.Equal(prs, []int{7, 42}) {
+		t.Errorf("List() PRs = %v, want [7 42]", prs)
+	}
+	if env := reloaded.Get(42); env == nil || env.Cluster != "preview-pr-42" {
+		t.Errorf("Get(42) = %+v, want cluster preview-pr-42", env)
+	}
+
+	expired := reloaded.Expired(now)
+	if len(expired) != 1 || expired[0].PR != 7 {
+		t.Errorf("Expired() = %+v, want only PR 7", expired)
+	}
+
+	reloaded.Remove(7)
+	if reloaded.Get(7) != nil {
+		t.Error("Remove() should forget the preview")
+	}
+}
+
+func TestEnvironment_Expired(t *testing.T) {
+	now := time.Date(2025, time.May, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		want      bool
+	}{
+		{"future", now.Add(time.Second), false},
+		{"exactly now", now, true},
+		{"past", now.Add(-time.Hour), true},
+		{"no expiry", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &Environment{ExpiresAt: tt.expiresAt}
+			if got := env.Expired(now); got != tt.want {
+				t.Errorf("Expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHelmArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		chart Chart
+		want  []string
+	}{
+		{
+			name:  "minimal",
+			chart: Chart{Release: "preview-pr-1", Chart: "./charts/app"},
+			want:  []string{"upgrade", "--install", "preview-pr-1", "./charts/app", "--kube-context", "preview-pr-1", "--wait", "--timeout", "10m"},
+		},
+		{
+			name:  "namespace values and overrides",
+			chart: Chart{Release: "app", Chart: "oci://registry/app", Namespace: "web", Values: []string{"a.yaml", "b.yaml"}, Set: []string{"image.tag=abc"}},
+			want: []string{"upgrade", "--install", "app", "oci://registry/app", "--kube-context", "preview-pr-1", "--wait", "--timeout", "10m",
+				"--namespace", "web", "--create-namespace", "-f", "a.yaml", "-f", "b.yaml", "--set", "image.tag=abc"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := helmArgs("preview-pr-1", tt.chart); !slices.Equal(got, tt.want) {
+				t.Errorf("helmArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotify(t *testing.T) {
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	env := &Environment{PR: 12, Cluster: ClusterName(12), Endpoint: "https://preview-pr-12.example:6443"}
+	if err := Notify(context.Background(), server.URL, "created", env); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if received.Event != "created" || received.Environment == nil || received.Environment.Endpoint != env.Endpoint {
+		t.Errorf("Notify() posted %+v, want created event with endpoint %s", received, env.Endpoint)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	if err := Notify(context.Background(), failing.URL, "created", env); err == nil {
+		t.Error("Notify() expected error for a non-2xx response")
+	}
+}
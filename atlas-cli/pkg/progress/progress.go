@@ -0,0 +1,124 @@
+// Package progress carries structured phase events from providers to whoever is driving them.
+// Providers report through the reporter stored on the context, so the CLI, a server or an SDK
+// caller can render progress however they like without changing provider signatures.
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Status is the state of a phase within an operation
+type Status string
+
+const (
+	StatusStarted   Status = "started"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+	StatusInfo      Status = "info"
+	StatusWarning   Status = "warning"
+)
+
+// Event is a single progress update emitted by a provider
+type Event struct {
+	Cluster   string    `json:"cluster"`
+	Operation string    `json:"operation"`
+	Phase     string    `json:"phase"`
+	Status    Status    `json:"status"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Reporter receives progress events
+type Reporter interface {
+	Report(event Event)
+}
+
+// ReporterFunc adapts a function to the Reporter interface
+type ReporterFunc func(event Event)
+
+// Report calls f(event)
+func (f ReporterFunc) Report(event Event) {
+	f(event)
+}
+
+type discard struct{}
+
+func (discard) Report(Event) {}
+
+// Discard drops every event
+var Discard Reporter = discard{}
+
+type contextKey struct{}
+
+// WithReporter returns a context that carries r
+func WithReporter(ctx context.Context, r Reporter) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+// FromContext returns the reporter carried by ctx, or Discard if there is none
+func FromContext(ctx context.Context) Reporter {
+	if r, ok := ctx.Value(contextKey{}).(Reporter); ok && r != nil {
+		return r
+	}
+	return Discard
+}
+
+// Report stamps event and sends it to the reporter carried by ctx
+func Report(ctx context.Context, event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	FromContext(ctx).Report(event)
+}
+
+// TextReporter prints event messages as plain lines
+type TextReporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewTextReporter creates a reporter that writes human-readable lines to w
+func NewTextReporter(w io.Writer) *TextReporter {
+	return &TextReporter{w: w}
+}
+
+// Report writes the event message, prefixing warnings and failures
+func (t *TextReporter) Report(event Event) {
+	if event.Message == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch event.Status {
+	case StatusWarning:
+		fmt.Fprintf(t.w, "Warning: %s\n", event.Message)
+	case StatusFailed:
+		fmt.Fprintf(t.w, "Failed: %s\n", event.Message)
+	default:
+		fmt.Fprintln(t.w, event.Message)
+	}
+}
+
+// JSONReporter writes each event as a JSON object on its own line
+type JSONReporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONReporter creates a reporter that streams JSON lines to w
+func NewJSONReporter(w io.Writer) *JSONReporter {
+	return &JSONReporter{enc: json.NewEncoder(w)}
+}
+
+// Report encodes the event
+func (j *JSONReporter) Report(event Event) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.enc.Encode(event)
+}
@@ -0,0 +1,59 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestFromContext_Default(t *testing.T) {
+	if FromContext(context.Background()) != Discard {
+		t.Error("FromContext() without a reporter should return Discard")
+	}
+}
+
+func TestReport(t *testing.T) {
+	var events []Event
+	ctx := WithReporter(context.Background(), ReporterFunc(func(e Event) {
+		events = append(events, e)
+	}))
+
+	Report(ctx, Event{Cluster: "dev", Operation: "create", Phase: "provision", Status: StatusStarted})
+
+	if len(events) != 1 {
+		t.Fatalf("Report() delivered %d events, want 1", len(events))
+	}
+	if events[0].Timestamp.IsZero() {
+		t.Error("Report() should stamp events")
+	}
+}
+
+func TestTextReporter(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewTextReporter(&buf)
+
+	r.Report(Event{Status: StatusStarted, Message: "Creating minikube cluster..."})
+	r.Report(Event{Status: StatusCompleted})
+	r.Report(Event{Status: StatusWarning, Message: "addon failed"})
+
+	want := "Creating minikube cluster...\nWarning: addon failed\n"
+	if buf.String() != want {
+		t.Errorf("TextReporter output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestJSONReporter(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewJSONReporter(&buf)
+
+	r.Report(Event{Cluster: "dev", Phase: "provision", Status: StatusCompleted})
+
+	var event Event
+	if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+		t.Fatalf("JSONReporter output is not valid JSON: %v", err)
+	}
+	if event.Cluster != "dev" || event.Status != StatusCompleted {
+		t.Errorf("JSONReporter event = %+v", event)
+	}
+}
@@ -0,0 +1,150 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Result is where one cluster stands in a bulk operation
+type Result string
+
+const (
+	ResultPending   Result = "pending"
+	ResultRunning   Result = "running"
+	ResultSucceeded Result = "succeeded"
+	ResultFailed    Result = "failed"
+)
+
+// ClusterResult is one cluster's row in a Summary
+type ClusterResult struct {
+	Cluster    string `json:"cluster"`
+	Phase      string `json:"phase,omitempty"`
+	Result     Result `json:"result"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+
+	started, finished time.Time
+}
+
+// Summary tracks an operation run against many clusters at once: the phase each cluster is in,
+// how long it has taken and how it ended. It is safe for concurrent use.
+type Summary struct {
+	mu      sync.Mutex
+	results []*ClusterResult
+	byName  map[string]*ClusterResult
+	now     func() time.Time
+}
+
+// NewSummary creates a summary with every cluster pending, in the given order
+func NewSummary(clusters ...string) *Summary {
+	s := &Summary{byName: make(map[string]*ClusterResult), now: time.Now}
+	for _, cluster := range clusters {
+		result := &ClusterResult{Cluster: cluster, Result: ResultPending}
+		s.results = append(s.results, result)
+		s.byName[cluster] = result
+	}
+	return s
+}
+
+// Start marks cluster as running
+func (s *Summary) Start(cluster string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if result, ok := s.byName[cluster]; ok {
+		result.Result = ResultRunning
+		result.started = s.now()
+	}
+}
+
+// Finish records how cluster's part of the operation ended
+func (s *Summary) Finish(cluster string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, ok := s.byName[cluster]
+	if !ok {
+		return
+	}
+	result.finished = s.now()
+	result.Result = ResultSucceeded
+	if err != nil {
+		result.Result = ResultFailed
+		result.Error = err.Error()
+	}
+}
+
+// Reporter returns a reporter that tracks the phase cluster is in and passes every event on to
+// next
+func (s *Summary) Reporter(cluster string, next Reporter) Reporter {
+	return ReporterFunc(func(event Event) {
+		if event.Phase != "" && event.Status == StatusStarted {
+			s.mu.Lock()
+			if result, ok := s.byName[cluster]; ok {
+				result.Phase = event.Phase
+			}
+			s.mu.Unlock()
+		}
+		next.Report(event)
+	})
+}
+
+// Results returns a snapshot of every cluster's row; running clusters report their duration so far
+func (s *Summary) Results() []ClusterResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	results := make([]ClusterResult, len(s.results))
+	for i, result := range s.results {
+		results[i] = *result
+		switch {
+		case !result.finished.IsZero():
+			results[i].DurationMS = result.finished.Sub(result.started).Milliseconds()
+		case !result.started.IsZero():
+			results[i].DurationMS = now.Sub(result.started).Milliseconds()
+		}
+	}
+	return results
+}
+
+// Failed returns the clusters whose part of the operation failed
+func (s *Summary) Failed() []ClusterResult {
+	var failed []ClusterResult
+	for _, result := range s.Results() {
+		if result.Result == ResultFailed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Render writes the summary as a table and returns how many lines it wrote, so a live display can
+// move back up and redraw it
+func (s *Summary) Render(w io.Writer) int {
+	results := s.Results()
+	fmt.Fprintf(w, "%-24s %-20s %-10s %s\n", "CLUSTER", "PHASE", "DURATION", "RESULT")
+	for _, result := range results {
+		phase, duration := result.Phase, "-"
+		if phase == "" {
+			phase = "-"
+		}
+		if result.Result != ResultPending {
+			duration = (time.Duration(result.DurationMS) * time.Millisecond).Round(100 * time.Millisecond).String()
+		}
+		fmt.Fprintf(w, "%-24s %-20s %-10s %s\n", result.Cluster, phase, duration, result.Result)
+	}
+	return len(results) + 1
+}
+
+// RenderErrors lists each failed cluster with its error, after a blank line, and does nothing when
+// every cluster succeeded
+func (s *Summary) RenderErrors(w io.Writer) {
+	failed := s.Failed()
+	if len(failed) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%d of %d clusters failed:\n", len(failed), len(s.results))
+	for _, result := range failed {
+		fmt.Fprintf(w, "  %s: %s\n", result.Cluster, result.Error)
+	}
+}
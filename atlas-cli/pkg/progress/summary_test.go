@@ -0,0 +1,56 @@
+package progress
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSummary(t *testing.T) {
+	clock := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := NewSummary("dev-1", "dev-2", "dev-3")
+	s.now = func() time.Time { return clock }
+
+	var forwarded int
+	next := ReporterFunc(func(Event) { forwarded++ })
+	s.Start("dev-1")
+	s.Start("dev-2")
+	s.Reporter("dev-1", next).Report(Event{Phase: "drain", Status: StatusStarted})
+	s.Reporter("dev-2", next).Report(Event{Phase: "stop", Status: StatusStarted})
+	s.Reporter("dev-2", next).Report(Event{Phase: "stop", Status: StatusFailed})
+	clock = clock.Add(1500 * time.Millisecond)
+	s.Finish("dev-2", errors.New("injected stop failure"))
+	clock = clock.Add(time.Second)
+
+	results := s.Results()
+	if forwarded != 3 {
+		t.Errorf("Reporter forwarded %d events, want 3", forwarded)
+	}
+	if got := results[0]; got.Phase != "drain" || got.Result != ResultRunning || got.DurationMS != 2500 {
+		t.Errorf("running result = %+v", got)
+	}
+	if got := results[1]; got.Phase != "stop" || got.Result != ResultFailed || got.DurationMS != 1500 || got.Error != "injected stop failure" {
+		t.Errorf("failed result = %+v", got)
+	}
+	if got := results[2]; got.Result != ResultPending || got.DurationMS != 0 {
+		t.Errorf("pending result = %+v", got)
+	}
+
+	var buf bytes.Buffer
+	if lines := s.Render(&buf); lines != 4 {
+		t.Errorf("Render() = %d lines, want 4", lines)
+	}
+	s.RenderErrors(&buf)
+	want := `CLUSTER                  PHASE                DURATION   RESULT
+dev-1                    drain                2.5s       running
+dev-2                    stop                 1.5s       failed
+dev-3                    -                    -          pending
+
+1 of 3 clusters failed:
+  dev-2: injected stop failure
+`
+	if buf.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
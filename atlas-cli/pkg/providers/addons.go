@@ -0,0 +1,176 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// Addon is an optional cluster component managed by the provider
+type Addon struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Version string `json:"version,omitempty"`
+}
+
+// AddonLister is implemented by providers that can report a cluster's addons
+type AddonLister interface {
+	// ListAddons returns the addons known for the cluster, sorted by name
+	ListAddons(ctx context.Context, name string) ([]Addon, error)
+}
+
+// AddonManager is implemented by providers that can enable and disable addons on a running cluster
+type AddonManager interface {
+	EnableAddon(ctx context.Context, name, addon string) error
+	DisableAddon(ctx context.Context, name, addon string) error
+}
+
+var _ AddonLister = (*LocalProvider)(nil)
+var _ AddonLister = (*AWSProvider)(nil)
+var _ AddonLister = (*FakeProvider)(nil)
+var _ AddonManager = (*LocalProvider)(nil)
+var _ AddonManager = (*AWSProvider)(nil)
+var _ AddonManager = (*FakeProvider)(nil)
+
+// ListAddons returns the minikube addons and whether each is enabled for the profile
+func (l *LocalProvider) ListAddons(ctx context.Context, name string) ([]Addon, error) {
+	cmd := subprocess.CommandContext(ctx, "minikube", "addons", "list", "-p", name, "-o", "json")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list addons: %w", err)
+	}
+	return parseMinikubeAddons(output)
+}
+
+// EnableAddon enables a minikube addon, e.g. metrics-server or dashboard, on the profile
+func (l *LocalProvider) EnableAddon(ctx context.Context, name, addon string) error {
+	cmd := subprocess.CommandContext(ctx, "minikube", "addons", "enable", addon, "-p", name)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to enable addon %s: %w\nOutput: %s", addon, err, string(output))
+	}
+	return nil
+}
+
+// DisableAddon disables a minikube addon on the profile
+func (l *LocalProvider) DisableAddon(ctx context.Context, name, addon string) error {
+	cmd := subprocess.CommandContext(ctx, "minikube", "addons", "disable", addon, "-p", name)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to disable addon %s: %w\nOutput: %s", addon, err, string(output))
+	}
+	return nil
+}
+
+func parseMinikubeAddons(output []byte) ([]Addon, error) {
+	var result map[string]struct {
+		Profile string `json:"Profile"`
+		Status  string `json:"Status"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse addon list: %w", err)
+	}
+
+	var addons []Addon
+	for name, addon := range result {
+		addons = append(addons, Addon{Name: name, Enabled: addon.Status == "enabled"})
+	}
+	sort.Slice(addons, func(i, j int) bool {
+		return addons[i].Name < addons[j].Name
+	})
+	return addons, nil
+}
+
+func (a *AWSProvider) ListAddons(ctx context.Context, name string) ([]Addon, error) {
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "list-addons",
+		"--cluster-name", name,
+		"--region", a.region)
+
+	if a.profile != "" {
+		cmd.Args = append(cmd.Args, "--profile", a.profile)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list addons: %w", err)
+	}
+
+	var result struct {
+		Addons []string `json:"addons"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse addon list: %w", err)
+	}
+
+	var addons []Addon
+	for _, addonName := range result.Addons {
+		cmd := subprocess.CommandContext(ctx, "aws", "eks", "describe-addon",
+			"--cluster-name", name,
+			"--addon-name", addonName,
+			"--region", a.region,
+			"--query", "addon.[addonVersion,status]",
+			"--output", "json")
+
+		if a.profile != "" {
+			cmd.Args = append(cmd.Args, "--profile", a.profile)
+		}
+
+		addon := Addon{Name: addonName, Enabled: true}
+		if output, err := cmd.Output(); err == nil {
+			var fields []string
+			if json.Unmarshal(output, &fields) == nil && len(fields) == 2 {
+				addon.Version = fields[0]
+				addon.Enabled = fields[1] == "ACTIVE"
+			}
+		}
+		addons = append(addons, addon)
+	}
+
+	sort.Slice(addons, func(i, j int) bool {
+		return addons[i].Name < addons[j].Name
+	})
+	return addons, nil
+}
+
+// EnableAddon installs an EKS add-on, e.g. vpc-cni or aws-ebs-csi-driver, and waits for it to
+// become active. The add-on's default version is installed unless one is given as name@version.
+func (a *AWSProvider) EnableAddon(ctx context.Context, name, addon string) error {
+	addon, version, _ := strings.Cut(addon, "@")
+	create := []string{"eks", "create-addon", "--cluster-name", name, "--addon-name", addon}
+	if version != "" {
+		create = append(create, "--addon-version", version)
+	}
+	for _, args := range [][]string{
+		create,
+		{"eks", "wait", "addon-active", "--cluster-name", name, "--addon-name", addon},
+	} {
+		cmd := subprocess.CommandContext(ctx, "aws", append(args, "--region", a.region)...)
+		if a.profile != "" {
+			cmd.Args = append(cmd.Args, "--profile", a.profile)
+		}
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to enable addon %s: %s", addon, strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
+
+// DisableAddon removes an EKS add-on and waits until it is gone. The software it installed is
+// removed from the cluster too.
+func (a *AWSProvider) DisableAddon(ctx context.Context, name, addon string) error {
+	for _, args := range [][]string{
+		{"eks", "delete-addon", "--cluster-name", name, "--addon-name", addon},
+		{"eks", "wait", "addon-deleted", "--cluster-name", name, "--addon-name", addon},
+	} {
+		cmd := subprocess.CommandContext(ctx, "aws", append(args, "--region", a.region)...)
+		if a.profile != "" {
+			cmd.Args = append(cmd.Args, "--profile", a.profile)
+		}
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to disable addon %s: %s", addon, strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
@@ -3,20 +3,28 @@ package providers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
 	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
 )
 
 type AWSProvider struct {
 	profile   string
 	region    string
 	logSource logsource.LogSource
-	monitor   monitoring.Monitor
+	monitor   func() monitoring.Monitor
+	run       commandRunner
+	// pollInterval is how often deletions are checked on
+	pollInterval time.Duration
 }
 
 type EKSCluster struct {
@@ -32,6 +40,7 @@ type EKSCluster struct {
 type EKSNodegroup struct {
 	NodegroupName string            `json:"nodegroupName"`
 	Status        string            `json:"status"`
+	Version       string            `json:"version"`
 	InstanceTypes []string          `json:"instanceTypes"`
 	AmiType       string            `json:"amiType"`
 	NodeRole      string            `json:"nodeRole"`
@@ -39,6 +48,8 @@ type EKSNodegroup struct {
 	RemoteAccess  map[string]interface{} `json:"remoteAccess"`
 	ScalingConfig EKSScalingConfig  `json:"scalingConfig"`
 	Tags          map[string]string `json:"tags"`
+	Labels        map[string]string `json:"labels"`
+	Taints        []Taint           `json:"taints"`
 	CreatedAt     time.Time         `json:"createdAt"`
 	ModifiedAt    time.Time         `json:"modifiedAt"`
 }
@@ -51,13 +62,23 @@ type EKSScalingConfig struct {
 
 func NewAWSProvider(profile, region string) *AWSProvider {
 	return &AWSProvider{
-		profile:   profile,
-		region:    region,
-		logSource: logsource.NewAWSLogSource(profile, region),
-		monitor:   monitoring.NewAWSMonitor(profile, region),
+		profile:      profile,
+		region:       region,
+		logSource:    logsource.NewAWSLogSource(profile, region),
+		monitor:      sync.OnceValue(func() monitoring.Monitor { return monitoring.NewAWSMonitor(profile, region) }),
+		run:          runCommand,
+		pollInterval: 30 * time.Second,
 	}
 }
 
+// aws runs the AWS CLI with the provider's profile
+func (a *AWSProvider) aws(ctx context.Context, args ...string) ([]byte, error) {
+	if a.profile != "" {
+		args = append(args, "--profile", a.profile)
+	}
+	return a.run(ctx, "aws", args...)
+}
+
 func (a *AWSProvider) GetProviderName() string {
 	return "aws"
 }
@@ -84,56 +105,45 @@ func (a *AWSProvider) GetLogSource() logsource.LogSource {
 }
 
 func (a *AWSProvider) GetMonitor() monitoring.Monitor {
-	return a.monitor
+	return a.monitor()
 }
 
 func (a *AWSProvider) HealthCheck(ctx context.Context, clusterName string) (*monitoring.HealthStatus, error) {
-	return a.monitor.CheckClusterHealth(ctx, clusterName)
+	return a.monitor().CheckClusterHealth(ctx, clusterName)
 }
 
 func (a *AWSProvider) ValidateConfig(config *ClusterConfig) error {
 	if config == nil {
 		return fmt.Errorf("config cannot be nil")
 	}
+	return a.Validate(config).Err()
+}
+
+// Validate reports every problem with the cluster configuration for EKS
+func (a *AWSProvider) Validate(config *ClusterConfig) *ValidationResult {
+	result := &ValidationResult{}
 
 	if config.Name == "" {
-		return fmt.Errorf("cluster name is required")
+		result.Errorf("name", "cluster name is required")
 	}
 
-	if config.Region != "" {
-		validRegions := a.GetSupportedRegions()
-		regionValid := false
-		for _, region := range validRegions {
-			if region == config.Region {
-				regionValid = true
-				break
-			}
-		}
-		if !regionValid {
-			return fmt.Errorf("unsupported region: %s", config.Region)
-		}
+	if config.Region != "" && !slices.Contains(a.GetSupportedRegions(), config.Region) {
+		result.Errorf("region", "unsupported region: %s", config.Region)
 	}
 
-	if config.Version != "" {
-		supportedVersions := a.GetSupportedVersions()
-		versionValid := false
-		for _, version := range supportedVersions {
-			if version == config.Version {
-				versionValid = true
-				break
-			}
-		}
-		if !versionValid {
-			return fmt.Errorf("unsupported EKS version: %s", config.Version)
-		}
+	if config.Version != "" && !slices.Contains(a.GetSupportedVersions(), config.Version) {
+		result.Errorf("version", "unsupported EKS version: %s", config.Version)
 	}
 
 	if config.NodeCount < 1 {
-		return fmt.Errorf("node count must be at least 1")
+		result.Errorf("nodeCount", "node count must be at least 1")
 	}
-
 	if config.NodeCount > 100 {
-		return fmt.Errorf("node count cannot exceed 100 for EKS")
+		result.Errorf("nodeCount", "node count cannot exceed 100 for EKS")
+	}
+
+	if _, err := ResolveTags(config); err != nil {
+		result.Errorf("tags", "%v", err)
 	}
 
 	if config.InstanceType != "" {
@@ -143,22 +153,39 @@ func (a *AWSProvider) ValidateConfig(config *ClusterConfig) error {
 			"c5.large", "c5.xlarge", "c5.2xlarge", "c5.4xlarge", "c5.9xlarge", "c5.12xlarge", "c5.18xlarge", "c5.24xlarge",
 			"r5.large", "r5.xlarge", "r5.2xlarge", "r5.4xlarge", "r5.8xlarge", "r5.12xlarge", "r5.16xlarge", "r5.24xlarge",
 		}
-		instanceValid := false
-		for _, instance := range validInstanceTypes {
-			if instance == config.InstanceType {
-				instanceValid = true
-				break
-			}
+		if !slices.Contains(validInstanceTypes, config.InstanceType) {
+			result.Errorf("instanceType", "unsupported instance type: %s", config.InstanceType)
+		}
+	}
+
+	if config.AWS != nil {
+		if arn := config.AWS.ClusterRoleARN; arn != "" && !strings.HasPrefix(arn, "arn:aws:iam::") {
+			result.Errorf("aws.clusterRoleArn", "invalid IAM role ARN: %s", arn)
+		}
+		if arn := config.AWS.NodeRoleARN; arn != "" && !strings.HasPrefix(arn, "arn:aws:iam::") {
+			result.Errorf("aws.nodeRoleArn", "invalid IAM role ARN: %s", arn)
 		}
-		if !instanceValid {
-			return fmt.Errorf("unsupported instance type: %s", config.InstanceType)
+		for i, subnet := range config.AWS.Subnets {
+			if !strings.HasPrefix(subnet, "subnet-") {
+				result.Errorf(fieldPath("aws", "subnets", i), "invalid subnet ID: %s", subnet)
+			}
 		}
 	}
 
-	return nil
+	if config.DiskSize != "" {
+		result.Warnf("diskSize", "disk size is ignored by the AWS provider")
+	}
+	if len(config.Mounts) > 0 {
+		result.Warnf("mounts", "host mounts are ignored by the AWS provider")
+	}
+	validateLogConfig(config, true, result)
+	validateTracingConfig(config, result)
+
+	return result
 }
 
 func (a *AWSProvider) CreateCluster(ctx context.Context, config *ClusterConfig) (*Cluster, error) {
+	ctx = subprocess.WithOperation(ctx, "create")
 	if err := a.ValidateConfig(config); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
@@ -173,19 +200,30 @@ func (a *AWSProvider) CreateCluster(ctx context.Context, config *ClusterConfig)
 		version = "1.31"
 	}
 
-	cmd := exec.CommandContext(ctx, "aws", "eks", "create-cluster",
+	tags, err := ResolveTags(config)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "create-cluster",
 		"--name", config.Name,
 		"--version", version,
-		"--role-arn", a.getClusterServiceRoleArn(),
+		"--role-arn", a.getClusterServiceRoleArn(config),
 		"--resources-vpc-config", a.buildVpcConfig(config),
 		"--region", region)
 
+	if len(tags) > 0 {
+		cmd.Args = append(cmd.Args, "--tags", formatTags(tags, ","))
+	}
+
 	if a.profile != "" {
 		cmd.Args = append(cmd.Args, "--profile", a.profile)
 	}
 
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "control-plane", Status: progress.StatusStarted, Message: "Creating EKS control plane..."})
 	output, err := cmd.CombinedOutput()
 	if err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "control-plane", Status: progress.StatusFailed})
 		return nil, fmt.Errorf("failed to create EKS cluster: %s", string(output))
 	}
 
@@ -196,19 +234,55 @@ func (a *AWSProvider) CreateCluster(ctx context.Context, config *ClusterConfig)
 		return nil, fmt.Errorf("failed to parse create cluster response: %w", err)
 	}
 
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "wait-active", Status: progress.StatusStarted, Message: "Waiting for EKS control plane to become active..."})
 	if err := a.waitForClusterActive(ctx, config.Name, region); err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "wait-active", Status: progress.StatusFailed})
 		return nil, fmt.Errorf("cluster creation failed: %w", err)
 	}
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "wait-active", Status: progress.StatusCompleted})
 
-	if err := a.createNodeGroup(ctx, config, region); err != nil {
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "nodegroup", Status: progress.StatusStarted, Message: "Creating node group..."})
+	if err := a.createNodeGroup(ctx, config, region, tags); err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "nodegroup", Status: progress.StatusFailed})
 		return nil, fmt.Errorf("failed to create node group: %w", err)
 	}
+	if len(tags) > 0 {
+		if err := a.propagateInstanceTags(ctx, config.Name, fmt.Sprintf("%s-nodes", config.Name), region, tags); err != nil {
+			progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "tags", Status: progress.StatusWarning,
+				Message: fmt.Sprintf("failed to tag node instances: %v", err)})
+		}
+	}
+	var resources []ClusterResource
+	if logAggregation(config) != nil || tracing(config) != nil {
+		kubeContext, err := a.updateKubeConfig(ctx, config.Name, region)
+		if err != nil {
+			progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "kubeconfig", Status: progress.StatusWarning,
+				Message: fmt.Sprintf("skipping logging and tracing installs: %v", err)})
+		} else {
+			if err := installLogging(ctx, kubeContext, region, config); err != nil {
+				progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "logging", Status: progress.StatusWarning,
+					Message: err.Error()})
+			}
+			resources, err = installTracing(ctx, kubeContext, config)
+			if err != nil {
+				progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "tracing", Status: progress.StatusWarning,
+					Message: err.Error()})
+			}
+		}
+	}
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "done", Status: progress.StatusCompleted,
+		Message: fmt.Sprintf("Successfully created cluster: %s", config.Name)})
 
-	return a.GetCluster(ctx, config.Name)
+	cluster, err := a.GetCluster(ctx, config.Name)
+	if err != nil {
+		return nil, err
+	}
+	cluster.Resources = resources
+	return cluster, nil
 }
 
 func (a *AWSProvider) GetCluster(ctx context.Context, name string) (*Cluster, error) {
-	cmd := exec.CommandContext(ctx, "aws", "eks", "describe-cluster",
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "describe-cluster",
 		"--name", name,
 		"--region", a.region)
 
@@ -217,6 +291,10 @@ func (a *AWSProvider) GetCluster(ctx context.Context, name string) (*Cluster, er
 	}
 
 	output, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && strings.Contains(string(exitErr.Stderr), "ResourceNotFoundException") {
+		return nil, clusterNotFound(name)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe cluster: %w", err)
 	}
@@ -262,7 +340,7 @@ func (a *AWSProvider) GetCluster(ctx context.Context, name string) (*Cluster, er
 }
 
 func (a *AWSProvider) ListClusters(ctx context.Context) ([]*Cluster, error) {
-	cmd := exec.CommandContext(ctx, "aws", "eks", "list-clusters",
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "list-clusters",
 		"--region", a.region)
 
 	if a.profile != "" {
@@ -294,11 +372,14 @@ func (a *AWSProvider) ListClusters(ctx context.Context) ([]*Cluster, error) {
 }
 
 func (a *AWSProvider) DeleteCluster(ctx context.Context, name string) error {
+	ctx = subprocess.WithOperation(ctx, "delete")
+	progress.Report(ctx, progress.Event{Cluster: name, Operation: "delete", Phase: "nodegroups", Status: progress.StatusStarted, Message: "Deleting node groups..."})
 	if err := a.deleteNodeGroups(ctx, name); err != nil {
 		return fmt.Errorf("failed to delete node groups: %w", err)
 	}
+	progress.Report(ctx, progress.Event{Cluster: name, Operation: "delete", Phase: "control-plane", Status: progress.StatusStarted, Message: "Deleting EKS control plane..."})
 
-	cmd := exec.CommandContext(ctx, "aws", "eks", "delete-cluster",
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "delete-cluster",
 		"--name", name,
 		"--region", a.region)
 
@@ -314,6 +395,172 @@ func (a *AWSProvider) DeleteCluster(ctx context.Context, name string) error {
 	return nil
 }
 
+func (a *AWSProvider) ForceDeleteCluster(ctx context.Context, name string) error {
+	ctx = subprocess.WithOperation(ctx, "delete")
+	var lastErr error
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		exists, err := a.clusterExists(ctx, name)
+		if err == nil && !exists {
+			return nil
+		}
+
+		nodeGroups, _ := a.listNodeGroups(ctx, name)
+		for _, nodeGroupName := range nodeGroups {
+			cmd := subprocess.CommandContext(ctx, "aws", "eks", "delete-nodegroup",
+				"--cluster-name", name,
+				"--nodegroup-name", nodeGroupName,
+				"--region", a.region)
+
+			if a.profile != "" {
+				cmd.Args = append(cmd.Args, "--profile", a.profile)
+			}
+			cmd.Run()
+		}
+
+		if len(nodeGroups) > 0 {
+			if err := a.waitForNodeGroupsDeleted(ctx, name, time.Duration(attempt)*10*time.Minute); err != nil {
+				lastErr = err
+				continue
+			}
+		}
+
+		cmd := subprocess.CommandContext(ctx, "aws", "eks", "delete-cluster",
+			"--name", name,
+			"--region", a.region)
+
+		if a.profile != "" {
+			cmd.Args = append(cmd.Args, "--profile", a.profile)
+		}
+
+		output, err := cmd.CombinedOutput()
+		if err == nil || strings.Contains(string(output), "ResourceNotFoundException") {
+			return nil
+		}
+		lastErr = fmt.Errorf("attempt %d: %s", attempt, strings.TrimSpace(string(output)))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * 30 * time.Second):
+		}
+	}
+
+	return fmt.Errorf("failed to force delete cluster %s: %w", name, lastErr)
+}
+
+func (a *AWSProvider) waitForNodeGroupsDeleted(ctx context.Context, clusterName string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		nodeGroups, err := a.listNodeGroups(ctx, clusterName)
+		if err != nil || len(nodeGroups) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(a.pollInterval):
+		}
+	}
+
+	return fmt.Errorf("timeout waiting for node groups of %s to be deleted", clusterName)
+}
+
+func (a *AWSProvider) WaitForDeletion(ctx context.Context, name string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		nodeGroups, nodeGroupErr := a.listNodeGroups(ctx, name)
+		exists, err := a.clusterExists(ctx, name)
+		if err != nil {
+			return fmt.Errorf("failed to check cluster status: %w", err)
+		}
+
+		if !exists && (nodeGroupErr != nil || len(nodeGroups) == 0) {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout waiting for cluster %s to be deleted (%d node groups remaining)", name, len(nodeGroups))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(a.pollInterval):
+		}
+	}
+
+	orphans, err := a.findOrphanedResources(ctx, name)
+	if err != nil {
+		return fmt.Errorf("cluster deleted but orphan verification failed: %w", err)
+	}
+	if len(orphans) > 0 {
+		return fmt.Errorf("cluster deleted but orphaned resources remain: %s", strings.Join(orphans, ", "))
+	}
+
+	return nil
+}
+
+func (a *AWSProvider) clusterExists(ctx context.Context, name string) (bool, error) {
+	output, err := a.aws(ctx, "eks", "describe-cluster",
+		"--name", name,
+		"--region", a.region,
+		"--query", "cluster.status",
+		"--output", "text")
+	if err != nil {
+		if commandFailedWith(err, "ResourceNotFoundException") {
+			return false, nil
+		}
+		return false, fmt.Errorf("%s", commandOutput(output, err))
+	}
+
+	return true, nil
+}
+
+func (a *AWSProvider) findOrphanedResources(ctx context.Context, name string) ([]string, error) {
+	var orphans []string
+
+	output, err := a.aws(ctx, "ec2", "describe-network-interfaces",
+		"--filters", fmt.Sprintf("Name=description,Values=Amazon EKS %s", name),
+		"--region", a.region,
+		"--query", "NetworkInterfaces[].NetworkInterfaceId",
+		"--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+
+	var eniIDs []string
+	if err := json.Unmarshal(output, &eniIDs); err != nil {
+		return nil, fmt.Errorf("failed to parse network interfaces: %w", err)
+	}
+	for _, id := range eniIDs {
+		orphans = append(orphans, "eni:"+id)
+	}
+
+	output, err = a.aws(ctx, "resourcegroupstaggingapi", "get-resources",
+		"--tag-filters", fmt.Sprintf("Key=kubernetes.io/cluster/%s", name),
+		"--resource-type-filters", "elasticloadbalancing:loadbalancer",
+		"--region", a.region,
+		"--query", "ResourceTagMappingList[].ResourceARN",
+		"--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list load balancers: %w", err)
+	}
+
+	var lbARNs []string
+	if err := json.Unmarshal(output, &lbARNs); err != nil {
+		return nil, fmt.Errorf("failed to parse load balancers: %w", err)
+	}
+	for _, arn := range lbARNs {
+		orphans = append(orphans, "loadbalancer:"+arn)
+	}
+
+	return orphans, nil
+}
+
 func (a *AWSProvider) StartCluster(ctx context.Context, name string) error {
 	return fmt.Errorf("EKS clusters cannot be started/stopped - they are always running once created")
 }
@@ -323,6 +570,7 @@ func (a *AWSProvider) StopCluster(ctx context.Context, name string) error {
 }
 
 func (a *AWSProvider) ScaleCluster(ctx context.Context, name string, nodeCount int) error {
+	ctx = subprocess.WithOperation(ctx, "scale")
 	if nodeCount < 1 {
 		return fmt.Errorf("node count must be at least 1")
 	}
@@ -338,7 +586,7 @@ func (a *AWSProvider) ScaleCluster(ctx context.Context, name string, nodeCount i
 
 	nodeGroupName := nodeGroups[0]
 
-	cmd := exec.CommandContext(ctx, "aws", "eks", "update-nodegroup-config",
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "update-nodegroup-config",
 		"--cluster-name", name,
 		"--nodegroup-name", nodeGroupName,
 		"--scaling-config", fmt.Sprintf("minSize=1,maxSize=%d,desiredSize=%d", nodeCount, nodeCount),
@@ -357,7 +605,7 @@ func (a *AWSProvider) ScaleCluster(ctx context.Context, name string, nodeCount i
 }
 
 func (a *AWSProvider) getEKSVersions() ([]string, error) {
-	cmd := exec.Command("aws", "eks", "describe-addon-versions",
+	cmd := subprocess.CommandContext(context.Background(), "aws", "eks", "describe-addon-versions",
 		"--kubernetes-version", "1.31",
 		"--region", a.region,
 		"--query", "addons[0].addonVersions[0].compatibilities[*].clusterVersion",
@@ -380,16 +628,22 @@ func (a *AWSProvider) getEKSVersions() ([]string, error) {
 	return versions, nil
 }
 
-func (a *AWSProvider) getClusterServiceRoleArn() string {
+func (a *AWSProvider) getClusterServiceRoleArn(config *ClusterConfig) string {
+	if config.AWS != nil && config.AWS.ClusterRoleARN != "" {
+		return config.AWS.ClusterRoleARN
+	}
 	return fmt.Sprintf("arn:aws:iam::%s:role/eks-service-role", a.getAccountID())
 }
 
-func (a *AWSProvider) getNodeInstanceRoleArn() string {
+func (a *AWSProvider) getNodeInstanceRoleArn(config *ClusterConfig) string {
+	if config.AWS != nil && config.AWS.NodeRoleARN != "" {
+		return config.AWS.NodeRoleARN
+	}
 	return fmt.Sprintf("arn:aws:iam::%s:role/NodeInstanceRole", a.getAccountID())
 }
 
 func (a *AWSProvider) getAccountID() string {
-	cmd := exec.Command("aws", "sts", "get-caller-identity",
+	cmd := subprocess.CommandContext(context.Background(), "aws", "sts", "get-caller-identity",
 		"--query", "Account",
 		"--output", "text")
 
@@ -406,7 +660,15 @@ func (a *AWSProvider) getAccountID() string {
 }
 
 func (a *AWSProvider) buildVpcConfig(config *ClusterConfig) string {
-	return "subnetIds=subnet-12345,subnet-67890,endpointConfigAccess={publicAccess=true,privateAccess=true}"
+	return fmt.Sprintf("subnetIds=%s,endpointConfigAccess={publicAccess=true,privateAccess=true}", awsSubnets(config))
+}
+
+// awsSubnets returns the configured subnets as a comma-separated list
+func awsSubnets(config *ClusterConfig) string {
+	if config.AWS != nil && len(config.AWS.Subnets) > 0 {
+		return strings.Join(config.AWS.Subnets, ",")
+	}
+	return "subnet-12345,subnet-67890"
 }
 
 func (a *AWSProvider) waitForClusterActive(ctx context.Context, name, region string) error {
@@ -416,7 +678,7 @@ func (a *AWSProvider) waitForClusterActive(ctx context.Context, name, region str
 	deadline := time.Now().Add(maxWait)
 	
 	for time.Now().Before(deadline) {
-		cmd := exec.CommandContext(ctx, "aws", "eks", "describe-cluster",
+		cmd := subprocess.CommandContext(ctx, "aws", "eks", "describe-cluster",
 			"--name", name,
 			"--region", region,
 			"--query", "cluster.status",
@@ -450,21 +712,25 @@ func (a *AWSProvider) waitForClusterActive(ctx context.Context, name, region str
 	return fmt.Errorf("timeout waiting for cluster to become active")
 }
 
-func (a *AWSProvider) createNodeGroup(ctx context.Context, config *ClusterConfig, region string) error {
+func (a *AWSProvider) createNodeGroup(ctx context.Context, config *ClusterConfig, region string, tags map[string]string) error {
 	instanceType := config.InstanceType
 	if instanceType == "" {
 		instanceType = "t3.medium"
 	}
 
-	cmd := exec.CommandContext(ctx, "aws", "eks", "create-nodegroup",
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "create-nodegroup",
 		"--cluster-name", config.Name,
 		"--nodegroup-name", fmt.Sprintf("%s-nodes", config.Name),
-		"--subnets", "subnet-12345,subnet-67890",
-		"--node-role", a.getNodeInstanceRoleArn(),
+		"--subnets", awsSubnets(config),
+		"--node-role", a.getNodeInstanceRoleArn(config),
 		"--instance-types", instanceType,
 		"--scaling-config", fmt.Sprintf("minSize=1,maxSize=%d,desiredSize=%d", config.NodeCount, config.NodeCount),
 		"--region", region)
 
+	if len(tags) > 0 {
+		cmd.Args = append(cmd.Args, "--tags", formatTags(tags, ","))
+	}
+
 	if a.profile != "" {
 		cmd.Args = append(cmd.Args, "--profile", a.profile)
 	}
@@ -484,7 +750,7 @@ func (a *AWSProvider) waitForNodeGroupActive(ctx context.Context, clusterName, n
 	deadline := time.Now().Add(maxWait)
 	
 	for time.Now().Before(deadline) {
-		cmd := exec.CommandContext(ctx, "aws", "eks", "describe-nodegroup",
+		cmd := subprocess.CommandContext(ctx, "aws", "eks", "describe-nodegroup",
 			"--cluster-name", clusterName,
 			"--nodegroup-name", nodeGroupName,
 			"--region", region,
@@ -527,7 +793,7 @@ func (a *AWSProvider) getNodeCount(ctx context.Context, clusterName string) (int
 
 	totalNodes := 0
 	for _, nodeGroupName := range nodeGroups {
-		cmd := exec.CommandContext(ctx, "aws", "eks", "describe-nodegroup",
+		cmd := subprocess.CommandContext(ctx, "aws", "eks", "describe-nodegroup",
 			"--cluster-name", clusterName,
 			"--nodegroup-name", nodeGroupName,
 			"--region", a.region,
@@ -552,17 +818,11 @@ func (a *AWSProvider) getNodeCount(ctx context.Context, clusterName string) (int
 }
 
 func (a *AWSProvider) listNodeGroups(ctx context.Context, clusterName string) ([]string, error) {
-	cmd := exec.CommandContext(ctx, "aws", "eks", "list-nodegroups",
+	output, err := a.aws(ctx, "eks", "list-nodegroups",
 		"--cluster-name", clusterName,
 		"--region", a.region,
 		"--query", "nodegroups",
 		"--output", "json")
-
-	if a.profile != "" {
-		cmd.Args = append(cmd.Args, "--profile", a.profile)
-	}
-
-	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list node groups: %w", err)
 	}
@@ -575,6 +835,30 @@ func (a *AWSProvider) listNodeGroups(ctx context.Context, clusterName string) ([
 	return nodeGroups, nil
 }
 
+func (a *AWSProvider) describeNodeGroup(ctx context.Context, clusterName, nodeGroupName string) (*EKSNodegroup, error) {
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "describe-nodegroup",
+		"--cluster-name", clusterName,
+		"--nodegroup-name", nodeGroupName,
+		"--region", a.region)
+
+	if a.profile != "" {
+		cmd.Args = append(cmd.Args, "--profile", a.profile)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe node group: %w", err)
+	}
+
+	var result struct {
+		Nodegroup EKSNodegroup `json:"nodegroup"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse node group description: %w", err)
+	}
+	return &result.Nodegroup, nil
+}
+
 func (a *AWSProvider) deleteNodeGroups(ctx context.Context, clusterName string) error {
 	nodeGroups, err := a.listNodeGroups(ctx, clusterName)
 	if err != nil {
@@ -582,7 +866,7 @@ func (a *AWSProvider) deleteNodeGroups(ctx context.Context, clusterName string)
 	}
 
 	for _, nodeGroupName := range nodeGroups {
-		cmd := exec.CommandContext(ctx, "aws", "eks", "delete-nodegroup",
+		cmd := subprocess.CommandContext(ctx, "aws", "eks", "delete-nodegroup",
 			"--cluster-name", clusterName,
 			"--nodegroup-name", nodeGroupName,
 			"--region", a.region)
@@ -599,4 +883,6 @@ func (a *AWSProvider) deleteNodeGroups(ctx context.Context, clusterName string)
 	return nil
 }
 
-var _ Provider = (*AWSProvider)(nil)
\ No newline at end of file
+var _ Provider = (*AWSProvider)(nil)
+var _ DeletionWaiter = (*AWSProvider)(nil)
+var _ ForceDeleter = (*AWSProvider)(nil)
\ No newline at end of file
@@ -0,0 +1,113 @@
+package providers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAWSProvider_WaitForDeletion(t *testing.T) {
+	const (
+		describe      = "aws eks describe-cluster"
+		nodeGroups    = "aws eks list-nodegroups"
+		interfaces    = "aws ec2 describe-network-interfaces"
+		loadBalancers = "aws resourcegroupstaggingapi get-resources"
+	)
+	active := fakeResponse{stdout: "ACTIVE\n"}
+	gone := fakeResponse{stderr: "An error occurred (ResourceNotFoundException) when calling the DescribeCluster operation: No cluster found for name: dev."}
+	none := fakeResponse{stdout: "[]"}
+
+	tests := []struct {
+		name          string
+		setup         func(f *fakeRunner)
+		wantDescribes int
+		wantErr       string
+	}{
+		{
+			name: "already gone without orphans",
+			setup: func(f *fakeRunner) {
+				f.on(describe, gone)
+			},
+			wantDescribes: 1,
+		},
+		{
+			name: "polls until the cluster and its node groups are gone",
+			setup: func(f *fakeRunner) {
+				f.on(nodeGroups, fakeResponse{stdout: `["workers"]`}, fakeResponse{stdout: `["workers"]`}, none)
+				f.on(describe, active, active, gone)
+			},
+			wantDescribes: 3,
+		},
+		{
+			name: "waits for node groups outliving the cluster",
+			setup: func(f *fakeRunner) {
+				f.on(nodeGroups, fakeResponse{stdout: `["workers"]`}, none)
+				f.on(describe, gone)
+			},
+			wantDescribes: 2,
+		},
+		{
+			name: "times out with node groups remaining",
+			setup: func(f *fakeRunner) {
+				f.on(nodeGroups, fakeResponse{stdout: `["workers","spot"]`})
+				f.on(describe, active)
+			},
+			wantErr: "timeout waiting for cluster dev to be deleted (2 node groups remaining)",
+		},
+		{
+			name: "fails when the cluster can't be described",
+			setup: func(f *fakeRunner) {
+				f.on(describe, fakeResponse{stderr: "An error occurred (AccessDeniedException) when calling the DescribeCluster operation"})
+			},
+			wantDescribes: 1,
+			wantErr:       "failed to check cluster status: An error occurred (AccessDeniedException)",
+		},
+		{
+			name: "reports orphaned network interfaces and load balancers",
+			setup: func(f *fakeRunner) {
+				f.on(describe, gone)
+				f.on(interfaces, fakeResponse{stdout: `["eni-0a1"]`})
+				f.on(loadBalancers, fakeResponse{stdout: `["arn:aws:elasticloadbalancing:us-west-2:123:loadbalancer/net/dev/1"]`})
+			},
+			wantDescribes: 1,
+			wantErr:       "cluster deleted but orphaned resources remain: eni:eni-0a1, loadbalancer:arn:aws:elasticloadbalancing:us-west-2:123:loadbalancer/net/dev/1",
+		},
+		{
+			name: "fails when orphans can't be listed",
+			setup: func(f *fakeRunner) {
+				f.on(describe, gone)
+				f.on(interfaces, fakeResponse{stderr: "UnauthorizedOperation"})
+			},
+			wantDescribes: 1,
+			wantErr:       "cluster deleted but orphan verification failed: failed to list network interfaces",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := newFakeRunner()
+			runner.on(nodeGroups, none)
+			runner.on(interfaces, none)
+			runner.on(loadBalancers, none)
+			tt.setup(runner)
+			provider := &AWSProvider{region: "us-west-2", profile: "team", run: runner.run, pollInterval: time.Millisecond}
+
+			err := provider.WaitForDeletion(context.Background(), "dev", 20*time.Millisecond)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("WaitForDeletion() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("WaitForDeletion() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if calls := len(runner.callsMatching(describe)); tt.wantDescribes > 0 && calls != tt.wantDescribes {
+				t.Errorf("describe-cluster ran %d times, want %d", calls, tt.wantDescribes)
+			}
+			for _, call := range runner.calls {
+				if !strings.Contains(call, "--region us-west-2") || !strings.HasSuffix(call, "--profile team") {
+					t.Errorf("%q doesn't use the provider's region and profile", call)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// BootstrapManifest is a Kubernetes manifest applied right after a cluster is created.
+// Exactly one of Inline, File or URL must be set.
+type BootstrapManifest struct {
+	Name   string `yaml:"name,omitempty"`
+	Inline string `yaml:"inline,omitempty"`
+	File   string `yaml:"file,omitempty"`
+	URL    string `yaml:"url,omitempty"`
+}
+
+// ClusterResource is a Kubernetes object Atlas created in a cluster
+type ClusterResource struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+// source returns a short description of where the manifest comes from
+func (m BootstrapManifest) source() string {
+	switch {
+	case m.Name != "":
+		return m.Name
+	case m.File != "":
+		return m.File
+	case m.URL != "":
+		return m.URL
+	default:
+		return "inline"
+	}
+}
+
+func validateBootstrapManifests(manifests []BootstrapManifest, result *ValidationResult) {
+	for i, manifest := range manifests {
+		field := fieldPath("bootstrapManifests", i)
+		set := 0
+		for _, value := range []string{manifest.Inline, manifest.File, manifest.URL} {
+			if value != "" {
+				set++
+			}
+		}
+		if set != 1 {
+			result.Errorf(field, "bootstrap manifest %d must set exactly one of inline, file or url", i)
+			continue
+		}
+		if manifest.URL != "" && !strings.HasPrefix(manifest.URL, "https://") && !strings.HasPrefix(manifest.URL, "http://") {
+			result.Errorf(field+".url", "bootstrap manifest %d has an invalid url: %s", i, manifest.URL)
+		}
+		if manifest.File != "" {
+			if _, err := os.Stat(manifest.File); err != nil {
+				result.Errorf(field+".file", "bootstrap manifest %d file is not accessible: %v", i, err)
+			}
+		}
+	}
+}
+
+// applyBootstrapManifests applies each manifest in order with the kubectl command line kubectl,
+// e.g. minikube kubectl -p dev --, and returns the objects that were created or configured
+func applyBootstrapManifests(ctx context.Context, clusterName string, kubectl []string, manifests []BootstrapManifest) ([]ClusterResource, error) {
+	var resources []ClusterResource
+
+	for _, manifest := range manifests {
+		args := append(slices.Clone(kubectl[1:]), "apply", "-o", "name")
+		var stdin string
+
+		switch {
+		case manifest.Inline != "":
+			args = append(args, "-f", "-")
+			stdin = manifest.Inline
+		case manifest.File != "":
+			data, err := os.ReadFile(manifest.File)
+			if err != nil {
+				return resources, fmt.Errorf("failed to read bootstrap manifest %s: %w", manifest.File, err)
+			}
+			args = append(args, "-f", "-")
+			stdin = string(data)
+		case manifest.URL != "":
+			args = append(args, "-f", manifest.URL)
+		}
+
+		cmd := subprocess.CommandContext(ctx, kubectl[0], args...)
+		if stdin != "" {
+			cmd.Stdin = strings.NewReader(stdin)
+		}
+		output, err := cmd.Output()
+		if err != nil {
+			return resources, fmt.Errorf("failed to apply bootstrap manifest %s: %w", manifest.source(), err)
+		}
+
+		applied := parseAppliedResources(string(output), manifest.source())
+		resources = append(resources, applied...)
+		progress.Report(ctx, progress.Event{Cluster: clusterName, Operation: "create", Phase: "bootstrap", Status: progress.StatusCompleted,
+			Message: fmt.Sprintf("Applied bootstrap manifest %s (%d resources) to cluster %s", manifest.source(), len(applied), clusterName)})
+	}
+
+	return resources, nil
+}
+
+// parseAppliedResources parses `kubectl apply -o name` output such as "namespace/dev"
+func parseAppliedResources(output, source string) []ClusterResource {
+	var resources []ClusterResource
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		line = strings.TrimSpace(line)
+		kind, name, found := strings.Cut(line, "/")
+		if !found {
+			continue
+		}
+		if idx := strings.Index(kind, "."); idx > 0 {
+			kind = kind[:idx]
+		}
+		resources = append(resources, ClusterResource{
+			Kind:   kind,
+			Name:   name,
+			Source: source,
+		})
+	}
+	return resources
+}
@@ -0,0 +1,79 @@
+package providers
+
+import (
+	"testing"
+)
+
+func TestValidateBootstrapManifests(t *testing.T) {
+	tests := []struct {
+		name      string
+		manifests []BootstrapManifest
+		wantErr   bool
+	}{
+		{
+			name:      "none",
+			manifests: nil,
+		},
+		{
+			name: "inline and url",
+			manifests: []BootstrapManifest{
+				{Inline: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: dev\n"},
+				{URL: "https://example.com/crds.yaml"},
+			},
+		},
+		{
+			name:      "nothing set",
+			manifests: []BootstrapManifest{{Name: "empty"}},
+			wantErr:   true,
+		},
+		{
+			name:      "multiple sources",
+			manifests: []BootstrapManifest{{Inline: "kind: Namespace", URL: "https://example.com/ns.yaml"}},
+			wantErr:   true,
+		},
+		{
+			name:      "bad url",
+			manifests: []BootstrapManifest{{URL: "ftp://example.com/ns.yaml"}},
+			wantErr:   true,
+		},
+		{
+			name:      "missing file",
+			manifests: []BootstrapManifest{{File: "/nonexistent/manifest.yaml"}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ValidationResult{}
+			validateBootstrapManifests(tt.manifests, result)
+			err := result.Err()
+			if tt.wantErr && err == nil {
+				t.Errorf("validateBootstrapManifests() expected error but got none")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("validateBootstrapManifests() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestParseAppliedResources(t *testing.T) {
+	output := "namespace/dev\ncustomresourcedefinition.apiextensions.k8s.io/widgets.example.com\n\nclusterrole.rbac.authorization.k8s.io/viewer\n"
+
+	resources := parseAppliedResources(output, "seed")
+	if len(resources) != 3 {
+		t.Fatalf("parseAppliedResources() returned %d resources, want 3", len(resources))
+	}
+
+	want := []ClusterResource{
+		{Kind: "namespace", Name: "dev", Source: "seed"},
+		{Kind: "customresourcedefinition", Name: "widgets.example.com", Source: "seed"},
+		{Kind: "clusterrole", Name: "viewer", Source: "seed"},
+	}
+	for i := range want {
+		if resources[i] != want[i] {
+			t.Errorf("parseAppliedResources()[%d] = %+v, want %+v", i, resources[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,864 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+	"gopkg.in/yaml.v3"
+)
+
+// Cluster API groups the provider creates objects in
+const (
+	capiClusterAPIVersion      = "cluster.x-k8s.io/v1beta1"
+	capiControlPlaneAPIVersion = "controlplane.cluster.x-k8s.io/v1beta1"
+	capiBootstrapAPIVersion    = "bootstrap.cluster.x-k8s.io/v1beta1"
+)
+
+// Labels and annotations Atlas puts on the Cluster API objects it creates
+const (
+	capiManagedByLabel       = "app.kubernetes.io/managed-by"
+	capiClusterNameLabel     = "cluster.x-k8s.io/cluster-name"
+	capiRegionAnnotation     = "atlas.io/region"
+	capiTagAnnotationPrefix  = "atlas.io/tag-"
+	capiInfrastructureLabel  = "atlas.io/infrastructure"
+	capiDefaultNamespace     = "default"
+	capiDefaultCreateTimeout = "30m"
+)
+
+// InfrastructureGeneric is the infrastructure provider for Cluster API providers Atlas has no
+// built-in mapping for; the config names their kinds
+const InfrastructureGeneric = "generic"
+
+// ClusterAPIConfig selects the management cluster and infrastructure provider a Cluster API
+// workload cluster is created with, usually kept in ~/.atlas/providers/capi.yaml. The management
+// cluster must already have the core, kubeadm and infrastructure providers installed, for
+// example with clusterctl init --infrastructure hetzner.
+type ClusterAPIConfig struct {
+	// ManagementContext is the kubeconfig context of the management cluster; the current
+	// context is used when it is empty
+	ManagementContext string `yaml:"managementContext,omitempty"`
+	Namespace         string `yaml:"namespace,omitempty"`
+	// InfrastructureProvider is hetzner, docker, aws, azure, gcp, openstack, vsphere or generic
+	InfrastructureProvider   string `yaml:"infrastructureProvider,omitempty"`
+	InfrastructureAPIVersion string `yaml:"infrastructureAPIVersion,omitempty"`
+	ClusterKind              string `yaml:"clusterKind,omitempty"`
+	MachineTemplateKind      string `yaml:"machineTemplateKind,omitempty"`
+	ControlPlaneReplicas     int    `yaml:"controlPlaneReplicas,omitempty"`
+	// InfrastructureSpec and MachineSpec are merged into the spec of the infrastructure cluster
+	// and of every machine template, for settings Atlas has no field for such as SSH keys
+	InfrastructureSpec map[string]interface{} `yaml:"infrastructureSpec,omitempty"`
+	MachineSpec        map[string]interface{} `yaml:"machineSpec,omitempty"`
+}
+
+// capiInfrastructure describes the objects of one Cluster API infrastructure provider and where
+// the cluster config's instance type and region go in them
+type capiInfrastructure struct {
+	APIVersion          string
+	ClusterKind         string
+	MachineTemplateKind string
+	InstanceTypeField   string
+	RegionField         string
+	RegionList          bool
+}
+
+var capiInfrastructures = map[string]capiInfrastructure{
+	"hetzner":   {"infrastructure.cluster.x-k8s.io/v1beta1", "HetznerCluster", "HCloudMachineTemplate", "type", "controlPlaneRegions", true},
+	"docker":    {"infrastructure.cluster.x-k8s.io/v1beta1", "DockerCluster", "DockerMachineTemplate", "", "", false},
+	"aws":       {"infrastructure.cluster.x-k8s.io/v1beta2", "AWSCluster", "AWSMachineTemplate", "instanceType", "region", false},
+	"azure":     {"infrastructure.cluster.x-k8s.io/v1beta1", "AzureCluster", "AzureMachineTemplate", "vmSize", "location", false},
+	"gcp":       {"infrastructure.cluster.x-k8s.io/v1beta1", "GCPCluster", "GCPMachineTemplate", "instanceType", "region", false},
+	"openstack": {"infrastructure.cluster.x-k8s.io/v1beta1", "OpenStackCluster", "OpenStackMachineTemplate", "flavor", "", false},
+	"vsphere":   {"infrastructure.cluster.x-k8s.io/v1beta1", "VSphereCluster", "VSphereMachineTemplate", "", "", false},
+}
+
+// infrastructure returns the infrastructure provider's objects, with any kinds set in the config
+// taking precedence
+func (c *ClusterAPIConfig) infrastructure() (capiInfrastructure, error) {
+	infra, known := capiInfrastructures[c.InfrastructureProvider]
+	if !known && c.InfrastructureProvider != InfrastructureGeneric {
+		return infra, fmt.Errorf("unknown infrastructure provider %q", c.InfrastructureProvider)
+	}
+	if c.InfrastructureAPIVersion != "" {
+		infra.APIVersion = c.InfrastructureAPIVersion
+	}
+	if c.ClusterKind != "" {
+		infra.ClusterKind = c.ClusterKind
+	}
+	if c.MachineTemplateKind != "" {
+		infra.MachineTemplateKind = c.MachineTemplateKind
+	}
+	if infra.APIVersion == "" || infra.ClusterKind == "" || infra.MachineTemplateKind == "" {
+		return infra, fmt.Errorf("infrastructureAPIVersion, clusterKind and machineTemplateKind are required for a generic infrastructure provider")
+	}
+	return infra, nil
+}
+
+func (c *ClusterAPIConfig) namespace() string {
+	if c == nil || c.Namespace == "" {
+		return capiDefaultNamespace
+	}
+	return c.Namespace
+}
+
+func (c *ClusterAPIConfig) controlPlaneReplicas() int {
+	if c.ControlPlaneReplicas < 1 {
+		return 1
+	}
+	return c.ControlPlaneReplicas
+}
+
+// kubectlRunner runs kubectl with args, feeding it stdin, and returns its output
+type kubectlRunner func(ctx context.Context, stdin string, args ...string) ([]byte, error)
+
+func runKubectl(ctx context.Context, stdin string, args ...string) ([]byte, error) {
+	cmd := subprocess.CommandContext(ctx, "kubectl", args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil && stderr.Len() > 0 {
+		return output, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return output, err
+}
+
+// ClusterAPIProvider implements Provider by creating Cluster API objects in a management cluster,
+// so Atlas can run workload clusters on any infrastructure Cluster API supports, such as Hetzner
+// Cloud, without a native provider. Each cluster is a Cluster, a KubeadmControlPlane and one
+// MachineDeployment; the management cluster is the only record of them.
+type ClusterAPIProvider struct {
+	region string
+	// defaultsDir overrides DefaultsDir when set
+	defaultsDir    string
+	kubeconfigPath string
+	kubectl        kubectlRunner
+	lookPath       func(file string) (string, error)
+	logSource      *clusterAPILogSource
+	monitor        func() monitoring.Monitor
+}
+
+// NewClusterAPIProvider creates a Cluster API provider. region is recorded on new clusters and
+// passed to infrastructure providers that take one.
+func NewClusterAPIProvider(region string) *ClusterAPIProvider {
+	p := &ClusterAPIProvider{
+		region:         region,
+		kubeconfigPath: defaultKubeconfigPath(),
+		kubectl:        runKubectl,
+		lookPath:       exec.LookPath,
+		monitor:        sync.OnceValue(func() monitoring.Monitor { return monitoring.NewClusterAPIMonitor() }),
+	}
+	p.logSource = &clusterAPILogSource{provider: p}
+	return p
+}
+
+// clusterAPIContext is the kubeconfig context Atlas writes for a workload cluster
+func clusterAPIContext(clusterName string) string {
+	return "capi-" + clusterName
+}
+
+// GetProviderName returns the name of this provider
+func (p *ClusterAPIProvider) GetProviderName() string {
+	return "capi"
+}
+
+// GetSupportedRegions returns the provider's region; which regions exist depends on the
+// infrastructure provider
+func (p *ClusterAPIProvider) GetSupportedRegions() []string {
+	return []string{p.region}
+}
+
+// GetSupportedVersions returns the Kubernetes versions the kubeadm control plane is asked to run.
+// The infrastructure provider's machine images must match.
+func (p *ClusterAPIProvider) GetSupportedVersions() []string {
+	return []string{"v1.33.0", "v1.32.0", "v1.31.0", "v1.30.0"}
+}
+
+// GetLogSource returns the log source for the provider
+func (p *ClusterAPIProvider) GetLogSource() logsource.LogSource {
+	return p.logSource
+}
+
+// GetMonitor returns the monitor for health checks and metrics collection
+func (p *ClusterAPIProvider) GetMonitor() monitoring.Monitor {
+	return p.monitor()
+}
+
+// HealthCheck performs a health check on the specified cluster
+func (p *ClusterAPIProvider) HealthCheck(ctx context.Context, clusterName string) (*monitoring.HealthStatus, error) {
+	return p.monitor().CheckClusterHealth(ctx, clusterName)
+}
+
+// settings returns the management cluster settings for operations that have no cluster config,
+// read from the provider's defaults file
+func (p *ClusterAPIProvider) settings() (*ClusterAPIConfig, error) {
+	dir := p.defaultsDir
+	if dir == "" {
+		var err error
+		if dir, err = DefaultsDir(); err != nil {
+			return nil, err
+		}
+	}
+	defaults, err := LoadProviderDefaults(dir, "capi")
+	if err != nil {
+		return nil, err
+	}
+	if defaults == nil || defaults.ClusterAPI == nil {
+		return &ClusterAPIConfig{}, nil
+	}
+	return defaults.ClusterAPI, nil
+}
+
+// managementArgs prefixes kubectl args with the management cluster's context and namespace
+func managementArgs(settings *ClusterAPIConfig, args ...string) []string {
+	var prefix []string
+	if settings.ManagementContext != "" {
+		prefix = append(prefix, "--context", settings.ManagementContext)
+	}
+	prefix = append(prefix, "--namespace", settings.namespace())
+	return append(prefix, args...)
+}
+
+// CreateCluster applies the cluster's Cluster API objects to the management cluster, waits for
+// it to be provisioned, fetches its kubeconfig and installs the pod network
+func (p *ClusterAPIProvider) CreateCluster(ctx context.Context, config *ClusterConfig) (*Cluster, error) {
+	ctx = subprocess.WithOperation(ctx, "create")
+	if err := p.ValidateConfig(config); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+	settings := config.ClusterAPI
+	report := func(phase string, status progress.Status, message string) {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: phase, Status: status, Message: message})
+	}
+
+	manifest, err := clusterAPIManifest(config, p.region)
+	if err != nil {
+		return nil, err
+	}
+	report("apply", progress.StatusStarted, fmt.Sprintf("Creating Cluster API objects for %s...", config.Name))
+	if _, err := p.kubectl(ctx, manifest, managementArgs(settings, "create", "-f", "-")...); err != nil {
+		report("apply", progress.StatusFailed, "")
+		return nil, fmt.Errorf("failed to create cluster %s: %w", config.Name, err)
+	}
+	report("apply", progress.StatusCompleted, "")
+
+	report("provision", progress.StatusStarted, fmt.Sprintf("Waiting for %s to provision the control plane...", settings.InfrastructureProvider))
+	if _, err := p.kubectl(ctx, "", managementArgs(settings, "wait", "--for=condition=Ready", "--timeout="+capiDefaultCreateTimeout,
+		"clusters.cluster.x-k8s.io/"+config.Name)...); err != nil {
+		report("provision", progress.StatusFailed, "")
+		return nil, fmt.Errorf("cluster %s did not become ready: %w", config.Name, err)
+	}
+	report("provision", progress.StatusCompleted, "")
+
+	report("kubeconfig", progress.StatusStarted, "Fetching admin credentials...")
+	kubeconfig, err := p.workloadKubeconfig(ctx, settings, config.Name)
+	if err == nil {
+		err = mergeKubeconfig(p.kubeconfigPath, kubeconfig, clusterAPIContext(config.Name))
+	}
+	if err != nil {
+		report("kubeconfig", progress.StatusFailed, "")
+		return nil, err
+	}
+	report("kubeconfig", progress.StatusCompleted, fmt.Sprintf("Added context %s to %s", clusterAPIContext(config.Name), p.kubeconfigPath))
+
+	// Cluster API leaves networking to the user, and nodes stay NotReady until a CNI runs
+	network := podNetworkManifest(config)
+	report("network", progress.StatusStarted, "Installing the pod network...")
+	if _, err := p.kubectl(ctx, "", "--context", clusterAPIContext(config.Name), "apply", "-f", network); err != nil {
+		report("network", progress.StatusFailed, "")
+		return nil, fmt.Errorf("failed to install pod network from %s: %w", network, err)
+	}
+	report("network", progress.StatusCompleted, "")
+
+	kubectl := []string{"kubectl", "--context", clusterAPIContext(config.Name)}
+	resources, err := applyBootstrapManifests(ctx, config.Name, kubectl, config.BootstrapManifests)
+	if err != nil {
+		report("bootstrap", progress.StatusWarning, fmt.Sprintf("failed to apply bootstrap manifests: %v", err))
+	}
+	if err := installLogging(ctx, clusterAPIContext(config.Name), config.Region, config); err != nil {
+		report("logging", progress.StatusWarning, err.Error())
+	}
+	tracingResources, err := installTracing(ctx, clusterAPIContext(config.Name), config)
+	resources = append(resources, tracingResources...)
+	if err != nil {
+		report("tracing", progress.StatusWarning, err.Error())
+	}
+
+	report("done", progress.StatusCompleted, fmt.Sprintf("Successfully created cluster: %s", config.Name))
+	cluster, err := p.getCluster(ctx, settings, config.Name)
+	if err != nil {
+		return nil, err
+	}
+	cluster.Resources = resources
+	return cluster, nil
+}
+
+// workloadKubeconfig reads the admin kubeconfig Cluster API stores in the <name>-kubeconfig secret
+func (p *ClusterAPIProvider) workloadKubeconfig(ctx context.Context, settings *ClusterAPIConfig, name string) ([]byte, error) {
+	output, err := p.kubectl(ctx, "", managementArgs(settings, "get", "secret", name+"-kubeconfig", "-o", "jsonpath={.data.value}")...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kubeconfig of cluster %s: %w", name, err)
+	}
+	kubeconfig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(output)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode kubeconfig of cluster %s: %w", name, err)
+	}
+	return kubeconfig, nil
+}
+
+// DeleteCluster deletes the Cluster object and waits for Cluster API to tear down its machines.
+// The control plane, machine deployment and templates are owned by the Cluster and go with it.
+func (p *ClusterAPIProvider) DeleteCluster(ctx context.Context, name string) error {
+	ctx = subprocess.WithOperation(ctx, "delete")
+	settings, err := p.settings()
+	if err != nil {
+		return err
+	}
+	if _, err := p.getCluster(ctx, settings, name); err != nil {
+		return err
+	}
+	progress.Report(ctx, progress.Event{Cluster: name, Operation: "delete", Phase: "delete", Status: progress.StatusStarted,
+		Message: fmt.Sprintf("Deleting cluster %s and its machines...", name)})
+	if _, err := p.kubectl(ctx, "", managementArgs(settings, "delete", "clusters.cluster.x-k8s.io", name, "--timeout="+capiDefaultCreateTimeout)...); err != nil {
+		progress.Report(ctx, progress.Event{Cluster: name, Operation: "delete", Phase: "delete", Status: progress.StatusFailed})
+		return fmt.Errorf("failed to delete cluster %s: %w", name, err)
+	}
+	progress.Report(ctx, progress.Event{Cluster: name, Operation: "delete", Phase: "delete", Status: progress.StatusCompleted})
+	return removeKubeconfigContext(p.kubeconfigPath, clusterAPIContext(name))
+}
+
+// StartCluster is not supported: Cluster API has no way to pause machines
+func (p *ClusterAPIProvider) StartCluster(ctx context.Context, name string) error {
+	return fmt.Errorf("the capi provider cannot start clusters; scale %s up instead", name)
+}
+
+// StopCluster is not supported: Cluster API has no way to pause machines
+func (p *ClusterAPIProvider) StopCluster(ctx context.Context, name string) error {
+	return fmt.Errorf("the capi provider cannot stop clusters; scale %s down or delete it instead", name)
+}
+
+// ScaleCluster sets the machine deployment's replicas so the cluster has nodeCount nodes,
+// counting the control plane
+func (p *ClusterAPIProvider) ScaleCluster(ctx context.Context, name string, nodeCount int) error {
+	ctx = subprocess.WithOperation(ctx, "scale")
+	settings, err := p.settings()
+	if err != nil {
+		return err
+	}
+	objects, err := p.listObjects(ctx, settings, name)
+	if err != nil {
+		return err
+	}
+	cluster := objects[name]
+	if cluster == nil || cluster.cluster == nil {
+		return clusterNotFound(name)
+	}
+	if cluster.deployment == nil {
+		return fmt.Errorf("cluster %s has no machine deployment %s", name, capiDeploymentName(name))
+	}
+	controlPlanes := cluster.controlPlaneReplicas()
+	if nodeCount < controlPlanes {
+		return fmt.Errorf("cannot scale cluster %s to %d nodes: only workers are removed and it has %d control-plane machines", name, nodeCount, controlPlanes)
+	}
+	workers := nodeCount - controlPlanes
+	if _, err := p.kubectl(ctx, "", managementArgs(settings, "scale", "machinedeployments.cluster.x-k8s.io", capiDeploymentName(name), "--replicas", strconv.Itoa(workers))...); err != nil {
+		return fmt.Errorf("failed to scale cluster %s: %w", name, err)
+	}
+	progress.Report(ctx, progress.Event{Cluster: name, Operation: "scale", Phase: "scale", Status: progress.StatusCompleted,
+		Message: fmt.Sprintf("Scaled %s to %d workers; Cluster API is reconciling the machines", capiDeploymentName(name), workers)})
+	return nil
+}
+
+// GetCluster returns the cluster as the management cluster reports it
+func (p *ClusterAPIProvider) GetCluster(ctx context.Context, name string) (*Cluster, error) {
+	settings, err := p.settings()
+	if err != nil {
+		return nil, err
+	}
+	return p.getCluster(ctx, settings, name)
+}
+
+func (p *ClusterAPIProvider) getCluster(ctx context.Context, settings *ClusterAPIConfig, name string) (*Cluster, error) {
+	objects, err := p.listObjects(ctx, settings, name)
+	if err != nil {
+		return nil, err
+	}
+	cluster := objects[name]
+	if cluster == nil || cluster.cluster == nil {
+		return nil, clusterNotFound(name)
+	}
+	return cluster.info(), nil
+}
+
+// ListClusters lists the clusters Atlas created in the management cluster's namespace. Until the
+// provider defaults name a management cluster there is none to ask, so nothing is listed; this
+// keeps cluster list -p all from querying whatever the current kubectl context is.
+func (p *ClusterAPIProvider) ListClusters(ctx context.Context) ([]*Cluster, error) {
+	settings, err := p.settings()
+	if err != nil {
+		return nil, err
+	}
+	if settings.ManagementContext == "" && settings.InfrastructureProvider == "" {
+		return nil, nil
+	}
+	objects, err := p.listObjects(ctx, settings, "")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(objects))
+	for name, cluster := range objects {
+		if cluster.cluster != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	clusters := make([]*Cluster, 0, len(names))
+	for _, name := range names {
+		clusters = append(clusters, objects[name].info())
+	}
+	return clusters, nil
+}
+
+// capiObject holds the fields Atlas reads from Cluster, KubeadmControlPlane and MachineDeployment
+type capiObject struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name              string            `json:"name"`
+		CreationTimestamp time.Time         `json:"creationTimestamp"`
+		DeletionTimestamp *time.Time        `json:"deletionTimestamp,omitempty"`
+		Labels            map[string]string `json:"labels"`
+		Annotations       map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec struct {
+		Replicas             *int   `json:"replicas,omitempty"`
+		Version              string `json:"version,omitempty"`
+		ControlPlaneEndpoint struct {
+			Host string `json:"host"`
+			Port int    `json:"port"`
+		} `json:"controlPlaneEndpoint"`
+	} `json:"spec"`
+	Status struct {
+		Phase string `json:"phase"`
+	} `json:"status"`
+}
+
+// capiCluster groups a workload cluster's objects
+type capiCluster struct {
+	cluster      *capiObject
+	controlPlane *capiObject
+	deployment   *capiObject
+}
+
+// listObjects fetches the Atlas-managed objects of one cluster, or of all of them when name is
+// empty, grouped by cluster name
+func (p *ClusterAPIProvider) listObjects(ctx context.Context, settings *ClusterAPIConfig, name string) (map[string]*capiCluster, error) {
+	selector := capiManagedByLabel + "=atlas"
+	if name != "" {
+		selector += "," + capiClusterNameLabel + "=" + name
+	}
+	output, err := p.kubectl(ctx, "", managementArgs(settings, "get",
+		"clusters.cluster.x-k8s.io,kubeadmcontrolplanes.controlplane.cluster.x-k8s.io,machinedeployments.cluster.x-k8s.io",
+		"-l", selector, "-o", "json")...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Cluster API clusters: %w", err)
+	}
+	return parseClusterAPIObjects(output)
+}
+
+func parseClusterAPIObjects(data []byte) (map[string]*capiCluster, error) {
+	var list struct {
+		Items []*capiObject `json:"items"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse Cluster API objects: %w", err)
+	}
+	clusters := make(map[string]*capiCluster)
+	for _, item := range list.Items {
+		name := item.Metadata.Labels[capiClusterNameLabel]
+		if name == "" {
+			continue
+		}
+		cluster := clusters[name]
+		if cluster == nil {
+			cluster = &capiCluster{}
+			clusters[name] = cluster
+		}
+		switch item.Kind {
+		case "Cluster":
+			cluster.cluster = item
+		case "KubeadmControlPlane":
+			cluster.controlPlane = item
+		case "MachineDeployment":
+			cluster.deployment = item
+		}
+	}
+	return clusters, nil
+}
+
+func (c *capiCluster) controlPlaneReplicas() int {
+	if c.controlPlane == nil || c.controlPlane.Spec.Replicas == nil {
+		return 1
+	}
+	return *c.controlPlane.Spec.Replicas
+}
+
+// info reports the cluster's desired node count; Cluster API reconciles machines towards it
+func (c *capiCluster) info() *Cluster {
+	meta := c.cluster.Metadata
+	nodes := c.controlPlaneReplicas()
+	if c.deployment != nil && c.deployment.Spec.Replicas != nil {
+		nodes += *c.deployment.Spec.Replicas
+	}
+	version := ""
+	if c.controlPlane != nil {
+		version = c.controlPlane.Spec.Version
+	}
+	endpoint := ""
+	if host := c.cluster.Spec.ControlPlaneEndpoint.Host; host != "" {
+		endpoint = fmt.Sprintf("https://%s:%d", host, c.cluster.Spec.ControlPlaneEndpoint.Port)
+	}
+	tags := make(map[string]string)
+	for key, value := range meta.Annotations {
+		if tag, ok := strings.CutPrefix(key, capiTagAnnotationPrefix); ok {
+			tags[tag] = value
+		}
+	}
+	status := capiClusterStatus(c.cluster.Status.Phase)
+	if meta.DeletionTimestamp != nil {
+		status = ClusterStatusDeleting
+	}
+	return &Cluster{
+		Name:      meta.Name,
+		Provider:  "capi",
+		Region:    meta.Annotations[capiRegionAnnotation],
+		Status:    status,
+		NodeCount: nodes,
+		Version:   version,
+		Endpoint:  endpoint,
+		CreatedAt: meta.CreationTimestamp,
+		UpdatedAt: time.Now(),
+		Tags:      tags,
+	}
+}
+
+// capiClusterStatus maps a Cluster's phase to a cluster status
+func capiClusterStatus(phase string) ClusterStatus {
+	switch phase {
+	case "Provisioned":
+		return ClusterStatusRunning
+	case "Pending", "Provisioning", "":
+		return ClusterStatusPending
+	case "Deleting":
+		return ClusterStatusDeleting
+	}
+	return ClusterStatusError
+}
+
+func capiControlPlaneName(clusterName string) string {
+	return clusterName + "-control-plane"
+}
+
+func capiDeploymentName(clusterName string) string {
+	return clusterName + "-md-0"
+}
+
+// capiVersion returns version as the full v-prefixed version Cluster API requires
+func capiVersion(version string) string {
+	v, err := parseKubeVersion(version)
+	if err != nil {
+		return version
+	}
+	if v.Patch < 0 {
+		v.Patch = 0
+	}
+	return "v" + v.String()
+}
+
+// clusterAPIManifest renders the Cluster API objects for config as a multi-document YAML stream
+func clusterAPIManifest(config *ClusterConfig, defaultRegion string) (string, error) {
+	settings := config.ClusterAPI
+	infra, err := settings.infrastructure()
+	if err != nil {
+		return "", err
+	}
+	name := config.Name
+	region := config.Region
+	if region == "" {
+		region = defaultRegion
+	}
+	if region == "default" {
+		// the factory's placeholder when no region was given
+		region = ""
+	}
+
+	labels := map[string]interface{}{capiManagedByLabel: "atlas", capiClusterNameLabel: name}
+	if settings.InfrastructureProvider != "" {
+		labels[capiInfrastructureLabel] = settings.InfrastructureProvider
+	}
+	metadata := func(objectName string, annotations map[string]interface{}) map[string]interface{} {
+		meta := map[string]interface{}{"name": objectName, "namespace": settings.namespace(), "labels": labels}
+		if len(annotations) > 0 {
+			meta["annotations"] = annotations
+		}
+		return meta
+	}
+	ref := func(apiVersion, kind, objectName string) map[string]interface{} {
+		return map[string]interface{}{"apiVersion": apiVersion, "kind": kind, "name": objectName}
+	}
+
+	annotations := map[string]interface{}{}
+	if region != "" {
+		annotations[capiRegionAnnotation] = region
+	}
+	for key, value := range config.Tags {
+		annotations[capiTagAnnotationPrefix+key] = value
+	}
+
+	clusterNetwork := map[string]interface{}{
+		"pods": map[string]interface{}{"cidrBlocks": []string{podCIDR(config)}},
+	}
+	if network := config.NetworkConfig; network != nil {
+		if network.ServiceCIDR != "" {
+			clusterNetwork["services"] = map[string]interface{}{"cidrBlocks": []string{network.ServiceCIDR}}
+		}
+		if network.APIServerPort > 0 {
+			clusterNetwork["apiServerPort"] = network.APIServerPort
+		}
+	}
+
+	infraSpec := copyMap(settings.InfrastructureSpec)
+	if infra.RegionField != "" && region != "" {
+		if infra.RegionList {
+			infraSpec[infra.RegionField] = []string{region}
+		} else {
+			infraSpec[infra.RegionField] = region
+		}
+	}
+	machineSpec := copyMap(settings.MachineSpec)
+	if infra.InstanceTypeField != "" && config.InstanceType != "" {
+		machineSpec[infra.InstanceTypeField] = config.InstanceType
+	}
+
+	workers := config.NodeCount - settings.controlPlaneReplicas()
+	if workers < 0 {
+		workers = 0
+	}
+	version := capiVersion(config.Version)
+
+	objects := []map[string]interface{}{
+		{
+			"apiVersion": capiClusterAPIVersion,
+			"kind":       "Cluster",
+			"metadata":   metadata(name, annotations),
+			"spec": map[string]interface{}{
+				"clusterNetwork":    clusterNetwork,
+				"controlPlaneRef":   ref(capiControlPlaneAPIVersion, "KubeadmControlPlane", capiControlPlaneName(name)),
+				"infrastructureRef": ref(infra.APIVersion, infra.ClusterKind, name),
+			},
+		},
+		{
+			"apiVersion": infra.APIVersion,
+			"kind":       infra.ClusterKind,
+			"metadata":   metadata(name, nil),
+			"spec":       infraSpec,
+		},
+		{
+			"apiVersion": infra.APIVersion,
+			"kind":       infra.MachineTemplateKind,
+			"metadata":   metadata(capiControlPlaneName(name), nil),
+			"spec":       map[string]interface{}{"template": map[string]interface{}{"spec": machineSpec}},
+		},
+		{
+			"apiVersion": capiControlPlaneAPIVersion,
+			"kind":       "KubeadmControlPlane",
+			"metadata":   metadata(capiControlPlaneName(name), nil),
+			"spec": map[string]interface{}{
+				"replicas": settings.controlPlaneReplicas(),
+				"version":  version,
+				"machineTemplate": map[string]interface{}{
+					"infrastructureRef": ref(infra.APIVersion, infra.MachineTemplateKind, capiControlPlaneName(name)),
+				},
+				"kubeadmConfigSpec": map[string]interface{}{},
+			},
+		},
+		{
+			"apiVersion": infra.APIVersion,
+			"kind":       infra.MachineTemplateKind,
+			"metadata":   metadata(capiDeploymentName(name), nil),
+			"spec":       map[string]interface{}{"template": map[string]interface{}{"spec": copyMap(machineSpec)}},
+		},
+		{
+			"apiVersion": capiBootstrapAPIVersion,
+			"kind":       "KubeadmConfigTemplate",
+			"metadata":   metadata(capiDeploymentName(name), nil),
+			"spec":       map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{}}},
+		},
+		{
+			"apiVersion": capiClusterAPIVersion,
+			"kind":       "MachineDeployment",
+			"metadata":   metadata(capiDeploymentName(name), nil),
+			"spec": map[string]interface{}{
+				"clusterName": name,
+				"replicas":    workers,
+				"selector":    map[string]interface{}{"matchLabels": map[string]interface{}{}},
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"clusterName": name,
+						"version":     version,
+						"bootstrap": map[string]interface{}{
+							"configRef": ref(capiBootstrapAPIVersion, "KubeadmConfigTemplate", capiDeploymentName(name)),
+						},
+						"infrastructureRef": ref(infra.APIVersion, infra.MachineTemplateKind, capiDeploymentName(name)),
+					},
+				},
+			},
+		},
+	}
+
+	var docs []string
+	for _, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return "", fmt.Errorf("failed to render Cluster API manifest: %w", err)
+		}
+		docs = append(docs, string(data))
+	}
+	return strings.Join(docs, "---\n"), nil
+}
+
+// copyMap returns a shallow copy of m that is never nil
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		copied[key] = value
+	}
+	return copied
+}
+
+// ValidateConfig validates the cluster configuration for the Cluster API provider
+func (p *ClusterAPIProvider) ValidateConfig(config *ClusterConfig) error {
+	return p.Validate(config).Err()
+}
+
+// Validate reports every problem with the cluster configuration and its Cluster API settings
+func (p *ClusterAPIProvider) Validate(config *ClusterConfig) *ValidationResult {
+	result := &ValidationResult{}
+
+	if config.Name == "" {
+		result.Errorf("name", "cluster name is required")
+	} else if strings.Contains(config.Name, " ") {
+		result.Errorf("name", "cluster name cannot contain spaces")
+	}
+	if _, err := p.lookPath("kubectl"); err != nil {
+		result.Errorf("", "kubectl is not installed or not in PATH")
+	}
+	if config.Version != "" {
+		if _, err := parseKubeVersion(config.Version); err != nil {
+			result.Errorf("version", "%v", err)
+		}
+	}
+
+	settings := config.ClusterAPI
+	if settings == nil || settings.InfrastructureProvider == "" {
+		result.Errorf("clusterAPI.infrastructureProvider", "no infrastructure provider set; add it to the cluster config or %s", defaultsHint("capi"))
+		return result
+	}
+	infra, err := settings.infrastructure()
+	if err != nil {
+		result.Errorf("clusterAPI.infrastructureProvider", "%v", err)
+	}
+	if replicas := settings.ControlPlaneReplicas; replicas < 0 || replicas > 0 && replicas%2 == 0 {
+		result.Errorf("clusterAPI.controlPlaneReplicas", "control plane replicas must be an odd number for etcd quorum, not %d", settings.ControlPlaneReplicas)
+	}
+	if config.NodeCount > 0 && config.NodeCount < settings.controlPlaneReplicas() {
+		result.Errorf("nodeCount", "%d nodes requested but the control plane alone has %d", config.NodeCount, settings.controlPlaneReplicas())
+	}
+
+	if config.InstanceType != "" && err == nil && infra.InstanceTypeField == "" {
+		result.Warnf("instanceType", "instance type %s is ignored for %s; set it in clusterAPI.machineSpec", config.InstanceType, settings.InfrastructureProvider)
+	}
+	if config.DiskSize != "" {
+		result.Warnf("diskSize", "disk size is ignored by the capi provider; set it in clusterAPI.machineSpec")
+	}
+	if len(config.Mounts) > 0 {
+		result.Warnf("mounts", "mounts are ignored by the capi provider")
+	}
+	if network := config.NetworkConfig; network != nil {
+		if plugin := network.NetworkPlugin; plugin != "" && plugin != "auto" && plugin != "flannel" && plugin != "calico" {
+			result.Errorf("networkConfig.networkPlugin", "the capi provider installs flannel or calico, not %s", plugin)
+		}
+		if len(network.ExtraPortMaps) > 0 {
+			result.Warnf("networkConfig.extraPortMaps", "port mappings are ignored by the capi provider")
+		}
+	}
+
+	validateBootstrapManifests(config.BootstrapManifests, result)
+	validateNetworkConfig(config.NetworkConfig, result)
+	validateSecurityConfig(config.SecurityConfig, result)
+	validateLogConfig(config, false, result)
+	validateTracingConfig(config, result)
+	return result
+}
+
+// clusterAPILogSource reports each cluster's creation from its Cluster object. The management
+// cluster keeps no audit log, so other operations are not available.
+type clusterAPILogSource struct {
+	provider *ClusterAPIProvider
+}
+
+func (s *clusterAPILogSource) GetSourceName() string {
+	return "capi"
+}
+
+func (s *clusterAPILogSource) GetClusterHistory(ctx context.Context, clusterName string, limit int) ([]*logsource.OperationHistory, error) {
+	cluster, err := s.provider.GetCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return capiCreationHistory(cluster, limit), nil
+}
+
+func (s *clusterAPILogSource) GetAllClustersHistory(ctx context.Context, limit int) (map[string][]*logsource.OperationHistory, error) {
+	clusters, err := s.provider.ListClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	histories := make(map[string][]*logsource.OperationHistory)
+	for _, cluster := range clusters {
+		histories[cluster.Name] = capiCreationHistory(cluster, limit)
+	}
+	return histories, nil
+}
+
+func capiCreationHistory(cluster *Cluster, limit int) []*logsource.OperationHistory {
+	if limit < 1 {
+		return nil
+	}
+	created := cluster.CreatedAt
+	return []*logsource.OperationHistory{{
+		ClusterName:     cluster.Name,
+		OperationType:   logsource.OpTypeCreate,
+		OperationStatus: logsource.OpStatusCompleted,
+		StartedAt:       created,
+		CompletedAt:     &created,
+		Metadata:        map[string]string{"source": "capi"},
+	}}
+}
+
+var _ Provider = (*ClusterAPIProvider)(nil)
+var _ ConfigValidator = (*ClusterAPIProvider)(nil)
+var _ logsource.LogSource = (*clusterAPILogSource)(nil)
@@ -0,0 +1,345 @@
+package providers
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const testClusterAPIObjects = `{"items": [
+  {"kind": "Cluster", "metadata": {"name": "edge", "creationTimestamp": "2026-01-02T03:04:05Z",
+    "labels": {"app.kubernetes.io/managed-by": "atlas", "cluster.x-k8s.io/cluster-name": "edge"},
+    "annotations": {"atlas.io/region": "fsn1", "atlas.io/tag-team": "platform"}},
+   "spec": {"controlPlaneEndpoint": {"host": "203.0.113.10", "port": 6443}},
+   "status": {"phase": "Provisioned"}},
+  {"kind": "KubeadmControlPlane", "metadata": {"name": "edge-control-plane",
+    "labels": {"cluster.x-k8s.io/cluster-name": "edge"}}, "spec": {"replicas": 1, "version": "v1.32.0"}},
+  {"kind": "MachineDeployment", "metadata": {"name": "edge-md-0",
+    "labels": {"cluster.x-k8s.io/cluster-name": "edge"}}, "spec": {"replicas": 2}},
+  {"kind": "Cluster", "metadata": {"name": "old", "deletionTimestamp": "2026-01-03T00:00:00Z",
+    "labels": {"cluster.x-k8s.io/cluster-name": "old"}}, "status": {"phase": "Provisioned"}}
+]}`
+
+// fakeManagementCluster answers kubectl like a management cluster whose clusters provision
+// instantly, and records each call's arguments
+type fakeManagementCluster struct {
+	mu      sync.Mutex
+	calls   []string
+	applied string
+	fail    string
+}
+
+func (f *fakeManagementCluster) run(ctx context.Context, stdin string, args ...string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	call := strings.Join(args, " ")
+	f.calls = append(f.calls, call)
+	if f.fail != "" && strings.Contains(call, f.fail) {
+		return nil, errors.New("exit status 1: connection refused")
+	}
+	switch {
+	case strings.Contains(call, " create -f -"):
+		f.applied = stdin
+	case strings.Contains(call, "get secret edge-kubeconfig"):
+		return []byte(base64.StdEncoding.EncodeToString([]byte(testAdminConf))), nil
+	case strings.Contains(call, " get clusters.cluster.x-k8s.io,"):
+		return []byte(testClusterAPIObjects), nil
+	}
+	return nil, nil
+}
+
+func (f *fakeManagementCluster) callsMatching(substr string) []string {
+	var matched []string
+	for _, call := range f.calls {
+		if strings.Contains(call, substr) {
+			matched = append(matched, call)
+		}
+	}
+	return matched
+}
+
+func newTestClusterAPIProvider(t *testing.T) (*ClusterAPIProvider, *fakeManagementCluster) {
+	dir := t.TempDir()
+	kubectl := &fakeManagementCluster{}
+	p := NewClusterAPIProvider("default")
+	p.defaultsDir = dir
+	p.kubeconfigPath = filepath.Join(dir, "kubeconfig")
+	p.kubectl = kubectl.run
+	p.lookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+	return p, kubectl
+}
+
+func testHetznerConfig() *ClusterConfig {
+	return &ClusterConfig{
+		Name:         "edge",
+		Region:       "fsn1",
+		Version:      "1.32",
+		NodeCount:    3,
+		InstanceType: "cpx31",
+		Tags:         map[string]string{"team": "platform"},
+		ClusterAPI: &ClusterAPIConfig{
+			ManagementContext:      "mgmt",
+			Namespace:              "clusters",
+			InfrastructureProvider: "hetzner",
+			InfrastructureSpec:     map[string]interface{}{"sshKeys": map[string]interface{}{"hcloud": []interface{}{map[string]interface{}{"name": "ops"}}}},
+			MachineSpec:            map[string]interface{}{"imageName": "ubuntu-24.04"},
+		},
+	}
+}
+
+// decodeManifest splits a rendered manifest into its objects keyed by kind/name
+func decodeManifest(t *testing.T, manifest string) (map[string]map[string]interface{}, []string) {
+	t.Helper()
+	objects := make(map[string]map[string]interface{})
+	var order []string
+	decoder := yaml.NewDecoder(strings.NewReader(manifest))
+	for {
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("manifest does not parse: %v\n%s", err, manifest)
+		}
+		metadata := object["metadata"].(map[string]interface{})
+		key := object["kind"].(string) + "/" + metadata["name"].(string)
+		objects[key] = object
+		order = append(order, key)
+	}
+	return objects, order
+}
+
+// field walks a decoded object along path
+func field(object map[string]interface{}, path ...string) interface{} {
+	var value interface{} = object
+	for _, key := range path {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
+
+func TestClusterAPIManifest(t *testing.T) {
+	manifest, err := clusterAPIManifest(testHetznerConfig(), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects, order := decodeManifest(t, manifest)
+	want := "Cluster/edge HetznerCluster/edge HCloudMachineTemplate/edge-control-plane KubeadmControlPlane/edge-control-plane " +
+		"HCloudMachineTemplate/edge-md-0 KubeadmConfigTemplate/edge-md-0 MachineDeployment/edge-md-0"
+	if got := strings.Join(order, " "); got != want {
+		t.Fatalf("manifest objects = %s, want %s", got, want)
+	}
+
+	cluster := objects["Cluster/edge"]
+	if ns := field(cluster, "metadata", "namespace"); ns != "clusters" {
+		t.Errorf("namespace = %v, want clusters", ns)
+	}
+	if labels := field(cluster, "metadata", "labels").(map[string]interface{}); labels[capiManagedByLabel] != "atlas" || labels[capiClusterNameLabel] != "edge" {
+		t.Errorf("labels = %v", labels)
+	}
+	if tag := field(cluster, "metadata", "annotations", "atlas.io/tag-team"); tag != "platform" {
+		t.Errorf("tag annotation = %v", tag)
+	}
+	if cidrs := field(cluster, "spec", "clusterNetwork", "pods", "cidrBlocks"); len(cidrs.([]interface{})) != 1 || cidrs.([]interface{})[0] != "10.244.0.0/16" {
+		t.Errorf("pod CIDRs = %v", cidrs)
+	}
+	if kind := field(cluster, "spec", "infrastructureRef", "kind"); kind != "HetznerCluster" {
+		t.Errorf("infrastructureRef kind = %v", kind)
+	}
+
+	hetzner := objects["HetznerCluster/edge"]
+	if regions := field(hetzner, "spec", "controlPlaneRegions").([]interface{}); len(regions) != 1 || regions[0] != "fsn1" {
+		t.Errorf("controlPlaneRegions = %v", regions)
+	}
+	if field(hetzner, "spec", "sshKeys") == nil {
+		t.Error("infrastructureSpec was not merged into the HetznerCluster")
+	}
+	for _, name := range []string{"edge-control-plane", "edge-md-0"} {
+		template := objects["HCloudMachineTemplate/"+name]
+		if got := field(template, "spec", "template", "spec", "type"); got != "cpx31" {
+			t.Errorf("%s type = %v, want cpx31", name, got)
+		}
+		if got := field(template, "spec", "template", "spec", "imageName"); got != "ubuntu-24.04" {
+			t.Errorf("%s imageName = %v", name, got)
+		}
+	}
+
+	controlPlane := objects["KubeadmControlPlane/edge-control-plane"]
+	if replicas, version := field(controlPlane, "spec", "replicas"), field(controlPlane, "spec", "version"); replicas != 1 || version != "v1.32.0" {
+		t.Errorf("control plane replicas %v version %v, want 1 and v1.32.0", replicas, version)
+	}
+	deployment := objects["MachineDeployment/edge-md-0"]
+	if replicas := field(deployment, "spec", "replicas"); replicas != 2 {
+		t.Errorf("machine deployment replicas = %v, want 2 workers", replicas)
+	}
+	if ref := field(deployment, "spec", "template", "spec", "bootstrap", "configRef", "name"); ref != "edge-md-0" {
+		t.Errorf("bootstrap configRef = %v", ref)
+	}
+
+	generic := &ClusterConfig{Name: "lab", NodeCount: 1, ClusterAPI: &ClusterAPIConfig{
+		InfrastructureProvider:   InfrastructureGeneric,
+		InfrastructureAPIVersion: "infrastructure.cluster.x-k8s.io/v1alpha1",
+		ClusterKind:              "ProxmoxCluster",
+		MachineTemplateKind:      "ProxmoxMachineTemplate",
+	}}
+	manifest, err = clusterAPIManifest(generic, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects, _ = decodeManifest(t, manifest)
+	if _, ok := objects["ProxmoxMachineTemplate/lab-md-0"]; !ok {
+		t.Errorf("generic manifest has no ProxmoxMachineTemplate:\n%s", manifest)
+	}
+	if annotations := field(objects["Cluster/lab"], "metadata", "annotations"); annotations != nil {
+		t.Errorf("annotations = %v, want none for the placeholder region", annotations)
+	}
+	if replicas := field(objects["MachineDeployment/lab-md-0"], "spec", "replicas"); replicas != 0 {
+		t.Errorf("single-node machine deployment replicas = %v, want 0", replicas)
+	}
+}
+
+func TestParseClusterAPIObjects(t *testing.T) {
+	objects, err := parseClusterAPIObjects([]byte(testClusterAPIObjects))
+	if err != nil {
+		t.Fatal(err)
+	}
+	edge := objects["edge"].info()
+	if edge.Status != ClusterStatusRunning || edge.NodeCount != 3 || edge.Version != "v1.32.0" || edge.Region != "fsn1" ||
+		edge.Endpoint != "https://203.0.113.10:6443" || edge.Tags["team"] != "platform" || edge.CreatedAt.Year() != 2026 {
+		t.Errorf("info() = %+v", edge)
+	}
+	if old := objects["old"].info(); old.Status != ClusterStatusDeleting || old.NodeCount != 1 {
+		t.Errorf("info() for a cluster being deleted = %+v", old)
+	}
+
+	for phase, want := range map[string]ClusterStatus{"Provisioning": ClusterStatusPending, "Deleting": ClusterStatusDeleting, "Failed": ClusterStatusError} {
+		if got := capiClusterStatus(phase); got != want {
+			t.Errorf("capiClusterStatus(%s) = %s, want %s", phase, got, want)
+		}
+	}
+}
+
+func TestClusterAPIProvider_Lifecycle(t *testing.T) {
+	p, kubectl := newTestClusterAPIProvider(t)
+	ctx := context.Background()
+
+	cluster, err := p.CreateCluster(ctx, testHetznerConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cluster.Name != "edge" || cluster.Provider != "capi" || cluster.NodeCount != 3 {
+		t.Errorf("CreateCluster() = %+v", cluster)
+	}
+	if !strings.Contains(kubectl.applied, "kind: HetznerCluster") {
+		t.Errorf("applied manifest = %s", kubectl.applied)
+	}
+	if calls := kubectl.callsMatching("--context mgmt --namespace clusters wait --for=condition=Ready"); len(calls) != 1 {
+		t.Errorf("wait calls = %v", kubectl.calls)
+	}
+	if calls := kubectl.callsMatching("--context capi-edge apply -f https://github.com/flannel-io"); len(calls) != 1 {
+		t.Errorf("pod network calls = %v", kubectl.calls)
+	}
+	config, err := clientcmd.LoadFromFile(p.kubeconfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.CurrentContext != "capi-edge" || config.Clusters["capi-edge"].Server != "https://10.0.0.1:6443" {
+		t.Errorf("kubeconfig = %+v", config)
+	}
+
+	if clusters, err := p.ListClusters(ctx); err != nil || len(clusters) != 0 {
+		t.Errorf("ListClusters() without a management cluster configured = %v, %v, want none", clusters, err)
+	}
+
+	// later commands read the management cluster from the provider defaults
+	defaults := []byte("clusterAPI:\n  managementContext: mgmt\n  namespace: clusters\n")
+	if err := os.WriteFile(filepath.Join(p.defaultsDir, "capi.yaml"), defaults, 0600); err != nil {
+		t.Fatal(err)
+	}
+	clusters, err := p.ListClusters(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 2 || clusters[0].Name != "edge" || clusters[1].Name != "old" {
+		t.Errorf("ListClusters() = %+v", clusters)
+	}
+
+	if err := p.ScaleCluster(ctx, "edge", 5); err != nil {
+		t.Fatal(err)
+	}
+	if calls := kubectl.callsMatching("--context mgmt --namespace clusters scale machinedeployments.cluster.x-k8s.io edge-md-0 --replicas 4"); len(calls) != 1 {
+		t.Errorf("scale calls = %v", kubectl.calls)
+	}
+	if err := p.ScaleCluster(ctx, "edge", 0); err == nil || !strings.Contains(err.Error(), "control-plane") {
+		t.Errorf("ScaleCluster() below the control plane = %v", err)
+	}
+
+	if err := p.DeleteCluster(ctx, "edge"); err != nil {
+		t.Fatal(err)
+	}
+	if calls := kubectl.callsMatching("delete clusters.cluster.x-k8s.io edge"); len(calls) != 1 {
+		t.Errorf("delete calls = %v", kubectl.calls)
+	}
+	config, _ = clientcmd.LoadFromFile(p.kubeconfigPath)
+	if _, ok := config.Contexts["capi-edge"]; ok {
+		t.Error("DeleteCluster() should remove the capi-edge context")
+	}
+	if err := p.DeleteCluster(ctx, "missing"); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("DeleteCluster() of a missing cluster = %v", err)
+	}
+
+	kubectl.fail = "wait"
+	if _, err := p.CreateCluster(ctx, testHetznerConfig()); err == nil || !strings.Contains(err.Error(), "did not become ready") {
+		t.Errorf("CreateCluster() when provisioning fails = %v", err)
+	}
+}
+
+func TestClusterAPIProvider_Validate(t *testing.T) {
+	p, _ := newTestClusterAPIProvider(t)
+
+	if result := p.Validate(testHetznerConfig()); len(result.Errors()) != 0 || len(result.Warnings()) != 0 {
+		t.Errorf("Validate() = %v, want no issues", result.Err())
+	}
+
+	missing := p.Validate(&ClusterConfig{Name: "edge"})
+	if errs := missing.Errors(); len(errs) != 1 || errs[0].Field != "clusterAPI.infrastructureProvider" {
+		t.Errorf("Validate() without settings = %+v", errs)
+	}
+
+	config := testHetznerConfig()
+	config.ClusterAPI.InfrastructureProvider = "generic"
+	config.ClusterAPI.ControlPlaneReplicas = 2
+	config.NodeCount = 1
+	config.NetworkConfig = &NetworkConfig{NetworkPlugin: "bridge"}
+	var fields []string
+	for _, issue := range p.Validate(config).Errors() {
+		fields = append(fields, issue.Field)
+	}
+	want := "clusterAPI.infrastructureProvider clusterAPI.controlPlaneReplicas nodeCount networkConfig.networkPlugin"
+	if got := strings.Join(fields, " "); got != want {
+		t.Errorf("Validate() error fields = %s, want %s", got, want)
+	}
+
+	docker := testHetznerConfig()
+	docker.ClusterAPI.InfrastructureProvider = "docker"
+	if warnings := p.Validate(docker).Warnings(); len(warnings) != 1 || warnings[0].Field != "instanceType" {
+		t.Errorf("Validate() warnings for docker = %+v", warnings)
+	}
+
+	p.lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	if err := p.ValidateConfig(testHetznerConfig()); err == nil || !strings.Contains(err.Error(), "kubectl") {
+		t.Errorf("ValidateConfig() without kubectl = %v", err)
+	}
+}
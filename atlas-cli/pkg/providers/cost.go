@@ -0,0 +1,118 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// hoursPerMonth is the average number of hours in a month used for monthly estimates
+const hoursPerMonth = 730
+
+// eksControlPlaneHourlyUSD is the flat EKS charge per cluster
+const eksControlPlaneHourlyUSD = 0.10
+
+// CostItem is a single line of a cost estimate
+type CostItem struct {
+	Name      string  `json:"name"`
+	Quantity  int     `json:"quantity"`
+	HourlyUSD float64 `json:"hourly_usd"`
+}
+
+// CostEstimate is an approximate on-demand cost for running a cluster
+type CostEstimate struct {
+	HourlyUSD  float64    `json:"hourly_usd"`
+	MonthlyUSD float64    `json:"monthly_usd"`
+	Items      []CostItem `json:"items,omitempty"`
+	Note       string     `json:"note,omitempty"`
+}
+
+// CostEstimator is implemented by providers that can estimate what a cluster costs to run
+type CostEstimator interface {
+	EstimateCost(ctx context.Context, name string) (*CostEstimate, error)
+}
+
+var _ CostEstimator = (*LocalProvider)(nil)
+var _ CostEstimator = (*AWSProvider)(nil)
+
+// EstimateCost reports zero cost since local clusters run on the developer's machine
+func (l *LocalProvider) EstimateCost(ctx context.Context, name string) (*CostEstimate, error) {
+	return &CostEstimate{Note: "local clusters run on the host and incur no cloud charges"}, nil
+}
+
+func (a *AWSProvider) EstimateCost(ctx context.Context, name string) (*CostEstimate, error) {
+	estimate := &CostEstimate{
+		Items: []CostItem{{Name: "EKS control plane", Quantity: 1, HourlyUSD: eksControlPlaneHourlyUSD}},
+		Note:  "on-demand list prices; excludes storage, data transfer and load balancers",
+	}
+
+	nodeGroups, err := a.listNodeGroups(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node groups: %w", err)
+	}
+
+	for _, nodeGroupName := range nodeGroups {
+		nodeGroup, err := a.describeNodeGroup(ctx, name, nodeGroupName)
+		if err != nil {
+			return nil, err
+		}
+		instanceType := "t3.medium"
+		if len(nodeGroup.InstanceTypes) > 0 {
+			instanceType = nodeGroup.InstanceTypes[0]
+		}
+		price, known := instanceHourlyPrice(instanceType)
+		if !known {
+			estimate.Note += fmt.Sprintf("; no price for %s", instanceType)
+		}
+		estimate.Items = append(estimate.Items, CostItem{
+			Name:      fmt.Sprintf("%s (%s)", nodeGroupName, instanceType),
+			Quantity:  nodeGroup.ScalingConfig.DesiredSize,
+			HourlyUSD: price,
+		})
+	}
+
+	for _, item := range estimate.Items {
+		estimate.HourlyUSD += float64(item.Quantity) * item.HourlyUSD
+	}
+	estimate.HourlyUSD = roundCents(estimate.HourlyUSD)
+	estimate.MonthlyUSD = roundCents(estimate.HourlyUSD * hoursPerMonth)
+	return estimate, nil
+}
+
+// largeHourlyUSD is the us-east-1 on-demand Linux price of the .large size of each family
+var largeHourlyUSD = map[string]float64{
+	"t3": 0.0832,
+	"m5": 0.096,
+	"c5": 0.085,
+	"r5": 0.126,
+}
+
+var smallT3HourlyUSD = map[string]float64{
+	"nano":   0.0052,
+	"micro":  0.0104,
+	"small":  0.0208,
+	"medium": 0.0416,
+}
+
+// instanceHourlyPrice estimates an instance's hourly price, scaling the family's .large price by vCPUs
+func instanceHourlyPrice(instanceType string) (float64, bool) {
+	family, size, found := strings.Cut(instanceType, ".")
+	if !found {
+		return 0, false
+	}
+	if family == "t3" {
+		if price, exists := smallT3HourlyUSD[size]; exists {
+			return price, true
+		}
+	}
+	base, exists := largeHourlyUSD[family]
+	if !exists || !strings.HasSuffix(size, "large") {
+		return 0, false
+	}
+	return base * float64(instanceVCPUs(instanceType)) / 2, true
+}
+
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
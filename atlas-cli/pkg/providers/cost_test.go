@@ -0,0 +1,49 @@
+package providers
+
+import "testing"
+
+func TestInstanceHourlyPrice(t *testing.T) {
+	tests := []struct {
+		instanceType string
+		want         float64
+		wantKnown    bool
+	}{
+		{"t3.micro", 0.0104, true},
+		{"t3.large", 0.0832, true},
+		{"m5.large", 0.096, true},
+		{"m5.2xlarge", 0.384, true},
+		{"c5.9xlarge", 1.53, true},
+		{"x1.large", 0, false},
+		{"bogus", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.instanceType, func(t *testing.T) {
+			got, known := instanceHourlyPrice(tt.instanceType)
+			if known != tt.wantKnown || roundCents(got*100) != roundCents(tt.want*100) {
+				t.Errorf("instanceHourlyPrice(%q) = %v, %v, want %v, %v", tt.instanceType, got, known, tt.want, tt.wantKnown)
+			}
+		})
+	}
+}
+
+func TestParseMinikubeAddons(t *testing.T) {
+	output := []byte(`{
+		"metrics-server": {"Profile": "dev", "Status": "enabled"},
+		"dashboard": {"Profile": "dev", "Status": "disabled"}
+	}`)
+
+	addons, err := parseMinikubeAddons(output)
+	if err != nil {
+		t.Fatalf("parseMinikubeAddons() unexpected error = %v", err)
+	}
+	if len(addons) != 2 {
+		t.Fatalf("parseMinikubeAddons() returned %d addons, want 2", len(addons))
+	}
+	if addons[0].Name != "dashboard" || addons[0].Enabled {
+		t.Errorf("addons[0] = %+v, want disabled dashboard", addons[0])
+	}
+	if addons[1].Name != "metrics-server" || !addons[1].Enabled {
+		t.Errorf("addons[1] = %+v, want enabled metrics-server", addons[1])
+	}
+}
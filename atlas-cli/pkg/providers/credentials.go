@@ -0,0 +1,184 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// Identity is who a cloud's credentials authenticate as
+type Identity struct {
+	Provider  string     `json:"provider"`
+	Principal string     `json:"principal"`
+	Account   string     `json:"account,omitempty"`
+	Profile   string     `json:"profile,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// ExpiresWithin reports whether the credentials expire within d; credentials without a known
+// expiry never do
+func (i *Identity) ExpiresWithin(now time.Time, d time.Duration) bool {
+	return i.ExpiresAt != nil && i.ExpiresAt.Sub(now) < d
+}
+
+// CredentialChecker is implemented by providers that authenticate against a cloud
+type CredentialChecker interface {
+	// CheckCredentials returns the identity the provider's credentials resolve to, or an error
+	// saying how to log in when they are missing or expired
+	CheckCredentials(ctx context.Context) (*Identity, error)
+}
+
+var _ CredentialChecker = (*AWSProvider)(nil)
+
+// CheckCredentials validates the profile's credentials with sts get-caller-identity
+func (a *AWSProvider) CheckCredentials(ctx context.Context) (*Identity, error) {
+	args := []string{"sts", "get-caller-identity", "--output", "json"}
+	if a.profile != "" {
+		args = append(args, "--profile", a.profile)
+	}
+	output, err := subprocess.CommandContext(ctx, "aws", args...).CombinedOutput()
+	if err != nil {
+		login := "aws configure"
+		if a.profile != "" {
+			login = "aws sso login --profile " + a.profile
+		}
+		return nil, fmt.Errorf("AWS credentials are missing or expired (%s); run '%s' or pass another --aws-profile",
+			strings.TrimSpace(string(output)), login)
+	}
+
+	var caller struct {
+		Account string `json:"Account"`
+		Arn     string `json:"Arn"`
+	}
+	if err := json.Unmarshal(output, &caller); err != nil {
+		return nil, fmt.Errorf("failed to parse caller identity: %w", err)
+	}
+	identity := &Identity{Provider: a.GetProviderName(), Principal: caller.Arn, Account: caller.Account, Profile: a.profile}
+	identity.ExpiresAt = a.credentialExpiry(ctx)
+	return identity, nil
+}
+
+// credentialExpiry returns when temporary credentials such as SSO sessions expire. Long-lived
+// keys, and CLIs too old for export-credentials, have no expiry.
+func (a *AWSProvider) credentialExpiry(ctx context.Context) *time.Time {
+	args := []string{"configure", "export-credentials", "--format", "process"}
+	if a.profile != "" {
+		args = append(args, "--profile", a.profile)
+	}
+	output, err := subprocess.CommandContext(ctx, "aws", args...).Output()
+	if err != nil {
+		return nil
+	}
+	var credentials struct {
+		Expiration string `json:"Expiration"`
+	}
+	if err := json.Unmarshal(output, &credentials); err != nil || credentials.Expiration == "" {
+		return nil
+	}
+	expiresAt, err := time.Parse(time.RFC3339, credentials.Expiration)
+	if err != nil {
+		return nil
+	}
+	return &expiresAt
+}
+
+// cloudCredentialChecks checks clouds Atlas has no cluster provider for yet, so their logins can
+// be verified ahead of time
+var cloudCredentialChecks = map[string]func(ctx context.Context) (*Identity, error){
+	"gcp":   checkGCPCredentials,
+	"azure": checkAzureCredentials,
+}
+
+// CloudCredentialCheck returns the credential check for a cloud without a provider, or nil
+func CloudCredentialCheck(name string) func(ctx context.Context) (*Identity, error) {
+	return cloudCredentialChecks[name]
+}
+
+// CloudCredentialNames lists the clouds CloudCredentialCheck knows
+func CloudCredentialNames() []string {
+	names := make([]string, 0, len(cloudCredentialChecks))
+	for name := range cloudCredentialChecks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkGCPCredentials mints an access token for the active gcloud account, as GKE's auth plugin does
+func checkGCPCredentials(ctx context.Context) (*Identity, error) {
+	output, err := subprocess.CommandContext(ctx, "gcloud", "config", "config-helper", "--format", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("GCP credentials are missing or expired (%s); run 'gcloud auth login'", strings.TrimSpace(string(output)))
+	}
+	var helper struct {
+		Configuration struct {
+			Properties struct {
+				Core struct {
+					Account string `json:"account"`
+					Project string `json:"project"`
+				} `json:"core"`
+			} `json:"properties"`
+		} `json:"configuration"`
+		Credential struct {
+			AccessToken string `json:"access_token"`
+			TokenExpiry string `json:"token_expiry"`
+		} `json:"credential"`
+	}
+	if err := json.Unmarshal(output, &helper); err != nil {
+		return nil, fmt.Errorf("failed to parse gcloud credentials: %w", err)
+	}
+	if helper.Credential.AccessToken == "" {
+		return nil, fmt.Errorf("gcloud returned no access token; run 'gcloud auth login'")
+	}
+
+	identity := &Identity{
+		Provider:  "gcp",
+		Principal: helper.Configuration.Properties.Core.Account,
+		Account:   helper.Configuration.Properties.Core.Project,
+	}
+	if expiresAt, err := time.Parse(time.RFC3339, helper.Credential.TokenExpiry); err == nil {
+		identity.ExpiresAt = &expiresAt
+	}
+	return identity, nil
+}
+
+// checkAzureCredentials checks the az CLI login and the expiry of its management token
+func checkAzureCredentials(ctx context.Context) (*Identity, error) {
+	output, err := subprocess.CommandContext(ctx, "az", "account", "show", "--output", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("Azure credentials are missing or expired (%s); run 'az login'", strings.TrimSpace(string(output)))
+	}
+	var account struct {
+		ID   string `json:"id"`
+		User struct {
+			Name string `json:"name"`
+		} `json:"user"`
+	}
+	if err := json.Unmarshal(output, &account); err != nil {
+		return nil, fmt.Errorf("failed to parse Azure account: %w", err)
+	}
+
+	output, err = subprocess.CommandContext(ctx, "az", "account", "get-access-token", "--output", "json").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("Azure login has expired (%s); run 'az login'", strings.TrimSpace(string(output)))
+	}
+	var token struct {
+		AccessToken string `json:"accessToken"`
+		ExpiresOn   int64  `json:"expires_on"`
+	}
+	if err := json.Unmarshal(output, &token); err != nil {
+		return nil, fmt.Errorf("failed to parse Azure access token: %w", err)
+	}
+
+	identity := &Identity{Provider: "azure", Principal: account.User.Name, Account: account.ID}
+	if token.ExpiresOn > 0 {
+		expiresAt := time.Unix(token.ExpiresOn, 0)
+		identity.ExpiresAt = &expiresAt
+	}
+	return identity, nil
+}
@@ -0,0 +1,132 @@
+package providers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultsDir returns the directory holding per-provider defaults such as
+// ~/.atlas/providers/aws.yaml
+func DefaultsDir() (string, error) {
+	return store.Path("providers")
+}
+
+// DefaultsPath returns where the defaults for providerName live in dir
+func DefaultsPath(dir, providerName string) string {
+	return filepath.Join(dir, providerName+".yaml")
+}
+
+// defaultsHint names providerName's defaults file in messages, even when DefaultsDir can't be
+// resolved
+func defaultsHint(providerName string) string {
+	dir, err := DefaultsDir()
+	if err != nil {
+		dir = filepath.Join("~", ".atlas", "providers")
+	}
+	return DefaultsPath(dir, providerName)
+}
+
+// LoadProviderDefaults reads the defaults for providerName from dir. The file uses the cluster
+// config format and is parsed strictly. A missing file returns nil defaults.
+func LoadProviderDefaults(dir, providerName string) (*ClusterConfig, error) {
+	path := DefaultsPath(dir, providerName)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provider defaults: %w", err)
+	}
+
+	var defaults ClusterConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&defaults); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse provider defaults %s: %w", path, err)
+	}
+	return &defaults, nil
+}
+
+// MergeDefaults fills every field config leaves unset with the value from defaults. Lists and
+// maps are taken whole, and nested sections are merged field by field.
+func MergeDefaults(config, defaults *ClusterConfig) error {
+	if defaults == nil {
+		return nil
+	}
+	base, err := toYAMLMap(defaults)
+	if err != nil {
+		return err
+	}
+	overrides, err := toYAMLMap(config)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(mergeMaps(base, overrides))
+	if err != nil {
+		return fmt.Errorf("failed to encode merged config: %w", err)
+	}
+	var merged ClusterConfig
+	if err := yaml.Unmarshal(data, &merged); err != nil {
+		return fmt.Errorf("failed to decode merged config: %w", err)
+	}
+	*config = merged
+	return nil
+}
+
+func toYAMLMap(config *ClusterConfig) (map[string]any, error) {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	values := make(map[string]any)
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+	return values, nil
+}
+
+// mergeMaps overlays the set values of overrides onto base. Zero scalars count as unset,
+// because the config types don't distinguish "" or 0 from a missing field.
+func mergeMaps(base, overrides map[string]any) map[string]any {
+	for key, value := range overrides {
+		nested, isMap := value.(map[string]any)
+		existing, existingIsMap := base[key].(map[string]any)
+		switch {
+		case isMap && existingIsMap:
+			base[key] = mergeMaps(existing, nested)
+		case isZeroYAML(value):
+			if _, ok := base[key]; !ok {
+				base[key] = value
+			}
+		default:
+			base[key] = value
+		}
+	}
+	return base
+}
+
+func isZeroYAML(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	case bool:
+		return !v
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
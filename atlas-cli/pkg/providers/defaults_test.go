@@ -0,0 +1,74 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadProviderDefaults(t *testing.T) {
+	dir := t.TempDir()
+
+	defaults, err := LoadProviderDefaults(dir, "aws")
+	if err != nil || defaults != nil {
+		t.Fatalf("missing file: got %v, %v", defaults, err)
+	}
+
+	write := func(content string) {
+		if err := os.WriteFile(filepath.Join(dir, "aws.yaml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("region: us-west-2\naws:\n  profile: staging\n  subnets: [subnet-a, subnet-b]\n")
+	defaults, err = LoadProviderDefaults(dir, "aws")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if defaults.Region != "us-west-2" || defaults.AWS == nil || defaults.AWS.Profile != "staging" || len(defaults.AWS.Subnets) != 2 {
+		t.Errorf("unexpected defaults: %+v", defaults)
+	}
+
+	write("regoin: us-west-2\n")
+	if _, err := LoadProviderDefaults(dir, "aws"); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+func TestMergeDefaults(t *testing.T) {
+	config := &ClusterConfig{
+		Name:      "web",
+		NodeCount: 5,
+		NetworkConfig: &NetworkConfig{
+			Ingress: &IngressConfig{Enabled: true},
+		},
+	}
+	defaults := &ClusterConfig{
+		Region:    "us-west-2",
+		NodeCount: 3,
+		NetworkConfig: &NetworkConfig{
+			APIServerPort: 6443,
+		},
+		AWS: &AWSConfig{Profile: "staging"},
+	}
+
+	if err := MergeDefaults(config, defaults); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Name != "web" || config.NodeCount != 5 {
+		t.Errorf("config values should win: %+v", config)
+	}
+	if config.Region != "us-west-2" {
+		t.Errorf("region = %q, want us-west-2", config.Region)
+	}
+	if config.NetworkConfig.Ingress == nil || !config.NetworkConfig.Ingress.Enabled || config.NetworkConfig.APIServerPort != 6443 {
+		t.Errorf("network config not merged: %+v", config.NetworkConfig)
+	}
+	if config.AWS == nil || config.AWS.Profile != "staging" {
+		t.Errorf("aws section not filled: %+v", config.AWS)
+	}
+
+	if err := MergeDefaults(config, nil); err != nil {
+		t.Errorf("nil defaults: %v", err)
+	}
+}
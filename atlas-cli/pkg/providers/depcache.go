@@ -0,0 +1,225 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// Kinds of dependency cache entries
+const (
+	CacheKindPreload = "preload"
+	CacheKindKicbase = "kicbase"
+	CacheKindBinary  = "binary"
+	CacheKindImage   = "image"
+)
+
+// cacheWarmProfile is the throwaway minikube profile used to download dependencies
+const cacheWarmProfile = "atlas-cache-warm"
+
+// CacheEntry is a downloaded dependency kept for later cluster creates
+type CacheEntry struct {
+	Kind string `json:"kind"`
+	// Version is the Kubernetes version the entry belongs to, empty when it is shared by all versions
+	Version string    `json:"version,omitempty"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// DependencyCacher is implemented by providers that keep the images and binaries clusters are
+// built from in a cache shared by every cluster they create
+type DependencyCacher interface {
+	// WarmCache downloads what a cluster of the Kubernetes version needs without creating one
+	WarmCache(ctx context.Context, version string) error
+	// CacheEntries lists what the cache holds
+	CacheEntries(ctx context.Context) ([]CacheEntry, error)
+	// RemoveCacheEntries deletes the entries from the cache
+	RemoveCacheEntries(ctx context.Context, entries []CacheEntry) error
+}
+
+var _ DependencyCacher = (*LocalProvider)(nil)
+var _ DependencyCacher = (*FakeProvider)(nil)
+
+// WarmCache has minikube download the kicbase image, the preload tarball and the Kubernetes
+// binaries for version, then removes the profile the download left behind
+func (l *LocalProvider) WarmCache(ctx context.Context, version string) error {
+	ctx = subprocess.WithOperation(ctx, "create")
+	version = normalizeCacheVersion(version)
+	progress.Report(ctx, progress.Event{Operation: "cache", Phase: "download", Status: progress.StatusStarted,
+		Message: fmt.Sprintf("Downloading dependencies for Kubernetes %s...", version)})
+	output, err := subprocess.CommandContext(ctx, "minikube", "start", "-p", cacheWarmProfile, "--download-only",
+		"--kubernetes-version="+version).CombinedOutput()
+	subprocess.CommandContext(ctx, "minikube", "delete", "-p", cacheWarmProfile).Run()
+	if err != nil {
+		progress.Report(ctx, progress.Event{Operation: "cache", Phase: "download", Status: progress.StatusFailed})
+		return fmt.Errorf("failed to download dependencies for Kubernetes %s: %w\nOutput: %s", version, err, string(output))
+	}
+	progress.Report(ctx, progress.Event{Operation: "cache", Phase: "download", Status: progress.StatusCompleted})
+	return nil
+}
+
+// CacheEntries lists the minikube download cache
+func (l *LocalProvider) CacheEntries(ctx context.Context) ([]CacheEntry, error) {
+	return scanMinikubeCache(filepath.Join(minikubeHome(), "cache"))
+}
+
+// RemoveCacheEntries deletes entries from the minikube download cache
+func (l *LocalProvider) RemoveCacheEntries(ctx context.Context, entries []CacheEntry) error {
+	return removeCacheEntries(filepath.Join(minikubeHome(), "cache"), entries)
+}
+
+// minikube's cache layout: preload tarballs are named after the Kubernetes version, binaries
+// live in a directory per version and images in a directory per architecture
+var (
+	preloadName  = regexp.MustCompile(`^preloaded-images-k8s-v\d+-(v\d+\.\d+\.\d+[^-]*)-`)
+	binaryParent = regexp.MustCompile(`^v\d+\.\d+\.\d+`)
+)
+
+// scanMinikubeCache lists the entries of a minikube cache directory. A missing directory is an
+// empty cache.
+func scanMinikubeCache(dir string) ([]CacheEntry, error) {
+	var entries []CacheEntry
+	add := func(kind, version, path string, info fs.FileInfo) {
+		entries = append(entries, CacheEntry{Kind: kind, Version: version, Path: path, Size: info.Size(), ModTime: info.ModTime()})
+	}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, ".checksum") || strings.HasSuffix(path, ".download") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		switch {
+		case parts[0] == "preloaded-tarball":
+			if match := preloadName.FindStringSubmatch(d.Name()); match != nil {
+				add(CacheKindPreload, match[1], path, info)
+			}
+		case parts[0] == "kic":
+			add(CacheKindKicbase, "", path, info)
+		case parts[0] == "images":
+			add(CacheKindImage, "", path, info)
+		case len(parts) >= 4 && binaryParent.MatchString(parts[len(parts)-2]):
+			add(CacheKindBinary, parts[len(parts)-2], path, info)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dependency cache %s: %w", dir, err)
+	}
+	return entries, nil
+}
+
+// removeCacheEntries deletes entries, refusing any path outside dir
+func removeCacheEntries(dir string, entries []CacheEntry) error {
+	var failed []string
+	for _, entry := range entries {
+		rel, err := filepath.Rel(dir, entry.Path)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			failed = append(failed, fmt.Sprintf("%s: not in the cache directory", entry.Path))
+			continue
+		}
+		if err := os.Remove(entry.Path); err != nil && !os.IsNotExist(err) {
+			failed = append(failed, fmt.Sprintf("%s: %v", entry.Path, err))
+		}
+		os.Remove(entry.Path + ".checksum")
+		os.Remove(filepath.Dir(entry.Path))
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to remove cache entries: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// PrunableCacheEntries returns the entries belonging to Kubernetes versions not in keep.
+// Entries shared by every version are only pruned with all, which selects everything.
+func PrunableCacheEntries(entries []CacheEntry, keep []string, all bool) []CacheEntry {
+	kept := make([]string, len(keep))
+	for i, version := range keep {
+		kept[i] = normalizeCacheVersion(version)
+	}
+	var prunable []CacheEntry
+	for _, entry := range entries {
+		if all || (entry.Version != "" && !slices.Contains(kept, normalizeCacheVersion(entry.Version))) {
+			prunable = append(prunable, entry)
+		}
+	}
+	return prunable
+}
+
+// normalizeCacheVersion adds the v prefix minikube names cache entries with
+func normalizeCacheVersion(version string) string {
+	if version != "" && !strings.HasPrefix(version, "v") {
+		return "v" + version
+	}
+	return version
+}
+
+// fakeCacheDir is where the fake provider keeps its simulated cache, next to its state file
+func (f *FakeProvider) fakeCacheDir() string {
+	return strings.TrimSuffix(f.opts.StatePath, filepath.Ext(f.opts.StatePath)) + "-cache"
+}
+
+// WarmCache writes placeholder files laid out like minikube's cache for the version
+func (f *FakeProvider) WarmCache(ctx context.Context, version string) error {
+	version = normalizeCacheVersion(version)
+	if !slices.Contains(f.GetSupportedVersions(), strings.TrimPrefix(version, "v")) {
+		return fmt.Errorf("failed to download dependencies for Kubernetes %s: version is not available", version)
+	}
+	progress.Report(ctx, progress.Event{Operation: "cache", Phase: "download", Status: progress.StatusStarted,
+		Message: fmt.Sprintf("Simulating download of dependencies for Kubernetes %s...", version)})
+	if f.shouldFail("cache") {
+		progress.Report(ctx, progress.Event{Operation: "cache", Phase: "download", Status: progress.StatusFailed})
+		return fmt.Errorf("failed to download dependencies for Kubernetes %s: %w", version, f.injectedFailure("cache"))
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(f.opts.Latency):
+	}
+	progress.Report(ctx, progress.Event{Operation: "cache", Phase: "download", Status: progress.StatusCompleted})
+	dir := f.fakeCacheDir()
+	files := map[string]int{
+		filepath.Join("kic", "amd64", "kicbase_v0.0.45.tar"):                                                     4096,
+		filepath.Join("preloaded-tarball", "preloaded-images-k8s-v18-"+version+"-docker-overlay2-amd64.tar.lz4"): 3072,
+	}
+	for _, binary := range []string{"kubeadm", "kubelet", "kubectl"} {
+		files[filepath.Join("linux", "amd64", version, binary)] = 1024
+	}
+	for name, size := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create cache directory: %w", err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			return fmt.Errorf("failed to write cache entry: %w", err)
+		}
+	}
+	return nil
+}
+
+func (f *FakeProvider) CacheEntries(ctx context.Context) ([]CacheEntry, error) {
+	return scanMinikubeCache(f.fakeCacheDir())
+}
+
+func (f *FakeProvider) RemoveCacheEntries(ctx context.Context, entries []CacheEntry) error {
+	return removeCacheEntries(f.fakeCacheDir(), entries)
+}
@@ -0,0 +1,70 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanMinikubeCache(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"kic/amd64/kicbase_v0.0.45@sha256_abc.tar":                                                  CacheKindKicbase,
+		"preloaded-tarball/preloaded-images-k8s-v18-v1.31.0-docker-overlay2-amd64.tar.lz4":          CacheKindPreload,
+		"preloaded-tarball/preloaded-images-k8s-v18-v1.31.0-docker-overlay2-amd64.tar.lz4.checksum": "",
+		"preloaded-tarball/preloaded-images-k8s-v18-v1.29.0-containerd-overlay2-arm64.tar.lz4":      CacheKindPreload,
+		"linux/amd64/v1.29.0/kubelet":                                                               CacheKindBinary,
+		"images/amd64/registry.k8s.io/pause_3.10":                                                   CacheKindImage,
+	}
+	for name := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := scanMinikubeCache(dir)
+	if err != nil {
+		t.Fatalf("scanMinikubeCache() error = %v", err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("scanMinikubeCache() returned %d entries, want 5 without the checksum: %+v", len(entries), entries)
+	}
+	versions := map[string]string{}
+	for _, entry := range entries {
+		rel, _ := filepath.Rel(dir, entry.Path)
+		if want := files[filepath.ToSlash(rel)]; entry.Kind != want || entry.Size != 4 {
+			t.Errorf("scanMinikubeCache() entry %s = %+v, want kind %s", rel, entry, want)
+		}
+		versions[filepath.Base(entry.Path)] = entry.Version
+	}
+	if versions["kubelet"] != "v1.29.0" || versions["preloaded-images-k8s-v18-v1.31.0-docker-overlay2-amd64.tar.lz4"] != "v1.31.0" {
+		t.Errorf("scanMinikubeCache() versions = %v", versions)
+	}
+
+	prunable := PrunableCacheEntries(entries, []string{"1.29.0"}, false)
+	if len(prunable) != 1 || prunable[0].Version != "v1.31.0" {
+		t.Errorf("PrunableCacheEntries() = %+v, want only the v1.31.0 preload", prunable)
+	}
+	if all := PrunableCacheEntries(entries, []string{"v1.29.0"}, true); len(all) != 5 {
+		t.Errorf("PrunableCacheEntries(all) returned %d entries, want 5", len(all))
+	}
+
+	if err := removeCacheEntries(dir, prunable); err != nil {
+		t.Fatalf("removeCacheEntries() error = %v", err)
+	}
+	if _, err := os.Stat(prunable[0].Path); !os.IsNotExist(err) {
+		t.Errorf("removeCacheEntries() left %s", prunable[0].Path)
+	}
+	outside := CacheEntry{Path: filepath.Join(t.TempDir(), "file")}
+	if err := removeCacheEntries(dir, []CacheEntry{outside}); err == nil {
+		t.Error("removeCacheEntries() should refuse paths outside the cache")
+	}
+
+	if entries, err := scanMinikubeCache(filepath.Join(dir, "missing")); err != nil || len(entries) != 0 {
+		t.Errorf("scanMinikubeCache() of a missing directory = %v, %v, want an empty cache", entries, err)
+	}
+}
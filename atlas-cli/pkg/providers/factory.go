@@ -2,6 +2,11 @@ package providers
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
 )
 
 type ProviderFactory struct {
@@ -17,9 +22,29 @@ func NewProviderFactory() *ProviderFactory {
 		return NewLocalProvider()
 	})
 	
+	factory.RegisterProvider("kind", func(region, profile string) Provider {
+		return NewKindProvider()
+	})
+	
+	factory.RegisterProvider("k3d", func(region, profile string) Provider {
+		return NewK3dProvider()
+	})
+	
+	factory.RegisterProvider("kubeadm", func(region, profile string) Provider {
+		return NewKubeadmProvider()
+	})
+	
+	factory.RegisterProvider("capi", func(region, profile string) Provider {
+		return NewClusterAPIProvider(region)
+	})
+	
 	factory.RegisterProvider("aws", func(region, profile string) Provider {
 		return NewAWSProvider(profile, region)
 	})
+
+	factory.RegisterProvider("fake", func(region, profile string) Provider {
+		return NewFakeProvider(FakeOptionsFromEnv())
+	})
 	
 	return factory
 }
@@ -35,19 +60,26 @@ func (f *ProviderFactory) CreateProvider(name, region, profile string) (Provider
 	}
 	
 	if region == "" {
-		switch name {
-		case "local":
-			region = "local"
-		case "aws":
-			region = "us-west-2"
-		default:
-			region = "default"
-		}
+		region = DefaultRegion(name)
 	}
 	
 	return constructor(region, profile), nil
 }
 
+// DefaultRegion returns the region CreateProvider gives the named provider when none is set
+func DefaultRegion(name string) string {
+	switch name {
+	case "local", "kind", "k3d":
+		return "local"
+	case "aws":
+		return "us-west-2"
+	case "kubeadm":
+		return "homelab"
+	default:
+		return "default"
+	}
+}
+
 func (f *ProviderFactory) GetSupportedProviders() []string {
 	var providers []string
 	for name := range f.providers {
@@ -56,6 +88,39 @@ func (f *ProviderFactory) GetSupportedProviders() []string {
 	return providers
 }
 
+// RunsOnHost reports whether the named provider runs clusters on this machine, where they share
+// host ports and resources
+func RunsOnHost(providerName string) bool {
+	return providerName == "local" || providerName == "kind" || providerName == "k3d"
+}
+
 func GetDefaultProviderFactory() *ProviderFactory {
 	return NewProviderFactory()
+}
+
+// DemoLatency is how long each simulated phase takes in demo mode unless ATLAS_FAKE_LATENCY is set
+const DemoLatency = 800 * time.Millisecond
+
+// NewDemoProviderFactory returns a factory where every provider is simulated, keeping each one's
+// clusters in dir/<provider>.json so commands can be demonstrated without minikube or cloud accounts
+func NewDemoProviderFactory(dir string) *ProviderFactory {
+	factory := NewProviderFactory()
+	for _, name := range factory.GetSupportedProviders() {
+		name := name
+		factory.RegisterProvider(name, func(region, profile string) Provider {
+			opts := FakeOptionsFromEnv()
+			opts.Name = name
+			opts.StatePath = filepath.Join(dir, name+".json")
+			if os.Getenv(FakeLatencyEnvVar) == "" {
+				opts.Latency = DemoLatency
+			}
+			return NewFakeProvider(opts)
+		})
+	}
+	return factory
+}
+
+// DefaultDemoDir returns where demo mode keeps its simulated clusters
+func DefaultDemoDir() (string, error) {
+	return store.Path("demo")
 }
\ No newline at end of file
@@ -0,0 +1,869 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+)
+
+const (
+	// FakeStateEnvVar overrides where the fake provider keeps its clusters
+	FakeStateEnvVar = "ATLAS_FAKE_STATE"
+	// FakeLatencyEnvVar sets how long each simulated phase takes, e.g. "2s"
+	FakeLatencyEnvVar = "ATLAS_FAKE_LATENCY"
+	// FakeFailEnvVar lists operations that fail, e.g. "create,scale"
+	FakeFailEnvVar = "ATLAS_FAKE_FAIL"
+	// FakeFailOutputEnvVar is tool output to include in injected failures, e.g. a minikube error
+	FakeFailOutputEnvVar = "ATLAS_FAKE_FAIL_OUTPUT"
+)
+
+// FakeOptions configures the simulated provider
+type FakeOptions struct {
+	// Name is reported as the provider of every cluster, "fake" by default
+	Name string
+	// StatePath is the file clusters and history persist in, so separate CLI runs share them
+	StatePath string
+	// Latency is how long each lifecycle phase takes
+	Latency time.Duration
+	// FailOn lists operations that fail: create, delete, start, stop, scale, rename, upgrade, addon,
+	// nodepool, health, auth, get. workloads leaves the demo app's deployment short of replicas.
+	FailOn []string
+	// FailOutput is appended to injected failures as if a real tool had printed it
+	FailOutput string
+}
+
+// DefaultFakeStatePath returns the state file used when no path is configured
+func DefaultFakeStatePath() (string, error) {
+	return store.Path("fake", "state.json")
+}
+
+// FakeOptionsFromEnv reads the fake provider's options from ATLAS_FAKE_* environment variables
+func FakeOptionsFromEnv() FakeOptions {
+	opts := FakeOptions{StatePath: os.Getenv(FakeStateEnvVar), FailOutput: os.Getenv(FakeFailOutputEnvVar)}
+	if latency, err := time.ParseDuration(os.Getenv(FakeLatencyEnvVar)); err == nil {
+		opts.Latency = latency
+	}
+	for _, op := range strings.Split(os.Getenv(FakeFailEnvVar), ",") {
+		if op = strings.TrimSpace(op); op != "" {
+			opts.FailOn = append(opts.FailOn, op)
+		}
+	}
+	return opts
+}
+
+// FakeProvider simulates a cluster lifecycle without creating anything, for end-to-end tests
+// and demos. Clusters live in a JSON file so they survive between CLI invocations.
+type FakeProvider struct {
+	opts      FakeOptions
+	monitor   *fakeMonitor
+	logSource *fakeLogSource
+	// stateErr is why there is no state file to use, when none was configured
+	stateErr error
+}
+
+// fakeStateLocks serializes access to each state file. Fleets run one provider instance per
+// member concurrently, so the lock can't live on the instance.
+var fakeStateLocks sync.Map
+
+func (f *FakeProvider) stateLock() *sync.Mutex {
+	lock, _ := fakeStateLocks.LoadOrStore(f.opts.StatePath, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
+
+type fakeState struct {
+	Clusters map[string]*Cluster           `json:"clusters"`
+	History  []*logsource.OperationHistory `json:"history"`
+	// Addons lists the addons enabled on each cluster
+	Addons map[string][]string `json:"addons,omitempty"`
+	// Networks holds the network settings each cluster was created with
+	Networks map[string]*NetworkConfig `json:"networks,omitempty"`
+	// NodePools holds the node pools added to each cluster besides its default one
+	NodePools map[string][]*NodePool `json:"nodePools,omitempty"`
+}
+
+// NewFakeProvider creates a fake provider with the given options
+func NewFakeProvider(opts FakeOptions) *FakeProvider {
+	if opts.Name == "" {
+		opts.Name = "fake"
+	}
+	var stateErr error
+	if opts.StatePath == "" {
+		opts.StatePath, stateErr = DefaultFakeStatePath()
+	}
+	f := &FakeProvider{opts: opts, stateErr: stateErr}
+	f.monitor = &fakeMonitor{provider: f}
+	f.logSource = &fakeLogSource{provider: f}
+	return f
+}
+
+func (f *FakeProvider) GetProviderName() string {
+	return f.opts.Name
+}
+
+func (f *FakeProvider) GetSupportedRegions() []string {
+	return []string{"fake-east-1", "fake-west-1"}
+}
+
+func (f *FakeProvider) GetSupportedVersions() []string {
+	return []string{"1.31.0", "1.30.0", "1.29.0"}
+}
+
+func (f *FakeProvider) GetLogSource() logsource.LogSource {
+	return f.logSource
+}
+
+func (f *FakeProvider) GetMonitor() monitoring.Monitor {
+	return f.monitor
+}
+
+func (f *FakeProvider) HealthCheck(ctx context.Context, clusterName string) (*monitoring.HealthStatus, error) {
+	return f.monitor.CheckClusterHealth(ctx, clusterName)
+}
+
+func (f *FakeProvider) ValidateConfig(config *ClusterConfig) error {
+	return f.Validate(config).Err()
+}
+
+// Validate applies the checks every real provider shares
+func (f *FakeProvider) Validate(config *ClusterConfig) *ValidationResult {
+	result := &ValidationResult{}
+	if config.Name == "" {
+		result.Errorf("name", "cluster name is required")
+	}
+	if config.NodeCount < 1 || config.NodeCount > 100 {
+		result.Errorf("nodeCount", "node count must be between 1 and 100")
+	}
+	if _, err := ResolveTags(config); err != nil {
+		result.Errorf("tags", "%v", err)
+	}
+	validateLogConfig(config, f.opts.Name == "aws", result)
+	validateTracingConfig(config, result)
+	return result
+}
+
+// CheckCredentials reports a demo identity, or expired credentials when auth is set to fail
+func (f *FakeProvider) CheckCredentials(ctx context.Context) (*Identity, error) {
+	if f.shouldFail("auth") {
+		return nil, fmt.Errorf("simulated %s credentials have expired", f.opts.Name)
+	}
+	expiresAt := time.Now().Add(time.Hour).UTC()
+	return &Identity{Provider: f.opts.Name, Principal: "demo-user", Account: "demo", ExpiresAt: &expiresAt}, nil
+}
+
+func (f *FakeProvider) CreateCluster(ctx context.Context, config *ClusterConfig) (*Cluster, error) {
+	if err := f.ValidateConfig(config); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+	if _, err := f.GetCluster(ctx, config.Name); err == nil {
+		return nil, fmt.Errorf("cluster %s already exists", config.Name)
+	}
+
+	tags, err := ResolveTags(config)
+	if err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	err = f.simulate(ctx, config.Name, "create", "provision", "configure")
+	if err == nil {
+		err = f.update(func(state *fakeState) error {
+			region := config.Region
+			if region == "" {
+				region = f.GetSupportedRegions()[0]
+			}
+			version := config.Version
+			if version == "" {
+				version = f.GetSupportedVersions()[0]
+			}
+			now := time.Now()
+			state.Clusters[config.Name] = &Cluster{
+				Name:      config.Name,
+				Provider:  f.opts.Name,
+				Region:    region,
+				Version:   version,
+				Status:    ClusterStatusRunning,
+				NodeCount: config.NodeCount,
+				Endpoint:  fmt.Sprintf("https://%s.fake.local:6443", config.Name),
+				CreatedAt: now,
+				UpdatedAt: now,
+				Tags:      tags,
+			}
+			if state.Networks == nil {
+				state.Networks = make(map[string]*NetworkConfig)
+			}
+			state.Networks[config.Name] = fakeNetwork(config.NetworkConfig)
+			return nil
+		})
+	}
+	f.record(config.Name, fakeOperationTypes["create"], started, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cluster %s: %w", config.Name, err)
+	}
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "done", Status: progress.StatusCompleted,
+		Message: fmt.Sprintf("Successfully created cluster: %s", config.Name)})
+	return f.GetCluster(ctx, config.Name)
+}
+
+func (f *FakeProvider) DeleteCluster(ctx context.Context, name string) error {
+	return f.lifecycle(ctx, name, "delete", func(state *fakeState, cluster *Cluster) error {
+		delete(state.Clusters, name)
+		delete(state.Addons, name)
+		delete(state.Networks, name)
+		delete(state.NodePools, name)
+		return nil
+	}, "drain", "deprovision")
+}
+
+func (f *FakeProvider) StartCluster(ctx context.Context, name string) error {
+	return f.lifecycle(ctx, name, "start", func(state *fakeState, cluster *Cluster) error {
+		cluster.Status = ClusterStatusRunning
+		return nil
+	}, "start")
+}
+
+func (f *FakeProvider) StopCluster(ctx context.Context, name string) error {
+	return f.lifecycle(ctx, name, "stop", func(state *fakeState, cluster *Cluster) error {
+		cluster.Status = ClusterStatusStopped
+		return nil
+	}, "stop")
+}
+
+func (f *FakeProvider) ScaleCluster(ctx context.Context, name string, nodeCount int) error {
+	if nodeCount < 1 || nodeCount > 100 {
+		return fmt.Errorf("node count must be between 1 and 100")
+	}
+	return f.lifecycle(ctx, name, "scale", func(state *fakeState, cluster *Cluster) error {
+		cluster.NodeCount = nodeCount
+		return nil
+	}, "scale")
+}
+
+// RenameCluster renames the simulated cluster and carries its history over
+func (f *FakeProvider) RenameCluster(ctx context.Context, oldName, newName string) error {
+	err := f.lifecycle(ctx, oldName, "rename", func(state *fakeState, cluster *Cluster) error {
+		if _, exists := state.Clusters[newName]; exists {
+			return fmt.Errorf("cluster %s already exists", newName)
+		}
+		delete(state.Clusters, oldName)
+		if addons, ok := state.Addons[oldName]; ok {
+			delete(state.Addons, oldName)
+			state.Addons[newName] = addons
+		}
+		if network, ok := state.Networks[oldName]; ok {
+			delete(state.Networks, oldName)
+			state.Networks[newName] = network
+		}
+		if pools, ok := state.NodePools[oldName]; ok {
+			delete(state.NodePools, oldName)
+			state.NodePools[newName] = pools
+		}
+		cluster.Name = newName
+		cluster.Endpoint = fmt.Sprintf("https://%s.fake.local:6443", newName)
+		state.Clusters[newName] = cluster
+		return nil
+	}, "rename")
+	if err != nil {
+		return err
+	}
+	return f.update(func(state *fakeState) error {
+		for _, op := range state.History {
+			if op.ClusterName == oldName {
+				op.ClusterName = newName
+			}
+		}
+		return nil
+	})
+}
+
+func (f *FakeProvider) GetCluster(ctx context.Context, name string) (*Cluster, error) {
+	state, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	cluster, exists := state.Clusters[name]
+	if !exists {
+		return nil, clusterNotFound(name)
+	}
+	if f.shouldFail("get") {
+		return nil, f.injectedFailure("get")
+	}
+	return cluster, nil
+}
+
+func (f *FakeProvider) ListClusters(ctx context.Context) ([]*Cluster, error) {
+	state, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	clusters := make([]*Cluster, 0, len(state.Clusters))
+	for _, cluster := range state.Clusters {
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
+	return clusters, nil
+}
+
+// fakeAddons are the addons a simulated cluster offers
+var fakeAddons = []string{"dashboard", "ingress", "metrics-server"}
+
+// ListAddons reports which of the simulated addons are enabled
+func (f *FakeProvider) ListAddons(ctx context.Context, name string) ([]Addon, error) {
+	state, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := state.Clusters[name]; !exists {
+		return nil, clusterNotFound(name)
+	}
+	addons := make([]Addon, len(fakeAddons))
+	for i, addon := range fakeAddons {
+		addons[i] = Addon{Name: addon, Enabled: slices.Contains(state.Addons[name], addon)}
+	}
+	return addons, nil
+}
+
+// EnableAddon enables one of the simulated addons
+func (f *FakeProvider) EnableAddon(ctx context.Context, name, addon string) error {
+	if !slices.Contains(fakeAddons, addon) {
+		return fmt.Errorf("unknown addon %s; available: %s", addon, strings.Join(fakeAddons, ", "))
+	}
+	return f.lifecycle(ctx, name, "addon", func(state *fakeState, cluster *Cluster) error {
+		if state.Addons == nil {
+			state.Addons = make(map[string][]string)
+		}
+		if !slices.Contains(state.Addons[name], addon) {
+			state.Addons[name] = append(state.Addons[name], addon)
+		}
+		return nil
+	}, "addon")
+}
+
+// DisableAddon disables one of the simulated addons
+func (f *FakeProvider) DisableAddon(ctx context.Context, name, addon string) error {
+	if !slices.Contains(fakeAddons, addon) {
+		return fmt.Errorf("unknown addon %s; available: %s", addon, strings.Join(fakeAddons, ", "))
+	}
+	return f.lifecycle(ctx, name, "addon", func(state *fakeState, cluster *Cluster) error {
+		if enabled, ok := state.Addons[name]; ok {
+			state.Addons[name] = slices.DeleteFunc(enabled, func(enabled string) bool { return enabled == addon })
+		}
+		return nil
+	}, "addon")
+}
+
+// fakeNetwork is the network a simulated cluster runs with: the requested settings, with minikube's
+// defaults for the rest
+func fakeNetwork(requested *NetworkConfig) *NetworkConfig {
+	network := &NetworkConfig{PodCIDR: "10.244.0.0/16", ServiceCIDR: "10.96.0.0/12", NetworkPlugin: "auto", APIServerPort: 8443}
+	if requested == nil {
+		return network
+	}
+	if requested.PodCIDR != "" {
+		network.PodCIDR = requested.PodCIDR
+	}
+	if requested.ServiceCIDR != "" {
+		network.ServiceCIDR = requested.ServiceCIDR
+	}
+	if requested.NetworkPlugin != "" {
+		network.NetworkPlugin = requested.NetworkPlugin
+	}
+	if requested.APIServerPort > 0 {
+		network.APIServerPort = requested.APIServerPort
+	}
+	return network
+}
+
+// GetNetworkConfig reports the network settings the simulated cluster was created with
+func (f *FakeProvider) GetNetworkConfig(ctx context.Context, name string) (*NetworkConfig, error) {
+	state, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := state.Clusters[name]; !exists {
+		return nil, clusterNotFound(name)
+	}
+	if network, ok := state.Networks[name]; ok {
+		return network, nil
+	}
+	return fakeNetwork(nil), nil
+}
+
+// fakeDefaultPool is the node pool every simulated cluster is created with, sized by its node count
+func fakeDefaultPool(cluster *Cluster) *NodePool {
+	return &NodePool{
+		Name:         cluster.Name + "-nodes",
+		Status:       "active",
+		OS:           NodeOSLinux,
+		InstanceType: "fake.medium",
+		Scaling:      NodePoolScaling{MinSize: 1, MaxSize: cluster.NodeCount, DesiredSize: cluster.NodeCount},
+	}
+}
+
+// ListNodePools returns the cluster's default node pool and any added ones
+func (f *FakeProvider) ListNodePools(ctx context.Context, clusterName string) ([]NodePool, error) {
+	state, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	cluster, exists := state.Clusters[clusterName]
+	if !exists {
+		return nil, clusterNotFound(clusterName)
+	}
+	pools := []NodePool{*fakeDefaultPool(cluster)}
+	for _, pool := range state.NodePools[clusterName] {
+		pools = append(pools, *pool)
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Name < pools[j].Name })
+	return pools, nil
+}
+
+// CreateNodePool adds a simulated node pool to the cluster
+func (f *FakeProvider) CreateNodePool(ctx context.Context, clusterName string, config NodePoolConfig) error {
+	if err := config.Scaling.Validate(); err != nil {
+		return err
+	}
+	return f.lifecycle(ctx, clusterName, "nodepool", func(state *fakeState, cluster *Cluster) error {
+		if config.Name == fakeDefaultPool(cluster).Name || fakeFindPool(state, clusterName, config.Name) >= 0 {
+			return fmt.Errorf("node pool %s already exists", config.Name)
+		}
+		existing := []NodePool{*fakeDefaultPool(cluster)}
+		for _, pool := range state.NodePools[clusterName] {
+			existing = append(existing, *pool)
+		}
+		config, err := resolveNodePoolOS(config, cluster.Version, existing)
+		if err != nil {
+			return err
+		}
+		instanceType := config.InstanceType
+		if instanceType == "" {
+			instanceType = "fake.medium"
+		}
+		if state.NodePools == nil {
+			state.NodePools = make(map[string][]*NodePool)
+		}
+		state.NodePools[clusterName] = append(state.NodePools[clusterName], &NodePool{
+			Name: config.Name, Status: "active", OS: config.OS, AMIType: config.AMIType, InstanceType: instanceType,
+			Scaling: config.Scaling, Labels: config.Labels, Taints: config.Taints,
+		})
+		return nil
+	}, "provision", "join")
+}
+
+// ScaleNodePool resizes a simulated node pool; resizing the default pool changes the cluster's
+// node count
+func (f *FakeProvider) ScaleNodePool(ctx context.Context, clusterName, pool string, scaling NodePoolScaling) error {
+	if err := scaling.Validate(); err != nil {
+		return err
+	}
+	return f.lifecycle(ctx, clusterName, "nodepool", func(state *fakeState, cluster *Cluster) error {
+		if pool == fakeDefaultPool(cluster).Name {
+			if scaling.DesiredSize < 1 {
+				return fmt.Errorf("the default node pool needs at least 1 node")
+			}
+			cluster.NodeCount = scaling.DesiredSize
+			return nil
+		}
+		i := fakeFindPool(state, clusterName, pool)
+		if i < 0 {
+			return fmt.Errorf("node pool %s does not exist", pool)
+		}
+		state.NodePools[clusterName][i].Scaling = scaling
+		return nil
+	}, "scale")
+}
+
+// DeleteNodePool removes a simulated node pool; the default pool can't be deleted
+func (f *FakeProvider) DeleteNodePool(ctx context.Context, clusterName, pool string) error {
+	return f.lifecycle(ctx, clusterName, "nodepool", func(state *fakeState, cluster *Cluster) error {
+		if pool == fakeDefaultPool(cluster).Name {
+			return fmt.Errorf("node pool %s is the cluster's default pool; scale the cluster instead", pool)
+		}
+		i := fakeFindPool(state, clusterName, pool)
+		if i < 0 {
+			return fmt.Errorf("node pool %s does not exist", pool)
+		}
+		pools := state.NodePools[clusterName]
+		state.NodePools[clusterName] = append(pools[:i], pools[i+1:]...)
+		return nil
+	}, "drain", "deprovision")
+}
+
+func fakeFindPool(state *fakeState, clusterName, pool string) int {
+	for i, existing := range state.NodePools[clusterName] {
+		if existing.Name == pool {
+			return i
+		}
+	}
+	return -1
+}
+
+// fakeOperationTypes maps the operations FailOn understands to their history entry type
+var fakeOperationTypes = map[string]logsource.OperationType{
+	"create":   logsource.OpTypeCreate,
+	"delete":   logsource.OpTypeDelete,
+	"start":    logsource.OpTypeStart,
+	"stop":     logsource.OpTypeStop,
+	"scale":    logsource.OpTypeScale,
+	"rename":   logsource.OpTypeUpdate,
+	"upgrade":  logsource.OpTypeUpdate,
+	"addon":    logsource.OpTypeUpdate,
+	"nodepool": logsource.OpTypeUpdate,
+}
+
+// lifecycle runs a simulated operation against an existing cluster and records it in history
+// QueryLogs returns a few simulated request log lines from pods matching the selector
+func (f *FakeProvider) QueryLogs(ctx context.Context, clusterName string, query LogQuery) ([]LogEntry, error) {
+	if _, err := f.GetCluster(ctx, clusterName); err != nil {
+		return nil, err
+	}
+	if f.shouldFail("logs") {
+		return nil, fmt.Errorf("simulated log query failure for cluster %s", clusterName)
+	}
+
+	app := query.Selector["app"]
+	if app == "" {
+		app = "demo"
+	}
+	now := time.Now().UTC()
+	paths := []string{"/healthz", "/api/orders", "/api/orders/42", "/metrics"}
+	var entries []LogEntry
+	for i := 0; i < len(paths) && (query.Limit <= 0 || i < query.Limit); i++ {
+		labels := map[string]string{"namespace": "default", "pod": app + "-7d9f8b-x2k4q", "container": app}
+		for key, value := range query.Selector {
+			labels[key] = value
+		}
+		entries = append(entries, LogEntry{
+			Time:   now.Add(time.Duration(i-len(paths)) * time.Second),
+			Labels: labels,
+			Line:   fmt.Sprintf("GET %s 200", paths[i]),
+		})
+	}
+	return entries, nil
+}
+
+// ServiceURLs returns a node port URL for any service on a running cluster
+func (f *FakeProvider) ServiceURLs(ctx context.Context, clusterName, namespace, service string) ([]string, error) {
+	cluster, err := f.GetCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.Status != ClusterStatusRunning {
+		return nil, fmt.Errorf("cluster %s is not running", clusterName)
+	}
+	return []string{fmt.Sprintf("http://%s.fake.local:30080", clusterName), fmt.Sprintf("http://%s.%s.%s.fake.local", service, namespace, clusterName)}, nil
+}
+
+func (f *FakeProvider) lifecycle(ctx context.Context, name, operation string, apply func(state *fakeState, cluster *Cluster) error, phases ...string) error {
+	if _, err := f.GetCluster(ctx, name); err != nil {
+		return err
+	}
+
+	started := time.Now()
+	err := f.simulate(ctx, name, operation, phases...)
+	if err == nil {
+		err = f.update(func(state *fakeState) error {
+			cluster, exists := state.Clusters[name]
+			if !exists {
+				return clusterNotFound(name)
+			}
+			cluster.UpdatedAt = time.Now()
+			return apply(state, cluster)
+		})
+	}
+	f.record(name, fakeOperationTypes[operation], started, err)
+	if err != nil {
+		return fmt.Errorf("failed to %s cluster %s: %w", operation, name, err)
+	}
+	return nil
+}
+
+// simulate reports each phase, waiting the configured latency, and fails the first phase when
+// the operation is listed in FailOn
+func (f *FakeProvider) simulate(ctx context.Context, cluster, operation string, phases ...string) error {
+	for _, phase := range phases {
+		progress.Report(ctx, progress.Event{Cluster: cluster, Operation: operation, Phase: phase, Status: progress.StatusStarted,
+			Message: fmt.Sprintf("Simulating %s of cluster %s (%s)...", operation, cluster, phase)})
+		if f.shouldFail(operation) {
+			progress.Report(ctx, progress.Event{Cluster: cluster, Operation: operation, Phase: phase, Status: progress.StatusFailed,
+				Message: fmt.Sprintf("injected %s failure", operation)})
+			return f.injectedFailure(operation)
+		}
+		select {
+		case <-ctx.Done():
+			progress.Report(ctx, progress.Event{Cluster: cluster, Operation: operation, Phase: phase, Status: progress.StatusFailed})
+			return ctx.Err()
+		case <-time.After(f.opts.Latency):
+		}
+		progress.Report(ctx, progress.Event{Cluster: cluster, Operation: operation, Phase: phase, Status: progress.StatusCompleted})
+	}
+	return nil
+}
+
+// injectedFailure is the error an operation listed in FailOn returns
+func (f *FakeProvider) injectedFailure(operation string) error {
+	if f.opts.FailOutput != "" {
+		return fmt.Errorf("injected %s failure\nOutput: %s", operation, f.opts.FailOutput)
+	}
+	return fmt.Errorf("injected %s failure", operation)
+}
+
+func (f *FakeProvider) shouldFail(operation string) bool {
+	for _, op := range f.opts.FailOn {
+		if op == operation {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FakeProvider) record(cluster string, opType logsource.OperationType, started time.Time, opErr error) {
+	completed := time.Now()
+	duration := float64(completed.Sub(started).Milliseconds())
+	entry := &logsource.OperationHistory{
+		ClusterName:     cluster,
+		OperationType:   opType,
+		OperationStatus: logsource.OpStatusCompleted,
+		StartedAt:       started,
+		CompletedAt:     &completed,
+		DurationMS:      &duration,
+		UserID:          currentUser(),
+	}
+	if opErr != nil {
+		entry.OperationStatus = logsource.OpStatusFailed
+		entry.ErrorMessage = opErr.Error()
+	}
+	f.update(func(state *fakeState) error {
+		entry.ID = len(state.History) + 1
+		state.History = append(state.History, entry)
+		return nil
+	})
+}
+
+func (f *FakeProvider) load() (*fakeState, error) {
+	lock := f.stateLock()
+	lock.Lock()
+	defer lock.Unlock()
+	return f.loadLocked()
+}
+
+func (f *FakeProvider) loadLocked() (*fakeState, error) {
+	if f.stateErr != nil {
+		return nil, f.stateErr
+	}
+	state := &fakeState{Clusters: make(map[string]*Cluster)}
+	if err := store.LoadJSON(f.opts.StatePath, state); err != nil {
+		return nil, err
+	}
+	if state.Clusters == nil {
+		state.Clusters = make(map[string]*Cluster)
+	}
+	return state, nil
+}
+
+func (f *FakeProvider) update(apply func(state *fakeState) error) error {
+	lock := f.stateLock()
+	lock.Lock()
+	defer lock.Unlock()
+
+	if f.stateErr != nil {
+		return f.stateErr
+	}
+	state := &fakeState{}
+	return store.UpdateJSON(f.opts.StatePath, state, func() error {
+		if state.Clusters == nil {
+			state.Clusters = make(map[string]*Cluster)
+		}
+		return apply(state)
+	})
+}
+
+// fakeMonitor reports every running cluster healthy and stopped clusters unhealthy
+type fakeMonitor struct {
+	provider *FakeProvider
+}
+
+func (m *fakeMonitor) GetMonitorName() string {
+	return "fake"
+}
+
+func (m *fakeMonitor) CheckClusterHealth(ctx context.Context, clusterName string) (*monitoring.HealthStatus, error) {
+	cluster, err := m.provider.GetCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if m.provider.shouldFail("health") {
+		return nil, fmt.Errorf("injected health failure")
+	}
+
+	now := time.Now()
+	status := &monitoring.HealthStatus{
+		ClusterName:   clusterName,
+		OverallStatus: monitoring.HealthStatusHealthy,
+		LastChecked:   now,
+		CheckDuration: m.provider.opts.Latency,
+		Warnings:      []string{},
+		Errors:        []string{},
+	}
+	if cluster.Status != ClusterStatusRunning {
+		status.OverallStatus = monitoring.HealthStatusUnhealthy
+		status.Errors = append(status.Errors, fmt.Sprintf("cluster is %s", cluster.Status))
+		return status, nil
+	}
+
+	healthy := monitoring.ComponentStatus{Status: monitoring.ComponentHealthy, LastCheck: now}
+	status.ControlPlane = &monitoring.ControlPlaneHealth{APIServer: healthy, Scheduler: healthy, ControllerManager: healthy, Etcd: healthy}
+	for _, node := range fakeNodeNames(cluster) {
+		status.Nodes = append(status.Nodes, monitoring.NodeHealth{
+			Name: node, Status: monitoring.NodeHealthy, Ready: true, Version: "v" + cluster.Version, LastChecked: now,
+		})
+	}
+	pods := 8 + 2*cluster.NodeCount
+	status.Pods = &monitoring.PodHealth{TotalPods: pods, RunningPods: pods, PodsByPhase: map[string]int{"Running": pods}}
+	status.Services = &monitoring.ServiceHealth{TotalServices: 3, HealthyServices: 3}
+	status.Workloads = &monitoring.WorkloadHealth{
+		Deployments: monitoring.WorkloadCounts{Total: 2, Healthy: 2},
+		DaemonSets:  monitoring.WorkloadCounts{Total: 1, Healthy: 1},
+	}
+	if m.provider.shouldFail("workloads") {
+		status.Workloads.Deployments.Healthy--
+		status.Workloads.Degraded = append(status.Workloads.Degraded, monitoring.DegradedWorkload{
+			Kind: "Deployment", Namespace: "default", Name: "demo", Desired: 2, Ready: 1, Message: "1/2 replicas available",
+		})
+		status.OverallStatus = monitoring.HealthStatusWarning
+	}
+
+	// kubeadm issues certificates valid for a year from cluster creation
+	expires := cluster.CreatedAt.AddDate(1, 0, 0).UTC().Truncate(time.Second)
+	status.Certificates = []monitoring.ClusterCertificate{
+		{Name: "apiserver", Source: monitoring.CertificateSourceAPIServer, Subject: "CN=minikube", NotAfter: expires},
+	}
+	for _, name := range []string{"apiserver", "apiserver-kubelet-client", "etcd/server", "front-proxy-client"} {
+		status.Certificates = append(status.Certificates, monitoring.ClusterCertificate{
+			Name: name, Source: monitoring.CertificateSourceKubeadm, NotAfter: expires,
+		})
+	}
+	monitoring.FlagExpiringCertificates(status, monitoring.CertificateWarningFrom(ctx), now)
+	if len(status.Errors) > 0 {
+		status.OverallStatus = monitoring.HealthStatusUnhealthy
+	} else if len(status.Warnings) > 0 {
+		status.OverallStatus = monitoring.HealthStatusWarning
+	}
+	return status, nil
+}
+
+func (m *fakeMonitor) GetClusterMetrics(ctx context.Context, clusterName string) (*monitoring.ClusterMetrics, error) {
+	cluster, err := m.provider.GetCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.Status != ClusterStatusRunning {
+		return nil, fmt.Errorf("cluster %s is not running", clusterName)
+	}
+
+	now := time.Now()
+	metrics := &monitoring.ClusterMetrics{ClusterName: clusterName, Timestamp: now}
+	for _, node := range fakeNodeNames(cluster) {
+		metrics.NodeMetrics = append(metrics.NodeMetrics, monitoring.NodeMetrics{
+			NodeName:    node,
+			CPUUsage:    monitoring.ResourceValue{Value: "500m", Usage: 25},
+			MemoryUsage: monitoring.ResourceValue{Value: "1Gi", Usage: 40},
+			Timestamp:   now,
+		})
+	}
+	metrics.ResourceUsage = &monitoring.ResourceUsage{CPUPercentage: 25, MemoryPercentage: 40}
+	return metrics, nil
+}
+
+// ListRoutes reports a TLS ingress for the demo app and a plain HTTP route whose backend has no
+// ready endpoints
+func (m *fakeMonitor) ListRoutes(ctx context.Context, clusterName string) ([]monitoring.Route, error) {
+	if _, err := m.provider.GetCluster(ctx, clusterName); err != nil {
+		return nil, err
+	}
+	host := clusterName + ".fake.local"
+	return []monitoring.Route{
+		{
+			Kind: "Ingress", Namespace: "default", Name: "demo", Host: host, Path: "/", Backend: "demo:80",
+			Health: monitoring.BackendHealth{Status: monitoring.BackendHealthy, ReadyEndpoints: 2, TotalEndpoints: 2},
+			TLS: &monitoring.Certificate{Secret: "default/demo-tls", Issuer: "CN=Demo CA", Subject: "CN=" + host,
+				DNSNames: []string{host}, NotAfter: time.Now().Add(60 * 24 * time.Hour).UTC().Truncate(time.Second)},
+		},
+		{
+			Kind: "HTTPRoute", Namespace: "default", Name: "admin", Host: host, Path: "/admin", Backend: "admin:8080",
+			Health: monitoring.BackendHealth{Status: monitoring.BackendUnhealthy, TotalEndpoints: 1},
+		},
+	}, nil
+}
+
+func (m *fakeMonitor) StartMonitoring(ctx context.Context, config *monitoring.MonitoringConfig) error {
+	return nil
+}
+
+func (m *fakeMonitor) StopMonitoring(ctx context.Context, clusterName string) error {
+	return nil
+}
+
+// fakeNodeNames follows minikube's naming: the first node is the cluster name, then name-m02...
+func fakeNodeNames(cluster *Cluster) []string {
+	names := []string{cluster.Name}
+	for i := 2; i <= cluster.NodeCount; i++ {
+		names = append(names, fmt.Sprintf("%s-m%02d", cluster.Name, i))
+	}
+	return names
+}
+
+// fakeLogSource serves the history the fake provider records for each operation
+type fakeLogSource struct {
+	provider *FakeProvider
+}
+
+func (s *fakeLogSource) GetSourceName() string {
+	return "fake"
+}
+
+func (s *fakeLogSource) GetClusterHistory(ctx context.Context, clusterName string, limit int) ([]*logsource.OperationHistory, error) {
+	all, err := s.GetAllClustersHistory(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	history := all[clusterName]
+	if limit > 0 && len(history) > limit {
+		history = history[:limit]
+	}
+	return history, nil
+}
+
+// GetAllClustersHistory returns each cluster's operations newest first
+func (s *fakeLogSource) GetAllClustersHistory(ctx context.Context, limit int) (map[string][]*logsource.OperationHistory, error) {
+	state, err := s.provider.load()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]*logsource.OperationHistory)
+	for i := len(state.History) - 1; i >= 0; i-- {
+		op := state.History[i]
+		if limit > 0 && len(result[op.ClusterName]) >= limit {
+			continue
+		}
+		result[op.ClusterName] = append(result[op.ClusterName], op)
+	}
+	return result, nil
+}
+
+var _ Provider = (*FakeProvider)(nil)
+var _ ClusterRenamer = (*FakeProvider)(nil)
+var _ LogQuerier = (*FakeProvider)(nil)
+var _ ServiceURLResolver = (*FakeProvider)(nil)
+var _ monitoring.Monitor = (*fakeMonitor)(nil)
+var _ monitoring.RouteLister = (*fakeMonitor)(nil)
+var _ logsource.LogSource = (*fakeLogSource)(nil)
@@ -0,0 +1,140 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+)
+
+func TestFakeProvider_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	p := NewFakeProvider(FakeOptions{StatePath: statePath})
+
+	if _, err := p.CreateCluster(ctx, &ClusterConfig{Name: "dev", NodeCount: 2}); err != nil {
+		t.Fatalf("CreateCluster() error = %v", err)
+	}
+	if _, err := p.CreateCluster(ctx, &ClusterConfig{Name: "dev", NodeCount: 1}); err == nil {
+		t.Error("CreateCluster() should fail for an existing cluster")
+	}
+
+	// A second provider on the same state file sees the cluster, as a later CLI run would
+	reopened := NewFakeProvider(FakeOptions{StatePath: statePath})
+	cluster, err := reopened.GetCluster(ctx, "dev")
+	if err != nil {
+		t.Fatalf("GetCluster() error = %v", err)
+	}
+	if cluster.Status != ClusterStatusRunning || cluster.NodeCount != 2 || cluster.Provider != "fake" {
+		t.Errorf("GetCluster() = %+v, want running fake cluster with 2 nodes", cluster)
+	}
+
+	if err := p.ScaleCluster(ctx, "dev", 3); err != nil {
+		t.Fatalf("ScaleCluster() error = %v", err)
+	}
+	health, err := p.HealthCheck(ctx, "dev")
+	if err != nil {
+		t.Fatalf("HealthCheck() error = %v", err)
+	}
+	if health.OverallStatus != monitoring.HealthStatusHealthy || len(health.Nodes) != 3 {
+		t.Errorf("HealthCheck() = %s with %d nodes, want healthy with 3", health.OverallStatus, len(health.Nodes))
+	}
+
+	if err := p.StopCluster(ctx, "dev"); err != nil {
+		t.Fatalf("StopCluster() error = %v", err)
+	}
+	health, _ = p.HealthCheck(ctx, "dev")
+	if health.OverallStatus != monitoring.HealthStatusUnhealthy {
+		t.Errorf("HealthCheck() of stopped cluster = %s, want unhealthy", health.OverallStatus)
+	}
+
+	if err := p.RenameCluster(ctx, "dev", "staging"); err != nil {
+		t.Fatalf("RenameCluster() error = %v", err)
+	}
+	if _, err := p.GetCluster(ctx, "dev"); !errors.Is(err, ErrClusterNotFound) {
+		t.Errorf("GetCluster() of the old name error = %v, want ErrClusterNotFound", err)
+	}
+	history, err := p.GetLogSource().GetClusterHistory(ctx, "staging", 0)
+	if err != nil {
+		t.Fatalf("GetClusterHistory() error = %v", err)
+	}
+	var types []logsource.OperationType
+	for _, op := range history {
+		types = append(types, op.OperationType)
+	}
+	want := []logsource.OperationType{logsource.OpTypeUpdate, logsource.OpTypeStop, logsource.OpTypeScale, logsource.OpTypeCreate}
+	if len(types) != len(want) {
+		t.Fatalf("GetClusterHistory() = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("GetClusterHistory()[%d] = %s, want %s", i, types[i], want[i])
+		}
+	}
+
+	if err := p.DeleteCluster(ctx, "staging"); err != nil {
+		t.Fatalf("DeleteCluster() error = %v", err)
+	}
+	clusters, err := p.ListClusters(ctx)
+	if err != nil {
+		t.Fatalf("ListClusters() error = %v", err)
+	}
+	if len(clusters) != 0 {
+		t.Errorf("ListClusters() returned %d clusters after delete, want 0", len(clusters))
+	}
+}
+
+func TestFakeProvider_FailureInjection(t *testing.T) {
+	ctx := context.Background()
+	p := NewFakeProvider(FakeOptions{StatePath: filepath.Join(t.TempDir(), "state.json"), FailOn: []string{"scale", "health"}})
+
+	if _, err := p.CreateCluster(ctx, &ClusterConfig{Name: "dev", NodeCount: 1}); err != nil {
+		t.Fatalf("CreateCluster() error = %v", err)
+	}
+	if err := p.ScaleCluster(ctx, "dev", 2); err == nil || !contains(err.Error(), "injected scale failure") {
+		t.Errorf("ScaleCluster() error = %v, want injected failure", err)
+	}
+	if _, err := p.HealthCheck(ctx, "dev"); err == nil {
+		t.Error("HealthCheck() should fail when health is injected")
+	}
+
+	cluster, _ := p.GetCluster(ctx, "dev")
+	if cluster.NodeCount != 1 {
+		t.Errorf("failed scale changed node count to %d", cluster.NodeCount)
+	}
+	history, _ := p.GetLogSource().GetClusterHistory(ctx, "dev", 1)
+	if len(history) != 1 || history[0].OperationStatus != logsource.OpStatusFailed {
+		t.Errorf("latest history entry = %+v, want failed scale", history)
+	}
+}
+
+func TestFakeProvider_LatencyHonoursCancellation(t *testing.T) {
+	p := NewFakeProvider(FakeOptions{StatePath: filepath.Join(t.TempDir(), "state.json"), Latency: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := p.CreateCluster(ctx, &ClusterConfig{Name: "dev", NodeCount: 1}); err == nil {
+		t.Fatal("CreateCluster() should fail when the context is canceled mid-operation")
+	}
+	if _, err := p.GetCluster(context.Background(), "dev"); err == nil {
+		t.Error("canceled create should not leave a cluster behind")
+	}
+}
+
+func TestFakeOptionsFromEnv(t *testing.T) {
+	t.Setenv(FakeStateEnvVar, "/tmp/fake.json")
+	t.Setenv(FakeLatencyEnvVar, "250ms")
+	t.Setenv(FakeFailEnvVar, "create, delete")
+
+	opts := FakeOptionsFromEnv()
+	if opts.StatePath != "/tmp/fake.json" || opts.Latency != 250*time.Millisecond {
+		t.Errorf("FakeOptionsFromEnv() = %+v", opts)
+	}
+	if len(opts.FailOn) != 2 || opts.FailOn[0] != "create" || opts.FailOn[1] != "delete" {
+		t.Errorf("FakeOptionsFromEnv().FailOn = %v, want [create delete]", opts.FailOn)
+	}
+}
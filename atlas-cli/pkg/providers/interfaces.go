@@ -2,8 +2,11 @@ package providers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/kubeversions"
 	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
 	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
 )
@@ -35,17 +38,79 @@ type Provider interface {
 	HealthCheck(ctx context.Context, clusterName string) (*monitoring.HealthStatus, error)
 }
 
+// ErrClusterNotFound matches, with errors.Is, the error providers return when the named cluster
+// doesn't exist, as opposed to a failure to find out
+var ErrClusterNotFound = errors.New("cluster does not exist")
+
+type clusterNotFoundError struct {
+	name string
+}
+
+func (e *clusterNotFoundError) Error() string {
+	return fmt.Sprintf("cluster %s does not exist", e.name)
+}
+
+func (e *clusterNotFoundError) Is(target error) bool {
+	return target == ErrClusterNotFound
+}
+
+// clusterNotFound reports that the cluster name doesn't exist
+func clusterNotFound(name string) error {
+	return &clusterNotFoundError{name: name}
+}
+
+// DeletionWaiter is implemented by providers that can confirm a cluster is fully removed
+type DeletionWaiter interface {
+	// WaitForDeletion blocks until the cluster and everything it owns are gone or the timeout expires
+	WaitForDeletion(ctx context.Context, name string, timeout time.Duration) error
+}
+
+// ForceDeleter is implemented by providers that can clean up broken or half-created clusters
+type ForceDeleter interface {
+	// ForceDeleteCluster retries deletion with escalating cleanup until nothing of the cluster is left
+	ForceDeleteCluster(ctx context.Context, name string) error
+}
+
+// ClusterRenamer is implemented by providers that can rename a cluster in place. Neither minikube
+// profiles nor EKS clusters can be renamed, so only the fake provider implements it.
+type ClusterRenamer interface {
+	RenameCluster(ctx context.Context, oldName, newName string) error
+}
+
 // ClusterConfig represents cluster configuration
 type ClusterConfig struct {
-	Name           string            `yaml:"name"`
+	Name           string            `yaml:"name" schema:"required"`
 	Region         string            `yaml:"region"`
 	Version        string            `yaml:"version"`
 	NodeCount      int               `yaml:"nodeCount"`
 	InstanceType   string            `yaml:"instanceType"`
+	DiskSize       string            `yaml:"diskSize,omitempty"`
+	Mounts         []MountConfig     `yaml:"mounts,omitempty"`
 	NetworkConfig  *NetworkConfig    `yaml:"networkConfig,omitempty"`
 	SecurityConfig *SecurityConfig   `yaml:"securityConfig,omitempty"`
 	ResourceConfig *ResourceConfig   `yaml:"resourceConfig,omitempty"`
 	Tags           map[string]string `yaml:"tags,omitempty"`
+	TaggingPolicy  *TaggingPolicy    `yaml:"taggingPolicy,omitempty"`
+	AWS            *AWSConfig        `yaml:"aws,omitempty"`
+	Kubeadm        *KubeadmConfig    `yaml:"kubeadm,omitempty"`
+	ClusterAPI     *ClusterAPIConfig `yaml:"clusterAPI,omitempty"`
+	Addons         []string          `yaml:"addons,omitempty"`
+
+	BootstrapManifests []BootstrapManifest `yaml:"bootstrapManifests,omitempty"`
+}
+
+// AWSConfig holds EKS account settings, usually set once per team in ~/.atlas/providers/aws.yaml
+type AWSConfig struct {
+	Profile        string   `yaml:"profile,omitempty"`
+	Subnets        []string `yaml:"subnets,omitempty"`
+	ClusterRoleARN string   `yaml:"clusterRoleArn,omitempty"`
+	NodeRoleARN    string   `yaml:"nodeRoleArn,omitempty"`
+}
+
+// MountConfig defines a host directory mounted into the cluster nodes
+type MountConfig struct {
+	HostPath string `yaml:"hostPath" schema:"required"`
+	NodePath string `yaml:"nodePath" schema:"required"`
 }
 
 // NetworkConfig defines networking configuration for clusters
@@ -293,6 +358,12 @@ type Cluster struct {
 	UpdatedAt  time.Time         `json:"updatedAt"`
 	Tags       map[string]string `json:"tags"`
 	KubeConfig string            `json:"kubeConfig,omitempty"`
+	Resources  []ClusterResource `json:"resources,omitempty"`
+
+	// Health is only populated when a caller requests health checks alongside the cluster
+	Health monitoring.ClusterHealthStatus `json:"health,omitempty"`
+	// VersionSupport is set by callers that check the version against its support window
+	VersionSupport *kubeversions.Support `json:"versionSupport,omitempty"`
 }
 
 // ClusterStatus represents cluster status
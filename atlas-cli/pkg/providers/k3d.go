@@ -0,0 +1,430 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// K3dProvider implements Provider for lightweight local k3s clusters run by k3d
+type K3dProvider struct {
+	logSource logsource.LogSource
+	monitor   func() monitoring.Monitor
+}
+
+// NewK3dProvider creates a new k3d provider
+func NewK3dProvider() *K3dProvider {
+	return &K3dProvider{
+		logSource: logsource.NewK3dLogSource(),
+		monitor:   sync.OnceValue(func() monitoring.Monitor { return monitoring.NewK3dMonitor() }),
+	}
+}
+
+// k3dCluster is one entry of `k3d cluster list -o json`
+type k3dCluster struct {
+	Name  string    `json:"name"`
+	Nodes []k3dNode `json:"nodes"`
+}
+
+type k3dNode struct {
+	Name    string `json:"name"`
+	Role    string `json:"role"`
+	Image   string `json:"image"`
+	Created string `json:"created"`
+	State   struct {
+		Running bool
+		Status  string
+	} `json:"State"`
+}
+
+// GetLogSource returns the log source for reading operation history
+func (k *K3dProvider) GetLogSource() logsource.LogSource {
+	return k.logSource
+}
+
+// GetProviderName returns the name of this provider
+func (k *K3dProvider) GetProviderName() string {
+	return "k3d"
+}
+
+// GetSupportedRegions returns the list of supported regions for the k3d provider
+func (k *K3dProvider) GetSupportedRegions() []string {
+	return []string{"local"}
+}
+
+// GetSupportedVersions returns the Kubernetes versions with published rancher/k3s images
+func (k *K3dProvider) GetSupportedVersions() []string {
+	return []string{"v1.31.0", "v1.30.0", "v1.29.0", "v1.28.0", "v1.27.0"}
+}
+
+// CreateCluster creates a k3d cluster with one server node and NodeCount-1 agents
+func (k *K3dProvider) CreateCluster(ctx context.Context, config *ClusterConfig) (*Cluster, error) {
+	ctx = subprocess.WithOperation(ctx, "create")
+	if err := k.ValidateConfig(config); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+	if err := checkHostPorts(config); err != nil {
+		return nil, fmt.Errorf("preflight check failed: %w", err)
+	}
+
+	cmd := subprocess.CommandContext(ctx, "k3d", buildK3dArgs(config)...)
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "provision", Status: progress.StatusStarted, Message: "Creating k3d cluster..."})
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "provision", Status: progress.StatusFailed})
+		return nil, fmt.Errorf("failed to create cluster %s: %w\nOutput: %s", config.Name, err, string(output))
+	}
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "provision", Status: progress.StatusCompleted})
+
+	kubectl := []string{"kubectl", "--context", "k3d-" + config.Name}
+	resources, err := applyBootstrapManifests(ctx, config.Name, kubectl, config.BootstrapManifests)
+	if err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "bootstrap", Status: progress.StatusWarning,
+			Message: fmt.Sprintf("failed to apply bootstrap manifests: %v", err)})
+	}
+	if err := installLogging(ctx, "k3d-"+config.Name, "", config); err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "logging", Status: progress.StatusWarning,
+			Message: err.Error()})
+	}
+	tracingResources, err := installTracing(ctx, "k3d-"+config.Name, config)
+	resources = append(resources, tracingResources...)
+	if err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "tracing", Status: progress.StatusWarning,
+			Message: err.Error()})
+	}
+
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "done", Status: progress.StatusCompleted,
+		Message: fmt.Sprintf("Successfully created cluster: %s", config.Name)})
+	cluster, err := k.GetCluster(ctx, config.Name)
+	if err != nil {
+		return nil, err
+	}
+	cluster.Resources = resources
+	return cluster, nil
+}
+
+// buildK3dArgs maps config to `k3d cluster create` arguments. Port mappings are published through
+// k3d's load balancer; mappings with a NodePort target that port on the nodes. k3s bundles
+// Traefik and its service load balancer, which are disabled unless ingress or loadBalancer are
+// enabled, matching the minikube addons.
+func buildK3dArgs(config *ClusterConfig) []string {
+	agents := config.NodeCount - 1
+	if agents < 0 {
+		agents = 0
+	}
+	args := []string{"cluster", "create", config.Name, "--servers", "1", "--agents", strconv.Itoa(agents), "--wait"}
+
+	if config.Version != "" {
+		args = append(args, "--image", "rancher/k3s:"+k3sImageTag(config.Version))
+	}
+	for _, mount := range config.Mounts {
+		args = append(args, "--volume", mount.HostPath+":"+mount.NodePath+"@all")
+	}
+
+	ingress, loadBalancer := false, false
+	if network := config.NetworkConfig; network != nil {
+		if network.APIServerPort > 0 {
+			args = append(args, "--api-port", "127.0.0.1:"+strconv.Itoa(network.APIServerPort))
+		}
+		if network.PodCIDR != "" {
+			args = append(args, "--k3s-arg", "--cluster-cidr="+network.PodCIDR+"@server:*")
+		}
+		if network.ServiceCIDR != "" {
+			args = append(args, "--k3s-arg", "--service-cidr="+network.ServiceCIDR+"@server:*")
+		}
+		for _, portMap := range network.ExtraPortMaps {
+			containerPort := portMap.ContainerPort
+			if portMap.NodePort > 0 {
+				containerPort = portMap.NodePort
+			}
+			protocol := strings.ToLower(portMap.Protocol)
+			if protocol == "" {
+				protocol = "tcp"
+			}
+			args = append(args, "--port", fmt.Sprintf("%d:%d/%s@loadbalancer", portMap.HostPort, containerPort, protocol))
+		}
+		ingress = network.Ingress != nil && network.Ingress.Enabled && isTraefik(network.Ingress.Controller)
+		loadBalancer = network.LoadBalancer != nil && network.LoadBalancer.Enabled
+	}
+	if !ingress {
+		args = append(args, "--k3s-arg", "--disable=traefik@server:*")
+	}
+	if !loadBalancer {
+		args = append(args, "--k3s-arg", "--disable=servicelb@server:*")
+	}
+
+	if resources := config.ResourceConfig; resources != nil && resources.Limits != nil && resources.Limits.Memory != "" {
+		args = append(args, "--servers-memory", resources.Limits.Memory, "--agents-memory", resources.Limits.Memory)
+	}
+	return args
+}
+
+// k3sImageTag turns a Kubernetes version into a rancher/k3s image tag, e.g. 1.30.0 into v1.30.0-k3s1
+func k3sImageTag(version string) string {
+	tag := "v" + strings.TrimPrefix(version, "v")
+	if !strings.Contains(tag, "k3s") {
+		tag += "-k3s1"
+	}
+	return strings.ReplaceAll(tag, "+", "-")
+}
+
+// isTraefik reports whether an ingress controller setting means k3s's bundled Traefik
+func isTraefik(controller string) bool {
+	return controller == "" || controller == "traefik"
+}
+
+// DeleteCluster deletes a k3d cluster by name
+func (k *K3dProvider) DeleteCluster(ctx context.Context, name string) error {
+	ctx = subprocess.WithOperation(ctx, "delete")
+	cmd := subprocess.CommandContext(ctx, "k3d", "cluster", "delete", name)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to delete cluster %s: %w\nOutput: %s", name, err, string(output))
+	}
+	return nil
+}
+
+// StartCluster starts a stopped k3d cluster
+func (k *K3dProvider) StartCluster(ctx context.Context, name string) error {
+	ctx = subprocess.WithOperation(ctx, "start")
+	output, err := subprocess.CommandContext(ctx, "k3d", "cluster", "start", name).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to start cluster %s: %w\nOutput: %s", name, err, string(output))
+	}
+	return nil
+}
+
+// StopCluster stops a k3d cluster
+func (k *K3dProvider) StopCluster(ctx context.Context, name string) error {
+	ctx = subprocess.WithOperation(ctx, "stop")
+	output, err := subprocess.CommandContext(ctx, "k3d", "cluster", "stop", name).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to stop cluster %s: %w\nOutput: %s", name, err, string(output))
+	}
+	return nil
+}
+
+// ScaleCluster adds or removes agent nodes so the cluster has nodeCount nodes. The server node
+// always stays, so nodeCount must be at least 1.
+func (k *K3dProvider) ScaleCluster(ctx context.Context, name string, nodeCount int) error {
+	ctx = subprocess.WithOperation(ctx, "scale")
+	if nodeCount < 1 {
+		return fmt.Errorf("k3d clusters need at least 1 node")
+	}
+	cluster, err := k.getK3dCluster(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	var servers int
+	var agents []string
+	for _, node := range cluster.Nodes {
+		switch node.Role {
+		case "server":
+			servers++
+		case "agent":
+			agents = append(agents, node.Name)
+		}
+	}
+
+	var args []string
+	switch want := nodeCount - servers; {
+	case want > len(agents):
+		// node names must be unique, so each scale-up gets its own prefix
+		args = []string{"node", "create", fmt.Sprintf("%s-agent-%d", name, time.Now().Unix()),
+			"--cluster", name, "--role", "agent", "--replicas", strconv.Itoa(want - len(agents)), "--wait"}
+	case want < len(agents):
+		if want < 0 {
+			want = 0
+		}
+		args = append([]string{"node", "delete"}, agents[want:]...)
+	default:
+		return nil
+	}
+
+	output, err := subprocess.CommandContext(ctx, "k3d", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to scale cluster %s: %w\nOutput: %s", name, err, string(output))
+	}
+	return nil
+}
+
+// listK3dClusters returns the clusters reported by `k3d cluster list -o json`, optionally
+// restricted to one name
+func listK3dClusters(ctx context.Context, name ...string) ([]k3dCluster, error) {
+	args := append([]string{"cluster", "list"}, name...)
+	output, err := subprocess.CommandContext(ctx, "k3d", append(args, "-o", "json")...).Output()
+	if err != nil {
+		return nil, err
+	}
+	var clusters []k3dCluster
+	if err := json.Unmarshal(output, &clusters); err != nil {
+		return nil, fmt.Errorf("failed to parse k3d cluster list: %w", err)
+	}
+	return clusters, nil
+}
+
+func (k *K3dProvider) getK3dCluster(ctx context.Context, name string) (*k3dCluster, error) {
+	clusters, err := listK3dClusters(ctx, name)
+	if err != nil || len(clusters) == 0 {
+		return nil, clusterNotFound(name)
+	}
+	return &clusters[0], nil
+}
+
+// GetCluster retrieves information about a k3d cluster
+func (k *K3dProvider) GetCluster(ctx context.Context, name string) (*Cluster, error) {
+	k3d, err := k.getK3dCluster(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	cluster := k3dClusterInfo(k3d)
+
+	output, err := subprocess.CommandContext(ctx, "docker", "port", "k3d-"+name+"-serverlb", "6443/tcp").Output()
+	if err == nil {
+		if fields := strings.Fields(string(output)); len(fields) > 0 {
+			cluster.Endpoint = "https://" + fields[0]
+		}
+	}
+	return cluster, nil
+}
+
+// k3dClusterInfo converts a k3d cluster listing into a Cluster. The cluster is running when
+// every server node is, and stopped when none are.
+func k3dClusterInfo(k3d *k3dCluster) *Cluster {
+	cluster := &Cluster{
+		Name:      k3d.Name,
+		Provider:  "k3d",
+		Region:    "local",
+		Status:    ClusterStatusError,
+		UpdatedAt: time.Now(),
+		Tags:      make(map[string]string),
+	}
+
+	var servers, running int
+	for _, node := range k3d.Nodes {
+		if node.Role != "server" && node.Role != "agent" {
+			continue
+		}
+		cluster.NodeCount++
+		if node.Role != "server" {
+			continue
+		}
+		servers++
+		if node.State.Running {
+			running++
+		}
+		if cluster.Version == "" {
+			image, _, _ := strings.Cut(node.Image, "@")
+			if idx := strings.LastIndex(image, ":"); idx >= 0 {
+				cluster.Version, _, _ = strings.Cut(image[idx+1:], "-k3s")
+			}
+		}
+		if created, err := time.Parse(time.RFC3339Nano, node.Created); err == nil && (cluster.CreatedAt.IsZero() || created.Before(cluster.CreatedAt)) {
+			cluster.CreatedAt = created
+		}
+	}
+	switch {
+	case servers > 0 && running == servers:
+		cluster.Status = ClusterStatusRunning
+	case servers > 0 && running == 0:
+		cluster.Status = ClusterStatusStopped
+	}
+	return cluster
+}
+
+// ListClusters lists all k3d clusters on this host
+func (k *K3dProvider) ListClusters(ctx context.Context) ([]*Cluster, error) {
+	k3dClusters, err := listK3dClusters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting k3d clusters: %w", err)
+	}
+
+	var clusters []*Cluster
+	for i := range k3dClusters {
+		clusters = append(clusters, k3dClusterInfo(&k3dClusters[i]))
+	}
+	return clusters, nil
+}
+
+// ValidateConfig validates the cluster configuration for the k3d provider
+func (k *K3dProvider) ValidateConfig(config *ClusterConfig) error {
+	return k.Validate(config).Err()
+}
+
+// Validate reports every problem with the cluster configuration for the k3d provider. Settings
+// k3d doesn't act on are reported as warnings.
+func (k *K3dProvider) Validate(config *ClusterConfig) *ValidationResult {
+	result := &ValidationResult{}
+
+	if config.Name == "" {
+		result.Errorf("name", "cluster name is required")
+	} else if strings.Contains(config.Name, " ") {
+		result.Errorf("name", "cluster name cannot contain spaces")
+	}
+
+	if _, err := DetectTool(context.Background(), "k3d"); err != nil {
+		result.Errorf("", "%v", err)
+	}
+
+	if config.NodeCount < 0 {
+		result.Errorf("nodeCount", "node count cannot be negative")
+	}
+	if config.NodeCount > 10 {
+		result.Errorf("nodeCount", "node count cannot exceed 10 for k3d provider")
+	}
+
+	if config.Region != "" && config.Region != "local" {
+		result.Warnf("region", "region %s is ignored by the k3d provider", config.Region)
+	}
+	if config.InstanceType != "" {
+		result.Warnf("instanceType", "instance type %s is ignored by the k3d provider", config.InstanceType)
+	}
+	if config.DiskSize != "" {
+		result.Warnf("diskSize", "disk size is ignored by the k3d provider; nodes share the docker host's disk")
+	}
+
+	validateMountPaths(config.Mounts, result)
+	validateBootstrapManifests(config.BootstrapManifests, result)
+	validateNetworkConfig(config.NetworkConfig, result)
+	validateSecurityConfig(config.SecurityConfig, result)
+	validateLogConfig(config, false, result)
+	validateTracingConfig(config, result)
+
+	if network := config.NetworkConfig; network != nil {
+		if network.NetworkPlugin != "" && network.NetworkPlugin != "auto" && network.NetworkPlugin != "flannel" {
+			result.Warnf("networkConfig.networkPlugin", "k3s runs flannel; %s is not set up", network.NetworkPlugin)
+		}
+		if network.Ingress != nil && network.Ingress.Enabled && !isTraefik(network.Ingress.Controller) {
+			result.Warnf("networkConfig.ingress.controller", "k3s bundles Traefik; install %s with bootstrapManifests", network.Ingress.Controller)
+		}
+	}
+	if resources := config.ResourceConfig; resources != nil && resources.Limits != nil && resources.Limits.CPU != "" {
+		result.Warnf("resourceConfig.limits.cpu", "CPU limits are ignored by the k3d provider")
+	}
+
+	return result
+}
+
+// GetMonitor returns the monitor for health checks and metrics collection
+func (k *K3dProvider) GetMonitor() monitoring.Monitor {
+	return k.monitor()
+}
+
+// HealthCheck performs a health check on the specified cluster
+func (k *K3dProvider) HealthCheck(ctx context.Context, clusterName string) (*monitoring.HealthStatus, error) {
+	return k.monitor().CheckClusterHealth(ctx, clusterName)
+}
+
+// Ensure K3dProvider implements Provider interface
+var _ Provider = (*K3dProvider)(nil)
+var _ ConfigValidator = (*K3dProvider)(nil)
@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBuildK3dArgs(t *testing.T) {
+	config := &ClusterConfig{
+		Name:      "dev",
+		Version:   "1.30.0",
+		NodeCount: 3,
+		Mounts:    []MountConfig{{HostPath: "/src", NodePath: "/workspace"}},
+		NetworkConfig: &NetworkConfig{
+			APIServerPort: 16443,
+			PodCIDR:       "10.42.0.0/16",
+			ExtraPortMaps: []PortMapping{
+				{HostPort: 8080, ContainerPort: 80},
+				{HostPort: 30080, ContainerPort: 8080, NodePort: 30080, Protocol: "UDP"},
+			},
+			Ingress: &IngressConfig{Enabled: true},
+		},
+		ResourceConfig: &ResourceConfig{Limits: &ResourceLimits{Memory: "2g"}},
+	}
+
+	got := strings.Join(buildK3dArgs(config), " ")
+	want := "cluster create dev --servers 1 --agents 2 --wait" +
+		" --image rancher/k3s:v1.30.0-k3s1" +
+		" --volume /src:/workspace@all" +
+		" --api-port 127.0.0.1:16443" +
+		" --k3s-arg --cluster-cidr=10.42.0.0/16@server:*" +
+		" --port 8080:80/tcp@loadbalancer" +
+		" --port 30080:30080/udp@loadbalancer" +
+		" --k3s-arg --disable=servicelb@server:*" +
+		" --servers-memory 2g --agents-memory 2g"
+	if got != want {
+		t.Errorf("buildK3dArgs() =\n%s\nwant\n%s", got, want)
+	}
+
+	minimal := strings.Join(buildK3dArgs(&ClusterConfig{Name: "ci", NodeCount: 1, Version: "v1.29.4+k3s2"}), " ")
+	for _, arg := range []string{"--agents 0", "rancher/k3s:v1.29.4-k3s2", "--disable=traefik@server:*", "--disable=servicelb@server:*"} {
+		if !strings.Contains(minimal, arg) {
+			t.Errorf("buildK3dArgs() = %s, want it to contain %s", minimal, arg)
+		}
+	}
+}
+
+func TestK3dClusterInfo(t *testing.T) {
+	output := `[{"name": "dev", "nodes": [
+		{"name": "k3d-dev-server-0", "role": "server", "image": "rancher/k3s:v1.30.0-k3s1", "created": "2024-08-01T10:00:00Z", "State": {"Running": true, "Status": "running"}},
+		{"name": "k3d-dev-agent-0", "role": "agent", "image": "rancher/k3s:v1.30.0-k3s1", "State": {"Running": true}},
+		{"name": "k3d-dev-serverlb", "role": "loadbalancer", "image": "ghcr.io/k3d-io/k3d-proxy:5.6.0", "State": {"Running": true}}
+	]}]`
+	var clusters []k3dCluster
+	if err := json.Unmarshal([]byte(output), &clusters); err != nil {
+		t.Fatal(err)
+	}
+
+	cluster := k3dClusterInfo(&clusters[0])
+	if cluster.Status != ClusterStatusRunning || cluster.NodeCount != 2 || cluster.Version != "v1.30.0" || cluster.CreatedAt.IsZero() {
+		t.Errorf("k3dClusterInfo() = %+v", cluster)
+	}
+
+	clusters[0].Nodes[0].State.Running = false
+	if got := k3dClusterInfo(&clusters[0]).Status; got != ClusterStatusStopped {
+		t.Errorf("status with the server stopped = %s, want %s", got, ClusterStatusStopped)
+	}
+}
+
+func TestK3dProvider_Validate(t *testing.T) {
+	config := &ClusterConfig{
+		Name:      "dev",
+		NodeCount: 11,
+		NetworkConfig: &NetworkConfig{
+			NetworkPlugin: "calico",
+			Ingress:       &IngressConfig{Enabled: true, Controller: "nginx"},
+		},
+		ResourceConfig: &ResourceConfig{Limits: &ResourceLimits{CPU: "2", Memory: "2g"}},
+	}
+
+	result := NewK3dProvider().Validate(config)
+	var errorFields, warningFields []string
+	for _, issue := range result.Errors() {
+		if issue.Field != "" {
+			errorFields = append(errorFields, issue.Field)
+		}
+	}
+	for _, issue := range result.Warnings() {
+		warningFields = append(warningFields, issue.Field)
+	}
+	if got, want := strings.Join(errorFields, ","), "nodeCount"; got != want {
+		t.Errorf("error fields = %s, want %s", got, want)
+	}
+	if got, want := strings.Join(warningFields, ","), "networkConfig.networkPlugin,networkConfig.ingress.controller,resourceConfig.limits.cpu"; got != want {
+		t.Errorf("warning fields = %s, want %s", got, want)
+	}
+}
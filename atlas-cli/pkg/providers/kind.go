@@ -0,0 +1,423 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+	"gopkg.in/yaml.v3"
+)
+
+// KindProvider implements Provider for local clusters run by kind (Kubernetes in Docker)
+type KindProvider struct {
+	logSource logsource.LogSource
+	monitor   func() monitoring.Monitor
+}
+
+// NewKindProvider creates a new kind provider
+func NewKindProvider() *KindProvider {
+	return &KindProvider{
+		logSource: logsource.NewKindLogSource(),
+		monitor:   sync.OnceValue(func() monitoring.Monitor { return monitoring.NewKindMonitor() }),
+	}
+}
+
+// kindClusterConfig is kind's cluster configuration file, see https://kind.sigs.k8s.io/docs/user/configuration/
+type kindClusterConfig struct {
+	Kind       string          `yaml:"kind"`
+	APIVersion string          `yaml:"apiVersion"`
+	Name       string          `yaml:"name"`
+	Networking *kindNetworking `yaml:"networking,omitempty"`
+	Nodes      []kindNode      `yaml:"nodes"`
+}
+
+type kindNetworking struct {
+	APIServerPort int    `yaml:"apiServerPort,omitempty"`
+	PodSubnet     string `yaml:"podSubnet,omitempty"`
+	ServiceSubnet string `yaml:"serviceSubnet,omitempty"`
+}
+
+type kindNode struct {
+	Role              string            `yaml:"role"`
+	Image             string            `yaml:"image,omitempty"`
+	ExtraPortMappings []kindPortMapping `yaml:"extraPortMappings,omitempty"`
+	ExtraMounts       []kindMount       `yaml:"extraMounts,omitempty"`
+}
+
+type kindPortMapping struct {
+	ContainerPort int    `yaml:"containerPort"`
+	HostPort      int    `yaml:"hostPort"`
+	Protocol      string `yaml:"protocol,omitempty"`
+}
+
+type kindMount struct {
+	HostPath      string `yaml:"hostPath"`
+	ContainerPath string `yaml:"containerPath"`
+}
+
+// GetLogSource returns the log source for reading operation history
+func (k *KindProvider) GetLogSource() logsource.LogSource {
+	return k.logSource
+}
+
+// GetProviderName returns the name of this provider
+func (k *KindProvider) GetProviderName() string {
+	return "kind"
+}
+
+// GetSupportedRegions returns the list of supported regions for the kind provider
+func (k *KindProvider) GetSupportedRegions() []string {
+	return []string{"local"}
+}
+
+// GetSupportedVersions returns the Kubernetes versions with published kindest/node images
+func (k *KindProvider) GetSupportedVersions() []string {
+	return []string{"v1.31.0", "v1.30.0", "v1.29.0", "v1.28.0", "v1.27.0"}
+}
+
+// CreateCluster creates a kind cluster with one control-plane node and NodeCount-1 workers
+func (k *KindProvider) CreateCluster(ctx context.Context, config *ClusterConfig) (*Cluster, error) {
+	ctx = subprocess.WithOperation(ctx, "create")
+	if err := k.ValidateConfig(config); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+	if err := checkHostPorts(config); err != nil {
+		return nil, fmt.Errorf("preflight check failed: %w", err)
+	}
+
+	kindConfig, err := buildKindConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := subprocess.CommandContext(ctx, "kind", "create", "cluster", "--name", config.Name, "--config", "-")
+	cmd.Stdin = strings.NewReader(string(kindConfig))
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "provision", Status: progress.StatusStarted, Message: "Creating kind cluster..."})
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "provision", Status: progress.StatusFailed})
+		return nil, fmt.Errorf("failed to create cluster %s: %w\nOutput: %s", config.Name, err, string(output))
+	}
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "provision", Status: progress.StatusCompleted})
+
+	kubectl := []string{"kubectl", "--context", "kind-" + config.Name}
+	resources, err := applyBootstrapManifests(ctx, config.Name, kubectl, config.BootstrapManifests)
+	if err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "bootstrap", Status: progress.StatusWarning,
+			Message: fmt.Sprintf("failed to apply bootstrap manifests: %v", err)})
+	}
+	if err := installLogging(ctx, "kind-"+config.Name, "", config); err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "logging", Status: progress.StatusWarning,
+			Message: err.Error()})
+	}
+	tracingResources, err := installTracing(ctx, "kind-"+config.Name, config)
+	resources = append(resources, tracingResources...)
+	if err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "tracing", Status: progress.StatusWarning,
+			Message: err.Error()})
+	}
+
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "done", Status: progress.StatusCompleted,
+		Message: fmt.Sprintf("Successfully created cluster: %s", config.Name)})
+	cluster, err := k.GetCluster(ctx, config.Name)
+	if err != nil {
+		return nil, err
+	}
+	cluster.Resources = resources
+	return cluster, nil
+}
+
+// buildKindConfig renders config as a kind cluster configuration. Port mappings are published
+// from the control-plane node; mappings with a NodePort target that port on the node so a
+// NodePort service is reachable from the host.
+func buildKindConfig(config *ClusterConfig) ([]byte, error) {
+	kindConfig := kindClusterConfig{
+		Kind:       "Cluster",
+		APIVersion: "kind.x-k8s.io/v1alpha4",
+		Name:       config.Name,
+	}
+
+	var image string
+	if config.Version != "" {
+		image = "kindest/node:v" + strings.TrimPrefix(config.Version, "v")
+	}
+	var mounts []kindMount
+	for _, mount := range config.Mounts {
+		mounts = append(mounts, kindMount{HostPath: mount.HostPath, ContainerPath: mount.NodePath})
+	}
+
+	controlPlane := kindNode{Role: "control-plane", Image: image, ExtraMounts: mounts}
+	if network := config.NetworkConfig; network != nil {
+		if network.APIServerPort > 0 || network.PodCIDR != "" || network.ServiceCIDR != "" {
+			kindConfig.Networking = &kindNetworking{
+				APIServerPort: network.APIServerPort,
+				PodSubnet:     network.PodCIDR,
+				ServiceSubnet: network.ServiceCIDR,
+			}
+		}
+		for _, portMap := range network.ExtraPortMaps {
+			containerPort := portMap.ContainerPort
+			if portMap.NodePort > 0 {
+				containerPort = portMap.NodePort
+			}
+			controlPlane.ExtraPortMappings = append(controlPlane.ExtraPortMappings, kindPortMapping{
+				ContainerPort: containerPort,
+				HostPort:      portMap.HostPort,
+				Protocol:      strings.ToUpper(portMap.Protocol),
+			})
+		}
+	}
+	kindConfig.Nodes = append(kindConfig.Nodes, controlPlane)
+
+	for i := 1; i < config.NodeCount; i++ {
+		kindConfig.Nodes = append(kindConfig.Nodes, kindNode{Role: "worker", Image: image, ExtraMounts: mounts})
+	}
+
+	data, err := yaml.Marshal(kindConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode kind config: %w", err)
+	}
+	return data, nil
+}
+
+// checkHostPorts checks that the API server port and every mapped host port are free, since kind
+// and k3d fail late and unclearly when docker can't publish a port
+func checkHostPorts(config *ClusterConfig) error {
+	network := config.NetworkConfig
+	if network == nil {
+		return nil
+	}
+	if network.APIServerPort > 0 {
+		if err := checkPortAvailable(network.APIServerPort); err != nil {
+			return fmt.Errorf("API server %w; choose another with --api-server-port or use --api-server-port auto", err)
+		}
+	}
+	for i, portMap := range network.ExtraPortMaps {
+		if err := checkPortAvailable(portMap.HostPort); err != nil {
+			return fmt.Errorf("%s: host %w", fieldPath("networkConfig", "extraPortMaps", i), err)
+		}
+	}
+	return nil
+}
+
+// DeleteCluster deletes a kind cluster by name
+func (k *KindProvider) DeleteCluster(ctx context.Context, name string) error {
+	ctx = subprocess.WithOperation(ctx, "delete")
+	cmd := subprocess.CommandContext(ctx, "kind", "delete", "cluster", "--name", name)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to delete cluster %s: %w\nOutput: %s", name, err, string(output))
+	}
+	return nil
+}
+
+// StartCluster starts the node containers of a stopped kind cluster
+func (k *KindProvider) StartCluster(ctx context.Context, name string) error {
+	ctx = subprocess.WithOperation(ctx, "start")
+	return k.dockerNodes(ctx, name, "start")
+}
+
+// StopCluster stops the node containers of a kind cluster
+func (k *KindProvider) StopCluster(ctx context.Context, name string) error {
+	ctx = subprocess.WithOperation(ctx, "stop")
+	return k.dockerNodes(ctx, name, "stop")
+}
+
+// dockerNodes runs docker start or stop on every node container of the cluster. kind has no
+// start or stop command of its own.
+func (k *KindProvider) dockerNodes(ctx context.Context, name, action string) error {
+	nodes, err := k.nodes(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return clusterNotFound(name)
+	}
+	output, err := subprocess.CommandContext(ctx, "docker", append([]string{action}, nodes...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to %s cluster %s: %w\nOutput: %s", action, name, err, string(output))
+	}
+	return nil
+}
+
+// ScaleCluster is not supported: kind fixes the node list when the cluster is created
+func (k *KindProvider) ScaleCluster(ctx context.Context, name string, nodeCount int) error {
+	return fmt.Errorf("kind clusters cannot be scaled; recreate cluster %s with --nodes %d", name, nodeCount)
+}
+
+// nodes returns the names of the cluster's node containers
+func (k *KindProvider) nodes(ctx context.Context, name string) ([]string, error) {
+	output, err := subprocess.CommandContext(ctx, "kind", "get", "nodes", "--name", name).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes of cluster %s: %w", name, err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// GetCluster retrieves information about a kind cluster from its control-plane container
+func (k *KindProvider) GetCluster(ctx context.Context, name string) (*Cluster, error) {
+	nodes, err := k.nodes(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, clusterNotFound(name)
+	}
+
+	controlPlane := name + "-control-plane"
+	cluster := &Cluster{
+		Name:      name,
+		Provider:  "kind",
+		Region:    "local",
+		Status:    ClusterStatusError,
+		NodeCount: len(nodes),
+		UpdatedAt: time.Now(),
+		Tags:      make(map[string]string),
+	}
+
+	output, err := subprocess.CommandContext(ctx, "docker", "inspect", "-f", "{{.State.Running}} {{.Config.Image}} {{.Created}}", controlPlane).Output()
+	if err == nil {
+		running, version, created := parseKindNodeInspect(string(output))
+		cluster.Status = ClusterStatusStopped
+		if running {
+			cluster.Status = ClusterStatusRunning
+		}
+		cluster.Version = version
+		cluster.CreatedAt = created
+	}
+
+	output, err = subprocess.CommandContext(ctx, "docker", "port", controlPlane, "6443/tcp").Output()
+	if err == nil {
+		if fields := strings.Fields(string(output)); len(fields) > 0 {
+			cluster.Endpoint = "https://" + fields[0]
+		}
+	}
+
+	return cluster, nil
+}
+
+// parseKindNodeInspect parses docker inspect output in the form
+// "true kindest/node:v1.31.0@sha256:... 2024-08-01T10:00:00.123Z"
+func parseKindNodeInspect(output string) (running bool, version string, created time.Time) {
+	fields := strings.Fields(output)
+	if len(fields) < 3 {
+		return false, "", time.Time{}
+	}
+	running, _ = strconv.ParseBool(fields[0])
+	image, _, _ := strings.Cut(fields[1], "@")
+	if idx := strings.LastIndex(image, ":"); idx >= 0 {
+		version = image[idx+1:]
+	}
+	created, _ = time.Parse(time.RFC3339Nano, fields[2])
+	return running, version, created
+}
+
+// ListClusters lists all kind clusters on this host
+func (k *KindProvider) ListClusters(ctx context.Context) ([]*Cluster, error) {
+	output, err := subprocess.CommandContext(ctx, "kind", "get", "clusters").Output()
+	if err != nil {
+		return nil, fmt.Errorf("error getting kind clusters: %w", err)
+	}
+
+	var clusters []*Cluster
+	for _, name := range strings.Fields(string(output)) {
+		cluster, err := k.GetCluster(ctx, name)
+		if err != nil {
+			if strings.Contains(err.Error(), "does not exist") {
+				continue
+			}
+			return nil, fmt.Errorf("error getting cluster %s: %w", name, err)
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters, nil
+}
+
+// ValidateConfig validates the cluster configuration for the kind provider
+func (k *KindProvider) ValidateConfig(config *ClusterConfig) error {
+	return k.Validate(config).Err()
+}
+
+// Validate reports every problem with the cluster configuration for the kind provider. Settings
+// kind doesn't act on are reported as warnings.
+func (k *KindProvider) Validate(config *ClusterConfig) *ValidationResult {
+	result := &ValidationResult{}
+
+	if config.Name == "" {
+		result.Errorf("name", "cluster name is required")
+	} else if strings.Contains(config.Name, " ") {
+		result.Errorf("name", "cluster name cannot contain spaces")
+	}
+
+	if _, err := DetectTool(context.Background(), "kind"); err != nil {
+		result.Errorf("", "%v", err)
+	}
+
+	if config.NodeCount < 0 {
+		result.Errorf("nodeCount", "node count cannot be negative")
+	}
+	if config.NodeCount > 10 {
+		result.Errorf("nodeCount", "node count cannot exceed 10 for kind provider")
+	}
+
+	if config.Region != "" && config.Region != "local" {
+		result.Warnf("region", "region %s is ignored by the kind provider", config.Region)
+	}
+	if config.InstanceType != "" {
+		result.Warnf("instanceType", "instance type %s is ignored by the kind provider", config.InstanceType)
+	}
+	if config.DiskSize != "" {
+		result.Warnf("diskSize", "disk size is ignored by the kind provider; nodes share the docker host's disk")
+	}
+
+	validateMountPaths(config.Mounts, result)
+	validateBootstrapManifests(config.BootstrapManifests, result)
+	validateNetworkConfig(config.NetworkConfig, result)
+	validateSecurityConfig(config.SecurityConfig, result)
+	validateLogConfig(config, false, result)
+	validateTracingConfig(config, result)
+
+	if network := config.NetworkConfig; network != nil {
+		if network.NetworkPlugin != "" && network.NetworkPlugin != "auto" {
+			result.Warnf("networkConfig.networkPlugin", "kind installs its own CNI; %s is not set up", network.NetworkPlugin)
+		}
+		if network.Ingress != nil && network.Ingress.Enabled {
+			result.Warnf("networkConfig.ingress.enabled", "kind has no ingress addon; install a controller with bootstrapManifests")
+		}
+		if network.LoadBalancer != nil && network.LoadBalancer.Enabled {
+			result.Warnf("networkConfig.loadBalancer.enabled", "kind has no load balancer addon; install one with bootstrapManifests")
+		}
+	}
+	if resources := config.ResourceConfig; resources != nil {
+		if resources.Limits != nil && (resources.Limits.CPU != "" || resources.Limits.Memory != "") {
+			result.Warnf("resourceConfig.limits", "CPU and memory limits are ignored by the kind provider")
+		}
+		if resources.Monitoring != nil && resources.Monitoring.Enabled {
+			result.Warnf("resourceConfig.monitoring.enabled", "kind has no metrics-server addon; install it with bootstrapManifests")
+		}
+	}
+
+	return result
+}
+
+// GetMonitor returns the monitor for health checks and metrics collection
+func (k *KindProvider) GetMonitor() monitoring.Monitor {
+	return k.monitor()
+}
+
+// HealthCheck performs a health check on the specified cluster
+func (k *KindProvider) HealthCheck(ctx context.Context, clusterName string) (*monitoring.HealthStatus, error) {
+	return k.monitor().CheckClusterHealth(ctx, clusterName)
+}
+
+// Ensure KindProvider implements Provider interface
+var _ Provider = (*KindProvider)(nil)
+var _ ConfigValidator = (*KindProvider)(nil)
@@ -0,0 +1,118 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestBuildKindConfig(t *testing.T) {
+	config := &ClusterConfig{
+		Name:      "dev",
+		Version:   "1.30.0",
+		NodeCount: 3,
+		Mounts:    []MountConfig{{HostPath: "/src", NodePath: "/workspace"}},
+		NetworkConfig: &NetworkConfig{
+			APIServerPort: 16443,
+			PodCIDR:       "10.244.0.0/16",
+			ExtraPortMaps: []PortMapping{
+				{HostPort: 8080, ContainerPort: 80},
+				{HostPort: 5353, ContainerPort: 53, Protocol: "udp"},
+				{HostPort: 30080, ContainerPort: 8080, NodePort: 30080},
+			},
+		},
+	}
+
+	data, err := buildKindConfig(config)
+	if err != nil {
+		t.Fatalf("buildKindConfig() error = %v", err)
+	}
+	var kindConfig kindClusterConfig
+	if err := yaml.Unmarshal(data, &kindConfig); err != nil {
+		t.Fatalf("generated config is not valid YAML: %v\n%s", err, data)
+	}
+
+	if kindConfig.Kind != "Cluster" || kindConfig.APIVersion != "kind.x-k8s.io/v1alpha4" || kindConfig.Name != "dev" {
+		t.Errorf("unexpected header: %+v", kindConfig)
+	}
+	if kindConfig.Networking == nil || kindConfig.Networking.APIServerPort != 16443 || kindConfig.Networking.PodSubnet != "10.244.0.0/16" {
+		t.Errorf("networking = %+v", kindConfig.Networking)
+	}
+
+	var roles []string
+	for _, node := range kindConfig.Nodes {
+		roles = append(roles, node.Role)
+		if node.Image != "kindest/node:v1.30.0" {
+			t.Errorf("%s image = %q, want kindest/node:v1.30.0", node.Role, node.Image)
+		}
+		if len(node.ExtraMounts) != 1 || node.ExtraMounts[0].ContainerPath != "/workspace" {
+			t.Errorf("%s mounts = %+v", node.Role, node.ExtraMounts)
+		}
+	}
+	if got := strings.Join(roles, ","); got != "control-plane,worker,worker" {
+		t.Errorf("roles = %s, want control-plane,worker,worker", got)
+	}
+
+	want := []kindPortMapping{
+		{ContainerPort: 80, HostPort: 8080},
+		{ContainerPort: 53, HostPort: 5353, Protocol: "UDP"},
+		{ContainerPort: 30080, HostPort: 30080},
+	}
+	mappings := kindConfig.Nodes[0].ExtraPortMappings
+	if len(mappings) != len(want) {
+		t.Fatalf("port mappings = %+v, want %+v", mappings, want)
+	}
+	for i := range want {
+		if mappings[i] != want[i] {
+			t.Errorf("port mapping %d = %+v, want %+v", i, mappings[i], want[i])
+		}
+	}
+	if len(kindConfig.Nodes[1].ExtraPortMappings) != 0 {
+		t.Error("port mappings should only be published from the control-plane node")
+	}
+}
+
+func TestParseKindNodeInspect(t *testing.T) {
+	running, version, created := parseKindNodeInspect("true kindest/node:v1.31.0@sha256:53df588e04085fd41ae12de0c3fe4c72f7013bba32a20e7325357a1ac94ba865 2024-08-01T10:00:00.123456789Z\n")
+	if !running || version != "v1.31.0" {
+		t.Errorf("parseKindNodeInspect() = %v, %q", running, version)
+	}
+	if want := time.Date(2024, 8, 1, 10, 0, 0, 123456789, time.UTC); !created.Equal(want) {
+		t.Errorf("created = %v, want %v", created, want)
+	}
+
+	if running, _, _ := parseKindNodeInspect("false kindest/node:v1.29.0 2024-08-01T10:00:00Z"); running {
+		t.Error("parseKindNodeInspect() should report a stopped node")
+	}
+}
+
+func TestKindProvider_Validate(t *testing.T) {
+	config := &ClusterConfig{
+		Name:      "dev",
+		NodeCount: 11,
+		DiskSize:  "40g",
+		NetworkConfig: &NetworkConfig{
+			Ingress:       &IngressConfig{Enabled: true},
+			ExtraPortMaps: []PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "sctp"}},
+		},
+	}
+
+	result := NewKindProvider().Validate(config)
+	var errorFields, warningFields []string
+	for _, issue := range result.Errors() {
+		if issue.Field != "" {
+			errorFields = append(errorFields, issue.Field)
+		}
+	}
+	for _, issue := range result.Warnings() {
+		warningFields = append(warningFields, issue.Field)
+	}
+	if got, want := strings.Join(errorFields, ","), "nodeCount,networkConfig.extraPortMaps[0].protocol"; got != want {
+		t.Errorf("error fields = %s, want %s", got, want)
+	}
+	if got, want := strings.Join(warningFields, ","), "diskSize,networkConfig.ingress.enabled"; got != want {
+		t.Errorf("warning fields = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,947 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Machine roles in a kubeadm inventory
+const (
+	MachineRoleControlPlane = "control-plane"
+	MachineRoleWorker       = "worker"
+)
+
+// adminKubectl runs kubectl on a control-plane machine with the cluster's admin credentials
+const adminKubectl = "kubectl --kubeconfig /etc/kubernetes/admin.conf"
+
+// maxKubeadmHistory caps the operations the kubeadm provider remembers
+const maxKubeadmHistory = 500
+
+// KubeadmConfig is the inventory of machines a kubeadm cluster is built from, usually kept in
+// ~/.atlas/providers/kubeadm.yaml. Each machine must already run a container runtime with
+// kubeadm, kubelet and kubectl installed and allow passwordless sudo; Atlas only drives kubeadm.
+type KubeadmConfig struct {
+	SSHUser string `yaml:"sshUser,omitempty" json:"sshUser,omitempty"`
+	SSHKey  string `yaml:"sshKey,omitempty" json:"sshKey,omitempty"`
+	SSHPort int    `yaml:"sshPort,omitempty" json:"sshPort,omitempty"`
+	// ControlPlaneEndpoint is a load-balanced address for the API server, needed to run more
+	// than one control-plane machine
+	ControlPlaneEndpoint string    `yaml:"controlPlaneEndpoint,omitempty" json:"controlPlaneEndpoint,omitempty"`
+	Machines             []Machine `yaml:"machines,omitempty" json:"machines,omitempty"`
+}
+
+// Machine is one host in a kubeadm inventory. SSH settings override the inventory's.
+type Machine struct {
+	Address string `yaml:"address" json:"address" schema:"required"`
+	// Role is control-plane or worker, the default
+	Role    string `yaml:"role,omitempty" json:"role,omitempty"`
+	Name    string `yaml:"name,omitempty" json:"name,omitempty"`
+	SSHUser string `yaml:"sshUser,omitempty" json:"sshUser,omitempty"`
+	SSHKey  string `yaml:"sshKey,omitempty" json:"sshKey,omitempty"`
+}
+
+// NodeName is the Kubernetes node name the machine joins as
+func (m Machine) NodeName() string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return m.Address
+}
+
+func (m Machine) isControlPlane() bool {
+	return m.Role == MachineRoleControlPlane
+}
+
+// sshRunner runs script as root on machine and returns its combined output
+type sshRunner func(ctx context.Context, inventory KubeadmConfig, machine Machine, script string) ([]byte, error)
+
+// KubeadmProvider implements Provider for clusters that kubeadm builds on user-supplied machines,
+// such as homelab or bare-metal servers, reached over SSH. Clusters and their operations are
+// remembered in a state file, since the machines carry no record of which cluster they are in.
+type KubeadmProvider struct {
+	mu             sync.Mutex
+	statePath      string
+	stateErr       error
+	kubeconfigPath string
+	ssh            sshRunner
+	lookPath       func(file string) (string, error)
+	logSource      *kubeadmLogSource
+	monitor        func() monitoring.Monitor
+}
+
+type kubeadmState struct {
+	Clusters map[string]*kubeadmCluster    `json:"clusters"`
+	History  []*logsource.OperationHistory `json:"history"`
+}
+
+// kubeadmCluster is a cluster built by the provider. Members are the addresses of the machines
+// joined to it, in join order, so scaling down removes the newest workers first.
+type kubeadmCluster struct {
+	Name      string            `json:"name"`
+	Version   string            `json:"version,omitempty"`
+	Inventory KubeadmConfig     `json:"inventory"`
+	Members   []string          `json:"members"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// DefaultKubeadmStatePath returns where the kubeadm provider keeps its clusters
+func DefaultKubeadmStatePath() (string, error) {
+	return store.Path("kubeadm", "clusters.json")
+}
+
+// NewKubeadmProvider creates a kubeadm provider that drives machines with the ssh CLI
+func NewKubeadmProvider() *KubeadmProvider {
+	statePath, stateErr := DefaultKubeadmStatePath()
+	k := &KubeadmProvider{
+		statePath:      statePath,
+		stateErr:       stateErr,
+		kubeconfigPath: defaultKubeconfigPath(),
+		ssh:            runSSH,
+		lookPath:       exec.LookPath,
+		monitor:        sync.OnceValue(func() monitoring.Monitor { return monitoring.NewKubeadmMonitor() }),
+	}
+	k.logSource = &kubeadmLogSource{provider: k}
+	return k
+}
+
+// defaultKubeconfigPath returns the kubeconfig file kubectl writes to
+func defaultKubeconfigPath() string {
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		return filepath.SplitList(env)[0]
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "atlas", "kubeconfig")
+	}
+	return filepath.Join(home, ".kube", "config")
+}
+
+// kubeadmContext is the kubeconfig context Atlas writes for a kubeadm cluster
+func kubeadmContext(clusterName string) string {
+	return "kubeadm-" + clusterName
+}
+
+// runSSH runs script through `sudo sh -c` unless the login user is root
+func runSSH(ctx context.Context, inventory KubeadmConfig, machine Machine, script string) ([]byte, error) {
+	cmd := subprocess.CommandContext(ctx, "ssh", sshArgs(inventory, machine, script)...)
+	return cmd.CombinedOutput()
+}
+
+func sshArgs(inventory KubeadmConfig, machine Machine, script string) []string {
+	user, key := inventory.SSHUser, inventory.SSHKey
+	if machine.SSHUser != "" {
+		user = machine.SSHUser
+	}
+	if machine.SSHKey != "" {
+		key = machine.SSHKey
+	}
+	args := []string{"-o", "BatchMode=yes", "-o", "StrictHostKeyChecking=accept-new", "-o", "ConnectTimeout=10"}
+	if key != "" {
+		args = append(args, "-i", key)
+	}
+	if inventory.SSHPort != 0 {
+		args = append(args, "-p", strconv.Itoa(inventory.SSHPort))
+	}
+	target := machine.Address
+	if user != "" {
+		target = user + "@" + target
+	}
+	remote := "sh -c " + shellQuote(script)
+	if user != "root" {
+		remote = "sudo -n " + remote
+	}
+	return append(args, target, remote)
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// GetProviderName returns the name of this provider
+func (k *KubeadmProvider) GetProviderName() string {
+	return "kubeadm"
+}
+
+// GetSupportedRegions returns the single pseudo-region kubeadm clusters are reported in
+func (k *KubeadmProvider) GetSupportedRegions() []string {
+	return []string{"homelab"}
+}
+
+// GetSupportedVersions returns the Kubernetes versions kubeadm is asked to install. The machines'
+// kubeadm and kubelet packages must match.
+func (k *KubeadmProvider) GetSupportedVersions() []string {
+	return []string{"v1.33.0", "v1.32.0", "v1.31.0", "v1.30.0"}
+}
+
+// GetLogSource returns the operations the provider has recorded
+func (k *KubeadmProvider) GetLogSource() logsource.LogSource {
+	return k.logSource
+}
+
+// GetMonitor returns the monitor for health checks and metrics collection
+func (k *KubeadmProvider) GetMonitor() monitoring.Monitor {
+	return k.monitor()
+}
+
+// HealthCheck performs a health check on the specified cluster
+func (k *KubeadmProvider) HealthCheck(ctx context.Context, clusterName string) (*monitoring.HealthStatus, error) {
+	return k.monitor().CheckClusterHealth(ctx, clusterName)
+}
+
+// CreateCluster runs kubeadm init on the first control-plane machine, installs the pod network
+// and joins NodeCount-1 more machines, control planes first
+func (k *KubeadmProvider) CreateCluster(ctx context.Context, config *ClusterConfig) (cluster *Cluster, err error) {
+	ctx = subprocess.WithOperation(ctx, "create")
+	if err := k.ValidateConfig(config); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+	started := time.Now()
+	defer func() { k.record(config.Name, logsource.OpTypeCreate, started, err) }()
+
+	inventory := *config.Kubeadm
+	members, err := selectMembers(inventory, config.NodeCount)
+	if err != nil {
+		return nil, err
+	}
+	state, err := k.load()
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := state.Clusters[config.Name]; exists {
+		return nil, fmt.Errorf("cluster %s already exists", config.Name)
+	}
+	for _, machine := range members {
+		if owner := state.owner(machine.Address); owner != "" {
+			return nil, fmt.Errorf("machine %s is already in cluster %s", machine.Address, owner)
+		}
+	}
+
+	report := func(phase string, status progress.Status, message string) {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: phase, Status: status, Message: message})
+	}
+
+	report("preflight", progress.StatusStarted, fmt.Sprintf("Checking %d machines...", len(members)))
+	for _, machine := range members {
+		if output, err := k.ssh(ctx, inventory, machine, "command -v kubeadm && command -v kubelet && command -v kubectl"); err != nil {
+			report("preflight", progress.StatusFailed, "")
+			return nil, fmt.Errorf("machine %s needs kubeadm, kubelet and kubectl installed: %w\nOutput: %s", machine.Address, err, string(output))
+		}
+	}
+	report("preflight", progress.StatusCompleted, "")
+
+	controlPlane := members[0]
+	report("init", progress.StatusStarted, fmt.Sprintf("Running kubeadm init on %s...", controlPlane.Address))
+	output, err := k.ssh(ctx, inventory, controlPlane, strings.Join(kubeadmInitArgs(config, controlPlane, len(members) > 1 && members[1].isControlPlane()), " "))
+	if err != nil {
+		report("init", progress.StatusFailed, "")
+		return nil, fmt.Errorf("failed to create cluster %s: %w\nOutput: %s", config.Name, err, string(output))
+	}
+	certificateKey := parseCertificateKey(string(output))
+	report("init", progress.StatusCompleted, "")
+
+	// from here on the cluster exists on the machines, so remember it even if a later step fails
+	if err := k.update(func(state *kubeadmState) error {
+		state.Clusters[config.Name] = &kubeadmCluster{
+			Name:      config.Name,
+			Version:   config.Version,
+			Inventory: inventory,
+			Members:   []string{controlPlane.Address},
+			Tags:      config.Tags,
+			CreatedAt: time.Now().UTC(),
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	manifest := podNetworkManifest(config)
+	report("network", progress.StatusStarted, "Installing the pod network...")
+	if output, err := k.ssh(ctx, inventory, controlPlane, adminKubectl+" apply -f "+manifest); err != nil {
+		report("network", progress.StatusFailed, "")
+		return nil, fmt.Errorf("failed to install pod network from %s: %w\nOutput: %s", manifest, err, string(output))
+	}
+	report("network", progress.StatusCompleted, "")
+
+	if err := k.joinMachines(ctx, config.Name, inventory, controlPlane, members[1:], certificateKey); err != nil {
+		return nil, err
+	}
+
+	report("kubeconfig", progress.StatusStarted, "Fetching admin credentials...")
+	adminConf, err := k.ssh(ctx, inventory, controlPlane, "cat /etc/kubernetes/admin.conf")
+	if err != nil {
+		report("kubeconfig", progress.StatusFailed, "")
+		return nil, fmt.Errorf("failed to read admin kubeconfig from %s: %w", controlPlane.Address, err)
+	}
+	if err := mergeKubeconfig(k.kubeconfigPath, adminConf, kubeadmContext(config.Name)); err != nil {
+		report("kubeconfig", progress.StatusFailed, "")
+		return nil, err
+	}
+	report("kubeconfig", progress.StatusCompleted, fmt.Sprintf("Added context %s to %s", kubeadmContext(config.Name), k.kubeconfigPath))
+
+	kubectl := []string{"kubectl", "--context", kubeadmContext(config.Name)}
+	resources, err := applyBootstrapManifests(ctx, config.Name, kubectl, config.BootstrapManifests)
+	if err != nil {
+		report("bootstrap", progress.StatusWarning, fmt.Sprintf("failed to apply bootstrap manifests: %v", err))
+	}
+	if err := installLogging(ctx, kubeadmContext(config.Name), "", config); err != nil {
+		report("logging", progress.StatusWarning, err.Error())
+	}
+	tracingResources, err := installTracing(ctx, kubeadmContext(config.Name), config)
+	resources = append(resources, tracingResources...)
+	if err != nil {
+		report("tracing", progress.StatusWarning, err.Error())
+	}
+
+	report("done", progress.StatusCompleted, fmt.Sprintf("Successfully created cluster: %s", config.Name))
+	cluster, err = k.GetCluster(ctx, config.Name)
+	if err != nil {
+		return nil, err
+	}
+	cluster.Resources = resources
+	return cluster, nil
+}
+
+// joinMachines joins machines to the cluster one at a time, recording each as a member once it
+// has joined
+func (k *KubeadmProvider) joinMachines(ctx context.Context, clusterName string, inventory KubeadmConfig, controlPlane Machine, machines []Machine, certificateKey string) error {
+	if len(machines) == 0 {
+		return nil
+	}
+	output, err := k.ssh(ctx, inventory, controlPlane, "kubeadm token create --print-join-command")
+	if err != nil {
+		return fmt.Errorf("failed to create join token: %w\nOutput: %s", err, string(output))
+	}
+	join, err := parseJoinCommand(string(output))
+	if err != nil {
+		return err
+	}
+
+	for _, machine := range machines {
+		progress.Report(ctx, progress.Event{Cluster: clusterName, Operation: "join", Phase: machine.NodeName(), Status: progress.StatusStarted,
+			Message: fmt.Sprintf("Joining %s as a %s...", machine.Address, roleOrWorker(machine.Role))})
+		script := join + " --node-name " + machine.NodeName()
+		if machine.isControlPlane() {
+			if certificateKey == "" {
+				return fmt.Errorf("cannot join control-plane machine %s: kubeadm init did not upload certificates", machine.Address)
+			}
+			script += " --control-plane --certificate-key " + certificateKey
+		}
+		if output, err := k.ssh(ctx, inventory, machine, script); err != nil {
+			progress.Report(ctx, progress.Event{Cluster: clusterName, Operation: "join", Phase: machine.NodeName(), Status: progress.StatusFailed})
+			return fmt.Errorf("failed to join %s to cluster %s: %w\nOutput: %s", machine.Address, clusterName, err, string(output))
+		}
+		if err := k.update(func(state *kubeadmState) error {
+			if cluster := state.Clusters[clusterName]; cluster != nil {
+				cluster.Members = append(cluster.Members, machine.Address)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		progress.Report(ctx, progress.Event{Cluster: clusterName, Operation: "join", Phase: machine.NodeName(), Status: progress.StatusCompleted})
+	}
+	return nil
+}
+
+func roleOrWorker(role string) string {
+	if role == "" {
+		return MachineRoleWorker
+	}
+	return role
+}
+
+// selectMembers picks the machines a new cluster of nodeCount nodes starts with: control-plane
+// machines first, then workers, each in inventory order
+func selectMembers(inventory KubeadmConfig, nodeCount int) ([]Machine, error) {
+	if nodeCount < 1 {
+		nodeCount = 1
+	}
+	var controlPlanes, workers []Machine
+	for _, machine := range inventory.Machines {
+		if machine.isControlPlane() {
+			controlPlanes = append(controlPlanes, machine)
+		} else {
+			workers = append(workers, machine)
+		}
+	}
+	if len(controlPlanes) == 0 {
+		return nil, fmt.Errorf("the kubeadm inventory has no control-plane machine")
+	}
+	ordered := append(controlPlanes, workers...)
+	if nodeCount > len(ordered) {
+		return nil, fmt.Errorf("%d nodes requested but the kubeadm inventory lists %d machines", nodeCount, len(ordered))
+	}
+	return ordered[:nodeCount], nil
+}
+
+// kubeadmInitArgs builds the kubeadm init command for the first control-plane machine.
+// Certificates are uploaded when more control-plane machines will join.
+func kubeadmInitArgs(config *ClusterConfig, machine Machine, uploadCerts bool) []string {
+	args := []string{"kubeadm", "init", "--node-name", machine.NodeName(), "--pod-network-cidr", podCIDR(config)}
+	if config.Version != "" {
+		args = append(args, "--kubernetes-version", "v"+strings.TrimPrefix(config.Version, "v"))
+	}
+	if network := config.NetworkConfig; network != nil {
+		if network.ServiceCIDR != "" {
+			args = append(args, "--service-cidr", network.ServiceCIDR)
+		}
+		if network.APIServerPort > 0 {
+			args = append(args, "--apiserver-bind-port", strconv.Itoa(network.APIServerPort))
+		}
+	}
+	if endpoint := config.Kubeadm.ControlPlaneEndpoint; endpoint != "" {
+		args = append(args, "--control-plane-endpoint", endpoint)
+	}
+	if uploadCerts {
+		args = append(args, "--upload-certs")
+	}
+	return args
+}
+
+// podNetworkPlugin returns the CNI to install, flannel unless calico is asked for
+func podNetworkPlugin(config *ClusterConfig) string {
+	if network := config.NetworkConfig; network != nil && network.NetworkPlugin == "calico" {
+		return "calico"
+	}
+	return "flannel"
+}
+
+// podCIDR returns the configured pod CIDR, or the one the pod network's manifest expects
+func podCIDR(config *ClusterConfig) string {
+	if network := config.NetworkConfig; network != nil && network.PodCIDR != "" {
+		return network.PodCIDR
+	}
+	if podNetworkPlugin(config) == "calico" {
+		return "192.168.0.0/16"
+	}
+	return "10.244.0.0/16"
+}
+
+// podNetworkManifest returns the URL of the pod network manifest to apply
+func podNetworkManifest(config *ClusterConfig) string {
+	if podNetworkPlugin(config) == "calico" {
+		return "https://raw.githubusercontent.com/projectcalico/calico/v3.28.0/manifests/calico.yaml"
+	}
+	return "https://github.com/flannel-io/flannel/releases/latest/download/kube-flannel.yml"
+}
+
+// parseJoinCommand finds the command printed by `kubeadm token create --print-join-command`
+func parseJoinCommand(output string) (string, error) {
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, "kubeadm join ") {
+			return line, nil
+		}
+	}
+	return "", fmt.Errorf("kubeadm did not print a join command")
+}
+
+// parseCertificateKey finds the key kubeadm init --upload-certs encrypted the certificates with
+func parseCertificateKey(output string) string {
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		if strings.Contains(line, "Using certificate key:") && i+1 < len(lines) {
+			return strings.TrimSpace(lines[i+1])
+		}
+	}
+	return ""
+}
+
+// mergeKubeconfig adds the current context of adminConf to the kubeconfig at path under
+// contextName and makes it current
+func mergeKubeconfig(path string, adminConf []byte, contextName string) error {
+	incoming, err := clientcmd.Load(adminConf)
+	if err != nil {
+		return fmt.Errorf("failed to parse admin kubeconfig: %w", err)
+	}
+	source := incoming.Contexts[incoming.CurrentContext]
+	if source == nil || incoming.Clusters[source.Cluster] == nil || incoming.AuthInfos[source.AuthInfo] == nil {
+		return fmt.Errorf("admin kubeconfig has no usable current context")
+	}
+
+	config, err := loadKubeconfig(path)
+	if err != nil {
+		return err
+	}
+	config.Clusters[contextName] = incoming.Clusters[source.Cluster]
+	config.AuthInfos[contextName] = incoming.AuthInfos[source.AuthInfo]
+	config.Contexts[contextName] = &clientcmdapi.Context{Cluster: contextName, AuthInfo: contextName}
+	config.CurrentContext = contextName
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create kubeconfig directory: %w", err)
+	}
+	if err := clientcmd.WriteToFile(*config, path); err != nil {
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	return nil
+}
+
+// removeKubeconfigContext deletes contextName and the cluster and user it refers to
+func removeKubeconfigContext(path, contextName string) error {
+	config, err := loadKubeconfig(path)
+	if err != nil {
+		return err
+	}
+	if _, ok := config.Contexts[contextName]; !ok {
+		return nil
+	}
+	delete(config.Clusters, contextName)
+	delete(config.AuthInfos, contextName)
+	delete(config.Contexts, contextName)
+	if config.CurrentContext == contextName {
+		config.CurrentContext = ""
+	}
+	if err := clientcmd.WriteToFile(*config, path); err != nil {
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	return nil
+}
+
+func loadKubeconfig(path string) (*clientcmdapi.Config, error) {
+	config, err := clientcmd.LoadFromFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return clientcmdapi.NewConfig(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kubeconfig %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// DeleteCluster resets every member machine, workers first, and forgets the cluster. A machine
+// that could not be reset keeps the cluster in state so the delete can be retried.
+func (k *KubeadmProvider) DeleteCluster(ctx context.Context, name string) (err error) {
+	ctx = subprocess.WithOperation(ctx, "delete")
+	started := time.Now()
+	defer func() { k.record(name, logsource.OpTypeDelete, started, err) }()
+
+	cluster, err := k.getState(name)
+	if err != nil {
+		return err
+	}
+	members := cluster.members()
+	var failed []string
+	for i := len(members) - 1; i >= 0; i-- {
+		machine := members[i]
+		progress.Report(ctx, progress.Event{Cluster: name, Operation: "delete", Phase: machine.NodeName(), Status: progress.StatusStarted,
+			Message: fmt.Sprintf("Resetting %s...", machine.Address)})
+		if output, err := k.ssh(ctx, cluster.Inventory, machine, resetScript); err != nil {
+			progress.Report(ctx, progress.Event{Cluster: name, Operation: "delete", Phase: machine.NodeName(), Status: progress.StatusFailed})
+			failed = append(failed, fmt.Sprintf("%s: %v: %s", machine.Address, err, strings.TrimSpace(string(output))))
+			continue
+		}
+		progress.Report(ctx, progress.Event{Cluster: name, Operation: "delete", Phase: machine.NodeName(), Status: progress.StatusCompleted})
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to delete cluster %s: could not reset %s", name, strings.Join(failed, "; "))
+	}
+
+	if err := k.update(func(state *kubeadmState) error {
+		delete(state.Clusters, name)
+		return nil
+	}); err != nil {
+		return err
+	}
+	return removeKubeconfigContext(k.kubeconfigPath, kubeadmContext(name))
+}
+
+// resetScript undoes kubeadm init or join on a machine
+const resetScript = "kubeadm reset -f && rm -rf /etc/cni/net.d /root/.kube"
+
+// StartCluster is not supported: the machines are powered and managed outside Atlas
+func (k *KubeadmProvider) StartCluster(ctx context.Context, name string) error {
+	return fmt.Errorf("the kubeadm provider cannot start clusters; power on the machines of %s instead", name)
+}
+
+// StopCluster is not supported: the machines are powered and managed outside Atlas
+func (k *KubeadmProvider) StopCluster(ctx context.Context, name string) error {
+	return fmt.Errorf("the kubeadm provider cannot stop clusters; shut down the machines of %s instead", name)
+}
+
+// ScaleCluster joins unused worker machines from the cluster's inventory, or drains and resets
+// the most recently joined workers, until the cluster has nodeCount nodes
+func (k *KubeadmProvider) ScaleCluster(ctx context.Context, name string, nodeCount int) (err error) {
+	ctx = subprocess.WithOperation(ctx, "scale")
+	started := time.Now()
+	defer func() { k.record(name, logsource.OpTypeScale, started, err) }()
+
+	state, err := k.load()
+	if err != nil {
+		return err
+	}
+	cluster, ok := state.Clusters[name]
+	if !ok {
+		return clusterNotFound(name)
+	}
+	members := cluster.members()
+	controlPlane := members[0]
+
+	if nodeCount > len(members) {
+		var spare []Machine
+		for _, machine := range cluster.Inventory.Machines {
+			if !machine.isControlPlane() && state.owner(machine.Address) == "" {
+				spare = append(spare, machine)
+			}
+		}
+		needed := nodeCount - len(members)
+		if needed > len(spare) {
+			return fmt.Errorf("cannot scale cluster %s to %d nodes: the inventory has %d unused worker machines", name, nodeCount, len(spare))
+		}
+		return k.joinMachines(ctx, name, cluster.Inventory, controlPlane, spare[:needed], "")
+	}
+
+	controlPlanes := 0
+	for _, machine := range members {
+		if machine.isControlPlane() {
+			controlPlanes++
+		}
+	}
+	if nodeCount < controlPlanes {
+		return fmt.Errorf("cannot scale cluster %s to %d nodes: only workers are removed and it has %d control-plane machines", name, nodeCount, controlPlanes)
+	}
+	remaining := len(members)
+	for i := len(members) - 1; i >= 0 && remaining > nodeCount; i-- {
+		machine := members[i]
+		if machine.isControlPlane() {
+			continue
+		}
+		progress.Report(ctx, progress.Event{Cluster: name, Operation: "scale", Phase: machine.NodeName(), Status: progress.StatusStarted,
+			Message: fmt.Sprintf("Draining and removing %s...", machine.NodeName())})
+		drain := fmt.Sprintf("%s drain %s --ignore-daemonsets --delete-emptydir-data --force --timeout 5m && %s delete node %s",
+			adminKubectl, machine.NodeName(), adminKubectl, machine.NodeName())
+		if output, err := k.ssh(ctx, cluster.Inventory, controlPlane, drain); err != nil {
+			return fmt.Errorf("failed to remove node %s: %w\nOutput: %s", machine.NodeName(), err, string(output))
+		}
+		if output, err := k.ssh(ctx, cluster.Inventory, machine, resetScript); err != nil {
+			return fmt.Errorf("failed to reset %s: %w\nOutput: %s", machine.Address, err, string(output))
+		}
+		if err := k.update(func(state *kubeadmState) error {
+			if cluster := state.Clusters[name]; cluster != nil {
+				cluster.Members = removeString(cluster.Members, machine.Address)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		remaining--
+		progress.Report(ctx, progress.Event{Cluster: name, Operation: "scale", Phase: machine.NodeName(), Status: progress.StatusCompleted})
+	}
+	return nil
+}
+
+func removeString(values []string, value string) []string {
+	var kept []string
+	for _, v := range values {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// GetCluster reports a cluster from state. It is running when its API server answers through
+// the first control-plane machine, and in error otherwise.
+func (k *KubeadmProvider) GetCluster(ctx context.Context, name string) (*Cluster, error) {
+	cluster, err := k.getState(name)
+	if err != nil {
+		return nil, err
+	}
+	info := cluster.info()
+	members := cluster.members()
+	if _, err := k.ssh(ctx, cluster.Inventory, members[0], adminKubectl+" get --raw /readyz"); err != nil {
+		info.Status = ClusterStatusError
+	}
+	return info, nil
+}
+
+// ListClusters lists the clusters the provider has built
+func (k *KubeadmProvider) ListClusters(ctx context.Context) ([]*Cluster, error) {
+	state, err := k.load()
+	if err != nil {
+		return nil, err
+	}
+	var clusters []*Cluster
+	for _, name := range sortedClusterNames(state.Clusters) {
+		cluster, err := k.GetCluster(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+func sortedClusterNames(clusters map[string]*kubeadmCluster) []string {
+	names := make([]string, 0, len(clusters))
+	for name := range clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// members returns the inventory entries of the cluster's member machines, in join order
+func (c *kubeadmCluster) members() []Machine {
+	byAddress := make(map[string]Machine)
+	for _, machine := range c.Inventory.Machines {
+		byAddress[machine.Address] = machine
+	}
+	machines := make([]Machine, 0, len(c.Members))
+	for _, address := range c.Members {
+		machine, ok := byAddress[address]
+		if !ok {
+			machine = Machine{Address: address}
+		}
+		machines = append(machines, machine)
+	}
+	return machines
+}
+
+func (c *kubeadmCluster) info() *Cluster {
+	endpoint := c.Inventory.ControlPlaneEndpoint
+	if endpoint == "" && len(c.Members) > 0 {
+		endpoint = c.Members[0] + ":6443"
+	}
+	tags := make(map[string]string)
+	for key, value := range c.Tags {
+		tags[key] = value
+	}
+	return &Cluster{
+		Name:      c.Name,
+		Provider:  "kubeadm",
+		Region:    "homelab",
+		Status:    ClusterStatusRunning,
+		NodeCount: len(c.Members),
+		Version:   c.Version,
+		Endpoint:  "https://" + endpoint,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: time.Now(),
+		Tags:      tags,
+	}
+}
+
+// owner returns the cluster a machine is a member of, or ""
+func (s *kubeadmState) owner(address string) string {
+	for name, cluster := range s.Clusters {
+		for _, member := range cluster.Members {
+			if member == address {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+func (k *KubeadmProvider) getState(name string) (*kubeadmCluster, error) {
+	state, err := k.load()
+	if err != nil {
+		return nil, err
+	}
+	cluster, ok := state.Clusters[name]
+	if !ok || len(cluster.Members) == 0 {
+		return nil, clusterNotFound(name)
+	}
+	return cluster, nil
+}
+
+func (k *KubeadmProvider) load() (*kubeadmState, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.loadLocked()
+}
+
+func (k *KubeadmProvider) loadLocked() (*kubeadmState, error) {
+	if k.stateErr != nil {
+		return nil, k.stateErr
+	}
+	state := &kubeadmState{Clusters: make(map[string]*kubeadmCluster)}
+	if err := store.LoadJSON(k.statePath, state); err != nil {
+		return nil, err
+	}
+	if state.Clusters == nil {
+		state.Clusters = make(map[string]*kubeadmCluster)
+	}
+	return state, nil
+}
+
+func (k *KubeadmProvider) update(apply func(state *kubeadmState) error) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.stateErr != nil {
+		return k.stateErr
+	}
+	state := &kubeadmState{}
+	return store.UpdateJSON(k.statePath, state, func() error {
+		if state.Clusters == nil {
+			state.Clusters = make(map[string]*kubeadmCluster)
+		}
+		return apply(state)
+	})
+}
+
+// record adds an operation to the provider's history
+func (k *KubeadmProvider) record(cluster string, opType logsource.OperationType, started time.Time, opErr error) {
+	completed := time.Now()
+	duration := float64(completed.Sub(started).Milliseconds())
+	entry := &logsource.OperationHistory{
+		ClusterName:     cluster,
+		OperationType:   opType,
+		OperationStatus: logsource.OpStatusCompleted,
+		StartedAt:       started,
+		CompletedAt:     &completed,
+		DurationMS:      &duration,
+		UserID:          currentUser(),
+	}
+	if opErr != nil {
+		entry.OperationStatus = logsource.OpStatusFailed
+		entry.ErrorMessage = opErr.Error()
+	}
+	k.update(func(state *kubeadmState) error {
+		entry.ID = 1
+		if len(state.History) > 0 {
+			entry.ID = state.History[len(state.History)-1].ID + 1
+		}
+		state.History = append(state.History, entry)
+		if len(state.History) > maxKubeadmHistory {
+			state.History = state.History[len(state.History)-maxKubeadmHistory:]
+		}
+		return nil
+	})
+}
+
+// ValidateConfig validates the cluster configuration for the kubeadm provider
+func (k *KubeadmProvider) ValidateConfig(config *ClusterConfig) error {
+	return k.Validate(config).Err()
+}
+
+// Validate reports every problem with the cluster configuration and its machine inventory.
+// Settings that only apply to machines Atlas creates are reported as warnings.
+func (k *KubeadmProvider) Validate(config *ClusterConfig) *ValidationResult {
+	result := &ValidationResult{}
+
+	if config.Name == "" {
+		result.Errorf("name", "cluster name is required")
+	} else if strings.Contains(config.Name, " ") {
+		result.Errorf("name", "cluster name cannot contain spaces")
+	}
+	// ssh -V prints to stderr and exits 0 on some builds and not others, so only check the PATH
+	if _, err := k.lookPath("ssh"); err != nil {
+		result.Errorf("", "ssh is not installed or not in PATH")
+	}
+
+	inventory := config.Kubeadm
+	if inventory == nil || len(inventory.Machines) == 0 {
+		result.Errorf("kubeadm.machines", "no machines listed; add them to the cluster config or %s", defaultsHint("kubeadm"))
+		return result
+	}
+	seen := make(map[string]bool)
+	controlPlanes := 0
+	for i, machine := range inventory.Machines {
+		field := fmt.Sprintf("kubeadm.machines[%d]", i)
+		if machine.Address == "" {
+			result.Errorf(field+".address", "address is required")
+		} else if seen[machine.Address] {
+			result.Errorf(field+".address", "machine %s is listed twice", machine.Address)
+		}
+		seen[machine.Address] = true
+		switch machine.Role {
+		case MachineRoleControlPlane:
+			controlPlanes++
+		case "", MachineRoleWorker:
+		default:
+			result.Errorf(field+".role", "role must be %s or %s, not %s", MachineRoleControlPlane, MachineRoleWorker, machine.Role)
+		}
+	}
+	if controlPlanes == 0 {
+		result.Errorf("kubeadm.machines", "at least one machine needs role %s", MachineRoleControlPlane)
+	}
+	if config.NodeCount > len(inventory.Machines) {
+		result.Errorf("nodeCount", "%d nodes requested but only %d machines are listed", config.NodeCount, len(inventory.Machines))
+	}
+	if members, err := selectMembers(*inventory, config.NodeCount); err == nil && len(members) > 1 && members[1].isControlPlane() && inventory.ControlPlaneEndpoint == "" {
+		result.Errorf("kubeadm.controlPlaneEndpoint", "a load-balanced endpoint is required to run more than one control-plane machine")
+	}
+
+	if config.InstanceType != "" {
+		result.Warnf("instanceType", "instance type %s is ignored by the kubeadm provider", config.InstanceType)
+	}
+	if config.DiskSize != "" {
+		result.Warnf("diskSize", "disk size is ignored by the kubeadm provider; nodes use the machines' disks")
+	}
+	if len(config.Mounts) > 0 {
+		result.Warnf("mounts", "mounts are ignored by the kubeadm provider")
+	}
+	if network := config.NetworkConfig; network != nil {
+		if plugin := network.NetworkPlugin; plugin != "" && plugin != "auto" && plugin != "flannel" && plugin != "calico" {
+			result.Errorf("networkConfig.networkPlugin", "the kubeadm provider installs flannel or calico, not %s", plugin)
+		}
+		if len(network.ExtraPortMaps) > 0 {
+			result.Warnf("networkConfig.extraPortMaps", "port mappings are ignored by the kubeadm provider; services are reached on the machines' addresses")
+		}
+	}
+
+	validateBootstrapManifests(config.BootstrapManifests, result)
+	validateNetworkConfig(config.NetworkConfig, result)
+	validateSecurityConfig(config.SecurityConfig, result)
+	validateLogConfig(config, false, result)
+	validateTracingConfig(config, result)
+	return result
+}
+
+// kubeadmLogSource serves the operations the kubeadm provider records
+type kubeadmLogSource struct {
+	provider *KubeadmProvider
+}
+
+func (s *kubeadmLogSource) GetSourceName() string {
+	return "kubeadm"
+}
+
+func (s *kubeadmLogSource) GetClusterHistory(ctx context.Context, clusterName string, limit int) ([]*logsource.OperationHistory, error) {
+	all, err := s.GetAllClustersHistory(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return all[clusterName], nil
+}
+
+// GetAllClustersHistory returns each cluster's operations newest first
+func (s *kubeadmLogSource) GetAllClustersHistory(ctx context.Context, limit int) (map[string][]*logsource.OperationHistory, error) {
+	state, err := s.provider.load()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]*logsource.OperationHistory)
+	for i := len(state.History) - 1; i >= 0; i-- {
+		op := state.History[i]
+		if limit > 0 && len(result[op.ClusterName]) >= limit {
+			continue
+		}
+		result[op.ClusterName] = append(result[op.ClusterName], op)
+	}
+	return result, nil
+}
+
+var _ Provider = (*KubeadmProvider)(nil)
+var _ ConfigValidator = (*KubeadmProvider)(nil)
+var _ logsource.LogSource = (*kubeadmLogSource)(nil)
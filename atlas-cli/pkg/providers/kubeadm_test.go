@@ -0,0 +1,339 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const testAdminConf = `apiVersion: v1
+kind: Config
+clusters:
+- name: kubernetes
+  cluster:
+    server: https://10.0.0.1:6443
+contexts:
+- name: kubernetes-admin@kubernetes
+  context:
+    cluster: kubernetes
+    user: kubernetes-admin
+current-context: kubernetes-admin@kubernetes
+users:
+- name: kubernetes-admin
+  user:
+    token: secret
+`
+
+// fakeSSH answers kubeadm's commands like a set of healthy machines and records each call as
+// "address: script"
+type fakeSSH struct {
+	mu    sync.Mutex
+	calls []string
+	fail  map[string]bool
+}
+
+func (f *fakeSSH) run(ctx context.Context, inventory KubeadmConfig, machine Machine, script string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, machine.Address+": "+script)
+	if f.fail[machine.Address] {
+		return []byte("connection refused"), fmt.Errorf("exit status 255")
+	}
+	switch {
+	case strings.HasPrefix(script, "kubeadm init"):
+		return []byte("[upload-certs] Using certificate key:\nabc123\n"), nil
+	case strings.HasPrefix(script, "kubeadm token create"):
+		return []byte("kubeadm join 10.0.0.1:6443 --token t.x --discovery-token-ca-cert-hash sha256:ff\n"), nil
+	case script == "cat /etc/kubernetes/admin.conf":
+		return []byte(testAdminConf), nil
+	}
+	return nil, nil
+}
+
+func (f *fakeSSH) callsMatching(prefix string) []string {
+	var matched []string
+	for _, call := range f.calls {
+		if _, script, _ := strings.Cut(call, ": "); strings.HasPrefix(script, prefix) {
+			matched = append(matched, call)
+		}
+	}
+	return matched
+}
+
+func newTestKubeadmProvider(t *testing.T) (*KubeadmProvider, *fakeSSH) {
+	dir := t.TempDir()
+	ssh := &fakeSSH{fail: make(map[string]bool)}
+	k := NewKubeadmProvider()
+	k.statePath = filepath.Join(dir, "clusters.json")
+	k.kubeconfigPath = filepath.Join(dir, "kubeconfig")
+	k.ssh = ssh.run
+	k.lookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+	return k, ssh
+}
+
+func testInventory() *KubeadmConfig {
+	return &KubeadmConfig{
+		SSHUser: "admin",
+		Machines: []Machine{
+			{Address: "10.0.0.2", Name: "worker-1"},
+			{Address: "10.0.0.1", Name: "cp-1", Role: MachineRoleControlPlane},
+			{Address: "10.0.0.3", Name: "worker-2", Role: MachineRoleWorker},
+		},
+	}
+}
+
+func TestSSHArgs(t *testing.T) {
+	inventory := KubeadmConfig{SSHUser: "admin", SSHKey: "~/.ssh/lab", SSHPort: 2222}
+	got := strings.Join(sshArgs(inventory, Machine{Address: "10.0.0.1"}, "echo 'hi'"), " ")
+	want := `-o BatchMode=yes -o StrictHostKeyChecking=accept-new -o ConnectTimeout=10 -i ~/.ssh/lab -p 2222 admin@10.0.0.1 sudo -n sh -c 'echo '\''hi'\'''`
+	if got != want {
+		t.Errorf("sshArgs() =\n%s\nwant\n%s", got, want)
+	}
+
+	root := sshArgs(inventory, Machine{Address: "10.0.0.2", SSHUser: "root", SSHKey: "/keys/root"}, "true")
+	if got := strings.Join(root[len(root)-2:], " "); got != "root@10.0.0.2 sh -c 'true'" {
+		t.Errorf("sshArgs() for root ends with %s, want no sudo", got)
+	}
+	if !strings.Contains(strings.Join(root, " "), "-i /keys/root") {
+		t.Errorf("sshArgs() = %v, want the machine's key", root)
+	}
+}
+
+func TestKubeadmInitArgs(t *testing.T) {
+	config := &ClusterConfig{
+		Name:          "lab",
+		Version:       "1.32.0",
+		NetworkConfig: &NetworkConfig{ServiceCIDR: "10.96.0.0/12", NetworkPlugin: "calico"},
+		Kubeadm:       &KubeadmConfig{ControlPlaneEndpoint: "lab.internal:6443"},
+	}
+	got := strings.Join(kubeadmInitArgs(config, Machine{Address: "10.0.0.1", Name: "cp-1"}, true), " ")
+	want := "kubeadm init --node-name cp-1 --pod-network-cidr 192.168.0.0/16 --kubernetes-version v1.32.0" +
+		" --service-cidr 10.96.0.0/12 --control-plane-endpoint lab.internal:6443 --upload-certs"
+	if got != want {
+		t.Errorf("kubeadmInitArgs() =\n%s\nwant\n%s", got, want)
+	}
+
+	minimal := strings.Join(kubeadmInitArgs(&ClusterConfig{Name: "lab", Kubeadm: &KubeadmConfig{}}, Machine{Address: "10.0.0.1"}, false), " ")
+	if minimal != "kubeadm init --node-name 10.0.0.1 --pod-network-cidr 10.244.0.0/16" {
+		t.Errorf("kubeadmInitArgs() = %s", minimal)
+	}
+}
+
+func TestSelectMembers(t *testing.T) {
+	members, err := selectMembers(*testInventory(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 2 || members[0].Name != "cp-1" || members[1].Name != "worker-1" {
+		t.Errorf("selectMembers() = %+v, want cp-1 then worker-1", members)
+	}
+	if _, err := selectMembers(*testInventory(), 4); err == nil {
+		t.Error("selectMembers() with too few machines should fail")
+	}
+	if _, err := selectMembers(KubeadmConfig{Machines: []Machine{{Address: "10.0.0.2"}}}, 1); err == nil {
+		t.Error("selectMembers() without a control plane should fail")
+	}
+}
+
+func TestParseKubeadmOutput(t *testing.T) {
+	join, err := parseJoinCommand("W0101 warning\nkubeadm join 10.0.0.1:6443 --token t.x --discovery-token-ca-cert-hash sha256:ff \n")
+	if err != nil || join != "kubeadm join 10.0.0.1:6443 --token t.x --discovery-token-ca-cert-hash sha256:ff" {
+		t.Errorf("parseJoinCommand() = %q, %v", join, err)
+	}
+	if _, err := parseJoinCommand("error"); err == nil {
+		t.Error("parseJoinCommand() without a join command should fail")
+	}
+	if key := parseCertificateKey("[upload-certs] Using certificate key:\n  abc123\n"); key != "abc123" {
+		t.Errorf("parseCertificateKey() = %q, want abc123", key)
+	}
+}
+
+func TestKubeadmProvider_Lifecycle(t *testing.T) {
+	k, ssh := newTestKubeadmProvider(t)
+	ctx := context.Background()
+
+	cluster, err := k.CreateCluster(ctx, &ClusterConfig{Name: "lab", Version: "1.32.0", NodeCount: 2, Kubeadm: testInventory()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cluster.Status != ClusterStatusRunning || cluster.NodeCount != 2 || cluster.Endpoint != "https://10.0.0.1:6443" {
+		t.Errorf("CreateCluster() = %+v", cluster)
+	}
+	if joins := ssh.callsMatching("kubeadm join"); len(joins) != 1 || !strings.HasPrefix(joins[0], "10.0.0.2: ") || strings.Contains(joins[0], "--control-plane") {
+		t.Errorf("joins = %v, want worker-1 joined as a worker", joins)
+	}
+
+	kubeconfig, err := clientcmd.LoadFromFile(k.kubeconfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kubeconfig.CurrentContext != "kubeadm-lab" || kubeconfig.Clusters["kubeadm-lab"].Server != "https://10.0.0.1:6443" {
+		t.Errorf("kubeconfig current context = %s, clusters = %v", kubeconfig.CurrentContext, kubeconfig.Clusters)
+	}
+
+	if _, err := k.CreateCluster(ctx, &ClusterConfig{Name: "other", NodeCount: 1, Kubeadm: testInventory()}); err == nil {
+		t.Error("CreateCluster() on machines already in use should fail")
+	}
+
+	if err := k.ScaleCluster(ctx, "lab", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.ScaleCluster(ctx, "lab", 4); err == nil {
+		t.Error("ScaleCluster() past the inventory should fail")
+	}
+	if err := k.ScaleCluster(ctx, "lab", 2); err != nil {
+		t.Fatal(err)
+	}
+	if resets := ssh.callsMatching("kubeadm reset"); len(resets) != 1 || !strings.HasPrefix(resets[0], "10.0.0.3: ") {
+		t.Errorf("resets = %v, want the newest worker reset", resets)
+	}
+	if err := k.ScaleCluster(ctx, "lab", 0); err == nil {
+		t.Error("ScaleCluster() should not remove the control plane")
+	}
+
+	ssh.fail["10.0.0.1"] = true
+	cluster, err = k.GetCluster(ctx, "lab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cluster.Status != ClusterStatusError || cluster.NodeCount != 2 {
+		t.Errorf("GetCluster() with an unreachable control plane = %+v", cluster)
+	}
+	if err := k.DeleteCluster(ctx, "lab"); err == nil {
+		t.Error("DeleteCluster() with an unreachable machine should fail")
+	}
+	if _, err := k.GetCluster(ctx, "lab"); err != nil {
+		t.Errorf("cluster should be kept after a failed delete: %v", err)
+	}
+
+	delete(ssh.fail, "10.0.0.1")
+	if err := k.DeleteCluster(ctx, "lab"); err != nil {
+		t.Fatal(err)
+	}
+	if clusters, err := k.ListClusters(ctx); err != nil || len(clusters) != 0 {
+		t.Errorf("ListClusters() after delete = %v, %v", clusters, err)
+	}
+	kubeconfig, err = clientcmd.LoadFromFile(k.kubeconfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := kubeconfig.Contexts["kubeadm-lab"]; ok {
+		t.Error("DeleteCluster() should remove the kubeconfig context")
+	}
+
+	history, err := k.GetLogSource().GetClusterHistory(ctx, "lab", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ops []string
+	for _, op := range history {
+		ops = append(ops, string(op.OperationType)+"/"+string(op.OperationStatus))
+	}
+	if got, want := strings.Join(ops, ","), "delete/completed,delete/failed,scale/failed,scale/completed,scale/failed,scale/completed,create/completed"; got != want {
+		t.Errorf("history = %s, want %s", got, want)
+	}
+}
+
+func TestKubeadmProvider_HighAvailability(t *testing.T) {
+	k, ssh := newTestKubeadmProvider(t)
+	inventory := testInventory()
+	inventory.ControlPlaneEndpoint = "lab.internal:6443"
+	inventory.Machines = append(inventory.Machines, Machine{Address: "10.0.0.4", Name: "cp-2", Role: MachineRoleControlPlane})
+
+	if _, err := k.CreateCluster(context.Background(), &ClusterConfig{Name: "ha", NodeCount: 3, Kubeadm: inventory}); err != nil {
+		t.Fatal(err)
+	}
+	if inits := ssh.callsMatching("kubeadm init"); len(inits) != 1 || !strings.Contains(inits[0], "--upload-certs") {
+		t.Errorf("init = %v, want certificates uploaded", inits)
+	}
+	joins := ssh.callsMatching("kubeadm join")
+	if len(joins) != 2 || !strings.HasPrefix(joins[0], "10.0.0.4: ") || !strings.Contains(joins[0], "--control-plane --certificate-key abc123") {
+		t.Errorf("joins = %v, want cp-2 joined as a control plane first", joins)
+	}
+}
+
+func TestKubeadmProvider_Validate(t *testing.T) {
+	config := &ClusterConfig{
+		Name:         "lab",
+		NodeCount:    4,
+		InstanceType: "m5.large",
+		Kubeadm: &KubeadmConfig{Machines: []Machine{
+			{Address: "10.0.0.1", Role: MachineRoleControlPlane},
+			{Address: "10.0.0.2", Role: MachineRoleControlPlane},
+			{Address: "10.0.0.2", Role: "master"},
+		}},
+	}
+
+	result := NewKubeadmProvider().Validate(config)
+	var errorFields, warningFields []string
+	for _, issue := range result.Errors() {
+		if issue.Field != "" {
+			errorFields = append(errorFields, issue.Field)
+		}
+	}
+	for _, issue := range result.Warnings() {
+		warningFields = append(warningFields, issue.Field)
+	}
+	if got, want := strings.Join(errorFields, ","), "kubeadm.machines[2].address,kubeadm.machines[2].role,nodeCount"; got != want {
+		t.Errorf("error fields = %s, want %s", got, want)
+	}
+	if got, want := strings.Join(warningFields, ","), "instanceType"; got != want {
+		t.Errorf("warning fields = %s, want %s", got, want)
+	}
+
+	config.NodeCount = 2
+	config.Kubeadm.Machines = config.Kubeadm.Machines[:2]
+	result = NewKubeadmProvider().Validate(config)
+	if !hasField(result.Errors(), "kubeadm.controlPlaneEndpoint") {
+		t.Errorf("errors = %v, want a control plane endpoint required", result.Errors())
+	}
+
+	empty := NewKubeadmProvider().Validate(&ClusterConfig{Name: "lab", NodeCount: 1})
+	if !hasField(empty.Errors(), "kubeadm.machines") {
+		t.Errorf("errors = %v, want machines required", empty.Errors())
+	}
+}
+
+func hasField(issues []ValidationIssue, field string) bool {
+	for _, issue := range issues {
+		if issue.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMergeKubeconfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	existing := "apiVersion: v1\nkind: Config\nclusters:\n- name: other\n  cluster:\n    server: https://other\ncontexts:\n- name: other\n  context:\n    cluster: other\ncurrent-context: other\n"
+	if err := os.WriteFile(path, []byte(existing), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := mergeKubeconfig(path, []byte(testAdminConf), "kubeadm-lab"); err != nil {
+		t.Fatal(err)
+	}
+	config, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.CurrentContext != "kubeadm-lab" || config.Contexts["other"] == nil || config.AuthInfos["kubeadm-lab"].Token != "secret" {
+		t.Errorf("merged kubeconfig = %+v", config)
+	}
+
+	if err := removeKubeconfigContext(path, "kubeadm-lab"); err != nil {
+		t.Fatal(err)
+	}
+	config, err = clientcmd.LoadFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.CurrentContext != "" || config.Contexts["kubeadm-lab"] != nil || config.Contexts["other"] == nil {
+		t.Errorf("kubeconfig after removal = %+v", config)
+	}
+}
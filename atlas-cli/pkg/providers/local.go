@@ -3,27 +3,39 @@ package providers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
 	"github.com/ryanjwong/Atlas/atlas-cli/pkg/monitoring"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
 )
 
 // LocalProvider implements Provider for local minikube clusters
 type LocalProvider struct {
 	logSource logsource.LogSource
-	monitor   monitoring.Monitor
+	monitor   func() monitoring.Monitor
+	run       commandRunner
+	// pollInterval is how often WaitForDeletion checks on the profile
+	pollInterval time.Duration
 }
 
 // NewLocalProvider creates a new local provider
 func NewLocalProvider() *LocalProvider {
 	return &LocalProvider{
-		logSource: logsource.NewMinikubeLogSource(),
-		monitor:   monitoring.NewMinikubeMonitor(),
+		logSource:    logsource.NewMinikubeLogSource(),
+		monitor:      sync.OnceValue(func() monitoring.Monitor { return monitoring.NewMinikubeMonitor() }),
+		run:          runCommand,
+		pollInterval: 2 * time.Second,
 	}
 }
 
@@ -58,9 +70,13 @@ func (l *LocalProvider) GetSupportedVersions() []string {
 
 // CreateCluster creates a new minikube cluster with the specified configuration
 func (l *LocalProvider) CreateCluster(ctx context.Context, config *ClusterConfig) (*Cluster, error) {
+	ctx = subprocess.WithOperation(ctx, "create")
 	if err := l.ValidateConfig(config); err != nil {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
 	}
+	if err := l.Preflight(ctx, config, false); err != nil {
+		return nil, fmt.Errorf("preflight check failed: %w", err)
+	}
 	args := []string{"start", "-p", config.Name}
 
 	if config.Version != "" {
@@ -71,6 +87,14 @@ func (l *LocalProvider) CreateCluster(ctx context.Context, config *ClusterConfig
 		args = append(args, "--nodes="+strconv.Itoa(config.NodeCount))
 	}
 
+	if config.DiskSize != "" {
+		args = append(args, "--disk-size="+config.DiskSize)
+	}
+
+	for _, mount := range config.Mounts {
+		args = append(args, "--mount", "--mount-string="+mount.HostPath+":"+mount.NodePath)
+	}
+
 	if config.NetworkConfig != nil {
 		if config.NetworkConfig.PodCIDR != "" {
 			args = append(args, "--extra-config", "kubeadm.pod-network-cidr="+config.NetworkConfig.PodCIDR)
@@ -109,34 +133,194 @@ func (l *LocalProvider) CreateCluster(ctx context.Context, config *ClusterConfig
 		}
 	}
 
-	cmd := exec.CommandContext(ctx, "minikube", args...)
-	fmt.Println("Creating minikube cluster...")
+	cmd := subprocess.CommandContext(ctx, "minikube", args...)
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "provision", Status: progress.StatusStarted, Message: "Creating minikube cluster..."})
 	output, err := cmd.CombinedOutput()
 	if err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "provision", Status: progress.StatusFailed})
 		return nil, fmt.Errorf("failed to create cluster %s: %w\nOutput: %s", config.Name, err, string(output))
 	}
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "provision", Status: progress.StatusCompleted})
 
 	if err := l.applyPostCreateConfigs(ctx, config); err != nil {
-		fmt.Printf("Warning: failed to apply some post-create configurations: %v\n", err)
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "configure", Status: progress.StatusWarning,
+			Message: fmt.Sprintf("failed to apply some post-create configurations: %v", err)})
+	}
+
+	kubectl := []string{"minikube", "kubectl", "-p", config.Name, "--"}
+	resources, err := applyBootstrapManifests(ctx, config.Name, kubectl, config.BootstrapManifests)
+	if err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "bootstrap", Status: progress.StatusWarning,
+			Message: fmt.Sprintf("failed to apply bootstrap manifests: %v", err)})
+	}
+	if err := installLogging(ctx, config.Name, "", config); err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "logging", Status: progress.StatusWarning,
+			Message: err.Error()})
+	}
+	tracingResources, err := installTracing(ctx, config.Name, config)
+	resources = append(resources, tracingResources...)
+	if err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "tracing", Status: progress.StatusWarning,
+			Message: err.Error()})
 	}
 
-	fmt.Printf("Successfully created cluster: %s\n", config.Name)
-	return l.GetCluster(ctx, config.Name)
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "done", Status: progress.StatusCompleted,
+		Message: fmt.Sprintf("Successfully created cluster: %s", config.Name)})
+	cluster, err := l.GetCluster(ctx, config.Name)
+	if err != nil {
+		return nil, err
+	}
+	cluster.Resources = resources
+	return cluster, nil
 }
 
 // DeleteCluster deletes a minikube cluster by name
 func (l *LocalProvider) DeleteCluster(ctx context.Context, name string) error {
-	cmd := exec.CommandContext(ctx, "minikube", "delete", "-p", name)
-	output, err := cmd.CombinedOutput()
+	ctx = subprocess.WithOperation(ctx, "delete")
+	output, err := l.run(ctx, "minikube", "delete", "-p", name)
 	if err != nil {
-		return fmt.Errorf("failed to delete cluster %s: %w\nOutput: %s", name, err, string(output))
+		return fmt.Errorf("failed to delete cluster %s: %w\nOutput: %s", name, err, commandOutput(output, err))
+	}
+	return nil
+}
+
+// minikubeProfileLabel is the label minikube puts on every container and volume of a profile
+const minikubeProfileLabel = "name.minikube.sigs.k8s.io"
+
+// ForceDeleteCluster removes a minikube cluster even when its profile is broken, falling back to
+// removing the profile's node containers, volumes and directories when minikube delete fails.
+// Containers and volumes are found by minikube's profile label, so nothing else with a similar
+// name is touched.
+func (l *LocalProvider) ForceDeleteCluster(ctx context.Context, name string) error {
+	ctx = subprocess.WithOperation(ctx, "delete")
+	err := l.DeleteCluster(ctx, name)
+	if err == nil {
+		return nil
+	}
+	progress.Report(ctx, progress.Event{Cluster: name, Operation: "delete", Phase: "force-cleanup", Status: progress.StatusWarning,
+		Message: fmt.Sprintf("minikube delete failed, escalating cleanup: %v", err)})
+
+	var failed []string
+	nodes, err := l.profileDockerObjects(ctx, name, "ps", "--all", "--format", "{{.Names}}")
+	if err != nil {
+		failed = append(failed, err.Error())
+	} else if len(nodes) > 0 {
+		if output, err := l.run(ctx, "docker", append([]string{"rm", "--force"}, nodes...)...); err != nil {
+			failed = append(failed, fmt.Sprintf("failed to remove containers %s: %s", strings.Join(nodes, ", "), commandOutput(output, err)))
+		}
+	}
+	volumes, err := l.profileDockerObjects(ctx, name, "volume", "ls", "--format", "{{.Name}}")
+	if err != nil {
+		failed = append(failed, err.Error())
+	} else if len(volumes) > 0 {
+		if output, err := l.run(ctx, "docker", append([]string{"volume", "rm", "--force"}, volumes...)...); err != nil {
+			failed = append(failed, fmt.Sprintf("failed to remove volumes %s: %s", strings.Join(volumes, ", "), commandOutput(output, err)))
+		}
+	}
+
+	home := minikubeHome()
+	dirs := []string{filepath.Join(home, "profiles", name), filepath.Join(home, "machines", name)}
+	for _, node := range nodes {
+		if node != name {
+			dirs = append(dirs, filepath.Join(home, "machines", node))
+		}
+	}
+	for _, dir := range dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			failed = append(failed, fmt.Sprintf("failed to remove %s: %v", dir, err))
+		}
+	}
+
+	// with its state gone, minikube delete only clears what's left of the profile's config
+	if output, err := l.run(ctx, "minikube", "delete", "-p", name); err != nil {
+		failed = append(failed, fmt.Sprintf("minikube delete failed again: %s", commandOutput(output, err)))
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to force delete cluster %s: %s", name, strings.Join(failed, "; "))
 	}
 	return nil
 }
 
+// profileDockerObjects lists the docker objects of a profile: the node containers (name, then
+// name-m02 and so on) or their volumes. It finds none when docker isn't installed, as the
+// profile then uses another driver.
+func (l *LocalProvider) profileDockerObjects(ctx context.Context, name string, list ...string) ([]string, error) {
+	args := append(slices.Clone(list), "--filter", "label="+minikubeProfileLabel+"="+name)
+	output, err := l.run(ctx, "docker", args...)
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list docker objects of %s: %s", name, commandOutput(output, err))
+	}
+	var objects []string
+	for _, object := range strings.Fields(string(output)) {
+		if isProfileNode(name, object) {
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+// isProfileNode reports whether object is named like a node of the profile: the profile itself
+// for the first node, or the profile followed by -mNN for the others
+func isProfileNode(profile, object string) bool {
+	if object == profile {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(object, profile+"-m")
+	if !ok || suffix == "" {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// WaitForDeletion waits until minikube no longer reports the profile
+func (l *LocalProvider) WaitForDeletion(ctx context.Context, name string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		exists, err := l.profileExists(ctx, name)
+		if err == nil && !exists {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			if err != nil {
+				return fmt.Errorf("timeout waiting for cluster %s to be deleted: %w", name, err)
+			}
+			return fmt.Errorf("timeout waiting for cluster %s to be deleted", name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(l.pollInterval):
+		}
+	}
+}
+
+// profileExists asks minikube status whether the profile is still there. Like GetCluster, it
+// takes exit status 7 without a host state, or a not found message, to mean the profile is gone.
+func (l *LocalProvider) profileExists(ctx context.Context, name string) (bool, error) {
+	output, err := l.run(ctx, "minikube", "status", "-p", name)
+	text := commandOutput(output, err)
+	if err == nil || strings.Contains(text, "Running") || strings.Contains(text, "Stopped") {
+		return true, nil
+	}
+	var exitErr interface{ ExitCode() int }
+	if (errors.As(err, &exitErr) && exitErr.ExitCode() == 7) || strings.Contains(text, "does not exist") || strings.Contains(text, "not found") {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to get status of cluster %s: %w\nOutput: %s", name, err, text)
+}
+
 // StartCluster starts a stopped minikube cluster
 func (l *LocalProvider) StartCluster(ctx context.Context, name string) error {
-	cmd := exec.CommandContext(ctx, "minikube", "start", "-p", name)
+	ctx = subprocess.WithOperation(ctx, "start")
+	cmd := subprocess.CommandContext(ctx, "minikube", "start", "-p", name)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to start cluster %s: %w\nOutput: %s", name, err, string(output))
@@ -146,7 +330,8 @@ func (l *LocalProvider) StartCluster(ctx context.Context, name string) error {
 
 // StopCluster stops a running minikube cluster
 func (l *LocalProvider) StopCluster(ctx context.Context, name string) error {
-	cmd := exec.CommandContext(ctx, "minikube", "stop", "-p", name)
+	ctx = subprocess.WithOperation(ctx, "stop")
+	cmd := subprocess.CommandContext(ctx, "minikube", "stop", "-p", name)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to stop cluster %s: %w\nOutput: %s", name, err, string(output))
@@ -156,6 +341,7 @@ func (l *LocalProvider) StopCluster(ctx context.Context, name string) error {
 
 // ScaleCluster scales a minikube cluster to the specified number of nodes
 func (l *LocalProvider) ScaleCluster(ctx context.Context, name string, nodeCount int) error {
+	ctx = subprocess.WithOperation(ctx, "scale")
 	if nodeCount <= 0 {
 		return fmt.Errorf("node count must be positive")
 	}
@@ -174,7 +360,7 @@ func (l *LocalProvider) ScaleCluster(ctx context.Context, name string, nodeCount
 
 	if nodeCount > currentCluster.NodeCount {
 		for i := currentCluster.NodeCount; i < nodeCount; i++ {
-			cmd := exec.CommandContext(ctx, "minikube", "node", "add", "-p", name)
+			cmd := subprocess.CommandContext(ctx, "minikube", "node", "add", "-p", name)
 			output, err := cmd.CombinedOutput()
 			if err != nil {
 				return fmt.Errorf("failed to add node to cluster %s: %w\nOutput: %s", name, err, string(output))
@@ -182,7 +368,7 @@ func (l *LocalProvider) ScaleCluster(ctx context.Context, name string, nodeCount
 		}
 	} else {
 		for i := currentCluster.NodeCount; i > nodeCount; i-- {
-			cmd := exec.CommandContext(ctx, "minikube", "node", "delete", fmt.Sprintf("%s-m%02d", name, i-1), "-p", name)
+			cmd := subprocess.CommandContext(ctx, "minikube", "node", "delete", fmt.Sprintf("%s-m%02d", name, i-1), "-p", name)
 			output, err := cmd.CombinedOutput()
 			if err != nil {
 				return fmt.Errorf("failed to remove node from cluster %s: %w\nOutput: %s", name, err, string(output))
@@ -195,7 +381,7 @@ func (l *LocalProvider) ScaleCluster(ctx context.Context, name string, nodeCount
 
 // GetCluster retrieves information about a minikube cluster
 func (l *LocalProvider) GetCluster(ctx context.Context, name string) (*Cluster, error) {
-	cmd := exec.CommandContext(ctx, "minikube", "status", "-p", name)
+	cmd := subprocess.CommandContext(ctx, "minikube", "status", "-p", name)
 	output, err := cmd.CombinedOutput()
 	statusStr := string(output)
 
@@ -205,15 +391,15 @@ func (l *LocalProvider) GetCluster(ctx context.Context, name string) (*Cluster,
 	} else if strings.Contains(statusStr, "Stopped") {
 		status = ClusterStatusStopped
 	} else if err != nil {
-		if strings.Contains(err.Error(), "exit status 7") || strings.Contains(statusStr, "does not exist") {
-			return nil, fmt.Errorf("cluster %s does not exist", name)
+		if strings.Contains(err.Error(), "exit status 7") || strings.Contains(statusStr, "does not exist") || strings.Contains(statusStr, "not found") {
+			return nil, clusterNotFound(name)
 		}
 		status = ClusterStatusError
 	} else {
 		status = ClusterStatusError
 	}
 
-	cmd = exec.CommandContext(ctx, "minikube", "ip", "-p", name)
+	cmd = subprocess.CommandContext(ctx, "minikube", "ip", "-p", name)
 	ipOutput, err := cmd.CombinedOutput()
 	var endpoint string
 	if err == nil {
@@ -223,7 +409,7 @@ func (l *LocalProvider) GetCluster(ctx context.Context, name string) (*Cluster,
 	var version string
 	var nodeCount int = 1
 
-	cmd = exec.CommandContext(ctx, "minikube", "profile", "list")
+	cmd = subprocess.CommandContext(ctx, "minikube", "profile", "list")
 	profileOutput, err := cmd.CombinedOutput()
 	if err == nil {
 		lines := strings.Split(string(profileOutput), "\n")
@@ -244,7 +430,7 @@ func (l *LocalProvider) GetCluster(ctx context.Context, name string) (*Cluster,
 	}
 
 	if version == "" && status == ClusterStatusRunning {
-		cmd = exec.CommandContext(ctx, "minikube", "kubectl", "-p", name, "--", "version", "--client=false", "--output=yaml")
+		cmd = subprocess.CommandContext(ctx, "minikube", "kubectl", "-p", name, "--", "version", "--client=false", "--output=yaml")
 		versionOutput, err := cmd.CombinedOutput()
 		if err == nil {
 			lines := strings.Split(string(versionOutput), "\n")
@@ -261,7 +447,7 @@ func (l *LocalProvider) GetCluster(ctx context.Context, name string) (*Cluster,
 	}
 
 	if status == ClusterStatusRunning {
-		cmd = exec.CommandContext(ctx, "minikube", "kubectl", "-p", name, "--", "get", "nodes", "--no-headers")
+		cmd = subprocess.CommandContext(ctx, "minikube", "kubectl", "-p", name, "--", "get", "nodes", "--no-headers")
 		nodesOutput, err := cmd.CombinedOutput()
 		if err == nil {
 			nodeLines := strings.Split(strings.TrimSpace(string(nodesOutput)), "\n")
@@ -287,7 +473,7 @@ func (l *LocalProvider) GetCluster(ctx context.Context, name string) (*Cluster,
 
 // ListClusters lists all minikube clusters managed by this provider
 func (l *LocalProvider) ListClusters(ctx context.Context) ([]*Cluster, error) {
-	cmd := exec.CommandContext(ctx, "minikube", "profile", "list", "-o=json")
+	cmd := subprocess.CommandContext(ctx, "minikube", "profile", "list", "-o=json")
 	var profiles MinikubeProfilesResponse
 
 	profileOutput, err := cmd.CombinedOutput()
@@ -316,39 +502,52 @@ func (l *LocalProvider) ListClusters(ctx context.Context) ([]*Cluster, error) {
 
 // ValidateConfig validates the cluster configuration for the local provider
 func (l *LocalProvider) ValidateConfig(config *ClusterConfig) error {
-	if config.Name == "" {
-		return fmt.Errorf("cluster name is required")
-	}
+	return l.Validate(config).Err()
+}
+
+// Validate reports every problem with the cluster configuration for the local provider
+func (l *LocalProvider) Validate(config *ClusterConfig) *ValidationResult {
+	result := &ValidationResult{}
 
-	cmd := exec.Command("minikube", "version")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("minikube is not installed or not in PATH")
+	if config.Name == "" {
+		result.Errorf("name", "cluster name is required")
+	} else if strings.Contains(config.Name, " ") {
+		result.Errorf("name", "cluster name cannot contain spaces")
 	}
 
-	if strings.Contains(config.Name, " ") {
-		return fmt.Errorf("cluster name cannot contain spaces")
+	if _, err := DetectTool(context.Background(), "minikube"); err != nil {
+		result.Errorf("", "%v", err)
 	}
 
 	if config.NodeCount < 0 {
-		return fmt.Errorf("node count cannot be negative")
+		result.Errorf("nodeCount", "node count cannot be negative")
 	}
 	if config.NodeCount > 10 {
-		return fmt.Errorf("node count cannot exceed 10 for local provider")
+		result.Errorf("nodeCount", "node count cannot exceed 10 for local provider")
 	}
 
-	if err := l.validateNetworkConfig(config.NetworkConfig); err != nil {
-		return fmt.Errorf("invalid network configuration: %w", err)
+	if config.DiskSize != "" {
+		if _, err := parseMemoryMB(config.DiskSize); err != nil {
+			result.Errorf("diskSize", "invalid disk size: %s", config.DiskSize)
+		}
 	}
 
-	if err := l.validateSecurityConfig(config.SecurityConfig); err != nil {
-		return fmt.Errorf("invalid security configuration: %w", err)
+	if config.Region != "" && config.Region != "local" {
+		result.Warnf("region", "region %s is ignored by the local provider", config.Region)
 	}
-
-	if err := l.validateResourceConfig(config.ResourceConfig); err != nil {
-		return fmt.Errorf("invalid resource configuration: %w", err)
+	if config.InstanceType != "" {
+		result.Warnf("instanceType", "instance type %s is ignored by the local provider", config.InstanceType)
 	}
 
-	return nil
+	l.validateMounts(config.Mounts, result)
+	validateBootstrapManifests(config.BootstrapManifests, result)
+	validateNetworkConfig(config.NetworkConfig, result)
+	validateSecurityConfig(config.SecurityConfig, result)
+	validateLogConfig(config, false, result)
+	validateTracingConfig(config, result)
+	l.validateResourceConfig(config.ResourceConfig, result)
+
+	return result
 }
 
 // applyPostCreateConfigs applies post-creation configurations like networking, security, and resources
@@ -377,19 +576,19 @@ func (l *LocalProvider) applyPostCreateConfigs(ctx context.Context, config *Clus
 // applyNetworkConfig applies network configuration including ingress and load balancer settings
 func (l *LocalProvider) applyNetworkConfig(ctx context.Context, clusterName string, netConfig *NetworkConfig) error {
 	if netConfig.Ingress != nil && netConfig.Ingress.Enabled {
-		cmd := exec.CommandContext(ctx, "minikube", "addons", "enable", "ingress", "-p", clusterName)
+		cmd := subprocess.CommandContext(ctx, "minikube", "addons", "enable", "ingress", "-p", clusterName)
 		if _, err := cmd.CombinedOutput(); err != nil {
 			return fmt.Errorf("failed to enable ingress addon: %w", err)
 		}
-		fmt.Printf("Enabled ingress controller for cluster %s\n", clusterName)
+		progress.Report(ctx, progress.Event{Cluster: clusterName, Operation: "create", Phase: "ingress", Status: progress.StatusCompleted, Message: fmt.Sprintf("Enabled ingress controller for cluster %s", clusterName)})
 	}
 
 	if netConfig.LoadBalancer != nil && netConfig.LoadBalancer.Enabled {
-		cmd := exec.CommandContext(ctx, "minikube", "addons", "enable", "metallb", "-p", clusterName)
+		cmd := subprocess.CommandContext(ctx, "minikube", "addons", "enable", "metallb", "-p", clusterName)
 		if _, err := cmd.CombinedOutput(); err != nil {
 			return fmt.Errorf("failed to enable metallb addon: %w", err)
 		}
-		fmt.Printf("Enabled MetalLB load balancer for cluster %s\n", clusterName)
+		progress.Report(ctx, progress.Event{Cluster: clusterName, Operation: "create", Phase: "load-balancer", Status: progress.StatusCompleted, Message: fmt.Sprintf("Enabled MetalLB load balancer for cluster %s", clusterName)})
 	}
 
 	return nil
@@ -413,7 +612,7 @@ spec:
 		if err := l.applyKubernetesResource(ctx, clusterName, networkPolicyYAML); err != nil {
 			return fmt.Errorf("failed to apply network policy: %w", err)
 		}
-		fmt.Printf("Applied default network policy for cluster %s\n", clusterName)
+		progress.Report(ctx, progress.Event{Cluster: clusterName, Operation: "create", Phase: "network-policy", Status: progress.StatusCompleted, Message: fmt.Sprintf("Applied default network policy for cluster %s", clusterName)})
 	}
 
 	return nil
@@ -423,21 +622,21 @@ spec:
 func (l *LocalProvider) applyResourceConfig(ctx context.Context, clusterName string, resConfig *ResourceConfig) error {
 	if resConfig.Monitoring != nil && resConfig.Monitoring.Enabled {
 		if resConfig.Monitoring.Prometheus != nil && resConfig.Monitoring.Prometheus.Enabled {
-			cmd := exec.CommandContext(ctx, "minikube", "addons", "enable", "metrics-server", "-p", clusterName)
+			cmd := subprocess.CommandContext(ctx, "minikube", "addons", "enable", "metrics-server", "-p", clusterName)
 			if _, err := cmd.CombinedOutput(); err != nil {
 				return fmt.Errorf("failed to enable metrics-server addon: %w", err)
 			}
-			fmt.Printf("Enabled metrics-server for cluster %s\n", clusterName)
+			progress.Report(ctx, progress.Event{Cluster: clusterName, Operation: "create", Phase: "metrics-server", Status: progress.StatusCompleted, Message: fmt.Sprintf("Enabled metrics-server for cluster %s", clusterName)})
 		}
 	}
 
 	if resConfig.Storage != nil {
 		if resConfig.Storage.DefaultStorageClass != "" {
-			cmd := exec.CommandContext(ctx, "minikube", "addons", "enable", "default-storageclass", "-p", clusterName)
+			cmd := subprocess.CommandContext(ctx, "minikube", "addons", "enable", "default-storageclass", "-p", clusterName)
 			if _, err := cmd.CombinedOutput(); err != nil {
 				return fmt.Errorf("failed to enable default storageclass: %w", err)
 			}
-			fmt.Printf("Enabled default storage class for cluster %s\n", clusterName)
+			progress.Report(ctx, progress.Event{Cluster: clusterName, Operation: "create", Phase: "storage-class", Status: progress.StatusCompleted, Message: fmt.Sprintf("Enabled default storage class for cluster %s", clusterName)})
 		}
 	}
 
@@ -446,7 +645,7 @@ func (l *LocalProvider) applyResourceConfig(ctx context.Context, clusterName str
 
 // applyKubernetesResource applies a YAML resource to the minikube cluster
 func (l *LocalProvider) applyKubernetesResource(ctx context.Context, clusterName, resourceYAML string) error {
-	cmd := exec.CommandContext(ctx, "minikube", "kubectl", "-p", clusterName, "--", "apply", "-f", "-")
+	cmd := subprocess.CommandContext(ctx, "minikube", "kubectl", "-p", clusterName, "--", "apply", "-f", "-")
 	cmd.Stdin = strings.NewReader(resourceYAML)
 	if _, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to apply kubernetes resource: %w", err)
@@ -454,159 +653,142 @@ func (l *LocalProvider) applyKubernetesResource(ctx context.Context, clusterName
 	return nil
 }
 
+// validateMounts validates host to node mount definitions
+func (l *LocalProvider) validateMounts(mounts []MountConfig, result *ValidationResult) {
+	if len(mounts) > 1 {
+		result.Errorf("mounts", "minikube supports a single mount per cluster, got %d", len(mounts))
+	}
+	validateMountPaths(mounts, result)
+}
+
+// validateMountPaths checks that each mount has an absolute node path and an existing host directory
+func validateMountPaths(mounts []MountConfig, result *ValidationResult) {
+	for i, mount := range mounts {
+		if mount.HostPath == "" || mount.NodePath == "" {
+			result.Errorf(fieldPath("mounts", i), "mounts require both hostPath and nodePath")
+			continue
+		}
+		if !strings.HasPrefix(mount.NodePath, "/") {
+			result.Errorf(fieldPath("mounts", i, "nodePath"), "node path must be absolute: %s", mount.NodePath)
+		}
+		info, err := os.Stat(mount.HostPath)
+		if err != nil {
+			result.Errorf(fieldPath("mounts", i, "hostPath"), "host path %s is not accessible: %v", mount.HostPath, err)
+		} else if !info.IsDir() {
+			result.Errorf(fieldPath("mounts", i, "hostPath"), "host path %s is not a directory", mount.HostPath)
+		}
+	}
+}
+
 // validateNetworkConfig validates network configuration parameters
-func (l *LocalProvider) validateNetworkConfig(netConfig *NetworkConfig) error {
+func validateNetworkConfig(netConfig *NetworkConfig, result *ValidationResult) {
 	if netConfig == nil {
-		return nil
+		return
 	}
 
 	if netConfig.APIServerPort > 0 && (netConfig.APIServerPort < 1024 || netConfig.APIServerPort > 65535) {
-		return fmt.Errorf("API server port must be between 1024 and 65535")
+		result.Errorf("networkConfig.apiServerPort", "API server port must be between 1024 and 65535")
 	}
 
 	if netConfig.NetworkPlugin != "" {
 		validPlugins := []string{"bridge", "flannel", "calico", "auto"}
-		isValid := false
-		for _, plugin := range validPlugins {
-			if netConfig.NetworkPlugin == plugin {
-				isValid = true
-				break
-			}
-		}
-		if !isValid {
-			return fmt.Errorf("invalid network plugin: %s. Valid options: %v", netConfig.NetworkPlugin, validPlugins)
+		if !slices.Contains(validPlugins, netConfig.NetworkPlugin) {
+			result.Errorf("networkConfig.networkPlugin", "invalid network plugin: %s. Valid options: %v", netConfig.NetworkPlugin, validPlugins)
 		}
 	}
 
-	for _, portMap := range netConfig.ExtraPortMaps {
+	for i, portMap := range netConfig.ExtraPortMaps {
 		if portMap.HostPort <= 0 || portMap.ContainerPort <= 0 {
-			return fmt.Errorf("port mappings must have positive port numbers")
+			result.Errorf(fieldPath("networkConfig", "extraPortMaps", i), "port mappings must have positive port numbers")
 		}
 		if portMap.Protocol != "" && portMap.Protocol != "tcp" && portMap.Protocol != "udp" {
-			return fmt.Errorf("invalid protocol: %s. Valid options: tcp, udp", portMap.Protocol)
+			result.Errorf(fieldPath("networkConfig", "extraPortMaps", i, "protocol"), "invalid protocol: %s. Valid options: tcp, udp", portMap.Protocol)
 		}
 	}
 
 	if netConfig.Ingress != nil && netConfig.Ingress.Controller != "" {
 		validControllers := []string{"nginx", "traefik", "haproxy"}
-		isValid := false
-		for _, controller := range validControllers {
-			if netConfig.Ingress.Controller == controller {
-				isValid = true
-				break
-			}
-		}
-		if !isValid {
-			return fmt.Errorf("invalid ingress controller: %s. Valid options: %v", netConfig.Ingress.Controller, validControllers)
+		if !slices.Contains(validControllers, netConfig.Ingress.Controller) {
+			result.Errorf("networkConfig.ingress.controller", "invalid ingress controller: %s. Valid options: %v", netConfig.Ingress.Controller, validControllers)
 		}
 	}
-
-	return nil
 }
 
 // validateSecurityConfig validates security configuration parameters
-func (l *LocalProvider) validateSecurityConfig(secConfig *SecurityConfig) error {
+func validateSecurityConfig(secConfig *SecurityConfig, result *ValidationResult) {
 	if secConfig == nil {
-		return nil
+		return
 	}
 
 	if secConfig.AuthenticationMode != "" {
 		validModes := []string{"RBAC", "ABAC", "Node", "Webhook"}
-		isValid := false
-		for _, mode := range validModes {
-			if secConfig.AuthenticationMode == mode {
-				isValid = true
-				break
-			}
-		}
-		if !isValid {
-			return fmt.Errorf("invalid authentication mode: %s. Valid options: %v", secConfig.AuthenticationMode, validModes)
+		if !slices.Contains(validModes, secConfig.AuthenticationMode) {
+			result.Errorf("securityConfig.authenticationMode", "invalid authentication mode: %s. Valid options: %v", secConfig.AuthenticationMode, validModes)
 		}
 	}
 
 	if secConfig.AuditLogging != nil && secConfig.AuditLogging.LogLevel != "" {
 		validLevels := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}
-		isValid := false
-		for _, level := range validLevels {
-			if secConfig.AuditLogging.LogLevel == level {
-				isValid = true
-				break
-			}
-		}
-		if !isValid {
-			return fmt.Errorf("invalid audit log level: %s. Valid options: 1-10", secConfig.AuditLogging.LogLevel)
+		if !slices.Contains(validLevels, secConfig.AuditLogging.LogLevel) {
+			result.Errorf("securityConfig.auditLogging.logLevel", "invalid audit log level: %s. Valid options: 1-10", secConfig.AuditLogging.LogLevel)
 		}
 	}
 
 	if secConfig.ImageSecurity != nil && secConfig.ImageSecurity.VulnerabilityThreshold != "" {
 		validThresholds := []string{"low", "medium", "high", "critical"}
-		isValid := false
-		for _, threshold := range validThresholds {
-			if secConfig.ImageSecurity.VulnerabilityThreshold == threshold {
-				isValid = true
-				break
-			}
-		}
-		if !isValid {
-			return fmt.Errorf("invalid vulnerability threshold: %s. Valid options: %v", secConfig.ImageSecurity.VulnerabilityThreshold, validThresholds)
+		if !slices.Contains(validThresholds, secConfig.ImageSecurity.VulnerabilityThreshold) {
+			result.Errorf("securityConfig.imageSecurity.vulnerabilityThreshold", "invalid vulnerability threshold: %s. Valid options: %v", secConfig.ImageSecurity.VulnerabilityThreshold, validThresholds)
 		}
 	}
-
-	return nil
 }
 
 // validateResourceConfig validates resource configuration parameters
-func (l *LocalProvider) validateResourceConfig(resConfig *ResourceConfig) error {
+func (l *LocalProvider) validateResourceConfig(resConfig *ResourceConfig, result *ValidationResult) {
 	if resConfig == nil {
-		return nil
+		return
 	}
 
-	if resConfig.AutoScaling != nil {
-		if resConfig.AutoScaling.MinNodes < 1 {
-			return fmt.Errorf("minimum nodes must be at least 1")
+	if scaling := resConfig.AutoScaling; scaling != nil {
+		if scaling.MinNodes < 1 {
+			result.Errorf("resourceConfig.autoScaling.minNodes", "minimum nodes must be at least 1")
 		}
-		if resConfig.AutoScaling.MaxNodes > 10 {
-			return fmt.Errorf("maximum nodes cannot exceed 10 for local provider")
+		if scaling.MaxNodes > 10 {
+			result.Errorf("resourceConfig.autoScaling.maxNodes", "maximum nodes cannot exceed 10 for local provider")
 		}
-		if resConfig.AutoScaling.MinNodes > resConfig.AutoScaling.MaxNodes {
-			return fmt.Errorf("minimum nodes cannot be greater than maximum nodes")
+		if scaling.MinNodes > scaling.MaxNodes {
+			result.Errorf("resourceConfig.autoScaling", "minimum nodes cannot be greater than maximum nodes")
 		}
-		if resConfig.AutoScaling.TargetCPU > 0 && (resConfig.AutoScaling.TargetCPU < 10 || resConfig.AutoScaling.TargetCPU > 90) {
-			return fmt.Errorf("target CPU must be between 10 and 90 percent")
+		if scaling.TargetCPU > 0 && (scaling.TargetCPU < 10 || scaling.TargetCPU > 90) {
+			result.Errorf("resourceConfig.autoScaling.targetCPU", "target CPU must be between 10 and 90 percent")
 		}
 	}
 
 	if resConfig.Storage != nil {
-		for _, sc := range resConfig.Storage.StorageClasses {
+		validProvisioners := []string{"hostpath", "local", "nfs"}
+		for i, sc := range resConfig.Storage.StorageClasses {
+			field := fieldPath("resourceConfig", "storage", "storageClasses", i)
 			if sc.Name == "" || sc.Provisioner == "" {
-				return fmt.Errorf("storage class name and provisioner are required")
-			}
-			validProvisioners := []string{"hostpath", "local", "nfs"}
-			isValid := false
-			for _, provisioner := range validProvisioners {
-				if sc.Provisioner == provisioner {
-					isValid = true
-					break
-				}
+				result.Errorf(field, "storage class name and provisioner are required")
+				continue
 			}
-			if !isValid {
-				return fmt.Errorf("invalid storage provisioner: %s. Valid options for local provider: %v", sc.Provisioner, validProvisioners)
+			if !slices.Contains(validProvisioners, sc.Provisioner) {
+				result.Errorf(field+".provisioner", "invalid storage provisioner: %s. Valid options for local provider: %v", sc.Provisioner, validProvisioners)
 			}
 		}
 	}
-
-	return nil
 }
 
 // GetMonitor returns the monitor for health checks and metrics collection
 func (l *LocalProvider) GetMonitor() monitoring.Monitor {
-	return l.monitor
+	return l.monitor()
 }
 
 // HealthCheck performs a health check on the specified cluster
 func (l *LocalProvider) HealthCheck(ctx context.Context, clusterName string) (*monitoring.HealthStatus, error) {
-	return l.monitor.CheckClusterHealth(ctx, clusterName)
+	return l.monitor().CheckClusterHealth(ctx, clusterName)
 }
 
 // Ensure LocalProvider implements Provider interface
-var _ Provider = (*LocalProvider)(nil)
\ No newline at end of file
+var _ Provider = (*LocalProvider)(nil)
+var _ DeletionWaiter = (*LocalProvider)(nil)
+var _ ForceDeleter = (*LocalProvider)(nil)
\ No newline at end of file
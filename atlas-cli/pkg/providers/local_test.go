@@ -4,6 +4,9 @@ import (
 	"context"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -324,8 +327,6 @@ func TestLocalProvider_GetSupportedVersions(t *testing.T) {
 }
 
 func TestNetworkConfigValidation(t *testing.T) {
-	provider := NewLocalProvider()
-
 	tests := []struct {
 		name        string
 		netConfig   *NetworkConfig
@@ -381,7 +382,9 @@ func TestNetworkConfigValidation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := provider.validateNetworkConfig(tt.netConfig)
+			result := &ValidationResult{}
+			validateNetworkConfig(tt.netConfig, result)
+			err := result.Err()
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("validateNetworkConfig() expected error but got none")
@@ -543,3 +546,159 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestLocalProvider_ForceDeleteCluster(t *testing.T) {
+	const label = "--filter label=name.minikube.sigs.k8s.io=dev"
+	deleteFailed := fakeResponse{stderr: "X Exiting due to GUEST_DELETE: corrupt profile"}
+
+	tests := []struct {
+		name            string
+		setup           func(f *fakeRunner)
+		wantRemoved     []string
+		wantDirsRemoved []string
+		wantErr         string
+	}{
+		{
+			name:  "minikube delete succeeds",
+			setup: func(f *fakeRunner) { f.on("minikube delete") },
+		},
+		{
+			name: "removes only the profile's labelled nodes and volumes",
+			setup: func(f *fakeRunner) {
+				f.on("minikube delete", deleteFailed, fakeResponse{})
+				f.on("docker ps", fakeResponse{stdout: "dev\ndev-m02\ndev-m03\ndev-monitoring\n"})
+				f.on("docker volume ls", fakeResponse{stdout: "dev\ndev-m02\n"})
+			},
+			wantRemoved: []string{
+				"docker rm --force dev dev-m02 dev-m03",
+				"docker volume rm --force dev dev-m02",
+			},
+			wantDirsRemoved: []string{"profiles/dev", "machines/dev", "machines/dev-m02"},
+		},
+		{
+			name: "skips docker cleanup when docker is not installed",
+			setup: func(f *fakeRunner) {
+				f.on("minikube delete", deleteFailed, fakeResponse{})
+				f.on("docker", fakeResponse{err: exec.ErrNotFound})
+			},
+		},
+		{
+			name: "surfaces container removal failures",
+			setup: func(f *fakeRunner) {
+				f.on("minikube delete", deleteFailed, fakeResponse{})
+				f.on("docker ps", fakeResponse{stdout: "dev\n"})
+				f.on("docker rm", fakeResponse{stderr: "Error response from daemon: permission denied"})
+			},
+			wantRemoved:     []string{"docker rm --force dev"},
+			wantDirsRemoved: []string{"profiles/dev", "machines/dev"},
+			wantErr:         "failed to remove containers dev: Error response from daemon: permission denied",
+		},
+		{
+			name: "surfaces the final minikube delete failure",
+			setup: func(f *fakeRunner) {
+				f.on("minikube delete", deleteFailed)
+			},
+			wantErr: "minikube delete failed again: X Exiting due to GUEST_DELETE: corrupt profile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("MINIKUBE_HOME", home)
+			for _, dir := range []string{"profiles/dev", "machines/dev", "machines/dev-m02", "machines/other"} {
+				if err := os.MkdirAll(filepath.Join(home, dir), 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			runner := newFakeRunner()
+			tt.setup(runner)
+			provider := &LocalProvider{run: runner.run}
+			err := provider.ForceDeleteCluster(context.Background(), "dev")
+
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("ForceDeleteCluster() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("ForceDeleteCluster() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+
+			for _, call := range runner.callsMatching("docker ps") {
+				if !strings.Contains(call, label) {
+					t.Errorf("container listing %q is not filtered by the profile label", call)
+				}
+			}
+			for _, call := range runner.callsMatching("docker volume ls") {
+				if !strings.Contains(call, label) {
+					t.Errorf("volume listing %q is not filtered by the profile label", call)
+				}
+			}
+			removed := append(runner.callsMatching("docker rm"), runner.callsMatching("docker volume rm")...)
+			if !slices.Equal(removed, tt.wantRemoved) {
+				t.Errorf("removed %q, want %q", removed, tt.wantRemoved)
+			}
+
+			if _, err := os.Stat(filepath.Join(home, "machines", "other")); err != nil {
+				t.Errorf("another profile's machine directory was removed: %v", err)
+			}
+			for _, dir := range tt.wantDirsRemoved {
+				if _, err := os.Stat(filepath.Join(home, dir)); !os.IsNotExist(err) {
+					t.Errorf("%s was not removed", dir)
+				}
+			}
+		})
+	}
+}
+
+func TestLocalProvider_WaitForDeletion(t *testing.T) {
+	running := fakeResponse{stdout: "host: Running\nkubelet: Running\n"}
+	notFound := fakeResponse{stderr: "Profile \"dev\" not found. Run \"minikube profile list\" to view all profiles."}
+
+	tests := []struct {
+		name      string
+		status    []fakeResponse
+		wantCalls int
+		wantErr   string
+	}{
+		{
+			name:      "already gone",
+			status:    []fakeResponse{notFound},
+			wantCalls: 1,
+		},
+		{
+			name:      "polls until the profile is gone",
+			status:    []fakeResponse{running, {stdout: "host: Stopped\n", stderr: "exit status 2"}, notFound},
+			wantCalls: 3,
+		},
+		{
+			name:    "times out while the profile remains",
+			status:  []fakeResponse{running},
+			wantErr: "timeout waiting for cluster dev to be deleted",
+		},
+		{
+			name:    "reports why status kept failing",
+			status:  []fakeResponse{{err: exec.ErrNotFound}},
+			wantErr: "failed to get status of cluster dev",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := newFakeRunner()
+			runner.on("minikube status -p dev", tt.status...)
+			provider := &LocalProvider{run: runner.run, pollInterval: time.Millisecond}
+
+			err := provider.WaitForDeletion(context.Background(), "dev", 20*time.Millisecond)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("WaitForDeletion() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("WaitForDeletion() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if calls := len(runner.calls); tt.wantCalls > 0 && calls != tt.wantCalls {
+				t.Errorf("minikube status ran %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
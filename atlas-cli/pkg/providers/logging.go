@@ -0,0 +1,450 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// Log aggregation backends
+const (
+	// LogBackendLoki runs Loki and promtail in the cluster
+	LogBackendLoki = "loki"
+	// LogBackendCloudWatch ships container logs to CloudWatch Logs with fluent-bit
+	LogBackendCloudWatch = "cloudwatch"
+)
+
+const (
+	loggingNamespace = "logging"
+	defaultRetention = "7d"
+)
+
+// LogQuery selects log lines from a cluster's logging stack
+type LogQuery struct {
+	// Selector matches pod labels, e.g. app=foo; an empty selector matches every container
+	Selector map[string]string
+	Since    time.Duration
+	Limit    int
+	// Backend is the stack to query; empty means the provider's default
+	Backend string
+}
+
+// LogEntry is one log line
+type LogEntry struct {
+	Time   time.Time         `json:"time"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Line   string            `json:"line"`
+}
+
+// LogQuerier is implemented by providers that can query a cluster's logging stack
+type LogQuerier interface {
+	// QueryLogs returns the matching lines, oldest first
+	QueryLogs(ctx context.Context, clusterName string, query LogQuery) ([]LogEntry, error)
+}
+
+var _ LogQuerier = (*LocalProvider)(nil)
+var _ LogQuerier = (*KindProvider)(nil)
+var _ LogQuerier = (*K3dProvider)(nil)
+var _ LogQuerier = (*AWSProvider)(nil)
+
+// ParseLogSelector parses a label selector such as "app=foo,tier=web"
+func ParseLogSelector(selector string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		key, value, found := strings.Cut(term, "=")
+		key, value = strings.TrimSpace(key), strings.Trim(strings.TrimSpace(value), `"'`)
+		if !found || key == "" || strings.HasSuffix(key, "!") {
+			return nil, fmt.Errorf("invalid selector %q; use label=value terms separated by commas", term)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// logAggregation returns the cluster's log aggregation settings when it is enabled
+func logAggregation(config *ClusterConfig) *LogConfig {
+	if config.ResourceConfig == nil || config.ResourceConfig.Monitoring == nil {
+		return nil
+	}
+	logs := config.ResourceConfig.Monitoring.LogAggregation
+	if logs == nil || !logs.Enabled {
+		return nil
+	}
+	return logs
+}
+
+// validateLogConfig checks the log aggregation settings; cloudWatch says whether the provider can
+// ship logs to CloudWatch
+func validateLogConfig(config *ClusterConfig, cloudWatch bool, result *ValidationResult) {
+	logs := logAggregation(config)
+	if logs == nil {
+		return
+	}
+	const field = "resourceConfig.monitoring.logAggregation"
+	switch logs.Backend {
+	case "", LogBackendLoki:
+	case LogBackendCloudWatch:
+		if !cloudWatch {
+			result.Errorf(field+".backend", "the %s backend needs a cluster on AWS; use %s", LogBackendCloudWatch, LogBackendLoki)
+		}
+	default:
+		result.Errorf(field+".backend", "unknown log backend %q; use %s or %s", logs.Backend, LogBackendLoki, LogBackendCloudWatch)
+	}
+	if logs.Retention != "" {
+		if _, err := parseRetentionDays(logs.Retention); err != nil {
+			result.Errorf(field+".retention", "%v", err)
+		}
+	}
+	for key := range logs.Config {
+		if key == "" || strings.ContainsAny(key, "= ") {
+			result.Errorf(fieldPath(field, "config"), "invalid chart value name %q", key)
+		}
+	}
+}
+
+// parseRetentionDays parses a retention such as 7d or 72h into whole days, rounding up
+func parseRetentionDays(retention string) (int, error) {
+	if days, ok := strings.CutSuffix(retention, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("invalid retention %q; use days such as 7d or a duration such as 72h", retention)
+		}
+		return n, nil
+	}
+	d, err := time.ParseDuration(retention)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid retention %q; use days such as 7d or a duration such as 72h", retention)
+	}
+	return int((d + 24*time.Hour - 1) / (24 * time.Hour)), nil
+}
+
+// cloudWatchRetentionDays are the retention periods CloudWatch Logs accepts
+var cloudWatchRetentionDays = []int{1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1096, 1827, 2192, 2557, 2922, 3288, 3653}
+
+// cloudWatchRetention rounds days up to a retention period CloudWatch Logs accepts
+func cloudWatchRetention(days int) int {
+	for _, allowed := range cloudWatchRetentionDays {
+		if allowed >= days {
+			return allowed
+		}
+	}
+	return cloudWatchRetentionDays[len(cloudWatchRetentionDays)-1]
+}
+
+// loggingChartArgs builds the `helm upgrade --install` invocation for the logging stack. region
+// and clusterName are only used by the CloudWatch backend.
+func loggingChartArgs(kubeContext, clusterName, region string, logs *LogConfig) ([]string, error) {
+	retention := logs.Retention
+	if retention == "" {
+		retention = defaultRetention
+	}
+	days, err := parseRetentionDays(retention)
+	if err != nil {
+		return nil, err
+	}
+
+	var args, set []string
+	switch logs.Backend {
+	case "", LogBackendLoki:
+		args = []string{"upgrade", "--install", "loki", "loki-stack", "--repo", "https://grafana.github.io/helm-charts"}
+		set = []string{
+			"promtail.enabled=true",
+			"loki.config.table_manager.retention_deletes_enabled=true",
+			fmt.Sprintf("loki.config.table_manager.retention_period=%dh", days*24),
+		}
+	case LogBackendCloudWatch:
+		args = []string{"upgrade", "--install", "aws-for-fluent-bit", "aws-for-fluent-bit", "--repo", "https://aws.github.io/eks-charts"}
+		set = []string{
+			"cloudWatchLogs.enabled=true",
+			"cloudWatchLogs.region=" + region,
+			"cloudWatchLogs.logGroupName=" + cloudWatchLogGroup(clusterName),
+			"cloudWatchLogs.autoCreateGroup=true",
+			"cloudWatchLogs.logRetentionDays=" + strconv.Itoa(cloudWatchRetention(days)),
+			"firehose.enabled=false",
+			"kinesis.enabled=false",
+			"elasticsearch.enabled=false",
+		}
+	default:
+		return nil, fmt.Errorf("unknown log backend %q", logs.Backend)
+	}
+	args = append(args, "--kube-context", kubeContext, "--namespace", loggingNamespace, "--create-namespace", "--wait", "--timeout", "10m")
+
+	keys := make([]string, 0, len(logs.Config))
+	for key := range logs.Config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		set = append(set, key+"="+logs.Config[key])
+	}
+	for _, value := range set {
+		args = append(args, "--set", value)
+	}
+	return args, nil
+}
+
+// installLogging installs the logging stack configured for config into the cluster behind
+// kubeContext. Clusters without log aggregation are left alone.
+func installLogging(ctx context.Context, kubeContext, region string, config *ClusterConfig) error {
+	logs := logAggregation(config)
+	if logs == nil {
+		return nil
+	}
+	args, err := loggingChartArgs(kubeContext, config.Name, region, logs)
+	if err != nil {
+		return err
+	}
+
+	backend := logs.Backend
+	if backend == "" {
+		backend = LogBackendLoki
+	}
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "logging", Status: progress.StatusStarted,
+		Message: fmt.Sprintf("Installing %s log aggregation...", backend)})
+	output, err := subprocess.CommandContext(ctx, "helm", args...).CombinedOutput()
+	if err != nil {
+		progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "logging", Status: progress.StatusFailed})
+		return fmt.Errorf("failed to install %s log aggregation: %w: %s", backend, err, strings.TrimSpace(string(output)))
+	}
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "logging", Status: progress.StatusCompleted,
+		Message: fmt.Sprintf("Installed %s log aggregation for cluster %s", backend, config.Name)})
+	return nil
+}
+
+// cloudWatchLogGroup is the log group fluent-bit writes a cluster's container logs to
+func cloudWatchLogGroup(clusterName string) string {
+	return "/aws/eks/" + clusterName + "/containers"
+}
+
+var lokiLabelInvalid = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// lokiSelector builds a LogQL stream selector. promtail turns pod label names into Loki label
+// names by replacing characters Loki doesn't allow with underscores.
+func lokiSelector(selector map[string]string) string {
+	if len(selector) == 0 {
+		return `{namespace=~".+"}`
+	}
+	keys := make([]string, 0, len(selector))
+	for key := range selector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	matchers := make([]string, 0, len(keys))
+	for _, key := range keys {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", lokiLabelInvalid.ReplaceAllString(key, "_"), selector[key]))
+	}
+	return "{" + strings.Join(matchers, ",") + "}"
+}
+
+// lokiQueryPath is the API server proxy path to Loki's query_range endpoint, so queries need
+// only kubectl access to the cluster
+func lokiQueryPath(query LogQuery, now time.Time) string {
+	params := url.Values{}
+	params.Set("query", lokiSelector(query.Selector))
+	params.Set("start", strconv.FormatInt(now.Add(-query.Since).UnixNano(), 10))
+	params.Set("end", strconv.FormatInt(now.UnixNano(), 10))
+	params.Set("limit", strconv.Itoa(query.Limit))
+	params.Set("direction", "backward")
+	return "/api/v1/namespaces/" + loggingNamespace + "/services/loki:3100/proxy/loki/api/v1/query_range?" + params.Encode()
+}
+
+// parseLokiResponse flattens a query_range response into entries, oldest first
+func parseLokiResponse(output []byte) ([]LogEntry, error) {
+	var response struct {
+		Status string `json:"status"`
+		Data   struct {
+			Result []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][2]string       `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse Loki response: %w", err)
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("Loki query failed with status %q", response.Status)
+	}
+
+	var entries []LogEntry
+	for _, stream := range response.Data.Result {
+		for _, value := range stream.Values {
+			nanos, err := strconv.ParseInt(value[0], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse Loki timestamp %q: %w", value[0], err)
+			}
+			entries = append(entries, LogEntry{Time: time.Unix(0, nanos).UTC(), Labels: stream.Stream, Line: value[1]})
+		}
+	}
+	sortLogEntries(entries)
+	return entries, nil
+}
+
+// queryLoki queries the Loki in the cluster with the kubectl command line kubectl
+func queryLoki(ctx context.Context, kubectl []string, query LogQuery) ([]LogEntry, error) {
+	args := append(append([]string(nil), kubectl[1:]...), "get", "--raw", lokiQueryPath(query, time.Now()))
+	output, err := subprocess.CommandContext(ctx, kubectl[0], args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Loki; is log aggregation enabled for this cluster? %w", err)
+	}
+	return parseLokiResponse(output)
+}
+
+var cloudWatchLabelValid = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// cloudWatchFilterPattern builds a filter pattern matching the pod labels fluent-bit's
+// kubernetes filter adds to each record
+func cloudWatchFilterPattern(selector map[string]string) (string, error) {
+	if len(selector) == 0 {
+		return "", nil
+	}
+	keys := make([]string, 0, len(selector))
+	for key := range selector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	terms := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !cloudWatchLabelValid.MatchString(key) {
+			return "", fmt.Errorf("label %q can't be matched in CloudWatch; use letters, digits and underscores", key)
+		}
+		terms = append(terms, fmt.Sprintf("$.kubernetes.labels.%s = %q", key, selector[key]))
+	}
+	return "{ " + strings.Join(terms, " && ") + " }", nil
+}
+
+// parseCloudWatchEvents converts filter-log-events output into entries, oldest first
+func parseCloudWatchEvents(output []byte) ([]LogEntry, error) {
+	var response struct {
+		Events []struct {
+			LogStreamName string `json:"logStreamName"`
+			Timestamp     int64  `json:"timestamp"`
+			Message       string `json:"message"`
+		} `json:"events"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse CloudWatch events: %w", err)
+	}
+
+	entries := make([]LogEntry, 0, len(response.Events))
+	for _, event := range response.Events {
+		entry := LogEntry{
+			Time:   time.UnixMilli(event.Timestamp).UTC(),
+			Labels: map[string]string{"stream": event.LogStreamName},
+			Line:   event.Message,
+		}
+		var record struct {
+			Log        string `json:"log"`
+			Kubernetes struct {
+				Namespace string            `json:"namespace_name"`
+				Pod       string            `json:"pod_name"`
+				Container string            `json:"container_name"`
+				Labels    map[string]string `json:"labels"`
+			} `json:"kubernetes"`
+		}
+		if json.Unmarshal([]byte(event.Message), &record) == nil && record.Log != "" {
+			entry.Line = strings.TrimRight(record.Log, "\n")
+			entry.Labels = map[string]string{"namespace": record.Kubernetes.Namespace, "pod": record.Kubernetes.Pod, "container": record.Kubernetes.Container}
+			for key, value := range record.Kubernetes.Labels {
+				entry.Labels[key] = value
+			}
+		}
+		entries = append(entries, entry)
+	}
+	sortLogEntries(entries)
+	return entries, nil
+}
+
+func sortLogEntries(entries []LogEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+}
+
+// QueryLogs queries the Loki stack installed in the minikube cluster
+func (l *LocalProvider) QueryLogs(ctx context.Context, clusterName string, query LogQuery) ([]LogEntry, error) {
+	if query.Backend != "" && query.Backend != LogBackendLoki {
+		return nil, fmt.Errorf("the local provider only supports the %s log backend", LogBackendLoki)
+	}
+	return queryLoki(ctx, []string{"minikube", "kubectl", "-p", clusterName, "--"}, query)
+}
+
+// QueryLogs queries the Loki stack installed in the kind cluster
+func (k *KindProvider) QueryLogs(ctx context.Context, clusterName string, query LogQuery) ([]LogEntry, error) {
+	if query.Backend != "" && query.Backend != LogBackendLoki {
+		return nil, fmt.Errorf("the kind provider only supports the %s log backend", LogBackendLoki)
+	}
+	return queryLoki(ctx, []string{"kubectl", "--context", "kind-" + clusterName}, query)
+}
+
+// QueryLogs queries the Loki stack installed in the k3d cluster
+func (k *K3dProvider) QueryLogs(ctx context.Context, clusterName string, query LogQuery) ([]LogEntry, error) {
+	if query.Backend != "" && query.Backend != LogBackendLoki {
+		return nil, fmt.Errorf("the k3d provider only supports the %s log backend", LogBackendLoki)
+	}
+	return queryLoki(ctx, []string{"kubectl", "--context", "k3d-" + clusterName}, query)
+}
+
+// QueryLogs queries the cluster's CloudWatch log group, or its Loki stack when backend is loki
+func (a *AWSProvider) QueryLogs(ctx context.Context, clusterName string, query LogQuery) ([]LogEntry, error) {
+	switch query.Backend {
+	case LogBackendLoki:
+		kubeContext, err := a.updateKubeConfig(ctx, clusterName, a.region)
+		if err != nil {
+			return nil, err
+		}
+		return queryLoki(ctx, []string{"kubectl", "--context", kubeContext}, query)
+	case "", LogBackendCloudWatch:
+	default:
+		return nil, fmt.Errorf("unknown log backend %q", query.Backend)
+	}
+
+	pattern, err := cloudWatchFilterPattern(query.Selector)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"logs", "filter-log-events",
+		"--log-group-name", cloudWatchLogGroup(clusterName),
+		"--start-time", strconv.FormatInt(time.Now().Add(-query.Since).UnixMilli(), 10),
+		"--max-items", strconv.Itoa(query.Limit),
+		"--region", a.region,
+		"--output", "json"}
+	if pattern != "" {
+		args = append(args, "--filter-pattern", pattern)
+	}
+	if a.profile != "" {
+		args = append(args, "--profile", a.profile)
+	}
+	output, err := subprocess.CommandContext(ctx, "aws", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CloudWatch log group %s; is log aggregation enabled for this cluster? %w", cloudWatchLogGroup(clusterName), err)
+	}
+	return parseCloudWatchEvents(output)
+}
+
+// updateKubeConfig writes a kubeconfig context for the EKS cluster and returns its name
+func (a *AWSProvider) updateKubeConfig(ctx context.Context, clusterName, region string) (string, error) {
+	kubeContext := "eks-" + clusterName
+	args := []string{"eks", "update-kubeconfig", "--name", clusterName, "--region", region, "--alias", kubeContext}
+	if a.profile != "" {
+		args = append(args, "--profile", a.profile)
+	}
+	output, err := subprocess.CommandContext(ctx, "aws", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to update kubeconfig: %s", strings.TrimSpace(string(output)))
+	}
+	return kubeContext, nil
+}
@@ -0,0 +1,189 @@
+package providers
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLogSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     map[string]string
+		wantErr  bool
+	}{
+		{selector: "", want: map[string]string{}},
+		{selector: "app=foo", want: map[string]string{"app": "foo"}},
+		{selector: `app=foo, tier="web"`, want: map[string]string{"app": "foo", "tier": "web"}},
+		{selector: "app", wantErr: true},
+		{selector: "app!=foo", wantErr: true},
+		{selector: "=foo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			got, err := ParseLogSelector(tt.selector)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseLogSelector(%q) expected error but got none", tt.selector)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLogSelector(%q) unexpected error = %v", tt.selector, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLogSelector(%q) = %v, want %v", tt.selector, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateLogConfig(t *testing.T) {
+	config := func(logs *LogConfig) *ClusterConfig {
+		return &ClusterConfig{Name: "dev", ResourceConfig: &ResourceConfig{Monitoring: &MonitoringConfig{LogAggregation: logs}}}
+	}
+	tests := []struct {
+		name       string
+		config     *ClusterConfig
+		cloudWatch bool
+		wantErr    bool
+	}{
+		{name: "not configured", config: &ClusterConfig{Name: "dev"}},
+		{name: "disabled with bad backend", config: config(&LogConfig{Backend: "splunk"})},
+		{name: "loki default", config: config(&LogConfig{Enabled: true, Retention: "14d"})},
+		{name: "cloudwatch on aws", config: config(&LogConfig{Enabled: true, Backend: "cloudwatch", Retention: "72h"}), cloudWatch: true},
+		{name: "cloudwatch off aws", config: config(&LogConfig{Enabled: true, Backend: "cloudwatch"}), wantErr: true},
+		{name: "unknown backend", config: config(&LogConfig{Enabled: true, Backend: "splunk"}), wantErr: true},
+		{name: "bad retention", config: config(&LogConfig{Enabled: true, Retention: "a week"}), wantErr: true},
+		{name: "bad chart value", config: config(&LogConfig{Enabled: true, Config: map[string]string{"a=b": "c"}}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ValidationResult{}
+			validateLogConfig(tt.config, tt.cloudWatch, result)
+			err := result.Err()
+			if tt.wantErr && err == nil {
+				t.Errorf("validateLogConfig() expected error but got none")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("validateLogConfig() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestParseRetentionDays(t *testing.T) {
+	tests := map[string]int{"7d": 7, "24h": 1, "25h": 2, "30m": 1}
+	for retention, want := range tests {
+		got, err := parseRetentionDays(retention)
+		if err != nil || got != want {
+			t.Errorf("parseRetentionDays(%q) = %d, %v; want %d", retention, got, err, want)
+		}
+	}
+	for _, retention := range []string{"0d", "-1h", "week"} {
+		if _, err := parseRetentionDays(retention); err == nil {
+			t.Errorf("parseRetentionDays(%q) expected error but got none", retention)
+		}
+	}
+	if got := cloudWatchRetention(10); got != 14 {
+		t.Errorf("cloudWatchRetention(10) = %d, want 14", got)
+	}
+}
+
+func TestLoggingChartArgs(t *testing.T) {
+	args, err := loggingChartArgs("kind-dev", "dev", "", &LogConfig{Enabled: true, Retention: "2d", Config: map[string]string{"loki.persistence.enabled": "true"}})
+	if err != nil {
+		t.Fatalf("loggingChartArgs() unexpected error = %v", err)
+	}
+	joined := strings.Join(args, " ")
+	for _, want := range []string{
+		"upgrade --install loki loki-stack --repo https://grafana.github.io/helm-charts",
+		"--kube-context kind-dev --namespace logging --create-namespace",
+		"--set loki.config.table_manager.retention_period=48h",
+		"--set loki.persistence.enabled=true",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("loggingChartArgs() = %s, missing %q", joined, want)
+		}
+	}
+
+	args, err = loggingChartArgs("eks-prod", "prod", "us-east-1", &LogConfig{Enabled: true, Backend: "cloudwatch", Retention: "10d"})
+	if err != nil {
+		t.Fatalf("loggingChartArgs() unexpected error = %v", err)
+	}
+	for _, want := range []string{"cloudWatchLogs.region=us-east-1", "cloudWatchLogs.logGroupName=/aws/eks/prod/containers", "cloudWatchLogs.logRetentionDays=14"} {
+		if !slices.Contains(args, want) {
+			t.Errorf("loggingChartArgs() = %v, missing %q", args, want)
+		}
+	}
+}
+
+func TestLokiQuery(t *testing.T) {
+	if got := lokiSelector(nil); got != `{namespace=~".+"}` {
+		t.Errorf("lokiSelector(nil) = %s", got)
+	}
+	if got := lokiSelector(map[string]string{"tier": "web", "app.kubernetes.io/name": "foo"}); got != `{app_kubernetes_io_name="foo",tier="web"}` {
+		t.Errorf("lokiSelector() = %s", got)
+	}
+
+	path := lokiQueryPath(LogQuery{Selector: map[string]string{"app": "foo"}, Since: time.Hour, Limit: 50}, time.Unix(7200, 0))
+	if !strings.HasPrefix(path, "/api/v1/namespaces/logging/services/loki:3100/proxy/loki/api/v1/query_range?") {
+		t.Errorf("lokiQueryPath() = %s", path)
+	}
+	for _, want := range []string{"limit=50", "start=3600000000000", "query=%7Bapp%3D%22foo%22%7D"} {
+		if !strings.Contains(path, want) {
+			t.Errorf("lokiQueryPath() = %s, missing %s", path, want)
+		}
+	}
+
+	output := `{"status":"success","data":{"resultType":"streams","result":[
+		{"stream":{"app":"foo","pod":"foo-1"},"values":[["3000000000","third"],["1000000000","first"]]},
+		{"stream":{"app":"foo","pod":"foo-2"},"values":[["2000000000","second"]]}]}}`
+	entries, err := parseLokiResponse([]byte(output))
+	if err != nil {
+		t.Fatalf("parseLokiResponse() unexpected error = %v", err)
+	}
+	var lines []string
+	for _, entry := range entries {
+		lines = append(lines, entry.Line)
+	}
+	if !reflect.DeepEqual(lines, []string{"first", "second", "third"}) {
+		t.Errorf("parseLokiResponse() lines = %v", lines)
+	}
+	if entries[1].Labels["pod"] != "foo-2" || !entries[0].Time.Equal(time.Unix(1, 0)) {
+		t.Errorf("parseLokiResponse() entries = %+v", entries)
+	}
+
+	if _, err := parseLokiResponse([]byte(`{"status":"error"}`)); err == nil {
+		t.Error("parseLokiResponse() expected error for a failed query")
+	}
+}
+
+func TestCloudWatchQuery(t *testing.T) {
+	pattern, err := cloudWatchFilterPattern(map[string]string{"tier": "web", "app": "foo"})
+	if err != nil || pattern != `{ $.kubernetes.labels.app = "foo" && $.kubernetes.labels.tier = "web" }` {
+		t.Errorf("cloudWatchFilterPattern() = %s, %v", pattern, err)
+	}
+	if _, err := cloudWatchFilterPattern(map[string]string{"app.kubernetes.io/name": "foo"}); err == nil {
+		t.Error("cloudWatchFilterPattern() expected error for a label CloudWatch can't match")
+	}
+
+	output := `{"events":[
+		{"logStreamName":"foo-1","timestamp":2000,"message":"{\"log\":\"GET / 200\\n\",\"kubernetes\":{\"namespace_name\":\"web\",\"pod_name\":\"foo-1\",\"container_name\":\"foo\",\"labels\":{\"app\":\"foo\"}}}"},
+		{"logStreamName":"raw","timestamp":1000,"message":"plain text"}]}`
+	entries, err := parseCloudWatchEvents([]byte(output))
+	if err != nil {
+		t.Fatalf("parseCloudWatchEvents() unexpected error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Line != "plain text" || entries[0].Labels["stream"] != "raw" {
+		t.Fatalf("parseCloudWatchEvents() = %+v", entries)
+	}
+	want := map[string]string{"namespace": "web", "pod": "foo-1", "container": "foo", "app": "foo"}
+	if entries[1].Line != "GET / 200" || !reflect.DeepEqual(entries[1].Labels, want) {
+		t.Errorf("parseCloudWatchEvents() = %+v", entries[1])
+	}
+}
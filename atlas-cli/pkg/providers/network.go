@@ -0,0 +1,113 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// NetworkInspector is implemented by providers that can report the network settings a cluster
+// actually runs with. Settings the provider doesn't expose are left empty.
+type NetworkInspector interface {
+	GetNetworkConfig(ctx context.Context, name string) (*NetworkConfig, error)
+}
+
+var _ NetworkInspector = (*LocalProvider)(nil)
+var _ NetworkInspector = (*AWSProvider)(nil)
+var _ NetworkInspector = (*FakeProvider)(nil)
+
+// GetNetworkConfig reads the network settings minikube saved in the profile's config
+func (l *LocalProvider) GetNetworkConfig(ctx context.Context, name string) (*NetworkConfig, error) {
+	cmd := subprocess.CommandContext(ctx, "minikube", "profile", "list", "-o=json")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error getting profiles: %w", err)
+	}
+	return parseMinikubeNetwork(output, name)
+}
+
+// parseMinikubeNetwork finds profile name in minikube profile list -o=json output and reads its
+// network settings
+func parseMinikubeNetwork(output []byte, name string) (*NetworkConfig, error) {
+	var profiles struct {
+		Valid []struct {
+			Name   string
+			Config struct {
+				KubernetesConfig struct {
+					APIServerPort int
+					ServiceCIDR   string
+					CNI           string
+					ExtraOptions  []struct {
+						Component string
+						Key       string
+						Value     string
+					}
+				}
+				Nodes []struct {
+					Port         int
+					ControlPlane bool
+				}
+			}
+		} `json:"valid"`
+	}
+	if err := json.Unmarshal(output, &profiles); err != nil {
+		return nil, fmt.Errorf("error unmarshaling profiles: %w", err)
+	}
+
+	for _, profile := range profiles.Valid {
+		if profile.Name != name {
+			continue
+		}
+		k8s := profile.Config.KubernetesConfig
+		network := &NetworkConfig{
+			ServiceCIDR:   k8s.ServiceCIDR,
+			NetworkPlugin: k8s.CNI,
+			APIServerPort: k8s.APIServerPort,
+		}
+		if network.NetworkPlugin == "" {
+			network.NetworkPlugin = "auto"
+		}
+		for _, node := range profile.Config.Nodes {
+			if node.ControlPlane && node.Port > 0 {
+				network.APIServerPort = node.Port
+				break
+			}
+		}
+		for _, option := range k8s.ExtraOptions {
+			if option.Component == "kubeadm" && option.Key == "pod-network-cidr" {
+				network.PodCIDR = option.Value
+			}
+		}
+		return network, nil
+	}
+	return nil, clusterNotFound(name)
+}
+
+// GetNetworkConfig reports the EKS cluster's service CIDR. Pods get addresses from the VPC's
+// subnets, so there is no pod CIDR to report.
+func (a *AWSProvider) GetNetworkConfig(ctx context.Context, name string) (*NetworkConfig, error) {
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "describe-cluster",
+		"--name", name,
+		"--region", a.region,
+		"--query", "cluster.kubernetesNetworkConfig",
+		"--output", "json")
+
+	if a.profile != "" {
+		cmd.Args = append(cmd.Args, "--profile", a.profile)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster: %w", err)
+	}
+
+	var result struct {
+		ServiceIPv4CIDR string `json:"serviceIpv4Cidr"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster network config: %w", err)
+	}
+	return &NetworkConfig{ServiceCIDR: result.ServiceIPv4CIDR}, nil
+}
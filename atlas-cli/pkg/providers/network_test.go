@@ -0,0 +1,26 @@
+package providers
+
+import "testing"
+
+func TestParseMinikubeNetwork(t *testing.T) {
+	output := []byte(`{"invalid":[],"valid":[
+		{"Name":"other","Config":{"KubernetesConfig":{"ServiceCIDR":"10.0.0.0/16"}}},
+		{"Name":"dev","Status":"Running","Config":{
+			"KubernetesConfig":{"KubernetesVersion":"v1.31.0","ServiceCIDR":"10.96.0.0/12","CNI":"calico",
+				"ExtraOptions":[{"Component":"kubeadm","Key":"pod-network-cidr","Value":"10.244.0.0/16"}]},
+			"Nodes":[{"Name":"","Port":8443,"ControlPlane":true},{"Name":"m02","Port":0,"ControlPlane":false}]}}]}`)
+
+	network, err := parseMinikubeNetwork(output, "dev")
+	if err != nil {
+		t.Fatalf("parseMinikubeNetwork() error = %v", err)
+	}
+	want := NetworkConfig{PodCIDR: "10.244.0.0/16", ServiceCIDR: "10.96.0.0/12", NetworkPlugin: "calico", APIServerPort: 8443}
+	if network.PodCIDR != want.PodCIDR || network.ServiceCIDR != want.ServiceCIDR ||
+		network.NetworkPlugin != want.NetworkPlugin || network.APIServerPort != want.APIServerPort {
+		t.Errorf("parseMinikubeNetwork() = %+v, want %+v", *network, want)
+	}
+
+	if _, err := parseMinikubeNetwork(output, "missing"); err == nil {
+		t.Error("parseMinikubeNetwork() found a profile that doesn't exist")
+	}
+}
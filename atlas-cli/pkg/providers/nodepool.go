@@ -0,0 +1,427 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// NodePool is a group of nodes in a cluster that share an instance type, labels and taints
+type NodePool struct {
+	Name         string            `json:"name"`
+	Status       string            `json:"status"`
+	OS           string            `json:"os,omitempty"`
+	AMIType      string            `json:"amiType,omitempty"`
+	InstanceType string            `json:"instanceType,omitempty"`
+	Scaling      NodePoolScaling   `json:"scaling"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Taints       []Taint           `json:"taints,omitempty"`
+}
+
+// NodePoolScaling is the size range of a node pool and the number of nodes it should run
+type NodePoolScaling struct {
+	MinSize     int `json:"minSize"`
+	MaxSize     int `json:"maxSize"`
+	DesiredSize int `json:"desiredSize"`
+}
+
+// Validate checks that 0 <= min <= desired <= max and max is at least 1
+func (s NodePoolScaling) Validate() error {
+	if s.MinSize < 0 || s.MaxSize < 1 {
+		return fmt.Errorf("node pool needs min size >= 0 and max size >= 1 (got min %d, max %d)", s.MinSize, s.MaxSize)
+	}
+	if s.DesiredSize < s.MinSize || s.DesiredSize > s.MaxSize {
+		return fmt.Errorf("desired size %d must be between min size %d and max size %d", s.DesiredSize, s.MinSize, s.MaxSize)
+	}
+	return nil
+}
+
+// Taint keeps pods off a node pool's nodes unless they tolerate it
+type Taint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect"`
+}
+
+// taintEffects are the Kubernetes taint effects and their EKS spellings
+var taintEffects = map[string]string{
+	"NoSchedule":       "NO_SCHEDULE",
+	"PreferNoSchedule": "PREFER_NO_SCHEDULE",
+	"NoExecute":        "NO_EXECUTE",
+}
+
+// ParseTaint parses a taint in kubectl's key=value:Effect or key:Effect form
+func ParseTaint(value string) (Taint, error) {
+	spec, effect, ok := strings.Cut(value, ":")
+	if !ok {
+		return Taint{}, fmt.Errorf("invalid taint %q; use key=value:Effect", value)
+	}
+	key, taintValue, _ := strings.Cut(spec, "=")
+	if key == "" {
+		return Taint{}, fmt.Errorf("invalid taint %q: key is empty", value)
+	}
+	if _, ok := taintEffects[effect]; !ok {
+		return Taint{}, fmt.Errorf("invalid taint %q: effect must be NoSchedule, PreferNoSchedule or NoExecute", value)
+	}
+	return Taint{Key: key, Value: taintValue, Effect: effect}, nil
+}
+
+func (t Taint) String() string {
+	if t.Value == "" {
+		return t.Key + ":" + t.Effect
+	}
+	return t.Key + "=" + t.Value + ":" + t.Effect
+}
+
+// Operating systems a node pool's nodes can run
+const (
+	NodeOSLinux   = "linux"
+	NodeOSWindows = "windows"
+)
+
+// minWindowsKubeVersion is the oldest Kubernetes version EKS runs Windows managed node groups on
+const minWindowsKubeVersion = "1.23"
+
+// defaultWindowsAMIType is the EKS AMI type of Windows node pools created without one
+const defaultWindowsAMIType = "WINDOWS_CORE_2022_x86_64"
+
+// windowsTaint keeps Linux pods off Windows nodes when a Windows pool is created without taints
+var windowsTaint = Taint{Key: "os", Value: "windows", Effect: "NoSchedule"}
+
+// NodePoolConfig describes a node pool to create
+type NodePoolConfig struct {
+	Name string
+	// OS is linux or windows; empty means the OS of AMIType, or linux
+	OS string
+	// AMIType is the EKS AMI type, e.g. AL2023_x86_64_STANDARD or WINDOWS_CORE_2022_x86_64
+	AMIType      string
+	InstanceType string
+	Scaling      NodePoolScaling
+	Labels       map[string]string
+	Taints       []Taint
+}
+
+// NodePoolManager is implemented by providers whose clusters can run several node pools, such as
+// EKS managed node groups
+type NodePoolManager interface {
+	// ListNodePools returns the cluster's node pools sorted by name
+	ListNodePools(ctx context.Context, clusterName string) ([]NodePool, error)
+	// CreateNodePool adds a node pool and waits for its nodes to become ready
+	CreateNodePool(ctx context.Context, clusterName string, config NodePoolConfig) error
+	ScaleNodePool(ctx context.Context, clusterName, pool string, scaling NodePoolScaling) error
+	DeleteNodePool(ctx context.Context, clusterName, pool string) error
+}
+
+var _ NodePoolManager = (*AWSProvider)(nil)
+var _ NodePoolManager = (*FakeProvider)(nil)
+
+// amiTypeOS returns the operating system of an EKS AMI type
+func amiTypeOS(amiType string) string {
+	if strings.HasPrefix(strings.ToUpper(amiType), "WINDOWS_") {
+		return NodeOSWindows
+	}
+	return NodeOSLinux
+}
+
+// resolveNodePoolOS fills in config's OS, and for Windows pools its AMI type and default taint,
+// and checks that the cluster can run it: Windows nodes need Kubernetes 1.23 or later and a
+// Linux node pool for CoreDNS and the other system pods that only run on Linux
+func resolveNodePoolOS(config NodePoolConfig, clusterVersion string, existing []NodePool) (NodePoolConfig, error) {
+	config.OS = strings.ToLower(config.OS)
+	switch {
+	case config.OS == "" && config.AMIType != "":
+		config.OS = amiTypeOS(config.AMIType)
+	case config.OS == "":
+		config.OS = NodeOSLinux
+	case config.OS != NodeOSLinux && config.OS != NodeOSWindows:
+		return config, fmt.Errorf("unsupported node OS %q (want linux or windows)", config.OS)
+	case config.AMIType != "" && amiTypeOS(config.AMIType) != config.OS:
+		return config, fmt.Errorf("AMI type %s is not a %s AMI", config.AMIType, config.OS)
+	}
+	if config.OS != NodeOSWindows {
+		return config, nil
+	}
+
+	version, err := parseKubeVersion(clusterVersion)
+	if err != nil {
+		return config, fmt.Errorf("cannot check Windows support of cluster version: %w", err)
+	}
+	minimum, _ := parseKubeVersion(minWindowsKubeVersion)
+	if compareKubeVersions(version, minimum) < 0 {
+		return config, fmt.Errorf("Windows node pools need Kubernetes %s or later; the cluster runs %s", minWindowsKubeVersion, clusterVersion)
+	}
+	hasLinux := false
+	for _, pool := range existing {
+		hasLinux = hasLinux || pool.OS != NodeOSWindows
+	}
+	if !hasLinux {
+		return config, fmt.Errorf("Windows node pools need a Linux node pool in the cluster to run system pods")
+	}
+
+	if config.AMIType == "" {
+		config.AMIType = defaultWindowsAMIType
+	}
+	if len(config.Taints) == 0 {
+		config.Taints = []Taint{windowsTaint}
+	}
+	return config, nil
+}
+
+// ListNodePools describes each of the cluster's EKS node groups
+func (a *AWSProvider) ListNodePools(ctx context.Context, clusterName string) ([]NodePool, error) {
+	names, err := a.listNodeGroups(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	var pools []NodePool
+	for _, name := range names {
+		nodeGroup, err := a.describeNodeGroup(ctx, clusterName, name)
+		if err != nil {
+			return nil, err
+		}
+		pools = append(pools, eksNodePool(nodeGroup))
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Name < pools[j].Name })
+	return pools, nil
+}
+
+// eksNodePool converts a described EKS node group, mapping its taint effects back to Kubernetes'
+// spelling
+func eksNodePool(nodeGroup *EKSNodegroup) NodePool {
+	pool := NodePool{
+		Name:    nodeGroup.NodegroupName,
+		Status:  strings.ToLower(nodeGroup.Status),
+		OS:      amiTypeOS(nodeGroup.AmiType),
+		AMIType: nodeGroup.AmiType,
+		Scaling: NodePoolScaling{
+			MinSize:     nodeGroup.ScalingConfig.MinSize,
+			MaxSize:     nodeGroup.ScalingConfig.MaxSize,
+			DesiredSize: nodeGroup.ScalingConfig.DesiredSize,
+		},
+		Labels: nodeGroup.Labels,
+	}
+	if len(nodeGroup.InstanceTypes) > 0 {
+		pool.InstanceType = strings.Join(nodeGroup.InstanceTypes, ",")
+	}
+	for _, taint := range nodeGroup.Taints {
+		effect := taint.Effect
+		for kubeEffect, eksEffect := range taintEffects {
+			if eksEffect == taint.Effect {
+				effect = kubeEffect
+			}
+		}
+		pool.Taints = append(pool.Taints, Taint{Key: taint.Key, Value: taint.Value, Effect: effect})
+	}
+	return pool
+}
+
+// CreateNodePool creates an EKS managed node group in the cluster's subnets, using the node role
+// of an existing node group when there is one. Before a cluster's first Windows node group joins,
+// Windows IP address management is turned on in the VPC CNI so Windows pods get addresses.
+func (a *AWSProvider) CreateNodePool(ctx context.Context, clusterName string, config NodePoolConfig) error {
+	ctx = subprocess.WithOperation(ctx, "create")
+	if err := config.Scaling.Validate(); err != nil {
+		return err
+	}
+	subnets, err := a.clusterSubnets(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	names, err := a.listNodeGroups(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	nodeRole := a.getNodeInstanceRoleArn(&ClusterConfig{})
+	var existing []NodePool
+	for _, name := range names {
+		nodeGroup, err := a.describeNodeGroup(ctx, clusterName, name)
+		if err != nil {
+			return err
+		}
+		if len(existing) == 0 && nodeGroup.NodeRole != "" {
+			nodeRole = nodeGroup.NodeRole
+		}
+		existing = append(existing, eksNodePool(nodeGroup))
+	}
+
+	clusterVersion, err := a.clusterVersion(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	if config, err = resolveNodePoolOS(config, clusterVersion, existing); err != nil {
+		return err
+	}
+	if config.OS == NodeOSWindows {
+		if err := a.enableWindowsIPAM(ctx, clusterName); err != nil {
+			return err
+		}
+	}
+
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "create-nodegroup",
+		"--cluster-name", clusterName,
+		"--region", a.region)
+	cmd.Args = append(cmd.Args, nodeGroupArgs(config, subnets, nodeRole)...)
+	if a.profile != "" {
+		cmd.Args = append(cmd.Args, "--profile", a.profile)
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to create node group %s: %s", config.Name, strings.TrimSpace(string(output)))
+	}
+	return a.waitForNodeGroupActive(ctx, clusterName, config.Name, a.region)
+}
+
+// nodeGroupArgs builds the create-nodegroup flags for config
+func nodeGroupArgs(config NodePoolConfig, subnets []string, nodeRole string) []string {
+	instanceType := config.InstanceType
+	if instanceType == "" && config.OS == NodeOSWindows {
+		instanceType = "m5.large"
+	} else if instanceType == "" {
+		instanceType = "t3.medium"
+	}
+	args := []string{
+		"--nodegroup-name", config.Name,
+		"--subnets", strings.Join(subnets, ","),
+		"--node-role", nodeRole,
+		"--instance-types", instanceType,
+		"--scaling-config", fmt.Sprintf("minSize=%d,maxSize=%d,desiredSize=%d",
+			config.Scaling.MinSize, config.Scaling.MaxSize, config.Scaling.DesiredSize),
+	}
+	if config.AMIType != "" {
+		args = append(args, "--ami-type", config.AMIType)
+	}
+	if len(config.Labels) > 0 {
+		args = append(args, "--labels", formatTags(config.Labels, ","))
+	}
+	if len(config.Taints) > 0 {
+		args = append(args, "--taints")
+		for _, taint := range config.Taints {
+			args = append(args, fmt.Sprintf("key=%s,value=%s,effect=%s", taint.Key, taint.Value, taintEffects[taint.Effect]))
+		}
+	}
+	return args
+}
+
+// clusterSubnets returns the subnets the EKS cluster was created in
+func (a *AWSProvider) clusterSubnets(ctx context.Context, clusterName string) ([]string, error) {
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "describe-cluster",
+		"--name", clusterName,
+		"--region", a.region,
+		"--query", "cluster.resourcesVpcConfig.subnetIds",
+		"--output", "json")
+	if a.profile != "" {
+		cmd.Args = append(cmd.Args, "--profile", a.profile)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster: %w", err)
+	}
+	var subnets []string
+	if err := json.Unmarshal(output, &subnets); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster subnets: %w", err)
+	}
+	if len(subnets) == 0 {
+		return nil, fmt.Errorf("cluster %s has no subnets", clusterName)
+	}
+	return subnets, nil
+}
+
+// clusterVersion returns the EKS cluster's Kubernetes version, e.g. 1.31
+func (a *AWSProvider) clusterVersion(ctx context.Context, clusterName string) (string, error) {
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "describe-cluster",
+		"--name", clusterName,
+		"--region", a.region,
+		"--query", "cluster.version",
+		"--output", "text")
+	if a.profile != "" {
+		cmd.Args = append(cmd.Args, "--profile", a.profile)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to describe cluster: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// enableWindowsIPAM turns on the VPC CNI's Windows IP address management, which EKS requires
+// before Windows nodes can run pods. Enabling it again is harmless.
+func (a *AWSProvider) enableWindowsIPAM(ctx context.Context, clusterName string) error {
+	kubeContext, err := a.updateKubeConfig(ctx, clusterName, a.region)
+	if err != nil {
+		return err
+	}
+	if _, err := runKubectl(ctx, "", "--context", kubeContext, "patch", "configmap", "amazon-vpc-cni",
+		"-n", "kube-system", "--type", "merge", "-p", `{"data":{"enable-windows-ipam":"true"}}`); err != nil {
+		return fmt.Errorf("failed to enable Windows IPAM in the VPC CNI: %w", err)
+	}
+	return nil
+}
+
+// ScaleNodePool changes the node group's size range and desired size
+func (a *AWSProvider) ScaleNodePool(ctx context.Context, clusterName, pool string, scaling NodePoolScaling) error {
+	ctx = subprocess.WithOperation(ctx, "scale")
+	if err := scaling.Validate(); err != nil {
+		return err
+	}
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "update-nodegroup-config",
+		"--cluster-name", clusterName,
+		"--nodegroup-name", pool,
+		"--scaling-config", fmt.Sprintf("minSize=%d,maxSize=%d,desiredSize=%d", scaling.MinSize, scaling.MaxSize, scaling.DesiredSize),
+		"--region", a.region)
+	if a.profile != "" {
+		cmd.Args = append(cmd.Args, "--profile", a.profile)
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to scale node group %s: %s", pool, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// DeleteNodePool deletes the node group and waits until EKS has removed it
+func (a *AWSProvider) DeleteNodePool(ctx context.Context, clusterName, pool string) error {
+	ctx = subprocess.WithOperation(ctx, "delete")
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "delete-nodegroup",
+		"--cluster-name", clusterName,
+		"--nodegroup-name", pool,
+		"--region", a.region)
+	if a.profile != "" {
+		cmd.Args = append(cmd.Args, "--profile", a.profile)
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to delete node group %s: %s", pool, strings.TrimSpace(string(output)))
+	}
+
+	deadline := time.Now().Add(15 * time.Minute)
+	for time.Now().Before(deadline) {
+		nodeGroups, err := a.listNodeGroups(ctx, clusterName)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, name := range nodeGroups {
+			found = found || name == pool
+		}
+		if !found {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(30 * time.Second):
+		}
+	}
+	return fmt.Errorf("timeout waiting for node group %s to be deleted", pool)
+}
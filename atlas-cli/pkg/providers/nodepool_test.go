@@ -0,0 +1,191 @@
+package providers
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTaint(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Taint
+		wantErr bool
+	}{
+		{"nvidia.com/gpu=present:NoSchedule", Taint{Key: "nvidia.com/gpu", Value: "present", Effect: "NoSchedule"}, false},
+		{"dedicated:NoExecute", Taint{Key: "dedicated", Effect: "NoExecute"}, false},
+		{"dedicated=batch", Taint{}, true},
+		{"=batch:NoSchedule", Taint{}, true},
+		{"dedicated=batch:NO_SCHEDULE", Taint{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTaint(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseTaint(%q) = %+v, %v, want %+v (error %v)", tt.value, got, err, tt.want, tt.wantErr)
+		}
+		if err == nil && got.String() != tt.value {
+			t.Errorf("ParseTaint(%q).String() = %q", tt.value, got.String())
+		}
+	}
+}
+
+func TestNodePoolScaling_Validate(t *testing.T) {
+	for _, scaling := range []NodePoolScaling{{0, 3, 0}, {1, 3, 2}, {2, 2, 2}} {
+		if err := scaling.Validate(); err != nil {
+			t.Errorf("Validate(%+v) error = %v", scaling, err)
+		}
+	}
+	for _, scaling := range []NodePoolScaling{{0, 0, 0}, {-1, 3, 1}, {2, 3, 1}, {1, 3, 4}} {
+		if err := scaling.Validate(); err == nil {
+			t.Errorf("Validate(%+v) should fail", scaling)
+		}
+	}
+}
+
+func TestNodeGroupArgs(t *testing.T) {
+	config := NodePoolConfig{
+		Name:    "gpu",
+		Scaling: NodePoolScaling{MinSize: 0, MaxSize: 4, DesiredSize: 2},
+		Labels:  map[string]string{"workload": "gpu", "team": "ml"},
+		Taints:  []Taint{{Key: "nvidia.com/gpu", Value: "present", Effect: "NoSchedule"}},
+	}
+	got := nodeGroupArgs(config, []string{"subnet-a", "subnet-b"}, "arn:aws:iam::1:role/nodes")
+	want := []string{
+		"--nodegroup-name", "gpu",
+		"--subnets", "subnet-a,subnet-b",
+		"--node-role", "arn:aws:iam::1:role/nodes",
+		"--instance-types", "t3.medium",
+		"--scaling-config", "minSize=0,maxSize=4,desiredSize=2",
+		"--labels", "team=ml,workload=gpu",
+		"--taints", "key=nvidia.com/gpu,value=present,effect=NO_SCHEDULE",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("nodeGroupArgs() = %q\nwant %q", got, want)
+	}
+
+	windows := NodePoolConfig{Name: "win", OS: NodeOSWindows, AMIType: "WINDOWS_CORE_2022_x86_64",
+		Scaling: NodePoolScaling{MinSize: 1, MaxSize: 1, DesiredSize: 1}, Taints: []Taint{windowsTaint}}
+	got = nodeGroupArgs(windows, []string{"subnet-a"}, "arn:aws:iam::1:role/nodes")
+	want = []string{
+		"--nodegroup-name", "win",
+		"--subnets", "subnet-a",
+		"--node-role", "arn:aws:iam::1:role/nodes",
+		"--instance-types", "m5.large",
+		"--scaling-config", "minSize=1,maxSize=1,desiredSize=1",
+		"--ami-type", "WINDOWS_CORE_2022_x86_64",
+		"--taints", "key=os,value=windows,effect=NO_SCHEDULE",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("nodeGroupArgs(windows) = %q\nwant %q", got, want)
+	}
+}
+
+func TestResolveNodePoolOS(t *testing.T) {
+	linuxPool := []NodePool{{Name: "nodes", OS: NodeOSLinux}}
+	tests := []struct {
+		name     string
+		config   NodePoolConfig
+		version  string
+		existing []NodePool
+		want     NodePoolConfig
+		wantErr  string
+	}{
+		{name: "linux by default", version: "1.20", want: NodePoolConfig{OS: NodeOSLinux}},
+		{name: "os from AMI type", config: NodePoolConfig{AMIType: "WINDOWS_FULL_2019_x86_64"}, version: "1.30", existing: linuxPool,
+			want: NodePoolConfig{OS: NodeOSWindows, AMIType: "WINDOWS_FULL_2019_x86_64", Taints: []Taint{windowsTaint}}},
+		{name: "windows defaults", config: NodePoolConfig{OS: "Windows"}, version: "1.31", existing: linuxPool,
+			want: NodePoolConfig{OS: NodeOSWindows, AMIType: defaultWindowsAMIType, Taints: []Taint{windowsTaint}}},
+		{name: "windows keeps taints", config: NodePoolConfig{OS: NodeOSWindows, Taints: []Taint{{Key: "team", Effect: "NoExecute"}}}, version: "v1.23.4", existing: linuxPool,
+			want: NodePoolConfig{OS: NodeOSWindows, AMIType: defaultWindowsAMIType, Taints: []Taint{{Key: "team", Effect: "NoExecute"}}}},
+		{name: "unknown os", config: NodePoolConfig{OS: "macos"}, wantErr: "unsupported node OS"},
+		{name: "mismatched AMI", config: NodePoolConfig{OS: NodeOSLinux, AMIType: "WINDOWS_CORE_2022_x86_64"}, wantErr: "is not a linux AMI"},
+		{name: "old cluster", config: NodePoolConfig{OS: NodeOSWindows}, version: "1.22", existing: linuxPool, wantErr: "need Kubernetes 1.23 or later"},
+		{name: "no linux pool", config: NodePoolConfig{OS: NodeOSWindows}, version: "1.30",
+			existing: []NodePool{{Name: "win", OS: NodeOSWindows}}, wantErr: "need a Linux node pool"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveNodePoolOS(tt.config, tt.version, tt.existing)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveNodePoolOS() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveNodePoolOS() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveNodePoolOS() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEKSNodePool(t *testing.T) {
+	pool := eksNodePool(&EKSNodegroup{
+		NodegroupName: "gpu",
+		Status:        "ACTIVE",
+		InstanceTypes: []string{"g5.xlarge"},
+		ScalingConfig: EKSScalingConfig{MinSize: 0, MaxSize: 4, DesiredSize: 2},
+		Taints:        []Taint{{Key: "dedicated", Effect: "NO_EXECUTE"}},
+	})
+	want := NodePool{
+		Name: "gpu", Status: "active", OS: NodeOSLinux, InstanceType: "g5.xlarge",
+		Scaling: NodePoolScaling{MinSize: 0, MaxSize: 4, DesiredSize: 2},
+		Taints:  []Taint{{Key: "dedicated", Effect: "NoExecute"}},
+	}
+	if !reflect.DeepEqual(pool, want) {
+		t.Errorf("eksNodePool() = %+v, want %+v", pool, want)
+	}
+}
+
+func TestFakeProvider_NodePools(t *testing.T) {
+	ctx := context.Background()
+	p := NewFakeProvider(FakeOptions{StatePath: filepath.Join(t.TempDir(), "state.json")})
+	if _, err := p.CreateCluster(ctx, &ClusterConfig{Name: "dev", NodeCount: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	config := NodePoolConfig{Name: "gpu", Scaling: NodePoolScaling{MinSize: 0, MaxSize: 2, DesiredSize: 1}}
+	if err := p.CreateNodePool(ctx, "dev", config); err != nil {
+		t.Fatalf("CreateNodePool() error = %v", err)
+	}
+	if err := p.CreateNodePool(ctx, "dev", config); err == nil {
+		t.Error("CreateNodePool() should fail for an existing pool")
+	}
+	if err := p.ScaleNodePool(ctx, "dev", "dev-nodes", NodePoolScaling{MinSize: 1, MaxSize: 3, DesiredSize: 3}); err != nil {
+		t.Fatalf("ScaleNodePool(default) error = %v", err)
+	}
+	if cluster, _ := p.GetCluster(ctx, "dev"); cluster.NodeCount != 3 {
+		t.Errorf("NodeCount = %d after scaling the default pool, want 3", cluster.NodeCount)
+	}
+
+	pools, err := p.ListNodePools(ctx, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pools) != 2 || pools[0].Name != "dev-nodes" || pools[1].Name != "gpu" || pools[1].Scaling.DesiredSize != 1 {
+		t.Errorf("ListNodePools() = %+v, want dev-nodes and gpu", pools)
+	}
+
+	windows := NodePoolConfig{Name: "win", OS: NodeOSWindows, Scaling: NodePoolScaling{MinSize: 1, MaxSize: 1, DesiredSize: 1}}
+	if err := p.CreateNodePool(ctx, "dev", windows); err != nil {
+		t.Fatalf("CreateNodePool(windows) error = %v", err)
+	}
+	if pools, _ := p.ListNodePools(ctx, "dev"); len(pools) != 3 || pools[2].AMIType != defaultWindowsAMIType || len(pools[2].Taints) != 1 {
+		t.Errorf("ListNodePools() = %+v, want a Windows pool with the default AMI and taint", pools)
+	}
+
+	if err := p.DeleteNodePool(ctx, "dev", "dev-nodes"); err == nil {
+		t.Error("DeleteNodePool() should refuse the default pool")
+	}
+	if err := p.DeleteNodePool(ctx, "dev", "gpu"); err != nil {
+		t.Fatalf("DeleteNodePool() error = %v", err)
+	}
+	if pools, _ := p.ListNodePools(ctx, "dev"); len(pools) != 2 {
+		t.Errorf("ListNodePools() after delete = %+v, want the default and Windows pools", pools)
+	}
+}
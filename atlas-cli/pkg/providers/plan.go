@@ -0,0 +1,83 @@
+package providers
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// PlanActionType is a kind of change apply makes to a cluster
+type PlanActionType string
+
+const (
+	PlanCreate      PlanActionType = "create"
+	PlanUpgrade     PlanActionType = "upgrade"
+	PlanScale       PlanActionType = "scale"
+	PlanEnableAddon PlanActionType = "enable-addon"
+)
+
+// PlanAction is one change needed to bring a cluster in line with its config
+type PlanAction struct {
+	Type PlanActionType `json:"type"`
+	From string         `json:"from,omitempty"`
+	To   string         `json:"to"`
+}
+
+func (a PlanAction) String() string {
+	switch a.Type {
+	case PlanCreate:
+		return "+ cluster    " + a.To
+	case PlanUpgrade:
+		return fmt.Sprintf("~ version    %s -> %s", a.From, a.To)
+	case PlanScale:
+		return fmt.Sprintf("~ nodeCount  %s -> %s", a.From, a.To)
+	case PlanEnableAddon:
+		return "+ addon      " + a.To
+	}
+	return fmt.Sprintf("%s %s", a.Type, a.To)
+}
+
+// Plan lists the changes, in the order they run, that bring a cluster in line with its config
+type Plan struct {
+	Cluster string       `json:"cluster"`
+	Actions []PlanAction `json:"actions"`
+}
+
+// PlanCluster compares config with the cluster as it is, nil if it doesn't exist yet, and its
+// addons. The version is upgraded before scaling so new nodes start on it, and addons are
+// enabled last. Addons enabled on the cluster but missing from config are left alone, as are
+// settings that can't be changed after creation. A version the cluster can't be upgraded to is
+// an error.
+func PlanCluster(config *ClusterConfig, cluster *Cluster, addons []Addon) (*Plan, error) {
+	plan := &Plan{Cluster: config.Name, Actions: []PlanAction{}}
+	if cluster == nil {
+		to := fmt.Sprintf("%s (%d nodes", config.Name, config.NodeCount)
+		if config.Version != "" {
+			to += ", " + config.Version
+		}
+		to += ")"
+		plan.Actions = append(plan.Actions, PlanAction{Type: PlanCreate, To: to})
+		for _, addon := range config.Addons {
+			plan.Actions = append(plan.Actions, PlanAction{Type: PlanEnableAddon, To: addon})
+		}
+		return plan, nil
+	}
+
+	if config.Version != "" && !SameKubeVersion(cluster.Version, config.Version) {
+		if err := ValidateUpgrade(cluster.Version, config.Version, nil); err != nil {
+			return nil, err
+		}
+		plan.Actions = append(plan.Actions, PlanAction{Type: PlanUpgrade, From: cluster.Version, To: config.Version})
+	}
+	if config.NodeCount > 0 && config.NodeCount != cluster.NodeCount {
+		plan.Actions = append(plan.Actions, PlanAction{Type: PlanScale,
+			From: strconv.Itoa(cluster.NodeCount), To: strconv.Itoa(config.NodeCount)})
+	}
+	for _, addon := range config.Addons {
+		enabled := slices.ContainsFunc(addons, func(a Addon) bool { return a.Name == addon && a.Enabled })
+		if !enabled {
+			plan.Actions = append(plan.Actions, PlanAction{Type: PlanEnableAddon, To: addon})
+		}
+	}
+	return plan, nil
+}
@@ -0,0 +1,76 @@
+package providers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlanCluster(t *testing.T) {
+	existing := &Cluster{Name: "dev", NodeCount: 1, Version: "v1.30.2"}
+	addons := []Addon{{Name: "dashboard", Enabled: true}, {Name: "ingress"}}
+
+	tests := []struct {
+		name    string
+		config  *ClusterConfig
+		cluster *Cluster
+		want    []PlanAction
+		wantErr string
+	}{
+		{
+			name:    "create",
+			config:  &ClusterConfig{Name: "dev", NodeCount: 2, Version: "v1.31.0", Addons: []string{"ingress"}},
+			cluster: nil,
+			want: []PlanAction{
+				{Type: PlanCreate, To: "dev (2 nodes, v1.31.0)"},
+				{Type: PlanEnableAddon, To: "ingress"},
+			},
+		},
+		{
+			name:    "up to date",
+			config:  &ClusterConfig{Name: "dev", NodeCount: 1, Version: "1.30.2", Addons: []string{"dashboard"}},
+			cluster: existing,
+			want:    []PlanAction{},
+		},
+		{
+			name:    "unset fields are left alone",
+			config:  &ClusterConfig{Name: "dev"},
+			cluster: existing,
+			want:    []PlanAction{},
+		},
+		{
+			name:    "upgrade before scale before addons",
+			config:  &ClusterConfig{Name: "dev", NodeCount: 3, Version: "v1.31.0", Addons: []string{"ingress", "dashboard"}},
+			cluster: existing,
+			want: []PlanAction{
+				{Type: PlanUpgrade, From: "v1.30.2", To: "v1.31.0"},
+				{Type: PlanScale, From: "1", To: "3"},
+				{Type: PlanEnableAddon, To: "ingress"},
+			},
+		},
+		{
+			name:    "downgrade",
+			config:  &ClusterConfig{Name: "dev", Version: "v1.29.0"},
+			cluster: existing,
+			wantErr: "cannot downgrade",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := PlanCluster(tt.config, tt.cluster, addons)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("PlanCluster() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PlanCluster() error = %v", err)
+			}
+			if !reflect.DeepEqual(plan.Actions, tt.want) {
+				t.Errorf("PlanCluster() actions = %v, want %v", plan.Actions, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+const defaultMinikubeDiskMB = 20000
+
+// HostResources describes the capacity available to local clusters on this machine
+type HostResources struct {
+	CPUs       int    `json:"cpus"`
+	MemoryMB   int64  `json:"memoryMB"`
+	DiskFreeMB int64  `json:"diskFreeMB"`
+	Driver     string `json:"driver,omitempty"`
+}
+
+// Preflight checks that the requested CPU, memory and disk fit within the host and driver limits,
+// and that the API server port is free. When autoFit is true, oversized requests are clamped in
+// place instead of failing.
+func (l *LocalProvider) Preflight(ctx context.Context, config *ClusterConfig, autoFit bool) error {
+	if config.NetworkConfig != nil && config.NetworkConfig.APIServerPort > 0 {
+		if err := checkPortAvailable(config.NetworkConfig.APIServerPort); err != nil {
+			return fmt.Errorf("API server %w; choose another with --api-server-port or use --api-server-port auto", err)
+		}
+	}
+	host := l.detectHostResources(ctx)
+	return checkHostResources(ctx, config, host, autoFit)
+}
+
+// checkPortAvailable returns an error if port can't be bound on the loopback interface
+func checkPortAvailable(port int) error {
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("port %d is already in use on this host", port)
+	}
+	return listener.Close()
+}
+
+func checkHostResources(ctx context.Context, config *ClusterConfig, host *HostResources, autoFit bool) error {
+	limits := &ResourceLimits{}
+	if config.ResourceConfig != nil && config.ResourceConfig.Limits != nil {
+		limits = config.ResourceConfig.Limits
+	}
+
+	nodes := config.NodeCount
+	if nodes < 1 {
+		nodes = 1
+	}
+
+	if limits.CPU != "" && host.CPUs > 0 {
+		cpus, err := strconv.ParseFloat(limits.CPU, 64)
+		if err != nil {
+			return fmt.Errorf("invalid CPU limit: %s", limits.CPU)
+		}
+		maxPerNode := host.CPUs / nodes
+		if cpus*float64(nodes) > float64(host.CPUs) {
+			if !autoFit || maxPerNode < 1 {
+				return fmt.Errorf("requested %s CPUs x %d nodes exceeds the %d CPUs available on this host (%s); lower --cpu-limit to %d or use --auto-fit",
+					limits.CPU, nodes, host.CPUs, host.describe(), maxPerNode)
+			}
+			progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "preflight", Status: progress.StatusInfo,
+				Message: fmt.Sprintf("Clamping CPU limit from %s to %d to fit host resources", limits.CPU, maxPerNode)})
+			limits.CPU = strconv.Itoa(maxPerNode)
+		}
+	}
+
+	if limits.Memory != "" && host.MemoryMB > 0 {
+		memoryMB, err := parseMemoryMB(limits.Memory)
+		if err != nil {
+			return fmt.Errorf("invalid memory limit: %w", err)
+		}
+		maxPerNode := host.MemoryMB / int64(nodes)
+		if memoryMB*int64(nodes) > host.MemoryMB {
+			if !autoFit || maxPerNode < 1 {
+				return fmt.Errorf("requested %s memory x %d nodes exceeds the %dMB available on this host (%s); lower --memory-limit to %dmb or use --auto-fit",
+					limits.Memory, nodes, host.MemoryMB, host.describe(), maxPerNode)
+			}
+			progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "preflight", Status: progress.StatusInfo,
+				Message: fmt.Sprintf("Clamping memory limit from %s to %dmb to fit host resources", limits.Memory, maxPerNode)})
+			limits.Memory = fmt.Sprintf("%dmb", maxPerNode)
+		}
+	}
+
+	diskMB := int64(defaultMinikubeDiskMB)
+	if config.DiskSize != "" {
+		if size, err := parseMemoryMB(config.DiskSize); err == nil {
+			diskMB = size
+		}
+	}
+	if host.DiskFreeMB > 0 && diskMB*int64(nodes) > host.DiskFreeMB {
+		return fmt.Errorf("%d nodes need about %dMB of disk but only %dMB is free; free up space, reduce --nodes or lower --disk-size",
+			nodes, diskMB*int64(nodes), host.DiskFreeMB)
+	}
+
+	return nil
+}
+
+func (h *HostResources) describe() string {
+	if h.Driver != "" {
+		return "driver " + h.Driver
+	}
+	return runtime.GOOS
+}
+
+// detectHostResources inspects the host and, for the docker driver, the docker daemon limits
+func (l *LocalProvider) detectHostResources(ctx context.Context) *HostResources {
+	host := &HostResources{
+		CPUs:       runtime.NumCPU(),
+		MemoryMB:   hostMemoryMB(ctx),
+		DiskFreeMB: diskFreeMB(ctx, minikubeHome()),
+	}
+
+	output, err := subprocess.CommandContext(ctx, "minikube", "config", "get", "driver").Output()
+	if err == nil {
+		host.Driver = strings.TrimSpace(string(output))
+	}
+
+	if host.Driver == "" || host.Driver == "docker" {
+		output, err := subprocess.CommandContext(ctx, "docker", "info", "--format", "{{.NCPU}} {{.MemTotal}}").Output()
+		if err == nil {
+			fields := strings.Fields(string(output))
+			if len(fields) == 2 {
+				if cpus, err := strconv.Atoi(fields[0]); err == nil && cpus > 0 && cpus < host.CPUs {
+					host.CPUs = cpus
+				}
+				if memBytes, err := strconv.ParseInt(fields[1], 10, 64); err == nil && memBytes > 0 {
+					if memMB := memBytes / (1024 * 1024); host.MemoryMB == 0 || memMB < host.MemoryMB {
+						host.MemoryMB = memMB
+					}
+				}
+				host.Driver = "docker"
+			}
+		}
+	}
+
+	return host
+}
+
+func hostMemoryMB(ctx context.Context) int64 {
+	switch runtime.GOOS {
+	case "linux":
+		data, err := os.ReadFile("/proc/meminfo")
+		if err != nil {
+			return 0
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) >= 2 && fields[0] == "MemTotal:" {
+				if kb, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+					return kb / 1024
+				}
+			}
+		}
+	case "darwin":
+		output, err := subprocess.CommandContext(ctx, "sysctl", "-n", "hw.memsize").Output()
+		if err != nil {
+			return 0
+		}
+		if memBytes, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64); err == nil {
+			return memBytes / (1024 * 1024)
+		}
+	}
+	return 0
+}
+
+func diskFreeMB(ctx context.Context, path string) int64 {
+	for path != "" {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			break
+		}
+		path = parent
+	}
+
+	output, err := subprocess.CommandContext(ctx, "df", "-Pk", path).Output()
+	if err != nil {
+		return 0
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) < 2 {
+		return 0
+	}
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 4 {
+		return 0
+	}
+	availKB, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return availKB / 1024
+}
+
+func minikubeHome() string {
+	if home := os.Getenv("MINIKUBE_HOME"); home != "" {
+		return home
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return os.TempDir()
+	}
+	return filepath.Join(home, ".minikube")
+}
+
+// parseMemoryMB converts minikube and Kubernetes style memory sizes (8Gi, 4096mb, 8g, 2048) to megabytes
+func parseMemoryMB(value string) (int64, error) {
+	s := strings.ToLower(strings.TrimSpace(value))
+	units := []struct {
+		suffix     string
+		multiplier float64
+	}{
+		{"gib", 1024}, {"gi", 1024}, {"gb", 1024}, {"g", 1024},
+		{"mib", 1}, {"mi", 1}, {"mb", 1}, {"m", 1},
+		{"kib", 1.0 / 1024}, {"ki", 1.0 / 1024}, {"kb", 1.0 / 1024}, {"k", 1.0 / 1024},
+	}
+
+	multiplier := 1.0
+	for _, unit := range units {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSuffix(s, unit.suffix)
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("unrecognized memory size: %s", value)
+	}
+	return int64(math.Round(n * multiplier)), nil
+}
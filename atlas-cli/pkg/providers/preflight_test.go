@@ -0,0 +1,137 @@
+package providers
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestParseMemoryMB(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "8Gi", want: 8192},
+		{input: "4096Mi", want: 4096},
+		{input: "4096mb", want: 4096},
+		{input: "2g", want: 2048},
+		{input: "2048", want: 2048},
+		{input: "1.5GB", want: 1536},
+		{input: "lots", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseMemoryMB(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseMemoryMB(%q) expected error but got none", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseMemoryMB(%q) unexpected error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseMemoryMB(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckHostResources(t *testing.T) {
+	host := &HostResources{CPUs: 8, MemoryMB: 16384, DiskFreeMB: 100000}
+
+	tests := []struct {
+		name        string
+		config      *ClusterConfig
+		autoFit     bool
+		wantErr     bool
+		errContains string
+		wantCPU     string
+		wantMemory  string
+	}{
+		{
+			name:   "no limits",
+			config: &ClusterConfig{Name: "test-cluster", NodeCount: 1},
+		},
+		{
+			name: "fits on host",
+			config: &ClusterConfig{Name: "test-cluster", NodeCount: 2, ResourceConfig: &ResourceConfig{
+				Limits: &ResourceLimits{CPU: "4", Memory: "8Gi"},
+			}},
+			wantCPU:    "4",
+			wantMemory: "8Gi",
+		},
+		{
+			name: "too many cpus",
+			config: &ClusterConfig{Name: "test-cluster", NodeCount: 2, ResourceConfig: &ResourceConfig{
+				Limits: &ResourceLimits{CPU: "6"},
+			}},
+			wantErr:     true,
+			errContains: "--auto-fit",
+		},
+		{
+			name: "too much memory clamped",
+			config: &ClusterConfig{Name: "test-cluster", NodeCount: 2, ResourceConfig: &ResourceConfig{
+				Limits: &ResourceLimits{CPU: "6", Memory: "12Gi"},
+			}},
+			autoFit:    true,
+			wantCPU:    "4",
+			wantMemory: "8192mb",
+		},
+		{
+			name:        "not enough disk",
+			config:      &ClusterConfig{Name: "test-cluster", NodeCount: 6},
+			wantErr:     true,
+			errContains: "disk",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkHostResources(context.Background(), tt.config, host, tt.autoFit)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("checkHostResources() expected error but got none")
+					return
+				}
+				if tt.errContains != "" && !contains(err.Error(), tt.errContains) {
+					t.Errorf("checkHostResources() error = %v, want error containing %v", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkHostResources() unexpected error = %v", err)
+			}
+			if tt.config.ResourceConfig == nil {
+				return
+			}
+			limits := tt.config.ResourceConfig.Limits
+			if limits.CPU != tt.wantCPU || limits.Memory != tt.wantMemory {
+				t.Errorf("checkHostResources() limits = %s/%s, want %s/%s", limits.CPU, limits.Memory, tt.wantCPU, tt.wantMemory)
+			}
+		})
+	}
+}
+
+func TestCheckPortAvailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	if err := checkPortAvailable(port); err == nil {
+		t.Errorf("checkPortAvailable(%d) expected error for a port in use", port)
+	}
+
+	config := &ClusterConfig{Name: "dev", NetworkConfig: &NetworkConfig{APIServerPort: port}}
+	err = (&LocalProvider{}).Preflight(context.Background(), config, false)
+	if err == nil || !strings.Contains(err.Error(), "--api-server-port auto") {
+		t.Errorf("Preflight() = %v, want a port conflict error suggesting --api-server-port auto", err)
+	}
+}
@@ -0,0 +1,189 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// presets maps preset name to a config builder per provider. Each call returns a fresh config so
+// callers can modify it freely.
+var presets = map[string]map[string]func() *ClusterConfig{
+	"dev": {
+		"kind": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount: 1,
+				Tags:      map[string]string{"environment": "dev"},
+			}
+		},
+		"k3d": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount: 1,
+				Tags:      map[string]string{"environment": "dev"},
+			}
+		},
+		"local": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount: 1,
+				DiskSize:  "20g",
+				ResourceConfig: &ResourceConfig{
+					Limits: &ResourceLimits{CPU: "2", Memory: "4Gi"},
+				},
+				Tags: map[string]string{"environment": "dev"},
+			}
+		},
+		"aws": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount:    1,
+				InstanceType: "t3.medium",
+				Tags:         map[string]string{"environment": "dev"},
+			}
+		},
+	},
+	"ci": {
+		"kind": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount: 1,
+				Tags:      map[string]string{"environment": "ci"},
+			}
+		},
+		"k3d": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount: 1,
+				Tags:      map[string]string{"environment": "ci"},
+			}
+		},
+		"local": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount: 1,
+				DiskSize:  "20g",
+				ResourceConfig: &ResourceConfig{
+					Limits: &ResourceLimits{CPU: "2", Memory: "2Gi"},
+				},
+				Tags: map[string]string{"environment": "ci"},
+			}
+		},
+		"aws": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount:    2,
+				InstanceType: "t3.large",
+				Tags:         map[string]string{"environment": "ci"},
+			}
+		},
+	},
+	"preview": {
+		"kind": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount: 1,
+				Tags:      map[string]string{"environment": "preview"},
+			}
+		},
+		"k3d": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount: 1,
+				Tags:      map[string]string{"environment": "preview"},
+			}
+		},
+		"local": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount: 1,
+				DiskSize:  "20g",
+				NetworkConfig: &NetworkConfig{
+					Ingress: &IngressConfig{Enabled: true},
+				},
+				ResourceConfig: &ResourceConfig{
+					Limits: &ResourceLimits{CPU: "2", Memory: "4Gi"},
+				},
+				Tags: map[string]string{"environment": "preview"},
+			}
+		},
+		"aws": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount:    1,
+				InstanceType: "t3.large",
+				NetworkConfig: &NetworkConfig{
+					LoadBalancer: &LoadBalancerConfig{Enabled: true},
+				},
+				Tags: map[string]string{"environment": "preview"},
+			}
+		},
+	},
+	"prod-small": {
+		"kind": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount: 3,
+				Tags:      map[string]string{"environment": "prod"},
+			}
+		},
+		"k3d": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount: 3,
+				Tags:      map[string]string{"environment": "prod"},
+			}
+		},
+		"local": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount: 3,
+				DiskSize:  "40g",
+				NetworkConfig: &NetworkConfig{
+					Ingress: &IngressConfig{Enabled: true},
+				},
+				SecurityConfig: &SecurityConfig{
+					RBAC:          &RBACConfig{Enabled: true},
+					NetworkPolicy: &NetworkPolicyConfig{Enabled: true},
+				},
+				ResourceConfig: &ResourceConfig{
+					Limits: &ResourceLimits{CPU: "4", Memory: "8Gi"},
+					Monitoring: &MonitoringConfig{
+						Enabled:    true,
+						Prometheus: &PrometheusConfig{Enabled: true},
+					},
+				},
+				Tags: map[string]string{"environment": "prod"},
+			}
+		},
+		"aws": func() *ClusterConfig {
+			return &ClusterConfig{
+				NodeCount:    3,
+				InstanceType: "m5.large",
+				NetworkConfig: &NetworkConfig{
+					Ingress: &IngressConfig{Enabled: true},
+				},
+				SecurityConfig: &SecurityConfig{
+					RBAC: &RBACConfig{Enabled: true},
+				},
+				ResourceConfig: &ResourceConfig{
+					AutoScaling: &AutoScalingConfig{Enabled: true, MinNodes: 3, MaxNodes: 6},
+					Monitoring: &MonitoringConfig{
+						Enabled:    true,
+						Prometheus: &PrometheusConfig{Enabled: true},
+					},
+				},
+				Tags: map[string]string{"environment": "prod"},
+			}
+		},
+	},
+}
+
+// PresetNames returns the built-in preset names sorted alphabetically
+func PresetNames() []string {
+	var names []string
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PresetConfig returns the full cluster config a preset expands to for the given provider
+func PresetConfig(preset, provider string) (*ClusterConfig, error) {
+	byProvider, exists := presets[preset]
+	if !exists {
+		return nil, fmt.Errorf("unknown preset: %s (available: %s)", preset, strings.Join(PresetNames(), ", "))
+	}
+	build, exists := byProvider[provider]
+	if !exists {
+		return nil, fmt.Errorf("preset %s is not available for provider %s", preset, provider)
+	}
+	return build(), nil
+}
@@ -0,0 +1,32 @@
+package providers
+
+import "testing"
+
+func TestPresetConfig(t *testing.T) {
+	for _, preset := range PresetNames() {
+		for _, provider := range []string{"local", "kind", "k3d", "aws"} {
+			t.Run(preset+"/"+provider, func(t *testing.T) {
+				config, err := PresetConfig(preset, provider)
+				if err != nil {
+					t.Fatalf("PresetConfig() unexpected error = %v", err)
+				}
+				if config.NodeCount < 1 {
+					t.Errorf("preset %s/%s has no nodes", preset, provider)
+				}
+
+				config.NodeCount = 99
+				again, _ := PresetConfig(preset, provider)
+				if again.NodeCount == 99 {
+					t.Error("PresetConfig() should return a fresh config on every call")
+				}
+			})
+		}
+	}
+
+	if _, err := PresetConfig("huge", "local"); err == nil {
+		t.Error("PresetConfig() expected error for unknown preset")
+	}
+	if _, err := PresetConfig("dev", "gcp"); err == nil {
+		t.Error("PresetConfig() expected error for unsupported provider")
+	}
+}
@@ -0,0 +1,258 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// quotaWarnRatio is the share of a quota above which a check warns even if the request fits
+const quotaWarnRatio = 0.9
+
+const (
+	eksClustersQuotaCode     = "L-1194D53C"
+	ec2StandardVCPUQuotaCode = "L-1216C47A"
+)
+
+// QuotaChecker is implemented by providers that can check account limits before provisioning
+type QuotaChecker interface {
+	// CheckCreateQuotas reports the quotas a new cluster with config would consume
+	CheckCreateQuotas(ctx context.Context, config *ClusterConfig) ([]QuotaCheck, error)
+	// CheckScaleQuotas reports the quotas consumed by scaling the named cluster to nodeCount nodes
+	CheckScaleQuotas(ctx context.Context, name string, nodeCount int) ([]QuotaCheck, error)
+}
+
+// QuotaCheck compares a request against a single account quota
+type QuotaCheck struct {
+	Name      string  `json:"name"`
+	Code      string  `json:"code"`
+	Limit     float64 `json:"limit"`
+	Used      float64 `json:"used"`
+	Requested float64 `json:"requested"`
+}
+
+// Exceeded reports whether the request would go over the quota
+func (q QuotaCheck) Exceeded() bool {
+	return q.Used+q.Requested > q.Limit
+}
+
+// NearLimit reports whether the request would leave the quota almost exhausted
+func (q QuotaCheck) NearLimit() bool {
+	return q.Limit > 0 && (q.Used+q.Requested)/q.Limit >= quotaWarnRatio
+}
+
+func (q QuotaCheck) String() string {
+	return fmt.Sprintf("%s: %g used + %g requested of %g allowed", q.Name, q.Used, q.Requested, q.Limit)
+}
+
+// EvaluateQuotas splits quota checks into warnings for requests close to a limit and an error
+// listing every exceeded quota
+func EvaluateQuotas(checks []QuotaCheck) ([]string, error) {
+	var warnings, exceeded []string
+	for _, check := range checks {
+		switch {
+		case check.Exceeded():
+			exceeded = append(exceeded, check.String())
+		case check.NearLimit():
+			warnings = append(warnings, "close to quota: "+check.String())
+		}
+	}
+	if len(exceeded) > 0 {
+		return warnings, fmt.Errorf("request exceeds account quotas: %s", strings.Join(exceeded, "; "))
+	}
+	return warnings, nil
+}
+
+var _ QuotaChecker = (*AWSProvider)(nil)
+
+func (a *AWSProvider) CheckCreateQuotas(ctx context.Context, config *ClusterConfig) ([]QuotaCheck, error) {
+	region := config.Region
+	if region == "" {
+		region = a.region
+	}
+
+	clusterLimit, err := a.getServiceQuota(ctx, "eks", eksClustersQuotaCode, region)
+	if err != nil {
+		return nil, err
+	}
+	clusterCount, err := a.countClusters(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceType := config.InstanceType
+	if instanceType == "" {
+		instanceType = "t3.medium"
+	}
+	vcpuCheck, err := a.vcpuQuotaCheck(ctx, region, config.NodeCount*instanceVCPUs(instanceType))
+	if err != nil {
+		return nil, err
+	}
+
+	return []QuotaCheck{
+		{
+			Name:      "EKS clusters per region",
+			Code:      eksClustersQuotaCode,
+			Limit:     clusterLimit,
+			Used:      float64(clusterCount),
+			Requested: 1,
+		},
+		*vcpuCheck,
+	}, nil
+}
+
+func (a *AWSProvider) CheckScaleQuotas(ctx context.Context, name string, nodeCount int) ([]QuotaCheck, error) {
+	nodeGroups, err := a.listNodeGroups(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node groups: %w", err)
+	}
+	if len(nodeGroups) == 0 {
+		return nil, fmt.Errorf("no node groups found for cluster %s", name)
+	}
+
+	nodeGroup, err := a.describeNodeGroup(ctx, name, nodeGroups[0])
+	if err != nil {
+		return nil, err
+	}
+
+	instanceType := "t3.medium"
+	if len(nodeGroup.InstanceTypes) > 0 {
+		instanceType = nodeGroup.InstanceTypes[0]
+	}
+
+	additionalNodes := nodeCount - nodeGroup.ScalingConfig.DesiredSize
+	if additionalNodes <= 0 {
+		return nil, nil
+	}
+
+	vcpuCheck, err := a.vcpuQuotaCheck(ctx, a.region, additionalNodes*instanceVCPUs(instanceType))
+	if err != nil {
+		return nil, err
+	}
+	return []QuotaCheck{*vcpuCheck}, nil
+}
+
+func (a *AWSProvider) vcpuQuotaCheck(ctx context.Context, region string, requested int) (*QuotaCheck, error) {
+	limit, err := a.getServiceQuota(ctx, "ec2", ec2StandardVCPUQuotaCode, region)
+	if err != nil {
+		return nil, err
+	}
+	used, err := a.countRunningVCPUs(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	return &QuotaCheck{
+		Name:      "EC2 On-Demand standard vCPUs",
+		Code:      ec2StandardVCPUQuotaCode,
+		Limit:     limit,
+		Used:      float64(used),
+		Requested: float64(requested),
+	}, nil
+}
+
+// getServiceQuota returns the applied quota value, falling back to the AWS default when the
+// account has never had the quota adjusted
+func (a *AWSProvider) getServiceQuota(ctx context.Context, serviceCode, quotaCode, region string) (float64, error) {
+	for _, subcommand := range []string{"get-service-quota", "get-aws-default-service-quota"} {
+		cmd := subprocess.CommandContext(ctx, "aws", "service-quotas", subcommand,
+			"--service-code", serviceCode,
+			"--quota-code", quotaCode,
+			"--region", region,
+			"--query", "Quota.Value",
+			"--output", "text")
+
+		if a.profile != "" {
+			cmd.Args = append(cmd.Args, "--profile", a.profile)
+		}
+
+		output, err := cmd.Output()
+		if err != nil {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse quota %s: %w", quotaCode, err)
+		}
+		return value, nil
+	}
+	return 0, fmt.Errorf("failed to query service quota %s/%s", serviceCode, quotaCode)
+}
+
+func (a *AWSProvider) countClusters(ctx context.Context, region string) (int, error) {
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "list-clusters",
+		"--region", region)
+
+	if a.profile != "" {
+		cmd.Args = append(cmd.Args, "--profile", a.profile)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	var result struct {
+		Clusters []string `json:"clusters"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return 0, fmt.Errorf("failed to parse cluster list: %w", err)
+	}
+	return len(result.Clusters), nil
+}
+
+func (a *AWSProvider) countRunningVCPUs(ctx context.Context, region string) (int, error) {
+	cmd := subprocess.CommandContext(ctx, "aws", "ec2", "describe-instances",
+		"--filters", "Name=instance-state-name,Values=pending,running",
+		"--query", "Reservations[].Instances[].CpuOptions",
+		"--region", region,
+		"--output", "json")
+
+	if a.profile != "" {
+		cmd.Args = append(cmd.Args, "--profile", a.profile)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to describe instances: %w", err)
+	}
+
+	var cpuOptions []struct {
+		CoreCount      int `json:"CoreCount"`
+		ThreadsPerCore int `json:"ThreadsPerCore"`
+	}
+	if err := json.Unmarshal(output, &cpuOptions); err != nil {
+		return 0, fmt.Errorf("failed to parse instance list: %w", err)
+	}
+
+	total := 0
+	for _, options := range cpuOptions {
+		threads := options.ThreadsPerCore
+		if threads == 0 {
+			threads = 1
+		}
+		total += options.CoreCount * threads
+	}
+	return total, nil
+}
+
+// instanceVCPUs derives the vCPU count of an EC2 instance type from its size suffix
+func instanceVCPUs(instanceType string) int {
+	_, size, found := strings.Cut(instanceType, ".")
+	if !found {
+		return 2
+	}
+	switch size {
+	case "nano", "micro", "small", "medium", "large":
+		return 2
+	case "xlarge":
+		return 4
+	}
+	if multiplier, err := strconv.Atoi(strings.TrimSuffix(size, "xlarge")); err == nil && strings.HasSuffix(size, "xlarge") {
+		return multiplier * 4
+	}
+	return 2
+}
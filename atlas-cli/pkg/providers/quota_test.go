@@ -0,0 +1,71 @@
+package providers
+
+import "testing"
+
+func TestInstanceVCPUs(t *testing.T) {
+	tests := []struct {
+		instanceType string
+		want         int
+	}{
+		{"t3.micro", 2},
+		{"t3.medium", 2},
+		{"m5.large", 2},
+		{"m5.xlarge", 4},
+		{"c5.2xlarge", 8},
+		{"c5.9xlarge", 36},
+		{"r5.24xlarge", 96},
+		{"unknown", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.instanceType, func(t *testing.T) {
+			if got := instanceVCPUs(tt.instanceType); got != tt.want {
+				t.Errorf("instanceVCPUs(%q) = %d, want %d", tt.instanceType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateQuotas(t *testing.T) {
+	tests := []struct {
+		name         string
+		checks       []QuotaCheck
+		wantErr      bool
+		wantWarnings int
+	}{
+		{
+			name:   "within quota",
+			checks: []QuotaCheck{{Name: "vcpus", Limit: 32, Used: 8, Requested: 4}},
+		},
+		{
+			name:         "close to quota",
+			checks:       []QuotaCheck{{Name: "vcpus", Limit: 32, Used: 24, Requested: 6}},
+			wantWarnings: 1,
+		},
+		{
+			name:         "exactly at quota",
+			checks:       []QuotaCheck{{Name: "clusters", Limit: 100, Used: 99, Requested: 1}},
+			wantWarnings: 1,
+		},
+		{
+			name: "exceeds quota",
+			checks: []QuotaCheck{
+				{Name: "clusters", Limit: 100, Used: 10, Requested: 1},
+				{Name: "vcpus", Limit: 32, Used: 30, Requested: 4},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := EvaluateQuotas(tt.checks)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EvaluateQuotas() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("EvaluateQuotas() warnings = %v, want %d", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// commandRunner runs a CLI and returns its standard output. A command that fails returns an
+// *exec.ExitError carrying its standard error. Providers hold one so tests can answer their
+// commands without the real CLIs.
+type commandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// runCommand runs the CLI under the subprocess watchdog
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return subprocess.CommandContext(ctx, name, args...).Output()
+}
+
+// commandOutput returns everything a command printed, for error messages
+func commandOutput(output []byte, err error) string {
+	text := string(output)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		text += string(exitErr.Stderr)
+	}
+	return strings.TrimSpace(text)
+}
+
+// commandFailedWith reports whether err is a failed command whose standard error contains text
+func commandFailedWith(err error, text string) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && strings.Contains(string(exitErr.Stderr), text)
+}
@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// fakeResponse is one answer of a fakeRunner. A non-empty stderr makes the command fail with an
+// *exec.ExitError carrying it, like a real CLI exiting non-zero.
+type fakeResponse struct {
+	stdout string
+	stderr string
+	err    error
+}
+
+// fakeRunner answers commands by the longest matching command-line prefix and records each call
+// as its command line. A prefix with several responses answers them in turn, repeating the last.
+type fakeRunner struct {
+	mu        sync.Mutex
+	calls     []string
+	responses map[string][]fakeResponse
+}
+
+func newFakeRunner() *fakeRunner {
+	return &fakeRunner{responses: make(map[string][]fakeResponse)}
+}
+
+func (f *fakeRunner) on(prefix string, responses ...fakeResponse) {
+	f.responses[prefix] = responses
+}
+
+func (f *fakeRunner) run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	line := strings.Join(append([]string{name}, args...), " ")
+	f.calls = append(f.calls, line)
+
+	match := ""
+	for prefix := range f.responses {
+		if strings.HasPrefix(line, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+	queue := f.responses[match]
+	if len(queue) == 0 {
+		return nil, nil
+	}
+	response := queue[0]
+	if len(queue) > 1 {
+		f.responses[match] = queue[1:]
+	}
+	switch {
+	case response.err != nil:
+		return []byte(response.stdout), response.err
+	case response.stderr != "":
+		return []byte(response.stdout), &exec.ExitError{Stderr: []byte(response.stderr)}
+	}
+	return []byte(response.stdout), nil
+}
+
+func (f *fakeRunner) callsMatching(prefix string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var matched []string
+	for _, call := range f.calls {
+		if strings.HasPrefix(call, prefix) {
+			matched = append(matched, call)
+		}
+	}
+	return matched
+}
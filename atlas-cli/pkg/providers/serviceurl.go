@@ -0,0 +1,213 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// ServiceURLResolver is implemented by providers that can work out where a Kubernetes service is
+// reachable from outside the cluster
+type ServiceURLResolver interface {
+	// ServiceURLs returns the URLs that reach the service, through its load balancer, node ports
+	// or the ingresses routing to it
+	ServiceURLs(ctx context.Context, clusterName, namespace, service string) ([]string, error)
+}
+
+var _ ServiceURLResolver = (*LocalProvider)(nil)
+var _ ServiceURLResolver = (*KindProvider)(nil)
+var _ ServiceURLResolver = (*K3dProvider)(nil)
+var _ ServiceURLResolver = (*AWSProvider)(nil)
+
+// ServiceURLs asks minikube for the service's URLs, which accounts for the driver's networking.
+// On drivers that need a tunnel minikube keeps forwarding until it is killed; subprocess registers
+// it for cleanup, so an interrupt doesn't leave the forward running.
+func (l *LocalProvider) ServiceURLs(ctx context.Context, clusterName, namespace, service string) ([]string, error) {
+	output, err := subprocess.CommandContext(ctx, "minikube", "service", service, "-n", namespace, "-p", clusterName, "--url").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get URL for service %s/%s: %w", namespace, service, err)
+	}
+	var urls []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://") {
+			urls = append(urls, line)
+		}
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("service %s/%s has no URL; is it a NodePort or LoadBalancer service?", namespace, service)
+	}
+	return urls, nil
+}
+
+func (k *KindProvider) ServiceURLs(ctx context.Context, clusterName, namespace, service string) ([]string, error) {
+	return kubeServiceURLs(ctx, []string{"kubectl", "--context", "kind-" + clusterName}, namespace, service)
+}
+
+func (k *K3dProvider) ServiceURLs(ctx context.Context, clusterName, namespace, service string) ([]string, error) {
+	return kubeServiceURLs(ctx, []string{"kubectl", "--context", "k3d-" + clusterName}, namespace, service)
+}
+
+// ServiceURLs resolves the service's load balancer hostname and ingresses on EKS
+func (a *AWSProvider) ServiceURLs(ctx context.Context, clusterName, namespace, service string) ([]string, error) {
+	kubeContext, err := a.updateKubeConfig(ctx, clusterName, a.region)
+	if err != nil {
+		return nil, err
+	}
+	return kubeServiceURLs(ctx, []string{"kubectl", "--context", kubeContext}, namespace, service)
+}
+
+// kubeServiceURLs reads the service, the ingresses in its namespace and the nodes with the
+// kubectl command line kubectl and derives the service's URLs from them
+func kubeServiceURLs(ctx context.Context, kubectl []string, namespace, service string) ([]string, error) {
+	get := func(v any, args ...string) error {
+		args = append(append(slices.Clone(kubectl[1:]), "get"), append(args, "-o", "json")...)
+		output, err := subprocess.CommandContext(ctx, kubectl[0], args...).Output()
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(output, v)
+	}
+
+	var svc corev1.Service
+	if err := get(&svc, "service", service, "-n", namespace); err != nil {
+		return nil, fmt.Errorf("failed to get service %s/%s: %w", namespace, service, err)
+	}
+	var ingresses networkingv1.IngressList
+	if err := get(&ingresses, "ingress", "-n", namespace); err != nil {
+		ingresses.Items = nil
+	}
+	var nodes corev1.NodeList
+	if svc.Spec.Type == corev1.ServiceTypeNodePort || svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		if err := get(&nodes, "nodes"); err != nil {
+			return nil, fmt.Errorf("failed to get nodes: %w", err)
+		}
+	}
+
+	urls := serviceURLs(&svc, ingresses.Items, nodes.Items)
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("service %s/%s is not exposed outside the cluster; use a NodePort or LoadBalancer service or an ingress", namespace, service)
+	}
+	return urls, nil
+}
+
+// serviceURLs derives a service's URLs: its load balancer addresses, or node ports on the first
+// node while the load balancer has none, followed by the ingress rules routing to it
+func serviceURLs(svc *corev1.Service, ingresses []networkingv1.Ingress, nodes []corev1.Node) []string {
+	var urls []string
+	add := func(url string) {
+		if !slices.Contains(urls, url) {
+			urls = append(urls, url)
+		}
+	}
+
+	var lbHosts []string
+	for _, lb := range svc.Status.LoadBalancer.Ingress {
+		if lb.Hostname != "" {
+			lbHosts = append(lbHosts, lb.Hostname)
+		} else if lb.IP != "" {
+			lbHosts = append(lbHosts, lb.IP)
+		}
+	}
+	switch {
+	case svc.Spec.Type == corev1.ServiceTypeLoadBalancer && len(lbHosts) > 0:
+		for _, host := range lbHosts {
+			for _, port := range svc.Spec.Ports {
+				add(hostURL(portScheme(port.Name, port.Port), host, port.Port))
+			}
+		}
+	case svc.Spec.Type == corev1.ServiceTypeNodePort || svc.Spec.Type == corev1.ServiceTypeLoadBalancer:
+		if address := nodeAddress(nodes); address != "" {
+			for _, port := range svc.Spec.Ports {
+				if port.NodePort != 0 {
+					add(hostURL(portScheme(port.Name, port.Port), address, port.NodePort))
+				}
+			}
+		}
+	}
+
+	for _, ingress := range ingresses {
+		tlsHosts := make(map[string]bool)
+		for _, tls := range ingress.Spec.TLS {
+			for _, host := range tls.Hosts {
+				tlsHosts[host] = true
+			}
+		}
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			host := rule.Host
+			if host == "" {
+				host = ingressAddress(&ingress)
+			}
+			if host == "" {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service == nil || path.Backend.Service.Name != svc.Name {
+					continue
+				}
+				scheme := "http"
+				if tlsHosts[rule.Host] {
+					scheme = "https"
+				}
+				add(scheme + "://" + host + path.Path)
+			}
+		}
+	}
+	return urls
+}
+
+// portScheme guesses whether a service port speaks HTTPS from its name or number
+func portScheme(name string, port int32) string {
+	if port == 443 || port == 8443 || strings.Contains(name, "https") {
+		return "https"
+	}
+	return "http"
+}
+
+// hostURL builds scheme://host:port, leaving out the scheme's default port
+func hostURL(scheme, host string, port int32) string {
+	if (scheme == "http" && port == 80) || (scheme == "https" && port == 443) {
+		return scheme + "://" + host
+	}
+	return scheme + "://" + host + ":" + strconv.Itoa(int(port))
+}
+
+// nodeAddress returns the first node's external address, or its internal one
+func nodeAddress(nodes []corev1.Node) string {
+	if len(nodes) == 0 {
+		return ""
+	}
+	var internal string
+	for _, address := range nodes[0].Status.Addresses {
+		switch address.Type {
+		case corev1.NodeExternalIP:
+			return address.Address
+		case corev1.NodeInternalIP:
+			if internal == "" {
+				internal = address.Address
+			}
+		}
+	}
+	return internal
+}
+
+func ingressAddress(ingress *networkingv1.Ingress) string {
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if lb.Hostname != "" {
+			return lb.Hostname
+		}
+		if lb.IP != "" {
+			return lb.IP
+		}
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package providers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServiceURLs(t *testing.T) {
+	node := corev1.Node{Status: corev1.NodeStatus{Addresses: []corev1.NodeAddress{
+		{Type: corev1.NodeHostName, Address: "dev-control-plane"},
+		{Type: corev1.NodeInternalIP, Address: "172.18.0.2"},
+	}}}
+	ports := []corev1.ServicePort{{Name: "http", Port: 80, NodePort: 30080}, {Name: "https", Port: 443, NodePort: 30443}}
+	ingress := networkingv1.Ingress{
+		Spec: networkingv1.IngressSpec{
+			TLS: []networkingv1.IngressTLS{{Hosts: []string{"web.example.com"}}},
+			Rules: []networkingv1.IngressRule{
+				{Host: "web.example.com", IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{
+					{Path: "/", Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "web"}}},
+					{Path: "/api", Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "api"}}},
+				}}}},
+				{IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{
+					{Path: "/web", Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "web"}}},
+				}}}},
+			},
+		},
+		Status: networkingv1.IngressStatus{LoadBalancer: networkingv1.IngressLoadBalancerStatus{Ingress: []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.9"}}}},
+	}
+
+	tests := []struct {
+		name      string
+		service   corev1.Service
+		ingresses []networkingv1.Ingress
+		want      []string
+	}{
+		{
+			name: "load balancer hostname",
+			service: corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "web"},
+				Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, Ports: []corev1.ServicePort{{Port: 8080, NodePort: 31000}}},
+				Status:     corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{Hostname: "abc.elb.amazonaws.com"}}}},
+			},
+			want: []string{"http://abc.elb.amazonaws.com:8080"},
+		},
+		{
+			name: "pending load balancer falls back to node ports",
+			service: corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "web"},
+				Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, Ports: ports},
+			},
+			want: []string{"http://172.18.0.2:30080", "https://172.18.0.2:30443"},
+		},
+		{
+			name: "cluster IP with ingresses",
+			service: corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "web"},
+				Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, Ports: ports},
+			},
+			ingresses: []networkingv1.Ingress{ingress},
+			want:      []string{"https://web.example.com/", "http://10.0.0.9/web"},
+		},
+		{
+			name: "cluster IP without ingresses",
+			service: corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "web"},
+				Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, Ports: ports},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := serviceURLs(&tt.service, tt.ingresses, []corev1.Node{node})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("serviceURLs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
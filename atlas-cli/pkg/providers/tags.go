@@ -0,0 +1,192 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"sort"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+const defaultTagPrefix = "atlas:"
+
+// TaggingPolicy controls the Atlas metadata propagated as tags to provisioned cloud resources
+type TaggingPolicy struct {
+	// Disabled turns off Atlas metadata tags; user tags from ClusterConfig.Tags are still applied
+	Disabled  bool   `yaml:"disabled,omitempty"`
+	Prefix    string `yaml:"prefix,omitempty"`
+	Owner     string `yaml:"owner,omitempty"`
+	Workspace string `yaml:"workspace,omitempty"`
+	// Required lists tag keys that must be present after metadata and user tags are merged
+	Required []string `yaml:"required,omitempty"`
+}
+
+// ResolveTags merges Atlas metadata tags (cluster name, owner, workspace, created-by) with the
+// user tags in config and enforces the tagging policy's required keys. User tags take precedence.
+func ResolveTags(config *ClusterConfig) (map[string]string, error) {
+	policy := config.TaggingPolicy
+	if policy == nil {
+		policy = &TaggingPolicy{}
+	}
+
+	tags := make(map[string]string)
+	if !policy.Disabled {
+		prefix := policy.Prefix
+		if prefix == "" {
+			prefix = defaultTagPrefix
+		}
+
+		owner := policy.Owner
+		if owner == "" {
+			owner = currentUser()
+		}
+
+		tags[prefix+"cluster-name"] = config.Name
+		tags[prefix+"created-by"] = "atlas-cli"
+		if owner != "" {
+			tags[prefix+"owner"] = owner
+		}
+		if policy.Workspace != "" {
+			tags[prefix+"workspace"] = policy.Workspace
+		}
+	}
+
+	for key, value := range config.Tags {
+		tags[key] = value
+	}
+
+	var missing []string
+	for _, key := range policy.Required {
+		if tags[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("tagging policy requires tags: %s", strings.Join(missing, ", "))
+	}
+
+	return tags, nil
+}
+
+// formatTags renders tags as sorted key=value pairs joined by sep
+func formatTags(tags map[string]string, sep string) string {
+	var pairs []string
+	for key, value := range tags {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, sep)
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+type asgTag struct {
+	ResourceId        string
+	ResourceType      string
+	Key               string
+	Value             string
+	PropagateAtLaunch bool
+}
+
+type ec2Tag struct {
+	Key   string
+	Value string
+}
+
+// propagateInstanceTags tags the node group's auto scaling groups so instances launched later
+// inherit the tags, and tags the instances that are already running
+func (a *AWSProvider) propagateInstanceTags(ctx context.Context, clusterName, nodeGroupName, region string, tags map[string]string) error {
+	cmd := subprocess.CommandContext(ctx, "aws", "eks", "describe-nodegroup",
+		"--cluster-name", clusterName,
+		"--nodegroup-name", nodeGroupName,
+		"--region", region,
+		"--query", "nodegroup.resources.autoScalingGroups[].name",
+		"--output", "json")
+
+	if a.profile != "" {
+		cmd.Args = append(cmd.Args, "--profile", a.profile)
+	}
+
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to describe node group: %w", err)
+	}
+
+	var groups []string
+	if err := json.Unmarshal(output, &groups); err != nil {
+		return fmt.Errorf("failed to parse auto scaling groups: %w", err)
+	}
+
+	var ec2Tags []ec2Tag
+	for key, value := range tags {
+		ec2Tags = append(ec2Tags, ec2Tag{Key: key, Value: value})
+	}
+	ec2TagsJSON, _ := json.Marshal(ec2Tags)
+
+	for _, group := range groups {
+		var asgTags []asgTag
+		for key, value := range tags {
+			asgTags = append(asgTags, asgTag{ResourceId: group, ResourceType: "auto-scaling-group", Key: key, Value: value, PropagateAtLaunch: true})
+		}
+		asgTagsJSON, _ := json.Marshal(asgTags)
+
+		cmd := subprocess.CommandContext(ctx, "aws", "autoscaling", "create-or-update-tags",
+			"--tags", string(asgTagsJSON),
+			"--region", region)
+
+		if a.profile != "" {
+			cmd.Args = append(cmd.Args, "--profile", a.profile)
+		}
+
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to tag auto scaling group %s: %s", group, string(output))
+		}
+
+		cmd = subprocess.CommandContext(ctx, "aws", "autoscaling", "describe-auto-scaling-groups",
+			"--auto-scaling-group-names", group,
+			"--query", "AutoScalingGroups[].Instances[].InstanceId",
+			"--region", region,
+			"--output", "json")
+
+		if a.profile != "" {
+			cmd.Args = append(cmd.Args, "--profile", a.profile)
+		}
+
+		output, err := cmd.Output()
+		if err != nil {
+			return fmt.Errorf("failed to list instances of %s: %w", group, err)
+		}
+
+		var instanceIDs []string
+		if err := json.Unmarshal(output, &instanceIDs); err != nil {
+			return fmt.Errorf("failed to parse instance list: %w", err)
+		}
+		if len(instanceIDs) == 0 {
+			continue
+		}
+
+		cmd = subprocess.CommandContext(ctx, "aws", "ec2", "create-tags",
+			"--resources")
+		cmd.Args = append(cmd.Args, instanceIDs...)
+		cmd.Args = append(cmd.Args, "--tags", string(ec2TagsJSON), "--region", region)
+
+		if a.profile != "" {
+			cmd.Args = append(cmd.Args, "--profile", a.profile)
+		}
+
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to tag instances: %s", string(output))
+		}
+	}
+
+	return nil
+}
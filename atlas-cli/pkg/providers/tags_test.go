@@ -0,0 +1,92 @@
+package providers
+
+import "testing"
+
+func TestResolveTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *ClusterConfig
+		want        map[string]string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "metadata tags",
+			config: &ClusterConfig{
+				Name:          "dev",
+				TaggingPolicy: &TaggingPolicy{Owner: "alice", Workspace: "payments"},
+			},
+			want: map[string]string{
+				"atlas:cluster-name": "dev",
+				"atlas:created-by":   "atlas-cli",
+				"atlas:owner":        "alice",
+				"atlas:workspace":    "payments",
+			},
+		},
+		{
+			name: "custom prefix and user tags override",
+			config: &ClusterConfig{
+				Name:          "dev",
+				Tags:          map[string]string{"cost-center": "42", "acme/owner": "team-a"},
+				TaggingPolicy: &TaggingPolicy{Prefix: "acme/", Owner: "alice"},
+			},
+			want: map[string]string{
+				"acme/cluster-name": "dev",
+				"acme/created-by":   "atlas-cli",
+				"acme/owner":        "team-a",
+				"cost-center":       "42",
+			},
+		},
+		{
+			name: "disabled keeps user tags",
+			config: &ClusterConfig{
+				Name:          "dev",
+				Tags:          map[string]string{"env": "dev"},
+				TaggingPolicy: &TaggingPolicy{Disabled: true},
+			},
+			want: map[string]string{"env": "dev"},
+		},
+		{
+			name: "missing required tag",
+			config: &ClusterConfig{
+				Name:          "dev",
+				TaggingPolicy: &TaggingPolicy{Owner: "alice", Required: []string{"cost-center", "atlas:owner"}},
+			},
+			wantErr:     true,
+			errContains: "cost-center",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveTags(tt.config)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ResolveTags() expected error but got none")
+				}
+				if !contains(err.Error(), tt.errContains) {
+					t.Errorf("ResolveTags() error = %v, want it to mention %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveTags() unexpected error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ResolveTags() = %v, want %v", got, tt.want)
+			}
+			for key, value := range tt.want {
+				if got[key] != value {
+					t.Errorf("ResolveTags()[%q] = %q, want %q", key, got[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatTags(t *testing.T) {
+	got := formatTags(map[string]string{"b": "2", "a": "1"}, ",")
+	if got != "a=1,b=2" {
+		t.Errorf("formatTags() = %q, want %q", got, "a=1,b=2")
+	}
+}
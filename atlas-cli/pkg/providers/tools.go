@@ -0,0 +1,187 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// DefaultToolCacheTTL is how long a detected CLI version is reused by later runs
+const DefaultToolCacheTTL = 24 * time.Hour
+
+// toolVersionArgs are the arguments that make each CLI print its version
+var toolVersionArgs = map[string][]string{
+	"minikube": {"version", "--short"},
+	"aws":      {"--version"},
+	"kubectl":  {"version", "--client"},
+	"helm":     {"version", "--short"},
+	"velero":   {"version", "--client-only"},
+	"kind":     {"version"},
+	"k3d":      {"version"},
+}
+
+// Tool is an external CLI found on this machine
+type Tool struct {
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	Version    string    `json:"version"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// ToolCache remembers which CLIs are installed and their versions, in memory for the life of
+// the process and on disk for the TTL, so validation doesn't shell out on every call
+type ToolCache struct {
+	mu       sync.Mutex
+	path     string
+	ttl      time.Duration
+	entries  map[string]Tool
+	detected map[string]bool
+	lookPath func(name string) (string, error)
+	version  func(ctx context.Context, name string) (string, error)
+}
+
+// DefaultToolCachePath returns the location of the shared tool detection cache
+func DefaultToolCachePath() (string, error) {
+	return store.Path("cache", "tools.json")
+}
+
+// NewToolCache loads the cache stored at path; a missing or unreadable file starts empty. With
+// an empty path nothing is kept between runs.
+func NewToolCache(path string, ttl time.Duration) *ToolCache {
+	c := &ToolCache{
+		path:     path,
+		ttl:      ttl,
+		entries:  make(map[string]Tool),
+		detected: make(map[string]bool),
+		lookPath: exec.LookPath,
+		version:  runToolVersion,
+	}
+	if path != "" {
+		store.LoadJSON(path, &c.entries)
+	}
+	return c
+}
+
+// Detect returns the named CLI and its version. The version command only runs when the binary
+// on PATH has no fresh cached result, so repeated validations cost a PATH lookup at most.
+func (c *ToolCache) Detect(ctx context.Context, name string) (Tool, error) {
+	// Replayed sessions answer the version command from the transcript, whatever is installed
+	if subprocess.Replaying() {
+		version, err := c.version(ctx, name)
+		if err != nil {
+			return Tool{}, fmt.Errorf("%s is not installed or not in PATH", name)
+		}
+		return Tool{Name: name, Version: version, DetectedAt: time.Now()}, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if tool, ok := c.entries[name]; ok && c.detected[name] {
+		return tool, nil
+	}
+
+	path, err := c.lookPath(name)
+	if err != nil {
+		return Tool{}, fmt.Errorf("%s is not installed or not in PATH", name)
+	}
+	if tool, ok := c.entries[name]; ok && tool.Path == path && time.Since(tool.DetectedAt) <= c.ttl {
+		c.detected[name] = true
+		return tool, nil
+	}
+
+	version, err := c.version(ctx, name)
+	if err != nil {
+		return Tool{}, fmt.Errorf("%s is not installed or not in PATH", name)
+	}
+	tool := Tool{Name: name, Path: path, Version: version, DetectedAt: time.Now()}
+	c.entries[name] = tool
+	c.detected[name] = true
+	c.save()
+	return tool, nil
+}
+
+// Detected returns the tools looked up by this process, sorted by name
+func (c *ToolCache) Detected() []Tool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tools := make([]Tool, 0, len(c.detected))
+	for name := range c.detected {
+		tools = append(tools, c.entries[name])
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+// save writes unexpired entries back to disk. A failed write only costs a version check on the
+// next run, so errors are ignored.
+func (c *ToolCache) save() {
+	if c.path == "" {
+		return
+	}
+	for name, tool := range c.entries {
+		if time.Since(tool.DetectedAt) > c.ttl {
+			delete(c.entries, name)
+		}
+	}
+
+	store.SaveJSON(c.path, c.entries)
+}
+
+var defaultToolCache = sync.OnceValue(func() *ToolCache {
+	// without a home directory tools are detected afresh on every run
+	path, _ := DefaultToolCachePath()
+	return NewToolCache(path, DefaultToolCacheTTL)
+})
+
+// DetectTool looks up name in the process-wide tool cache
+func DetectTool(ctx context.Context, name string) (Tool, error) {
+	return defaultToolCache().Detect(ctx, name)
+}
+
+// DetectedTools returns every tool this process has looked up
+func DetectedTools() []Tool {
+	return defaultToolCache().Detected()
+}
+
+func runToolVersion(ctx context.Context, name string) (string, error) {
+	args, ok := toolVersionArgs[name]
+	if !ok {
+		args = []string{"--version"}
+	}
+	output, err := subprocess.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return parseToolVersion(string(output)), nil
+}
+
+// parseToolVersion pulls the version out of output such as "v1.33.1", "Client Version: v1.30.2"
+// or "aws-cli/2.15.0 Python/3.11.6 Linux/6.5.0"
+func parseToolVersion(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if idx := strings.Index(line, ": "); idx >= 0 {
+			line = line[idx+2:]
+		}
+		// the version is the first field that starts with a digit, e.g. "kind v0.23.0 go1.22.2 linux/amd64"
+		// or "k3d version v5.6.0"
+		for _, version := range strings.Fields(line) {
+			if idx := strings.LastIndex(version, "/"); idx >= 0 {
+				version = version[idx+1:]
+			}
+			if digits := strings.TrimPrefix(version, "v"); digits != "" && digits[0] >= '0' && digits[0] <= '9' {
+				return version
+			}
+		}
+	}
+	return "unknown"
+}
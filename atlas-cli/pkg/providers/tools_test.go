@@ -0,0 +1,85 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestToolCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tools.json")
+	binary := "/usr/local/bin/minikube"
+	runs := 0
+
+	newCache := func(ttl time.Duration) *ToolCache {
+		cache := NewToolCache(path, ttl)
+		cache.lookPath = func(name string) (string, error) {
+			if name != "minikube" {
+				return "", errors.New("not found")
+			}
+			return binary, nil
+		}
+		cache.version = func(ctx context.Context, name string) (string, error) {
+			runs++
+			return "v1.33.1", nil
+		}
+		return cache
+	}
+
+	cache := newCache(time.Hour)
+	for i := 0; i < 3; i++ {
+		tool, err := cache.Detect(context.Background(), "minikube")
+		if err != nil {
+			t.Fatalf("Detect() error = %v", err)
+		}
+		if tool.Version != "v1.33.1" || tool.Path != binary {
+			t.Errorf("Detect() = %+v, want v1.33.1 at %s", tool, binary)
+		}
+	}
+	if runs != 1 {
+		t.Errorf("version command ran %d times in one process, want 1", runs)
+	}
+	if _, err := cache.Detect(context.Background(), "aws"); err == nil {
+		t.Error("Detect() expected error for a tool missing from PATH")
+	}
+	if detected := cache.Detected(); len(detected) != 1 || detected[0].Name != "minikube" {
+		t.Errorf("Detected() = %+v, want only minikube", detected)
+	}
+
+	// A new process reuses the on-disk result until the binary moves or the TTL passes
+	newCache(time.Hour).Detect(context.Background(), "minikube")
+	if runs != 1 {
+		t.Errorf("version command ran again despite a fresh disk cache")
+	}
+	binary = "/opt/homebrew/bin/minikube"
+	newCache(time.Hour).Detect(context.Background(), "minikube")
+	if runs != 2 {
+		t.Errorf("version command should rerun when the binary on PATH changes")
+	}
+	newCache(0).Detect(context.Background(), "minikube")
+	if runs != 3 {
+		t.Errorf("version command should rerun once the cached entry expires")
+	}
+}
+
+func TestParseToolVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"v1.33.1\n", "v1.33.1"},
+		{"Client Version: v1.30.2\nKustomize Version: v5.0.4\n", "v1.30.2"},
+		{"aws-cli/2.15.0 Python/3.11.6 Linux/6.5.0 exe/x86_64\n", "2.15.0"},
+		{"Client:\n\tVersion: v1.13.0\n\tGit commit: abc\n", "v1.13.0"},
+		{"kind v0.23.0 go1.22.2 linux/amd64\n", "v0.23.0"},
+		{"k3d version v5.6.0\nk3s version v1.27.4-k3s1 (default)\n", "v5.6.0"},
+		{"", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := parseToolVersion(tt.output); got != tt.want {
+			t.Errorf("parseToolVersion(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// Tracing backends
+const (
+	TracingBackendJaeger = "jaeger"
+	TracingBackendTempo  = "tempo"
+)
+
+const tracingNamespace = "tracing"
+
+// helmRelease is one chart installed with `helm upgrade --install`
+type helmRelease struct {
+	Name  string
+	Chart string
+	Repo  string
+	Set   []string
+	// Values is a values document passed on stdin
+	Values string
+}
+
+// args builds the idempotent install invocation for r in the cluster behind kubeContext
+func (r helmRelease) args(kubeContext, namespace string) []string {
+	args := []string{"upgrade", "--install", r.Name, r.Chart, "--repo", r.Repo,
+		"--kube-context", kubeContext, "--namespace", namespace, "--create-namespace", "--wait", "--timeout", "10m"}
+	if r.Values != "" {
+		args = append(args, "-f", "-")
+	}
+	for _, value := range r.Set {
+		args = append(args, "--set", value)
+	}
+	return args
+}
+
+// tracing returns the cluster's tracing settings when tracing is enabled
+func tracing(config *ClusterConfig) *TracingConfig {
+	if config.ResourceConfig == nil || config.ResourceConfig.Monitoring == nil {
+		return nil
+	}
+	t := config.ResourceConfig.Monitoring.Tracing
+	if t == nil || !t.Enabled {
+		return nil
+	}
+	return t
+}
+
+func validateTracingConfig(config *ClusterConfig, result *ValidationResult) {
+	t := tracing(config)
+	if t == nil {
+		return
+	}
+	const field = "resourceConfig.monitoring.tracing"
+	switch t.Backend {
+	case "", TracingBackendJaeger, TracingBackendTempo:
+	default:
+		result.Errorf(field+".backend", "unknown tracing backend %q; use %s or %s", t.Backend, TracingBackendJaeger, TracingBackendTempo)
+	}
+	if t.SampleRate < 0 || t.SampleRate > 1 {
+		result.Errorf(field+".sampleRate", "sample rate must be between 0 and 1, got %g", t.SampleRate)
+	}
+	for key := range t.Config {
+		if key == "" || strings.ContainsAny(key, "= ") {
+			result.Errorf(fieldPath(field, "config"), "invalid chart value name %q", key)
+		}
+	}
+}
+
+// tracingReleases returns the charts for the tracing backend and the OpenTelemetry collector that
+// samples spans and forwards them to it. A sample rate of 0 means unset and keeps every trace.
+func tracingReleases(t *TracingConfig) ([]helmRelease, error) {
+	var backend helmRelease
+	var endpoint string
+	switch t.Backend {
+	case "", TracingBackendJaeger:
+		backend = helmRelease{Name: "jaeger", Chart: "jaeger", Repo: "https://jaegertracing.github.io/helm-charts", Set: []string{
+			"allInOne.enabled=true",
+			"storage.type=memory",
+			"provisionDataStore.cassandra=false",
+			"agent.enabled=false",
+			"collector.enabled=false",
+			"query.enabled=false",
+		}}
+		endpoint = "jaeger-collector." + tracingNamespace + ".svc:4317"
+	case TracingBackendTempo:
+		backend = helmRelease{Name: "tempo", Chart: "tempo", Repo: "https://grafana.github.io/helm-charts", Set: []string{
+			"traces.otlp.grpc.enabled=true",
+		}}
+		endpoint = "tempo." + tracingNamespace + ".svc:4317"
+	default:
+		return nil, fmt.Errorf("unknown tracing backend %q", t.Backend)
+	}
+
+	keys := make([]string, 0, len(t.Config))
+	for key := range t.Config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		backend.Set = append(backend.Set, key+"="+t.Config[key])
+	}
+
+	rate := t.SampleRate
+	if rate == 0 {
+		rate = 1
+	}
+	collector := helmRelease{Name: "otel-collector", Chart: "opentelemetry-collector", Repo: "https://open-telemetry.github.io/opentelemetry-helm-charts",
+		Values: fmt.Sprintf(`mode: deployment
+image:
+  repository: otel/opentelemetry-collector-contrib
+config:
+  processors:
+    probabilistic_sampler:
+      sampling_percentage: %s
+  exporters:
+    otlp:
+      endpoint: %s
+      tls:
+        insecure: true
+  service:
+    pipelines:
+      traces:
+        receivers: [otlp]
+        processors: [memory_limiter, probabilistic_sampler, batch]
+        exporters: [otlp]
+`, strconv.FormatFloat(rate*100, 'f', -1, 64), endpoint)}
+
+	return []helmRelease{backend, collector}, nil
+}
+
+// installTracing installs the tracing backend and collector configured for config into the
+// cluster behind kubeContext and returns the releases it installed. Clusters without tracing are
+// left alone.
+func installTracing(ctx context.Context, kubeContext string, config *ClusterConfig) ([]ClusterResource, error) {
+	t := tracing(config)
+	if t == nil {
+		return nil, nil
+	}
+	releases, err := tracingReleases(t)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "tracing", Status: progress.StatusStarted,
+		Message: fmt.Sprintf("Installing %s tracing...", releases[0].Name)})
+	var resources []ClusterResource
+	for _, release := range releases {
+		cmd := subprocess.CommandContext(ctx, "helm", release.args(kubeContext, tracingNamespace)...)
+		if release.Values != "" {
+			cmd.Stdin = strings.NewReader(release.Values)
+		}
+		if output, err := cmd.CombinedOutput(); err != nil {
+			progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "tracing", Status: progress.StatusFailed})
+			return resources, fmt.Errorf("failed to install %s: %w: %s", release.Chart, err, strings.TrimSpace(string(output)))
+		}
+		resources = append(resources, ClusterResource{Kind: "helmrelease", Name: tracingNamespace + "/" + release.Name, Source: "tracing"})
+	}
+	progress.Report(ctx, progress.Event{Cluster: config.Name, Operation: "create", Phase: "tracing", Status: progress.StatusCompleted,
+		Message: fmt.Sprintf("Installed %s tracing for cluster %s", releases[0].Name, config.Name)})
+	return resources, nil
+}
@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateTracingConfig(t *testing.T) {
+	config := func(tracing *TracingConfig) *ClusterConfig {
+		return &ClusterConfig{Name: "dev", ResourceConfig: &ResourceConfig{Monitoring: &MonitoringConfig{Tracing: tracing}}}
+	}
+	tests := []struct {
+		name    string
+		config  *ClusterConfig
+		wantErr bool
+	}{
+		{name: "not configured", config: &ClusterConfig{Name: "dev"}},
+		{name: "disabled with bad backend", config: config(&TracingConfig{Backend: "zipkin"})},
+		{name: "jaeger default", config: config(&TracingConfig{Enabled: true, SampleRate: 0.1})},
+		{name: "tempo", config: config(&TracingConfig{Enabled: true, Backend: "tempo", SampleRate: 1})},
+		{name: "unknown backend", config: config(&TracingConfig{Enabled: true, Backend: "zipkin"}), wantErr: true},
+		{name: "sample rate above one", config: config(&TracingConfig{Enabled: true, SampleRate: 10}), wantErr: true},
+		{name: "negative sample rate", config: config(&TracingConfig{Enabled: true, SampleRate: -0.5}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ValidationResult{}
+			validateTracingConfig(tt.config, result)
+			err := result.Err()
+			if tt.wantErr && err == nil {
+				t.Errorf("validateTracingConfig() expected error but got none")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("validateTracingConfig() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestTracingReleases(t *testing.T) {
+	releases, err := tracingReleases(&TracingConfig{Enabled: true, Backend: "tempo", SampleRate: 0.25, Config: map[string]string{"persistence.enabled": "true"}})
+	if err != nil {
+		t.Fatalf("tracingReleases() unexpected error = %v", err)
+	}
+	if len(releases) != 2 || releases[0].Name != "tempo" || releases[1].Name != "otel-collector" {
+		t.Fatalf("tracingReleases() = %+v", releases)
+	}
+	if !slices.Contains(releases[0].Set, "persistence.enabled=true") {
+		t.Errorf("tempo values = %v, missing chart config", releases[0].Set)
+	}
+	for _, want := range []string{"sampling_percentage: 25\n", "endpoint: tempo.tracing.svc:4317\n"} {
+		if !strings.Contains(releases[1].Values, want) {
+			t.Errorf("collector values missing %q:\n%s", want, releases[1].Values)
+		}
+	}
+
+	releases, err = tracingReleases(&TracingConfig{Enabled: true})
+	if err != nil {
+		t.Fatalf("tracingReleases() unexpected error = %v", err)
+	}
+	if releases[0].Name != "jaeger" || !strings.Contains(releases[1].Values, "sampling_percentage: 100\n") {
+		t.Errorf("tracingReleases() with defaults = %+v", releases)
+	}
+
+	args := strings.Join(releases[1].args("kind-dev", tracingNamespace), " ")
+	want := "upgrade --install otel-collector opentelemetry-collector --repo https://open-telemetry.github.io/opentelemetry-helm-charts " +
+		"--kube-context kind-dev --namespace tracing --create-namespace --wait --timeout 10m -f -"
+	if args != want {
+		t.Errorf("args() = %s\nwant %s", args, want)
+	}
+}
@@ -0,0 +1,388 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// maxNodeSkew is how many minor versions kubelets may lag the API server under Kubernetes' version
+// skew policy
+const maxNodeSkew = 3
+
+// UpgradeOptions describes an in-place Kubernetes version upgrade
+type UpgradeOptions struct {
+	// Version is the target Kubernetes version, e.g. v1.31.0; EKS only uses its minor version
+	Version string
+	// MaxUnavailable and MaxUnavailablePercent bound how many nodes a node group replaces at once
+	// during its rolling upgrade. Zero keeps the node group's current setting.
+	MaxUnavailable        int
+	MaxUnavailablePercent int
+}
+
+// Validate checks the options independently of any cluster
+func (o UpgradeOptions) Validate() error {
+	if _, err := parseKubeVersion(o.Version); err != nil {
+		return err
+	}
+	if o.MaxUnavailable < 0 || o.MaxUnavailablePercent < 0 || o.MaxUnavailablePercent > 100 {
+		return fmt.Errorf("max unavailable must be positive and max unavailable percent at most 100")
+	}
+	if o.MaxUnavailable > 0 && o.MaxUnavailablePercent > 0 {
+		return fmt.Errorf("set either max unavailable or max unavailable percent, not both")
+	}
+	return nil
+}
+
+// ClusterUpgrader is implemented by providers that can upgrade a cluster's Kubernetes version in
+// place. kind and k3d clusters can't be upgraded; they have to be recreated.
+type ClusterUpgrader interface {
+	// UpgradeCluster upgrades the control plane and then the nodes, reporting each phase
+	UpgradeCluster(ctx context.Context, name string, opts UpgradeOptions) error
+}
+
+var _ ClusterUpgrader = (*LocalProvider)(nil)
+var _ ClusterUpgrader = (*AWSProvider)(nil)
+var _ ClusterUpgrader = (*FakeProvider)(nil)
+
+// kubeVersion is a parsed Kubernetes version; Patch is -1 when only the minor version is known
+type kubeVersion struct {
+	Major, Minor, Patch int
+}
+
+func (v kubeVersion) String() string {
+	if v.Patch < 0 {
+		return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// parseKubeVersion parses v1.31.0, 1.31 or a build-qualified version like v1.31.2-eks-7f9249a
+func parseKubeVersion(version string) (kubeVersion, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(trimmed, "-+"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+	parts := strings.Split(trimmed, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return kubeVersion{}, fmt.Errorf("invalid Kubernetes version %q; use a version like v1.31.0", version)
+	}
+	numbers := []int{0, 0, -1}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return kubeVersion{}, fmt.Errorf("invalid Kubernetes version %q; use a version like v1.31.0", version)
+		}
+		numbers[i] = n
+	}
+	return kubeVersion{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// compareKubeVersions orders a and b, ignoring patch versions unless both have one
+func compareKubeVersions(a, b kubeVersion) int {
+	if a.Major != b.Major {
+		return a.Major - b.Major
+	}
+	if a.Minor != b.Minor {
+		return a.Minor - b.Minor
+	}
+	if a.Patch < 0 || b.Patch < 0 {
+		return 0
+	}
+	return a.Patch - b.Patch
+}
+
+// CompareKubeVersions orders Kubernetes versions such as v1.9.0 and 1.10 numerically. Versions
+// that don't parse sort before those that do, and among themselves as strings.
+func CompareKubeVersions(a, b string) int {
+	va, errA := parseKubeVersion(a)
+	vb, errB := parseKubeVersion(b)
+	switch {
+	case errA == nil && errB == nil:
+		return compareKubeVersions(va, vb)
+	case errA == nil:
+		return 1
+	case errB == nil:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+// SameKubeVersion reports whether a and b name the same Kubernetes version, so v1.31.0, 1.31.0
+// and an EKS cluster's 1.31 all match. Versions that don't parse only match themselves.
+func SameKubeVersion(a, b string) bool {
+	va, errA := parseKubeVersion(a)
+	vb, errB := parseKubeVersion(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return compareKubeVersions(va, vb) == 0
+}
+
+// ValidateUpgrade checks an upgrade from current to target against Kubernetes' version skew
+// policy: no downgrades, one minor version at a time, and no node more than three minor versions
+// behind the upgraded control plane. Upgrading to the current version is allowed, so callers can
+// resume an upgrade that stopped after the control plane.
+func ValidateUpgrade(current, target string, nodeVersions []string) error {
+	from, err := parseKubeVersion(current)
+	if err != nil {
+		return fmt.Errorf("cannot determine the cluster's current version: %w", err)
+	}
+	to, err := parseKubeVersion(target)
+	if err != nil {
+		return err
+	}
+
+	if from.Major != to.Major {
+		return fmt.Errorf("cannot upgrade across major versions (%s to %s)", from, to)
+	}
+	if compareKubeVersions(to, from) < 0 {
+		return fmt.Errorf("cannot downgrade from %s to %s", from, to)
+	}
+	if to.Minor > from.Minor+1 {
+		return fmt.Errorf("cannot skip minor versions upgrading from %s to %s; upgrade to %d.%d first", from, to, from.Major, from.Minor+1)
+	}
+	for _, node := range nodeVersions {
+		v, err := parseKubeVersion(node)
+		if err != nil {
+			continue
+		}
+		if to.Minor-v.Minor > maxNodeSkew {
+			return fmt.Errorf("nodes at %s would be more than %d minor versions behind a %s control plane; upgrade them first", v, maxNodeSkew, to)
+		}
+	}
+	return nil
+}
+
+// UpgradeCluster restarts the minikube profile with the new Kubernetes version, which minikube
+// upgrades in place. Every node runs the control plane's version, so nodes need no phase of their
+// own.
+func (l *LocalProvider) UpgradeCluster(ctx context.Context, name string, opts UpgradeOptions) error {
+	ctx = subprocess.WithOperation(ctx, "upgrade")
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	cluster, err := l.GetCluster(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster %s: %w", name, err)
+	}
+	target := "v" + strings.TrimPrefix(opts.Version, "v")
+	if err := ValidateUpgrade(cluster.Version, target, nil); err != nil {
+		return err
+	}
+	current, _ := parseKubeVersion(cluster.Version)
+	if to, _ := parseKubeVersion(target); compareKubeVersions(current, to) == 0 {
+		return fmt.Errorf("cluster %s is already running %s", name, cluster.Version)
+	}
+	if opts.MaxUnavailable > 0 || opts.MaxUnavailablePercent > 0 {
+		progress.Report(ctx, progress.Event{Cluster: name, Operation: "upgrade", Phase: "validate", Status: progress.StatusWarning,
+			Message: "Max unavailable settings only apply to EKS node groups; ignoring them"})
+	}
+
+	if cluster.Status == ClusterStatusRunning {
+		progress.Report(ctx, progress.Event{Cluster: name, Operation: "upgrade", Phase: "stop", Status: progress.StatusStarted,
+			Message: fmt.Sprintf("Stopping minikube cluster %s...", name)})
+		if output, err := subprocess.CommandContext(ctx, "minikube", "stop", "-p", name).CombinedOutput(); err != nil {
+			progress.Report(ctx, progress.Event{Cluster: name, Operation: "upgrade", Phase: "stop", Status: progress.StatusFailed})
+			return fmt.Errorf("failed to stop cluster %s: %w\nOutput: %s", name, err, string(output))
+		}
+		progress.Report(ctx, progress.Event{Cluster: name, Operation: "upgrade", Phase: "stop", Status: progress.StatusCompleted})
+	}
+
+	progress.Report(ctx, progress.Event{Cluster: name, Operation: "upgrade", Phase: "control-plane", Status: progress.StatusStarted,
+		Message: fmt.Sprintf("Restarting %s with Kubernetes %s...", name, target)})
+	cmd := subprocess.CommandContext(ctx, "minikube", "start", "-p", name, "--kubernetes-version="+target)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		progress.Report(ctx, progress.Event{Cluster: name, Operation: "upgrade", Phase: "control-plane", Status: progress.StatusFailed})
+		return fmt.Errorf("failed to upgrade cluster %s: %w\nOutput: %s", name, err, string(output))
+	}
+	progress.Report(ctx, progress.Event{Cluster: name, Operation: "upgrade", Phase: "control-plane", Status: progress.StatusCompleted})
+	return nil
+}
+
+// eksUpgradeTimeout bounds each EKS update; control plane updates usually take 10-25 minutes and
+// node groups longer the more nodes they replace
+const eksUpgradeTimeout = 90 * time.Minute
+
+// UpgradeCluster upgrades the EKS control plane one minor version, then rolls every node group
+// that's behind onto it. Node groups already at the target are skipped, so rerunning the command
+// resumes an upgrade that failed partway.
+func (a *AWSProvider) UpgradeCluster(ctx context.Context, name string, opts UpgradeOptions) error {
+	ctx = subprocess.WithOperation(ctx, "upgrade")
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	to, _ := parseKubeVersion(opts.Version)
+	target := fmt.Sprintf("%d.%d", to.Major, to.Minor)
+	if !slices.Contains(a.GetSupportedVersions(), target) {
+		return fmt.Errorf("unsupported EKS version: %s", target)
+	}
+
+	cluster, err := a.GetCluster(ctx, name)
+	if err != nil {
+		return err
+	}
+	nodeGroups, err := a.listNodeGroups(ctx, name)
+	if err != nil {
+		return err
+	}
+	nodeVersions := make(map[string]string)
+	var versions []string
+	for _, nodeGroup := range nodeGroups {
+		ng, err := a.describeNodeGroup(ctx, name, nodeGroup)
+		if err != nil {
+			return err
+		}
+		nodeVersions[nodeGroup] = ng.Version
+		versions = append(versions, ng.Version)
+	}
+	if err := ValidateUpgrade(cluster.Version, target, versions); err != nil {
+		return err
+	}
+
+	current, _ := parseKubeVersion(cluster.Version)
+	upgradeControlPlane := compareKubeVersions(current, to) != 0
+	var behind []string
+	for _, nodeGroup := range nodeGroups {
+		if v, err := parseKubeVersion(nodeVersions[nodeGroup]); err != nil || compareKubeVersions(v, to) != 0 {
+			behind = append(behind, nodeGroup)
+		}
+	}
+	if !upgradeControlPlane && len(behind) == 0 {
+		return fmt.Errorf("cluster %s and its node groups are already running %s", name, target)
+	}
+
+	if upgradeControlPlane {
+		progress.Report(ctx, progress.Event{Cluster: name, Operation: "upgrade", Phase: "control-plane", Status: progress.StatusStarted,
+			Message: fmt.Sprintf("Upgrading EKS control plane of %s from %s to %s...", name, cluster.Version, target)})
+		err := a.runEKSUpdate(ctx, name, "", "update-cluster-version", "--name", name, "--kubernetes-version", target)
+		if err != nil {
+			progress.Report(ctx, progress.Event{Cluster: name, Operation: "upgrade", Phase: "control-plane", Status: progress.StatusFailed})
+			return fmt.Errorf("failed to upgrade control plane: %w", err)
+		}
+		progress.Report(ctx, progress.Event{Cluster: name, Operation: "upgrade", Phase: "control-plane", Status: progress.StatusCompleted})
+	}
+
+	for _, nodeGroup := range behind {
+		phase := "nodegroup/" + nodeGroup
+		if updateConfig := nodeGroupUpdateConfig(opts); updateConfig != "" {
+			progress.Report(ctx, progress.Event{Cluster: name, Operation: "upgrade", Phase: phase, Status: progress.StatusInfo,
+				Message: fmt.Sprintf("Setting %s on node group %s...", updateConfig, nodeGroup)})
+			err := a.runEKSUpdate(ctx, name, nodeGroup, "update-nodegroup-config", "--cluster-name", name,
+				"--nodegroup-name", nodeGroup, "--update-config", updateConfig)
+			if err != nil {
+				progress.Report(ctx, progress.Event{Cluster: name, Operation: "upgrade", Phase: phase, Status: progress.StatusFailed})
+				return fmt.Errorf("failed to configure node group %s: %w", nodeGroup, err)
+			}
+		}
+
+		progress.Report(ctx, progress.Event{Cluster: name, Operation: "upgrade", Phase: phase, Status: progress.StatusStarted,
+			Message: fmt.Sprintf("Rolling node group %s onto %s...", nodeGroup, target)})
+		err := a.runEKSUpdate(ctx, name, nodeGroup, "update-nodegroup-version", "--cluster-name", name,
+			"--nodegroup-name", nodeGroup, "--kubernetes-version", target)
+		if err != nil {
+			progress.Report(ctx, progress.Event{Cluster: name, Operation: "upgrade", Phase: phase, Status: progress.StatusFailed})
+			return fmt.Errorf("failed to upgrade node group %s: %w", nodeGroup, err)
+		}
+		progress.Report(ctx, progress.Event{Cluster: name, Operation: "upgrade", Phase: phase, Status: progress.StatusCompleted})
+	}
+	return nil
+}
+
+// nodeGroupUpdateConfig renders the --update-config value for opts, or "" to leave it alone
+func nodeGroupUpdateConfig(opts UpgradeOptions) string {
+	switch {
+	case opts.MaxUnavailable > 0:
+		return fmt.Sprintf("maxUnavailable=%d", opts.MaxUnavailable)
+	case opts.MaxUnavailablePercent > 0:
+		return fmt.Sprintf("maxUnavailablePercentage=%d", opts.MaxUnavailablePercent)
+	}
+	return ""
+}
+
+// runEKSUpdate starts an EKS update with aws eks <args> and waits for it to finish. nodeGroup
+// names the node group the update belongs to, if any, since describe-update needs it.
+func (a *AWSProvider) runEKSUpdate(ctx context.Context, clusterName, nodeGroup string, args ...string) error {
+	cmd := subprocess.CommandContext(ctx, "aws", append(append([]string{"eks"}, args...),
+		"--region", a.region, "--query", "update.id", "--output", "text")...)
+	if a.profile != "" {
+		cmd.Args = append(cmd.Args, "--profile", a.profile)
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+	return a.waitForUpdate(ctx, clusterName, nodeGroup, strings.TrimSpace(string(output)))
+}
+
+func (a *AWSProvider) waitForUpdate(ctx context.Context, clusterName, nodeGroup, updateID string) error {
+	checkInterval := 30 * time.Second
+	deadline := time.Now().Add(eksUpgradeTimeout)
+
+	for time.Now().Before(deadline) {
+		cmd := subprocess.CommandContext(ctx, "aws", "eks", "describe-update",
+			"--name", clusterName,
+			"--update-id", updateID,
+			"--region", a.region,
+			"--query", "update.status",
+			"--output", "text")
+		if nodeGroup != "" {
+			cmd.Args = append(cmd.Args, "--nodegroup-name", nodeGroup)
+		}
+		if a.profile != "" {
+			cmd.Args = append(cmd.Args, "--profile", a.profile)
+		}
+
+		output, err := cmd.Output()
+		if err != nil {
+			return fmt.Errorf("failed to check update %s: %w", updateID, err)
+		}
+
+		switch status := strings.TrimSpace(string(output)); status {
+		case "Successful":
+			return nil
+		case "Failed", "Cancelled":
+			return fmt.Errorf("update %s %s; see 'aws eks describe-update --update-id %s'", updateID, strings.ToLower(status), updateID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(checkInterval):
+		}
+	}
+
+	return fmt.Errorf("timeout waiting for update %s", updateID)
+}
+
+// UpgradeCluster simulates upgrading the control plane and then the nodes, holding the simulated
+// cluster to the same version rules as the real providers
+func (f *FakeProvider) UpgradeCluster(ctx context.Context, name string, opts UpgradeOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	cluster, err := f.GetCluster(ctx, name)
+	if err != nil {
+		return err
+	}
+	target := strings.TrimPrefix(opts.Version, "v")
+	if !slices.Contains(f.GetSupportedVersions(), target) {
+		return fmt.Errorf("unsupported version: %s", opts.Version)
+	}
+	if err := ValidateUpgrade(cluster.Version, target, nil); err != nil {
+		return err
+	}
+	if cluster.Version == target {
+		return fmt.Errorf("cluster %s is already running %s", name, target)
+	}
+	return f.lifecycle(ctx, name, "upgrade", func(state *fakeState, cluster *Cluster) error {
+		cluster.Version = target
+		return nil
+	}, "control-plane", "nodes")
+}
@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateUpgrade(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		target  string
+		nodes   []string
+		wantErr string
+	}{
+		{name: "next minor", current: "v1.30.4", target: "v1.31.0"},
+		{name: "patch", current: "1.31.0", target: "1.31.2"},
+		{name: "eks minor", current: "1.30", target: "1.31", nodes: []string{"1.29", "1.30"}},
+		{name: "resume after control plane", current: "1.31", target: "1.31", nodes: []string{"1.30"}},
+		{name: "build suffix", current: "v1.30.2-eks-7f9249a", target: "1.31"},
+		{name: "downgrade", current: "v1.31.0", target: "v1.30.0", wantErr: "cannot downgrade"},
+		{name: "patch downgrade", current: "v1.31.2", target: "v1.31.1", wantErr: "cannot downgrade"},
+		{name: "skipped minor", current: "1.29", target: "1.31", wantErr: "upgrade to 1.30 first"},
+		{name: "node skew", current: "1.30", target: "1.31", nodes: []string{"1.31", "1.27"}, wantErr: "nodes at 1.27"},
+		{name: "invalid target", current: "1.30", target: "latest", wantErr: "invalid Kubernetes version"},
+		{name: "unknown current", current: "", target: "1.31", wantErr: "current version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpgrade(tt.current, tt.target, tt.nodes)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateUpgrade() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateUpgrade() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpgradeOptions_Validate(t *testing.T) {
+	if err := (UpgradeOptions{Version: "v1.31.0", MaxUnavailablePercent: 25}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := (UpgradeOptions{Version: "v1.31.0", MaxUnavailable: 1, MaxUnavailablePercent: 25}).Validate(); err == nil {
+		t.Error("Validate() should reject both max unavailable settings")
+	}
+	if err := (UpgradeOptions{Version: "v1.31.0", MaxUnavailablePercent: 150}).Validate(); err == nil {
+		t.Error("Validate() should reject a percentage over 100")
+	}
+	if got := nodeGroupUpdateConfig(UpgradeOptions{MaxUnavailablePercent: 25}); got != "maxUnavailablePercentage=25" {
+		t.Errorf("nodeGroupUpdateConfig() = %q", got)
+	}
+}
+
+func TestFakeProvider_UpgradeCluster(t *testing.T) {
+	ctx := context.Background()
+	p := NewFakeProvider(FakeOptions{StatePath: filepath.Join(t.TempDir(), "state.json")})
+	if _, err := p.CreateCluster(ctx, &ClusterConfig{Name: "dev", Version: "1.29.0", NodeCount: 1}); err != nil {
+		t.Fatalf("CreateCluster() error = %v", err)
+	}
+
+	if err := p.UpgradeCluster(ctx, "dev", UpgradeOptions{Version: "v1.31.0"}); err == nil || !strings.Contains(err.Error(), "upgrade to 1.30 first") {
+		t.Errorf("UpgradeCluster() skipping a minor error = %v", err)
+	}
+	if err := p.UpgradeCluster(ctx, "dev", UpgradeOptions{Version: "v1.30.0"}); err != nil {
+		t.Fatalf("UpgradeCluster() error = %v", err)
+	}
+	if cluster, _ := p.GetCluster(ctx, "dev"); cluster.Version != "1.30.0" {
+		t.Errorf("Version = %q after upgrade, want 1.30.0", cluster.Version)
+	}
+	if err := p.UpgradeCluster(ctx, "dev", UpgradeOptions{Version: "1.30.0"}); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("UpgradeCluster() to the current version error = %v", err)
+	}
+}
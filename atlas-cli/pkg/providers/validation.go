@@ -0,0 +1,129 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity says whether a validation issue blocks cluster creation
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// ValidationIssue is one problem found in a cluster configuration. Field is the dotted YAML
+// path of the offending value, e.g. "networkConfig.extraPortMaps[1].protocol".
+type ValidationIssue struct {
+	Field    string   `json:"field"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (i ValidationIssue) String() string {
+	if i.Field == "" {
+		return i.Message
+	}
+	return i.Field + ": " + i.Message
+}
+
+// ValidationResult collects every issue found in a configuration so they can be fixed in one pass
+type ValidationResult struct {
+	Issues []ValidationIssue `json:"issues"`
+}
+
+// ConfigValidator is implemented by providers that report every configuration issue at once.
+// ValidateConfig on these providers returns the same issues as a *ValidationError.
+type ConfigValidator interface {
+	Validate(config *ClusterConfig) *ValidationResult
+}
+
+// Validate returns every issue p finds in config. Providers that only implement ValidateConfig
+// report their single error without a field path.
+func Validate(p Provider, config *ClusterConfig) *ValidationResult {
+	if validator, ok := p.(ConfigValidator); ok {
+		return validator.Validate(config)
+	}
+	result := &ValidationResult{}
+	if err := p.ValidateConfig(config); err != nil {
+		result.Errorf("", "%v", err)
+	}
+	return result
+}
+
+// Errorf records an error for field
+func (r *ValidationResult) Errorf(field, format string, args ...any) {
+	r.Issues = append(r.Issues, ValidationIssue{Field: field, Severity: SeverityError, Message: fmt.Sprintf(format, args...)})
+}
+
+// Warnf records a warning for field
+func (r *ValidationResult) Warnf(field, format string, args ...any) {
+	r.Issues = append(r.Issues, ValidationIssue{Field: field, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)})
+}
+
+// Check records err, if any, as an error for field
+func (r *ValidationResult) Check(field string, err error) {
+	if err != nil {
+		r.Errorf(field, "%v", err)
+	}
+}
+
+// Errors returns the issues that block creation
+func (r *ValidationResult) Errors() []ValidationIssue {
+	return r.filter(SeverityError)
+}
+
+// Warnings returns the issues that don't block creation
+func (r *ValidationResult) Warnings() []ValidationIssue {
+	return r.filter(SeverityWarning)
+}
+
+func (r *ValidationResult) filter(severity Severity) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// Err returns a *ValidationError listing every error, or nil when there are only warnings
+func (r *ValidationResult) Err() error {
+	errs := r.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Issues: errs}
+}
+
+// ValidationError is returned by ValidateConfig when a configuration has one or more errors
+type ValidationError struct {
+	Issues []ValidationIssue
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		messages[i] = issue.String()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// fieldPath joins YAML path segments, e.g. fieldPath("mounts", 0, "nodePath") is "mounts[0].nodePath"
+func fieldPath(parts ...any) string {
+	var b strings.Builder
+	for _, part := range parts {
+		switch p := part.(type) {
+		case int:
+			fmt.Fprintf(&b, "[%d]", p)
+		case string:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(p)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,85 @@
+package providers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAWSProvider_Validate(t *testing.T) {
+	provider := NewAWSProvider("", "us-west-2")
+	config := &ClusterConfig{
+		Name:         "prod",
+		Region:       "mars-north-1",
+		NodeCount:    0,
+		InstanceType: "t9.huge",
+		DiskSize:     "40g",
+	}
+
+	result := provider.Validate(config)
+	var fields []string
+	for _, issue := range result.Errors() {
+		fields = append(fields, issue.Field)
+	}
+	if got, want := strings.Join(fields, ","), "region,nodeCount,instanceType"; got != want {
+		t.Errorf("error fields = %s, want %s", got, want)
+	}
+	if warnings := result.Warnings(); len(warnings) != 1 || warnings[0].Field != "diskSize" {
+		t.Errorf("Warnings() = %+v, want one for diskSize", warnings)
+	}
+
+	var validationErr *ValidationError
+	if err := provider.ValidateConfig(config); !errors.As(err, &validationErr) || len(validationErr.Issues) != 3 {
+		t.Errorf("ValidateConfig() = %v, want a ValidationError with all 3 errors", err)
+	}
+}
+
+func TestAWSProvider_ValidateAWSSection(t *testing.T) {
+	provider := NewAWSProvider("", "us-west-2")
+	config := &ClusterConfig{
+		Name:      "prod",
+		Region:    "us-west-2",
+		NodeCount: 2,
+		AWS: &AWSConfig{
+			Subnets:        []string{"subnet-0a1b", "vpc-123"},
+			ClusterRoleARN: "eks-cluster-role",
+			NodeRoleARN:    "arn:aws:iam::123456789012:role/eks-node",
+		},
+	}
+
+	var fields []string
+	for _, issue := range provider.Validate(config).Errors() {
+		fields = append(fields, issue.Field)
+	}
+	if got, want := strings.Join(fields, ","), "aws.clusterRoleArn,aws.subnets[1]"; got != want {
+		t.Errorf("error fields = %s, want %s", got, want)
+	}
+}
+
+func TestValidationResult_Err(t *testing.T) {
+	result := &ValidationResult{}
+	result.Warnf("region", "region eu is ignored by the local provider")
+	if err := result.Err(); err != nil {
+		t.Errorf("Err() with only warnings = %v, want nil", err)
+	}
+
+	result.Errorf(fieldPath("mounts", 0, "nodePath"), "node path must be absolute: data")
+	result.Errorf("", "minikube is not installed or not in PATH")
+	want := "mounts[0].nodePath: node path must be absolute: data; minikube is not installed or not in PATH"
+	if err := result.Err(); err == nil || err.Error() != want {
+		t.Errorf("Err() = %v, want %q", err, want)
+	}
+}
+
+func TestValidate_PlainProvider(t *testing.T) {
+	// Providers without a ConfigValidator still report their single error
+	result := Validate(plainProvider{NewFakeProvider(FakeOptions{})}, &ClusterConfig{NodeCount: 1})
+	if errs := result.Errors(); len(errs) != 1 || errs[0].Field != "" || !strings.Contains(errs[0].Message, "cluster name is required") {
+		t.Errorf("Validate() = %+v, want the ValidateConfig error", result.Issues)
+	}
+}
+
+// plainProvider hides FakeProvider's Validate method
+type plainProvider struct {
+	Provider
+}
@@ -0,0 +1,170 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document
+type Schema struct {
+	SchemaURI            string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Generate builds a JSON Schema for v's type using the field names from the given struct tag (json or yaml).
+// Only fields tagged schema:"required" are required: the ones validation rejects when missing.
+// Fields without omitempty are not, as Atlas fills in defaults for most of them.
+func Generate(v any, tagName string) *Schema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	g := &generator{tagName: tagName, defs: make(map[string]*Schema)}
+	root := g.structSchema(t)
+	root.SchemaURI = draft
+	root.Title = t.Name()
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	return root
+}
+
+type generator struct {
+	tagName string
+	defs    map[string]*Schema
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func (g *generator) typeSchema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.typeSchema(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if _, exists := g.defs[t.Name()]; !exists {
+			g.defs[t.Name()] = &Schema{}
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return &Schema{Ref: "#/$defs/" + t.Name()}
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := g.fieldName(field)
+		if name == "-" {
+			continue
+		}
+
+		s.Properties[name] = g.typeSchema(field.Type)
+		if field.Tag.Get("schema") == "required" {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	sort.Strings(s.Required)
+	return s
+}
+
+func (g *generator) fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get(g.tagName), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// Type describes a published schema
+type Type struct {
+	Name        string
+	Description string
+	Value       any
+	TagName     string
+}
+
+// Registry holds the schemas published by Atlas keyed by name
+type Registry struct {
+	types map[string]Type
+}
+
+// NewRegistry creates an empty schema registry
+func NewRegistry() *Registry {
+	return &Registry{types: make(map[string]Type)}
+}
+
+// Register adds a type to the registry
+func (r *Registry) Register(t Type) {
+	r.types[t.Name] = t
+}
+
+// Get generates the schema registered under name
+func (r *Registry) Get(name string) (*Schema, error) {
+	t, exists := r.types[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown schema type: %s (available: %s)", name, strings.Join(r.Names(), ", "))
+	}
+	return Generate(t.Value, t.TagName), nil
+}
+
+// Types returns all registered types sorted by name
+func (r *Registry) Types() []Type {
+	var types []Type
+	for _, name := range r.Names() {
+		types = append(types, r.types[name])
+	}
+	return types
+}
+
+// Names returns the registered schema names sorted alphabetically
+func (r *Registry) Names() []string {
+	var names []string
+	for name := range r.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
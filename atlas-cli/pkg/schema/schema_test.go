@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"testing"
+	"time"
+)
+
+type testNested struct {
+	Value string `json:"value"`
+}
+
+type testConfig struct {
+	Name     string            `json:"name" schema:"required"`
+	Count    int               `json:"count,omitempty"`
+	Created  time.Time         `json:"created"`
+	Nested   *testNested       `json:"nested,omitempty"`
+	Items    []testNested      `json:"items"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+func TestGenerate(t *testing.T) {
+	s := Generate(&testConfig{}, "json")
+
+	if s.Title != "testConfig" || s.Type != "object" {
+		t.Fatalf("Generate() title/type = %s/%s, want testConfig/object", s.Title, s.Type)
+	}
+
+	if len(s.Properties) != 6 {
+		t.Errorf("Generate() produced %d properties, want 6", len(s.Properties))
+	}
+	if _, exists := s.Properties["Ignored"]; exists {
+		t.Error("Generate() should skip fields tagged with -")
+	}
+	if s.Properties["created"].Format != "date-time" {
+		t.Errorf("Generate() time format = %q, want date-time", s.Properties["created"].Format)
+	}
+	if s.Properties["nested"].Ref != "#/$defs/testNested" {
+		t.Errorf("Generate() nested ref = %q, want #/$defs/testNested", s.Properties["nested"].Ref)
+	}
+	if s.Properties["items"].Items == nil || s.Properties["items"].Items.Ref != "#/$defs/testNested" {
+		t.Error("Generate() slice items should reference the nested definition")
+	}
+	if s.Properties["labels"].AdditionalProperties == nil || s.Properties["labels"].AdditionalProperties.Type != "string" {
+		t.Error("Generate() map values should be typed")
+	}
+
+	// only the explicitly required field, not every field without omitempty
+	want := []string{"name"}
+	if len(s.Required) != len(want) {
+		t.Fatalf("Generate() required = %v, want %v", s.Required, want)
+	}
+	for i := range want {
+		if s.Required[i] != want[i] {
+			t.Errorf("Generate() required = %v, want %v", s.Required, want)
+		}
+	}
+}
+
+func TestRegistry_Get(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(Type{Name: "config", Value: testConfig{}, TagName: "json"})
+
+	if _, err := registry.Get("config"); err != nil {
+		t.Errorf("Get() unexpected error = %v", err)
+	}
+	if _, err := registry.Get("missing"); err == nil {
+		t.Error("Get() expected error for unknown type")
+	}
+}
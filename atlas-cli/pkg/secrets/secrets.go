@@ -0,0 +1,184 @@
+// Package secrets resolves secret references used in place of credentials in Atlas config, so
+// tokens and profiles don't have to be stored in plaintext. A reference names its backend by
+// scheme:
+//
+//	env://NAME               the environment variable NAME
+//	keychain://service/user  the OS keychain (macOS Keychain, or the Secret Service via secret-tool)
+//	vault://path#key         field key of the Vault KV secret at path, read with the vault CLI
+//
+// Values without a registered scheme are used as they are.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/subprocess"
+)
+
+// Backend looks up the secret a reference points to. ref is the reference without its scheme,
+// e.g. "secret/atlas#token" for vault://secret/atlas#token.
+type Backend interface {
+	Lookup(ctx context.Context, ref string) (string, error)
+}
+
+// BackendFunc adapts a function to Backend
+type BackendFunc func(ctx context.Context, ref string) (string, error)
+
+func (f BackendFunc) Lookup(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+// Resolver resolves references against its registered backends
+type Resolver struct {
+	backends map[string]Backend
+}
+
+// NewResolver creates a resolver with the env, keychain and vault backends registered
+func NewResolver() *Resolver {
+	r := &Resolver{backends: make(map[string]Backend)}
+	r.Register("env", BackendFunc(lookupEnv))
+	r.Register("keychain", BackendFunc(lookupKeychain))
+	r.Register("vault", BackendFunc(lookupVault))
+	return r
+}
+
+// Register adds or replaces the backend for scheme
+func (r *Resolver) Register(scheme string, backend Backend) {
+	r.backends[scheme] = backend
+}
+
+// IsReference reports whether value is a reference to a registered backend
+func (r *Resolver) IsReference(value string) bool {
+	scheme, _, ok := strings.Cut(value, "://")
+	if !ok {
+		return false
+	}
+	_, ok = r.backends[scheme]
+	return ok
+}
+
+// Resolve returns the secret value references, or value itself when it isn't a reference.
+// Resolved secrets are redacted from recorded transcripts.
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	scheme, ref, ok := strings.Cut(value, "://")
+	if !ok {
+		return value, nil
+	}
+	backend, ok := r.backends[scheme]
+	if !ok {
+		return value, nil
+	}
+	secret, err := backend.Lookup(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", value, err)
+	}
+	subprocess.RegisterSecret(secret)
+	return secret, nil
+}
+
+// ResolveAll replaces every reference in the strings reachable from v, which must be a pointer,
+// through struct fields, pointers, slices and map values
+func (r *Resolver) ResolveAll(ctx context.Context, v any) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return fmt.Errorf("ResolveAll needs a non-nil pointer, got %T", v)
+	}
+	return r.resolveValue(ctx, value.Elem(), "")
+}
+
+func (r *Resolver) resolveValue(ctx context.Context, v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.String:
+		if !r.IsReference(v.String()) || !v.CanSet() {
+			return nil
+		}
+		secret, err := r.Resolve(ctx, v.String())
+		if err != nil {
+			return fmt.Errorf("%s: %w", strings.TrimPrefix(path, "."), err)
+		}
+		v.SetString(secret)
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			return r.resolveValue(ctx, v.Elem(), path)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if err := r.resolveValue(ctx, v.Field(i), path+"."+v.Type().Field(i).Name); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := r.resolveValue(ctx, v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+		// map elements aren't addressable, so resolved values are stored back by key
+		for _, key := range v.MapKeys() {
+			value := v.MapIndex(key).String()
+			if !r.IsReference(value) {
+				continue
+			}
+			secret, err := r.Resolve(ctx, value)
+			if err != nil {
+				return fmt.Errorf("%s[%v]: %w", strings.TrimPrefix(path, "."), key, err)
+			}
+			v.SetMapIndex(key, reflect.ValueOf(secret).Convert(v.Type().Elem()))
+		}
+	}
+	return nil
+}
+
+func lookupEnv(ctx context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+// lookupKeychain reads service/account from the macOS Keychain, or elsewhere from the Secret
+// Service through secret-tool
+func lookupKeychain(ctx context.Context, ref string) (string, error) {
+	service, account, ok := strings.Cut(ref, "/")
+	if !ok || service == "" || account == "" {
+		return "", fmt.Errorf("keychain references need the form keychain://service/account")
+	}
+	var cmd *subprocess.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = subprocess.CommandContext(ctx, "security", "find-generic-password", "-s", service, "-a", account, "-w")
+	} else {
+		cmd = subprocess.CommandContext(ctx, "secret-tool", "lookup", "service", service, "account", account)
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s/%s from the keychain: %w", service, account, err)
+	}
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
+
+// lookupVault reads one field of a KV secret with the vault CLI, which takes its address and
+// token from VAULT_ADDR and VAULT_TOKEN or its own login
+func lookupVault(ctx context.Context, ref string) (string, error) {
+	path, key, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || key == "" {
+		return "", fmt.Errorf("vault references need the form vault://path#key")
+	}
+	output, err := subprocess.CommandContext(ctx, "vault", "kv", "get", "-field="+key, path).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s from vault: %w", key, err)
+	}
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
@@ -0,0 +1,82 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testConfig struct {
+	Name    string
+	Profile *string
+	Tags    map[string]string
+	Hooks   []struct{ URL string }
+	secret  string
+}
+
+func TestResolver(t *testing.T) {
+	t.Setenv("ATLAS_TEST_PROFILE", "prod-admin")
+
+	r := NewResolver()
+	r.Register("vault", BackendFunc(func(ctx context.Context, ref string) (string, error) {
+		if ref != "secret/atlas#webhook" {
+			return "", fmt.Errorf("no secret at %s", ref)
+		}
+		return "https://hooks.example.com/abc123", nil
+	}))
+
+	tests := []struct {
+		value   string
+		want    string
+		wantErr string
+	}{
+		{"env://ATLAS_TEST_PROFILE", "prod-admin", ""},
+		{"vault://secret/atlas#webhook", "https://hooks.example.com/abc123", ""},
+		{"https://example.com", "https://example.com", ""},
+		{"s3://bucket/key", "s3://bucket/key", ""},
+		{"env://ATLAS_TEST_MISSING", "", "environment variable ATLAS_TEST_MISSING is not set"},
+		{"vault://secret/other#key", "", "no secret at secret/other#key"},
+		{"keychain://atlas", "", "keychain references need the form keychain://service/account"},
+	}
+	for _, tt := range tests {
+		got, err := r.Resolve(context.Background(), tt.value)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Resolve(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestResolver_ResolveAll(t *testing.T) {
+	t.Setenv("ATLAS_TEST_PROFILE", "prod-admin")
+	t.Setenv("ATLAS_TEST_TOKEN", "s3cr3t")
+
+	profile := "env://ATLAS_TEST_PROFILE"
+	config := &testConfig{
+		Name:    "dev",
+		Profile: &profile,
+		Tags:    map[string]string{"token": "env://ATLAS_TEST_TOKEN", "team": "web"},
+		Hooks:   []struct{ URL string }{{URL: "env://ATLAS_TEST_TOKEN"}},
+		secret:  "env://ATLAS_TEST_TOKEN",
+	}
+	if err := NewResolver().ResolveAll(context.Background(), config); err != nil {
+		t.Fatalf("ResolveAll() error = %v", err)
+	}
+	if *config.Profile != "prod-admin" || config.Tags["token"] != "s3cr3t" || config.Tags["team"] != "web" || config.Hooks[0].URL != "s3cr3t" {
+		t.Errorf("ResolveAll() = %+v", config)
+	}
+	if config.secret != "env://ATLAS_TEST_TOKEN" {
+		t.Error("ResolveAll() should leave unexported fields alone")
+	}
+
+	err := NewResolver().ResolveAll(context.Background(), &testConfig{Hooks: []struct{ URL string }{{URL: "env://ATLAS_TEST_MISSING"}}})
+	if err == nil || !strings.HasPrefix(err.Error(), "Hooks[0].URL: ") {
+		t.Errorf("ResolveAll() error = %v, want it to name the field", err)
+	}
+}
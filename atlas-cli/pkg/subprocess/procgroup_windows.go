@@ -0,0 +1,16 @@
+//go:build windows
+
+package subprocess
+
+import "os/exec"
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills the command; Windows has no process groups to signal, so children
+// are left to exit when their pipes close
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,246 @@
+// Package subprocess runs the external CLIs Atlas drives (minikube, aws, kubectl) under a
+// watchdog. Every command gets a timeout chosen by the operation it belongs to, runs in its own
+// process group, and is killed together with anything it spawned when the timeout or its context
+// expires, so a hung minikube or aws call can't block Atlas forever. Commands still running when
+// Atlas exits are killed by cleanup.Run.
+package subprocess
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/cleanup"
+)
+
+// TimeoutsEnvVar overrides per-operation timeouts, e.g. "create=45m,default=5m"
+const TimeoutsEnvVar = "ATLAS_EXEC_TIMEOUTS"
+
+// DefaultOperation is used for commands run outside any operation, such as status queries
+const DefaultOperation = "default"
+
+// killGrace is how long Wait waits for output pipes to close after the process group is killed
+const killGrace = 5 * time.Second
+
+// DefaultTimeouts bound a single command by the operation it runs for. Mutating operations get
+// long limits because minikube start or EKS creation legitimately takes many minutes.
+var DefaultTimeouts = map[string]time.Duration{
+	"create":         30 * time.Minute,
+	"delete":         15 * time.Minute,
+	"start":          15 * time.Minute,
+	"stop":           10 * time.Minute,
+	"scale":          20 * time.Minute,
+	"update":         20 * time.Minute,
+	DefaultOperation: 2 * time.Minute,
+}
+
+// HungError is returned when the watchdog kills a command that outlived its timeout
+type HungError struct {
+	Command   string
+	Operation string
+	Timeout   time.Duration
+}
+
+func (e *HungError) Error() string {
+	return fmt.Sprintf("command hung for %v during %s and was killed: %s (raise the limit with %s=%s=<duration>)",
+		e.Timeout, e.Operation, e.Command, TimeoutsEnvVar, e.Operation)
+}
+
+type operationKey struct{}
+
+// WithOperation tags ctx so commands run with it use the timeout for op
+func WithOperation(ctx context.Context, op string) context.Context {
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+// OperationFrom returns the operation ctx was tagged with, or DefaultOperation
+func OperationFrom(ctx context.Context) string {
+	if op, ok := ctx.Value(operationKey{}).(string); ok && op != "" {
+		return op
+	}
+	return DefaultOperation
+}
+
+// TimeoutFor returns the timeout for op, honouring ATLAS_EXEC_TIMEOUTS. Unknown operations fall
+// back to the default timeout.
+func TimeoutFor(op string) time.Duration {
+	timeouts := TimeoutsFromEnv()
+	if timeout, ok := timeouts[op]; ok {
+		return timeout
+	}
+	return timeouts[DefaultOperation]
+}
+
+// TimeoutsFromEnv returns DefaultTimeouts with any overrides from ATLAS_EXEC_TIMEOUTS applied.
+// Malformed entries are ignored.
+func TimeoutsFromEnv() map[string]time.Duration {
+	timeouts := make(map[string]time.Duration, len(DefaultTimeouts))
+	for op, timeout := range DefaultTimeouts {
+		timeouts[op] = timeout
+	}
+	for _, entry := range strings.Split(os.Getenv(TimeoutsEnvVar), ",") {
+		op, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		if timeout, err := time.ParseDuration(strings.TrimSpace(value)); err == nil && timeout > 0 {
+			timeouts[strings.TrimSpace(op)] = timeout
+		}
+	}
+	return timeouts
+}
+
+// Cmd is an exec.Cmd guarded by the watchdog. Use it exactly like the exec.Cmd it embeds.
+type Cmd struct {
+	*exec.Cmd
+	// Timeout is how long the command may run before it is killed, set from the operation on ctx
+	Timeout   time.Duration
+	operation string
+	timer     *time.Timer
+	hung      atomic.Bool
+	// release drops the registration that kills the process group if Atlas exits first
+	release func()
+
+	transcript *Transcript
+	recording  *recording
+	replayed   *TranscriptEntry
+}
+
+// CommandContext is exec.CommandContext with the watchdog: the command runs in its own process
+// group, which is killed when ctx is done or the operation's timeout elapses.
+func CommandContext(ctx context.Context, name string, args ...string) *Cmd {
+	op := OperationFrom(ctx)
+	c := &Cmd{Cmd: exec.CommandContext(ctx, name, args...), Timeout: TimeoutFor(op), operation: op}
+	setProcessGroup(c.Cmd)
+	c.Cmd.Cancel = func() error { return killProcessGroup(c.Cmd) }
+	c.Cmd.WaitDelay = killGrace
+	return c
+}
+
+// String returns the command line, quoting arguments that contain spaces
+func (c *Cmd) String() string {
+	parts := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+			arg = strconv.Quote(arg)
+		}
+		parts[i] = arg
+	}
+	return strings.Join(parts, " ")
+}
+
+// Start starts the command and arms the watchdog. With a replay transcript active nothing is
+// run; the recorded entry is looked up instead.
+func (c *Cmd) Start() error {
+	c.transcript = activeTranscript.Load()
+	if c.transcript != nil && c.transcript.replay {
+		entry, err := c.transcript.take(c.Args)
+		if err != nil {
+			return err
+		}
+		c.replayed = entry
+		return nil
+	}
+	if c.transcript != nil {
+		c.recording = &recording{started: time.Now()}
+		if c.Stdout != nil && c.Stdout == c.Stderr {
+			// exec shares one pipe between identical writers, which keeps combined output in
+			// order; separate wrappers would split it across two racing pipes
+			c.Stdout = c.recording.writer("stdout", c.Stdout)
+			c.Stderr = c.Stdout
+		} else {
+			c.Stdout = c.recording.writer("stdout", c.Stdout)
+			c.Stderr = c.recording.writer("stderr", c.Stderr)
+		}
+	}
+
+	if err := c.Cmd.Start(); err != nil {
+		if c.recording != nil {
+			c.transcript.write(c.recording.entry(c, err))
+		}
+		return err
+	}
+	c.release = cleanup.Register(c.String(), func() error {
+		if err := killProcessGroup(c.Cmd); err != nil && !errors.Is(err, os.ErrProcessDone) {
+			return err
+		}
+		return nil
+	})
+	if c.Timeout > 0 {
+		c.timer = time.AfterFunc(c.Timeout, func() {
+			c.hung.Store(true)
+			killProcessGroup(c.Cmd)
+		})
+	}
+	return nil
+}
+
+// Wait waits for the command to exit, returning a *HungError if the watchdog killed it
+func (c *Cmd) Wait() error {
+	if c.replayed != nil {
+		return c.replay(c.replayed)
+	}
+
+	err := c.Cmd.Wait()
+	if c.release != nil {
+		c.release()
+	}
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	if c.hung.Load() {
+		err = &HungError{Command: c.String(), Operation: c.operation, Timeout: c.Timeout}
+	}
+	if c.recording != nil {
+		c.transcript.write(c.recording.entry(c, err))
+	}
+	return err
+}
+
+// Run starts the command and waits for it to finish
+func (c *Cmd) Run() error {
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Wait()
+}
+
+// Output runs the command and returns its standard output
+func (c *Cmd) Output() ([]byte, error) {
+	if c.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	captureStderr := c.Stderr == nil
+	if captureStderr {
+		c.Stderr = &stderr
+	}
+
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if captureStderr && errors.As(err, &exitErr) {
+		exitErr.Stderr = stderr.Bytes()
+	}
+	return stdout.Bytes(), err
+}
+
+// CombinedOutput runs the command and returns its combined standard output and error
+func (c *Cmd) CombinedOutput() ([]byte, error) {
+	if c.Stdout != nil || c.Stderr != nil {
+		return nil, errors.New("exec: Stdout or Stderr already set")
+	}
+	var output bytes.Buffer
+	c.Stdout = &output
+	c.Stderr = &output
+
+	err := c.Run()
+	return output.Bytes(), err
+}
@@ -0,0 +1,95 @@
+//go:build !windows
+
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/cleanup"
+)
+
+func TestCommandContext_KillsHungProcessGroup(t *testing.T) {
+	cmd := CommandContext(WithOperation(context.Background(), "stop"), "sh", "-c", "sleep 30 & sleep 30")
+	cmd.Timeout = 200 * time.Millisecond
+
+	start := time.Now()
+	_, err := cmd.CombinedOutput()
+
+	var hung *HungError
+	if !errors.As(err, &hung) {
+		t.Fatalf("CombinedOutput() error = %v, want *HungError", err)
+	}
+	if elapsed := time.Since(start); elapsed > killGrace {
+		t.Errorf("hung command took %v to return, want the group killed promptly", elapsed)
+	}
+	if hung.Operation != "stop" || hung.Command != `sh -c "sleep 30 & sleep 30"` {
+		t.Errorf("HungError = %+v", hung)
+	}
+	if !strings.Contains(err.Error(), TimeoutsEnvVar+"=stop=") {
+		t.Errorf("error %q should say how to raise the limit", err)
+	}
+}
+
+func TestCommandContext_KilledByCleanup(t *testing.T) {
+	cmd := CommandContext(context.Background(), "sh", "-c", "sleep 30 & sleep 30")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	start := time.Now()
+	if err := cleanup.Run(); err != nil {
+		t.Fatalf("cleanup.Run() error = %v", err)
+	}
+	if err := cmd.Wait(); err == nil {
+		t.Error("Wait() error = nil, want the killed command to fail")
+	}
+	if elapsed := time.Since(start); elapsed > killGrace {
+		t.Errorf("command took %v to exit after cleanup, want the group killed promptly", elapsed)
+	}
+
+	// a command that finished on its own is no longer registered
+	if err := CommandContext(context.Background(), "true").Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if err := cleanup.Run(); err != nil {
+		t.Errorf("cleanup.Run() after the command exited = %v, want nothing to clean up", err)
+	}
+}
+
+func TestCommandContext_FastCommand(t *testing.T) {
+	output, err := CommandContext(context.Background(), "echo", "ok").Output()
+	if err != nil {
+		t.Fatalf("Output() error = %v", err)
+	}
+	if strings.TrimSpace(string(output)) != "ok" {
+		t.Errorf("Output() = %q, want ok", output)
+	}
+}
+
+func TestTimeoutFor(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		op   string
+		want time.Duration
+	}{
+		{"default for operation", "", "create", DefaultTimeouts["create"]},
+		{"unknown operation uses default", "", "describe", DefaultTimeouts[DefaultOperation]},
+		{"env override", "create=45m, default=5m", "create", 45 * time.Minute},
+		{"env default override", "create=45m,default=5m", "describe", 5 * time.Minute},
+		{"malformed entries ignored", "create=soon,stop", "create", DefaultTimeouts["create"]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(TimeoutsEnvVar, tt.env)
+			if got := TimeoutFor(tt.op); got != tt.want {
+				t.Errorf("TimeoutFor(%q) = %v, want %v", tt.op, got, tt.want)
+			}
+		})
+	}
+}
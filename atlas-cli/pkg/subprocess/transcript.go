@@ -0,0 +1,286 @@
+package subprocess
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Chunk is one write a command made to stdout or stderr, kept in order so interleaved
+// combined output replays exactly as it was produced
+type Chunk struct {
+	Stream string `json:"stream"`
+	Data   string `json:"data"`
+}
+
+// TranscriptEntry is one recorded command and everything it printed
+type TranscriptEntry struct {
+	Args       []string `json:"args"`
+	Operation  string   `json:"operation,omitempty"`
+	ExitCode   int      `json:"exit_code"`
+	NotFound   bool     `json:"not_found,omitempty"`
+	Hung       bool     `json:"hung,omitempty"`
+	Error      string   `json:"error,omitempty"`
+	DurationMS int64    `json:"duration_ms"`
+	Output     []Chunk  `json:"output,omitempty"`
+}
+
+// Transcript records every command run through this package to a session file, one JSON entry
+// per line, or replays a recorded session instead of running anything. Secrets and the home
+// directory are scrubbed before they are written.
+type Transcript struct {
+	mu       sync.Mutex
+	replay   bool
+	file     *os.File
+	entries  []*TranscriptEntry
+	consumed []bool
+}
+
+var activeTranscript atomic.Pointer[Transcript]
+
+// UseTranscript routes every command through t; nil restores normal execution
+func UseTranscript(t *Transcript) {
+	activeTranscript.Store(t)
+}
+
+// Replaying reports whether commands are being answered from a replay transcript
+func Replaying() bool {
+	t := activeTranscript.Load()
+	return t != nil && t.replay
+}
+
+// RecordTo starts a transcript that appends each command to path as it finishes
+func RecordTo(path string) (*Transcript, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcript: %w", err)
+	}
+	return &Transcript{file: file}, nil
+}
+
+// ReplayFrom loads a recorded transcript. Commands are answered from the first unused entry
+// with the same arguments, so a session replays faithfully even when it repeats a command.
+func ReplayFrom(path string) (*Transcript, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer file.Close()
+
+	t := &Transcript{replay: true}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry TranscriptEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse transcript line %d: %w", line, err)
+		}
+		t.entries = append(t.entries, &entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	t.consumed = make([]bool, len(t.entries))
+	return t, nil
+}
+
+// Close flushes and closes a recording transcript
+func (t *Transcript) Close() error {
+	if t.file == nil {
+		return nil
+	}
+	return t.file.Close()
+}
+
+func (t *Transcript) write(entry *TranscriptEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.file.Write(append(data, '\n'))
+}
+
+// take returns the first unconsumed entry recorded for args
+func (t *Transcript) take(args []string) (*TranscriptEntry, error) {
+	scrubbed := scrubArgs(args)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, entry := range t.entries {
+		if !t.consumed[i] && slices.Equal(entry.Args, scrubbed) {
+			t.consumed[i] = true
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("transcript has no recorded output for: %s", strings.Join(scrubbed, " "))
+}
+
+// ReplayExitError reports the non-zero exit status of a replayed command
+type ReplayExitError struct {
+	Code int
+}
+
+func (e *ReplayExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// ExitCode returns the recorded exit status, matching exec.ExitError
+func (e *ReplayExitError) ExitCode() int {
+	return e.Code
+}
+
+// replay writes the recorded output of entry to the command's writers and returns the error the
+// original run produced
+func (c *Cmd) replay(entry *TranscriptEntry) error {
+	for _, chunk := range entry.Output {
+		w := c.Stdout
+		if chunk.Stream == "stderr" {
+			w = c.Stderr
+		}
+		if w != nil {
+			io.WriteString(w, chunk.Data)
+		}
+	}
+
+	switch {
+	case entry.NotFound:
+		return &exec.Error{Name: c.Args[0], Err: exec.ErrNotFound}
+	case entry.Hung:
+		return &HungError{Command: c.String(), Operation: c.operation, Timeout: c.Timeout}
+	case entry.Error != "":
+		return errors.New(entry.Error)
+	case entry.ExitCode != 0:
+		return &ReplayExitError{Code: entry.ExitCode}
+	}
+	return nil
+}
+
+// recording captures a running command's output for its transcript entry
+type recording struct {
+	mu      sync.Mutex
+	started time.Time
+	chunks  []Chunk
+}
+
+func (r *recording) writer(stream string, w io.Writer) io.Writer {
+	return &chunkWriter{recording: r, stream: stream, w: w}
+}
+
+type chunkWriter struct {
+	recording *recording
+	stream    string
+	w         io.Writer
+}
+
+// Write records p and passes it on. The lock is held across the underlying write because
+// CombinedOutput hands the same buffer to both streams.
+func (c *chunkWriter) Write(p []byte) (int, error) {
+	r := c.recording
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n := len(r.chunks); n > 0 && r.chunks[n-1].Stream == c.stream {
+		r.chunks[n-1].Data += string(p)
+	} else {
+		r.chunks = append(r.chunks, Chunk{Stream: c.stream, Data: string(p)})
+	}
+
+	if c.w == nil {
+		return len(p), nil
+	}
+	return c.w.Write(p)
+}
+
+// entry builds the scrubbed transcript entry for a finished command
+func (r *recording) entry(c *Cmd, err error) *TranscriptEntry {
+	entry := &TranscriptEntry{
+		Args:       scrubArgs(c.Args),
+		Operation:  c.operation,
+		DurationMS: time.Since(r.started).Milliseconds(),
+	}
+	r.mu.Lock()
+	for _, chunk := range r.chunks {
+		entry.Output = append(entry.Output, Chunk{Stream: chunk.Stream, Data: scrub(chunk.Data)})
+	}
+	r.mu.Unlock()
+
+	var hung *HungError
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.Is(err, exec.ErrNotFound):
+		entry.NotFound = true
+	case errors.As(err, &hung):
+		entry.Hung = true
+	case errors.As(err, &exitErr):
+		entry.ExitCode = exitErr.ExitCode()
+	default:
+		entry.Error = scrub(err.Error())
+	}
+	return entry
+}
+
+var secretPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\b(AKIA|ASIA|A3T[A-Z0-9])[A-Z0-9]{16}\b`), "${1}REDACTED"},
+	{regexp.MustCompile(`(?i)("(?:SecretAccessKey|AccessKeyId|[a-z_]*token|password|secret|client-key-data|client-certificate-data)"\s*:\s*)"[^"]*"`), `$1"REDACTED"`},
+	{regexp.MustCompile(`(?i)((?:token|password|secret|client-key-data|client-certificate-data)\s*[:=]\s*)\S+`), "${1}REDACTED"},
+	{regexp.MustCompile(`(?i)(bearer\s+)\S+`), "${1}REDACTED"},
+	{regexp.MustCompile(`(arn:aws[a-z-]*:[a-z0-9-]*:[a-z0-9-]*:)\d{12}`), "${1}000000000000"},
+	{regexp.MustCompile(`("Account"\s*:\s*)"\d{12}"`), `$1"000000000000"`},
+}
+
+var (
+	secretValuesMu sync.Mutex
+	secretValues   []string
+)
+
+// RegisterSecret makes transcripts redact value wherever it appears, e.g. a token resolved from a
+// secret backend. Values shorter than four characters are ignored since they'd redact too much.
+func RegisterSecret(value string) {
+	if len(value) < 4 {
+		return
+	}
+	secretValuesMu.Lock()
+	defer secretValuesMu.Unlock()
+	secretValues = append(secretValues, value)
+}
+
+// scrub removes credentials, AWS account IDs and the user's home directory from s
+func scrub(s string) string {
+	secretValuesMu.Lock()
+	for _, value := range secretValues {
+		s = strings.ReplaceAll(s, value, "REDACTED")
+	}
+	secretValuesMu.Unlock()
+	for _, secret := range secretPatterns {
+		s = secret.pattern.ReplaceAllString(s, secret.replacement)
+	}
+	if home, err := os.UserHomeDir(); err == nil && len(home) > 1 {
+		s = strings.ReplaceAll(s, home, "$HOME")
+	}
+	return s
+}
+
+func scrubArgs(args []string) []string {
+	scrubbed := make([]string, len(args))
+	for i, arg := range args {
+		scrubbed[i] = scrub(arg)
+	}
+	return scrubbed
+}
@@ -0,0 +1,107 @@
+package timings
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+)
+
+// DurationStats summarizes the durations of a group of operations or phases. The percentiles
+// only cover entries with a known duration.
+type DurationStats struct {
+	Name   string  `json:"name"`
+	Count  int     `json:"count"`
+	Failed int     `json:"failed,omitempty"`
+	P50MS  float64 `json:"p50_ms"`
+	P95MS  float64 `json:"p95_ms"`
+	MaxMS  float64 `json:"max_ms"`
+	// SLOBreaches counts the times the phase took longer than its objective
+	SLOBreaches int `json:"slo_breaches,omitempty"`
+
+	durations []float64
+}
+
+// Stats summarizes operation history: durations by operation type, durations of each phase of
+// the operations with recorded phase timings, and how often budgets were exceeded
+type Stats struct {
+	Operations     []*DurationStats `json:"operations"`
+	Phases         []*DurationStats `json:"phases"`
+	Budgeted       int              `json:"budgeted"`
+	BudgetExceeded int              `json:"budget_exceeded"`
+}
+
+// Analyze computes Stats for ops, whose phase timings must already have been added with Apply
+func Analyze(ops []*logsource.OperationHistory) *Stats {
+	operations := make(map[string]*DurationStats)
+	phases := make(map[string]*DurationStats)
+	group := func(groups map[string]*DurationStats, name string) *DurationStats {
+		if groups[name] == nil {
+			groups[name] = &DurationStats{Name: name}
+		}
+		return groups[name]
+	}
+
+	stats := &Stats{}
+	for _, op := range ops {
+		opStats := group(operations, string(op.OperationType))
+		opStats.Count++
+		if op.DurationMS != nil {
+			opStats.durations = append(opStats.durations, *op.DurationMS)
+		}
+		if op.OperationStatus == logsource.OpStatusFailed {
+			opStats.Failed++
+		}
+
+		timed, _ := op.OperationDetails[DetailPhases].(map[string]interface{})
+		for name, value := range timed {
+			phaseStats := group(phases, name)
+			phaseStats.Count++
+			if ms, ok := value.(int64); ok {
+				phaseStats.durations = append(phaseStats.durations, float64(ms))
+			}
+		}
+		breaches, _ := op.OperationDetails[DetailSLOBreaches].([]string)
+		for _, name := range breaches {
+			group(phases, name).SLOBreaches++
+		}
+		if _, ok := op.OperationDetails[DetailBudget]; ok {
+			stats.Budgeted++
+			if exceeded, _ := op.OperationDetails[DetailBudgetExceeded].(bool); exceeded {
+				stats.BudgetExceeded++
+			}
+		}
+	}
+
+	stats.Operations = finish(operations)
+	stats.Phases = finish(phases)
+	return stats
+}
+
+// finish computes each group's percentiles and returns the groups sorted by name
+func finish(groups map[string]*DurationStats) []*DurationStats {
+	result := make([]*DurationStats, 0, len(groups))
+	for _, stats := range groups {
+		sort.Float64s(stats.durations)
+		stats.P50MS = percentile(stats.durations, 50)
+		stats.P95MS = percentile(stats.durations, 95)
+		if n := len(stats.durations); n > 0 {
+			stats.MaxMS = stats.durations[n-1]
+		}
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// percentile returns the nearest-rank percentile p of sorted, or 0 when it is empty
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
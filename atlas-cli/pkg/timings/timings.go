@@ -0,0 +1,193 @@
+// Package timings measures how long each phase of a cluster operation takes and keeps the
+// results, so they can be shown with the operation in history and analysed across operations.
+package timings
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/internal/store"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+)
+
+// Operation detail keys Apply sets
+const (
+	DetailPhases         = "phase_timings_ms"
+	DetailBudget         = "budget_ms"
+	DetailBudgetExceeded = "budget_exceeded"
+	DetailSLOBreaches    = "slo_breaches"
+)
+
+// matchWindow is how far apart a record and a logged operation may start and still be the same
+// operation; providers log the start of their own command, after Atlas's preflight checks
+const matchWindow = 5 * time.Minute
+
+// maxRecords caps the store; the oldest records are dropped first
+const maxRecords = 1000
+
+// Phase is how long one phase of an operation took
+type Phase struct {
+	Name       string          `json:"name"`
+	DurationMS int64           `json:"duration_ms"`
+	Status     progress.Status `json:"status"`
+}
+
+// Record is the phase timings of one operation on a cluster
+type Record struct {
+	Cluster   string    `json:"cluster"`
+	Provider  string    `json:"provider"`
+	Operation string    `json:"operation"`
+	StartedAt time.Time `json:"started_at"`
+	Phases    []Phase   `json:"phases"`
+	// BudgetMS is the time the operation was allowed, zero when it had no budget
+	BudgetMS       int64 `json:"budget_ms,omitempty"`
+	BudgetExceeded bool  `json:"budget_exceeded,omitempty"`
+	// SLOBreaches lists the phases that took longer than their objective
+	SLOBreaches []string `json:"slo_breaches,omitempty"`
+}
+
+// Recorder is a progress reporter that times each phase from its started event to the event that
+// ends it, passing every event on. It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	next    progress.Reporter
+	now     func() time.Time
+	started map[string]time.Time
+	current string
+	phases  []Phase
+}
+
+// NewRecorder creates a recorder that forwards events to next
+func NewRecorder(next progress.Reporter) *Recorder {
+	return &Recorder{next: next, now: time.Now, started: make(map[string]time.Time)}
+}
+
+// Report records the event's phase transition and forwards it
+func (r *Recorder) Report(event progress.Event) {
+	r.mu.Lock()
+	if event.Phase != "" {
+		switch event.Status {
+		case progress.StatusStarted:
+			r.started[event.Phase] = r.now()
+			r.current = event.Phase
+		case progress.StatusCompleted, progress.StatusFailed:
+			if started, ok := r.started[event.Phase]; ok {
+				r.phases = append(r.phases, Phase{Name: event.Phase, DurationMS: r.now().Sub(started).Milliseconds(), Status: event.Status})
+				delete(r.started, event.Phase)
+				if r.current == event.Phase && event.Status == progress.StatusCompleted {
+					r.current = ""
+				}
+			}
+		}
+	}
+	r.mu.Unlock()
+	r.next.Report(event)
+}
+
+// Current returns the phase that started last unless it completed, so after a failure it is the
+// phase that failed, and "" between phases
+func (r *Recorder) Current() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Phases returns the phases that have ended, in the order they ended. Phases still running are
+// included with their duration so far and no status.
+func (r *Recorder) Phases() []Phase {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	phases := append([]Phase(nil), r.phases...)
+	for name, started := range r.started {
+		phases = append(phases, Phase{Name: name, DurationMS: r.now().Sub(started).Milliseconds()})
+	}
+	return phases
+}
+
+// SLOBreaches returns the names of the phases that took longer than their objective in slos
+func SLOBreaches(phases []Phase, slos map[string]time.Duration) []string {
+	var breaches []string
+	for _, phase := range phases {
+		if slo, ok := slos[phase.Name]; ok && time.Duration(phase.DurationMS)*time.Millisecond > slo {
+			breaches = append(breaches, phase.Name)
+		}
+	}
+	return breaches
+}
+
+// DefaultStorePath returns the location of the phase timings file
+func DefaultStorePath() (string, error) {
+	return store.Path("phase-timings.json")
+}
+
+// Store holds phase timing records on disk
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	records []*Record
+}
+
+// LoadStore reads the records at path; a missing file starts empty
+func LoadStore(path string) (*Store, error) {
+	s := &Store{path: path}
+	if err := store.LoadJSON(path, &s.records); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Add appends a record, dropping the oldest beyond maxRecords
+func (s *Store) Add(record *Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	if len(s.records) > maxRecords {
+		s.records = s.records[len(s.records)-maxRecords:]
+	}
+}
+
+// Apply adds the phase timings of each operation to its details. An operation gets the record
+// of the same cluster and operation type that started closest to it, within matchWindow.
+func (s *Store) Apply(ops []*logsource.OperationHistory) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, op := range ops {
+		var match *Record
+		var best time.Duration
+		for _, record := range s.records {
+			if record.Cluster != op.ClusterName || record.Operation != string(op.OperationType) {
+				continue
+			}
+			gap := op.StartedAt.Sub(record.StartedAt).Abs()
+			if gap <= matchWindow && (match == nil || gap < best) {
+				match, best = record, gap
+			}
+		}
+		if match == nil {
+			continue
+		}
+		phases := make(map[string]interface{}, len(match.Phases))
+		for _, phase := range match.Phases {
+			phases[phase.Name] = phase.DurationMS
+		}
+		if op.OperationDetails == nil {
+			op.OperationDetails = make(map[string]interface{})
+		}
+		op.OperationDetails[DetailPhases] = phases
+		if match.BudgetMS > 0 {
+			op.OperationDetails[DetailBudget] = match.BudgetMS
+			op.OperationDetails[DetailBudgetExceeded] = match.BudgetExceeded
+		}
+		if len(match.SLOBreaches) > 0 {
+			op.OperationDetails[DetailSLOBreaches] = match.SLOBreaches
+		}
+	}
+}
+
+// Save writes the records back to disk
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return store.SaveJSON(s.path, s.records)
+}
@@ -0,0 +1,101 @@
+package timings
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/logsource"
+	"github.com/ryanjwong/Atlas/atlas-cli/pkg/progress"
+)
+
+func TestRecorder(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	var forwarded int
+	r := NewRecorder(progress.ReporterFunc(func(progress.Event) { forwarded++ }))
+	r.now = func() time.Time { return now }
+
+	r.Report(progress.Event{Phase: "provision", Status: progress.StatusStarted})
+	now = now.Add(3 * time.Second)
+	r.Report(progress.Event{Phase: "provision", Status: progress.StatusCompleted})
+	if r.Current() != "" {
+		t.Errorf("Current() = %q after the phase completed, want none", r.Current())
+	}
+	r.Report(progress.Event{Phase: "done", Status: progress.StatusCompleted})
+	r.Report(progress.Event{Phase: "configure", Status: progress.StatusStarted})
+	now = now.Add(time.Second)
+	r.Report(progress.Event{Phase: "configure", Status: progress.StatusFailed})
+
+	want := []Phase{
+		{Name: "provision", DurationMS: 3000, Status: progress.StatusCompleted},
+		{Name: "configure", DurationMS: 1000, Status: progress.StatusFailed},
+	}
+	if got := r.Phases(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Phases() = %+v, want %+v", got, want)
+	}
+	if r.Current() != "configure" {
+		t.Errorf("Current() = %q, want the failed phase", r.Current())
+	}
+	if forwarded != 5 {
+		t.Errorf("forwarded %d events, want 5", forwarded)
+	}
+	if got := SLOBreaches(want, map[string]time.Duration{"provision": 2 * time.Second, "configure": time.Minute}); !reflect.DeepEqual(got, []string{"provision"}) {
+		t.Errorf("SLOBreaches() = %v, want provision", got)
+	}
+}
+
+func TestStoreApplyAndAnalyze(t *testing.T) {
+	started := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "phase-timings.json")
+	store, err := LoadStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Add(&Record{Cluster: "dev", Operation: "create", StartedAt: started, BudgetMS: 60000, BudgetExceeded: true,
+		Phases: []Phase{{Name: "provision", DurationMS: 60000}}, SLOBreaches: []string{"provision"}})
+	store.Add(&Record{Cluster: "dev", Operation: "create", StartedAt: started.Add(time.Hour),
+		Phases: []Phase{{Name: "provision", DurationMS: 20000}, {Name: "configure", DurationMS: 5000}}})
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+	store, err = LoadStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := func(v float64) *float64 { return &v }
+	ops := []*logsource.OperationHistory{
+		// the provider logs its own start a little after Atlas began timing
+		{ClusterName: "dev", OperationType: logsource.OpTypeCreate, OperationStatus: logsource.OpStatusFailed, StartedAt: started.Add(10 * time.Second), DurationMS: ms(61000)},
+		{ClusterName: "dev", OperationType: logsource.OpTypeCreate, OperationStatus: logsource.OpStatusCompleted, StartedAt: started.Add(time.Hour + 5*time.Second), DurationMS: ms(26000)},
+		{ClusterName: "dev", OperationType: logsource.OpTypeCreate, StartedAt: started.Add(3 * time.Hour), DurationMS: ms(30000)},
+		{ClusterName: "other", OperationType: logsource.OpTypeCreate, StartedAt: started},
+		{ClusterName: "dev", OperationType: logsource.OpTypeStop, StartedAt: started.Add(time.Hour), DurationMS: ms(4000)},
+	}
+	store.Apply(ops)
+	if got := ops[0].OperationDetails; got[DetailBudgetExceeded] != true || !reflect.DeepEqual(got[DetailPhases], map[string]interface{}{"provision": int64(60000)}) {
+		t.Errorf("Apply() first create details = %v", got)
+	}
+	if _, ok := ops[1].OperationDetails[DetailBudget]; ok || len(ops[1].OperationDetails[DetailPhases].(map[string]interface{})) != 2 {
+		t.Errorf("Apply() second create details = %v, want two phases and no budget", ops[1].OperationDetails)
+	}
+	for _, op := range ops[2:] {
+		if op.OperationDetails != nil {
+			t.Errorf("Apply() matched %s of %s at %s, want no record", op.OperationType, op.ClusterName, op.StartedAt)
+		}
+	}
+
+	stats := Analyze(ops)
+	if stats.Budgeted != 1 || stats.BudgetExceeded != 1 {
+		t.Errorf("Analyze() budgets = %d exceeded of %d, want 1 of 1", stats.BudgetExceeded, stats.Budgeted)
+	}
+	create := stats.Operations[0]
+	if create.Name != "create" || create.Count != 4 || create.Failed != 1 || create.P50MS != 30000 || create.MaxMS != 61000 {
+		t.Errorf("Analyze() create stats = %+v", create)
+	}
+	provision := stats.Phases[1]
+	if provision.Name != "provision" || provision.Count != 2 || provision.P50MS != 20000 || provision.P95MS != 60000 || provision.SLOBreaches != 1 {
+		t.Errorf("Analyze() provision stats = %+v", provision)
+	}
+}
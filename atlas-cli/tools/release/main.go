@@ -0,0 +1,262 @@
+// Command release builds versioned atlas-cli binaries for every supported platform, stamps them
+// with build metadata, and writes checksums, a CycloneDX SBOM and optional Ed25519 signatures.
+//
+// Run it from the atlas-cli module root:
+//
+//	go run ./tools/release -version 1.2.0 -sign-key release.key
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"debug/buildinfo"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const modulePath = "github.com/ryanjwong/Atlas/atlas-cli"
+
+const defaultPlatforms = "linux/amd64,linux/arm64,darwin/amd64,darwin/arm64,windows/amd64,windows/arm64"
+
+type buildInfo struct {
+	Version string
+	Commit  string
+	Date    string
+}
+
+func main() {
+	version := flag.String("version", "", "Release version, e.g. 1.2.0 (required)")
+	outDir := flag.String("out", "dist", "Directory to write artifacts to")
+	platforms := flag.String("platforms", defaultPlatforms, "Comma-separated GOOS/GOARCH pairs to build")
+	signKey := flag.String("sign-key", "", "PEM-encoded PKCS#8 Ed25519 private key used to sign artifacts")
+	flag.Parse()
+
+	if *version == "" {
+		fmt.Fprintln(os.Stderr, "release: -version is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := release(*version, *outDir, strings.Split(*platforms, ","), *signKey); err != nil {
+		fmt.Fprintf(os.Stderr, "release: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func release(version, outDir string, platforms []string, signKey string) error {
+	var key ed25519.PrivateKey
+	if signKey != "" {
+		var err error
+		if key, err = loadSigningKey(signKey); err != nil {
+			return err
+		}
+	}
+
+	info := buildInfo{Version: strings.TrimPrefix(version, "v"), Commit: gitCommit(), Date: buildDate()}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	var artifacts []string
+	for _, platform := range platforms {
+		goos, goarch, ok := strings.Cut(strings.TrimSpace(platform), "/")
+		if !ok {
+			return fmt.Errorf("invalid platform %q, want GOOS/GOARCH", platform)
+		}
+		artifact, err := build(info, outDir, goos, goarch)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("built %s\n", artifact)
+		artifacts = append(artifacts, artifact)
+	}
+
+	checksums := filepath.Join(outDir, "checksums.txt")
+	if err := writeChecksums(checksums, artifacts); err != nil {
+		return err
+	}
+	sbom := filepath.Join(outDir, "sbom.cdx.json")
+	if err := writeSBOM(sbom, artifacts[0], info); err != nil {
+		return err
+	}
+
+	if key != nil {
+		for _, path := range append(artifacts, checksums, sbom) {
+			if err := signFile(path, key); err != nil {
+				return err
+			}
+		}
+		fmt.Println("signed artifacts")
+	}
+
+	fmt.Printf("release %s written to %s\n", info.Version, outDir)
+	return nil
+}
+
+// build cross-compiles a static binary for goos/goarch with the build metadata stamped in
+func build(info buildInfo, outDir, goos, goarch string) (string, error) {
+	name := fmt.Sprintf("atlas-cli_%s_%s_%s", info.Version, goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	output := filepath.Join(outDir, name)
+
+	ldflags := strings.Join([]string{
+		"-s", "-w",
+		"-X", modulePath + "/cmd.version=" + info.Version,
+		"-X", modulePath + "/cmd.commit=" + info.Commit,
+		"-X", modulePath + "/cmd.buildDate=" + info.Date,
+	}, " ")
+
+	cmd := exec.Command("go", "build", "-trimpath", "-ldflags", ldflags, "-o", output, ".")
+	cmd.Env = append(os.Environ(), "GOOS="+goos, "GOARCH="+goarch, "CGO_ENABLED=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to build %s/%s: %w\n%s", goos, goarch, err, out)
+	}
+	return output, nil
+}
+
+// gitCommit returns the short commit hash, marked dirty when the tree has local changes
+func gitCommit() string {
+	out, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return "unknown"
+	}
+	commit := strings.TrimSpace(string(out))
+	if status, err := exec.Command("git", "status", "--porcelain").Output(); err == nil && len(status) > 0 {
+		commit += "-dirty"
+	}
+	return commit
+}
+
+// buildDate honours SOURCE_DATE_EPOCH so rebuilding a tagged commit yields identical binaries
+func buildDate() string {
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		if seconds, err := strconv.ParseInt(epoch, 10, 64); err == nil {
+			return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+		}
+	}
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// writeChecksums writes sha256sum-compatible lines for each artifact
+func writeChecksums(path string, artifacts []string) error {
+	var lines []string
+	for _, artifact := range artifacts {
+		sum, err := sha256File(artifact)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, fmt.Sprintf("%s  %s", sum, filepath.Base(artifact)))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write checksums: %w", err)
+	}
+	return nil
+}
+
+func sha256File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+type cycloneDXComponent struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	PURL    string `json:"purl"`
+}
+
+// writeSBOM records the modules actually linked into binary as a CycloneDX document
+func writeSBOM(path, binary string, info buildInfo) error {
+	bi, err := buildinfo.ReadFile(binary)
+	if err != nil {
+		return fmt.Errorf("failed to read build info from %s: %w", binary, err)
+	}
+
+	var components []cycloneDXComponent
+	components = append(components, cycloneDXComponent{Type: "library", Name: "golang.org/toolchain", Version: bi.GoVersion,
+		PURL: "pkg:golang/golang.org/toolchain@" + bi.GoVersion})
+	for _, dep := range bi.Deps {
+		module := dep
+		if dep.Replace != nil {
+			module = dep.Replace
+		}
+		components = append(components, cycloneDXComponent{Type: "library", Name: module.Path, Version: module.Version,
+			PURL: fmt.Sprintf("pkg:golang/%s@%s", module.Path, module.Version)})
+	}
+
+	doc := map[string]any{
+		"bomFormat":   "CycloneDX",
+		"specVersion": "1.5",
+		"version":     1,
+		"metadata": map[string]any{
+			"timestamp": info.Date,
+			"component": cycloneDXComponent{Type: "application", Name: modulePath, Version: info.Version,
+				PURL: fmt.Sprintf("pkg:golang/%s@v%s", modulePath, info.Version)},
+		},
+		"components": components,
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode SBOM: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write SBOM: %w", err)
+	}
+	return nil
+}
+
+func loadSigningKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not PEM encoded", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key must be Ed25519, got %T", parsed)
+	}
+	return key, nil
+}
+
+// signFile writes path.sig holding the base64 Ed25519 signature of the file's contents
+func signFile(path string, key ed25519.PrivateKey) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	if err := os.WriteFile(path+".sig", []byte(signature+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write signature for %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// coverageThresholds is the minimum statement coverage, in percent, each package must reach
+// when the runner is invoked with -coverage. Packages not listed are reported but never fail.
+var coverageThresholds = map[string]float64{
+	"./cmd":            50,
+	"./pkg/metrics":    90,
+	"./pkg/operations": 65,
+	"./pkg/progress":   90,
+	"./pkg/providers":  15,
+	"./pkg/schema":     75,
+}
+
+// SuiteRun is the outcome of running one suite
+type SuiteRun struct {
+	Suite    TestSuite
+	Results  []TestResult
+	Duration time.Duration
+	// Coverage is the statement coverage percentage, or -1 when coverage was not collected
+	Coverage float64
+	// Output is the combined go test output of every attempt
+	Output string
+}
+
+var coverageLine = regexp.MustCompile(`coverage: ([0-9.]+)% of statements`)
+
+// parseCoverage extracts the percentage from go test's coverage summary line
+func parseCoverage(output string) float64 {
+	match := coverageLine.FindStringSubmatch(output)
+	if match == nil {
+		return -1
+	}
+	percent, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return -1
+	}
+	return percent
+}
+
+// coverProfilePath names the profile for a suite after its package and name so suites sharing
+// a package don't overwrite each other
+func coverProfilePath(dir string, suite TestSuite) string {
+	slug := strings.NewReplacer(" ", "-", "/", "_", ".", "").Replace(strings.ToLower(suite.Name))
+	return filepath.Join(dir, slug+".out")
+}
+
+// checkCoverage returns one message per package whose best suite coverage is below its threshold
+func checkCoverage(runs []SuiteRun) []string {
+	best := make(map[string]float64)
+	for _, run := range runs {
+		if run.Coverage < 0 {
+			continue
+		}
+		if current, seen := best[run.Suite.Package]; !seen || run.Coverage > current {
+			best[run.Suite.Package] = run.Coverage
+		}
+	}
+
+	var failures []string
+	for pkg, coverage := range best {
+		if threshold, ok := coverageThresholds[pkg]; ok && coverage < threshold {
+			failures = append(failures, fmt.Sprintf("%s: %.1f%% coverage is below the %.0f%% threshold", pkg, coverage, threshold))
+		}
+	}
+	sort.Strings(failures)
+	return failures
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Package    string          `xml:"package,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+// writeJUnit writes the runs as a JUnit XML report, one testsuite element per suite
+func writeJUnit(path string, runs []SuiteRun) error {
+	report := junitTestSuites{}
+	var total time.Duration
+	for _, run := range runs {
+		suite := junitTestSuite{Name: run.Suite.Name, Package: run.Suite.Package, Time: seconds(run.Duration)}
+		if run.Coverage >= 0 {
+			suite.Properties = append(suite.Properties, junitProperty{Name: "coverage", Value: strconv.FormatFloat(run.Coverage, 'f', 1, 64)})
+		}
+		for _, result := range run.Results {
+			testCase := junitTestCase{Name: result.Test, Classname: run.Suite.Package, Time: seconds(result.Duration)}
+			switch {
+			case result.Status == "FAIL" && result.Quarantined:
+				testCase.Skipped = &junitSkipped{Message: "quarantined: " + result.Error}
+				testCase.SystemOut = result.Output
+				suite.Skipped++
+			case result.Status == "FAIL":
+				testCase.Failure = &junitFailure{Message: result.Error, Contents: result.Output}
+				suite.Failures++
+			case result.Status == "SKIP":
+				testCase.Skipped = &junitSkipped{Message: result.Error}
+				suite.Skipped++
+			case result.Attempts > 1:
+				testCase.SystemOut = fmt.Sprintf("flaky: passed on attempt %d\n%s", result.Attempts, result.Output)
+			default:
+				testCase.SystemOut = result.Output
+			}
+			suite.Cases = append(suite.Cases, testCase)
+		}
+		suite.Tests = len(suite.Cases)
+
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+		total += run.Duration
+		report.Suites = append(report.Suites, suite)
+	}
+	report.Time = seconds(total)
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JUnit report: %w", err)
+	}
+	return writeReport(path, append([]byte(xml.Header), data...))
+}
+
+type jsonResult struct {
+	Test            string  `json:"test"`
+	Status          string  `json:"status"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Attempts        int     `json:"attempts"`
+	Flaky           bool    `json:"flaky,omitempty"`
+	Quarantined     bool    `json:"quarantined,omitempty"`
+	Error           string  `json:"error,omitempty"`
+	Output          string  `json:"output,omitempty"`
+}
+
+type jsonSuite struct {
+	Name              string       `json:"name"`
+	Package           string       `json:"package"`
+	Tags              []string     `json:"tags,omitempty"`
+	DurationSeconds   float64      `json:"duration_seconds"`
+	Coverage          *float64     `json:"coverage,omitempty"`
+	CoverageThreshold *float64     `json:"coverage_threshold,omitempty"`
+	Results           []jsonResult `json:"results"`
+}
+
+// writeJSON writes the runs as a JSON document for tooling that doesn't speak JUnit
+func writeJSON(path string, runs []SuiteRun) error {
+	suites := make([]jsonSuite, 0, len(runs))
+	for _, run := range runs {
+		suite := jsonSuite{
+			Name:            run.Suite.Name,
+			Package:         run.Suite.Package,
+			Tags:            run.Suite.Tags,
+			DurationSeconds: run.Duration.Seconds(),
+			Results:         []jsonResult{},
+		}
+		if run.Coverage >= 0 {
+			coverage := run.Coverage
+			suite.Coverage = &coverage
+			if threshold, ok := coverageThresholds[run.Suite.Package]; ok {
+				suite.CoverageThreshold = &threshold
+			}
+		}
+		for _, result := range run.Results {
+			suite.Results = append(suite.Results, jsonResult{
+				Test:            result.Test,
+				Status:          result.Status,
+				DurationSeconds: result.Duration.Seconds(),
+				Attempts:        result.Attempts,
+				Flaky:           result.Status == "PASS" && result.Attempts > 1,
+				Quarantined:     result.Quarantined,
+				Error:           result.Error,
+				Output:          result.Output,
+			})
+		}
+		suites = append(suites, suite)
+	}
+
+	data, err := json.MarshalIndent(map[string]any{"suites": suites}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON report: %w", err)
+	}
+	return writeReport(path, append(data, '\n'))
+}
+
+func writeReport(path string, data []byte) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create report directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"runtime"
@@ -15,6 +18,10 @@ type TestSuite struct {
 	Package     string
 	Description string
 	Tests       []string
+	// Tags select the suite with -tags; a suite runs when it has any of the requested tags
+	Tags        []string
+	// Budget is the wall-clock limit for the suite including retries, defaultSuiteBudget when zero
+	Budget      time.Duration
 }
 
 type TestResult struct {
@@ -24,8 +31,21 @@ type TestResult struct {
 	Duration  time.Duration
 	Output    string
 	Error     string
+	// Attempts is how many runs it took to reach Status; more than one means the test is flaky
+	Attempts    int
+	// Quarantined tests are reported but their failures don't fail the run
+	Quarantined bool
 }
 
+const (
+	defaultSuiteBudget = 5 * time.Minute
+	// budgetGrace is how long past its budget a go test process gets before it is killed
+	budgetGrace = 30 * time.Second
+)
+
+// quarantinedTests maps known-flaky top-level test names to the reason they are quarantined
+var quarantinedTests = map[string]string{}
+
 var testSuites = []TestSuite{
 	{
 		Name:        "Provider Validation Tests",
@@ -36,8 +56,75 @@ var testSuites = []TestSuite{
 			"TestLocalProvider_GetProviderName",
 			"TestLocalProvider_GetSupportedRegions",
 			"TestLocalProvider_GetSupportedVersions",
+			"TestLocalProvider_ForceDeleteCluster",
+			"TestLocalProvider_WaitForDeletion",
+			"TestAWSProvider_WaitForDeletion",
 			"TestNetworkConfigValidation",
+			"TestParseMemoryMB",
+			"TestCheckPortAvailable",
+			"TestCheckHostResources",
+			"TestValidateBootstrapManifests",
+			"TestParseAppliedResources",
+			"TestInstanceVCPUs",
+			"TestEvaluateQuotas",
+			"TestResolveTags",
+			"TestFormatTags",
+			"TestInstanceHourlyPrice",
+			"TestParseMinikubeAddons",
+			"TestPresetConfig",
+			"TestFakeProvider_Lifecycle",
+			"TestFakeProvider_FailureInjection",
+			"TestFakeProvider_LatencyHonoursCancellation",
+			"TestFakeOptionsFromEnv",
+			"TestToolCache",
+			"TestParseToolVersion",
+			"TestAWSProvider_Validate",
+			"TestAWSProvider_ValidateAWSSection",
+			"TestValidationResult_Err",
+			"TestValidate_PlainProvider",
+			"TestLoadProviderDefaults",
+			"TestMergeDefaults",
+			"TestBuildKindConfig",
+			"TestParseKindNodeInspect",
+			"TestKindProvider_Validate",
+			"TestBuildK3dArgs",
+			"TestK3dClusterInfo",
+			"TestK3dProvider_Validate",
+			"TestSSHArgs",
+			"TestKubeadmInitArgs",
+			"TestSelectMembers",
+			"TestParseKubeadmOutput",
+			"TestKubeadmProvider_Lifecycle",
+			"TestKubeadmProvider_HighAvailability",
+			"TestKubeadmProvider_Validate",
+			"TestMergeKubeconfig",
+			"TestClusterAPIManifest",
+			"TestParseClusterAPIObjects",
+			"TestClusterAPIProvider_Lifecycle",
+			"TestClusterAPIProvider_Validate",
+			"TestParseLogSelector",
+			"TestValidateLogConfig",
+			"TestParseRetentionDays",
+			"TestLoggingChartArgs",
+			"TestLokiQuery",
+			"TestCloudWatchQuery",
+			"TestValidateTracingConfig",
+			"TestTracingReleases",
+			"TestServiceURLs",
+			"TestValidateUpgrade",
+			"TestUpgradeOptions_Validate",
+			"TestFakeProvider_UpgradeCluster",
+			"TestPlanCluster",
+			"TestParseTaint",
+			"TestNodePoolScaling_Validate",
+			"TestNodeGroupArgs",
+			"TestEKSNodePool",
+			"TestResolveNodePoolOS",
+			"TestFakeProvider_NodePools",
+			"TestParseMinikubeNetwork",
+			"TestScanMinikubeCache",
 		},
+		Tags: []string{"unit", "providers"},
 	},
 	{
 		Name:        "Command-line Interface Tests",
@@ -46,10 +133,382 @@ var testSuites = []TestSuite{
 		Tests: []string{
 			"TestLoadClusterConfig",
 			"TestLoadClusterConfig_FileNotFound",
+			"TestLoadClusterConfig_UnknownFields",
 			"TestClusterGenerateConfigCmd",
 			"TestClusterCreateCmd_FlagParsing",
 			"TestConfigFileVsFlagsIntegration",
+			"TestParseAPIServerPort",
+			"TestAllocateClusterPorts",
+			"TestReserveClusterPorts",
+			"TestRequireApproval",
+			"TestSyncInventory",
+			"TestListedScopes",
+			"TestParseMountFlag",
+			"TestFilterAndSortClusters",
+			"TestParseTagFilters",
+			"TestParseFieldFilters",
+			"TestListAllClusters",
+			"TestAnnotateHealth",
+			"TestWatchCluster_StopsOnCancel",
+			"TestSummarizeHealth",
+			"TestToYAML_UsesJSONFieldNames",
+			"TestPrompter_RepromptsInvalidAnswers",
+			"TestRunCreateWizard",
+			"TestApplyFlagOverrides",
+			"TestResolveClusterConfig",
+			"TestResolveClusterConfig_InvalidEnv",
+			"TestResolveClusterConfig_ProviderDefaults",
+			"TestCollectOperationMetrics",
+			"TestRecordHealthMetrics",
+			"TestExporterTargets",
+			"TestExportRound",
+			"TestStartDaemonMonitoring",
+			"TestBuildMonitorDaemonStatus",
+			"TestHealthGate",
+			"TestCertificateExpiry",
+			"TestCompareSnapshots",
+			"TestTableRenderWidth",
+			"TestWrapCell",
+			"TestCompareConfigFields",
+			"TestPrintClusterDiff",
+			"TestBulkOperationError",
+			"TestBuildProfileURL",
+			"TestPrintClusterTable_Golden",
+			"TestPrintOperationHistory_Golden",
+			"TestFormatRelativeTime",
+			"TestParseAge",
+			"TestHistoryTable",
+			"TestFormatExpiry",
+			"TestPreviewManifests",
+			"TestPrintHealthStatus_Golden",
+			"TestPrintMetrics_Golden",
+			"TestPrintFleetHealth_Golden",
+			"TestPrintMigrationReport_Golden",
+			"TestPrintValidationResult_Golden",
+			"TestMaintenanceWarning",
+			"TestFormatWait",
+			"TestExamplesCatalog",
+			"TestMatchExamples",
+			"TestParseOutputFormat",
+			"TestRenderStructured",
+			"TestScripts",
+		},
+		Tags: []string{"unit", "cli"},
+	},
+	{
+		Name:        "Schema Tests",
+		Package:     "./pkg/schema",
+		Description: "Tests for JSON Schema generation from config and output types",
+		Tests: []string{
+			"TestGenerate",
+			"TestRegistry_Get",
+		},
+		Tags: []string{"unit", "schema"},
+	},
+	{
+		Name:        "Progress Tests",
+		Package:     "./pkg/progress",
+		Description: "Tests for provider progress event reporting",
+		Tests: []string{
+			"TestFromContext_Default",
+			"TestReport",
+			"TestTextReporter",
+			"TestJSONReporter",
+			"TestSummary",
+		},
+		Tags: []string{"unit", "progress"},
+	},
+	{
+		Name:        "Operation Limiter Tests",
+		Package:     "./pkg/operations",
+		Description: "Tests for the cross-process operation concurrency limit",
+		Tests: []string{
+			"TestLimiter_Acquire",
+			"TestLimiter_AcquireCancelled",
+			"TestLimiter_Cancel",
+			"TestLimiter_ListPrunesDeadProcesses",
+			"TestProcessAlive",
+			"TestLimitFromEnv",
+		},
+		Tags: []string{"unit", "operations"},
+	},
+	{
+		Name:        "Monitoring Tests",
+		Package:     "./pkg/monitoring",
+		Description: "Tests for health result caching, uptime reporting, alert notifications, the Prometheus exporter, metrics history, the event journal, the cluster API checks and certificate expiry",
+		Tests: []string{
+			"TestHealthCache",
+			"TestHealthEndpoint",
+			"TestSendHeartbeat",
+			"TestLoadAlertConfig",
+			"TestEvaluateHealth",
+			"TestEvaluateMetrics",
+			"TestNotifier",
+			"TestChannels",
+			"TestExporter",
+			"TestMetricsHistory",
+			"TestEventJournal",
+			"TestKubeClientChecks",
+			"TestCheckNodes_Windows",
+			"TestCheckWorkloads",
+			"TestGetNodeMetrics",
+			"TestListRoutes",
+			"TestListRoutes_WithoutGatewayAPI",
+			"TestParseCertificateListing",
+			"TestAPIServerCertificate",
+			"TestFlagExpiringCertificates",
+		},
+		Tags: []string{"unit", "monitoring"},
+	},
+	{
+		Name:        "Metrics Tests",
+		Package:     "./pkg/metrics",
+		Description: "Tests for Atlas's own Prometheus metrics",
+		Tests: []string{
+			"TestRegistry_Write",
+			"TestRegistry_Since",
+		},
+		Tags: []string{"unit", "metrics"},
+	},
+	{
+		Name:        "Subprocess Tests",
+		Package:     "./pkg/subprocess",
+		Description: "Tests for the provider command watchdog, exit cleanup and session record/replay",
+		Tests: []string{
+			"TestCommandContext_KillsHungProcessGroup",
+			"TestCommandContext_KilledByCleanup",
+			"TestCommandContext_FastCommand",
+			"TestTimeoutFor",
+			"TestTranscript_RecordReplay",
+			"TestScrub",
+		},
+		Tags: []string{"unit", "subprocess"},
+	},
+	{
+		Name:        "Cleanup Tests",
+		Package:     "./pkg/cleanup",
+		Description: "Tests for the registry that reaps child processes and temporary files on exit",
+		Tests: []string{
+			"TestRegistry",
+			"TestRemoveOnExit",
+		},
+		Tags: []string{"unit", "cleanup"},
+	},
+	{
+		Name:        "State Store Tests",
+		Package:     "./internal/store",
+		Description: "Tests for the locked, owner-only files Atlas keeps its state in",
+		Tests: []string{
+			"TestSaveJSON",
+			"TestUpdateJSON",
+			"TestDir_NoHome",
+		},
+		Tags: []string{"unit", "store"},
+	},
+	{
+		Name:        "Export Tests",
+		Package:     "./pkg/export",
+		Description: "Tests for CSV and Parquet history export",
+		Tests: []string{
+			"TestWriteCSV",
+			"TestWriteParquet",
+			"TestEncodeRLELevels",
+		},
+		Tags: []string{"unit", "export"},
+	},
+	{
+		Name:        "Preview Tests",
+		Package:     "./pkg/preview",
+		Description: "Tests for the pull request preview registry, Helm deploys and notifications",
+		Tests: []string{
+			"TestStore",
+			"TestEnvironment_Expired",
+			"TestHelmArgs",
+			"TestNotify",
+		},
+		Tags: []string{"unit", "preview"},
+	},
+	{
+		Name:        "Fleet Tests",
+		Package:     "./pkg/fleet",
+		Description: "Tests for fleet definitions and aggregated fleet health",
+		Tests: []string{
+			"TestFleet_AddRemove",
+			"TestSelector",
+			"TestStore",
+			"TestAggregate",
+			"TestAggregate_Totals",
+		},
+		Tags: []string{"unit", "fleet"},
+	},
+	{
+		Name:        "Migration Tests",
+		Package:     "./pkg/migrate",
+		Description: "Tests for cross-cluster workload export, restore and diffing",
+		Tests: []string{
+			"TestExportRestore",
+			"TestDiff",
+			"TestVeleroMigrate",
+		},
+		Tags: []string{"unit", "migrate"},
+	},
+	{
+		Name:        "Maintenance Tests",
+		Package:     "./pkg/maintenance",
+		Description: "Tests for maintenance window schedules and storage",
+		Tests: []string{
+			"TestWindow_Contains",
+			"TestWindow_Next",
+			"TestWindow_Validate",
+			"TestStore",
+		},
+		Tags: []string{"unit", "maintenance"},
+	},
+	{
+		Name:        "Port Tests",
+		Package:     "./pkg/ports",
+		Description: "Tests for the local cluster port registry",
+		Tests: []string{
+			"TestStore",
+			"TestUpdate",
+		},
+		Tags: []string{"unit", "ports"},
+	},
+	{
+		Name:        "SDK Tests",
+		Package:     "./pkg/atlas",
+		Description: "Runnable examples of the embeddable Atlas client",
+		Tests: []string{
+			"Example",
+		},
+		Tags: []string{"unit", "sdk"},
+	},
+	{
+		Name:        "Teardown Tests",
+		Package:     "./internal/services",
+		Description: "Tests for the cleanup run after a cluster is deleted",
+		Tests: []string{
+			"TestTunnelPattern",
+			"TestTeardownCluster",
+			"TestTeardownPorts",
+		},
+		Tags: []string{"unit", "teardown"},
+	},
+	{
+		Name:        "Approval Tests",
+		Package:     "./pkg/approvals",
+		Description: "Tests for protected clusters and operation approvals",
+		Tests: []string{
+			"TestStore",
+			"TestCurrentUser",
+			"TestUpdate",
+		},
+		Tags: []string{"unit", "approvals"},
+	},
+	{
+		Name:        "Annotation Tests",
+		Package:     "./pkg/annotations",
+		Description: "Tests for notes attached to past operations",
+		Tests: []string{
+			"TestStore",
+			"TestApply",
+		},
+		Tags: []string{"unit", "annotations"},
+	},
+	{
+		Name:        "Log Source Tests",
+		Package:     "./pkg/logsource",
+		Description: "Tests for the deploy log and merged operation history",
+		Tests: []string{
+			"TestDeployLog",
+			"TestMerge",
+		},
+		Tags: []string{"unit", "history"},
+	},
+	{
+		Name:        "Kubernetes Version Tests",
+		Package:     "./pkg/kubeversions",
+		Description: "Tests for Kubernetes version support windows",
+		Tests: []string{
+			"TestParse",
+			"TestEmbedded",
+			"TestTable_Check",
+			"TestLoad",
+		},
+		Tags: []string{"unit", "versions"},
+	},
+	{
+		Name:        "Error Hint Tests",
+		Package:     "./pkg/errhints",
+		Description: "Tests for recognizing common provider failures",
+		Tests: []string{
+			"TestTranslate",
+			"TestTranslate_Unrecognized",
+			"TestHint_String",
+		},
+		Tags: []string{"unit", "errors"},
+	},
+	{
+		Name:        "Secret Tests",
+		Package:     "./pkg/secrets",
+		Description: "Tests for resolving secret references in config",
+		Tests: []string{
+			"TestResolver",
+			"TestResolver_ResolveAll",
+		},
+		Tags: []string{"unit", "secrets"},
+	},
+	{
+		Name:        "Inventory Tests",
+		Package:     "./pkg/inventory",
+		Description: "Tests for pushing cluster inventory to external systems",
+		Tests: []string{
+			"TestLoadConfig",
+			"TestConfig_Push",
+			"TestState",
+		},
+		Tags: []string{"unit", "inventory"},
+	},
+	{
+		Name:        "Grafana Tests",
+		Package:     "./pkg/grafana",
+		Description: "Tests for the Atlas dashboards and Grafana provisioning",
+		Tests: []string{
+			"TestDashboards",
+			"TestClient_Provision",
+		},
+		Tags: []string{"unit", "grafana"},
+	},
+	{
+		Name:        "Daemon Tests",
+		Package:     "./pkg/daemon",
+		Description: "Tests for running commands as background processes tracked by a PID file",
+		Tests: []string{
+			"TestPIDFile",
+			"TestStartStop",
+		},
+		Tags: []string{"unit", "daemon"},
+	},
+	{
+		Name:        "Golden Artifact Tests",
+		Package:     "./pkg/golden",
+		Description: "Tests for capturing, signing and verifying golden cluster artifacts",
+		Tests: []string{
+			"TestResolveManifests",
+			"TestArtifactRoundTrip",
+			"TestSignAndVerify",
 		},
+		Tags: []string{"unit", "golden"},
+	},
+	{
+		Name:        "Phase Timing Tests",
+		Package:     "./pkg/timings",
+		Description: "Tests for timing operation phases and summarizing operation history",
+		Tests: []string{
+			"TestRecorder",
+			"TestStoreApplyAndAnalyze",
+		},
+		Tags: []string{"unit", "timings"},
 	},
 	{
 		Name:        "Integration Tests",
@@ -58,6 +517,8 @@ var testSuites = []TestSuite{
 		Tests: []string{
 			"TestLocalProvider_Integration",
 		},
+		Tags:   []string{"integration", "providers"},
+		Budget: 20 * time.Minute,
 	},
 }
 
@@ -66,30 +527,46 @@ func main() {
 	fmt.Println("==============================")
 	
 	// Parse command line arguments
-	runIntegration := false
-	runBenchmarks := false
-	verbose := false
-	
-	for _, arg := range os.Args[1:] {
-		switch arg {
-		case "-integration":
-			runIntegration = true
-		case "-bench":
-			runBenchmarks = true
-		case "-v", "-verbose":
-			verbose = true
-		case "-h", "-help":
-			printUsage()
-			return
-		}
+	var runIntegration, runBenchmarks, verbose bool
+	var tags, junitPath, jsonPath, coverageDir string
+	flag.BoolVar(&runIntegration, "integration", false, "Run integration tests (requires minikube)")
+	flag.BoolVar(&runBenchmarks, "bench", false, "Run benchmark tests")
+	flag.BoolVar(&verbose, "v", false, "Verbose output")
+	flag.BoolVar(&verbose, "verbose", false, "Verbose output")
+	flag.StringVar(&tags, "tags", "", "Comma-separated suite tags to run")
+	flag.StringVar(&junitPath, "junit", "", "Write a JUnit XML report to this file")
+	flag.StringVar(&jsonPath, "json", "", "Write a JSON report to this file")
+	flag.StringVar(&coverageDir, "coverage", "", "Collect coverage profiles into this directory and enforce thresholds")
+	parallel := flag.Int("parallel", runtime.NumCPU(), "Number of suites to run at once")
+	retries := flag.Int("retries", 2, "Times to re-run a failing test before reporting it as failed")
+	flag.Usage = printUsage
+	flag.Parse()
+	
+	selectedTags := splitTags(tags)
+	if hasTag(selectedTags, "integration") {
+		runIntegration = true
 	}
 	
 	fmt.Printf("Go version: %s\n", runtime.Version())
 	fmt.Printf("Platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
 	fmt.Printf("Integration tests: %v\n", runIntegration)
 	fmt.Printf("Benchmarks: %v\n", runBenchmarks)
+	fmt.Printf("Parallel suites: %d, retries: %d\n", *parallel, *retries)
+	if len(selectedTags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(selectedTags, ", "))
+	}
+	if coverageDir != "" {
+		fmt.Printf("Coverage: %s\n", coverageDir)
+	}
 	fmt.Printf("Verbose: %v\n\n", verbose)
 	
+	if coverageDir != "" {
+		if err := os.MkdirAll(coverageDir, 0755); err != nil {
+			fmt.Printf("❌ Failed to create coverage directory: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	
 	// Check prerequisites
 	if err := checkPrerequisites(); err != nil {
 		fmt.Printf("❌ Prerequisites check failed: %v\n", err)
@@ -101,52 +578,46 @@ func main() {
 	passedTests := 0
 	failedTests := 0
 	skippedTests := 0
+	flakyTests := 0
+	quarantinedFailures := 0
 	
-	// Run each test suite
+	// Select the suites to run
+	var selected []TestSuite
 	for _, suite := range testSuites {
-		if suite.Name == "Integration Tests" && !runIntegration {
+		if len(selectedTags) > 0 && !hasAnyTag(suite.Tags, selectedTags) {
+			continue
+		}
+		if hasTag(suite.Tags, "integration") && !runIntegration {
 			fmt.Printf("⏭️  Skipping %s (use -integration flag to run)\n\n", suite.Name)
 			continue
 		}
-		
-		fmt.Printf("📦 Running %s\n", suite.Name)
-		fmt.Printf("   %s\n", suite.Description)
-		fmt.Printf("   Package: %s\n\n", suite.Package)
-		
-		results := runTestSuite(suite, verbose)
-		allResults = append(allResults, results...)
-		
-		// Count results for this suite
-		suiteTotal := 0
-		suitePassed := 0
-		suiteFailed := 0
-		suiteSkipped := 0
-		
-		for _, result := range results {
-			suiteTotal++
-			switch result.Status {
-			case "PASS":
-				suitePassed++
-			case "FAIL":
-				suiteFailed++
-			case "SKIP":
-				suiteSkipped++
+		selected = append(selected, suite)
+	}
+	
+	// Run the suites on a worker pool, printing each one's report in order as it finishes
+	opts := runOptions{Verbose: verbose, CoverageDir: coverageDir, Retries: *retries}
+	runs := runSuites(selected, *parallel, opts, func(run SuiteRun) {
+		printSuiteRun(os.Stdout, run, verbose)
+	})
+	
+	for _, run := range runs {
+		allResults = append(allResults, run.Results...)
+		for _, result := range run.Results {
+			totalTests++
+			switch {
+			case result.Status == "FAIL" && result.Quarantined:
+				quarantinedFailures++
+			case result.Status == "FAIL":
+				failedTests++
+			case result.Status == "SKIP":
+				skippedTests++
+			case result.Status == "PASS":
+				passedTests++
+				if result.Attempts > 1 {
+					flakyTests++
+				}
 			}
 		}
-		
-		totalTests += suiteTotal
-		passedTests += suitePassed
-		failedTests += suiteFailed
-		skippedTests += suiteSkipped
-		
-		// Print suite summary
-		if suiteFailed > 0 {
-			fmt.Printf("   ❌ Suite: %d/%d tests failed\n\n", suiteFailed, suiteTotal)
-		} else if suiteSkipped == suiteTotal {
-			fmt.Printf("   ⏭️  Suite: All %d tests skipped\n\n", suiteTotal)
-		} else {
-			fmt.Printf("   ✅ Suite: %d/%d tests passed\n\n", suitePassed, suiteTotal)
-		}
 	}
 	
 	// Run benchmarks if requested
@@ -167,12 +638,31 @@ func main() {
 	if skippedTests > 0 {
 		fmt.Printf("⏭️  Skipped: %d\n", skippedTests)
 	}
+	if flakyTests > 0 {
+		fmt.Printf("🔁 Flaky: %d\n", flakyTests)
+	}
+	if quarantinedFailures > 0 {
+		fmt.Printf("🚧 Quarantined failures: %d\n", quarantinedFailures)
+	}
+	
+	// Print flaky and quarantined tests so they can be fixed or quarantined
+	if flakyTests > 0 || quarantinedFailures > 0 {
+		fmt.Printf("\n🔁 Quarantine Report:\n")
+		for _, result := range allResults {
+			switch {
+			case result.Status == "PASS" && result.Attempts > 1:
+				fmt.Printf("   %s.%s passed on attempt %d (flaky; add it to quarantinedTests if it keeps happening)\n", result.Suite, result.Test, result.Attempts)
+			case result.Status == "FAIL" && result.Quarantined:
+				fmt.Printf("   %s.%s failed but is quarantined: %s\n", result.Suite, result.Test, quarantinedTests[topLevelTest(result.Test)])
+			}
+		}
+	}
 	
 	// Print failed tests details
 	if failedTests > 0 {
 		fmt.Printf("\n❌ Failed Tests:\n")
 		for _, result := range allResults {
-			if result.Status == "FAIL" {
+			if result.Status == "FAIL" && !result.Quarantined {
 				fmt.Printf("   %s.%s\n", result.Suite, result.Test)
 				if result.Error != "" {
 					fmt.Printf("     Error: %s\n", result.Error)
@@ -206,29 +696,95 @@ func main() {
 		fmt.Printf("   ✅ Performance benchmarks\n")
 	}
 	
+	// Write machine-readable reports for CI
+	if junitPath != "" {
+		if err := writeJUnit(junitPath, runs); err != nil {
+			fmt.Printf("\n❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("\n📝 JUnit report written to %s\n", junitPath)
+	}
+	if jsonPath != "" {
+		if err := writeJSON(jsonPath, runs); err != nil {
+			fmt.Printf("\n❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("📝 JSON report written to %s\n", jsonPath)
+	}
+	
+	var coverageFailures []string
+	if coverageDir != "" {
+		coverageFailures = checkCoverage(runs)
+		if len(coverageFailures) > 0 {
+			fmt.Printf("\n❌ Coverage below threshold:\n")
+			for _, failure := range coverageFailures {
+				fmt.Printf("   %s\n", failure)
+			}
+		}
+	}
+	
 	if failedTests > 0 {
 		fmt.Printf("\n❌ Test suite failed with %d failing tests\n", failedTests)
 		os.Exit(1)
+	} else if len(coverageFailures) > 0 {
+		fmt.Printf("\n❌ Test suite failed coverage thresholds\n")
+		os.Exit(1)
 	} else {
 		fmt.Printf("\n🎉 All tests passed!\n")
 	}
 }
 
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyTag(tags, wanted []string) bool {
+	for _, tag := range wanted {
+		if hasTag(tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
 func printUsage() {
 	fmt.Println("Atlas CLI Test Suite Runner")
 	fmt.Println("")
-	fmt.Println("Usage: go run run_tests.go [options]")
+	fmt.Println("Usage: go run ./tools [options]")
 	fmt.Println("")
 	fmt.Println("Options:")
 	fmt.Println("  -integration    Run integration tests (requires minikube)")
 	fmt.Println("  -bench         Run benchmark tests")
 	fmt.Println("  -v, -verbose   Verbose output")
+	fmt.Println("  -tags list     Only run suites with any of these comma-separated tags")
+	fmt.Println("  -junit file    Write a JUnit XML report")
+	fmt.Println("  -json file     Write a JSON report")
+	fmt.Println("  -coverage dir  Collect coverage profiles and enforce per-package thresholds")
+	fmt.Println("  -parallel n    Run up to n suites at once (default: number of CPUs)")
+	fmt.Println("  -retries n     Re-run failing tests up to n times and report them as flaky if they pass (default 2)")
 	fmt.Println("  -h, -help      Show this help")
 	fmt.Println("")
 	fmt.Println("Examples:")
-	fmt.Println("  go run run_tests.go                    # Run unit tests only")
-	fmt.Println("  go run run_tests.go -integration       # Run all tests including integration")
-	fmt.Println("  go run run_tests.go -bench -v          # Run with benchmarks and verbose output")
+	fmt.Println("  go run ./tools                         # Run unit tests only")
+	fmt.Println("  go run ./tools -integration            # Run all tests including integration")
+	fmt.Println("  go run ./tools -bench -v               # Run with benchmarks and verbose output")
+	fmt.Println("  go run ./tools -tags cli,schema        # Run only the CLI and schema suites")
+	fmt.Println("  go run ./tools -junit report.xml -coverage coverage  # CI mode")
 }
 
 func checkPrerequisites() error {
@@ -250,37 +806,178 @@ func checkPrerequisites() error {
 	return nil
 }
 
-func runTestSuite(suite TestSuite, verbose bool) []TestResult {
-	var results []TestResult
+// runOptions controls how each suite is executed
+type runOptions struct {
+	Verbose     bool
+	CoverageDir string
+	Retries     int
+}
+
+// runSuites runs suites on a pool of parallel workers. report is called once per suite, in the
+// order the suites were given, as soon as that suite and every suite before it has finished.
+func runSuites(suites []TestSuite, parallel int, opts runOptions, report func(SuiteRun)) []SuiteRun {
+	if parallel < 1 {
+		parallel = 1
+	}
+	
+	done := make([]chan SuiteRun, len(suites))
+	for i := range done {
+		done[i] = make(chan SuiteRun, 1)
+	}
+	
+	queue := make(chan int)
+	for w := 0; w < parallel; w++ {
+		go func() {
+			for i := range queue {
+				done[i] <- runTestSuite(suites[i], opts)
+			}
+		}()
+	}
+	go func() {
+		for i := range suites {
+			queue <- i
+		}
+		close(queue)
+	}()
+	
+	runs := make([]SuiteRun, 0, len(suites))
+	for i := range suites {
+		run := <-done[i]
+		report(run)
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// runTestSuite runs a suite within its budget, re-running failing tests up to opts.Retries times
+func runTestSuite(suite TestSuite, opts runOptions) SuiteRun {
+	budget := suite.Budget
+	if budget == 0 {
+		budget = defaultSuiteBudget
+	}
+	start := time.Now()
+	deadline := start.Add(budget)
 	
 	// Run all tests in the package if no specific tests are listed
-	args := []string{"test", suite.Package, "-v"}
+	args := []string{"test", suite.Package, "-v", "-count=1"}
 	if len(suite.Tests) > 0 {
 		args = append(args, "-run", strings.Join(suite.Tests, "|"))
 	}
+	if opts.CoverageDir != "" {
+		args = append(args, "-coverprofile", coverProfilePath(opts.CoverageDir, suite))
+	}
 	
-	cmd := exec.Command("go", args...)
+	outputStr, err, timedOut := runGoTest(args, deadline)
+	results := parseTestOutput(suite.Name, outputStr)
 	
-	// Set environment variables for testing
-	env := os.Environ()
-	env = append(env, "CGO_ENABLED=1") // Needed for sqlite
-	cmd.Env = env
+	// Handle case where no individual test results were parsed (e.g., compilation errors)
+	if len(results) == 0 && err != nil && !timedOut {
+		results = append(results, TestResult{
+			Suite:  suite.Name,
+			Test:   "compilation",
+			Status: "FAIL",
+			Error:  err.Error(),
+			Output: outputStr,
+		})
+	}
 	
-	output, err := cmd.CombinedOutput()
-	outputStr := string(output)
+	// Re-run failing top-level tests on their own; a test that passes on retry is flaky, not broken
+	for attempt := 2; attempt <= opts.Retries+1 && !timedOut; attempt++ {
+		failing := failingTests(results)
+		if len(failing) == 0 || (len(failing) == 1 && failing[0] == "compilation") {
+			break
+		}
+		
+		retryArgs := []string{"test", suite.Package, "-v", "-count=1", "-run", "^(" + strings.Join(failing, "|") + ")$"}
+		var retryOutput string
+		retryOutput, _, timedOut = runGoTest(retryArgs, deadline)
+		outputStr += retryOutput
+		
+		for _, retried := range parseTestOutput(suite.Name, retryOutput) {
+			if retried.Status != "PASS" || strings.Contains(retried.Test, "/") {
+				continue
+			}
+			for i := range results {
+				if topLevelTest(results[i].Test) == retried.Test && results[i].Status == "FAIL" {
+					results[i].Status = "PASS"
+					results[i].Attempts = attempt
+				}
+			}
+		}
+	}
 	
-	// Parse test output
-	scanner := bufio.NewScanner(strings.NewReader(outputStr))
+	if timedOut {
+		for i := range results {
+			if results[i].Status == "RUN" {
+				results[i].Status = "FAIL"
+				results[i].Error = "still running when the suite budget ran out"
+			}
+		}
+		results = append(results, TestResult{
+			Suite:  suite.Name,
+			Test:   "budget",
+			Status: "FAIL",
+			Error:  fmt.Sprintf("suite exceeded its %v wall-clock budget", budget),
+			Output: outputStr,
+		})
+	}
+	
+	// Failures of quarantined tests are reported but don't fail the run
+	for i := range results {
+		if _, ok := quarantinedTests[topLevelTest(results[i].Test)]; ok {
+			results[i].Quarantined = true
+		}
+		if results[i].Attempts == 0 {
+			results[i].Attempts = 1
+		}
+	}
+	
+	run := SuiteRun{Suite: suite, Results: results, Duration: time.Since(start), Coverage: -1, Output: outputStr}
+	if opts.CoverageDir != "" {
+		run.Coverage = parseCoverage(outputStr)
+	}
+	return run
+}
+
+// runGoTest runs go with args, killing it at deadline. timedOut reports whether the deadline, or
+// go test's own -timeout derived from it, cut the run short.
+func runGoTest(args []string, deadline time.Time) (output string, err error, timedOut bool) {
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return "", context.DeadlineExceeded, true
+	}
+	
+	// go test's own timeout fires first so the panic names the hung test; the context is the backstop
+	ctx, cancel := context.WithDeadline(context.Background(), deadline.Add(budgetGrace))
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "go", append(args, "-timeout", remaining.Round(time.Second).String())...)
+	cmd.WaitDelay = budgetGrace
+	
+	out, err := cmd.CombinedOutput()
+	output = string(out)
+	timedOut = ctx.Err() != nil || strings.Contains(output, "panic: test timed out after")
+	return output, err, timedOut
+}
+
+// parseTestOutput turns go test -v output into results, attributing log lines to the test that
+// was running when they were printed
+func parseTestOutput(suiteName, output string) []TestResult {
+	var results []TestResult
+	current := -1
+	scanner := bufio.NewScanner(strings.NewReader(output))
 	for scanner.Scan() {
 		line := scanner.Text()
 		
 		if strings.Contains(line, "=== RUN") {
 			testName := strings.TrimSpace(strings.TrimPrefix(line, "=== RUN"))
 			results = append(results, TestResult{
-				Suite: suite.Name,
+				Suite: suiteName,
 				Test:  testName,
 				Status: "RUN",
 			})
+			current = len(results) - 1
+		} else if current >= 0 && strings.HasPrefix(line, "    ") && !strings.Contains(line, "--- ") {
+			results[current].Output += strings.TrimSpace(line) + "\n"
 		} else if strings.Contains(line, "--- PASS:") || strings.Contains(line, "--- FAIL:") || strings.Contains(line, "--- SKIP:") {
 			parts := strings.Fields(line)
 			if len(parts) >= 3 {
@@ -289,8 +986,11 @@ func runTestSuite(suite TestSuite, verbose bool) []TestResult {
 				
 				// Find the test result to update
 				for i := range results {
-					if results[i].Test == testName && results[i].Suite == suite.Name {
+					if results[i].Test == testName {
 						results[i].Status = status
+						if status != "PASS" && results[i].Error == "" {
+							results[i].Error, _, _ = strings.Cut(results[i].Output, "\n")
+						}
 						
 						// Parse duration if available
 						if len(parts) >= 4 {
@@ -304,27 +1004,57 @@ func runTestSuite(suite TestSuite, verbose bool) []TestResult {
 			}
 		}
 	}
-	
-	// Handle case where no individual test results were parsed (e.g., compilation errors)
-	if len(results) == 0 && err != nil {
-		results = append(results, TestResult{
-			Suite:  suite.Name,
-			Test:   "compilation",
-			Status: "FAIL",
-			Error:  err.Error(),
-			Output: outputStr,
-		})
+	return results
+}
+
+// failingTests returns the distinct top-level names of failed results
+func failingTests(results []TestResult) []string {
+	var names []string
+	for _, result := range results {
+		if result.Status != "FAIL" {
+			continue
+		}
+		if name := topLevelTest(result.Test); !hasTag(names, name) {
+			names = append(names, name)
+		}
 	}
+	return names
+}
+
+func topLevelTest(name string) string {
+	top, _, _ := strings.Cut(name, "/")
+	return top
+}
+
+// printSuiteRun prints one suite's results and summary
+func printSuiteRun(w io.Writer, run SuiteRun, verbose bool) {
+	fmt.Fprintf(w, "📦 Running %s\n", run.Suite.Name)
+	fmt.Fprintf(w, "   %s\n", run.Suite.Description)
+	fmt.Fprintf(w, "   Package: %s\n\n", run.Suite.Package)
 	
-	// Print test results
-	for _, result := range results {
+	suiteTotal := 0
+	suitePassed := 0
+	suiteFailed := 0
+	suiteSkipped := 0
+	
+	for _, result := range run.Results {
+		suiteTotal++
 		symbol := "❓"
 		switch result.Status {
 		case "PASS":
+			suitePassed++
 			symbol = "✅"
+			if result.Attempts > 1 {
+				symbol = "🔁"
+			}
 		case "FAIL":
+			suiteFailed++
 			symbol = "❌"
+			if result.Quarantined {
+				symbol = "🚧"
+			}
 		case "SKIP":
+			suiteSkipped++
 			symbol = "⏭️ "
 		}
 		
@@ -332,19 +1062,32 @@ func runTestSuite(suite TestSuite, verbose bool) []TestResult {
 		if result.Duration > 0 {
 			durationStr = fmt.Sprintf(" (%v)", result.Duration)
 		}
+		if result.Attempts > 1 {
+			durationStr += fmt.Sprintf(" [passed on attempt %d]", result.Attempts)
+		}
 		
-		fmt.Printf("   %s %s%s\n", symbol, result.Test, durationStr)
+		fmt.Fprintf(w, "   %s %s%s\n", symbol, result.Test, durationStr)
 		
 		if verbose && result.Status == "FAIL" && result.Error != "" {
-			fmt.Printf("      Error: %s\n", result.Error)
+			fmt.Fprintf(w, "      Error: %s\n", result.Error)
 		}
 	}
 	
-	if verbose && err != nil {
-		fmt.Printf("   Command output:\n%s\n", outputStr)
+	if verbose && suiteFailed > 0 {
+		fmt.Fprintf(w, "   Command output:\n%s\n", run.Output)
 	}
 	
-	return results
+	// Print suite summary
+	if suiteFailed > 0 {
+		fmt.Fprintf(w, "   ❌ Suite: %d/%d tests failed (%v)\n\n", suiteFailed, suiteTotal, run.Duration.Round(time.Millisecond))
+	} else if suiteSkipped == suiteTotal {
+		fmt.Fprintf(w, "   ⏭️  Suite: All %d tests skipped\n\n", suiteTotal)
+	} else {
+		fmt.Fprintf(w, "   ✅ Suite: %d/%d tests passed (%v)\n\n", suitePassed, suiteTotal, run.Duration.Round(time.Millisecond))
+	}
+	if run.Coverage >= 0 {
+		fmt.Fprintf(w, "   📈 Coverage: %.1f%% of statements\n\n", run.Coverage)
+	}
 }
 
 func runBenchmarkSuite(verbose bool) {